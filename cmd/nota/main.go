@@ -9,6 +9,7 @@ import (
 func main() {
 	rootCmd := cmd.NewRootCmd()
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		cmd.PrintError(os.Stderr, err, cmd.JSONOutput(rootCmd))
+		os.Exit(cmd.ExitCode(err))
 	}
 }
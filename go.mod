@@ -2,9 +2,16 @@ module github.com/TechnicallyShaun/nota-orbis
 
 go 1.25.5
 
+require (
+	filippo.io/age v1.2.1
+	github.com/klauspost/compress v1.18.0
+	github.com/spf13/cobra v1.10.2
+	github.com/yuin/goldmark v1.8.2
+	golang.org/x/sys v0.40.0
+)
+
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/cobra v1.10.2 // indirect
 	github.com/spf13/pflag v1.0.9 // indirect
-	golang.org/x/sys v0.40.0 // indirect
+	golang.org/x/crypto v0.24.0 // indirect
 )
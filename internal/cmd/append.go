@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/index"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/notes"
+	"github.com/spf13/cobra"
+)
+
+// ErrNothingToAppend is returned when append is given no text.
+var ErrNothingToAppend = errors.New("nothing to append")
+
+// appendJSON is the JSON form of an append.
+type appendJSON struct {
+	Path string `json:"path"`
+}
+
+// NewAppendCmd creates the append command
+func NewAppendCmd() *cobra.Command {
+	var heading string
+
+	cmd := &cobra.Command{
+		Use:   "append <note> [text]",
+		Short: "Append text to an existing note",
+		Long: `Appends text to a note, given as an argument or read from stdin. The note is
+a path, or a title or file name looked up in the vault's index, matching
+exactly or, failing that, in part; a query matching several notes is an error
+listing them.
+
+With --heading the text goes at the end of that heading's section, and the
+heading is added at the end of the note when missing. A heading given as text
+("Log") matches any level and is created as "## Log"; one given with its
+markers ("### Log") matches exactly.
+
+  nota append 2026-01-22 --heading Log -- "- 10:30 called the plumber"
+  git log -1 --format=%s | nota append "Website" --heading Changes`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+
+			text := ""
+			if len(args) == 2 {
+				text = args[1]
+			} else {
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("read text: %w", err)
+				}
+				text = string(data)
+			}
+			if strings.TrimSpace(text) == "" {
+				return ErrNothingToAppend
+			}
+
+			rel, err := findNote(vaultRoot, args[0])
+			if err != nil {
+				return err
+			}
+			path := filepath.Join(vaultRoot, filepath.FromSlash(rel))
+			if err := notes.Append(path, text, heading); err != nil {
+				return fmt.Errorf("append to %s: %w", rel, err)
+			}
+
+			out := cmd.OutOrStdout()
+			if JSONOutput(cmd) {
+				return writeJSON(out, appendJSON{Path: path})
+			}
+			fmt.Fprintf(out, "Appended to %s\n", rel)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&heading, "heading", "", "Heading to append under, added when missing")
+
+	return cmd
+}
+
+// findNote returns the path, relative to the vault root, of the note query
+// refers to: an existing file, or the one note the vault's index finds for
+// it.
+func findNote(vaultRoot, query string) (string, error) {
+	if info, err := os.Stat(query); err == nil && info.Mode().IsRegular() {
+		return vaultPath(vaultRoot, query)
+	}
+
+	ix, err := index.Open(vaultRoot)
+	if err != nil {
+		return "", fmt.Errorf("index vault: %w", err)
+	}
+	found := ix.Find(query)
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("no note matches %q", query)
+	case 1:
+		return found[0].Path, nil
+	}
+	paths := make([]string, len(found))
+	for i, n := range found {
+		paths[i] = n.Path
+	}
+	return "", fmt.Errorf("%q matches %d notes, give one of: %s", query, len(found), strings.Join(paths, ", "))
+}
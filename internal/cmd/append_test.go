@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAppendCmd(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	daily := filepath.Join(vaultRoot, "Areas", "Journal", "2026-01-22.md")
+	os.MkdirAll(filepath.Dir(daily), 0755)
+	os.WriteFile(daily, []byte("# Thursday\n\n## Log\n\n- 09:00 standup\n"), 0644)
+
+	// By title, with the text as an argument
+	var buf bytes.Buffer
+	cmd := NewAppendCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"thursday", "--heading", "Log", "--", "- 10:30 called the plumber"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Appended to Areas/Journal/2026-01-22.md") {
+		t.Errorf("expected the note's path, got: %s", buf.String())
+	}
+
+	// By path, with the text on stdin
+	cmd = NewAppendCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetIn(strings.NewReader("Slept well.\n"))
+	cmd.SetArgs([]string{daily, "--heading", "Journal"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	content, _ := os.ReadFile(daily)
+	expected := "# Thursday\n\n## Log\n\n- 09:00 standup\n- 10:30 called the plumber\n\n## Journal\n\nSlept well.\n"
+	if string(content) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, content)
+	}
+}
+
+func TestAppendCmd_Errors(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	os.Mkdir(filepath.Join(vaultRoot, "Inbox"), 0755)
+	os.WriteFile(filepath.Join(vaultRoot, "Inbox", "garden-plan.md"), []byte("# Garden plan\n"), 0644)
+	os.WriteFile(filepath.Join(vaultRoot, "Inbox", "garden-shed.md"), []byte("# Garden shed\n"), 0644)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"no match", []string{"recipes", "text"}, "no note matches"},
+		{"several matches", []string{"garden", "text"}, "Inbox/garden-plan.md, Inbox/garden-shed.md"},
+		{"no text", []string{"garden plan", " "}, "nothing to append"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewAppendCmd()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected an error containing %q, got: %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/backup"
+	"github.com/spf13/cobra"
+)
+
+// restoreJSON is the JSON form of nota backup restore.
+type restoreJSON struct {
+	Archive string `json:"archive"`
+	Dir     string `json:"dir"`
+	Files   int    `json:"files"`
+}
+
+// NewBackupCmd creates the backup command
+func NewBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the vault to a timestamped archive",
+		Long: `Archives the vault into a timestamped, zstd-compressed tar file named after
+the vault's folder, e.g. notes-20261016T093000Z.tar.zst, leaving out what
+.notaignore lists. Only the newest --keep backups of the vault are kept.
+
+Backups go to the directory given with --to, to an S3 bucket with --s3, or by
+default to the backups folder in nota's state directory. The defaults can be
+set under backup in .nota/config.json. S3 credentials are read from
+AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+
+Restore a backup with "nota backup restore".`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+			cfg, err := vault.LoadConfig(vaultRoot)
+			if err != nil {
+				return err
+			}
+
+			to, _ := cmd.Flags().GetString("to")
+			s3URI, _ := cmd.Flags().GetString("s3")
+			if to != "" && s3URI != "" {
+				return fmt.Errorf("--to and --s3 cannot be used together")
+			}
+			if to != "" || s3URI != "" {
+				cfg.Backup.To, cfg.Backup.S3 = to, s3URI
+			}
+			keep := backup.Keep(cfg.Backup)
+			if cmd.Flags().Changed("keep") {
+				keep, _ = cmd.Flags().GetInt("keep")
+			}
+			if keep < 0 {
+				return fmt.Errorf("--keep must not be negative")
+			}
+
+			store, err := backup.NewStore(cfg.Backup)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			result, err := backup.Backup(ctx, vaultRoot, store, backup.Options{Keep: keep})
+			if err != nil {
+				return fmt.Errorf("backup: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if JSONOutput(cmd) {
+				result.Removed = nonNil(result.Removed)
+				return writeJSON(out, result)
+			}
+			fmt.Fprintf(out, "Backed up %d files to %s (%s)\n", result.Files, joinLocation(fmt.Sprint(store), result.Archive), formatSize(result.Size))
+			for _, name := range result.Removed {
+				fmt.Fprintf(out, "Removed old backup %s\n", name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("to", "", "Directory to write the backup to (default: backup.to, or nota's state directory)")
+	cmd.Flags().String("s3", "", "Upload the backup to s3://bucket/prefix instead")
+	cmd.Flags().Int("keep", 0, "Number of backups to keep; 0 keeps them all (default: backup.keep, or 7)")
+
+	cmd.AddCommand(newBackupRestoreCmd())
+
+	return cmd
+}
+
+// newBackupRestoreCmd creates the backup restore subcommand
+func newBackupRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <archive> <dir>",
+		Short: "Restore a vault backup into a directory",
+		Long: `Extracts a backup made with "nota backup" into dir, which must be empty or
+not exist yet unless --force is given. The archive is a file or an
+s3://bucket/key URI.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			archive, dir := args[0], args[1]
+			force, _ := cmd.Flags().GetBool("force")
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			var src *os.File
+			if strings.HasPrefix(archive, "s3://") {
+				bucket, key, err := backup.ParseS3URI(archive)
+				if err != nil {
+					return err
+				}
+				cfg := restoreConfig()
+				cfg.To, cfg.S3 = "", "s3://"+bucket
+				store, err := backup.NewStore(cfg)
+				if err != nil {
+					return err
+				}
+				if src, err = os.CreateTemp("", "nota-restore-*"+backup.Ext); err != nil {
+					return err
+				}
+				defer os.Remove(src.Name())
+				if err := store.Get(ctx, strings.TrimSuffix(key, "/"), src); err != nil {
+					src.Close()
+					return fmt.Errorf("download %s: %w", archive, err)
+				}
+				if _, err := src.Seek(0, 0); err != nil {
+					src.Close()
+					return err
+				}
+			} else {
+				var err error
+				if src, err = os.Open(archive); err != nil {
+					return err
+				}
+			}
+			defer src.Close()
+
+			files, err := backup.Restore(src, dir, force)
+			if err != nil {
+				return fmt.Errorf("restore: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if JSONOutput(cmd) {
+				return writeJSON(out, restoreJSON{Archive: archive, Dir: dir, Files: files})
+			}
+			fmt.Fprintf(out, "Restored %d files from %s to %s\n", files, archive, dir)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("force", false, "Restore into a non-empty directory, replacing files")
+
+	return cmd
+}
+
+// restoreConfig returns the backup settings of the vault restore is run in,
+// for its S3 endpoint and region. Restoring may happen outside any vault,
+// which leaves the defaults.
+func restoreConfig() vault.BackupConfig {
+	root, err := vault.FindVaultRoot()
+	if err != nil {
+		return vault.BackupConfig{}
+	}
+	cfg, err := vault.LoadConfig(root)
+	if err != nil {
+		return vault.BackupConfig{}
+	}
+	return cfg.Backup
+}
+
+// joinLocation returns where the named archive is in the store at location.
+func joinLocation(location, name string) string {
+	if strings.HasPrefix(location, "s3://") {
+		return strings.TrimSuffix(location, "/") + "/" + name
+	}
+	return filepath.Join(location, name)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBackupCmd(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+	os.WriteFile(filepath.Join(vaultRoot, "memo.md"), []byte("# Memo\n"), 0644)
+
+	// A backup folder inside the vault is not backed up itself
+	dest := filepath.Join(vaultRoot, "Backups")
+	var buf bytes.Buffer
+	cmd := NewBackupCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--to", dest})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Backed up 2 files") {
+		t.Errorf("expected a backup report, got: %s", buf.String())
+	}
+	archives, _ := filepath.Glob(filepath.Join(dest, "*.tar.zst"))
+	if len(archives) != 1 {
+		t.Fatalf("expected one archive, got %v", archives)
+	}
+
+	restored := filepath.Join(t.TempDir(), "restored")
+	buf.Reset()
+	cmd = NewBackupCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"restore", archives[0], restored})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(restored, "memo.md")); string(data) != "# Memo\n" {
+		t.Errorf("expected the note restored, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(restored, "Backups")); err == nil {
+		t.Error("expected the backup folder to be left out")
+	}
+}
+
+func TestBackupCmd_ToAndS3(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	cmd := NewBackupCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--to", t.TempDir(), "--s3", "s3://backups"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for both --to and --s3")
+	}
+}
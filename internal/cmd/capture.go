@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/capture"
+	"github.com/spf13/cobra"
+)
+
+// captureJSON is the JSON form of a captured note.
+type captureJSON struct {
+	Path string `json:"path"`
+}
+
+// NewCaptureCmd creates the capture command
+func NewCaptureCmd() *cobra.Command {
+	var (
+		title     string
+		clipboard bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "capture",
+		Short: "Write text from stdin or the clipboard to a new Inbox note",
+		Long: `Reads text from stdin, or the system clipboard with --clipboard, and writes it
+as a new note in the vault's Inbox, with its title, creation time and source in
+the frontmatter. An existing note is never overwritten: the name gets a
+numbered suffix instead.
+
+  echo "call the plumber" | nota capture
+  nota capture --clipboard --title "Recipe"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+
+			var text string
+			if clipboard {
+				text, err = capture.ReadClipboard(cmd.Context())
+			} else {
+				var data []byte
+				data, err = io.ReadAll(cmd.InOrStdin())
+				text = string(data)
+			}
+			if err != nil {
+				return fmt.Errorf("read text: %w", err)
+			}
+
+			path, err := capture.Capture(vaultRoot, text, capture.Options{Title: title})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if JSONOutput(cmd) {
+				return writeJSON(out, captureJSON{Path: path})
+			}
+			rel, _ := filepath.Rel(vaultRoot, path)
+			fmt.Fprintf(out, "Captured %s\n", rel)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&title, "title", "", "Title of the note, also used in its file name")
+	cmd.Flags().BoolVar(&clipboard, "clipboard", false, "Read the system clipboard instead of stdin")
+
+	return cmd
+}
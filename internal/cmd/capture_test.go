@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCaptureCmd(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	var buf bytes.Buffer
+	cmd := NewCaptureCmd()
+	cmd.SetIn(strings.NewReader("call the plumber\n"))
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--title", "Plumber"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Captured Inbox/") {
+		t.Errorf("expected the note's path, got: %s", buf.String())
+	}
+
+	captured, _ := filepath.Glob(filepath.Join(vaultRoot, "Inbox", "*-plumber.md"))
+	if len(captured) != 1 {
+		t.Fatalf("expected a captured note, got: %v", captured)
+	}
+	content, _ := os.ReadFile(captured[0])
+	if !strings.Contains(string(content), "source: capture") || !strings.HasSuffix(string(content), "call the plumber\n") {
+		t.Errorf("unexpected note:\n%s", content)
+	}
+}
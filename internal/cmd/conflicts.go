@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/conflicts"
+	"github.com/spf13/cobra"
+)
+
+// resolvedJSON is the JSON form of a resolved conflict.
+type resolvedJSON struct {
+	Path   string `json:"path"`
+	Choice string `json:"choice"`
+}
+
+// NewConflictsCmd creates the conflicts command group. A nil prompter reads
+// choices from stdin.
+func NewConflictsCmd(prompter Prompter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "conflicts",
+		Short: "Find and resolve sync conflict copies of notes",
+		Long: `Syncthing keeps both versions when a file changes on two devices at once,
+saving the losing one as a conflict copy such as
+garden.sync-conflict-20261016-093000-ABCDEFG.md next to garden.md.
+These commands find the copies in the vault and resolve them.`,
+	}
+
+	cmd.AddCommand(newConflictsListCmd())
+	cmd.AddCommand(newConflictsResolveCmd(prompter))
+
+	return cmd
+}
+
+// newConflictsListCmd creates the conflicts list subcommand
+func newConflictsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List sync conflict copies in the vault",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+			found, err := conflicts.Find(vaultRoot)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if JSONOutput(cmd) {
+				if found == nil {
+					found = []conflicts.Conflict{}
+				}
+				return writeJSON(out, found)
+			}
+			if len(found) == 0 {
+				fmt.Fprintln(out, "No conflicts")
+				return nil
+			}
+			for _, c := range found {
+				fmt.Fprintf(out, "%s\n  conflicts with %s (device %s, %s)\n", c.Path, c.Original, c.Device, c.Time.Format("2006-01-02 15:04"))
+			}
+			return nil
+		},
+	}
+}
+
+// newConflictsResolveCmd creates the conflicts resolve subcommand
+func newConflictsResolveCmd(prompter Prompter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resolve [conflict-file]...",
+		Short: "Resolve sync conflicts by keeping or merging versions",
+		Long: `Resolves the given conflict copies, or every one in the vault. For each, the
+diff from the original note to the copy is shown and you pick what to keep:
+
+  original  keep the original and delete the copy
+  conflict  replace the original with the copy
+  both      merge the copy into the original, keeping the lines of both
+  skip      leave both for now
+
+--keep makes the same choice for every conflict without asking.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+
+			keep, _ := cmd.Flags().GetString("keep")
+			if keep != "" && !conflicts.Choice(keep).Valid() {
+				return conflicts.ErrInvalidChoice
+			}
+			if keep == "" && JSONOutput(cmd) {
+				return fmt.Errorf("--json requires --keep")
+			}
+
+			var pending []conflicts.Conflict
+			if len(args) == 0 {
+				if pending, err = conflicts.Find(vaultRoot); err != nil {
+					return err
+				}
+			}
+			for _, arg := range args {
+				rel, err := vaultPath(vaultRoot, arg)
+				if err != nil {
+					return err
+				}
+				c, err := conflicts.Parse(rel)
+				if err != nil {
+					return err
+				}
+				pending = append(pending, c)
+			}
+
+			p := prompter
+			if p == nil {
+				p = NewStdinPrompter()
+			}
+
+			out := cmd.OutOrStdout()
+			resolved := []resolvedJSON{}
+			for _, c := range pending {
+				choice := conflicts.Choice(keep)
+				if choice == "" {
+					if choice, err = askConflictChoice(cmd, p, vaultRoot, c); err != nil {
+						return err
+					}
+					if choice == "" {
+						fmt.Fprintf(out, "Skipped %s\n", c.Path)
+						continue
+					}
+				}
+
+				if err := conflicts.Resolve(vaultRoot, c, choice); err != nil {
+					return fmt.Errorf("resolve %s: %w", c.Path, err)
+				}
+				resolved = append(resolved, resolvedJSON{Path: c.Path, Choice: string(choice)})
+				if !JSONOutput(cmd) {
+					fmt.Fprintf(out, "Resolved %s: kept %s\n", c.Original, choice)
+				}
+			}
+
+			if JSONOutput(cmd) {
+				return writeJSON(out, resolved)
+			}
+			if len(pending) == 0 {
+				fmt.Fprintln(out, "No conflicts")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("keep", "", "Resolve every conflict the same way: original, conflict or both")
+
+	return cmd
+}
+
+// askConflictChoice shows the conflict and asks how to resolve it. An empty
+// choice skips it.
+func askConflictChoice(cmd *cobra.Command, p Prompter, vaultRoot string, c conflicts.Conflict) (conflicts.Choice, error) {
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "\n%s conflicts with %s (device %s, %s)\n", c.Path, c.Original, c.Device, c.Time.Format("2006-01-02 15:04"))
+
+	options := "[o]riginal, [c]onflict, [s]kip"
+	if c.IsNote() {
+		diff, err := c.Diff(vaultRoot)
+		if err != nil {
+			return "", err
+		}
+		if diff == "" {
+			fmt.Fprintln(out, "The versions have the same content.")
+		}
+		fmt.Fprint(out, diff)
+		options = "[o]riginal, [c]onflict, [b]oth, [s]kip"
+	}
+
+	for {
+		answer, err := p.Prompt(fmt.Sprintf("Keep %s: ", options))
+		if err != nil {
+			return "", err
+		}
+		switch strings.ToLower(answer) {
+		case "o", "original":
+			return conflicts.KeepOriginal, nil
+		case "c", "conflict":
+			return conflicts.KeepConflict, nil
+		case "b", "both":
+			if c.IsNote() {
+				return conflicts.KeepBoth, nil
+			}
+		case "s", "skip":
+			return "", nil
+		}
+		fmt.Fprintf(out, "Please answer with one of %s\n", options)
+	}
+}
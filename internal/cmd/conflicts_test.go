@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testConflictCopy = "garden.sync-conflict-20261016-093000-ABCDEFG.md"
+
+func setupConflictVault(t *testing.T) string {
+	t.Helper()
+	vaultRoot := setupTestVault(t)
+	os.WriteFile(filepath.Join(vaultRoot, "garden.md"), []byte("# Garden\n\n- basil\n"), 0644)
+	os.WriteFile(filepath.Join(vaultRoot, testConflictCopy), []byte("# Garden\n\n- chillies\n"), 0644)
+	return vaultRoot
+}
+
+func TestConflictsListCmd(t *testing.T) {
+	vaultRoot := setupConflictVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	var buf bytes.Buffer
+	cmd := NewConflictsCmd(nil)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), testConflictCopy) || !strings.Contains(buf.String(), "conflicts with garden.md") {
+		t.Errorf("expected the conflict listed, got: %s", buf.String())
+	}
+}
+
+func TestConflictsResolveCmd_Prompt(t *testing.T) {
+	vaultRoot := setupConflictVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	var buf bytes.Buffer
+	cmd := NewConflictsCmd(NewReaderPrompter(strings.NewReader("newest\nb\n")))
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"resolve"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, "-- basil") || !strings.Contains(output, "+- chillies") {
+		t.Errorf("expected the diff to be shown, got: %s", output)
+	}
+	if !strings.Contains(output, "Please answer") {
+		t.Errorf("expected an invalid answer to be asked again, got: %s", output)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultRoot, "garden.md"))
+	if string(data) != "# Garden\n\n- basil\n- chillies\n" {
+		t.Errorf("expected the versions merged, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(vaultRoot, testConflictCopy)); !os.IsNotExist(err) {
+		t.Error("expected the conflict copy to be removed")
+	}
+}
+
+func TestConflictsResolveCmd_Keep(t *testing.T) {
+	vaultRoot := setupConflictVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	var buf bytes.Buffer
+	cmd := NewConflictsCmd(nil)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"resolve", testConflictCopy, "--keep", "conflict"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultRoot, "garden.md"))
+	if string(data) != "# Garden\n\n- chillies\n" {
+		t.Errorf("expected the conflict copy kept, got %q", data)
+	}
+
+	cmd = NewConflictsCmd(nil)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"resolve", "--keep", "newest"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an invalid choice error")
+	}
+}
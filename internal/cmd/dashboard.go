@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/control"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+// DefaultWatchInterval is how often status --watch refreshes.
+const DefaultWatchInterval = 2 * time.Second
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// watchStatus redraws the live dashboard from the control socket every
+// interval until ctx is cancelled. A stopped service is shown as such and
+// picked up again once it is back.
+func watchStatus(ctx context.Context, out io.Writer, socket string, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		snap, err := control.Fetch(ctx, socket)
+		if ctx.Err() != nil {
+			return nil
+		}
+		fmt.Fprint(out, clearScreen)
+		now := time.Now()
+		if err != nil {
+			fmt.Fprintf(out, "nota transcribe  %s\n\n", now.Format("15:04:05"))
+			if errors.Is(err, control.ErrNotRunning) {
+				fmt.Fprintln(out, "Status: not running")
+			} else {
+				fmt.Fprintf(out, "Status: unavailable: %v\n", err)
+			}
+		} else {
+			printDashboard(out, snap, now)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// printDashboard renders a snapshot compactly, e.g.
+//
+//	nota transcribe  14:32:05  up 3h12m0s
+//
+//	Queue: 1 waiting, 2 in flight
+//	  uploading     meeting.m4a   1m2s
+//	  queued        memo.m4a      4s
+func printDashboard(out io.Writer, snap *control.Snapshot, now time.Time) {
+	fmt.Fprintf(out, "nota transcribe  %s  up %s\n\n",
+		now.Format("15:04:05"), now.Sub(snap.Started).Round(time.Second))
+
+	fmt.Fprintf(out, "Queue: %d waiting, %d in flight\n", snap.Queued, len(snap.InFlight))
+	for _, f := range snap.InFlight {
+		fmt.Fprintf(out, "  %-13s %-30s %s\n", f.Stage, filepath.Base(f.Path), now.Sub(f.Since).Round(time.Second))
+	}
+
+	if len(snap.Recent) > 0 {
+		fmt.Fprintln(out, "\nRecent:")
+		for _, c := range snap.Recent {
+			line := fmt.Sprintf("  %s  %-9s %s", c.Time.Local().Format("15:04:05"), c.Status, filepath.Base(c.Path))
+			if c.Category != "" {
+				line += " (" + c.Category + ")"
+			}
+			if c.Status == history.StatusFailed && c.Stage != "" {
+				line += " after " + c.Stage
+			}
+			fmt.Fprintln(out, line)
+		}
+	}
+
+	if len(snap.Jobs) > 0 {
+		fmt.Fprintln(out, "\nJobs:")
+		for _, j := range snap.Jobs {
+			fmt.Fprintln(out, jobLine(j, now))
+		}
+	}
+
+	fmt.Fprintf(out, "\nSince start: %d completed, %d failed, %d skipped\n", snap.Completed, snap.Failed, snap.Skipped)
+	printFailures(out, snap.Errors)
+}
+
+// jobLine renders a scheduled job with the outcome of its last run, e.g.
+//
+//	backup           ok       next Jan 23 03:00  notes-20261022T030000Z.tar.zst, 812 files, 1 old backups removed
+func jobLine(j control.JobState, now time.Time) string {
+	status, detail := "waiting", ""
+	switch {
+	case j.Running:
+		status = "running"
+	case j.LastError != "":
+		status, detail = "failed", j.LastError
+	case !j.LastRun.IsZero():
+		status, detail = "ok", j.LastResult
+	}
+
+	next := "never"
+	if !j.Next.IsZero() {
+		at := j.Next.In(now.Location())
+		next = at.Format("15:04")
+		if y, m, d := at.Date(); y != now.Year() || m != now.Month() || d != now.Day() {
+			next = at.Format("Jan 2 15:04")
+		}
+	}
+	line := fmt.Sprintf("  %-16s %-8s next %-13s %s", j.Name, status, next, detail)
+	return strings.TrimRight(line, " ")
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/control"
+)
+
+func TestPrintDashboard(t *testing.T) {
+	now := time.Date(2026, 1, 22, 12, 0, 0, 0, time.Local)
+	snap := &control.Snapshot{
+		Started: now.Add(-3 * time.Hour),
+		Queued:  1,
+		InFlight: []control.FileState{
+			{Path: "/in/meeting.m4a", Stage: "uploading", Since: now.Add(-62 * time.Second)},
+			{Path: "/in/memo.m4a", Stage: "queued", Since: now.Add(-4 * time.Second)},
+		},
+		Completed: 12,
+		Failed:    1,
+		Errors:    map[string]int{"api_unreachable": 1},
+		Recent: []control.Completion{
+			{Time: now.Add(-time.Minute), Path: "/in/call.m4a", Status: "failed", Category: "api_unreachable", Stage: "stable"},
+		},
+		Jobs: []control.JobState{
+			{Name: "backup", Type: "backup", Next: now.Add(15 * time.Hour), LastRun: now.Add(-9 * time.Hour), LastResult: "notes.tar.zst, 812 files"},
+			{Name: "index_refresh", Type: "index_refresh", Next: now.Add(30 * time.Minute), Running: true},
+		},
+	}
+
+	var out bytes.Buffer
+	printDashboard(&out, snap, now)
+
+	for _, want := range []string{
+		"up 3h0m0s",
+		"Queue: 1 waiting, 2 in flight",
+		"uploading     meeting.m4a",
+		"1m2s",
+		"11:59:00  failed    call.m4a (api_unreachable) after stable",
+		"backup           ok       next Jan 23 03:00  notes.tar.zst, 812 files",
+		"index_refresh    running  next 12:30",
+		"Since start: 12 completed, 1 failed, 0 skipped",
+		"api_unreachable        1",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected dashboard to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestWatchStatus_NotRunning(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var out bytes.Buffer
+	if err := watchStatus(ctx, &out, filepath.Join(t.TempDir(), control.SocketName), 10*time.Millisecond); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(out.String(), "Status: not running") {
+		t.Errorf("expected not running, got:\n%s", out.String())
+	}
+}
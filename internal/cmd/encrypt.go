@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/crypt"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/index"
+	"github.com/spf13/cobra"
+)
+
+// cryptJSON is the JSON form of an encrypt or decrypt run.
+type cryptJSON struct {
+	Notes []string `json:"notes"`
+}
+
+// NewEncryptCmd creates the encrypt command
+func NewEncryptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "encrypt <note-or-folder>...",
+		Short: "Encrypt notes at rest with age",
+		Long: `Encrypts the given notes, and every note in the given folders, to the age
+recipients listed under encryption.recipients in .nota/config.json. Each note
+is replaced by an ASCII-armored copy with .age appended to its name, so only
+ciphertext syncs.
+
+Decrypt notes again with "nota decrypt".`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+			cfg, err := vault.LoadConfig(vaultRoot)
+			if err != nil {
+				return err
+			}
+			recipients, err := crypt.ParseRecipients(cfg.Encryption.Recipients)
+			if err != nil {
+				return fmt.Errorf("%w (set encryption.recipients in %s)", err, vault.ConfigPath(vaultRoot))
+			}
+
+			paths, err := cryptTargets(args, func(p string) bool {
+				return strings.EqualFold(filepath.Ext(p), index.NoteExt)
+			})
+			if err != nil {
+				return err
+			}
+
+			var done []string
+			for _, p := range paths {
+				encrypted, err := crypt.EncryptFile(p, recipients...)
+				if err != nil {
+					return fmt.Errorf("encrypt %s: %w", p, err)
+				}
+				done = append(done, encrypted)
+			}
+			return printCrypted(cmd, "Encrypted", done)
+		},
+	}
+}
+
+// NewDecryptCmd creates the decrypt command
+func NewDecryptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decrypt <note-or-folder>...",
+		Short: "Decrypt notes encrypted with nota encrypt",
+		Long: `Decrypts the given .age notes, and every .age note in the given folders,
+replacing each with its plaintext. The age identities are read from
+encryption.identity_file in .nota/config.json (default ~/.nota/age/keys.txt),
+or from --identity.
+
+With --stdout the notes are printed instead and left encrypted.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+			cfg, err := vault.LoadConfig(vaultRoot)
+			if err != nil {
+				return err
+			}
+			identityFile, _ := cmd.Flags().GetString("identity")
+			if identityFile == "" {
+				identityFile = cfg.Encryption.IdentityFile
+			}
+			identities, err := crypt.LoadIdentities(identityFile)
+			if err != nil {
+				return err
+			}
+
+			paths, err := cryptTargets(args, crypt.IsEncrypted)
+			if err != nil {
+				return err
+			}
+
+			toStdout, _ := cmd.Flags().GetBool("stdout")
+			var done []string
+			for _, p := range paths {
+				if toStdout {
+					plain, err := crypt.ReadNote(p, identities...)
+					if err != nil {
+						return err
+					}
+					cmd.OutOrStdout().Write(plain)
+					continue
+				}
+				decrypted, err := crypt.DecryptFile(p, identities...)
+				if err != nil {
+					return fmt.Errorf("decrypt %s: %w", p, err)
+				}
+				done = append(done, decrypted)
+			}
+			if toStdout {
+				return nil
+			}
+			return printCrypted(cmd, "Decrypted", done)
+		},
+	}
+
+	cmd.Flags().String("identity", "", "File holding the age identities (default: encryption.identity_file)")
+	cmd.Flags().Bool("stdout", false, "Print the decrypted notes instead of replacing them")
+
+	return cmd
+}
+
+// cryptTargets expands args, notes or folders, to the files match accepts.
+// Files named directly must be accepted; hidden entries in folders are
+// skipped.
+func cryptTargets(args []string, match func(string) bool) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if !match(arg) {
+				return nil, fmt.Errorf("%s: not a note this command applies to", arg)
+			}
+			paths = append(paths, arg)
+			continue
+		}
+		err = filepath.WalkDir(arg, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if p != arg && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.Type().IsRegular() && match(p) {
+				paths = append(paths, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// printCrypted reports the notes an encrypt or decrypt run wrote.
+func printCrypted(cmd *cobra.Command, verb string, paths []string) error {
+	out := cmd.OutOrStdout()
+	if JSONOutput(cmd) {
+		return writeJSON(out, cryptJSON{Notes: nonNil(paths)})
+	}
+	for _, p := range paths {
+		fmt.Fprintf(out, "%s %s\n", verb, p)
+	}
+	if len(paths) == 0 {
+		fmt.Fprintln(out, "No notes found")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+)
+
+func TestEncryptDecryptCmd(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("failed to generate identity: %v", err)
+	}
+	keys := filepath.Join(t.TempDir(), "keys.txt")
+	os.WriteFile(keys, []byte(identity.String()+"\n"), 0600)
+	config := `{"encryption": {"recipients": ["` + identity.Recipient().String() + `"], "identity_file": "` + keys + `"}}`
+	os.WriteFile(vault.ConfigPath(vaultRoot), []byte(config), 0644)
+
+	os.MkdirAll(filepath.Join("Areas", "Medical"), 0755)
+	os.WriteFile(filepath.Join("Areas", "Medical", "results.md"), []byte("# Results\n"), 0644)
+	os.WriteFile(filepath.Join("Areas", "Medical", "scan.png"), []byte("png"), 0644)
+
+	var buf bytes.Buffer
+	cmd := NewEncryptCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{filepath.Join("Areas", "Medical")})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	encrypted := filepath.Join("Areas", "Medical", "results.md.age")
+	if _, err := os.Stat(encrypted); err != nil {
+		t.Fatalf("expected the note to be encrypted: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join("Areas", "Medical", "scan.png.age")); err == nil {
+		t.Error("expected only notes to be encrypted")
+	}
+
+	buf.Reset()
+	cmd = NewDecryptCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{encrypted, "--stdout"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if buf.String() != "# Results\n" {
+		t.Errorf("expected the plaintext on stdout, got %q", buf.String())
+	}
+
+	buf.Reset()
+	cmd = NewDecryptCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"Areas"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Decrypted") {
+		t.Errorf("expected the note to be decrypted, got: %s", buf.String())
+	}
+	if data, _ := os.ReadFile(filepath.Join("Areas", "Medical", "results.md")); string(data) != "# Results\n" {
+		t.Errorf("expected the plaintext back, got %q", data)
+	}
+}
+
+func TestEncryptCmd_NoRecipients(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+	os.WriteFile("memo.md", []byte("# Memo\n"), 0644)
+
+	cmd := NewEncryptCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"memo.md"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "recipients") {
+		t.Errorf("expected a missing recipients error, got: %v", err)
+	}
+}
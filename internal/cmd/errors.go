@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+)
+
+// Error categories. Commands wrap these (or the package errors they stand
+// for) so scripts and service managers can tell failures apart by exit code.
+var (
+	// ErrNotInVault is returned by commands that need a vault when run
+	// outside one.
+	ErrNotInVault = vault.ErrNotInVault
+	// ErrConfigInvalid is returned when the transcription config cannot be
+	// loaded or fails validation.
+	ErrConfigInvalid = transcribe.ErrInvalidConfig
+	// ErrDaemonNotRunning is returned by commands that need the transcription
+	// service when it is not running.
+	ErrDaemonNotRunning = errors.New("transcription service is not running")
+	// ErrAPIUnreachable is returned when the transcription API cannot be
+	// reached.
+	ErrAPIUnreachable = transcribe.ErrAPIUnreachable
+)
+
+// Process exit codes.
+const (
+	ExitOK               = 0
+	ExitFailure          = 1
+	ExitNotInVault       = 2
+	ExitConfigInvalid    = 3
+	ExitDaemonNotRunning = 4
+	ExitAPIUnreachable   = 5
+)
+
+// errorCategory names a class of failure and its exit code.
+type errorCategory struct {
+	name string
+	code int
+	errs []error
+}
+
+// errorCategories is checked in order; the first match wins.
+var errorCategories = []errorCategory{
+	{"not-in-vault", ExitNotInVault, []error{ErrNotInVault, ErrNotAVault}},
+	{"daemon-not-running", ExitDaemonNotRunning, []error{ErrDaemonNotRunning}},
+	{"api-unreachable", ExitAPIUnreachable, []error{ErrAPIUnreachable}},
+	{"config-invalid", ExitConfigInvalid, []error{ErrConfigInvalid}},
+}
+
+// Category returns the category name and exit code for err: "" and
+// ExitFailure for errors outside the categories, and ExitOK for nil.
+func Category(err error) (string, int) {
+	if err == nil {
+		return "", ExitOK
+	}
+	for _, c := range errorCategories {
+		for _, target := range c.errs {
+			if errors.Is(err, target) {
+				return c.name, c.code
+			}
+		}
+	}
+	return "", ExitFailure
+}
+
+// ExitCode returns the process exit code for an error returned by a command.
+func ExitCode(err error) int {
+	_, code := Category(err)
+	return code
+}
+
+// errorJSON is the --json form of a command error.
+type errorJSON struct {
+	Error    string `json:"error"`
+	Category string `json:"category,omitempty"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// PrintError writes err to w as "Error [category]: message", leaving out
+// the category for uncategorized errors, or as a JSON object if asJSON is
+// set.
+func PrintError(w io.Writer, err error, asJSON bool) {
+	if asJSON {
+		name, code := Category(err)
+		writeJSON(w, errorJSON{Error: err.Error(), Category: name, ExitCode: code})
+		return
+	}
+	if name, _ := Category(err); name != "" {
+		fmt.Fprintf(w, "Error [%s]: %v\n", name, err)
+		return
+	}
+	fmt.Fprintf(w, "Error: %v\n", err)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+)
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		category string
+		code     int
+	}{
+		{"nil", nil, "", ExitOK},
+		{"plain", errors.New("boom"), "", ExitFailure},
+		{"not in vault", fmt.Errorf("not in a vault: %w", vault.ErrNotInVault), "not-in-vault", ExitNotInVault},
+		{"hw outside vault", ErrNotAVault, "not-in-vault", ExitNotInVault},
+		{"config invalid", fmt.Errorf("create service: %w: %w", transcribe.ErrInvalidConfig, transcribe.ErrAPIURLRequired), "config-invalid", ExitConfigInvalid},
+		{"daemon not running", ErrDaemonNotRunning, "daemon-not-running", ExitDaemonNotRunning},
+		{"api unreachable", fmt.Errorf("transcription failed: %w", transcribe.ErrAPIUnreachable), "api-unreachable", ExitAPIUnreachable},
+	}
+
+	for _, tt := range tests {
+		category, code := Category(tt.err)
+		if category != tt.category || code != tt.code {
+			t.Errorf("%s: expected %q/%d, got %q/%d", tt.name, tt.category, tt.code, category, code)
+		}
+	}
+}
+
+func TestPrintError(t *testing.T) {
+	var buf bytes.Buffer
+	PrintError(&buf, ErrDaemonNotRunning, false)
+	if got := buf.String(); got != "Error [daemon-not-running]: transcription service is not running\n" {
+		t.Errorf("unexpected output: %q", got)
+	}
+
+	buf.Reset()
+	PrintError(&buf, errors.New("boom"), false)
+	if got := buf.String(); got != "Error: boom\n" {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
+
+func TestPrintError_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	PrintError(&buf, ErrDaemonNotRunning, true)
+
+	var got errorJSON
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", buf.String(), err)
+	}
+	expected := errorJSON{Error: "transcription service is not running", Category: "daemon-not-running", ExitCode: ExitDaemonNotRunning}
+	if got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
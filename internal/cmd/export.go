@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/export"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/index"
+	"github.com/spf13/cobra"
+)
+
+// NewExportCmd creates the export command
+func NewExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <note-or-folder>...",
+		Short: "Export notes to HTML or PDF",
+		Long: `Renders the given notes, and every note in the given folders, to standalone
+HTML pages in the output directory, keeping their paths within the vault.
+
+Links between exported notes point at each other's pages, links to notes left
+out become plain text, and embedded images and linked attachments are copied
+alongside the pages.
+
+With --pdf each page is converted to PDF by an external tool instead; set the
+command with --pdf-command, where {input} and {output} stand for the page and
+the PDF.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+
+			paths := make([]string, len(args))
+			for i, arg := range args {
+				paths[i], err = vaultPath(vaultRoot, arg)
+				if err != nil {
+					return err
+				}
+			}
+
+			outDir, _ := cmd.Flags().GetString("out")
+			pdf, _ := cmd.Flags().GetBool("pdf")
+			pdfCommand, _ := cmd.Flags().GetString("pdf-command")
+			opts := export.Options{Paths: paths, OutDir: outDir, Format: export.FormatHTML, PDFCommand: pdfCommand}
+			if pdf {
+				opts.Format = export.FormatPDF
+			}
+
+			ix, err := index.Open(vaultRoot)
+			if err != nil {
+				return fmt.Errorf("index vault: %w", err)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			result, err := export.Export(ctx, ix, opts)
+			if err != nil {
+				return fmt.Errorf("export: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if JSONOutput(cmd) {
+				return writeJSON(out, result)
+			}
+			fmt.Fprintf(out, "Exported %d notes and %d attachments to %s\n", len(result.Notes), len(result.Attachments), outDir)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("out", "o", "", "Directory to write the export to (required)")
+	cmd.Flags().Bool("pdf", false, "Convert the pages to PDF")
+	cmd.Flags().String("pdf-command", export.DefaultPDFCommand, "Command converting a page to PDF")
+	cmd.MarkFlagRequired("out")
+
+	return cmd
+}
+
+// vaultPath returns the path p, relative to the current directory, as a
+// slash-separated path relative to the vault root.
+func vaultPath(vaultRoot, p string) (string, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	// The vault root may have been found through a symlinked directory
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	root := vaultRoot
+	if resolved, err := filepath.EvalSymlinks(vaultRoot); err == nil {
+		root = resolved
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the vault", p)
+	}
+	return filepath.ToSlash(rel), nil
+}
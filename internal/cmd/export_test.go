@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportCmd(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+
+	projects := filepath.Join(vaultRoot, "Projects")
+	if err := os.MkdirAll(projects, 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projects, "garden.md"), []byte("# Garden\n\nSee [[beds]].\n"), 0644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projects, "beds.md"), []byte("# Beds\n"), 0644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+	os.Chdir(projects)
+
+	outDir := t.TempDir()
+	var buf bytes.Buffer
+	cmd := NewExportCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{".", "--out", outDir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Exported 2 notes") {
+		t.Errorf("expected 2 notes exported, got: %s", buf.String())
+	}
+	page, err := os.ReadFile(filepath.Join(outDir, "Projects", "garden.html"))
+	if err != nil {
+		t.Fatalf("expected the page to be written: %v", err)
+	}
+	if !strings.Contains(string(page), `<a href="beds.html">beds</a>`) {
+		t.Errorf("expected the wikilink to point at the exported page, got:\n%s", page)
+	}
+}
+
+func TestExportCmd_OutsideVault(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	cmd := NewExportCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"..", "--out", t.TempDir()})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "outside the vault") {
+		t.Errorf("expected an outside-the-vault error, got: %v", err)
+	}
+}
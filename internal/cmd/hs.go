@@ -3,7 +3,7 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/TechnicallyShaun/nota-orbis/internal/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
 	"github.com/spf13/cobra"
 )
 
@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/TechnicallyShaun/nota-orbis/internal/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
 	"github.com/spf13/cobra"
 )
 
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/importer"
+	"github.com/spf13/cobra"
+)
+
+// NewImportCmd creates the import command
+func NewImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <export>",
+		Short: "Import notes exported from other tools",
+		Long: `Imports a folder of markdown notes, an Evernote .enex export or a Notion
+"Markdown & CSV" export (zipped or unzipped) into the vault, under the folder
+given with --to, which must be inside one of the PARA folders.
+
+File names are cleaned up (Notion's page ids are dropped), frontmatter gets
+the note's title, dates, tags and source, and links between the imported
+notes are rewritten to their new names. Attachments are copied alongside.
+Existing notes are never overwritten.
+
+The kind of export is detected from the path unless --source is given.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+
+			folder, _ := cmd.Flags().GetString("to")
+			source, _ := cmd.Flags().GetString("source")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			if source != "" && !importer.Source(source).Valid() {
+				return importer.ErrInvalidSource
+			}
+
+			result, err := importer.Import(vaultRoot, args[0], importer.Options{
+				Source: importer.Source(source),
+				Folder: folder,
+				DryRun: dryRun,
+			})
+			if err != nil {
+				return fmt.Errorf("import: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if JSONOutput(cmd) {
+				return writeJSON(out, result)
+			}
+			verb := "Imported"
+			if dryRun {
+				verb = "Would import"
+				for _, p := range append(result.Notes, result.Attachments...) {
+					fmt.Fprintf(out, "  %s\n", p)
+				}
+			}
+			fmt.Fprintf(out, "%s %d notes and %d attachments from %s\n", verb, len(result.Notes), len(result.Attachments), result.Source)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("to", "", "Vault folder to import into, inside a PARA folder (required)")
+	cmd.Flags().String("source", "", "Kind of export: markdown, evernote or notion (default: detected)")
+	cmd.Flags().Bool("dry-run", false, "List what would be imported without writing anything")
+	cmd.MarkFlagRequired("to")
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportCmd(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "memo.md"), []byte("# Memo\n"), 0644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd := NewImportCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{src, "--to", "Inbox/Imported", "--dry-run"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Would import 1 notes") {
+		t.Errorf("expected a dry run report, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	cmd = NewImportCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{src, "--to", "Inbox/Imported"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(vaultRoot, "Inbox", "Imported", "memo.md")); err != nil {
+		t.Errorf("expected the note to be imported: %v", err)
+	}
+}
+
+func TestImportCmd_InvalidSource(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	cmd := NewImportCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{t.TempDir(), "--to", "Inbox", "--source", "onenote"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an invalid source error")
+	}
+}
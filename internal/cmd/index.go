@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os/signal"
+	"syscall"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/index"
+	"github.com/spf13/cobra"
+)
+
+// indexJSON is the JSON form of an index update.
+type indexJSON struct {
+	Notes   int      `json:"notes"`
+	Updated []string `json:"updated"`
+}
+
+// NewIndexCmd creates the index command group
+func NewIndexCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "index",
+		Short: "Manage the vault's note index",
+		Long: `Commands for managing the note index saved in .nota/index.json, which tools
+use to look notes up without rescanning the vault.`,
+	}
+
+	cmd.AddCommand(newIndexBuildCmd())
+	cmd.AddCommand(newIndexWatchCmd())
+
+	return cmd
+}
+
+// newIndexBuildCmd creates the index build command
+func newIndexBuildCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build",
+		Short: "Bring the index up to date",
+		Long: `Brings the saved index up to date with the vault, re-reading only the notes
+that changed since it was last saved. With --full the index is rebuilt from
+scratch.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+
+			full, _ := cmd.Flags().GetBool("full")
+			var ix *index.Index
+			var updated []string
+			if saved, err := index.Load(vaultRoot); err == nil && !full {
+				ix = saved
+				updated, err = ix.Refresh()
+				if err != nil {
+					return fmt.Errorf("refresh index: %w", err)
+				}
+			} else {
+				ix, err = index.Build(vaultRoot)
+				if err != nil {
+					return fmt.Errorf("build index: %w", err)
+				}
+				for _, n := range ix.Notes() {
+					updated = append(updated, n.Path)
+				}
+			}
+			if err := ix.Save(); err != nil {
+				return fmt.Errorf("save index: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if JSONOutput(cmd) {
+				return writeJSON(out, indexJSON{Notes: ix.Len(), Updated: nonNil(updated)})
+			}
+			fmt.Fprintf(out, "Indexed %d notes (%d updated)\n", ix.Len(), len(updated))
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("full", false, "Rebuild the index from scratch")
+
+	return cmd
+}
+
+// newIndexWatchCmd creates the index watch command
+func newIndexWatchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "watch",
+		Short: "Keep the index up to date as notes change",
+		Long: `Brings the saved index up to date, then watches the vault and updates the
+index as notes are created, edited, moved and deleted, until interrupted.
+The index is saved after each batch of changes.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+
+			ix, err := index.Open(vaultRoot)
+			if err != nil {
+				return fmt.Errorf("open index: %w", err)
+			}
+			if err := ix.Save(); err != nil {
+				return fmt.Errorf("save index: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if !JSONOutput(cmd) {
+				fmt.Fprintf(out, "Watching %d notes in %s (Ctrl+C to stop)\n", ix.Len(), vaultRoot)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			var saveErr error
+			err = ix.Watch(ctx, func(paths []string) {
+				if saveErr = ix.Save(); saveErr != nil {
+					stop()
+					return
+				}
+				printIndexUpdate(out, JSONOutput(cmd), ix.Len(), paths)
+			})
+			if err != nil {
+				return fmt.Errorf("watch vault: %w", err)
+			}
+			if saveErr != nil {
+				return fmt.Errorf("save index: %w", saveErr)
+			}
+			return nil
+		},
+	}
+}
+
+// printIndexUpdate prints the paths updated by one batch of changes.
+func printIndexUpdate(out io.Writer, asJSON bool, notes int, paths []string) {
+	if asJSON {
+		writeJSON(out, indexJSON{Notes: notes, Updated: paths})
+		return
+	}
+	for _, p := range paths {
+		fmt.Fprintf(out, "Updated %s\n", p)
+	}
+}
+
+// nonNil returns s, or an empty slice if s is nil, so it encodes as [].
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/index"
+)
+
+func TestIndexBuildCmd(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	if err := os.WriteFile(filepath.Join(vaultRoot, "memo.md"), []byte("# Memo\n"), 0644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd := NewIndexCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"build"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Indexed 1 notes (1 updated)") {
+		t.Errorf("expected the note to be indexed, got: %s", buf.String())
+	}
+	if _, err := os.Stat(index.Path(vaultRoot)); err != nil {
+		t.Errorf("expected the index to be saved: %v", err)
+	}
+
+	// A second build only reports what changed
+	buf.Reset()
+	root := NewRootCmd()
+	root.SetOut(&buf)
+	root.SetArgs([]string{"index", "build", "--json"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	var got indexJSON
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expected JSON output, got: %s", buf.String())
+	}
+	if got.Notes != 1 || len(got.Updated) != 0 {
+		t.Errorf("expected 1 note and none updated, got %+v", got)
+	}
+}
+
+func TestIndexBuildCmd_RequiresVault(t *testing.T) {
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(t.TempDir())
+
+	cmd := NewIndexCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"build"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected error outside a vault")
+	}
+}
@@ -2,11 +2,18 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
 
-	"github.com/TechnicallyShaun/nota-orbis/internal/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
 	"github.com/spf13/cobra"
 )
 
+// initJSON is the --json output of the init command.
+type initJSON struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
 // NewInitCmd creates the init command
 func NewInitCmd() *cobra.Command {
 	return &cobra.Command{
@@ -21,6 +28,13 @@ func NewInitCmd() *cobra.Command {
 				return err
 			}
 
+			if JSONOutput(cmd) {
+				path, err := filepath.Abs(".")
+				if err != nil {
+					return err
+				}
+				return writeJSON(cmd.OutOrStdout(), initJSON{Name: name, Path: path})
+			}
 			fmt.Fprintf(cmd.OutOrStdout(), "Initialized vault '%s'\n", name)
 			return nil
 		},
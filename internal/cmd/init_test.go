@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -72,3 +73,27 @@ func TestInitCmd_ReturnsErrorWhenVaultExists(t *testing.T) {
 		t.Error("expected error when vault already exists")
 	}
 }
+
+func TestInitCmd_JSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	var buf bytes.Buffer
+	root := NewRootCmd()
+	root.SetOut(&buf)
+	root.SetArgs([]string{"init", "test-vault", "--json"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	var got initJSON
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", buf.String(), err)
+	}
+	wd, _ := os.Getwd()
+	if got.Name != "test-vault" || got.Path != wd {
+		t.Errorf("unexpected output: %+v", got)
+	}
+}
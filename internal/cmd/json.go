@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// JSONOutput reports whether the global --json flag is set for cmd.
+func JSONOutput(cmd *cobra.Command) bool {
+	f := cmd.Flag("json")
+	return f != nil && f.Value.String() == "true"
+}
+
+// writeJSON writes v to w as indented JSON followed by a newline.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
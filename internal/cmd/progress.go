@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe"
+)
+
+// ConsoleProgress prints one line per pipeline stage transition for foreground runs
+type ConsoleProgress struct {
+	out io.Writer
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewConsoleProgress creates a progress reporter that writes to out
+func NewConsoleProgress(out io.Writer) *ConsoleProgress {
+	return &ConsoleProgress{out: out, now: time.Now}
+}
+
+// Report prints a stage transition, e.g.
+// "14:30:07 meeting.m4a: uploading (2.4 MB) [6.0s]"
+func (p *ConsoleProgress) Report(event transcribe.ProgressEvent) {
+	line := fmt.Sprintf("%s %s: %s", p.now().Format("15:04:05"), filepath.Base(event.Path), event.Stage)
+
+	switch event.Stage {
+	case transcribe.StageDetected, transcribe.StageUploading:
+		line += fmt.Sprintf(" (%s)", formatSize(event.Size))
+	}
+
+	line += fmt.Sprintf(" [%s]", event.Elapsed.Round(100*time.Millisecond))
+
+	switch {
+	case event.Stage == transcribe.StageFailed && event.Err != nil:
+		line += ": " + event.Err.Error()
+	case (event.Stage == transcribe.StageArchived || event.Stage == transcribe.StageCompleted) && event.Output != "":
+		line += " -> " + event.Output
+	case event.Detail != "":
+		line += ": " + event.Detail
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(p.out, line)
+}
+
+// BarProgress renders a single-line progress bar for batch imports. When the
+// output is not a terminal it prints one line per finished file instead.
+type BarProgress struct {
+	out    io.Writer
+	tty    bool
+	total  int
+	done   int
+	failed int
+	mu     sync.Mutex
+}
+
+// NewBarProgress creates a progress bar for total files that writes to out
+func NewBarProgress(out io.Writer, total int) *BarProgress {
+	return &BarProgress{out: out, tty: isTerminal(out), total: total}
+}
+
+// Report advances the bar when a file reaches a final stage
+func (p *BarProgress) Report(event transcribe.ProgressEvent) {
+	switch event.Stage {
+	case transcribe.StageArchived, transcribe.StageCompleted, transcribe.StageDryRun, transcribe.StageFailed, transcribe.StageSkipped:
+	default:
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done++
+	if event.Stage == transcribe.StageFailed {
+		p.failed++
+	}
+
+	name := filepath.Base(event.Path)
+	if !p.tty {
+		line := fmt.Sprintf("[%d/%d] %s: %s", p.done, p.total, name, event.Stage)
+		if event.Err != nil {
+			line += ": " + event.Err.Error()
+		}
+		fmt.Fprintln(p.out, line)
+		return
+	}
+
+	if event.Err != nil {
+		// Print the failure above the bar
+		fmt.Fprintf(p.out, "\r\033[K%s: %v\n", name, event.Err)
+	}
+	fmt.Fprintf(p.out, "\r\033[K%s", p.bar(name))
+}
+
+// Finish ends the progress bar line
+func (p *BarProgress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.tty && p.done > 0 {
+		fmt.Fprintln(p.out)
+	}
+}
+
+// bar renders e.g. "[=========>          ] 12/30 (1 failed) memo.m4a"
+func (p *BarProgress) bar(current string) string {
+	const width = 30
+	filled := width
+	if p.total > 0 {
+		filled = p.done * width / p.total
+	}
+
+	bar := strings.Repeat("=", filled)
+	if filled < width {
+		bar += ">" + strings.Repeat(" ", width-filled-1)
+	}
+
+	line := fmt.Sprintf("[%s] %d/%d", bar, p.done, p.total)
+	if p.failed > 0 {
+		line += fmt.Sprintf(" (%d failed)", p.failed)
+	}
+	return line + " " + current
+}
+
+// isTerminal reports whether w is an interactive terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// formatSize renders a byte count in human-readable units
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
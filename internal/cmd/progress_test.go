@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe"
+)
+
+func TestConsoleProgress_Report(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewConsoleProgress(&buf)
+	p.now = func() time.Time { return time.Date(2026, 1, 22, 14, 30, 7, 0, time.UTC) }
+
+	p.Report(transcribe.ProgressEvent{
+		Path:    "/mnt/sync/voice-notes/meeting.m4a",
+		Stage:   transcribe.StageUploading,
+		Size:    2516582,
+		Elapsed: 6 * time.Second,
+	})
+	p.Report(transcribe.ProgressEvent{
+		Path:    "/mnt/sync/voice-notes/meeting.m4a",
+		Stage:   transcribe.StageArchived,
+		Elapsed: 12340 * time.Millisecond,
+		Output:  "/vault/Inbox/meeting.md",
+	})
+	p.Report(transcribe.ProgressEvent{
+		Path:    "/mnt/sync/voice-notes/other.m4a",
+		Stage:   transcribe.StageFailed,
+		Elapsed: time.Second,
+		Err:     errors.New("connection refused"),
+	})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expected := []string{
+		"14:30:07 meeting.m4a: uploading (2.4 MB) [6s]",
+		"14:30:07 meeting.m4a: archived [12.3s] -> /vault/Inbox/meeting.md",
+		"14:30:07 other.m4a: failed [1s]: connection refused",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d: %q", len(expected), len(lines), lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("line %d: expected %q, got %q", i, expected[i], lines[i])
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		input    int64
+		expected string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{2048, "2.0 KB"},
+		{2516582, "2.4 MB"},
+		{5 * 1024 * 1024 * 1024, "5.0 GB"},
+	}
+
+	for _, tc := range tests {
+		if got := formatSize(tc.input); got != tc.expected {
+			t.Errorf("formatSize(%d) = %q, expected %q", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestBarProgress_NonTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewBarProgress(&buf, 2)
+
+	p.Report(transcribe.ProgressEvent{Path: "/old/a.m4a", Stage: transcribe.StageUploading})
+	p.Report(transcribe.ProgressEvent{Path: "/old/a.m4a", Stage: transcribe.StageCompleted})
+	p.Report(transcribe.ProgressEvent{Path: "/old/b.m4a", Stage: transcribe.StageFailed, Err: errors.New("corrupt audio")})
+	p.Finish()
+
+	expected := "[1/2] a.m4a: completed\n[2/2] b.m4a: failed: corrupt audio\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestBarProgress_Bar(t *testing.T) {
+	p := &BarProgress{total: 4, done: 1, failed: 1}
+
+	expected := "[=======>                      ] 1/4 (1 failed) memo.m4a"
+	if got := p.bar("memo.m4a"); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	p.done = 4
+	expected = "[==============================] 4/4 (1 failed) memo.m4a"
+	if got := p.bar("memo.m4a"); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
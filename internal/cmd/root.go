@@ -10,12 +10,30 @@ func NewRootCmd() *cobra.Command {
 		Use:   "nota",
 		Short: "Personal knowledge management system",
 		Long:  "Nota Orbis - Personal knowledge management system with PARA-inspired structure and AI-driven workflows",
+		// main prints errors with their category and exits with its code;
+		// usage is only shown on request, not after every failure
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
 
+	rootCmd.PersistentFlags().Bool("json", false, "Print machine-readable JSON instead of text")
+
 	rootCmd.AddCommand(NewInitCmd())
 	rootCmd.AddCommand(NewHwCmd())
 	rootCmd.AddCommand(NewVersionCmd())
 	rootCmd.AddCommand(NewTranscribeCmd())
+	rootCmd.AddCommand(NewTemplatesCmd())
+	rootCmd.AddCommand(NewIndexCmd())
+	rootCmd.AddCommand(NewSearchCmd())
+	rootCmd.AddCommand(NewExportCmd())
+	rootCmd.AddCommand(NewImportCmd())
+	rootCmd.AddCommand(NewCaptureCmd())
+	rootCmd.AddCommand(NewAppendCmd())
+	rootCmd.AddCommand(NewEncryptCmd())
+	rootCmd.AddCommand(NewDecryptCmd())
+	rootCmd.AddCommand(NewBackupCmd())
+	rootCmd.AddCommand(NewConflictsCmd(nil))
+	rootCmd.AddCommand(NewUpgradeCmd())
 
 	return rootCmd
 }
@@ -17,7 +17,7 @@ func TestNewRootCmd(t *testing.T) {
 		subcommands[cmd.Use] = true
 	}
 
-	expected := []string{"init <name>", "hw", "version"}
+	expected := []string{"init <name>", "hw", "version", "templates", "index", "search <query>", "export <note-or-folder>...", "import <export>", "encrypt <note-or-folder>...", "decrypt <note-or-folder>...", "backup", "conflicts", "upgrade"}
 	for _, name := range expected {
 		if !subcommands[name] {
 			t.Errorf("expected subcommand '%s' to be registered", name)
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/crypt"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/index"
+	"github.com/spf13/cobra"
+)
+
+// searchJSON is the JSON form of a search.
+type searchJSON struct {
+	Matches []index.Match `json:"matches"`
+}
+
+// NewSearchCmd creates the search command
+func NewSearchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Find notes containing text",
+		Long: `Lists the notes whose title or content contains the query, ignoring case,
+with the first line that matches. Notes are looked up through the vault's
+index, which is brought up to date first.
+
+Encrypted notes are left out unless --include-encrypted is given. They are
+then decrypted in memory with the age identities from encryption.identity_file
+in .nota/config.json, or from --identity, and searched too; their plaintext is
+never written to disk or the index. Notes that cannot be decrypted are
+reported and skipped.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+
+			var identities []age.Identity
+			if includeEncrypted, _ := cmd.Flags().GetBool("include-encrypted"); includeEncrypted {
+				cfg, err := vault.LoadConfig(vaultRoot)
+				if err != nil {
+					return err
+				}
+				identityFile, _ := cmd.Flags().GetString("identity")
+				if identityFile == "" {
+					identityFile = cfg.Encryption.IdentityFile
+				}
+				if identities, err = crypt.LoadIdentities(identityFile); err != nil {
+					return err
+				}
+			}
+
+			ix, err := index.Open(vaultRoot)
+			if err != nil {
+				return fmt.Errorf("index vault: %w", err)
+			}
+			matches, err := ix.Search(strings.Join(args, " "), identities...)
+			if errors.Is(err, index.ErrNotDecrypted) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+			} else if err != nil {
+				return fmt.Errorf("search notes: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if JSONOutput(cmd) {
+				if matches == nil {
+					matches = []index.Match{}
+				}
+				return writeJSON(out, searchJSON{Matches: matches})
+			}
+			for _, m := range matches {
+				lock := ""
+				if m.Encrypted {
+					lock = " (encrypted)"
+				}
+				if m.Line == 0 {
+					fmt.Fprintf(out, "%s%s: %s\n", m.Path, lock, m.Title)
+					continue
+				}
+				fmt.Fprintf(out, "%s:%d%s: %s\n", m.Path, m.Line, lock, m.Text)
+			}
+			if len(matches) == 0 {
+				fmt.Fprintln(out, "No notes found")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("include-encrypted", false, "Decrypt encrypted notes in memory and search them too")
+	cmd.Flags().String("identity", "", "File holding the age identities (default: encryption.identity_file)")
+
+	return cmd
+}
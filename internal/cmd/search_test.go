@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/crypt"
+)
+
+func TestSearchCmd_IncludeEncrypted(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("failed to generate identity: %v", err)
+	}
+	keys := filepath.Join(t.TempDir(), "keys.txt")
+	os.WriteFile(keys, []byte(identity.String()+"\n"), 0600)
+	config := `{"encryption": {"identity_file": "` + keys + `"}}`
+	os.WriteFile(vault.ConfigPath(vaultRoot), []byte(config), 0644)
+
+	os.MkdirAll(filepath.Join("Areas", "Medical"), 0755)
+	os.WriteFile("memo.md", []byte("# Memo\n\nBook a blood test.\n"), 0644)
+	results := filepath.Join("Areas", "Medical", "results.md")
+	os.WriteFile(results, []byte("# Results\n\nBlood test: normal.\n"), 0644)
+	if _, err := crypt.EncryptFile(results, identity.Recipient()); err != nil {
+		t.Fatalf("failed to encrypt note: %v", err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		cmd := NewSearchCmd()
+		cmd.SetOut(&buf)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		return buf.String()
+	}
+
+	if output := run("blood", "test"); output != "memo.md:3: Book a blood test.\n" {
+		t.Errorf("expected only the plain note, got:\n%s", output)
+	}
+
+	output := run("blood", "test", "--include-encrypted")
+	expected := "Areas/Medical/results.md.age:3 (encrypted): Blood test: normal.\n" +
+		"memo.md:3: Book a blood test.\n"
+	if output != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, output)
+	}
+	if data, _ := os.ReadFile(results + crypt.Ext); strings.Contains(string(data), "normal") {
+		t.Error("expected the note to stay encrypted")
+	}
+
+	if output := run("dentist"); output != "No notes found\n" {
+		t.Errorf("expected no matches, got:\n%s", output)
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/spf13/cobra"
+)
+
+// NewTemplatesCmd creates the templates command group
+func NewTemplatesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Manage note templates",
+		Long:  "Commands for managing the note templates in .nota/templates",
+	}
+
+	cmd.AddCommand(newTemplatesInitCmd())
+
+	return cmd
+}
+
+// newTemplatesInitCmd creates the templates init command
+func newTemplatesInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init",
+		Short: "Create the starter templates",
+		Long: `Creates .nota/templates in the current vault with the starter templates
+(daily-note, meeting, voice-note and project). Existing templates are kept.
+
+Vaults created with "nota init" already have them.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+
+			created, err := vault.InitTemplates(vaultRoot)
+			if err != nil {
+				return fmt.Errorf("create templates: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(created) == 0 {
+				fmt.Fprintln(out, "All starter templates already exist")
+				return nil
+			}
+			for _, name := range created {
+				fmt.Fprintf(out, "Created %s\n", vault.TemplatePath(vaultRoot, name))
+			}
+			return nil
+		},
+	}
+}
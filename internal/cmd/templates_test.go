@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+)
+
+func TestTemplatesInitCmd_CreatesTemplates(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	var buf bytes.Buffer
+	cmd := NewTemplatesCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"init"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, err := os.Stat(vault.TemplatePath(vaultRoot, "voice-note")); err != nil {
+		t.Errorf("expected voice-note template to exist: %v", err)
+	}
+
+	buf.Reset()
+	cmd = NewTemplatesCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"init"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "already exist") {
+		t.Errorf("expected second run to report existing templates, got: %s", buf.String())
+	}
+}
+
+func TestTemplatesInitCmd_RequiresVault(t *testing.T) {
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(t.TempDir())
+
+	cmd := NewTemplatesCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"init"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected error outside a vault")
+	}
+}
@@ -3,20 +3,33 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
-	"github.com/TechnicallyShaun/nota-orbis/internal/transcribe"
-	"github.com/TechnicallyShaun/nota-orbis/internal/transcribe/pidfile"
-	"github.com/TechnicallyShaun/nota-orbis/internal/transcribe/status"
-	"github.com/TechnicallyShaun/nota-orbis/internal/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/checkpoint"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/control"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/mockasr"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/pidfile"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/status"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/supervisor"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
 	"github.com/spf13/cobra"
+	"golang.org/x/sys/unix"
 )
 
 // Prompter defines the interface for reading user input
@@ -65,16 +78,35 @@ func (p *ReaderPrompter) Prompt(prompt string) (string, error) {
 
 // NewTranscribeCmd creates the transcribe command group
 func NewTranscribeCmd() *cobra.Command {
+	// Generated notes record the version that produced them
+	transcribe.Version = Version
+
 	cmd := &cobra.Command{
 		Use:   "transcribe",
 		Short: "Manage audio transcription service",
 		Long:  "Commands for configuring and managing the audio transcription service",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			// Passed through the environment so daemon children use it too
+			if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+				os.Setenv(transcribe.ProfileEnv, profile)
+			}
+		},
 	}
+	cmd.PersistentFlags().String("profile", "", "Configuration profile to use (default: the profile matching this hostname)")
 
 	cmd.AddCommand(NewTranscribeConfigCmd(nil, false))
+	cmd.AddCommand(newTranscribeDoctorCmd())
 	cmd.AddCommand(newTranscribeStartCmd())
 	cmd.AddCommand(newTranscribeStopCmd())
 	cmd.AddCommand(newTranscribeStatusCmd())
+	cmd.AddCommand(newTranscribeStatsCmd())
+	cmd.AddCommand(newTranscribeTestCmd())
+	cmd.AddCommand(newTranscribeFileCmd())
+	cmd.AddCommand(newTranscribeCompareCmd())
+	cmd.AddCommand(newTranscribeReprocessCmd())
+	cmd.AddCommand(newTranscribeArchiveCmd())
+	cmd.AddCommand(newTranscribeImportCmd())
+	cmd.AddCommand(newTranscribeMockServerCmd())
 
 	return cmd
 }
@@ -98,6 +130,55 @@ func NewTranscribeConfigCmd(prompter Prompter, advanced bool) *cobra.Command {
 	}
 
 	cmd.Flags().Bool("advanced", false, "Prompt for advanced configuration options")
+	cmd.AddCommand(newTranscribeConfigInitCmd())
+	cmd.AddCommand(newTranscribeConfigEditCmd(prompter))
+
+	return cmd
+}
+
+// newTranscribeConfigInitCmd creates the transcribe config init command
+func newTranscribeConfigInitCmd() *cobra.Command {
+	var (
+		printOnly bool
+		force     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Write a commented example configuration",
+		Long: `Writes an example .nota/transcribe.json listing every setting with its default
+and an explanation, for editing by hand instead of using the interactive wizard.
+Replace the placeholder watch_dir and api_url before starting the service.
+
+Lines starting with // are comments and are ignored when the file is loaded.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sample := transcribe.SampleConfig()
+			if printOnly {
+				fmt.Fprint(cmd.OutOrStdout(), sample)
+				return nil
+			}
+
+			vaultRoot, err := vault.FindVaultRoot()
+			if err != nil {
+				return fmt.Errorf("not in a vault: %w", err)
+			}
+
+			configPath := filepath.Join(vaultRoot, vault.VaultMarkerDir, transcribe.ConfigFileName)
+			if _, err := os.Stat(configPath); err == nil && !force {
+				return fmt.Errorf("%s already exists (use --force to overwrite, or --print to view the example)", configPath)
+			}
+			if err := os.WriteFile(configPath, []byte(sample), 0644); err != nil {
+				return fmt.Errorf("write config: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Example configuration written to %s\n", configPath)
+			fmt.Fprintln(cmd.OutOrStdout(), "Edit watch_dir and api_url before running \"nota transcribe start\".")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&printOnly, "print", false, "Print the example to stdout instead of writing it")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing transcribe.json")
 
 	return cmd
 }
@@ -127,15 +208,22 @@ func runTranscribeConfig(cmd *cobra.Command, prompter Prompter, advanced bool) e
 	if err != nil {
 		return err
 	}
+	if apiURL, err = transcribe.NormalizeAPIURL(apiURL); err != nil {
+		return err
+	}
 
-	// Prompt for output_dir (required)
-	outputDir, err := promptRequired(prompter, "Output location (inbox) [required]: ")
+	// Prompt for output_dir (defaults to the vault's inbox)
+	defaultOutputDir := transcribe.VaultVariable + "/Inbox"
+	outputDir, err := prompter.Prompt(fmt.Sprintf("Output location (inbox) [default: %s]: ", defaultOutputDir))
 	if err != nil {
 		return err
 	}
+	if outputDir == "" {
+		outputDir = defaultOutputDir
+	}
 
 	// Prompt for template_path (optional)
-	templatePath, err := prompter.Prompt("Template file [optional, Enter to skip]: ")
+	templatePath, err := prompter.Prompt("Template (name in .nota/templates or file path) [optional, Enter to skip]: ")
 	if err != nil {
 		return err
 	}
@@ -149,16 +237,26 @@ func runTranscribeConfig(cmd *cobra.Command, prompter Prompter, advanced bool) e
 		archiveDir = transcribe.DefaultArchiveDir
 	}
 
-	// Build config
+	// Build config, storing paths inside the vault relative to it so the
+	// config survives syncing to machines with a different home layout
 	cfg := &transcribe.Config{
-		WatchDir:   watchDir,
+		WatchDir:   transcribe.VaultRelative(watchDir, vaultRoot),
 		APIURL:     apiURL,
-		OutputDir:  outputDir,
-		ArchiveDir: archiveDir,
+		OutputDir:  transcribe.VaultRelative(outputDir, vaultRoot),
+		ArchiveDir: transcribe.VaultRelative(archiveDir, vaultRoot),
+	}
+
+	// Keep the per-device profiles of an existing config
+	configPath := filepath.Join(vaultRoot, vault.VaultMarkerDir, transcribe.ConfigFileName)
+	if data, err := os.ReadFile(configPath); err == nil {
+		if existing, err := transcribe.ParseConfig(data); err == nil {
+			cfg.Profiles = existing.Profiles
+		}
 	}
 
 	// Set template path if provided
 	if templatePath != "" {
+		templatePath = transcribe.VaultRelative(templatePath, vaultRoot)
 		cfg.TemplatePath = &templatePath
 	}
 
@@ -253,7 +351,18 @@ func runTranscribeConfig(cmd *cobra.Command, prompter Prompter, advanced bool) e
 
 	// Validate
 	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid configuration: %w", err)
+		return fmt.Errorf("%w: %w", ErrConfigInvalid, err)
+	}
+
+	// Check directories now rather than failing at daemon start
+	if err := checkConfigDirs(out, prompter, cfg, vaultRoot); err != nil {
+		return err
+	}
+	if templatePath != "" {
+		path := transcribe.ResolveTemplate(templatePath, vaultRoot)
+		for _, problem := range lintTemplate(path) {
+			fmt.Fprintf(out, "Warning: template %s: %s\n", path, problem)
+		}
 	}
 
 	// Save to vault
@@ -263,12 +372,274 @@ func runTranscribeConfig(cmd *cobra.Command, prompter Prompter, advanced bool) e
 
 	// Show summary
 	fmt.Fprintln(out, "")
-	configPath := fmt.Sprintf("%s/.nota/transcribe.json", vaultRoot)
 	fmt.Fprintf(out, "Configuration saved to %s\n", configPath)
 
 	return nil
 }
 
+// printConfigWarnings prints problems found while loading the config, such
+// as misspelled fields, that would otherwise only reach the log.
+func printConfigWarnings(w io.Writer, cfg *transcribe.Config) {
+	for _, warning := range cfg.Warnings() {
+		fmt.Fprintf(w, "Warning: %s: %s\n", transcribe.ConfigFileName, warning)
+	}
+}
+
+// newTranscribeConfigEditCmd creates the transcribe config edit command
+func newTranscribeConfigEditCmd(prompter Prompter) *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Edit the configuration in $EDITOR",
+		Long: `Opens .nota/transcribe.json in $VISUAL or $EDITOR (vi if neither is set).
+
+The edits are made on a copy. When the editor exits the copy is validated and,
+if valid, saved with defaults filled in; an invalid file is never saved, and
+you are offered to reopen the editor. Comments are not preserved. A vault
+without a configuration starts from the example written by "config init".`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p := prompter
+			if p == nil {
+				p = NewStdinPrompter()
+			}
+			return runTranscribeConfigEdit(cmd, p)
+		},
+	}
+}
+
+func runTranscribeConfigEdit(cmd *cobra.Command, prompter Prompter) error {
+	vaultRoot, err := vault.FindVaultRoot()
+	if err != nil {
+		return fmt.Errorf("not in a vault: %w", err)
+	}
+	out := cmd.OutOrStdout()
+
+	configPath := filepath.Join(vaultRoot, vault.VaultMarkerDir, transcribe.ConfigFileName)
+	content, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		content = []byte(transcribe.SampleConfig())
+	} else if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "transcribe-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(content)
+	tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := runEditor(cmd, tmp.Name()); err != nil {
+			return err
+		}
+
+		edited, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			return err
+		}
+		cfg, err := parseEditedConfig(edited)
+		if err == nil {
+			if err := cfg.SaveToVault(vaultRoot); err != nil {
+				return fmt.Errorf("failed to save configuration: %w", err)
+			}
+			fmt.Fprintf(out, "Configuration saved to %s\n", configPath)
+			return nil
+		}
+
+		fmt.Fprintf(out, "Invalid configuration: %v\n", err)
+		answer, promptErr := prompter.Prompt("Reopen the editor? [Y/n]: ")
+		if promptErr != nil || (answer != "" && !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes")) {
+			return fmt.Errorf("configuration not saved: %w: %w", ErrConfigInvalid, err)
+		}
+	}
+}
+
+// parseEditedConfig parses and validates an edited config with defaults
+// applied. Unknown fields are errors here, since saving would drop them.
+func parseEditedConfig(data []byte) (*transcribe.Config, error) {
+	cfg, err := transcribe.ParseConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	if warnings := cfg.Warnings(); len(warnings) > 0 {
+		return nil, errors.New(strings.Join(warnings, "; "))
+	}
+	cfg.ApplyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// runEditor opens path in $VISUAL, $EDITOR or vi and waits for it to exit.
+// The variable may include arguments, such as "code --wait".
+func runEditor(cmd *cobra.Command, path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	fields := strings.Fields(editor)
+	c := exec.Command(fields[0], append(fields[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = cmd.OutOrStdout()
+	c.Stderr = cmd.ErrOrStderr()
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("run editor %q: %w", editor, err)
+	}
+	return nil
+}
+
+// checkConfigDirs checks that the configured directories exist and are
+// writable, offering to create any that are missing.
+func checkConfigDirs(out io.Writer, prompter Prompter, cfg *transcribe.Config, vaultRoot string) error {
+	dirs := []struct {
+		label string
+		path  string
+	}{
+		{"Watch folder", cfg.WatchDir},
+		{"Output location", cfg.OutputDir},
+		{"Audio archive location", cfg.ArchiveDir},
+	}
+
+	for _, d := range dirs {
+		path := transcribe.ResolvePath(d.path, vaultRoot)
+		info, err := os.Stat(path)
+		switch {
+		case os.IsNotExist(err):
+			answer, err := prompter.Prompt(fmt.Sprintf("%s %s does not exist. Create it? [Y/n]: ", d.label, path))
+			if err != nil {
+				return err
+			}
+			if answer != "" && !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+				fmt.Fprintf(out, "Warning: %s %s does not exist yet\n", strings.ToLower(d.label), path)
+				continue
+			}
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return fmt.Errorf("create %s: %w", path, err)
+			}
+			fmt.Fprintf(out, "Created %s\n", path)
+		case err != nil:
+			return fmt.Errorf("check %s: %w", path, err)
+		case !info.IsDir():
+			return fmt.Errorf("%s %s is not a directory", strings.ToLower(d.label), path)
+		default:
+			if err := checkWritable(path); err != nil {
+				return fmt.Errorf("%s %s is not writable: %w", strings.ToLower(d.label), path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// lintTemplate returns the problems with the template at path, including
+// it being missing.
+func lintTemplate(path string) []string {
+	problems, err := vault.LintTemplate(path)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	return problems
+}
+
+// newTranscribeDoctorCmd creates the transcribe doctor command
+func newTranscribeDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the transcription config for problems",
+		Long: `Checks the transcription config without starting the service: that it loads
+and validates, that the watch directories exist and they and the output and
+archive directories are writable, and that its templates exist, have valid
+YAML frontmatter and use no placeholders, which nota would copy into notes as
+written.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := transcribe.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			out := cmd.OutOrStdout()
+			problems := 0
+			report := func(subject string, issues ...string) {
+				if len(issues) == 0 {
+					fmt.Fprintf(out, "ok       %s\n", subject)
+					return
+				}
+				for _, issue := range issues {
+					fmt.Fprintf(out, "problem  %s: %s\n", subject, issue)
+				}
+				problems += len(issues)
+			}
+
+			report("config", cfg.Warnings()...)
+			checkDirs := func(created bool, dirs ...string) {
+				for _, dir := range dirs {
+					if err := checkDir(dir, created); err != nil {
+						report("directory "+dir, err.Error())
+					} else if dir != "" {
+						report("directory " + dir)
+					}
+				}
+			}
+			for _, w := range cfg.Watches() {
+				checkDirs(false, w.Path)
+			}
+			checkDirs(true, cfg.OutputDir, cfg.ArchiveDir)
+			for _, path := range cfg.Templates() {
+				report("template "+path, lintTemplate(path)...)
+			}
+
+			switch {
+			case problems == 1:
+				return errors.New("found 1 problem")
+			case problems > 1:
+				return fmt.Errorf("found %d problems", problems)
+			}
+			return nil
+		},
+	}
+}
+
+// checkDir checks that dir is a writable directory. A missing directory is
+// fine when created is set, as the service creates it when first needed.
+func checkDir(dir string, created bool) error {
+	if dir == "" {
+		return nil
+	}
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) && created {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("not a directory")
+	}
+	if err := checkWritable(dir); err != nil {
+		return fmt.Errorf("not writable: %w", err)
+	}
+	return nil
+}
+
+// checkWritable creates and removes a temporary file in dir.
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".nota-write-check-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
 // promptRequired prompts for a required field, returning an error if empty
 func promptRequired(prompter Prompter, prompt string) (string, error) {
 	value, err := prompter.Prompt(prompt)
@@ -293,62 +664,123 @@ a whisper-asr-webservice instance. Configuration is read from .nota/transcribe.j
 in the current vault.
 
 Use --daemon to run in the background. The service runs until stopped with
-'nota transcribe stop' or interrupted with Ctrl+C/SIGTERM.`,
-		RunE: func(cmd *cobra.Command, args []string) error {
+'nota transcribe stop' or interrupted with Ctrl+C/SIGTERM.
+
+Use --supervise to run the daemon under a supervisor process that restarts it
+with backoff if it crashes, for systems without systemd.
+
+Use --dry-run to validate a configuration: files are detected and stabilized,
+and the upload, output filename and archive destination are reported, but no
+file is uploaded, written or moved.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			daemon, _ := cmd.Flags().GetBool("daemon")
 			daemonChild, _ := cmd.Flags().GetBool("daemon-child")
+			supervise, _ := cmd.Flags().GetBool("supervise")
+			supervisorChild, _ := cmd.Flags().GetBool("supervisor-child")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
 
-			if daemon {
-				return runDaemon(cmd)
+			if dryRun && (daemon || supervise) {
+				return fmt.Errorf("--dry-run runs in the foreground and cannot be combined with --daemon or --supervise")
 			}
 
-			if daemonChild {
-				// Running as daemon child - write PID file
-				if err := pidfile.Write(os.Getpid()); err != nil {
-					return fmt.Errorf("write PID file: %w", err)
-				}
+			if daemon || supervise {
+				return runDaemon(cmd, supervise)
+			}
+
+			if supervisorChild {
+				return runSupervisor()
+			}
+
+			// Claim the PID file so only one service instance can run
+			cleanStalePID(cmd.ErrOrStderr())
+			lock, err := pidfile.Acquire(os.Getpid())
+			if err != nil {
+				return err
 			}
+			defer lock.Release()
+
+			supervisorPID, _ := strconv.Atoi(os.Getenv(envSupervisorPID))
+			if _, err := pidfile.RecordStart(os.Getpid(), supervisorPID, time.Now()); err != nil {
+				return fmt.Errorf("record start: %w", err)
+			}
+			var svc *transcribe.Service
+			defer func() {
+				pidfile.RecordStop(time.Now(), stopReason(svc, err))
+			}()
 
 			// Load configuration from vault
 			cfg, err := transcribe.Load()
 			if err != nil {
 				return fmt.Errorf("load config: %w", err)
 			}
+			if !daemonChild {
+				printConfigWarnings(cmd.ErrOrStderr(), cfg)
+			}
 
 			// Create and run service
-			svc, err := transcribe.NewService(cfg)
+			svc, err = transcribe.NewService(cfg)
 			if err != nil {
 				return fmt.Errorf("create service: %w", err)
 			}
 
+			svc.SetDryRun(dryRun)
+
 			if !daemonChild {
+				svc.SetProgressReporter(NewConsoleProgress(cmd.OutOrStdout()))
+				if dryRun {
+					fmt.Fprintln(cmd.OutOrStdout(), "Dry run: files will not be uploaded, written or archived")
+				}
 				fmt.Fprintln(cmd.OutOrStdout(), "Starting transcription service...")
-				fmt.Fprintf(cmd.OutOrStdout(), "Watching: %s\n", cfg.WatchDir)
+				if cfg.Profile() != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "Profile:  %s\n", cfg.Profile())
+				}
+				for _, wd := range cfg.Watches() {
+					fmt.Fprintf(cmd.OutOrStdout(), "Watching: %s (%s)\n", wd.Path, strings.Join(wd.Patterns, ", "))
+				}
+				if cfg.Source != nil {
+					fmt.Fprintf(cmd.OutOrStdout(), "Polling:  %s (%s)\n", cfg.Source, strings.Join(cfg.WatchPatterns, ", "))
+				}
 				fmt.Fprintf(cmd.OutOrStdout(), "Output:   %s\n", cfg.OutputDir)
+				fmt.Fprintf(cmd.OutOrStdout(), "Archive:  %s\n", cfg.ArchiveDir)
+				fmt.Fprintf(cmd.OutOrStdout(), "API:      %s (model %s, language %s)\n", cfg.Effective().APIURL, cfg.Model, cfg.Language)
+				if err := printEffectiveConfig(cmd.OutOrStdout(), cfg); err != nil {
+					return err
+				}
 				fmt.Fprintln(cmd.OutOrStdout(), "Press Ctrl+C to stop")
 				fmt.Fprintln(cmd.OutOrStdout())
 			}
 
-			err = svc.Run(context.Background())
-
-			// Clean up PID file if we were a daemon child
-			if daemonChild {
-				pidfile.Remove()
-			}
-
-			return err
+			return svc.Run(context.Background())
 		},
 	}
 
 	cmd.Flags().Bool("daemon", false, "Run in background as daemon")
+	cmd.Flags().Bool("dry-run", false, "Detect and stabilize files, but only log what would be uploaded, written and archived")
+	cmd.Flags().Bool("supervise", false, "Run as daemon under a supervisor that restarts it on crash")
 	cmd.Flags().Bool("daemon-child", false, "Internal flag for daemon child process")
 	cmd.Flags().MarkHidden("daemon-child")
+	cmd.Flags().Bool("supervisor-child", false, "Internal flag for supervisor process")
+	cmd.Flags().MarkHidden("supervisor-child")
 
 	return cmd
 }
 
-// runDaemon spawns a daemon child process
-func runDaemon(cmd *cobra.Command) error {
+// printEffectiveConfig prints the configuration the service runs with, after
+// defaults, the profile and path expansion, with secrets redacted.
+func printEffectiveConfig(out io.Writer, cfg *transcribe.Config) error {
+	data, err := cfg.EffectiveJSON()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Effective config:\n%s\n", data)
+	return nil
+}
+
+// envSupervisorPID passes the supervisor's PID to the daemon child it starts
+const envSupervisorPID = "NOTA_SUPERVISOR_PID"
+
+// runDaemon spawns a daemon child process, or a supervisor that spawns it
+func runDaemon(cmd *cobra.Command, supervise bool) error {
 	// Check if already running
 	running, pid, err := pidfile.IsRunning()
 	if err != nil {
@@ -359,7 +791,7 @@ func runDaemon(cmd *cobra.Command) error {
 	}
 
 	// Clean up stale PID file if any
-	pidfile.CleanStale()
+	cleanStalePID(cmd.ErrOrStderr())
 
 	// Find vault root for the child process
 	vaultRoot, err := vault.FindVaultRoot()
@@ -394,7 +826,11 @@ func runDaemon(cmd *cobra.Command) error {
 	}
 
 	// Spawn child process
-	childCmd := exec.Command(exe, "transcribe", "start", "--daemon-child")
+	childFlag := "--daemon-child"
+	if supervise {
+		childFlag = "--supervisor-child"
+	}
+	childCmd := exec.Command(exe, "transcribe", "start", childFlag)
 	childCmd.Env = append(os.Environ(), vault.EnvVaultRoot+"="+vaultRoot)
 	childCmd.Stdout = logFile
 	childCmd.Stderr = logFile
@@ -422,19 +858,132 @@ func runDaemon(cmd *cobra.Command) error {
 	devNull.Close()
 	logFile.Close()
 
-	// Write PID file for the child
-	if err := pidfile.Write(childPID); err != nil {
-		// Try to kill the child if we can't write PID file
-		childCmd.Process.Kill()
-		return fmt.Errorf("write PID file: %w", err)
+	// The daemon claims the PID file itself; wait until it has done so, or
+	// until it exits because another instance won the race.
+	claimed := func() bool {
+		pid, err := pidfile.Read()
+		return err == nil && pid == childPID
+	}
+	if supervise {
+		// The supervisor's own child claims the PID file and records the supervisor in the state file
+		claimed = func() bool {
+			state, err := pidfile.ReadState()
+			if err != nil || state.SupervisorPID != childPID {
+				return false
+			}
+			pid, err := pidfile.Read()
+			return err == nil && pid == state.PID
+		}
+	}
+	if err := waitForDaemonLock(childCmd, claimed, daemonStartTimeout); err != nil {
+		return fmt.Errorf("%w (see %s)", err, logPath)
 	}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "Transcription service started (PID %d)\n", childPID)
+	if supervise {
+		pid, _ := pidfile.Read()
+		fmt.Fprintf(cmd.OutOrStdout(), "Transcription service started (PID %d, supervisor PID %d)\n", pid, childPID)
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "Transcription service started (PID %d)\n", childPID)
+	}
 	fmt.Fprintf(cmd.OutOrStdout(), "Logs: %s\n", logPath)
 
 	return nil
 }
 
+// cleanStalePID removes the PID file left behind by a service that crashed
+// or was killed, with a notice on w.
+func cleanStalePID(w io.Writer) {
+	pid, err := pidfile.CleanStale()
+	if err != nil {
+		fmt.Fprintf(w, "Warning: could not remove stale PID file: %v\n", err)
+		return
+	}
+	if pid > 0 {
+		fmt.Fprintf(w, "Removed stale PID file (PID %d is not running)\n", pid)
+	}
+}
+
+// stopReason returns why the service stopped, for the state file: the
+// signal svc received, the error the start command returned, or otherwise
+// that it drained. svc is nil when the service was never created.
+func stopReason(svc *transcribe.Service, err error) pidfile.Stop {
+	if err != nil {
+		return pidfile.Stop{Reason: pidfile.StopError, Error: err.Error()}
+	}
+	if svc != nil {
+		if sig, ok := svc.StopSignal().(syscall.Signal); ok {
+			return pidfile.Stop{Reason: pidfile.StopSignal, Signal: unix.SignalName(sig)}
+		}
+	}
+	return pidfile.Stop{Reason: pidfile.StopDrained}
+}
+
+// daemonStartTimeout bounds how long start --daemon waits for the child to claim the PID file
+const daemonStartTimeout = 5 * time.Second
+
+// waitForDaemonLock waits until claimed reports that the daemon owns the PID file.
+// Returns an error if the child exits first or the timeout expires.
+func waitForDaemonLock(childCmd *exec.Cmd, claimed func() bool, timeout time.Duration) error {
+	exited := make(chan error, 1)
+	go func() {
+		exited <- childCmd.Wait()
+	}()
+
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-exited:
+			if err != nil {
+				return fmt.Errorf("daemon exited during startup: %w", err)
+			}
+			return fmt.Errorf("daemon exited during startup")
+		case <-deadline:
+			return fmt.Errorf("timed out waiting for daemon to start")
+		case <-ticker.C:
+			if claimed() {
+				return nil
+			}
+		}
+	}
+}
+
+// runSupervisor runs the daemon child under a supervisor until stopped.
+// Its stdout and stderr are already redirected to the log file by runDaemon.
+func runSupervisor() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("get executable: %w", err)
+	}
+
+	logConfig := logging.DefaultConfig()
+	logConfig.Component = "supervisor"
+	logger, err := logging.New(logConfig)
+	if err != nil {
+		return fmt.Errorf("create logger: %w", err)
+	}
+	defer logger.Close()
+
+	sup := supervisor.New(func() *exec.Cmd {
+		child := exec.Command(exe, "transcribe", "start", "--daemon-child")
+		child.Env = append(os.Environ(), fmt.Sprintf("%s=%d", envSupervisorPID, os.Getpid()))
+		child.Stdout = os.Stdout
+		child.Stderr = os.Stderr
+		return child
+	}, logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := sup.Run(ctx); err != nil {
+		logger.Error("supervisor exiting", err)
+		return err
+	}
+	return nil
+}
+
 // newTranscribeStopCmd creates the transcribe stop command
 func newTranscribeStopCmd() *cobra.Command {
 	return &cobra.Command{
@@ -452,17 +1001,21 @@ func newTranscribeStopCmd() *cobra.Command {
 
 			if !running {
 				if pid > 0 {
-					// Stale PID file
-					pidfile.Remove()
-					fmt.Fprintln(out, "Transcription service is not running (cleaned stale PID file)")
-				} else {
-					fmt.Fprintln(out, "Transcription service is not running")
+					cleanStalePID(cmd.ErrOrStderr())
 				}
-				return nil
+				return ErrDaemonNotRunning
 			}
 
 			fmt.Fprintf(out, "Stopping transcription service (PID %d)...\n", pid)
 
+			// Stop the supervisor first so it doesn't restart the service; it
+			// forwards SIGTERM to the daemon itself
+			if state, err := pidfile.ReadState(); err == nil && state.PID == pid && state.SupervisorPID > 0 {
+				if supervisorProc, err := os.FindProcess(state.SupervisorPID); err == nil {
+					supervisorProc.Signal(syscall.SIGTERM)
+				}
+			}
+
 			// Send SIGTERM
 			process, err := os.FindProcess(pid)
 			if err != nil {
@@ -507,49 +1060,1145 @@ func newTranscribeStopCmd() *cobra.Command {
 
 // newTranscribeStatusCmd creates the transcribe status command
 func newTranscribeStatusCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show transcription service status",
-		Long:  "Shows the current status of the transcription service daemon.",
+		Long: `Shows the current status of the transcription service daemon.
+
+Totals are read from the processing history, so they are not limited to
+today's log. Use --since to summarize a recent window, e.g. --since 7d, and
+--verbose to break failures down by category.
+
+Use --watch for a live dashboard of the running service, read from its control
+socket: files waiting and in flight with their stage, the latest completions
+and failure counts since it started. It refreshes every --interval until
+interrupted with Ctrl+C.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := cmd.OutOrStdout()
 
+			if watch, _ := cmd.Flags().GetBool("watch"); watch {
+				interval, _ := cmd.Flags().GetDuration("interval")
+				if interval <= 0 {
+					return fmt.Errorf("invalid --interval: must be positive")
+				}
+				socket, err := control.SocketPath()
+				if err != nil {
+					return err
+				}
+				ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+				defer stop()
+				return watchStatus(ctx, out, socket, interval)
+			}
+
+			var window time.Duration
+			since, _ := cmd.Flags().GetString("since")
+			if since != "" {
+				var err error
+				window, err = history.ParseSince(since)
+				if err != nil {
+					return fmt.Errorf("invalid --since: %w", err)
+				}
+			}
+
 			// Check if running
 			running, pid, err := pidfile.IsRunning()
 			if err != nil {
 				return fmt.Errorf("check running status: %w", err)
 			}
 
-			if !running {
-				fmt.Fprintln(out, "Status: not running")
-				return nil
+			if !running && pid > 0 {
+				cleanStalePID(cmd.ErrOrStderr())
 			}
 
-			fmt.Fprintf(out, "Status: running (pid %d)\n", pid)
-
-			// Try to load config to show watch directory
-			cfg, err := transcribe.Load()
-			if err == nil {
-				fmt.Fprintf(out, "Watching: %s\n", cfg.WatchDir)
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			report := statusReport{Running: running, verbose: verbose}
+			if running {
+				report.collectRunning(pid)
+			} else {
+				report.collectLastRun()
 			}
+			report.History = collectHistory(since, window)
+			report.Unfinished = collectUnfinished()
 
-			// Parse today's stats
-			stats, err := status.ParseTodayStats()
-			if err != nil {
-				// Don't fail if we can't parse stats
-				return nil
+			if JSONOutput(cmd) {
+				return writeJSON(out, report)
 			}
+			report.print(out)
+			return nil
+		},
+	}
 
-			if stats.LastProcessed != nil {
-				fmt.Fprintf(out, "Last processed: %s (%s)\n",
-					status.FormatTimestamp(stats.LastProcessed.Timestamp),
-					status.BaseName(stats.LastProcessed.Path))
-			}
+	cmd.Flags().String("since", "", "Summarize processing history over a window (e.g. 24h, 7d)")
+	cmd.Flags().Bool("verbose", false, "Break failures down by category")
+	cmd.Flags().Bool("watch", false, "Show a live dashboard of the running service, refreshed until interrupted")
+	cmd.Flags().Duration("interval", DefaultWatchInterval, "Refresh interval for --watch")
+
+	return cmd
+}
 
-			fmt.Fprintf(out, "Files processed today: %d\n", stats.FilesProcessed)
-			fmt.Fprintf(out, "Errors today: %d\n", stats.Errors)
+// statusReport is what transcribe status shows; its JSON form is the
+// --json output.
+type statusReport struct {
+	Running bool `json:"running"`
+	PID     int  `json:"pid,omitempty"`
+	// Service is read from the state file written by the running service.
+	Service *serviceReport `json:"service,omitempty"`
+	// LastRun is read from the state file when the service is not running.
+	LastRun   *lastRunReport   `json:"last_run,omitempty"`
+	WatchDirs []watchDirReport `json:"watch_dirs,omitempty"`
+	// Today is parsed from today's log.
+	Today   *todayReport  `json:"today,omitempty"`
+	History historyReport `json:"history"`
+	// Unfinished lists files with saved pipeline progress: those in flight,
+	// and failed ones a retry or restart resumes.
+	Unfinished []unfinishedReport `json:"unfinished,omitempty"`
+
+	// verbose adds the failure categories to the text output.
+	verbose bool
+}
 
-			return nil
-		},
+type serviceReport struct {
+	StartedAt     time.Time `json:"started_at"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+	Restarts      int       `json:"restarts"`
+	SupervisorPID int       `json:"supervisor_pid,omitempty"`
+	// LastError is how the latest run that did not stop cleanly ended.
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// stopCrashed is the reason reported for a run that ended without
+// recording why.
+const stopCrashed = "crashed"
+
+// lastRunReport is how the last run of the service ended.
+type lastRunReport struct {
+	PID       int        `json:"pid"`
+	StartedAt time.Time  `json:"started_at"`
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+	// Reason is signal, error or drained as recorded by the run, or crashed
+	// when it recorded none.
+	Reason string `json:"reason"`
+	Signal string `json:"signal,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type watchDirReport struct {
+	Path           string   `json:"path"`
+	Patterns       []string `json:"patterns"`
+	ProcessedToday int      `json:"processed_today"`
+	FailedToday    int      `json:"failed_today"`
+}
+
+type todayReport struct {
+	LastProcessed  *lastProcessedReport `json:"last_processed,omitempty"`
+	FilesProcessed int                  `json:"files_processed"`
+	Errors         int                  `json:"errors"`
+	// DiskSpaceLow is why processing is paused for disk space, if it is.
+	DiskSpaceLow string `json:"disk_space_low,omitempty"`
+	// BudgetExceeded is why processing is paused for the monthly budget, if
+	// it is.
+	BudgetExceeded string `json:"budget_exceeded,omitempty"`
+}
+
+type unfinishedReport struct {
+	Path    string    `json:"path"`
+	Stage   string    `json:"stage"`
+	Updated time.Time `json:"updated"`
+}
+
+type lastProcessedReport struct {
+	Time   time.Time `json:"time"`
+	Path   string    `json:"path"`
+	Output string    `json:"output"`
+}
+
+type historyReport struct {
+	Since   string         `json:"since,omitempty"`
+	Window  *summaryReport `json:"window,omitempty"`
+	AllTime *summaryReport `json:"all_time,omitempty"`
+}
+
+type summaryReport struct {
+	Processed             int            `json:"processed"`
+	Failed                int            `json:"failed"`
+	Skipped               int            `json:"skipped"`
+	FailureRate           float64        `json:"failure_rate"`
+	AverageElapsedSeconds float64        `json:"average_elapsed_seconds"`
+	Failures              map[string]int `json:"failures,omitempty"`
+
+	summary history.Summary
+}
+
+func newSummaryReport(sum history.Summary) *summaryReport {
+	return &summaryReport{
+		Processed:             sum.Processed,
+		Failed:                sum.Failed,
+		Skipped:               sum.Skipped,
+		FailureRate:           sum.FailureRate(),
+		AverageElapsedSeconds: sum.AverageElapsed.Seconds(),
+		Failures:              sum.Failures,
+		summary:               sum,
+	}
+}
+
+// collectRunning fills in details of the running service and today's log activity
+func (r *statusReport) collectRunning(pid int) {
+	r.PID = pid
+
+	// Report uptime from the state file written by the running service
+	if state, err := pidfile.ReadState(); err == nil && state.PID == pid {
+		r.Service = &serviceReport{
+			StartedAt:     state.StartedAt,
+			UptimeSeconds: int64(state.Uptime(time.Now()).Round(time.Second).Seconds()),
+			Restarts:      state.Restarts,
+			SupervisorPID: state.SupervisorPID,
+			LastError:     state.LastError,
+			LastErrorAt:   state.LastErrorAt,
+		}
+	}
+
+	// Try to load config to show watch directories
+	cfg, err := transcribe.Load()
+	if err == nil {
+		cfg.ApplyDefaults()
+		r.WatchDirs = collectWatchDirs(cfg.Watches())
+	}
+
+	// Parse today's stats; don't fail if we can't
+	stats, err := status.ParseTodayStats()
+	if err != nil {
+		return
+	}
+	r.Today = &todayReport{
+		FilesProcessed: stats.FilesProcessed,
+		Errors:         stats.Errors,
+		DiskSpaceLow:   stats.DiskSpaceLow,
+		BudgetExceeded: stats.BudgetExceeded,
+	}
+	if stats.LastProcessed != nil {
+		r.Today.LastProcessed = &lastProcessedReport{
+			Time:   stats.LastProcessed.Timestamp,
+			Path:   stats.LastProcessed.Path,
+			Output: stats.LastProcessed.Output,
+		}
+	}
+}
+
+// print writes how the last run ended as text, e.g. "Last stopped: SIGTERM
+// at ..." or "Crashed: ...".
+func (l *lastRunReport) print(out io.Writer) {
+	switch {
+	case l.Reason == stopCrashed:
+		fmt.Fprintf(out, "Crashed: pid %d exited without recording a stop (started %s)\n",
+			l.PID, status.FormatTimestamp(l.StartedAt))
+	case l.Reason == pidfile.StopError:
+		fmt.Fprintf(out, "Crashed: %s at %s\n", l.Error, status.FormatTimestamp(*l.StoppedAt))
+	default:
+		stop := pidfile.Stop{Reason: l.Reason, Signal: l.Signal}
+		fmt.Fprintf(out, "Last stopped: %s at %s\n", stop, status.FormatTimestamp(*l.StoppedAt))
+	}
+}
+
+// collectLastRun fills in how the last run of the service ended, from the
+// state file it left behind.
+func (r *statusReport) collectLastRun() {
+	state, err := pidfile.ReadState()
+	if err != nil {
+		return
+	}
+	r.LastRun = &lastRunReport{
+		PID:       state.PID,
+		StartedAt: state.StartedAt,
+		StoppedAt: state.StoppedAt,
+		Reason:    stopCrashed,
+	}
+	if state.StoppedAt != nil && state.Stop != nil {
+		r.LastRun.Reason = state.Stop.Reason
+		r.LastRun.Signal = state.Stop.Signal
+		r.LastRun.Error = state.Stop.Error
+	} else if state.StoppedAt != nil {
+		// Stopped by a version that did not record why
+		r.LastRun.Reason = pidfile.StopDrained
+	}
+}
+
+// collectWatchDirs returns each watched directory with today's totals for files from it
+func collectWatchDirs(watches []transcribe.WatchDirConfig) []watchDirReport {
+	var today []history.Record
+	if store, err := history.Open(); err == nil {
+		now := time.Now()
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		today, _ = store.Load(midnight)
+	}
+
+	records := make([][]history.Record, len(watches))
+	for _, rec := range today {
+		if i := transcribe.WatchFor(watches, rec.Source); i >= 0 {
+			records[i] = append(records[i], rec)
+		}
+	}
+
+	reports := make([]watchDirReport, 0, len(watches))
+	for i, wd := range watches {
+		sum := history.Summarize(records[i])
+		reports = append(reports, watchDirReport{
+			Path:           wd.Path,
+			Patterns:       wd.Patterns,
+			ProcessedToday: sum.Processed,
+			FailedToday:    sum.Failed,
+		})
+	}
+	return reports
+}
+
+// collectUnfinished returns the files whose pipeline progress is saved,
+// leaving out those that no longer exist.
+func collectUnfinished() []unfinishedReport {
+	store, err := checkpoint.Open()
+	if err != nil {
+		return nil
+	}
+	states, err := store.List()
+	if err != nil {
+		return nil
+	}
+	var reports []unfinishedReport
+	for _, st := range states {
+		if _, err := os.Stat(st.Source); err != nil {
+			continue
+		}
+		reports = append(reports, unfinishedReport{Path: st.Source, Stage: string(st.Stage), Updated: st.Updated})
+	}
+	return reports
+}
+
+// collectHistory returns totals from the processing history store: the
+// --since window if one was given, and all-time totals if any history exists.
+func collectHistory(since string, window time.Duration) historyReport {
+	report := historyReport{Since: since}
+
+	store, err := history.Open()
+	if err != nil {
+		return report
+	}
+
+	all, err := store.Load(time.Time{})
+	if err != nil {
+		return report
+	}
+
+	if window > 0 {
+		cutoff := time.Now().Add(-window)
+		var recent []history.Record
+		for _, rec := range all {
+			if !rec.Time.Before(cutoff) {
+				recent = append(recent, rec)
+			}
+		}
+		report.Window = newSummaryReport(history.Summarize(recent))
+	}
+
+	if len(all) > 0 {
+		report.AllTime = newSummaryReport(history.Summarize(all))
+	}
+	return report
+}
+
+// print writes the report as text
+func (r *statusReport) print(out io.Writer) {
+	if !r.Running {
+		fmt.Fprintln(out, "Status: not running")
+	} else {
+		fmt.Fprintf(out, "Status: running (pid %d)\n", r.PID)
+	}
+
+	if s := r.Service; s != nil {
+		fmt.Fprintf(out, "Started: %s (uptime %s)\n",
+			status.FormatTimestamp(s.StartedAt),
+			time.Duration(s.UptimeSeconds)*time.Second)
+		fmt.Fprintf(out, "Restarts: %d\n", s.Restarts)
+		if s.SupervisorPID > 0 {
+			fmt.Fprintf(out, "Supervisor: pid %d\n", s.SupervisorPID)
+		}
+		if s.LastError != "" {
+			if s.LastErrorAt != nil {
+				fmt.Fprintf(out, "Last error: %s (%s)\n", s.LastError, status.FormatTimestamp(*s.LastErrorAt))
+			} else {
+				fmt.Fprintf(out, "Last error: %s\n", s.LastError)
+			}
+		}
+	}
+	if l := r.LastRun; l != nil {
+		l.print(out)
+	}
+
+	if r.WatchDirs != nil {
+		printWatchDirs(out, r.WatchDirs)
+	}
+
+	if t := r.Today; t != nil {
+		if t.LastProcessed != nil {
+			fmt.Fprintf(out, "Last processed: %s (%s)\n",
+				status.FormatTimestamp(t.LastProcessed.Time),
+				status.BaseName(t.LastProcessed.Path))
+		}
+		fmt.Fprintf(out, "Files processed today: %d\n", t.FilesProcessed)
+		fmt.Fprintf(out, "Errors today: %d\n", t.Errors)
+		if t.DiskSpaceLow != "" {
+			fmt.Fprintf(out, "Warning: processing paused: %s\n", t.DiskSpaceLow)
+		}
+		if t.BudgetExceeded != "" {
+			fmt.Fprintf(out, "Warning: processing paused: %s\n", t.BudgetExceeded)
+		}
+	}
+
+	if len(r.Unfinished) > 0 {
+		fmt.Fprintln(out, "Unfinished:")
+		for _, u := range r.Unfinished {
+			fmt.Fprintf(out, "  %s: %s (%s)\n", status.BaseName(u.Path), u.Stage, status.FormatTimestamp(u.Updated))
+		}
+	}
+
+	if r.History.Window != nil {
+		fmt.Fprintf(out, "Last %s: %s\n", r.History.Since, formatSummary(r.History.Window.summary))
+		if r.verbose {
+			printFailures(out, r.History.Window.Failures)
+		}
+	}
+	if r.History.AllTime != nil {
+		fmt.Fprintf(out, "All time: %s\n", formatSummary(r.History.AllTime.summary))
+		if r.verbose {
+			printFailures(out, r.History.AllTime.Failures)
+		}
+	}
+}
+
+// printFailures prints failure counts by category, most frequent first.
+func printFailures(out io.Writer, failures map[string]int) {
+	categories := make([]string, 0, len(failures))
+	for category := range failures {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		a, b := categories[i], categories[j]
+		if failures[a] != failures[b] {
+			return failures[a] > failures[b]
+		}
+		return a < b
+	})
+	for _, category := range categories {
+		fmt.Fprintf(out, "  %-22s %d\n", category, failures[category])
+	}
+}
+
+// printWatchDirs prints each watched directory with today's totals for files from it
+func printWatchDirs(out io.Writer, watchDirs []watchDirReport) {
+	fmt.Fprintln(out, "Watching:")
+	for _, wd := range watchDirs {
+		fmt.Fprintf(out, "  %s (%s): %d processed, %d failed today\n",
+			wd.Path, strings.Join(wd.Patterns, ", "), wd.ProcessedToday, wd.FailedToday)
+	}
+}
+
+// formatSummary renders a history summary as a single line
+func formatSummary(sum history.Summary) string {
+	line := fmt.Sprintf("%d processed, %d failed", sum.Processed, sum.Failed)
+	if sum.Total() > 0 {
+		line += fmt.Sprintf(" (%.1f%% failure rate)", sum.FailureRate()*100)
+	}
+	if sum.Skipped > 0 {
+		line += fmt.Sprintf(", %d skipped", sum.Skipped)
+	}
+	if sum.Processed > 0 {
+		line += fmt.Sprintf(", avg processing time %s", sum.AverageElapsed.Round(100*time.Millisecond))
+	}
+	return line
+}
+
+// newTranscribeStatsCmd creates the transcribe stats command
+func newTranscribeStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize transcription usage for a month",
+		Long: `Summarizes a month of transcription from the local processing history:
+files and minutes of audio transcribed, words produced, average latency, speed
+relative to realtime and failures by category. Nothing leaves this machine.
+
+Minutes are broken down by transcription provider. With a "usage" block in
+transcribe.json the estimated cost of each is shown too, along with how much of
+the monthly budget is spent.
+
+The month defaults to the current one; use --month 2024-03 for another.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			month, _ := cmd.Flags().GetString("month")
+			start, err := parseMonth(month, time.Now())
+			if err != nil {
+				return err
+			}
+
+			store, err := history.Open()
+			if err != nil {
+				return fmt.Errorf("open history: %w", err)
+			}
+			// Costs are labelled with the vault's usage settings, if any
+			var usage *transcribe.UsageConfig
+			if cfg, err := transcribe.Load(); err == nil {
+				usage = cfg.Usage
+			}
+			report, err := collectStats(store, start, usage)
+			if err != nil {
+				return fmt.Errorf("read history: %w", err)
+			}
+
+			if JSONOutput(cmd) {
+				return writeJSON(cmd.OutOrStdout(), report)
+			}
+			report.print(cmd.OutOrStdout())
+			return nil
+		},
+	}
+
+	cmd.Flags().String("month", "", "Month to summarize as YYYY-MM (default: the current month)")
+
+	return cmd
+}
+
+// parseMonth returns the local start of the month given as YYYY-MM, or of
+// now's month when month is empty.
+func parseMonth(month string, now time.Time) (time.Time, error) {
+	if month == "" {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local), nil
+	}
+	start, err := time.ParseInLocation("2006-01", month, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --month %q: expected YYYY-MM, e.g. 2024-03", month)
+	}
+	return start, nil
+}
+
+// statsReport is what transcribe stats shows; its JSON form is the --json
+// output.
+type statsReport struct {
+	Month                 string         `json:"month"`
+	Processed             int            `json:"processed"`
+	Failed                int            `json:"failed"`
+	FailureRate           float64        `json:"failure_rate"`
+	AudioSeconds          float64        `json:"audio_seconds"`
+	Words                 int            `json:"words"`
+	AverageElapsedSeconds float64        `json:"average_elapsed_seconds"`
+	Speed                 float64        `json:"speed"`
+	Failures              map[string]int `json:"failures"`
+	// Cost is the estimated cost of the month's transcriptions; Budget is
+	// the configured monthly budget, if any.
+	Cost      float64                   `json:"cost"`
+	Budget    float64                   `json:"budget,omitempty"`
+	Currency  string                    `json:"currency,omitempty"`
+	Providers map[string]providerReport `json:"providers"`
+
+	summary history.Summary
+	usage   transcribe.UsageConfig
+}
+
+// providerReport is a month's transcription with one provider.
+type providerReport struct {
+	Files        int     `json:"files"`
+	AudioSeconds float64 `json:"audio_seconds"`
+	Cost         float64 `json:"cost"`
+}
+
+// collectStats summarizes the history records in the month starting at
+// start. usage, if set, labels costs and supplies the budget.
+func collectStats(store *history.Store, start time.Time, usage *transcribe.UsageConfig) (*statsReport, error) {
+	end := start.AddDate(0, 1, 0)
+	records, err := store.Load(start)
+	if err != nil {
+		return nil, err
+	}
+
+	var month []history.Record
+	for _, rec := range records {
+		if rec.Time.Before(end) {
+			month = append(month, rec)
+		}
+	}
+
+	sum := history.Summarize(month)
+	report := &statsReport{
+		Month:                 start.Format("2006-01"),
+		Processed:             sum.Processed,
+		Failed:                sum.Failed,
+		FailureRate:           sum.FailureRate(),
+		AudioSeconds:          sum.AudioDuration.Seconds(),
+		Words:                 sum.Words,
+		AverageElapsedSeconds: sum.AverageElapsed.Seconds(),
+		Speed:                 sum.Speed(),
+		Failures:              sum.Failures,
+		Cost:                  sum.Cost,
+		Providers:             make(map[string]providerReport, len(sum.Usage)),
+		summary:               sum,
+	}
+	if report.Failures == nil {
+		report.Failures = map[string]int{}
+	}
+	for name, u := range sum.Usage {
+		report.Providers[name] = providerReport{Files: u.Files, AudioSeconds: u.AudioDuration.Seconds(), Cost: u.Cost}
+	}
+	if usage != nil {
+		report.usage = *usage
+		report.Budget = usage.MonthlyBudget
+		report.Currency = usage.Currency
+	}
+	return report, nil
+}
+
+// print writes the report as text
+func (r *statsReport) print(out io.Writer) {
+	month, _ := time.Parse("2006-01", r.Month)
+	title := month.Format("January 2006")
+
+	sum := r.summary
+	if sum.Total() == 0 {
+		fmt.Fprintf(out, "No files processed in %s\n", title)
+		return
+	}
+
+	fmt.Fprintf(out, "Transcription usage for %s\n\n", title)
+	fmt.Fprintf(out, "Files:           %d transcribed, %d failed (%.1f%% failure rate)\n",
+		sum.Processed, sum.Failed, sum.FailureRate()*100)
+	fmt.Fprintf(out, "Audio:           %.1f minutes\n", sum.AudioDuration.Minutes())
+	fmt.Fprintf(out, "Words:           %d\n", sum.Words)
+	if sum.Processed > 0 {
+		fmt.Fprintf(out, "Average latency: %s per file\n", sum.AverageElapsed.Round(100*time.Millisecond))
+	}
+	if speed := sum.Speed(); speed > 0 {
+		fmt.Fprintf(out, "Speed:           %.1fx realtime\n", speed)
+	}
+	r.printUsage(out)
+
+	if len(sum.Failures) > 0 {
+		fmt.Fprintln(out, "Failures:")
+		printFailures(out, sum.Failures)
+	}
+}
+
+// printUsage writes the minutes and cost per provider and the budget spent.
+func (r *statsReport) printUsage(out io.Writer) {
+	priced := r.Cost > 0 || r.Budget > 0
+	if priced {
+		line := fmt.Sprintf("Cost:            %s", r.usage.FormatCost(r.Cost))
+		if r.Budget > 0 {
+			line += fmt.Sprintf(" of %s budget (%.1f%%)", r.usage.FormatCost(r.Budget), 100*r.Cost/r.Budget)
+		}
+		fmt.Fprintln(out, line)
+	}
+	if len(r.Providers) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(r.Providers))
+	for name := range r.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(out, "Providers:")
+	for _, name := range names {
+		p := r.Providers[name]
+		line := fmt.Sprintf("  %-24s %4d files %8.1f minutes", name, p.Files, p.AudioSeconds/60)
+		if priced {
+			line += "  " + r.usage.FormatCost(p.Cost)
+		}
+		fmt.Fprintln(out, line)
+	}
+}
+
+// newTranscribeTestCmd creates the transcribe test command
+func newTranscribeTestCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "test <audio-file>",
+		Short: "Transcribe one file and print the resulting note",
+		Long: `Runs a single audio file through the transcription pipeline using the vault's
+configuration and prints the note that would be written to stdout.
+
+Nothing is written to the output directory and the audio file is not archived,
+so this is a quick way to iterate on templates and settings.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := transcribe.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			printConfigWarnings(cmd.ErrOrStderr(), cfg)
+
+			svc, err := transcribe.NewService(cfg)
+			if err != nil {
+				return fmt.Errorf("create service: %w", err)
+			}
+			defer svc.Close()
+
+			preview, err := svc.Preview(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprint(cmd.OutOrStdout(), preview.Note)
+			fmt.Fprintf(cmd.ErrOrStderr(), "\nTranscribed %s in %s (language: %s)\n",
+				status.BaseName(args[0]), preview.Elapsed.Round(100*time.Millisecond), preview.Language)
+			fmt.Fprintf(cmd.ErrOrStderr(), "Would write: %s\n", preview.OutputPath)
+			return nil
+		},
+	}
+}
+
+// newTranscribeFileCmd creates the transcribe file command
+func newTranscribeFileCmd() *cobra.Command {
+	var (
+		stdout bool
+		keep   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "file <audio-file>",
+		Short: "Transcribe one file",
+		Long: `Runs a single audio file through the transcription pipeline, writing its note
+to the configured output directory and archiving the file unless --keep is set.
+
+With --stdout only the transcript text is printed, and nothing is written or
+archived, so the command can be used in shell pipelines:
+
+  nota transcribe file memo.m4a --stdout | wl-copy`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := transcribe.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			printConfigWarnings(cmd.ErrOrStderr(), cfg)
+
+			svc, err := transcribe.NewService(cfg)
+			if err != nil {
+				return fmt.Errorf("create service: %w", err)
+			}
+			defer svc.Close()
+
+			if stdout {
+				result, err := svc.Transcript(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), result.Text)
+				return nil
+			}
+
+			summary := svc.Import(cmd.Context(), args, transcribe.ImportOptions{KeepOriginals: keep})
+			if len(summary.Failed) > 0 {
+				return summary.Failed[0].Err
+			}
+			if summary.Skipped > 0 {
+				return cmd.Context().Err()
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Transcribed %s in %s\n",
+				status.BaseName(args[0]), summary.Elapsed.Round(100*time.Millisecond))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&stdout, "stdout", false, "Print only the transcript instead of writing a note")
+	cmd.Flags().BoolVar(&keep, "keep", false, "Leave the file in place instead of archiving it")
+
+	return cmd
+}
+
+// compareChangesShown is how many differing passages compare prints.
+const compareChangesShown = 10
+
+// compareReport is the JSON form of a comparison.
+type compareReport struct {
+	Audio       string      `json:"audio"`
+	A           compareSide `json:"a"`
+	B           compareSide `json:"b"`
+	WordsA      int         `json:"words_a"`
+	WordsB      int         `json:"words_b"`
+	Substituted int         `json:"substituted"`
+	Inserted    int         `json:"inserted"`
+	Deleted     int         `json:"deleted"`
+	Rate        float64     `json:"rate"`
+	Diff        string      `json:"diff"`
+}
+
+// compareSide is one transcription in a compareReport.
+type compareSide struct {
+	Model      string  `json:"model"`
+	APIURL     string  `json:"api_url"`
+	Language   string  `json:"language"`
+	ElapsedSec float64 `json:"elapsed_sec"`
+	Transcript string  `json:"transcript"`
+}
+
+// newTranscribeCompareCmd creates the transcribe compare command
+func newTranscribeCompareCmd() *cobra.Command {
+	var (
+		model  string
+		apiURL string
+		dir    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "compare <audio-file>",
+		Short: "Transcribe one file with two models and diff the transcripts",
+		Long: `Transcribes an audio file with the configured model and endpoint (A), then
+again with another (B), and summarizes how the transcripts differ word by word,
+to judge whether a bigger model is worth the GPU time.
+
+B is set with --model and --api-url, or the "compare" block in transcribe.json.
+Both transcripts and the diff, in git's word-diff style ([-A's words-]{+B's
+words+}), are written to --dir, by default the compare folder in nota's state
+directory. Nothing is written to the vault and the audio is not archived.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := transcribe.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			printConfigWarnings(cmd.ErrOrStderr(), cfg)
+
+			svc, err := transcribe.NewService(cfg)
+			if err != nil {
+				return fmt.Errorf("create service: %w", err)
+			}
+			defer svc.Close()
+
+			c, err := svc.Compare(cmd.Context(), args[0], transcribe.CompareOptions{Model: model, APIURL: apiURL, Dir: dir})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if JSONOutput(cmd) {
+				return writeJSON(out, newCompareReport(c))
+			}
+			printComparison(out, c)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&model, "model", "", "Whisper model to compare with (default: compare.model, else the configured model)")
+	cmd.Flags().StringVar(&apiURL, "api-url", "", "Transcription endpoint to compare with (default: compare.api_url, else api_url)")
+	cmd.Flags().StringVar(&dir, "dir", "", "Directory to write the transcripts and diff to")
+
+	return cmd
+}
+
+// newCompareReport builds the JSON form of c.
+func newCompareReport(c *transcribe.Comparison) compareReport {
+	side := func(r transcribe.CompareRun) compareSide {
+		return compareSide{
+			Model:      r.Model,
+			APIURL:     r.APIURL,
+			Language:   r.Language,
+			ElapsedSec: r.Elapsed.Seconds(),
+			Transcript: r.Text,
+		}
+	}
+	return compareReport{
+		Audio:       c.Audio,
+		A:           side(c.A),
+		B:           side(c.B),
+		WordsA:      c.Diff.WordsA,
+		WordsB:      c.Diff.WordsB,
+		Substituted: c.Diff.Substituted,
+		Inserted:    c.Diff.Inserted,
+		Deleted:     c.Diff.Deleted,
+		Rate:        c.Diff.Rate(),
+		Diff:        c.Diff.Render(),
+	}
+}
+
+// printComparison prints the timings and word counts of both
+// transcriptions, the diff summary and the first differing passages.
+func printComparison(out io.Writer, c *transcribe.Comparison) {
+	fmt.Fprintf(out, "Compared %s\n", status.BaseName(c.Audio))
+	fmt.Fprintf(out, "  A  %-12s %8s  %5d words  %s\n", c.A.Model, c.A.Elapsed.Round(100*time.Millisecond), c.Diff.WordsA, c.A.APIURL)
+	fmt.Fprintf(out, "  B  %-12s %8s  %5d words  %s\n", c.B.Model, c.B.Elapsed.Round(100*time.Millisecond), c.Diff.WordsB, c.B.APIURL)
+	fmt.Fprintf(out, "\n%s\n", c.Diff)
+
+	for i, ch := range c.Diff.Changes {
+		if i == compareChangesShown {
+			fmt.Fprintf(out, "  ... %d more\n", len(c.Diff.Changes)-i)
+			break
+		}
+		fmt.Fprintf(out, "  %q -> %q\n", strings.Join(ch.A, " "), strings.Join(ch.B, " "))
+	}
+
+	fmt.Fprintf(out, "\nTranscripts: %s, %s\n", c.A.Path, c.B.Path)
+	fmt.Fprintf(out, "Diff: %s\n", c.DiffPath)
+}
+
+// newTranscribeReprocessCmd creates the transcribe reprocess command
+func newTranscribeReprocessCmd() *cobra.Command {
+	var (
+		model    string
+		language string
+		replace  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "reprocess <note|audio-file>",
+		Short: "Regenerate a note from its archived audio",
+		Long: `Transcribes the audio behind a note again, for example with a better model.
+
+Given a note, the audio is located through the archive_path (or source_path)
+recorded in its frontmatter. Given an audio file, its note is looked up in the
+output directories; audio without a note gets a new one.
+
+By default the new transcript is written next to the note as a new version
+(memo-v2.md) with the same layout, so the two can be diffed. With --replace the
+note's "# Transcription" section and processing details are rewritten in place.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := transcribe.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			printConfigWarnings(cmd.ErrOrStderr(), cfg)
+			if model != "" {
+				cfg.Model = model
+			}
+			if language != "" {
+				cfg.Language = language
+			}
+
+			svc, err := transcribe.NewService(cfg)
+			if err != nil {
+				return fmt.Errorf("create service: %w", err)
+			}
+			defer svc.Close()
+
+			result, err := svc.Reprocess(cmd.Context(), args[0], transcribe.ReprocessOptions{Replace: replace})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Transcribed %s in %s (model: %s, language: %s)\n",
+				result.Audio, result.Elapsed.Round(100*time.Millisecond), cfg.Model, result.Language)
+			switch {
+			case result.Previous == "":
+				fmt.Fprintf(out, "Wrote new note: %s\n", result.Note)
+			case result.Note == result.Previous:
+				fmt.Fprintf(out, "Updated: %s\n", result.Note)
+			default:
+				fmt.Fprintf(out, "Wrote: %s\n", result.Note)
+				fmt.Fprintf(out, "Compare with: diff %s %s\n", result.Previous, result.Note)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&model, "model", "", "Whisper model to use instead of the configured one")
+	cmd.Flags().StringVar(&language, "language", "", "Transcription language instead of the configured one")
+	cmd.Flags().BoolVar(&replace, "replace", false, "Rewrite the transcript in the existing note instead of writing a new version")
+
+	return cmd
+}
+
+// newTranscribeArchiveCmd creates the transcribe archive command
+func newTranscribeArchiveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Work with archived audio",
+	}
+	cmd.AddCommand(newTranscribeArchiveRestoreCmd())
+	return cmd
+}
+
+// newTranscribeArchiveRestoreCmd creates the transcribe archive restore command
+func newTranscribeArchiveRestoreCmd() *cobra.Command {
+	var target string
+
+	cmd := &cobra.Command{
+		Use:   "restore <note|archived-file>",
+		Short: "Copy archived audio back into the watch directory",
+		Long: `Copies a recording out of the archive so it can be listened to again or
+reprocessed. The archived copy is left in place.
+
+The recording is located through the archive_path recorded in a note's
+frontmatter, or given as the path or file name of an archived file; names are
+looked up in archive_dir, and the most recent match is restored.
+
+The copy goes to the watch directory, where a running daemon picks it up and
+transcribes it again, unless --to names another file or directory. Existing
+files are never overwritten.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := transcribe.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			printConfigWarnings(cmd.ErrOrStderr(), cfg)
+
+			restored, err := transcribe.Restore(cfg, args[0], target)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Restored: %s\n", restored)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&target, "to", "", "File or directory to restore to instead of the watch directory")
+
+	return cmd
+}
+
+// newTranscribeMockServerCmd creates the transcribe mock-server command
+func newTranscribeMockServerCmd() *cobra.Command {
+	var (
+		port     int
+		text     string
+		language string
+		delay    time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "mock-server",
+		Short: "Run a fake ASR server for local development",
+		Long: `Serves a fake whisper-asr-webservice /asr endpoint that returns a canned
+transcription for every upload, so the pipeline can be tested end-to-end without
+Whisper hardware.
+
+Run "nota transcribe config" and set the API URL to http://localhost:9000
+(or the chosen --port) to use it.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mock := mockasr.New()
+			mock.Text = text
+			mock.Language = language
+			mock.Delay = delay
+			mock.OnRequest = func(filename string, size int64) {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s received %s (%s)\n",
+					time.Now().Format("15:04:05"), filename, formatSize(size))
+			}
+
+			listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+			if err != nil {
+				return fmt.Errorf("listen: %w", err)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			server := &http.Server{Handler: mock}
+			go func() {
+				<-ctx.Done()
+				server.Close()
+			}()
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Mock ASR server listening on http://%s (Ctrl+C to stop)\n", listener.Addr())
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("serve: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&port, "port", 9000, "Port to listen on")
+	cmd.Flags().StringVar(&text, "text", mockasr.DefaultText, "Transcription text to return")
+	cmd.Flags().StringVar(&language, "language", "en", "Detected language to report")
+	cmd.Flags().DurationVar(&delay, "delay", 0, "Simulated transcription time per request")
+
+	return cmd
+}
+
+// newTranscribeImportCmd creates the transcribe import command
+func newTranscribeImportCmd() *cobra.Command {
+	var (
+		workers  int
+		patterns []string
+		keep     bool
+		dryRun   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <dir>",
+		Short: "Transcribe a directory of existing recordings",
+		Long: `Walks a directory recursively and runs every matching audio file through the
+transcription pipeline, without moving it through the watch folder.
+
+Files are matched against the configured watch patterns unless --pattern is
+given. Notes are written to the configured output directory and the originals
+are archived, unless --keep is set.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := transcribe.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			printConfigWarnings(cmd.ErrOrStderr(), cfg)
+			if len(patterns) == 0 {
+				cfg.ApplyDefaults()
+				patterns = cfg.WatchPatterns
+			}
+
+			files, err := transcribe.FindAudioFiles(args[0], patterns)
+			if err != nil {
+				return fmt.Errorf("scan %s: %w", args[0], err)
+			}
+			out := cmd.OutOrStdout()
+			if len(files) == 0 {
+				fmt.Fprintf(out, "No files matching %s found in %s\n", strings.Join(patterns, ", "), args[0])
+				return nil
+			}
+
+			svc, err := transcribe.NewService(cfg)
+			if err != nil {
+				return fmt.Errorf("create service: %w", err)
+			}
+			defer svc.Close()
+
+			fmt.Fprintf(out, "Found %d files in %s\n", len(files), args[0])
+			bar := NewBarProgress(out, len(files))
+			svc.SetProgressReporter(bar)
+			svc.SetDryRun(dryRun)
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			summary := svc.Import(ctx, files, transcribe.ImportOptions{
+				Workers:       workers,
+				KeepOriginals: keep,
+			})
+			bar.Finish()
+
+			printImportSummary(out, summary)
+			if len(summary.Failed) > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d of %d files failed to import", len(summary.Failed), summary.Total)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&workers, "workers", "w", 0, "Number of files to transcribe concurrently (default: configured workers)")
+	cmd.Flags().StringSliceVar(&patterns, "pattern", nil, "File patterns to import (default: configured watch patterns)")
+	cmd.Flags().BoolVar(&keep, "keep", false, "Leave the original files in place instead of archiving them")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List what would be transcribed, written and archived without doing it")
+
+	return cmd
+}
+
+// printImportSummary prints the result of a batch import
+func printImportSummary(out io.Writer, summary *transcribe.ImportSummary) {
+	fmt.Fprintf(out, "\nImported %d of %d files in %s\n",
+		summary.Completed, summary.Total, summary.Elapsed.Round(time.Second))
+
+	if len(summary.Failed) > 0 {
+		fmt.Fprintf(out, "Failed (%d):\n", len(summary.Failed))
+		for _, f := range summary.Failed {
+			fmt.Fprintf(out, "  %s: %v\n", f.Path, f.Err)
+		}
+	}
+	if summary.Skipped > 0 {
+		fmt.Fprintf(out, "Skipped %d files (interrupted)\n", summary.Skipped)
 	}
 }
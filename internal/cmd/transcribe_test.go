@@ -3,14 +3,31 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
-
-	"github.com/TechnicallyShaun/nota-orbis/internal/transcribe"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/checkpoint"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/pidfile"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/status"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/worddiff"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
 )
 
+// TestMain clears XDG_STATE_HOME so tests that point HOME at a temporary
+// directory keep their state files there too.
+func TestMain(m *testing.M) {
+	os.Unsetenv("XDG_STATE_HOME")
+	os.Exit(m.Run())
+}
+
 func setupTestVault(t *testing.T) string {
 	t.Helper()
 	tmpDir := t.TempDir()
@@ -41,14 +58,35 @@ func TestTranscribeCmd_HasConfigSubcommand(t *testing.T) {
 	}
 }
 
+// setupWizardDirs creates a watch folder and output location for config wizard
+// input and points HOME at a temp dir holding the default archive directory,
+// so the wizard's directory checks pass without prompting.
+func setupWizardDirs(t *testing.T) (watchDir, outputDir string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	t.Cleanup(func() { os.Setenv("HOME", originalHome) })
+
+	watchDir = filepath.Join(tmpDir, "voice-notes")
+	outputDir = filepath.Join(tmpDir, "Inbox")
+	for _, dir := range []string{watchDir, outputDir, filepath.Join(tmpDir, ".nota", "archive", "audio")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	return watchDir, outputDir
+}
+
 func TestTranscribeConfigCmd_SavesConfiguration(t *testing.T) {
 	vaultRoot := setupTestVault(t)
 	originalWd, _ := os.Getwd()
 	defer os.Chdir(originalWd)
 	os.Chdir(vaultRoot)
+	watchDir, outputDir := setupWizardDirs(t)
 
 	// Simulate user input
-	input := "/mnt/sync/voice-notes\nhttp://nas:9000/asr\n/home/user/vault/Inbox\n\n\n"
+	input := watchDir + "\nhttp://nas:9000/asr\n" + outputDir + "\n\n\n"
 	prompter := NewReaderPrompter(strings.NewReader(input))
 
 	var buf bytes.Buffer
@@ -72,14 +110,14 @@ func TestTranscribeConfigCmd_SavesConfiguration(t *testing.T) {
 		t.Fatalf("expected valid JSON config: %v", err)
 	}
 
-	if cfg.WatchDir != "/mnt/sync/voice-notes" {
-		t.Errorf("expected WatchDir %q, got %q", "/mnt/sync/voice-notes", cfg.WatchDir)
+	if cfg.WatchDir != watchDir {
+		t.Errorf("expected WatchDir %q, got %q", watchDir, cfg.WatchDir)
 	}
 	if cfg.APIURL != "http://nas:9000/asr" {
 		t.Errorf("expected APIURL %q, got %q", "http://nas:9000/asr", cfg.APIURL)
 	}
-	if cfg.OutputDir != "/home/user/vault/Inbox" {
-		t.Errorf("expected OutputDir %q, got %q", "/home/user/vault/Inbox", cfg.OutputDir)
+	if cfg.OutputDir != outputDir {
+		t.Errorf("expected OutputDir %q, got %q", outputDir, cfg.OutputDir)
 	}
 }
 
@@ -88,9 +126,10 @@ func TestTranscribeConfigCmd_AppliesDefaultArchiveDir(t *testing.T) {
 	originalWd, _ := os.Getwd()
 	defer os.Chdir(originalWd)
 	os.Chdir(vaultRoot)
+	watchDir, outputDir := setupWizardDirs(t)
 
 	// Simulate user input with empty archive dir
-	input := "/mnt/sync/voice-notes\nhttp://nas:9000/asr\n/home/user/vault/Inbox\n\n\n"
+	input := watchDir + "\nhttp://nas:9000/asr\n" + outputDir + "\n\n\n"
 	prompter := NewReaderPrompter(strings.NewReader(input))
 
 	var buf bytes.Buffer
@@ -123,9 +162,12 @@ func TestTranscribeConfigCmd_SavesCustomArchiveDir(t *testing.T) {
 	originalWd, _ := os.Getwd()
 	defer os.Chdir(originalWd)
 	os.Chdir(vaultRoot)
+	watchDir, outputDir := setupWizardDirs(t)
+	archiveDir := filepath.Join(t.TempDir(), "archive")
+	os.Mkdir(archiveDir, 0755)
 
 	// Simulate user input with custom archive dir
-	input := "/mnt/sync/voice-notes\nhttp://nas:9000/asr\n/home/user/vault/Inbox\n\n/custom/archive\n"
+	input := watchDir + "\nhttp://nas:9000/asr\n" + outputDir + "\n\n" + archiveDir + "\n"
 	prompter := NewReaderPrompter(strings.NewReader(input))
 
 	var buf bytes.Buffer
@@ -148,8 +190,8 @@ func TestTranscribeConfigCmd_SavesCustomArchiveDir(t *testing.T) {
 		t.Fatalf("expected valid JSON config: %v", err)
 	}
 
-	if cfg.ArchiveDir != "/custom/archive" {
-		t.Errorf("expected ArchiveDir %q, got %q", "/custom/archive", cfg.ArchiveDir)
+	if cfg.ArchiveDir != archiveDir {
+		t.Errorf("expected ArchiveDir %q, got %q", archiveDir, cfg.ArchiveDir)
 	}
 }
 
@@ -158,9 +200,10 @@ func TestTranscribeConfigCmd_SavesTemplatePath(t *testing.T) {
 	originalWd, _ := os.Getwd()
 	defer os.Chdir(originalWd)
 	os.Chdir(vaultRoot)
+	watchDir, outputDir := setupWizardDirs(t)
 
 	// Simulate user input with template path
-	input := "/mnt/sync/voice-notes\nhttp://nas:9000/asr\n/home/user/vault/Inbox\n/path/to/template.md\n\n"
+	input := watchDir + "\nhttp://nas:9000/asr\n" + outputDir + "\n/path/to/template.md\n\n"
 	prompter := NewReaderPrompter(strings.NewReader(input))
 
 	var buf bytes.Buffer
@@ -196,9 +239,10 @@ func TestTranscribeConfigCmd_SkipsTemplatePath(t *testing.T) {
 	originalWd, _ := os.Getwd()
 	defer os.Chdir(originalWd)
 	os.Chdir(vaultRoot)
+	watchDir, outputDir := setupWizardDirs(t)
 
 	// Simulate user input with empty template path
-	input := "/mnt/sync/voice-notes\nhttp://nas:9000/asr\n/home/user/vault/Inbox\n\n\n"
+	input := watchDir + "\nhttp://nas:9000/asr\n" + outputDir + "\n\n\n"
 	prompter := NewReaderPrompter(strings.NewReader(input))
 
 	var buf bytes.Buffer
@@ -266,23 +310,327 @@ func TestTranscribeConfigCmd_RequiresAPIURL(t *testing.T) {
 	}
 }
 
-func TestTranscribeConfigCmd_RequiresOutputDir(t *testing.T) {
+func TestTranscribeConfigCmd_DefaultsOutputDirToVaultInbox(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+	watchDir, _ := setupWizardDirs(t)
+
+	// Simulate user input with empty output dir, accepting the offer to
+	// create the vault inbox
+	input := watchDir + "\nhttp://nas:9000/asr\n\n\n\n\n"
+	prompter := NewReaderPrompter(strings.NewReader(input))
+
+	var buf bytes.Buffer
+	cmd := NewTranscribeConfigCmd(prompter, false)
+	cmd.SetOut(&buf)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(vaultRoot, ".nota", "transcribe.json"))
+	if err != nil {
+		t.Fatalf("expected config file to exist: %v", err)
+	}
+	var cfg transcribe.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("expected valid JSON config: %v", err)
+	}
+	if cfg.OutputDir != "${VAULT}/Inbox" {
+		t.Errorf("expected OutputDir %q, got %q", "${VAULT}/Inbox", cfg.OutputDir)
+	}
+	if info, err := os.Stat(filepath.Join(vaultRoot, "Inbox")); err != nil || !info.IsDir() {
+		t.Errorf("expected vault inbox to be created, got: %v", err)
+	}
+}
+
+func TestTranscribeConfigCmd_StoresPathsInsideVaultRelative(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+	watchDir, _ := setupWizardDirs(t)
+
+	// FindVaultRoot may resolve symlinks in the temp dir, so use its answer
+	root, err := vault.FindVaultRoot()
+	if err != nil {
+		t.Fatalf("expected vault root, got: %v", err)
+	}
+	input := watchDir + "\nhttp://nas:9000/asr\n" + filepath.Join(root, "Areas", "Inbox") + "\n" +
+		filepath.Join(root, "Templates", "voice.md") + "\n\ny\n"
+	prompter := NewReaderPrompter(strings.NewReader(input))
+
+	var buf bytes.Buffer
+	cmd := NewTranscribeConfigCmd(prompter, false)
+	cmd.SetOut(&buf)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(vaultRoot, ".nota", "transcribe.json"))
+	var cfg transcribe.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("expected valid JSON config: %v", err)
+	}
+	if cfg.OutputDir != "${VAULT}/Areas/Inbox" {
+		t.Errorf("expected OutputDir %q, got %q", "${VAULT}/Areas/Inbox", cfg.OutputDir)
+	}
+	if cfg.TemplatePath == nil || *cfg.TemplatePath != "${VAULT}/Templates/voice.md" {
+		t.Errorf("expected vault-relative TemplatePath, got %v", cfg.TemplatePath)
+	}
+	if cfg.WatchDir != watchDir {
+		t.Errorf("expected WatchDir outside the vault unchanged, got %q", cfg.WatchDir)
+	}
+}
+
+func TestTranscribeConfigCmd_DeclinedDirectoryWarns(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+	_, outputDir := setupWizardDirs(t)
+	missing := filepath.Join(t.TempDir(), "not-mounted")
+
+	input := missing + "\nhttp://nas:9000/asr\n" + outputDir + "\n\n\nn\n"
+	prompter := NewReaderPrompter(strings.NewReader(input))
+
+	var buf bytes.Buffer
+	cmd := NewTranscribeConfigCmd(prompter, false)
+	cmd.SetOut(&buf)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Warning: watch folder "+missing+" does not exist yet") {
+		t.Errorf("expected warning about missing watch folder, got:\n%s", buf.String())
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Error("expected declined directory not to be created")
+	}
+}
+
+func TestTranscribeConfigCmd_WarnsAboutTemplateProblems(t *testing.T) {
 	vaultRoot := setupTestVault(t)
 	originalWd, _ := os.Getwd()
 	defer os.Chdir(originalWd)
 	os.Chdir(vaultRoot)
+	watchDir, outputDir := setupWizardDirs(t)
 
-	// Simulate user input with empty output dir
-	input := "/mnt/sync/voice-notes\nhttp://nas:9000/asr\n\n\n\n"
+	templatePath := vault.TemplatePath(vaultRoot, "memo")
+	os.MkdirAll(filepath.Dir(templatePath), 0755)
+	os.WriteFile(templatePath, []byte("---\ntags: [memo\n---\n# {{title}}\n"), 0644)
+
+	input := watchDir + "\nhttp://nas:9000/asr\n" + outputDir + "\nmemo\n\n"
 	prompter := NewReaderPrompter(strings.NewReader(input))
 
 	var buf bytes.Buffer
 	cmd := NewTranscribeConfigCmd(prompter, false)
 	cmd.SetOut(&buf)
 
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	for _, expected := range []string{
+		"Warning: template " + templatePath + ": invalid frontmatter: line 2: unclosed [",
+		"Warning: template " + templatePath + ": line 4: placeholder {{title}} is copied into notes as written",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("expected %q, got:\n%s", expected, buf.String())
+		}
+	}
+}
+
+func TestTranscribeDoctorCmd(t *testing.T) {
+	tmpHome := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpHome)
+	defer os.Setenv("HOME", originalHome)
+
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	os.MkdirAll(filepath.Dir(vault.TemplatePath(vaultRoot, "memo")), 0755)
+	os.WriteFile(vault.TemplatePath(vaultRoot, "memo"), []byte("---\ntype: memo\n---\n# Memo\n"), 0644)
+	os.WriteFile(vault.TemplatePath(vaultRoot, "meeting"), []byte("---\ntype meeting\n---\n"), 0644)
+
+	template := "memo"
+	cfg := &transcribe.Config{
+		WatchDir:     vaultRoot,
+		APIURL:       "http://nas:9000",
+		OutputDir:    filepath.Join(vaultRoot, "Inbox"),
+		TemplatePath: &template,
+		Routes:       []transcribe.RouteRule{{FilenameRegex: "^meeting-", TemplatePath: "meeting"}},
+	}
+	if err := cfg.SaveToVault(vaultRoot); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd := newTranscribeDoctorCmd()
+	cmd.SetOut(&buf)
 	err := cmd.Execute()
-	if err == nil {
-		t.Error("expected error when output dir is empty")
+	if err == nil || err.Error() != "found 1 problem" {
+		t.Errorf("expected one problem, got: %v", err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		"ok       config",
+		"ok       directory " + vaultRoot,
+		"ok       template " + vault.TemplatePath(vaultRoot, "memo"),
+		"problem  template " + vault.TemplatePath(vaultRoot, "meeting") + ": invalid frontmatter: line 2 is not a \"key: value\" pair",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestTranscribeConfigCmd_RejectsInvalidAPIURL(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+	watchDir, outputDir := setupWizardDirs(t)
+
+	input := watchDir + "\nnas:9000\n" + outputDir + "\n\n\n"
+	prompter := NewReaderPrompter(strings.NewReader(input))
+
+	var buf bytes.Buffer
+	cmd := NewTranscribeConfigCmd(prompter, false)
+	cmd.SetOut(&buf)
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), `did you mean "http://nas:9000"`) {
+		t.Errorf("expected error suggesting http://nas:9000, got: %v", err)
+	}
+}
+
+func TestTranscribeConfigInitCmd_WritesExample(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	var buf bytes.Buffer
+	cmd := NewTranscribeConfigCmd(nil, false)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"init"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	cfg, err := transcribe.LoadFromVault(vaultRoot)
+	if err != nil {
+		t.Fatalf("expected example config to load, got: %v", err)
+	}
+	if cfg.Model != transcribe.DefaultModel {
+		t.Errorf("expected default model %q, got %q", transcribe.DefaultModel, cfg.Model)
+	}
+
+	// A second run must not overwrite the file
+	cmd = NewTranscribeConfigCmd(nil, false)
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs([]string{"init"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected already exists error, got: %v", err)
+	}
+}
+
+func TestTranscribeConfigInitCmd_Print(t *testing.T) {
+	var buf bytes.Buffer
+	cmd := NewTranscribeConfigCmd(nil, false)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"init", "--print"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if buf.String() != transcribe.SampleConfig() {
+		t.Errorf("expected the sample config on stdout, got:\n%s", buf.String())
+	}
+}
+
+// setupFakeEditor points $EDITOR at a script that replaces the edited file
+// with content.
+func setupFakeEditor(t *testing.T, content string) {
+	t.Helper()
+	dir := t.TempDir()
+	contentPath := filepath.Join(dir, "content.json")
+	os.WriteFile(contentPath, []byte(content), 0644)
+	script := filepath.Join(dir, "editor.sh")
+	os.WriteFile(script, []byte("#!/bin/sh\ncp "+contentPath+" \"$1\"\n"), 0755)
+
+	for key, value := range map[string]string{"EDITOR": script, "VISUAL": ""} {
+		original := os.Getenv(key)
+		os.Setenv(key, value)
+		t.Cleanup(func() { os.Setenv(key, original) })
+	}
+}
+
+func TestTranscribeConfigEditCmd_SavesWithDefaults(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+	setupFakeEditor(t, `{"watch_dir": "/mnt/sync", "api_url": "http://nas:9000/asr/", "output_dir": "${VAULT}/Inbox"}`)
+
+	var buf bytes.Buffer
+	cmd := NewTranscribeConfigCmd(NewReaderPrompter(strings.NewReader("")), false)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"edit"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(vaultRoot, ".nota", "transcribe.json"))
+	var cfg transcribe.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("expected valid JSON config: %v", err)
+	}
+	if cfg.OutputDir != "${VAULT}/Inbox" {
+		t.Errorf("expected OutputDir to stay vault-relative, got %q", cfg.OutputDir)
+	}
+	if cfg.APIURL != "http://nas:9000/asr" {
+		t.Errorf("expected normalized APIURL, got %q", cfg.APIURL)
+	}
+	if cfg.Model != transcribe.DefaultModel || cfg.SchemaVersion != transcribe.CurrentSchemaVersion {
+		t.Errorf("expected defaults to be filled in, got model %q schema %d", cfg.Model, cfg.SchemaVersion)
+	}
+}
+
+func TestTranscribeConfigEditCmd_RefusesInvalid(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	configPath := filepath.Join(vaultRoot, ".nota", "transcribe.json")
+	original := `{"watch_dir": "/mnt/sync", "api_url": "http://nas:9000/asr", "output_dir": "/vault/Inbox"}`
+	os.WriteFile(configPath, []byte(original), 0644)
+	setupFakeEditor(t, `{"wacth_dir": "/mnt/sync", "api_url": "http://nas:9000/asr", "output_dir": "/vault/Inbox"}`)
+
+	var buf bytes.Buffer
+	cmd := NewTranscribeConfigCmd(NewReaderPrompter(strings.NewReader("n\n")), false)
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs([]string{"edit"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "did you mean watch_dir?") {
+		t.Errorf("expected unknown field error, got: %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if string(data) != original {
+		t.Errorf("expected config to be unchanged, got:\n%s", data)
 	}
 }
 
@@ -311,8 +659,9 @@ func TestTranscribeConfigCmd_PrintsSummary(t *testing.T) {
 	originalWd, _ := os.Getwd()
 	defer os.Chdir(originalWd)
 	os.Chdir(vaultRoot)
+	watchDir, outputDir := setupWizardDirs(t)
 
-	input := "/mnt/sync/voice-notes\nhttp://nas:9000/asr\n/home/user/vault/Inbox\n\n\n"
+	input := watchDir + "\nhttp://nas:9000/asr\n" + outputDir + "\n\n\n"
 	prompter := NewReaderPrompter(strings.NewReader(input))
 
 	var buf bytes.Buffer
@@ -341,8 +690,9 @@ func TestTranscribeConfigCmd_AppliesDefaults(t *testing.T) {
 	originalWd, _ := os.Getwd()
 	defer os.Chdir(originalWd)
 	os.Chdir(vaultRoot)
+	watchDir, outputDir := setupWizardDirs(t)
 
-	input := "/mnt/sync/voice-notes\nhttp://nas:9000/asr\n/home/user/vault/Inbox\n\n\n"
+	input := watchDir + "\nhttp://nas:9000/asr\n" + outputDir + "\n\n\n"
 	prompter := NewReaderPrompter(strings.NewReader(input))
 
 	var buf bytes.Buffer
@@ -421,6 +771,70 @@ func TestTranscribeCmd_HasStatusSubcommand(t *testing.T) {
 	}
 }
 
+func TestTranscribeCmd_HasMockServerSubcommand(t *testing.T) {
+	cmd := NewTranscribeCmd()
+
+	found := false
+	for _, sub := range cmd.Commands() {
+		if sub.Use == "mock-server" {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		t.Error("expected transcribe command to have mock-server subcommand")
+	}
+}
+
+func TestTranscribeCmd_HasReprocessSubcommand(t *testing.T) {
+	cmd := NewTranscribeCmd()
+
+	found := false
+	for _, sub := range cmd.Commands() {
+		if sub.Name() == "reprocess" {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		t.Error("expected transcribe command to have reprocess subcommand")
+	}
+}
+
+func TestTranscribeCmd_HasArchiveRestoreSubcommand(t *testing.T) {
+	cmd, _, err := NewTranscribeCmd().Find([]string{"archive", "restore"})
+	if err != nil || cmd.Name() != "restore" {
+		t.Errorf("expected transcribe archive to have restore subcommand, got: %v", err)
+	}
+}
+
+func TestPrintComparison(t *testing.T) {
+	c := &transcribe.Comparison{
+		Audio:    "/in/memo.m4a",
+		A:        transcribe.CompareRun{Model: "base", APIURL: "http://nas:9000", Elapsed: 4200 * time.Millisecond, Path: "/state/compare/memo.base.txt"},
+		B:        transcribe.CompareRun{Model: "large-v3", APIURL: "http://gpu:9000", Elapsed: 12900 * time.Millisecond, Path: "/state/compare/memo.large-v3.txt"},
+		Diff:     worddiff.Compare("call the dentist on tuesday", "call the dentist on Thursday"),
+		DiffPath: "/state/compare/memo.diff.txt",
+	}
+
+	var out bytes.Buffer
+	printComparison(&out, c)
+	for _, want := range []string{
+		"Compared memo.m4a",
+		"A  base             4.2s      5 words  http://nas:9000",
+		"B  large-v3        12.9s      5 words  http://gpu:9000",
+		"1 of 5 words differ (20.0%): 1 substituted, 0 inserted, 0 deleted",
+		`"tuesday" -> "Thursday"`,
+		"Diff: /state/compare/memo.diff.txt",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
 func TestTranscribeStopCmd_NoDaemonRunning(t *testing.T) {
 	// Use a temp HOME so we don't interfere with real PID files
 	tmpDir := t.TempDir()
@@ -431,15 +845,14 @@ func TestTranscribeStopCmd_NoDaemonRunning(t *testing.T) {
 	var buf bytes.Buffer
 	cmd := newTranscribeStopCmd()
 	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
 
 	err := cmd.Execute()
-	if err != nil {
-		t.Fatalf("expected no error, got: %v", err)
+	if !errors.Is(err, ErrDaemonNotRunning) {
+		t.Fatalf("expected ErrDaemonNotRunning, got: %v", err)
 	}
-
-	output := buf.String()
-	if !strings.Contains(output, "not running") {
-		t.Errorf("expected output to say 'not running', got: %s", output)
+	if ExitCode(err) != ExitDaemonNotRunning {
+		t.Errorf("expected exit code %d, got %d", ExitDaemonNotRunning, ExitCode(err))
 	}
 }
 
@@ -465,16 +878,105 @@ func TestTranscribeStatusCmd_NoDaemonRunning(t *testing.T) {
 	}
 }
 
+func TestTranscribeStatusCmd_ShowsLastStop(t *testing.T) {
+	started := time.Date(2026, 1, 22, 14, 0, 0, 0, time.UTC)
+	stopped := started.Add(2 * time.Minute)
+
+	tests := []struct {
+		name     string
+		stop     *pidfile.Stop
+		expected string
+	}{
+		{"signal", &pidfile.Stop{Reason: pidfile.StopSignal, Signal: "SIGTERM"},
+			"Last stopped: SIGTERM at " + status.FormatTimestamp(stopped)},
+		{"drained", &pidfile.Stop{Reason: pidfile.StopDrained},
+			"Last stopped: drained at " + status.FormatTimestamp(stopped)},
+		{"error", &pidfile.Stop{Reason: pidfile.StopError, Error: "file watcher stopped unexpectedly"},
+			"Crashed: file watcher stopped unexpectedly at " + status.FormatTimestamp(stopped)},
+		{"no stop recorded", nil,
+			"Crashed: pid 4242 exited without recording a stop (started " + status.FormatTimestamp(started) + ")"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			if _, err := pidfile.RecordStart(4242, 0, started); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if tt.stop != nil {
+				if err := pidfile.RecordStop(stopped, *tt.stop); err != nil {
+					t.Fatalf("expected no error, got: %v", err)
+				}
+			}
+
+			var buf bytes.Buffer
+			cmd := newTranscribeStatusCmd()
+			cmd.SetOut(&buf)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if !strings.Contains(buf.String(), tt.expected+"\n") {
+				t.Errorf("expected %q, got:\n%s", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestTranscribeStatusAndStop_RemoveStalePIDFile(t *testing.T) {
+	for _, name := range []string{"status", "stop"} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			// A PID that's almost certainly not running
+			stalePID := 4194300
+			if err := pidfile.Write(stalePID); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if running, _, _ := pidfile.IsRunning(); running {
+				t.Skip("stale PID is unexpectedly running")
+			}
+
+			var out, errOut bytes.Buffer
+			root := NewRootCmd()
+			root.SetOut(&out)
+			root.SetErr(&errOut)
+			root.SetArgs([]string{"transcribe", name})
+			err := root.Execute()
+			if name == "stop" && !errors.Is(err, ErrDaemonNotRunning) {
+				t.Errorf("expected ErrDaemonNotRunning, got: %v", err)
+			}
+			if name == "status" && err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+
+			if _, err := pidfile.Read(); !errors.Is(err, pidfile.ErrNoPIDFile) {
+				t.Errorf("expected the stale PID file removed, got: %v", err)
+			}
+			if !strings.Contains(errOut.String(), "Removed stale PID file (PID 4194300 is not running)") {
+				t.Errorf("expected a notice, got: %q", errOut.String())
+			}
+		})
+	}
+}
+
+func TestStopReason(t *testing.T) {
+	if got := stopReason(nil, errors.New("load config: boom")); got.Reason != pidfile.StopError || got.Error != "load config: boom" {
+		t.Errorf("expected an error stop, got %+v", got)
+	}
+	if got := stopReason(nil, nil); got.Reason != pidfile.StopDrained {
+		t.Errorf("expected a drained stop, got %+v", got)
+	}
+}
+
 func TestTranscribeConfigCmd_AdvancedPromptsForAllFields(t *testing.T) {
 	vaultRoot := setupTestVault(t)
 	originalWd, _ := os.Getwd()
 	defer os.Chdir(originalWd)
 	os.Chdir(vaultRoot)
+	watchDir, outputDir := setupWizardDirs(t)
 
 	// Simulate user input with advanced options
 	// Basic: watch_dir, api_url, output_dir, template_path, archive_dir
 	// Advanced: stab_interval, stab_checks, language, model, max_file_size, retry_count, watch_patterns
-	input := "/mnt/sync/voice-notes\nhttp://nas:9000/asr\n/home/user/vault/Inbox\n\n\n" +
+	input := watchDir + "\nhttp://nas:9000/asr\n" + outputDir + "\n\n\n" +
 		"3000\n5\nen\nlarge\n200\n5\n*.m4a,*.wav\n"
 	prompter := NewReaderPrompter(strings.NewReader(input))
 
@@ -533,9 +1035,10 @@ func TestTranscribeConfigCmd_AdvancedAcceptsDefaults(t *testing.T) {
 	originalWd, _ := os.Getwd()
 	defer os.Chdir(originalWd)
 	os.Chdir(vaultRoot)
+	watchDir, outputDir := setupWizardDirs(t)
 
 	// Simulate user input accepting all defaults (empty inputs)
-	input := "/mnt/sync/voice-notes\nhttp://nas:9000/asr\n/home/user/vault/Inbox\n\n\n" +
+	input := watchDir + "\nhttp://nas:9000/asr\n" + outputDir + "\n\n\n" +
 		"\n\n\n\n\n\n\n"
 	prompter := NewReaderPrompter(strings.NewReader(input))
 
@@ -589,3 +1092,514 @@ func TestTranscribeConfigCmd_HasAdvancedFlag(t *testing.T) {
 		t.Error("expected config command to have --advanced flag")
 	}
 }
+
+func TestTranscribeStatusCmd_SummarizesHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	store, _ := history.Open()
+	now := time.Now().UTC()
+	store.Append(history.Record{Time: now.Add(-30 * 24 * time.Hour), Source: "/in/old.m4a", Status: history.StatusCompleted, ElapsedMs: 10000})
+	store.Append(history.Record{Time: now.Add(-2 * time.Hour), Source: "/in/a.m4a", Status: history.StatusCompleted, ElapsedMs: 2000})
+	store.Append(history.Record{Time: now.Add(-1 * time.Hour), Source: "/in/b.m4a", Status: history.StatusFailed, Error: "boom"})
+
+	var buf bytes.Buffer
+	cmd := newTranscribeStatusCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--since", "7d"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "Last 7d: 1 processed, 1 failed (50.0% failure rate), avg processing time 2s") {
+		t.Errorf("expected 7d summary, got: %s", output)
+	}
+	if !strings.Contains(output, "All time: 2 processed, 1 failed") {
+		t.Errorf("expected all-time summary, got: %s", output)
+	}
+}
+
+func TestTranscribeStatusCmd_ListsUnfinishedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	audio := filepath.Join(tmpDir, "memo.m4a")
+	os.WriteFile(audio, []byte("audio"), 0644)
+	store, _ := checkpoint.Open()
+	store.Save(&checkpoint.State{Source: audio, Stage: checkpoint.Transcribed, Updated: time.Now()})
+	// Gone since, so not worth listing
+	store.Save(&checkpoint.State{Source: filepath.Join(tmpDir, "deleted.m4a"), Stage: checkpoint.Stable, Updated: time.Now()})
+
+	var buf bytes.Buffer
+	cmd := newTranscribeStatusCmd()
+	cmd.SetOut(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "Unfinished:\n  memo.m4a: transcribed (") {
+		t.Errorf("expected memo.m4a listed as transcribed, got: %s", output)
+	}
+	if strings.Contains(output, "deleted.m4a") {
+		t.Errorf("expected deleted files left out, got: %s", output)
+	}
+}
+
+func TestTranscribeStatusCmd_VerboseShowsFailureCategories(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	store, _ := history.Open()
+	now := time.Now().UTC()
+	store.Append(history.Record{Time: now.Add(-time.Hour), Source: "/in/a.m4a", Status: history.StatusFailed, Category: history.CategoryAPI4xx})
+	store.Append(history.Record{Time: now.Add(-time.Hour), Source: "/in/b.m4a", Status: history.StatusFailed, Category: history.CategoryAPI4xx})
+	store.Append(history.Record{Time: now.Add(-time.Hour), Source: "/in/c.m4a", Status: history.StatusFailed, Category: history.CategoryWrite})
+
+	run := func(args ...string) string {
+		var buf bytes.Buffer
+		cmd := newTranscribeStatusCmd()
+		cmd.SetOut(&buf)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		return buf.String()
+	}
+
+	if output := run(); strings.Contains(output, history.CategoryAPI4xx) {
+		t.Errorf("expected no categories without --verbose, got: %s", output)
+	}
+
+	output := run("--verbose")
+	for _, want := range []string{"  api_4xx                2\n", "  write_failed           1\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+	if strings.Index(output, "api_4xx") > strings.Index(output, "write_failed") {
+		t.Errorf("expected the most frequent category first, got: %s", output)
+	}
+}
+
+func TestTranscribeStatsCmd_SummarizesMonth(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	store, _ := history.Open()
+	march := time.Date(2024, 3, 10, 12, 0, 0, 0, time.Local)
+	store.Append(history.Record{Time: march.AddDate(0, -1, 0), Source: "/in/feb.m4a", Status: history.StatusCompleted, ElapsedMs: 1000, AudioSeconds: 60, Words: 100})
+	store.Append(history.Record{Time: march, Source: "/in/a.m4a", Status: history.StatusCompleted, ElapsedMs: 20000, AudioSeconds: 120, Words: 250})
+	store.Append(history.Record{Time: march, Source: "/in/b.m4a", Status: history.StatusCompleted, ElapsedMs: 40000, AudioSeconds: 480, Words: 750})
+	store.Append(history.Record{Time: march, Source: "/in/c.m4a", Status: history.StatusFailed, Error: "dial tcp", Category: history.CategoryAPIUnreachable})
+	store.Append(history.Record{Time: march.AddDate(0, 1, 0), Source: "/in/apr.m4a", Status: history.StatusFailed, Category: history.CategoryTimeout})
+
+	var buf bytes.Buffer
+	cmd := newTranscribeStatsCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--month", "2024-03"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"Transcription usage for March 2024",
+		"2 transcribed, 1 failed (33.3% failure rate)",
+		"10.0 minutes",
+		"Words:           1000",
+		"Average latency: 30s per file",
+		"Speed:           10.0x realtime",
+		"api_unreachable",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "timeout") {
+		t.Errorf("expected April failures to be excluded, got:\n%s", output)
+	}
+}
+
+func TestCollectStats_Usage(t *testing.T) {
+	store := history.New(filepath.Join(t.TempDir(), "transcribe.jsonl"))
+	march := time.Date(2024, 3, 10, 12, 0, 0, 0, time.Local)
+	store.Append(history.Record{Time: march, Status: history.StatusCompleted, AudioSeconds: 600, Provider: "api.openai.com", Cost: 0.06})
+	store.Append(history.Record{Time: march, Status: history.StatusCompleted, AudioSeconds: 900, Provider: "api.openai.com", Cost: 0.09})
+	store.Append(history.Record{Time: march, Status: history.StatusCompleted, AudioSeconds: 300, Provider: "nas:9000"})
+
+	report, err := collectStats(store, time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local),
+		&transcribe.UsageConfig{CostPerMinute: 0.006, Currency: "USD", MonthlyBudget: 5})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if report.Providers["api.openai.com"].Files != 2 || report.Providers["nas:9000"].AudioSeconds != 300 {
+		t.Errorf("unexpected providers: %+v", report.Providers)
+	}
+
+	var buf bytes.Buffer
+	report.print(&buf)
+	for _, want := range []string{
+		"Cost:            0.15 USD of 5.00 USD budget (3.0%)",
+		"api.openai.com              2 files     25.0 minutes  0.15 USD",
+		"nas:9000                    1 files      5.0 minutes  0.00 USD",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestTranscribeStatsCmd_JSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	store, _ := history.Open()
+	march := time.Date(2024, 3, 10, 12, 0, 0, 0, time.Local)
+	store.Append(history.Record{Time: march, Source: "/in/a.m4a", Status: history.StatusCompleted, ElapsedMs: 2000, AudioSeconds: 60, Words: 120})
+	store.Append(history.Record{Time: march, Source: "/in/b.m4a", Status: history.StatusFailed, Category: history.CategoryWrite})
+
+	var buf bytes.Buffer
+	root := NewRootCmd()
+	root.SetOut(&buf)
+	root.SetArgs([]string{"transcribe", "stats", "--month", "2024-03", "--json"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	var got statsReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", buf.String(), err)
+	}
+	if got.Month != "2024-03" || got.Processed != 1 || got.Failed != 1 || got.AudioSeconds != 60 || got.Words != 120 {
+		t.Errorf("unexpected report: %+v", got)
+	}
+	if got.Failures[history.CategoryWrite] != 1 {
+		t.Errorf("expected one write failure, got: %v", got.Failures)
+	}
+}
+
+func TestTranscribeStatsCmd_EmptyMonth(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	var buf bytes.Buffer
+	cmd := newTranscribeStatsCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--month", "2024-03"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No files processed in March 2024") {
+		t.Errorf("expected empty month message, got: %s", buf.String())
+	}
+}
+
+func TestTranscribeStatsCmd_InvalidMonth(t *testing.T) {
+	cmd := newTranscribeStatsCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--month", "March"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "YYYY-MM") {
+		t.Errorf("expected invalid month error, got: %v", err)
+	}
+}
+
+func TestTranscribeStatusCmd_InvalidSince(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	var buf bytes.Buffer
+	cmd := newTranscribeStatusCmd()
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs([]string{"--since", "soon"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected error for invalid --since")
+	}
+}
+
+func TestTranscribeTestCmd_PrintsNote(t *testing.T) {
+	tmpHome := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpHome)
+	defer os.Setenv("HOME", originalHome)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"text":"Remember to buy milk","language":"en"}`))
+	}))
+	defer server.Close()
+
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	outputDir := filepath.Join(vaultRoot, "Inbox")
+	cfg := &transcribe.Config{
+		WatchDir:  vaultRoot,
+		APIURL:    server.URL,
+		OutputDir: outputDir,
+	}
+	if err := cfg.SaveToVault(vaultRoot); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	audioPath := filepath.Join(vaultRoot, "memo.m4a")
+	os.WriteFile(audioPath, []byte("fake audio"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	cmd := newTranscribeTestCmd()
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{audioPath})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if !strings.Contains(stdout.String(), "Remember to buy milk") {
+		t.Errorf("expected note to contain transcript, got: %s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "language: en") {
+		t.Errorf("expected summary on stderr, got: %s", stderr.String())
+	}
+
+	// Nothing is written or archived
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Error("expected output directory not to be created")
+	}
+	if _, err := os.Stat(audioPath); err != nil {
+		t.Error("expected audio file to remain in place")
+	}
+}
+
+func TestTranscribeImportCmd_ImportsDirectory(t *testing.T) {
+	tmpHome := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpHome)
+	defer os.Setenv("HOME", originalHome)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"text":"Old recording","language":"en"}`))
+	}))
+	defer server.Close()
+
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	outputDir := filepath.Join(vaultRoot, "Inbox")
+	cfg := &transcribe.Config{
+		WatchDir:  vaultRoot,
+		APIURL:    server.URL,
+		OutputDir: outputDir,
+	}
+	if err := cfg.SaveToVault(vaultRoot); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	importDir := t.TempDir()
+	os.MkdirAll(filepath.Join(importDir, "2024"), 0755)
+	os.WriteFile(filepath.Join(importDir, "a.m4a"), []byte("audio"), 0644)
+	os.WriteFile(filepath.Join(importDir, "2024", "b.m4a"), []byte("audio"), 0644)
+	os.WriteFile(filepath.Join(importDir, "readme.txt"), []byte("text"), 0644)
+
+	var out bytes.Buffer
+	cmd := newTranscribeImportCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{importDir, "--keep"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v\n%s", err, out.String())
+	}
+
+	if !strings.Contains(out.String(), "Imported 2 of 2 files") {
+		t.Errorf("expected summary, got: %s", out.String())
+	}
+
+	notes, _ := filepath.Glob(filepath.Join(outputDir, "*.md"))
+	if len(notes) != 2 {
+		t.Errorf("expected 2 notes, got: %v", notes)
+	}
+	if _, err := os.Stat(filepath.Join(importDir, "2024", "b.m4a")); err != nil {
+		t.Error("expected original to be kept with --keep")
+	}
+}
+
+func TestTranscribeFileCmd(t *testing.T) {
+	tmpHome := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpHome)
+	defer os.Setenv("HOME", originalHome)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"text":"Remember to buy milk","language":"en"}`))
+	}))
+	defer server.Close()
+
+	vaultRoot := setupTestVault(t)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(vaultRoot)
+
+	outputDir := filepath.Join(vaultRoot, "Inbox")
+	cfg := &transcribe.Config{
+		WatchDir:  vaultRoot,
+		APIURL:    server.URL,
+		OutputDir: outputDir,
+	}
+	if err := cfg.SaveToVault(vaultRoot); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	audioPath := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(audioPath, []byte("fake audio"), 0644)
+
+	// --stdout prints only the transcript and writes nothing
+	var stdout bytes.Buffer
+	cmd := newTranscribeFileCmd()
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{audioPath, "--stdout"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if stdout.String() != "Remember to buy milk\n" {
+		t.Errorf("expected only the transcript, got: %q", stdout.String())
+	}
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Error("expected output directory not to be created")
+	}
+
+	// Without it the note is written
+	stdout.Reset()
+	cmd = newTranscribeFileCmd()
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{audioPath, "--keep"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Transcribed memo.m4a") {
+		t.Errorf("expected summary, got: %s", stdout.String())
+	}
+	if notes, _ := filepath.Glob(filepath.Join(outputDir, "*.md")); len(notes) != 1 {
+		t.Errorf("expected 1 note, got: %v", notes)
+	}
+	if _, err := os.Stat(audioPath); err != nil {
+		t.Error("expected audio file to remain in place with --keep")
+	}
+}
+
+func TestTranscribeStatusCmd_JSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	store, _ := history.Open()
+	now := time.Now().UTC()
+	store.Append(history.Record{Time: now, Source: "/sync/phone/a.m4a", Status: history.StatusCompleted, ElapsedMs: 2000})
+	store.Append(history.Record{Time: now, Source: "/sync/phone/b.m4a", Status: history.StatusFailed, Error: "boom"})
+
+	var buf bytes.Buffer
+	root := NewRootCmd()
+	root.SetOut(&buf)
+	root.SetArgs([]string{"transcribe", "status", "--since", "7d", "--json"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	var got struct {
+		Running bool `json:"running"`
+		History struct {
+			Since  string `json:"since"`
+			Window struct {
+				Processed             int     `json:"processed"`
+				Failed                int     `json:"failed"`
+				FailureRate           float64 `json:"failure_rate"`
+				AverageElapsedSeconds float64 `json:"average_elapsed_seconds"`
+			} `json:"window"`
+		} `json:"history"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", buf.String(), err)
+	}
+	if got.Running {
+		t.Error("expected running to be false")
+	}
+	w := got.History.Window
+	if got.History.Since != "7d" || w.Processed != 1 || w.Failed != 1 || w.FailureRate != 0.5 || w.AverageElapsedSeconds != 2 {
+		t.Errorf("unexpected history: %+v", got.History)
+	}
+}
+
+func TestPrintWatchDirs_PerDirectoryTotals(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	store, _ := history.Open()
+	now := time.Now().UTC()
+	store.Append(history.Record{Time: now, Source: "/sync/phone/a.m4a", Status: history.StatusCompleted})
+	store.Append(history.Record{Time: now, Source: "/sync/phone/b.m4a", Status: history.StatusCompleted})
+	store.Append(history.Record{Time: now, Source: "/sync/tablet/c.wav", Status: history.StatusFailed, Error: "boom"})
+	store.Append(history.Record{Time: now.Add(-48 * time.Hour), Source: "/sync/tablet/old.wav", Status: history.StatusCompleted})
+
+	var buf bytes.Buffer
+	printWatchDirs(&buf, collectWatchDirs([]transcribe.WatchDirConfig{
+		{Path: "/sync/phone", Patterns: []string{"*.m4a"}},
+		{Path: "/sync/tablet", Patterns: []string{"*.wav", "*.mp3"}},
+	}))
+
+	expected := "Watching:\n" +
+		"  /sync/phone (*.m4a): 2 processed, 0 failed today\n" +
+		"  /sync/tablet (*.wav, *.mp3): 0 processed, 1 failed today\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestCollectWatchDirs_SymlinkedDirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	realDir := filepath.Join(tmpDir, "sync", "phone")
+	os.MkdirAll(realDir, 0755)
+	linked := filepath.Join(tmpDir, "phone")
+	if err := os.Symlink(realDir, linked); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	store, _ := history.Open()
+	now := time.Now().UTC()
+	store.Append(history.Record{Time: now, Source: filepath.Join(realDir, "a.m4a"), Status: history.StatusCompleted})
+	store.Append(history.Record{Time: now, Source: filepath.Join(linked, "b.m4a"), Status: history.StatusCompleted})
+
+	reports := collectWatchDirs([]transcribe.WatchDirConfig{{Path: linked + "/"}})
+	if len(reports) != 1 || reports[0].ProcessedToday != 2 {
+		t.Errorf("expected both files counted for the linked directory, got %+v", reports)
+	}
+}
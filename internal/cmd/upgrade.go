@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/TechnicallyShaun/nota-orbis/internal/release"
+	"github.com/spf13/cobra"
+)
+
+// executablePath returns the path of the running binary; tests replace it.
+var executablePath = func() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exe)
+}
+
+// NewUpgradeCmd creates the upgrade command
+func NewUpgradeCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Update nota to the latest release",
+		Long: `Downloads the latest GitHub release for this platform, verifies it against
+the release's published SHA-256 checksums and atomically replaces the running
+executable, whatever it is named.
+
+The checksums only show that the download is intact: they come from the same
+release and are not signed, so they do not prove who published it.
+
+Development builds and up-to-date installs are left alone unless --force is
+given. A running transcription daemon keeps the old version until restarted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			ctx := cmd.Context()
+			client := newReleaseClient()
+
+			rel, err := client.Latest(ctx)
+			if err != nil {
+				return fmt.Errorf("check for updates: %w", err)
+			}
+
+			newer, ok := release.IsNewer(Version, rel.Tag)
+			if !force {
+				if !ok {
+					return fmt.Errorf("cannot compare %s build with release %s; use --force to install it", Version, rel.Tag)
+				}
+				if !newer {
+					fmt.Fprintf(out, "Already running the latest release (%s)\n", rel.Tag)
+					return nil
+				}
+			}
+
+			archive, err := rel.ArchiveFor(runtime.GOOS, runtime.GOARCH)
+			if err != nil {
+				return err
+			}
+			checksums, err := rel.Asset(release.ChecksumsAsset)
+			if err != nil {
+				return fmt.Errorf("cannot verify download: %w", err)
+			}
+
+			exe, err := executablePath()
+			if err != nil {
+				return fmt.Errorf("locate executable: %w", err)
+			}
+
+			fmt.Fprintf(out, "Downloading %s...\n", archive.Name)
+			sums, err := client.Download(ctx, checksums)
+			if err != nil {
+				return err
+			}
+			data, err := client.Download(ctx, archive)
+			if err != nil {
+				return err
+			}
+			if err := release.VerifyChecksum(sums, archive.Name, data); err != nil {
+				return err
+			}
+
+			binary, err := release.ExtractBinary(archive.Name, data, release.BinaryFor(runtime.GOOS))
+			if err != nil {
+				return err
+			}
+			if err := release.ReplaceExecutable(exe, binary); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "Upgraded %s from %s to %s\n", exe, Version, rel.Tag)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Install the latest release even if it is not newer")
+
+	return cmd
+}
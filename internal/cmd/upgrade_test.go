@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/internal/release"
+)
+
+// withUpgradeServer serves release tag with a nota binary of the given
+// contents, and points executablePath at a temporary "nota" file.
+func withUpgradeServer(t *testing.T, tag, binary string, corrupt bool) string {
+	return withUpgradeServerAt(t, "nota", tag, binary, corrupt)
+}
+
+// withUpgradeServerAt is withUpgradeServer with the executable named name.
+func withUpgradeServerAt(t *testing.T, name, tag, binary string, corrupt bool) string {
+	t.Helper()
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: release.BinaryFor(runtime.GOOS), Mode: 0755, Size: int64(len(binary)), Typeflag: tar.TypeReg})
+	tw.Write([]byte(binary))
+	tw.Close()
+	gz.Close()
+
+	archiveName := fmt.Sprintf("nota-orbis_%s_%s_%s.tar.gz", strings.TrimPrefix(tag, "v"), runtime.GOOS, runtime.GOARCH)
+	sum := sha256.Sum256(archive.Bytes())
+	if corrupt {
+		sum[0] ^= 0xff
+	}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc("/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release.Release{Tag: tag, Assets: []release.Asset{
+			{Name: "checksums.txt", URL: server.URL + "/checksums.txt"},
+			{Name: archiveName, URL: server.URL + "/archive"},
+		}})
+	})
+	mux.HandleFunc("/checksums.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s  %s\n", hex.EncodeToString(sum[:]), archiveName)
+	})
+	mux.HandleFunc("/archive", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive.Bytes())
+	})
+
+	exe := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(exe, []byte("old binary"), 0755); err != nil {
+		t.Fatalf("write executable: %v", err)
+	}
+
+	originalClient, originalExe := newReleaseClient, executablePath
+	newReleaseClient = func() *release.Client {
+		c := release.NewClient()
+		c.URL = server.URL + "/latest"
+		return c
+	}
+	executablePath = func() (string, error) { return exe, nil }
+	t.Cleanup(func() {
+		newReleaseClient, executablePath = originalClient, originalExe
+		server.Close()
+	})
+	return exe
+}
+
+func runUpgrade(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	cmd := NewUpgradeCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestUpgrade_ReplacesExecutable(t *testing.T) {
+	originalVersion := Version
+	defer func() { Version = originalVersion }()
+	Version = "1.2.3"
+	exe := withUpgradeServer(t, "v1.3.0", "new binary", false)
+
+	output, err := runUpgrade(t)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(output, "from 1.2.3 to v1.3.0") {
+		t.Errorf("expected upgrade message, got: %q", output)
+	}
+	data, _ := os.ReadFile(exe)
+	if string(data) != "new binary" {
+		t.Errorf("expected executable to be replaced, got: %q", data)
+	}
+}
+
+func TestUpgrade_RenamedExecutable(t *testing.T) {
+	originalVersion := Version
+	defer func() { Version = originalVersion }()
+	Version = "1.2.3"
+	exe := withUpgradeServerAt(t, "nota-1.2.3", "v1.3.0", "new binary", false)
+
+	if _, err := runUpgrade(t); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	data, _ := os.ReadFile(exe)
+	if string(data) != "new binary" {
+		t.Errorf("expected executable to be replaced, got: %q", data)
+	}
+}
+
+func TestUpgrade_ChecksumMismatchKeepsExecutable(t *testing.T) {
+	originalVersion := Version
+	defer func() { Version = originalVersion }()
+	Version = "1.2.3"
+	exe := withUpgradeServer(t, "v1.3.0", "new binary", true)
+
+	if _, err := runUpgrade(t); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got: %v", err)
+	}
+	data, _ := os.ReadFile(exe)
+	if string(data) != "old binary" {
+		t.Errorf("expected executable to be untouched, got: %q", data)
+	}
+}
+
+func TestUpgrade_AlreadyLatest(t *testing.T) {
+	originalVersion := Version
+	defer func() { Version = originalVersion }()
+	Version = "1.3.0"
+	exe := withUpgradeServer(t, "v1.3.0", "new binary", false)
+
+	output, err := runUpgrade(t)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(output, "Already running the latest release") {
+		t.Errorf("expected up-to-date message, got: %q", output)
+	}
+	data, _ := os.ReadFile(exe)
+	if string(data) != "old binary" {
+		t.Errorf("expected executable to be untouched, got: %q", data)
+	}
+}
+
+func TestUpgrade_DevBuildNeedsForce(t *testing.T) {
+	originalVersion := Version
+	defer func() { Version = originalVersion }()
+	Version = "dev"
+	exe := withUpgradeServer(t, "v1.3.0", "new binary", false)
+
+	if _, err := runUpgrade(t); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected error suggesting --force, got: %v", err)
+	}
+
+	if _, err := runUpgrade(t, "--force"); err != nil {
+		t.Fatalf("expected no error with --force, got: %v", err)
+	}
+	data, _ := os.ReadFile(exe)
+	if string(data) != "new binary" {
+		t.Errorf("expected executable to be replaced, got: %q", data)
+	}
+}
@@ -2,24 +2,108 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"runtime"
 
+	"github.com/TechnicallyShaun/nota-orbis/internal/release"
 	"github.com/spf13/cobra"
 )
 
-// Version and Commit are set at build time via ldflags
+// Version, Commit and BuildDate are set at build time via ldflags
 var (
-	Version = "dev"
-	Commit  = "unknown"
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
 )
 
+// newReleaseClient creates the client used by --check; tests replace it.
+var newReleaseClient = release.NewClient
+
+// versionJSON is the --json output of the version command.
+type versionJSON struct {
+	Version   string             `json:"version"`
+	Commit    string             `json:"commit"`
+	BuildDate string             `json:"build_date"`
+	GoVersion string             `json:"go_version"`
+	Platform  string             `json:"platform"`
+	Latest    *latestReleaseJSON `json:"latest,omitempty"`
+}
+
+// latestReleaseJSON describes the latest published release for --check.
+type latestReleaseJSON struct {
+	Version string `json:"version"`
+	URL     string `json:"url"`
+	// UpdateAvailable is nil when the running version cannot be compared,
+	// such as for development builds.
+	UpdateAvailable *bool `json:"update_available"`
+}
+
 // NewVersionCmd creates the version command
 func NewVersionCmd() *cobra.Command {
-	return &cobra.Command{
+	var check bool
+
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print version information",
-		Long:  "Print the version and commit hash of the nota CLI",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Fprintf(cmd.OutOrStdout(), "nota version %s (commit: %s)\n", Version, Commit)
+		Long: `Print the version, commit hash, build date, Go version and platform of the nota CLI.
+
+With --check, the GitHub releases API is queried to report whether a newer
+release exists. Nothing is downloaded.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := versionJSON{
+				Version:   Version,
+				Commit:    Commit,
+				BuildDate: BuildDate,
+				GoVersion: runtime.Version(),
+				Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+			}
+
+			if check {
+				rel, err := newReleaseClient().Latest(cmd.Context())
+				if err != nil {
+					return fmt.Errorf("check for updates: %w", err)
+				}
+				info.Latest = &latestReleaseJSON{Version: rel.Tag, URL: rel.URL}
+				if newer, ok := release.IsNewer(Version, rel.Tag); ok {
+					info.Latest.UpdateAvailable = &newer
+				}
+			}
+
+			if JSONOutput(cmd) {
+				return writeJSON(cmd.OutOrStdout(), info)
+			}
+			printVersion(cmd.OutOrStdout(), info)
+			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "Check GitHub for a newer release")
+
+	return cmd
+}
+
+// printVersion writes the text form of the version command.
+func printVersion(out io.Writer, info versionJSON) {
+	fmt.Fprintf(out, "nota version %s (commit: %s)\n", info.Version, info.Commit)
+	fmt.Fprintf(out, "Built:    %s\n", info.BuildDate)
+	fmt.Fprintf(out, "Go:       %s\n", info.GoVersion)
+	fmt.Fprintf(out, "Platform: %s\n", info.Platform)
+
+	latest := info.Latest
+	if latest == nil {
+		return
+	}
+	fmt.Fprintln(out)
+	switch {
+	case latest.UpdateAvailable == nil:
+		fmt.Fprintf(out, "Latest release is %s; cannot compare with this %s build.\n", latest.Version, info.Version)
+	case *latest.UpdateAvailable:
+		fmt.Fprintf(out, "A newer release is available: %s\n", latest.Version)
+	default:
+		fmt.Fprintf(out, "You are running the latest release (%s).\n", latest.Version)
+		return
+	}
+	if latest.URL != "" {
+		fmt.Fprintf(out, "  %s\n", latest.URL)
+	}
 }
@@ -2,8 +2,15 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/internal/release"
 )
 
 func TestVersion_OutputsVersionString(t *testing.T) {
@@ -55,3 +62,131 @@ func TestVersion_IncludesCommitHash(t *testing.T) {
 		t.Errorf("expected output to contain 'commit:', got: %q", output)
 	}
 }
+
+func TestVersion_JSON(t *testing.T) {
+	originalVersion, originalCommit := Version, Commit
+	defer func() { Version, Commit = originalVersion, originalCommit }()
+	Version, Commit = "1.2.3", "abc123def"
+
+	var buf bytes.Buffer
+	root := NewRootCmd()
+	root.SetOut(&buf)
+	root.SetArgs([]string{"version", "--json"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	var got versionJSON
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", buf.String(), err)
+	}
+	if got.Version != "1.2.3" || got.Commit != "abc123def" {
+		t.Errorf("unexpected output: %+v", got)
+	}
+}
+
+func TestVersion_IncludesBuildMetadata(t *testing.T) {
+	originalDate := BuildDate
+	defer func() { BuildDate = originalDate }()
+	BuildDate = "2026-01-22T10:00:00Z"
+
+	var buf bytes.Buffer
+	cmd := NewVersionCmd()
+	cmd.SetOut(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"2026-01-22T10:00:00Z", runtime.Version(), runtime.GOOS + "/" + runtime.GOARCH} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got: %q", want, output)
+		}
+	}
+}
+
+// withReleaseServer points --check at a server reporting tag as the latest release.
+func withReleaseServer(t *testing.T, tag string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"tag_name": %q, "html_url": "https://example.com/releases/%s"}`, tag, tag)
+	}))
+	original := newReleaseClient
+	newReleaseClient = func() *release.Client {
+		c := release.NewClient()
+		c.URL = server.URL
+		return c
+	}
+	t.Cleanup(func() {
+		newReleaseClient = original
+		server.Close()
+	})
+}
+
+func TestVersion_CheckReportsNewerRelease(t *testing.T) {
+	originalVersion := Version
+	defer func() { Version = originalVersion }()
+	Version = "1.2.3"
+	withReleaseServer(t, "v1.3.0")
+
+	var buf bytes.Buffer
+	cmd := NewVersionCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--check"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "A newer release is available: v1.3.0") {
+		t.Errorf("expected newer release message, got: %q", output)
+	}
+	if !strings.Contains(output, "https://example.com/releases/v1.3.0") {
+		t.Errorf("expected release URL, got: %q", output)
+	}
+}
+
+func TestVersion_CheckUpToDate(t *testing.T) {
+	originalVersion := Version
+	defer func() { Version = originalVersion }()
+	Version = "1.3.0"
+	withReleaseServer(t, "v1.3.0")
+
+	var buf bytes.Buffer
+	cmd := NewVersionCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--check"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "You are running the latest release (v1.3.0)") {
+		t.Errorf("expected up-to-date message, got: %q", buf.String())
+	}
+}
+
+func TestVersion_CheckJSON(t *testing.T) {
+	originalVersion := Version
+	defer func() { Version = originalVersion }()
+	Version = "dev"
+	withReleaseServer(t, "v1.3.0")
+
+	var buf bytes.Buffer
+	root := NewRootCmd()
+	root.SetOut(&buf)
+	root.SetArgs([]string{"version", "--check", "--json"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	var got versionJSON
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", buf.String(), err)
+	}
+	if got.Latest == nil || got.Latest.Version != "v1.3.0" {
+		t.Fatalf("expected latest release v1.3.0, got: %+v", got.Latest)
+	}
+	if got.Latest.UpdateAvailable != nil {
+		t.Errorf("expected no comparison for a dev build, got: %v", *got.Latest.UpdateAvailable)
+	}
+}
@@ -0,0 +1,51 @@
+// Package golden compares generated files in tests with golden copies kept
+// in the package's testdata/golden directory, so changes to their layout
+// show as readable diffs in review. After an intended change, rewrite the
+// golden files from the current output with the -update flag and commit
+// them with the change. Only packages with golden tests know the flag:
+//
+//	go test ./pkg/transcribe/writer ./pkg/transcribe/output -update
+package golden
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/conflicts"
+)
+
+// Dir is the directory, relative to the package under test, holding the
+// golden files.
+var Dir = filepath.Join("testdata", "golden")
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata/golden with the current output")
+
+// Assert fails the test with a unified diff when got differs from the
+// golden file name, or writes got to it when the tests run with -update.
+func Assert(t testing.TB, name, got string) {
+	t.Helper()
+	path := filepath.Join(Dir, name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if got != string(want) {
+		diff := conflicts.Diff(path, "got", string(want), got)
+		if diff == "" {
+			diff = "line endings or final newline differ\n"
+		}
+		t.Errorf("output differs from %s (run with -update to accept it):\n%s", path, diff)
+	}
+}
@@ -0,0 +1,112 @@
+// Package release looks up published nota releases on GitHub.
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LatestURL is the GitHub API endpoint describing the latest release.
+const LatestURL = "https://api.github.com/repos/TechnicallyShaun/nota-orbis/releases/latest"
+
+// DefaultTimeout bounds a release lookup.
+const DefaultTimeout = 10 * time.Second
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Release is a published release.
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	URL    string  `json:"html_url"`
+	Assets []Asset `json:"assets"`
+}
+
+// Client queries the releases API.
+type Client struct {
+	// URL is the latest-release endpoint; LatestURL unless overridden.
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for the nota repository.
+func NewClient() *Client {
+	return &Client{
+		URL:        LatestURL,
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// Latest returns the latest published release.
+func (c *Client) Latest(ctx context.Context) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("query releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("query releases: status %d", resp.StatusCode)
+	}
+
+	var rel Release
+	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
+		return nil, fmt.Errorf("decode release: %w", err)
+	}
+	if rel.Tag == "" {
+		return nil, fmt.Errorf("decode release: no tag_name")
+	}
+	return &rel, nil
+}
+
+// IsNewer reports whether latest is a later version than current. ok is
+// false when either is not a version such as "1.2.3" or "v1.2.3", e.g. for
+// development builds.
+func IsNewer(current, latest string) (newer bool, ok bool) {
+	c, okC := parseVersion(current)
+	l, okL := parseVersion(latest)
+	if !okC || !okL {
+		return false, false
+	}
+	for i := range c {
+		if l[i] != c[i] {
+			return l[i] > c[i], true
+		}
+	}
+	return false, true
+}
+
+// parseVersion parses "v1.2.3", ignoring any pre-release or build suffix.
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
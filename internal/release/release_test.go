@@ -0,0 +1,61 @@
+package release
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		newer, ok       bool
+	}{
+		{"1.2.3", "v1.2.4", true, true},
+		{"v1.2.3", "v1.2.3", false, true},
+		{"1.10.0", "v1.9.9", false, true},
+		{"1.2.3", "v2.0.0", true, true},
+		{"1.2.3-rc1", "v1.2.3", false, true},
+		{"dev", "v1.2.3", false, false},
+		{"1.2.3", "nightly", false, false},
+	}
+
+	for _, tt := range tests {
+		newer, ok := IsNewer(tt.current, tt.latest)
+		if newer != tt.newer || ok != tt.ok {
+			t.Errorf("IsNewer(%q, %q): expected %v/%v, got %v/%v", tt.current, tt.latest, tt.newer, tt.ok, newer, ok)
+		}
+	}
+}
+
+func TestClient_Latest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name": "v1.4.0", "html_url": "https://example.com/v1.4.0",
+			"assets": [{"name": "checksums.txt", "browser_download_url": "https://example.com/checksums.txt"}]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.URL = server.URL
+	rel, err := c.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if rel.Tag != "v1.4.0" || rel.URL != "https://example.com/v1.4.0" || len(rel.Assets) != 1 {
+		t.Errorf("unexpected release: %+v", rel)
+	}
+}
+
+func TestClient_LatestHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.URL = server.URL
+	if _, err := c.Latest(context.Background()); err == nil {
+		t.Error("expected error for non-200 response")
+	}
+}
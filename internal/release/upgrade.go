@@ -0,0 +1,209 @@
+package release
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ChecksumsAsset is the release asset listing the SHA-256 of every archive.
+const ChecksumsAsset = "checksums.txt"
+
+// BinaryName is the name of the executable in release archives, as set by
+// the goreleaser build.
+const BinaryName = "nota"
+
+// maxDownloadSize bounds a downloaded asset.
+const maxDownloadSize = 200 << 20
+
+var (
+	// ErrNoAsset is returned when a release has no archive for the platform.
+	ErrNoAsset = errors.New("no release asset for this platform")
+	// ErrChecksumMismatch is returned when a download does not match the
+	// published checksum.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// ArchiveFor returns the release archive built for goos and goarch, named
+// like "nota-orbis_1.2.3_linux_amd64.tar.gz".
+func (r *Release) ArchiveFor(goos, goarch string) (*Asset, error) {
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	suffix := "_" + goos + "_" + goarch + ext
+	for i := range r.Assets {
+		if strings.HasSuffix(r.Assets[i].Name, suffix) {
+			return &r.Assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s/%s in %s", ErrNoAsset, goos, goarch, r.Tag)
+}
+
+// Asset returns the release asset with the given name.
+func (r *Release) Asset(name string) (*Asset, error) {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s in %s", ErrNoAsset, name, r.Tag)
+}
+
+// Download fetches an asset's contents.
+func (c *Client) Download(ctx context.Context, asset *Asset) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, asset.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+
+	// Downloads can take longer than an API call, so only ctx bounds them.
+	client := *c.HTTPClient
+	client.Timeout = 0
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: status %d", asset.Name, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	if len(data) > maxDownloadSize {
+		return nil, fmt.Errorf("download %s: larger than %d MB", asset.Name, maxDownloadSize>>20)
+	}
+	return data, nil
+}
+
+// VerifyChecksum checks data against name's entry in a checksums file of
+// "<sha256>  <name>" lines.
+func VerifyChecksum(checksums []byte, name string, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, name)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s not listed in %s", ErrChecksumMismatch, name, ChecksumsAsset)
+}
+
+// BinaryFor returns the name of the executable in the release archive for
+// goos.
+func BinaryFor(goos string) string {
+	if goos == "windows" {
+		return BinaryName + ".exe"
+	}
+	return BinaryName
+}
+
+// ExtractBinary returns the file called binary from a .tar.gz or .zip
+// release archive.
+func ExtractBinary(archiveName string, data []byte, binary string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		return extractZip(data, binary)
+	}
+	return extractTarGz(data, binary)
+}
+
+func extractTarGz(data []byte, binary string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == binary {
+			return io.ReadAll(io.LimitReader(tr, maxDownloadSize))
+		}
+	}
+	return nil, fmt.Errorf("archive does not contain %s", binary)
+}
+
+func extractZip(data []byte, binary string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || path.Base(f.Name) != binary {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+		defer rc.Close()
+		return io.ReadAll(io.LimitReader(rc, maxDownloadSize))
+	}
+	return nil, fmt.Errorf("archive does not contain %s", binary)
+}
+
+// ReplaceExecutable atomically replaces the file at exe with data, keeping
+// its permissions. The new binary is written next to exe and renamed over
+// it, so a running process keeps its old image and exe is never partial.
+func ReplaceExecutable(exe string, data []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return fmt.Errorf("stat executable: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(exe), "."+filepath.Base(exe)+".upgrade-*")
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write new executable: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write new executable: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write new executable: %w", err)
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("set mode on new executable: %w", err)
+	}
+	if err := os.Rename(tmpPath, exe); err != nil {
+		return fmt.Errorf("replace executable: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package release
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tarGz builds a release archive holding files.
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func checksumLine(name string, data []byte) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), name)
+}
+
+func TestRelease_ArchiveFor(t *testing.T) {
+	rel := &Release{Tag: "v1.3.0", Assets: []Asset{
+		{Name: "checksums.txt"},
+		{Name: "nota-orbis_1.3.0_linux_amd64.tar.gz"},
+		{Name: "nota-orbis_1.3.0_linux_arm64.tar.gz"},
+		{Name: "nota-orbis_1.3.0_windows_amd64.zip"},
+	}}
+
+	asset, err := rel.ArchiveFor("linux", "arm64")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if asset.Name != "nota-orbis_1.3.0_linux_arm64.tar.gz" {
+		t.Errorf("unexpected asset: %s", asset.Name)
+	}
+
+	asset, err = rel.ArchiveFor("windows", "amd64")
+	if err != nil || asset.Name != "nota-orbis_1.3.0_windows_amd64.zip" {
+		t.Errorf("expected windows zip, got: %v, %v", asset, err)
+	}
+
+	if _, err := rel.ArchiveFor("darwin", "arm64"); !errors.Is(err, ErrNoAsset) {
+		t.Errorf("expected ErrNoAsset, got: %v", err)
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("archive contents")
+	sums := []byte(checksumLine("other.tar.gz", []byte("x")) + checksumLine("nota.tar.gz", data))
+
+	if err := VerifyChecksum(sums, "nota.tar.gz", data); err != nil {
+		t.Errorf("expected checksum to match, got: %v", err)
+	}
+	if err := VerifyChecksum(sums, "nota.tar.gz", []byte("tampered")); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("expected ErrChecksumMismatch for tampered data, got: %v", err)
+	}
+	if err := VerifyChecksum(sums, "missing.tar.gz", data); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("expected ErrChecksumMismatch for unlisted file, got: %v", err)
+	}
+}
+
+func TestExtractBinary(t *testing.T) {
+	archive := tarGz(t, map[string]string{"README.md": "readme", "nota": "new binary"})
+	got, err := ExtractBinary("nota_linux_amd64.tar.gz", archive, BinaryFor("linux"))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if string(got) != "new binary" {
+		t.Errorf("expected binary contents, got: %q", got)
+	}
+
+	if _, err := ExtractBinary("nota_linux_amd64.tar.gz", archive, "missing"); err == nil {
+		t.Error("expected error for missing binary")
+	}
+}
+
+func TestExtractBinary_Zip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("nota.exe")
+	w.Write([]byte("windows binary"))
+	zw.Close()
+
+	got, err := ExtractBinary("nota_windows_amd64.zip", buf.Bytes(), BinaryFor("windows"))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if string(got) != "windows binary" {
+		t.Errorf("expected binary contents, got: %q", got)
+	}
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "nota")
+	if err := os.WriteFile(exe, []byte("old"), 0750); err != nil {
+		t.Fatalf("write executable: %v", err)
+	}
+
+	if err := ReplaceExecutable(exe, []byte("new")); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	data, _ := os.ReadFile(exe)
+	if string(data) != "new" {
+		t.Errorf("expected new contents, got: %q", data)
+	}
+	info, _ := os.Stat(exe)
+	if info.Mode().Perm() != 0750 {
+		t.Errorf("expected mode 0750 to be kept, got: %o", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected no temporary files left, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,39 @@
+// Package dirs locates nota's per-user directories outside the vault. They
+// follow the XDG base directory variables when set and otherwise fall back
+// to ~/.nota, so existing installs keep their files where they are.
+package dirs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appName is the subdirectory nota uses under each XDG base directory.
+const appName = "nota"
+
+// State returns the directory for logs, PID and state files and history:
+// $XDG_STATE_HOME/nota, or ~/.nota.
+func State() (string, error) {
+	return resolve("XDG_STATE_HOME")
+}
+
+// Config returns the directory for user-level configuration:
+// $XDG_CONFIG_HOME/nota, or ~/.nota. Vault settings stay in the vault's
+// own .nota directory.
+func Config() (string, error) {
+	return resolve("XDG_CONFIG_HOME")
+}
+
+// resolve returns $env/nota when env holds an absolute path, as the XDG
+// specification requires, and ~/.nota otherwise.
+func resolve(env string) (string, error) {
+	if base := os.Getenv(env); filepath.IsAbs(base) {
+		return filepath.Join(base, appName), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".nota"), nil
+}
@@ -0,0 +1,60 @@
+package dirs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestState(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome, originalState := os.Getenv("HOME"), os.Getenv("XDG_STATE_HOME")
+	defer func() {
+		os.Setenv("HOME", originalHome)
+		os.Setenv("XDG_STATE_HOME", originalState)
+	}()
+	os.Setenv("HOME", tmpDir)
+
+	tests := []struct {
+		name     string
+		xdg      string
+		expected string
+	}{
+		{"unset falls back to ~/.nota", "", filepath.Join(tmpDir, ".nota")},
+		{"absolute XDG_STATE_HOME", "/var/lib/user/state", "/var/lib/user/state/nota"},
+		{"relative XDG_STATE_HOME is ignored", "state", filepath.Join(tmpDir, ".nota")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("XDG_STATE_HOME", tt.xdg)
+			got, err := State()
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %s, got: %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome, originalConfig := os.Getenv("HOME"), os.Getenv("XDG_CONFIG_HOME")
+	defer func() {
+		os.Setenv("HOME", originalHome)
+		os.Setenv("XDG_CONFIG_HOME", originalConfig)
+	}()
+	os.Setenv("HOME", tmpDir)
+
+	os.Setenv("XDG_CONFIG_HOME", "")
+	if got, _ := Config(); got != filepath.Join(tmpDir, ".nota") {
+		t.Errorf("expected fallback to ~/.nota, got: %s", got)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	if got, _ := Config(); got != filepath.Join(tmpDir, "config", "nota") {
+		t.Errorf("expected XDG_CONFIG_HOME/nota, got: %s", got)
+	}
+}
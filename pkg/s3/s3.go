@@ -0,0 +1,209 @@
+// Package s3 is a minimal client for Amazon S3 and S3-compatible object
+// stores such as MinIO, signing requests with AWS Signature Version 4.
+// Buckets are addressed path-style (endpoint/bucket/key).
+package s3
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultRegion is used when no region is configured.
+const DefaultRegion = "us-east-1"
+
+// Client talks to one S3 endpoint.
+type Client struct {
+	endpoint *url.URL
+	// Region is the signing region.
+	Region string
+	// AccessKeyID and SecretAccessKey sign requests; when both are empty
+	// requests are sent unsigned, for public buckets.
+	AccessKeyID     string
+	SecretAccessKey string
+	HTTPClient      *http.Client
+
+	now func() time.Time
+}
+
+// New creates a client for endpoint, e.g. https://s3.eu-west-1.amazonaws.com
+// or http://minio.lan:9000. An empty endpoint selects AWS for the region.
+func New(endpoint, region, accessKeyID, secretAccessKey string) (*Client, error) {
+	if region == "" {
+		region = DefaultRegion
+	}
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	u, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	return &Client{
+		endpoint:        u,
+		Region:          region,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		HTTPClient:      &http.Client{Timeout: 5 * time.Minute},
+		now:             time.Now,
+	}, nil
+}
+
+// Object describes a stored object.
+type Object struct {
+	Key          string    `xml:"Key"`
+	Size         int64     `xml:"Size"`
+	ETag         string    `xml:"ETag"`
+	LastModified time.Time `xml:"LastModified"`
+}
+
+// listResult is a ListObjectsV2 response.
+type listResult struct {
+	Contents              []Object `xml:"Contents"`
+	IsTruncated           bool     `xml:"IsTruncated"`
+	NextContinuationToken string   `xml:"NextContinuationToken"`
+}
+
+// List returns every object in bucket whose key starts with prefix.
+func (c *Client) List(ctx context.Context, bucket, prefix string) ([]Object, error) {
+	var objects []Object
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := c.do(ctx, http.MethodGet, bucket, "", query)
+		if err != nil {
+			return nil, err
+		}
+		var result listResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decode object list: %w", err)
+		}
+
+		objects = append(objects, result.Contents...)
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// Get writes the contents of an object to w.
+func (c *Client) Get(ctx context.Context, bucket, key string, w io.Writer) error {
+	resp, err := c.do(ctx, http.MethodGet, bucket, key, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("download %s: %w", key, err)
+	}
+	return nil
+}
+
+// Put uploads the contents of body as an object. body is read twice, once
+// to hash it for the signature and once to send it.
+func (c *Client) Put(ctx context.Context, bucket, key string, body io.ReadSeeker) error {
+	h := sha256.New()
+	size, err := io.Copy(h, body)
+	if err != nil {
+		return fmt.Errorf("upload %s: %w", key, err)
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("upload %s: %w", key, err)
+	}
+	resp, err := c.send(ctx, http.MethodPut, bucket, key, nil, io.NopCloser(body), size, hex.EncodeToString(h.Sum(nil)))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Delete removes an object.
+func (c *Client) Delete(ctx context.Context, bucket, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, bucket, key, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do sends a signed request without a body and returns the response for
+// 2xx statuses.
+func (c *Client) do(ctx context.Context, method, bucket, key string, query url.Values) (*http.Response, error) {
+	return c.send(ctx, method, bucket, key, query, nil, 0, emptyPayloadHash)
+}
+
+// send sends a signed request with a body of size bytes whose SHA-256 hex
+// digest is payloadHash, and returns the response for 2xx statuses.
+func (c *Client) send(ctx context.Context, method, bucket, key string, query url.Values, body io.ReadCloser, size int64, payloadHash string) (*http.Response, error) {
+	u := *c.endpoint
+	u.Path = strings.TrimRight(u.Path, "/") + "/" + bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	// Send the path exactly as it is signed
+	u.RawPath = canonicalURI(u.Path)
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Body = body
+		req.ContentLength = size
+	}
+	if c.AccessKeyID != "" || c.SecretAccessKey != "" {
+		c.sign(req, payloadHash, c.now())
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s: %w", method, u.Path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return resp, nil
+}
+
+// Error is an error response from S3.
+type Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("s3: %s: %s (status %d)", e.Code, e.Message, e.StatusCode)
+}
+
+// responseError decodes an S3 error response.
+func responseError(resp *http.Response) error {
+	e := &Error{StatusCode: resp.StatusCode}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	xml.Unmarshal(body, e)
+	return e
+}
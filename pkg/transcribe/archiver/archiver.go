@@ -0,0 +1,208 @@
+// Package archiver provides file archiving capabilities for the transcription service.
+package archiver
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/fileperm"
+)
+
+// Archiver moves processed files to an archive location.
+type Archiver interface {
+	Archive(ctx context.Context, sourcePath, archiveDir string) error
+}
+
+// SimpleArchiver implements Archiver with basic file moving.
+type SimpleArchiver struct {
+	perms fileperm.Permissions
+	clock clock.Clock
+}
+
+// Option configures a SimpleArchiver.
+type Option func(*SimpleArchiver)
+
+// WithPermissions sets the mode and group of archived files and the
+// directories created for them.
+func WithPermissions(p fileperm.Permissions) Option {
+	return func(a *SimpleArchiver) {
+		a.perms = p
+	}
+}
+
+// WithClock sets the clock that picks the date directory and collision
+// suffix of archived files.
+func WithClock(c clock.Clock) Option {
+	return func(a *SimpleArchiver) {
+		a.clock = clock.OrReal(c)
+	}
+}
+
+// NewSimpleArchiver creates a new simple archiver.
+func NewSimpleArchiver(opts ...Option) *SimpleArchiver {
+	a := &SimpleArchiver{clock: clock.Real{}}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Archive moves a file from sourcePath to the archiveDir.
+// Files are organized by date in subdirectories (YYYY/MM/DD).
+func (a *SimpleArchiver) Archive(ctx context.Context, sourcePath, archiveDir string) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	return a.ArchiveTo(ctx, sourcePath, a.DestinationPath(sourcePath, archiveDir))
+}
+
+// ArchiveTo moves a file from sourcePath to destPath, typically a path
+// returned by DestinationPath.
+func (a *SimpleArchiver) ArchiveTo(ctx context.Context, sourcePath, destPath string) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if err := a.perms.MkdirAll(filepath.Dir(destPath)); err != nil {
+		return fmt.Errorf("create archive directory: %w", err)
+	}
+
+	// Move the file
+	if err := os.Rename(sourcePath, destPath); err != nil {
+		// If rename fails (cross-device), try copy and delete
+		if err := copyAndDelete(sourcePath, destPath); err != nil {
+			return fmt.Errorf("archive file: %w", err)
+		}
+	}
+
+	if err := a.perms.ApplyFile(destPath); err != nil {
+		return fmt.Errorf("archive file: %w", err)
+	}
+	return nil
+}
+
+// DestinationPath returns the path Archive would move sourcePath to.
+// Files are organized by date in subdirectories (YYYY/MM/DD); a name that is
+// already taken gets a time suffix.
+func (a *SimpleArchiver) DestinationPath(sourcePath, archiveDir string) string {
+	now := a.clock.Now()
+	dateDir := filepath.Join(archiveDir, now.Format("2006"), now.Format("01"), now.Format("02"))
+
+	baseName := filepath.Base(sourcePath)
+	destPath := filepath.Join(dateDir, baseName)
+
+	// Handle filename collision by adding timestamp
+	if _, err := os.Stat(destPath); err == nil {
+		ext := filepath.Ext(baseName)
+		nameWithoutExt := baseName[:len(baseName)-len(ext)]
+		timestamp := now.Format("150405")
+		destPath = filepath.Join(dateDir, fmt.Sprintf("%s-%s%s", nameWithoutExt, timestamp, ext))
+	}
+
+	return destPath
+}
+
+// copyAndDelete copies a file and then deletes the original.
+// Used when os.Rename fails due to cross-device link.
+func copyAndDelete(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("read source file: %w", err)
+	}
+
+	// Get original file permissions
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("stat source file: %w", err)
+	}
+
+	if err := os.WriteFile(dst, data, info.Mode()); err != nil {
+		return fmt.Errorf("write destination file: %w", err)
+	}
+
+	if err := os.Remove(src); err != nil {
+		return fmt.Errorf("remove source file: %w", err)
+	}
+
+	return nil
+}
+
+// Prune removes the files archived before cutoff from archiveDir and then
+// the date directories it leaves empty, returning the paths removed. A
+// file's archive date is read from its YYYY/MM/DD directory, since archiving
+// keeps the recording's own modification time; files outside date
+// directories go by modification time.
+func Prune(ctx context.Context, archiveDir string, cutoff time.Time) ([]string, error) {
+	var removed []string
+	var dirs []string
+	err := filepath.WalkDir(archiveDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == archiveDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != archiveDir {
+				dirs = append(dirs, path)
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		archived, ok := archiveDate(archiveDir, path)
+		if !ok {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			archived = info.ModTime()
+		}
+		if !archived.Before(cutoff) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("remove archived file: %w", err)
+		}
+		removed = append(removed, path)
+		return nil
+	})
+	if err != nil {
+		return removed, err
+	}
+
+	// Deepest first, so emptied parents go too; non-empty ones stay
+	for i := len(dirs) - 1; i >= 0; i-- {
+		os.Remove(dirs[i])
+	}
+	return removed, nil
+}
+
+// archiveDate returns the end of the day named by the YYYY/MM/DD directory
+// path was archived into, in local time.
+func archiveDate(archiveDir, path string) (time.Time, bool) {
+	rel, err := filepath.Rel(archiveDir, filepath.Dir(path))
+	if err != nil {
+		return time.Time{}, false
+	}
+	day, err := time.ParseInLocation("2006/01/02", filepath.ToSlash(rel), time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day.AddDate(0, 0, 1), true
+}
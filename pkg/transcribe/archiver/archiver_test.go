@@ -0,0 +1,82 @@
+package archiver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+)
+
+func TestSimpleArchiver_DestinationPath(t *testing.T) {
+	archiveDir := t.TempDir()
+	clk := clock.NewFake(time.Date(2026, 1, 22, 9, 30, 15, 0, time.Local))
+	a := NewSimpleArchiver(WithClock(clk))
+
+	first := a.DestinationPath("/sync/memo.m4a", archiveDir)
+	expected := filepath.Join(archiveDir, "2026", "01", "22", "memo.m4a")
+	if first != expected {
+		t.Errorf("expected %s, got %s", expected, first)
+	}
+
+	src := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(src, []byte("audio"), 0644)
+	if err := a.ArchiveTo(context.Background(), src, first); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// A second recording with the same name gets the clock's time as suffix
+	collision := a.DestinationPath("/sync/memo.m4a", archiveDir)
+	expected = filepath.Join(archiveDir, "2026", "01", "22", "memo-093015.m4a")
+	if collision != expected {
+		t.Errorf("expected %s, got %s", expected, collision)
+	}
+
+	// After midnight files go to the next day's directory
+	clk.Set(time.Date(2026, 1, 23, 0, 0, 1, 0, time.Local))
+	expected = filepath.Join(archiveDir, "2026", "01", "23", "memo.m4a")
+	if next := a.DestinationPath("/sync/memo.m4a", archiveDir); next != expected {
+		t.Errorf("expected %s, got %s", expected, next)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	archiveDir := t.TempDir()
+	files := map[string]time.Time{
+		filepath.Join("2026", "08", "01", "old.m4a"):    time.Now(),
+		filepath.Join("2026", "10", "15", "recent.m4a"): time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local),
+		"loose-old.m4a": time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local),
+		"loose-new.m4a": time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local),
+	}
+	for rel, mtime := range files {
+		p := filepath.Join(archiveDir, rel)
+		os.MkdirAll(filepath.Dir(p), 0755)
+		os.WriteFile(p, []byte("audio"), 0644)
+		os.Chtimes(p, mtime, mtime)
+	}
+
+	cutoff := time.Date(2026, 9, 16, 0, 0, 0, 0, time.Local)
+	removed, err := Prune(context.Background(), archiveDir, cutoff)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(removed) != 2 {
+		t.Errorf("expected 2 files removed, got %v", removed)
+	}
+	// The date directory, not the recording's own time, decides
+	if _, err := os.Stat(filepath.Join(archiveDir, "2026", "10", "15", "recent.m4a")); err != nil {
+		t.Error("expected the recently archived file to be kept")
+	}
+	if _, err := os.Stat(filepath.Join(archiveDir, "2026", "08")); !os.IsNotExist(err) {
+		t.Error("expected emptied date directories to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(archiveDir, "loose-new.m4a")); err != nil {
+		t.Error("expected a new file outside date directories to be kept")
+	}
+
+	if removed, err := Prune(context.Background(), filepath.Join(archiveDir, "missing"), cutoff); err != nil || len(removed) != 0 {
+		t.Errorf("expected a missing archive to prune nothing, got %v, %v", removed, err)
+	}
+}
@@ -0,0 +1,114 @@
+package transcribe
+
+import (
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/archiver"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/events"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/stabilizer"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/watcher"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
+)
+
+// Builder assembles a Service, using the default implementation for any
+// component that is not supplied.
+//
+//	svc, err := transcribe.NewBuilder(cfg).
+//		WithClient(myClient).
+//		WithWriter(myWriter).
+//		Build()
+type Builder struct {
+	config     *Config
+	watcher    FileWatcher
+	stabilizer Stabilizer
+	client     TranscriptionClient
+	writer     OutputWriter
+	archiver   Archiver
+	logger     Logger
+	events     *events.Log
+	clock      clock.Clock
+}
+
+// NewBuilder creates a Builder for the given configuration.
+func NewBuilder(cfg *Config) *Builder {
+	return &Builder{config: cfg}
+}
+
+// WithWatcher sets the file watcher. Defaults to an inotify watcher.
+func (b *Builder) WithWatcher(w FileWatcher) *Builder {
+	b.watcher = w
+	return b
+}
+
+// WithStabilizer sets the stabilizer. Defaults to a polling stabilizer using
+// the configured interval and check count.
+func (b *Builder) WithStabilizer(st Stabilizer) *Builder {
+	b.stabilizer = st
+	return b
+}
+
+// WithClient sets the transcription client. Defaults to a
+// whisper-asr-webservice client for the configured API URL.
+func (b *Builder) WithClient(c TranscriptionClient) *Builder {
+	b.client = c
+	return b
+}
+
+// WithWriter sets the output writer. Defaults to a markdown writer.
+func (b *Builder) WithWriter(w OutputWriter) *Builder {
+	b.writer = w
+	return b
+}
+
+// WithArchiver sets the archiver. Defaults to moving files into the archive directory.
+func (b *Builder) WithArchiver(a Archiver) *Builder {
+	b.archiver = a
+	return b
+}
+
+// WithLogger sets the logger. Defaults to a daily file logger in ~/.nota/logs.
+func (b *Builder) WithLogger(l Logger) *Builder {
+	b.logger = l
+	return b
+}
+
+// WithEvents sets the log that pipeline events are written to. Defaults to
+// daily events files in ~/.nota/logs, unless WithLogger is used.
+func (b *Builder) WithEvents(l *events.Log) *Builder {
+	b.events = l
+	return b
+}
+
+// WithClock sets the clock that dates logs, note names, archive directories
+// and history records. Defaults to the system clock.
+func (b *Builder) WithClock(c clock.Clock) *Builder {
+	b.clock = c
+	return b
+}
+
+// Build validates the configuration and creates the Service.
+func (b *Builder) Build() (*Service, error) {
+	return NewServiceWith(b.config, Options{
+		Watcher:    b.watcher,
+		Stabilizer: b.stabilizer,
+		Client:     b.client,
+		Writer:     b.writer,
+		Archiver:   b.archiver,
+		Logger:     b.logger,
+		Events:     b.events,
+		Clock:      b.clock,
+	})
+}
+
+// Compile-time checks that the default components implement the pipeline interfaces.
+var (
+	_ FileWatcher         = (*watcher.InotifyWatcher)(nil)
+	_ Stabilizer          = (*stabilizer.PollStabilizer)(nil)
+	_ TranscriptionClient = (*client.WhisperASRClient)(nil)
+	_ OutputWriter        = (*writer.SimpleWriter)(nil)
+	_ NoteRenderer        = (*writer.SimpleWriter)(nil)
+	_ Archiver            = (*archiver.SimpleArchiver)(nil)
+	_ ArchivePlanner      = (*archiver.SimpleArchiver)(nil)
+	_ Logger              = (*logging.FileLogger)(nil)
+)
@@ -0,0 +1,157 @@
+package transcribe
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+// TestMain clears XDG_STATE_HOME so tests that point HOME at a temporary
+// directory keep their state files there too.
+func TestMain(m *testing.M) {
+	os.Unsetenv("XDG_STATE_HOME")
+	os.Exit(m.Run())
+}
+
+type fakeWatcher struct {
+	events chan FileEvent
+}
+
+func (w *fakeWatcher) Watch(ctx context.Context, dir string, patterns []string) (<-chan FileEvent, error) {
+	return w.events, nil
+}
+
+func (w *fakeWatcher) Stop() error { return nil }
+
+type fakeStabilizer struct{}
+
+func (fakeStabilizer) WaitForStable(ctx context.Context, path string) error { return nil }
+
+type fakeClient struct{}
+
+func (fakeClient) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	return &TranscriptionResult{Text: "transcribed " + audioPath, Language: "en"}, nil
+}
+
+type recordingWriter struct {
+	mu    sync.Mutex
+	texts []string
+}
+
+func (w *recordingWriter) Write(ctx context.Context, text string, opts OutputOptions) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.texts = append(w.texts, text)
+	return "/notes/out.md", nil
+}
+
+type recordingArchiver struct {
+	archived chan string
+}
+
+func (a *recordingArchiver) Archive(ctx context.Context, sourcePath, archiveDir string) error {
+	a.archived <- sourcePath
+	return nil
+}
+
+func setupBuilderTest(t *testing.T) *Config {
+	t.Helper()
+	tmpHome := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpHome)
+	t.Cleanup(func() { os.Setenv("HOME", originalHome) })
+
+	return &Config{
+		WatchDir:  tmpHome,
+		APIURL:    "http://localhost:9000",
+		OutputDir: tmpHome,
+	}
+}
+
+func TestBuilder_InjectedComponents(t *testing.T) {
+	cfg := setupBuilderTest(t)
+
+	fw := &fakeWatcher{events: make(chan FileEvent, 1)}
+	ow := &recordingWriter{}
+	arch := &recordingArchiver{archived: make(chan string, 1)}
+
+	svc, err := NewBuilder(cfg).
+		WithWatcher(fw).
+		WithStabilizer(fakeStabilizer{}).
+		WithClient(fakeClient{}).
+		WithWriter(ow).
+		WithArchiver(arch).
+		Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- svc.Run(ctx) }()
+
+	fw.events <- FileEvent{Path: "/audio/memo.m4a", Size: 10, Timestamp: time.Now()}
+
+	select {
+	case path := <-arch.archived:
+		if path != "/audio/memo.m4a" {
+			t.Errorf("expected memo.m4a to be archived, got: %s", path)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for file to be archived")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected clean shutdown, got: %v", err)
+	}
+
+	if len(ow.texts) != 1 || ow.texts[0] != "transcribed /audio/memo.m4a" {
+		t.Errorf("expected writer to receive transcription, got: %v", ow.texts)
+	}
+
+	historyPath, _ := history.DefaultPath()
+	records, err := history.New(historyPath).Load(time.Time{})
+	if err != nil {
+		t.Fatalf("failed to load history: %v", err)
+	}
+	if len(records) != 1 || records[0].Status != history.StatusCompleted {
+		t.Errorf("expected one completed history record, got: %+v", records)
+	}
+}
+
+func TestBuilder_PreviewRequiresRenderer(t *testing.T) {
+	cfg := setupBuilderTest(t)
+
+	svc, err := NewBuilder(cfg).
+		WithWatcher(&fakeWatcher{}).
+		WithClient(fakeClient{}).
+		WithWriter(&recordingWriter{}).
+		Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	audioPath := cfg.WatchDir + "/memo.m4a"
+	os.WriteFile(audioPath, []byte("audio"), 0644)
+
+	if _, err := svc.Preview(context.Background(), audioPath); err == nil {
+		t.Fatal("expected error for writer without Render")
+	}
+}
+
+func TestBuilder_InvalidConfig(t *testing.T) {
+	setupBuilderTest(t)
+
+	if _, err := NewBuilder(&Config{}).Build(); err == nil {
+		t.Fatal("expected error for invalid config")
+	}
+	if _, err := NewBuilder(nil).Build(); err == nil {
+		t.Fatal("expected error for nil config")
+	}
+}
@@ -0,0 +1,191 @@
+// Package checkpoint persists how far each file got through the
+// transcription pipeline, so a restarted daemon resumes a file at its last
+// completed stage rather than uploading it again.
+package checkpoint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/dirs"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// Stage is the last pipeline stage a file completed.
+type Stage string
+
+// Stages in pipeline order.
+const (
+	Detected Stage = "detected"
+	// Stable means the file stopped changing; from here on the state
+	// records the audio's hash.
+	Stable Stage = "stable"
+	// Transcribed means the transcript is saved in the state.
+	Transcribed Stage = "transcribed"
+	// Written means the note was written; only archiving is left.
+	Written  Stage = "written"
+	Archived Stage = "archived"
+)
+
+var order = map[Stage]int{Detected: 1, Stable: 2, Transcribed: 3, Written: 4, Archived: 5}
+
+// Reached reports whether stage s is other or a later one.
+func (s Stage) Reached(other Stage) bool {
+	return order[s] >= order[other]
+}
+
+// State is a file's progress through the pipeline.
+type State struct {
+	Source string `json:"source"`
+	Stage  Stage  `json:"stage"`
+	// Hash is the SHA-256 of the audio once stable; a file whose content
+	// changed since starts over.
+	Hash    string    `json:"hash,omitempty"`
+	Updated time.Time `json:"updated"`
+	// Transcript is the transcription, from Transcribed on.
+	Transcript *Transcript `json:"transcript,omitempty"`
+	// Output is the note written and ArchivePath where the audio is to be
+	// archived, from Written on.
+	Output      string `json:"output,omitempty"`
+	ArchivePath string `json:"archive_path,omitempty"`
+}
+
+// Transcript is a saved transcription result.
+type Transcript struct {
+	Text     string           `json:"text"`
+	Language string           `json:"language,omitempty"`
+	Duration float64          `json:"duration,omitempty"`
+	Segments []client.Segment `json:"segments,omitempty"`
+	Provider string           `json:"provider,omitempty"`
+}
+
+// NewTranscript saves a transcription result.
+func NewTranscript(r *client.TranscriptionResult) *Transcript {
+	return &Transcript{Text: r.Text, Language: r.Language, Duration: r.Duration, Segments: r.Segments, Provider: r.Provider}
+}
+
+// Result returns the saved transcription result.
+func (t *Transcript) Result() *client.TranscriptionResult {
+	return &client.TranscriptionResult{Text: t.Text, Language: t.Language, Duration: t.Duration, Segments: t.Segments, Provider: t.Provider}
+}
+
+// Store keeps one state file per source in a directory.
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the default checkpoint directory in the state
+// directory ($XDG_STATE_HOME/nota or ~/.nota, under pipeline).
+func DefaultDir() (string, error) {
+	dir, err := dirs.State()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pipeline"), nil
+}
+
+// New creates a store in dir. The directory is created on first save.
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Open creates a store in the default directory.
+func Open() (*Store, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return New(dir), nil
+}
+
+// path returns the state file of source.
+func (s *Store) path(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// Load returns the state of source, or nil if it has none.
+func (s *Store) Load(source string) (*State, error) {
+	data, err := os.ReadFile(s.path(source))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("read checkpoint: %w", err)
+	}
+	if st.Source != source {
+		// A hash collision; treat as no state
+		return nil, nil
+	}
+	return &st, nil
+}
+
+// Save writes the state, replacing the source's previous one atomically.
+func (s *Store) Save(st *State) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("create checkpoint directory: %w", err)
+	}
+	path := s.path(st.Source)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the state of source, if any.
+func (s *Store) Remove(source string) error {
+	err := os.Remove(s.path(source))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// List returns every saved state, oldest first. Unreadable files are
+// skipped.
+func (s *Store) List() ([]*State, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var states []*State
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var st State
+		if json.Unmarshal(data, &st) == nil && st.Source != "" {
+			states = append(states, &st)
+		}
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Updated.Before(states[j].Updated) })
+	return states, nil
+}
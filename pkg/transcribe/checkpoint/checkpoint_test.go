@@ -0,0 +1,92 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+func TestStore_SaveLoadRemove(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), "pipeline"))
+
+	if st, err := store.Load("/in/memo.m4a"); err != nil || st != nil {
+		t.Fatalf("expected no state, got %+v, %v", st, err)
+	}
+
+	result := &client.TranscriptionResult{
+		Text:     "Buy milk.",
+		Language: "en",
+		Duration: 2.5,
+		Segments: []client.Segment{{Start: 0, End: 2.5, Text: "Buy milk."}},
+	}
+	want := &State{
+		Source:     "/in/memo.m4a",
+		Stage:      Transcribed,
+		Hash:       "sha256:abc",
+		Updated:    time.Date(2026, 1, 22, 9, 30, 0, 0, time.UTC),
+		Transcript: NewTranscript(result),
+	}
+	if err := store.Save(want); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	got, err := store.Load("/in/memo.m4a")
+	if err != nil || got == nil {
+		t.Fatalf("expected saved state, got %+v, %v", got, err)
+	}
+	if got.Stage != Transcribed || got.Hash != want.Hash || !got.Updated.Equal(want.Updated) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if r := got.Transcript.Result(); r.Text != result.Text || r.Duration != result.Duration || len(r.Segments) != 1 {
+		t.Errorf("expected transcript %+v, got %+v", result, r)
+	}
+
+	// A later stage replaces the earlier one
+	got.Stage, got.Output = Written, "/notes/memo.md"
+	if err := store.Save(got); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	states, err := store.List()
+	if err != nil || len(states) != 1 || states[0].Stage != Written {
+		t.Fatalf("expected one written state, got %+v, %v", states, err)
+	}
+
+	if err := store.Remove("/in/memo.m4a"); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := store.Remove("/in/memo.m4a"); err != nil {
+		t.Errorf("expected removing a missing state to succeed, got: %v", err)
+	}
+	if st, _ := store.Load("/in/memo.m4a"); st != nil {
+		t.Errorf("expected no state after remove, got %+v", st)
+	}
+}
+
+func TestStore_ListOldestFirst(t *testing.T) {
+	dir := t.TempDir()
+	store := New(dir)
+	now := time.Now()
+	store.Save(&State{Source: "/in/b.m4a", Stage: Stable, Updated: now})
+	store.Save(&State{Source: "/in/a.m4a", Stage: Detected, Updated: now.Add(-time.Minute)})
+	os.WriteFile(filepath.Join(dir, "junk.json"), []byte("{"), 0644)
+
+	states, err := store.List()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(states) != 2 || states[0].Source != "/in/a.m4a" || states[1].Source != "/in/b.m4a" {
+		t.Errorf("expected a then b, got %+v", states)
+	}
+}
+
+func TestStage_Reached(t *testing.T) {
+	if !Written.Reached(Transcribed) || !Stable.Reached(Stable) {
+		t.Error("expected later and equal stages to count as reached")
+	}
+	if Stable.Reached(Transcribed) || Stage("").Reached(Detected) {
+		t.Error("expected earlier and unknown stages not to count as reached")
+	}
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultPollInterval is the initial delay between checks of a job.
+const DefaultPollInterval = 2 * time.Second
+
+// DefaultMaxPollInterval caps the delay between checks of a job.
+const DefaultMaxPollInterval = 30 * time.Second
+
+// JobService is a transcription API that works asynchronously: a recording
+// is submitted as a job, which is polled until it is done before its result
+// is fetched.
+type JobService interface {
+	// Submit starts a job transcribing the audio file and returns its ID.
+	Submit(ctx context.Context, audioPath string, opts TranscribeOptions) (string, error)
+	// Poll reports whether the job is done. A job that failed is an error.
+	Poll(ctx context.Context, id string) (bool, error)
+	// Result returns the transcription of a done job.
+	Result(ctx context.Context, id string) (*TranscriptionResult, error)
+}
+
+// JobRemover is implemented by a JobService that keeps jobs, and the audio
+// they were given, until they are removed.
+type JobRemover interface {
+	Remove(ctx context.Context, id string) error
+}
+
+// AsyncClient implements TranscriptionClient on top of a JobService. The
+// delay between polls doubles from the poll interval up to the maximum.
+type AsyncClient struct {
+	jobs        JobService
+	interval    time.Duration
+	maxInterval time.Duration
+}
+
+// AsyncOption configures the AsyncClient.
+type AsyncOption func(*AsyncClient)
+
+// WithPollInterval sets the initial and the maximum delay between polls.
+func WithPollInterval(initial, max time.Duration) AsyncOption {
+	return func(c *AsyncClient) {
+		c.interval = initial
+		c.maxInterval = max
+	}
+}
+
+// NewAsyncClient creates a new AsyncClient transcribing with jobs.
+func NewAsyncClient(jobs JobService, opts ...AsyncOption) *AsyncClient {
+	c := &AsyncClient{
+		jobs:        jobs,
+		interval:    DefaultPollInterval,
+		maxInterval: DefaultMaxPollInterval,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Transcribe submits an audio file, waits for the job and returns its
+// result. The job is removed afterwards, even when ctx is cancelled.
+func (c *AsyncClient) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	id, err := c.jobs.Submit(ctx, audioPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	if remover, ok := c.jobs.(JobRemover); ok {
+		defer remover.Remove(context.WithoutCancel(ctx), id)
+	}
+
+	delay := c.interval
+	for {
+		done, err := c.jobs.Poll(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, c.maxInterval)
+	}
+
+	return c.jobs.Result(ctx, id)
+}
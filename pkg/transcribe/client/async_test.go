@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// mockJobs is a test double for JobService whose job is done after a number
+// of polls.
+type mockJobs struct {
+	pending int
+	failure error
+
+	polls   []time.Time
+	removed []string
+}
+
+func (m *mockJobs) Submit(ctx context.Context, audioPath string, opts TranscribeOptions) (string, error) {
+	return "job-1", nil
+}
+
+func (m *mockJobs) Poll(ctx context.Context, id string) (bool, error) {
+	m.polls = append(m.polls, time.Now())
+	if len(m.polls) <= m.pending {
+		return false, nil
+	}
+	return true, m.failure
+}
+
+func (m *mockJobs) Result(ctx context.Context, id string) (*TranscriptionResult, error) {
+	return &TranscriptionResult{Text: "result of " + id}, nil
+}
+
+func (m *mockJobs) Remove(ctx context.Context, id string) error {
+	m.removed = append(m.removed, id)
+	return nil
+}
+
+func TestAsyncClient_PollsWithBackoff(t *testing.T) {
+	jobs := &mockJobs{pending: 3}
+	c := NewAsyncClient(jobs, WithPollInterval(10*time.Millisecond, 25*time.Millisecond))
+
+	result, err := c.Transcribe(context.Background(), "test.wav", TranscribeOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Text != "result of job-1" {
+		t.Errorf("got %q, want %q", result.Text, "result of job-1")
+	}
+	if len(jobs.polls) != 4 {
+		t.Fatalf("expected 4 polls, got %d", len(jobs.polls))
+	}
+
+	// 10ms, 20ms, then capped at 25ms
+	for i, min := range []time.Duration{10, 20, 25} {
+		if gap := jobs.polls[i+1].Sub(jobs.polls[i]); gap < min*time.Millisecond {
+			t.Errorf("poll %d: expected a delay of at least %dms, got %v", i+2, min, gap)
+		}
+	}
+	if len(jobs.removed) != 1 || jobs.removed[0] != "job-1" {
+		t.Errorf("expected the job to be removed, got %v", jobs.removed)
+	}
+}
+
+func TestAsyncClient_FailedJob(t *testing.T) {
+	jobs := &mockJobs{failure: &APIError{StatusCode: http.StatusUnprocessableEntity, Body: "unsupported audio"}}
+	c := NewAsyncClient(jobs)
+
+	_, err := c.Transcribe(context.Background(), "test.wav", TranscribeOptions{})
+	if StatusCode(err) != http.StatusUnprocessableEntity {
+		t.Errorf("expected the job's failure, got: %v", err)
+	}
+	if len(jobs.removed) != 1 {
+		t.Errorf("expected a failed job to be removed, got %v", jobs.removed)
+	}
+}
+
+func TestAsyncClient_ContextCancelled(t *testing.T) {
+	jobs := &mockJobs{pending: 100}
+	c := NewAsyncClient(jobs, WithPollInterval(time.Hour, time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := c.Transcribe(ctx, "test.wav", TranscribeOptions{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got: %v", err)
+	}
+	if len(jobs.removed) != 1 {
+		t.Errorf("expected an abandoned job to be removed, got %v", jobs.removed)
+	}
+}
@@ -120,23 +120,15 @@ func isRetryable(err error) bool {
 		return true
 	}
 
-	// Parse API error status codes
-	errStr := err.Error()
-	if strings.Contains(errStr, "API error: status ") {
-		var status int
-		if _, scanErr := fmt.Sscanf(errStr, "API error: status %d", &status); scanErr == nil {
-			// 4xx client errors are not retryable
-			if status >= 400 && status < 500 {
-				return false
-			}
-			// 5xx server errors are retryable
-			if status >= 500 && status < 600 {
-				return true
-			}
-		}
+	// 4xx client errors are not retryable; 5xx server errors are
+	if status := StatusCode(err); status >= 400 && status < 500 {
+		return false
+	} else if status >= 500 && status < 600 {
+		return true
 	}
 
 	// Connection errors in wrapped error messages - retryable
+	errStr := err.Error()
 	if strings.Contains(errStr, "connection refused") ||
 		strings.Contains(errStr, "connection reset") ||
 		strings.Contains(errStr, "no such host") ||
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket capping the combined rate of the uploads that
+// share it, so concurrent workers together stay under the limit.
+type Limiter struct {
+	// rate is in bytes per second; burst is the most that may be sent at
+	// once after a pause, and the largest read the limiter allows.
+	rate  float64
+	burst int
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a limiter allowing bytesPerSecond, with bursts of a
+// quarter of a second's worth.
+func NewLimiter(bytesPerSecond int64) *Limiter {
+	burst := max(int(bytesPerSecond/4), 1024)
+	return &Limiter{
+		rate:   float64(bytesPerSecond),
+		burst:  burst,
+		now:    time.Now,
+		sleep:  sleep,
+		tokens: float64(burst),
+	}
+}
+
+// sleep waits for d or until ctx is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// wait takes n bytes from the bucket, blocking until they are covered. The
+// bucket may go into debt, which makes later callers wait their turn.
+func (l *Limiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = min(float64(l.burst), l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	return l.sleep(ctx, delay)
+}
+
+// Reader returns r throttled by the limiter. Reads stop early with ctx's
+// error once it is cancelled.
+func (l *Limiter) Reader(ctx context.Context, r io.Reader) io.Reader {
+	return &limitedReader{ctx: ctx, r: r, l: l}
+}
+
+type limitedReader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *Limiter
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if len(p) > r.l.burst {
+		p = p[:r.l.burst]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.l.wait(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+// UploadStats describes a finished upload.
+type UploadStats struct {
+	Path  string
+	Bytes int64
+	// Elapsed runs from the first byte read to the last.
+	Elapsed time.Duration
+	// Reused is whether the request went over a kept-alive connection
+	// rather than a new one.
+	Reused bool
+}
+
+// Throughput returns the upload rate in bytes per second.
+func (s UploadStats) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) / s.Elapsed.Seconds()
+}
+
+// meteredReader counts the bytes read through it and times the reading.
+type meteredReader struct {
+	r          io.Reader
+	bytes      int64
+	start, end time.Time
+}
+
+func (m *meteredReader) Read(p []byte) (int, error) {
+	if m.start.IsZero() {
+		m.start = time.Now()
+	}
+	n, err := m.r.Read(p)
+	m.bytes += int64(n)
+	if err == io.EOF && m.end.IsZero() {
+		m.end = time.Now()
+	}
+	return n, err
+}
+
+// stats returns what was read, timed until EOF or, before it, until now.
+func (m *meteredReader) stats(path string) UploadStats {
+	end := m.end
+	if end.IsZero() {
+		end = time.Now()
+	}
+	var elapsed time.Duration
+	if !m.start.IsZero() {
+		elapsed = end.Sub(m.start)
+	}
+	return UploadStats{Path: path, Bytes: m.bytes, Elapsed: elapsed}
+}
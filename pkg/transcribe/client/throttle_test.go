@@ -0,0 +1,111 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLimiter returns a limiter whose clock only advances by the time it
+// sleeps, with the sleeps recorded.
+func fakeLimiter(bytesPerSecond int64) (*Limiter, *[]time.Duration) {
+	l := NewLimiter(bytesPerSecond)
+	now := time.Date(2026, 1, 22, 9, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	l.now = func() time.Time { return now }
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		now = now.Add(d)
+		return nil
+	}
+	return l, &slept
+}
+
+func TestLimiter_ThrottlesToRate(t *testing.T) {
+	l, slept := fakeLimiter(4096)
+	data := make([]byte, 5*4096)
+
+	n, err := io.Copy(io.Discard, l.Reader(context.Background(), bytes.NewReader(data)))
+	if err != nil || n != int64(len(data)) {
+		t.Fatalf("expected %d bytes, got %d, %v", len(data), n, err)
+	}
+
+	var total time.Duration
+	for _, d := range *slept {
+		total += d
+	}
+	// The first burst, a quarter of a second's worth, goes out at once
+	want := 4750 * time.Millisecond
+	if total < want-time.Millisecond || total > want+time.Millisecond {
+		t.Errorf("expected about %v spent waiting, got %v", want, total)
+	}
+}
+
+func TestLimiter_SharedBetweenReaders(t *testing.T) {
+	l, slept := fakeLimiter(4096)
+	a := l.Reader(context.Background(), bytes.NewReader(make([]byte, 4096)))
+	b := l.Reader(context.Background(), bytes.NewReader(make([]byte, 4096)))
+	io.Copy(io.Discard, a)
+	io.Copy(io.Discard, b)
+
+	var total time.Duration
+	for _, d := range *slept {
+		total += d
+	}
+	if total < 1700*time.Millisecond {
+		t.Errorf("expected the two readers to share 4 KB/s, waited only %v", total)
+	}
+}
+
+func TestLimiter_StopsWhenCancelled(t *testing.T) {
+	l := NewLimiter(1024)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := io.Copy(io.Discard, l.Reader(ctx, bytes.NewReader(make([]byte, 8192))))
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got: %v", err)
+	}
+}
+
+func TestWhisperASRClient_UploadLimitAndReport(t *testing.T) {
+	var mu sync.Mutex
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = len(body)
+		mu.Unlock()
+		if r.ContentLength != int64(len(body)) {
+			t.Errorf("expected Content-Length %d, got %d", len(body), r.ContentLength)
+		}
+		w.Write([]byte(`{"text": "hello", "language": "en"}`))
+	}))
+	defer server.Close()
+
+	audio := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(audio, make([]byte, 16*1024), 0644)
+
+	var stats []UploadStats
+	c := NewWhisperASRClient(server.URL,
+		WithUploadLimit(NewLimiter(64*1024)),
+		WithUploadReporter(func(s UploadStats) { stats = append(stats, s) }),
+	)
+	if _, err := c.Transcribe(context.Background(), audio, TranscribeOptions{}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if len(stats) != 1 || stats[0].Path != audio || stats[0].Bytes != int64(received) {
+		t.Fatalf("expected one upload of %d bytes reported, got %+v", received, stats)
+	}
+	if stats[0].Throughput() <= 0 {
+		t.Errorf("expected a throughput, got %+v", stats[0])
+	}
+}
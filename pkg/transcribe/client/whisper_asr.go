@@ -5,13 +5,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -24,6 +27,27 @@ type TranscriptionClient interface {
 type TranscribeOptions struct {
 	Language string
 	Model    string
+	// Prompt is text the transcript is expected to follow on from, which
+	// steers spelling and style, as Whisper's initial_prompt does.
+	Prompt string
+	// Vocabulary lists names and jargon the audio is likely to contain.
+	Vocabulary []string
+	// VADFilter asks the server to drop the parts of the audio without
+	// speech before transcribing, so noise is not transcribed as words.
+	VADFilter bool
+}
+
+// InitialPrompt returns the prompt followed by the vocabulary, for APIs that
+// take a single prompt.
+func (o TranscribeOptions) InitialPrompt() string {
+	parts := make([]string, 0, 2)
+	if o.Prompt != "" {
+		parts = append(parts, o.Prompt)
+	}
+	if len(o.Vocabulary) > 0 {
+		parts = append(parts, strings.Join(o.Vocabulary, ", ")+".")
+	}
+	return strings.Join(parts, " ")
 }
 
 // TranscriptionResult contains the API response.
@@ -31,6 +55,21 @@ type TranscriptionResult struct {
 	Text     string
 	Language string
 	Duration float64
+	// Segments are the timed parts of the transcript, when the API reports
+	// them.
+	Segments []Segment
+	// Provider names the provider that transcribed the audio. Clients
+	// leave it empty; the service sets it, as it may fall back from one
+	// provider to another.
+	Provider string
+}
+
+// Segment is a part of the transcript with its position in the audio, in
+// seconds.
+type Segment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
 }
 
 // OutputFormat specifies the response format from the transcription API.
@@ -41,6 +80,41 @@ const (
 	OutputFormatJSON OutputFormat = "json"
 )
 
+// ErrUnreachable is returned when the transcription API cannot be reached.
+var ErrUnreachable = errors.New("transcription API unreachable")
+
+// APIError is returned when the transcription API answers with a status
+// other than 200 OK.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: status %d: %s", e.StatusCode, e.Body)
+}
+
+// StatusCode returns the HTTP status of the API error in err's chain, or 0
+// if there is none. Errors from other clients that only carry the message
+// are recognized as well.
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	if err == nil {
+		return 0
+	}
+	errStr := err.Error()
+	if i := strings.Index(errStr, "API error: status "); i >= 0 {
+		var status int
+		if _, scanErr := fmt.Sscanf(errStr[i:], "API error: status %d", &status); scanErr == nil {
+			return status
+		}
+	}
+	return 0
+}
+
 // DefaultTimeout is the default HTTP request timeout.
 const DefaultTimeout = 5 * time.Minute
 
@@ -49,6 +123,9 @@ type WhisperASRClient struct {
 	baseURL    string
 	httpClient *http.Client
 	output     OutputFormat
+	// limiter, if set, throttles uploads; onUpload is told about each.
+	limiter  *Limiter
+	onUpload func(UploadStats)
 }
 
 // WhisperASROption configures the WhisperASRClient.
@@ -75,6 +152,22 @@ func WithHTTPClient(client *http.Client) WhisperASROption {
 	}
 }
 
+// WithUploadLimit throttles uploads with l, which may be shared with other
+// clients to limit their combined rate.
+func WithUploadLimit(l *Limiter) WhisperASROption {
+	return func(c *WhisperASRClient) {
+		c.limiter = l
+	}
+}
+
+// WithUploadReporter calls f with the size and duration of each upload once
+// the request has been sent.
+func WithUploadReporter(f func(UploadStats)) WhisperASROption {
+	return func(c *WhisperASRClient) {
+		c.onUpload = f
+	}
+}
+
 // NewWhisperASRClient creates a new client for the whisper-asr-webservice.
 func NewWhisperASRClient(baseURL string, opts ...WhisperASROption) *WhisperASRClient {
 	c := &WhisperASRClient{
@@ -124,25 +217,44 @@ func (c *WhisperASRClient) Transcribe(ctx context.Context, audioPath string, opt
 		return nil, fmt.Errorf("build URL: %w", err)
 	}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, &buf)
+	// Create HTTP request, throttled and timed as configured
+	size := int64(buf.Len())
+	var body io.Reader = &buf
+	if c.limiter != nil {
+		body = c.limiter.Reader(ctx, body)
+	}
+	meter := &meteredReader{r: body}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, meter)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
+	req.ContentLength = size
+	var reused bool
+	req = req.WithContext(httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) { reused = info.Reused },
+	}))
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	req.Header.Set("Accept", "application/json")
 
 	// Send request
 	resp, err := c.httpClient.Do(req)
+	if c.onUpload != nil && meter.bytes > 0 {
+		stats := meter.stats(audioPath)
+		stats.Reused = reused
+		c.onUpload(stats)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("send request: %w", err)
+		}
+		return nil, fmt.Errorf("send request: %w: %w", ErrUnreachable, err)
 	}
 	defer resp.Body.Close()
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error: status %d: %s", resp.StatusCode, string(body))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	// Parse response based on output format
@@ -166,6 +278,12 @@ func (c *WhisperASRClient) buildURL(opts TranscribeOptions) (string, error) {
 	if opts.Language != "" && opts.Language != "auto" {
 		q.Set("language", opts.Language)
 	}
+	if prompt := opts.InitialPrompt(); prompt != "" {
+		q.Set("initial_prompt", prompt)
+	}
+	if opts.VADFilter {
+		q.Set("vad_filter", "true")
+	}
 
 	u.RawQuery = q.Encode()
 	return u.String(), nil
@@ -192,11 +310,13 @@ func (c *WhisperASRClient) parseResponse(body io.Reader) (*TranscriptionResult,
 	return &TranscriptionResult{
 		Text:     resp.Text,
 		Language: resp.Language,
+		Segments: resp.Segments,
 	}, nil
 }
 
 // whisperASRResponse represents the JSON response from the whisper-asr-webservice.
 type whisperASRResponse struct {
-	Text     string `json:"text"`
-	Language string `json:"language"`
+	Text     string    `json:"text"`
+	Language string    `json:"language"`
+	Segments []Segment `json:"segments"`
 }
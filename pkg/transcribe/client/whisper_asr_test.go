@@ -2,11 +2,14 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -70,6 +73,27 @@ func TestWhisperASRClient_buildURL(t *testing.T) {
 			opts:    TranscribeOptions{Language: "auto"},
 			want:    "http://localhost:9000/asr?output=json",
 		},
+		{
+			name:    "with prompt and vocabulary",
+			baseURL: "http://localhost:9000",
+			output:  OutputFormatJSON,
+			opts:    TranscribeOptions{Prompt: "Standup notes.", Vocabulary: []string{"Nota", "Kubernetes"}},
+			want:    "http://localhost:9000/asr?initial_prompt=Standup+notes.+Nota%2C+Kubernetes.&output=json",
+		},
+		{
+			name:    "with vocabulary only",
+			baseURL: "http://localhost:9000",
+			output:  OutputFormatJSON,
+			opts:    TranscribeOptions{Vocabulary: []string{"Nota"}},
+			want:    "http://localhost:9000/asr?initial_prompt=Nota.&output=json",
+		},
+		{
+			name:    "with vad filter",
+			baseURL: "http://localhost:9000",
+			output:  OutputFormatJSON,
+			opts:    TranscribeOptions{VADFilter: true},
+			want:    "http://localhost:9000/asr?output=json&vad_filter=true",
+		},
 		{
 			name:    "text output format",
 			baseURL: "http://localhost:9000",
@@ -123,6 +147,21 @@ func TestWhisperASRClient_parseResponse(t *testing.T) {
 		}
 	})
 
+	t.Run("JSON response with segments", func(t *testing.T) {
+		c := NewWhisperASRClient("http://localhost:9000", WithOutputFormat(OutputFormatJSON))
+		body := strings.NewReader(`{"text":" Hello. World.","language":"en","segments":[` +
+			`{"id":0,"start":0.0,"end":1.5,"text":" Hello.","tokens":[1,2]},` +
+			`{"id":1,"start":1.5,"end":3.25,"text":" World."}]}`)
+		result, err := c.parseResponse(body)
+		if err != nil {
+			t.Fatalf("parseResponse() error = %v", err)
+		}
+		want := []Segment{{Start: 0, End: 1.5, Text: " Hello."}, {Start: 1.5, End: 3.25, Text: " World."}}
+		if !reflect.DeepEqual(result.Segments, want) {
+			t.Errorf("Segments = %+v, want %+v", result.Segments, want)
+		}
+	})
+
 	t.Run("text response", func(t *testing.T) {
 		c := NewWhisperASRClient("http://localhost:9000", WithOutputFormat(OutputFormatText))
 		body := strings.NewReader("Hello, world!")
@@ -267,6 +306,9 @@ func TestWhisperASRClient_Transcribe(t *testing.T) {
 		if !strings.Contains(err.Error(), "status 500") {
 			t.Errorf("Error should contain status code: %v", err)
 		}
+		if got := StatusCode(fmt.Errorf("transcribe: %w", err)); got != http.StatusInternalServerError {
+			t.Errorf("StatusCode() = %d, want 500", got)
+		}
 	})
 
 	t.Run("file not found", func(t *testing.T) {
@@ -295,6 +337,23 @@ func TestWhisperASRClient_Transcribe(t *testing.T) {
 	})
 }
 
+func TestWhisperASRClient_Unreachable(t *testing.T) {
+	audioFile := filepath.Join(t.TempDir(), "test.m4a")
+	if err := os.WriteFile(audioFile, []byte("fake audio content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// Start and immediately stop a server so its address refuses connections
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	c := NewWhisperASRClient(server.URL)
+	_, err := c.Transcribe(context.Background(), audioFile, TranscribeOptions{})
+	if !errors.Is(err, ErrUnreachable) {
+		t.Errorf("Transcribe() error = %v, want ErrUnreachable", err)
+	}
+}
+
 func TestTranscriptionClientInterface(t *testing.T) {
 	// Verify WhisperASRClient implements TranscriptionClient
 	var _ TranscriptionClient = (*WhisperASRClient)(nil)
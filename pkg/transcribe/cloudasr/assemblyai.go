@@ -0,0 +1,221 @@
+package cloudasr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// AssemblyAIConfig selects an AssemblyAI speech model and API key.
+type AssemblyAIConfig struct {
+	Key string
+	// SpeechModel is the model, e.g. universal or slam-1. Empty selects the
+	// service's default.
+	SpeechModel string
+	// Endpoint replaces https://api.assemblyai.com, e.g. with
+	// https://api.eu.assemblyai.com.
+	Endpoint string
+	// PollInterval is how long to wait before checking the job again,
+	// doubling with each check up to client.DefaultMaxPollInterval.
+	PollInterval time.Duration
+}
+
+// AssemblyAIClient transcribes with AssemblyAI: each recording is uploaded,
+// transcribed by a job polled until it finishes, and then deleted together
+// with the transcript kept by the service.
+type AssemblyAIClient struct {
+	cfg AssemblyAIConfig
+	transport
+	async *client.AsyncClient
+
+	mu   sync.Mutex
+	done map[string]*assemblyAITranscript
+}
+
+// NewAssemblyAI creates a client for the AssemblyAI account in cfg.
+func NewAssemblyAI(cfg AssemblyAIConfig, opts ...Option) *AssemblyAIClient {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://api.assemblyai.com"
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = client.DefaultPollInterval
+	}
+	c := &AssemblyAIClient{cfg: cfg, transport: newTransport(opts), done: make(map[string]*assemblyAITranscript)}
+	c.async = client.NewAsyncClient(c, client.WithPollInterval(cfg.PollInterval, max(cfg.PollInterval, client.DefaultMaxPollInterval)))
+	return c
+}
+
+// assemblyAIRequest starts a transcription.
+type assemblyAIRequest struct {
+	AudioURL          string   `json:"audio_url"`
+	LanguageCode      string   `json:"language_code,omitempty"`
+	LanguageDetection bool     `json:"language_detection,omitempty"`
+	SpeechModel       string   `json:"speech_model,omitempty"`
+	WordBoost         []string `json:"word_boost,omitempty"`
+}
+
+// assemblyAITranscript is a transcription, complete once its status is
+// completed.
+type assemblyAITranscript struct {
+	ID            string  `json:"id"`
+	Status        string  `json:"status"`
+	Error         string  `json:"error"`
+	Text          string  `json:"text"`
+	LanguageCode  string  `json:"language_code"`
+	AudioDuration float64 `json:"audio_duration"`
+}
+
+// Transcribe uploads an audio file, transcribes it with a job and returns
+// the transcription. The model option does not apply, and the prompt is not
+// sent; vocabulary is sent as words to boost.
+func (c *AssemblyAIClient) Transcribe(ctx context.Context, audioPath string, opts client.TranscribeOptions) (*client.TranscriptionResult, error) {
+	return c.async.Transcribe(ctx, audioPath, opts)
+}
+
+// Submit uploads an audio file and starts a job transcribing it.
+func (c *AssemblyAIClient) Submit(ctx context.Context, audioPath string, opts client.TranscribeOptions) (string, error) {
+	audio, err := os.ReadFile(audioPath)
+	if err != nil {
+		return "", fmt.Errorf("open audio file: %w", err)
+	}
+	header := c.header()
+	header.Set("Content-Type", "application/octet-stream")
+	data, err := c.post(ctx, audioPath, c.cfg.Endpoint+"/v2/upload", header, audio)
+	if err != nil {
+		return "", fmt.Errorf("assemblyai: upload: %w", err)
+	}
+	var upload struct {
+		UploadURL string `json:"upload_url"`
+	}
+	if err := json.Unmarshal(data, &upload); err != nil || upload.UploadURL == "" {
+		return "", fmt.Errorf("assemblyai: upload: unexpected response %q", data)
+	}
+
+	req := assemblyAIRequest{AudioURL: upload.UploadURL, SpeechModel: c.cfg.SpeechModel, WordBoost: opts.Vocabulary}
+	if opts.Language == "" || opts.Language == "auto" {
+		req.LanguageDetection = true
+	} else {
+		req.LanguageCode = assemblyAILanguage(opts.Language)
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	header = c.header()
+	header.Set("Content-Type", "application/json")
+	data, err = c.post(ctx, "", c.cfg.Endpoint+"/v2/transcript", header, body)
+	if err != nil {
+		return "", fmt.Errorf("assemblyai: start transcription: %w", err)
+	}
+	var started assemblyAITranscript
+	if err := json.Unmarshal(data, &started); err != nil || started.ID == "" {
+		return "", fmt.Errorf("assemblyai: start transcription: unexpected response %q", data)
+	}
+	return started.ID, nil
+}
+
+// Poll checks on a job, and fails if the job did.
+func (c *AssemblyAIClient) Poll(ctx context.Context, id string) (bool, error) {
+	data, err := c.get(ctx, c.transcriptURL(id), c.header())
+	if err != nil {
+		return false, fmt.Errorf("assemblyai: check transcription: %w", err)
+	}
+	var t assemblyAITranscript
+	if err := json.Unmarshal(data, &t); err != nil {
+		return false, fmt.Errorf("assemblyai: parse transcription: %w", err)
+	}
+	switch t.Status {
+	case "completed":
+		c.mu.Lock()
+		c.done[id] = &t
+		c.mu.Unlock()
+		return true, nil
+	case "error":
+		// The recording is at fault, so retrying would not help
+		return false, &client.APIError{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       "assemblyai: transcription failed: " + t.Error,
+		}
+	}
+	return false, nil
+}
+
+// Result returns the transcription of a completed job, with its sentences as
+// the segments.
+func (c *AssemblyAIClient) Result(ctx context.Context, id string) (*client.TranscriptionResult, error) {
+	c.mu.Lock()
+	t := c.done[id]
+	c.mu.Unlock()
+	if t == nil {
+		return nil, fmt.Errorf("assemblyai: transcription %s has not completed", id)
+	}
+
+	data, err := c.get(ctx, c.transcriptURL(id)+"/sentences", c.header())
+	if err != nil {
+		return nil, fmt.Errorf("assemblyai: fetch sentences: %w", err)
+	}
+	var resp struct {
+		Sentences []struct {
+			Start int64  `json:"start"`
+			End   int64  `json:"end"`
+			Text  string `json:"text"`
+		} `json:"sentences"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("assemblyai: parse sentences: %w", err)
+	}
+
+	result := &client.TranscriptionResult{Text: t.Text, Language: t.LanguageCode, Duration: t.AudioDuration}
+	for _, s := range resp.Sentences {
+		result.Segments = append(result.Segments, client.Segment{
+			Start: float64(s.Start) / 1000,
+			End:   float64(s.End) / 1000,
+			Text:  s.Text,
+		})
+	}
+	return result, nil
+}
+
+// Remove deletes a transcription, and with it the uploaded recording.
+func (c *AssemblyAIClient) Remove(ctx context.Context, id string) error {
+	c.mu.Lock()
+	delete(c.done, id)
+	c.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.transcriptURL(id), nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	copyHeader(req.Header, c.header())
+	_, err = c.do(req, "", 0)
+	return err
+}
+
+func (c *AssemblyAIClient) header() http.Header {
+	header := http.Header{}
+	header.Set("Authorization", c.cfg.Key)
+	return header
+}
+
+func (c *AssemblyAIClient) transcriptURL(id string) string {
+	return c.cfg.Endpoint + "/v2/transcript/" + url.PathEscape(id)
+}
+
+// assemblyAILanguage returns the code AssemblyAI uses for language, such as
+// en_us for en-US. It calls British English en_uk.
+func assemblyAILanguage(language string) string {
+	code := strings.ToLower(strings.ReplaceAll(language, "-", "_"))
+	if code == "en_gb" {
+		return "en_uk"
+	}
+	return code
+}
@@ -0,0 +1,146 @@
+package cloudasr
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// fakeAssemblyAI serves AssemblyAI's API. Transcriptions are processing on
+// their first check and then end in status.
+type fakeAssemblyAI struct {
+	status string
+
+	mu       sync.Mutex
+	uploaded string
+	request  map[string]any
+	checks   int
+	deleted  []string
+}
+
+func (f *fakeAssemblyAI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("Authorization") != "secret" {
+		http.Error(w, `{"error": "Authentication error"}`, http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v2/upload":
+		data, _ := io.ReadAll(r.Body)
+		f.uploaded = string(data)
+		w.Write([]byte(`{"upload_url": "https://cdn.assemblyai.com/upload/abc"}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/v2/transcript":
+		json.NewDecoder(r.Body).Decode(&f.request)
+		w.Write([]byte(`{"id": "tx-1", "status": "queued"}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/v2/transcript/tx-1":
+		f.checks++
+		status := "processing"
+		if f.checks > 1 {
+			status = f.status
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"id":             "tx-1",
+			"status":         status,
+			"error":          "File does not appear to contain audio",
+			"text":           "Buy milk. And bread.",
+			"language_code":  "en_us",
+			"audio_duration": 4,
+		})
+	case r.Method == http.MethodGet && r.URL.Path == "/v2/transcript/tx-1/sentences":
+		w.Write([]byte(`{"sentences": [
+			{"start": 200, "end": 1100, "text": "Buy milk."},
+			{"start": 1600, "end": 3400, "text": "And bread."}
+		]}`))
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v2/transcript/"):
+		f.deleted = append(f.deleted, strings.TrimPrefix(r.URL.Path, "/v2/transcript/"))
+		w.Write([]byte(`{}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newFakeAssemblyAI(t *testing.T, status string) (*fakeAssemblyAI, *AssemblyAIClient) {
+	t.Helper()
+	fake := &fakeAssemblyAI{status: status}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, NewAssemblyAI(AssemblyAIConfig{Key: "secret", Endpoint: server.URL, PollInterval: time.Millisecond})
+}
+
+func TestAssemblyAIClient_Transcribe(t *testing.T) {
+	fake, c := newFakeAssemblyAI(t, "completed")
+	var uploaded int64
+	c.onUpload = func(u client.UploadStats) { uploaded = u.Bytes }
+
+	result, err := c.Transcribe(context.Background(), writeAudio(t, "audio"), client.TranscribeOptions{Language: "en-GB", Vocabulary: []string{"Nota"}})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if result.Text != "Buy milk. And bread." || result.Language != "en_us" || result.Duration != 4 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	want := client.Segment{Start: 1.6, End: 3.4, Text: "And bread."}
+	if len(result.Segments) != 2 || result.Segments[1] != want {
+		t.Errorf("expected second segment %+v, got %+v", want, result.Segments)
+	}
+	if uploaded != 5 {
+		t.Errorf("expected the 5 byte upload reported, got %d", uploaded)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.uploaded != "audio" {
+		t.Errorf("expected the audio uploaded, got %q", fake.uploaded)
+	}
+	if fake.request["audio_url"] != "https://cdn.assemblyai.com/upload/abc" || fake.request["language_code"] != "en_uk" {
+		t.Errorf("unexpected transcription request %v", fake.request)
+	}
+	if boost, _ := fake.request["word_boost"].([]any); len(boost) != 1 || boost[0] != "Nota" {
+		t.Errorf("expected the vocabulary boosted, got %v", fake.request["word_boost"])
+	}
+	if fake.checks != 2 {
+		t.Errorf("expected 2 checks, got %d", fake.checks)
+	}
+	if len(fake.deleted) != 1 || fake.deleted[0] != "tx-1" {
+		t.Errorf("expected the transcription deleted, got %v", fake.deleted)
+	}
+}
+
+func TestAssemblyAIClient_FailedJob(t *testing.T) {
+	fake, c := newFakeAssemblyAI(t, "error")
+
+	_, err := c.Transcribe(context.Background(), writeAudio(t, "audio"), client.TranscribeOptions{})
+	if client.StatusCode(err) != http.StatusUnprocessableEntity || !strings.Contains(err.Error(), "does not appear to contain audio") {
+		t.Errorf("expected the job's failure as status 422, got: %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.request["language_detection"] != true {
+		t.Errorf("expected language detection, got %v", fake.request)
+	}
+	if len(fake.deleted) != 1 {
+		t.Errorf("expected the failed transcription deleted, got %v", fake.deleted)
+	}
+}
+
+func TestAssemblyAIClient_Unauthorized(t *testing.T) {
+	_, c := newFakeAssemblyAI(t, "completed")
+	c.cfg.Key = "wrong"
+
+	_, err := c.Transcribe(context.Background(), writeAudio(t, "audio"), client.TranscribeOptions{})
+	if client.StatusCode(err) != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got: %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+package cloudasr
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/s3"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// DefaultAWSPollInterval is how long to wait before checking a
+// transcription job the first time.
+const DefaultAWSPollInterval = 5 * time.Second
+
+// AWSConfig selects the region and the S3 bucket recordings are staged in,
+// which AWS Transcribe reads them from.
+type AWSConfig struct {
+	Region string
+	// Bucket and Prefix are where recordings are uploaded; each is deleted
+	// once transcribed.
+	Bucket string
+	Prefix string
+	// AccessKeyID and SecretAccessKey sign the requests.
+	AccessKeyID     string
+	SecretAccessKey string
+	// Endpoint replaces https://transcribe.<region>.amazonaws.com and
+	// S3Endpoint the S3 one.
+	Endpoint   string
+	S3Endpoint string
+	// PollInterval is how long to wait before checking the job again,
+	// doubling with each check up to client.DefaultMaxPollInterval.
+	PollInterval time.Duration
+}
+
+// AWSClient transcribes with AWS Transcribe batch jobs: each recording is
+// uploaded to S3, transcribed by a job polled until it finishes, and then
+// removed together with the job. Uploads to S3 are not throttled.
+type AWSClient struct {
+	cfg AWSConfig
+	s3  *s3.Client
+	transport
+	async *client.AsyncClient
+	now   func() time.Time
+
+	mu   sync.Mutex
+	jobs map[string]*awsJobState
+}
+
+// awsJobState is what the client keeps of a running job.
+type awsJobState struct {
+	// key is the staged recording.
+	key string
+	// done is the job once completed.
+	done *awsJob
+}
+
+// NewAWS creates a client for the region and bucket in cfg.
+func NewAWS(cfg AWSConfig, opts ...Option) (*AWSClient, error) {
+	if cfg.Region == "" || cfg.Bucket == "" {
+		return nil, errors.New("aws: region and bucket are required")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://transcribe." + cfg.Region + ".amazonaws.com"
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultAWSPollInterval
+	}
+	store, err := s3.New(cfg.S3Endpoint, cfg.Region, cfg.AccessKeyID, cfg.SecretAccessKey)
+	if err != nil {
+		return nil, fmt.Errorf("aws: %w", err)
+	}
+	c := &AWSClient{cfg: cfg, s3: store, transport: newTransport(opts), now: time.Now, jobs: make(map[string]*awsJobState)}
+	c.async = client.NewAsyncClient(c, client.WithPollInterval(cfg.PollInterval, max(cfg.PollInterval, client.DefaultMaxPollInterval)))
+	store.HTTPClient = c.httpClient
+	return c, nil
+}
+
+// awsJob is the part of a transcription job nota reads.
+type awsJob struct {
+	TranscriptionJobStatus string `json:"TranscriptionJobStatus"`
+	FailureReason          string `json:"FailureReason"`
+	LanguageCode           string `json:"LanguageCode"`
+	Transcript             struct {
+		TranscriptFileUri string `json:"TranscriptFileUri"`
+	} `json:"Transcript"`
+}
+
+// awsTranscript is the transcript file of a completed job.
+type awsTranscript struct {
+	Results struct {
+		Transcripts []struct {
+			Transcript string `json:"transcript"`
+		} `json:"transcripts"`
+		AudioSegments []struct {
+			StartTime  string `json:"start_time"`
+			EndTime    string `json:"end_time"`
+			Transcript string `json:"transcript"`
+		} `json:"audio_segments"`
+		Items []struct {
+			EndTime string `json:"end_time"`
+		} `json:"items"`
+	} `json:"results"`
+}
+
+// Transcribe uploads an audio file to S3, transcribes it with a job and
+// returns the transcription. The model, prompt and vocabulary options do not
+// apply.
+func (c *AWSClient) Transcribe(ctx context.Context, audioPath string, opts client.TranscribeOptions) (*client.TranscriptionResult, error) {
+	return c.async.Transcribe(ctx, audioPath, opts)
+}
+
+// Submit uploads an audio file to S3 and starts a job transcribing it.
+func (c *AWSClient) Submit(ctx context.Context, audioPath string, opts client.TranscribeOptions) (string, error) {
+	file, err := os.Open(audioPath)
+	if err != nil {
+		return "", fmt.Errorf("open audio file: %w", err)
+	}
+	defer file.Close()
+
+	job := c.jobName()
+	key := c.cfg.Prefix + job + strings.ToLower(filepath.Ext(audioPath))
+	start := time.Now()
+	if err := c.s3.Put(ctx, c.cfg.Bucket, key, file); err != nil {
+		return "", fmt.Errorf("aws: stage recording in S3: %w", err)
+	}
+	if c.onUpload != nil {
+		info, _ := file.Stat()
+		c.onUpload(client.UploadStats{Path: audioPath, Bytes: info.Size(), Elapsed: time.Since(start)})
+	}
+
+	request := map[string]any{
+		"TranscriptionJobName": job,
+		"Media":                map[string]string{"MediaFileUri": "s3://" + c.cfg.Bucket + "/" + key},
+	}
+	if l := locale(opts.Language); l != "" {
+		request["LanguageCode"] = l
+	} else {
+		request["IdentifyLanguage"] = true
+	}
+	if err := c.call(ctx, "StartTranscriptionJob", request, nil); err != nil {
+		// Clean up even when ctx was cancelled
+		c.s3.Delete(context.WithoutCancel(ctx), c.cfg.Bucket, key)
+		return "", err
+	}
+	c.mu.Lock()
+	c.jobs[job] = &awsJobState{key: key}
+	c.mu.Unlock()
+	return job, nil
+}
+
+// Poll checks on a job, and fails if the job did.
+func (c *AWSClient) Poll(ctx context.Context, job string) (bool, error) {
+	var resp struct {
+		TranscriptionJob awsJob `json:"TranscriptionJob"`
+	}
+	if err := c.call(ctx, "GetTranscriptionJob", map[string]string{"TranscriptionJobName": job}, &resp); err != nil {
+		return false, err
+	}
+	switch resp.TranscriptionJob.TranscriptionJobStatus {
+	case "COMPLETED":
+		c.mu.Lock()
+		if state, ok := c.jobs[job]; ok {
+			state.done = &resp.TranscriptionJob
+		}
+		c.mu.Unlock()
+		return true, nil
+	case "FAILED":
+		// The recording is at fault, so retrying would not help
+		return false, &client.APIError{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       "aws: transcription job failed: " + resp.TranscriptionJob.FailureReason,
+		}
+	}
+	return false, nil
+}
+
+// Result fetches the transcript of a completed job.
+func (c *AWSClient) Result(ctx context.Context, job string) (*client.TranscriptionResult, error) {
+	c.mu.Lock()
+	state := c.jobs[job]
+	c.mu.Unlock()
+	if state == nil || state.done == nil {
+		return nil, fmt.Errorf("aws: job %s has not completed", job)
+	}
+	done := state.done
+
+	data, err := c.get(ctx, done.Transcript.TranscriptFileUri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("aws: fetch transcript: %w", err)
+	}
+	var transcript awsTranscript
+	if err := json.Unmarshal(data, &transcript); err != nil {
+		return nil, fmt.Errorf("aws: parse transcript: %w", err)
+	}
+
+	res := transcript.Results
+	result := &client.TranscriptionResult{Language: done.LanguageCode}
+	var texts []string
+	for _, t := range res.Transcripts {
+		texts = append(texts, t.Transcript)
+	}
+	result.Text = strings.Join(texts, "\n")
+	for _, s := range res.AudioSegments {
+		seg := client.Segment{Start: seconds(s.StartTime), End: seconds(s.EndTime), Text: s.Transcript}
+		result.Segments = append(result.Segments, seg)
+		result.Duration = max(result.Duration, seg.End)
+	}
+	for _, item := range res.Items {
+		result.Duration = max(result.Duration, seconds(item.EndTime))
+	}
+	return result, nil
+}
+
+// Remove deletes a job and its staged recording.
+func (c *AWSClient) Remove(ctx context.Context, job string) error {
+	c.mu.Lock()
+	state := c.jobs[job]
+	delete(c.jobs, job)
+	c.mu.Unlock()
+
+	err := c.call(ctx, "DeleteTranscriptionJob", map[string]string{"TranscriptionJobName": job}, nil)
+	if state != nil {
+		if s3Err := c.s3.Delete(ctx, c.cfg.Bucket, state.key); err == nil {
+			err = s3Err
+		}
+	}
+	return err
+}
+
+// call invokes a Transcribe API action and decodes its response into out,
+// if set.
+func (c *AWSClient) call(ctx context.Context, action string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.Endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "Transcribe."+action)
+	s3.Sign(req, c.cfg.Region, "transcribe", c.cfg.AccessKeyID, c.cfg.SecretAccessKey, s3.PayloadHash(body), c.now())
+	data, err := c.do(req, "", 0)
+	if err != nil {
+		return fmt.Errorf("aws: %s: %w", action, err)
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("aws: %s: parse response: %w", action, err)
+		}
+	}
+	return nil
+}
+
+// jobName returns a unique job name, which is also the staged object's.
+func (c *AWSClient) jobName() string {
+	var b [4]byte
+	rand.Read(b[:])
+	return "nota-" + c.now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b[:])
+}
+
+// seconds parses a time in seconds, such as "2.35", or returns 0.
+func seconds(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
@@ -0,0 +1,162 @@
+package cloudasr
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// fakeAWS serves S3 and Transcribe from one endpoint. Jobs are in progress
+// on their first check and then end in status.
+type fakeAWS struct {
+	status string
+
+	mu      sync.Mutex
+	objects map[string]string
+	actions []string
+	start   map[string]any
+	checks  int
+}
+
+func (f *fakeAWS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.URL.Path == "/transcript.json" {
+		// A presigned URL, fetched without signing
+		w.Write([]byte(`{"results": {
+			"transcripts": [{"transcript": "Buy milk."}],
+			"audio_segments": [{"start_time": "0.3", "end_time": "1.9", "transcript": "Buy milk."}],
+			"items": [{"end_time": "1.2"}, {"end_time": "1.9"}]
+		}}`))
+		return
+	}
+	if !strings.Contains(r.Header.Get("Authorization"), "aws4_request") {
+		http.Error(w, "unsigned", http.StatusForbidden)
+		return
+	}
+
+	target := r.Header.Get("X-Amz-Target")
+	switch {
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = string(data)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+	case target != "":
+		if !strings.Contains(r.Header.Get("Authorization"), "/transcribe/aws4_request") {
+			http.Error(w, `{"__type": "InvalidSignatureException"}`, http.StatusForbidden)
+			return
+		}
+		action := strings.TrimPrefix(target, "Transcribe.")
+		f.actions = append(f.actions, action)
+		var in map[string]any
+		json.NewDecoder(r.Body).Decode(&in)
+		switch action {
+		case "StartTranscriptionJob":
+			f.start = in
+			w.Write([]byte(`{}`))
+		case "GetTranscriptionJob":
+			f.checks++
+			status := "IN_PROGRESS"
+			if f.checks > 1 {
+				status = f.status
+			}
+			json.NewEncoder(w).Encode(map[string]any{"TranscriptionJob": map[string]any{
+				"TranscriptionJobStatus": status,
+				"FailureReason":          "Unsupported audio format",
+				"LanguageCode":           "en-US",
+				"Transcript":             map[string]string{"TranscriptFileUri": "http://" + r.Host + "/transcript.json"},
+			}})
+		default:
+			w.Write([]byte(`{}`))
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newFakeAWS(t *testing.T, status string) (*fakeAWS, *AWSClient) {
+	t.Helper()
+	fake := &fakeAWS{status: status, objects: make(map[string]string)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	c, err := NewAWS(AWSConfig{
+		Region:          "eu-west-1",
+		Bucket:          "memos",
+		Prefix:          "staging/",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		Endpoint:        server.URL,
+		S3Endpoint:      server.URL,
+		PollInterval:    time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return fake, c
+}
+
+func TestAWSClient_Transcribe(t *testing.T) {
+	fake, c := newFakeAWS(t, "COMPLETED")
+	var uploaded int64
+	c.onUpload = func(u client.UploadStats) { uploaded = u.Bytes }
+
+	result, err := c.Transcribe(context.Background(), writeAudio(t, "audio"), client.TranscribeOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if result.Text != "Buy milk." || result.Language != "en-US" || result.Duration != 1.9 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(result.Segments) != 1 || result.Segments[0].Start != 0.3 {
+		t.Errorf("unexpected segments: %+v", result.Segments)
+	}
+	if uploaded != 5 {
+		t.Errorf("expected the 5 byte upload reported, got %d", uploaded)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	uri, _ := fake.start["Media"].(map[string]any)["MediaFileUri"].(string)
+	if !strings.HasPrefix(uri, "s3://memos/staging/nota-") || !strings.HasSuffix(uri, ".m4a") {
+		t.Errorf("unexpected media URI %q", uri)
+	}
+	if fake.start["IdentifyLanguage"] != true {
+		t.Errorf("expected language identification, got %v", fake.start)
+	}
+	want := "StartTranscriptionJob GetTranscriptionJob GetTranscriptionJob DeleteTranscriptionJob"
+	if got := strings.Join(fake.actions, " "); got != want {
+		t.Errorf("expected actions %q, got %q", want, got)
+	}
+	if len(fake.objects) != 0 {
+		t.Errorf("expected the staged recording deleted, got %v", fake.objects)
+	}
+}
+
+func TestAWSClient_FailedJob(t *testing.T) {
+	fake, c := newFakeAWS(t, "FAILED")
+
+	_, err := c.Transcribe(context.Background(), writeAudio(t, "audio"), client.TranscribeOptions{Language: "en"})
+	if client.StatusCode(err) != http.StatusUnprocessableEntity || !strings.Contains(err.Error(), "Unsupported audio format") {
+		t.Errorf("expected the job's failure as status 422, got: %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.start["LanguageCode"] != "en-US" {
+		t.Errorf("expected language en-US, got %v", fake.start)
+	}
+	if len(fake.objects) != 0 || fake.actions[len(fake.actions)-1] != "DeleteTranscriptionJob" {
+		t.Errorf("expected the recording and job cleaned up, got %v and %v", fake.objects, fake.actions)
+	}
+}
@@ -0,0 +1,128 @@
+package cloudasr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// azureAPIVersion is the version of the fast transcription API used.
+const azureAPIVersion = "2024-11-15"
+
+// AzureConfig selects an Azure Speech resource.
+type AzureConfig struct {
+	// Region is the resource's region, e.g. westeurope.
+	Region string
+	// Key is one of the resource's keys.
+	Key string
+	// Endpoint replaces https://<region>.api.cognitive.microsoft.com, e.g.
+	// for a custom domain.
+	Endpoint string
+}
+
+// AzureClient transcribes with Azure Speech's fast transcription API, which
+// answers synchronously.
+type AzureClient struct {
+	cfg AzureConfig
+	transport
+}
+
+// NewAzure creates a client for the Azure Speech resource in cfg.
+func NewAzure(cfg AzureConfig, opts ...Option) *AzureClient {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://" + cfg.Region + ".api.cognitive.microsoft.com"
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	return &AzureClient{cfg: cfg, transport: newTransport(opts)}
+}
+
+// azureDefinition is the transcription request's definition part.
+type azureDefinition struct {
+	// Locales empty lets the service identify the language.
+	Locales []string `json:"locales,omitempty"`
+}
+
+// azureResponse is a fast transcription result.
+type azureResponse struct {
+	DurationMilliseconds int64 `json:"durationMilliseconds"`
+	CombinedPhrases      []struct {
+		Text string `json:"text"`
+	} `json:"combinedPhrases"`
+	Phrases []struct {
+		OffsetMilliseconds   int64  `json:"offsetMilliseconds"`
+		DurationMilliseconds int64  `json:"durationMilliseconds"`
+		Text                 string `json:"text"`
+		Locale               string `json:"locale"`
+	} `json:"phrases"`
+}
+
+// Transcribe uploads an audio file and returns the transcription. The
+// model, prompt and vocabulary options do not apply.
+func (c *AzureClient) Transcribe(ctx context.Context, audioPath string, opts client.TranscribeOptions) (*client.TranscriptionResult, error) {
+	audio, err := os.ReadFile(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("open audio file: %w", err)
+	}
+
+	var def azureDefinition
+	if l := locale(opts.Language); l != "" {
+		def.Locales = []string{l}
+	}
+	definition, err := json.Marshal(def)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.WriteField("definition", string(definition)); err != nil {
+		return nil, fmt.Errorf("create form: %w", err)
+	}
+	part, err := w.CreateFormFile("audio", filepath.Base(audioPath))
+	if err != nil {
+		return nil, fmt.Errorf("create form file: %w", err)
+	}
+	part.Write(audio)
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", w.FormDataContentType())
+	header.Set("Ocp-Apim-Subscription-Key", c.cfg.Key)
+	url := c.cfg.Endpoint + "/speechtotext/transcriptions:transcribe?api-version=" + azureAPIVersion
+	data, err := c.post(ctx, audioPath, url, header, buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	var resp azureResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("parse JSON response: %w", err)
+	}
+	result := &client.TranscriptionResult{Duration: float64(resp.DurationMilliseconds) / 1000}
+	var texts []string
+	for _, p := range resp.CombinedPhrases {
+		texts = append(texts, p.Text)
+	}
+	result.Text = strings.Join(texts, "\n")
+	for _, p := range resp.Phrases {
+		if result.Language == "" {
+			result.Language = p.Locale
+		}
+		result.Segments = append(result.Segments, client.Segment{
+			Start: float64(p.OffsetMilliseconds) / 1000,
+			End:   float64(p.OffsetMilliseconds+p.DurationMilliseconds) / 1000,
+			Text:  p.Text,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,104 @@
+package cloudasr
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+func writeAudio(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "memo.m4a")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write audio: %v", err)
+	}
+	return path
+}
+
+func TestAzureClient_Transcribe(t *testing.T) {
+	var definition azureDefinition
+	var audio string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/speechtotext/transcriptions:transcribe" || r.URL.Query().Get("api-version") != azureAPIVersion {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Ocp-Apim-Subscription-Key") != "secret" {
+			http.Error(w, `{"error": {"code": "401"}}`, http.StatusUnauthorized)
+			return
+		}
+		json.Unmarshal([]byte(r.FormValue("definition")), &definition)
+		f, _, err := r.FormFile("audio")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(f)
+		audio = string(data)
+		w.Write([]byte(`{
+			"durationMilliseconds": 4200,
+			"combinedPhrases": [{"text": "Milch kaufen. Brot auch."}],
+			"phrases": [
+				{"offsetMilliseconds": 100, "durationMilliseconds": 1500, "text": "Milch kaufen.", "locale": "de-DE"},
+				{"offsetMilliseconds": 2000, "durationMilliseconds": 1200, "text": "Brot auch.", "locale": "de-DE"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	c := NewAzure(AzureConfig{Region: "westeurope", Key: "secret", Endpoint: server.URL})
+	result, err := c.Transcribe(context.Background(), writeAudio(t, "audio"), client.TranscribeOptions{Language: "de"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if audio != "audio" {
+		t.Errorf("expected the audio uploaded, got %q", audio)
+	}
+	if len(definition.Locales) != 1 || definition.Locales[0] != "de-DE" {
+		t.Errorf("expected locale de-DE, got %v", definition.Locales)
+	}
+	if result.Text != "Milch kaufen. Brot auch." || result.Language != "de-DE" || result.Duration != 4.2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	want := client.Segment{Start: 2, End: 3.2, Text: "Brot auch."}
+	if len(result.Segments) != 2 || result.Segments[1] != want {
+		t.Errorf("expected second segment %+v, got %+v", want, result.Segments)
+	}
+}
+
+func TestAzureClient_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": {"code": "InvalidSubscriptionKey"}}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c := NewAzure(AzureConfig{Key: "wrong", Endpoint: server.URL})
+	_, err := c.Transcribe(context.Background(), writeAudio(t, "audio"), client.TranscribeOptions{})
+	if client.StatusCode(err) != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got: %v", err)
+	}
+}
+
+func TestNewAzure_DefaultEndpoint(t *testing.T) {
+	c := NewAzure(AzureConfig{Region: "westeurope"})
+	if c.cfg.Endpoint != "https://westeurope.api.cognitive.microsoft.com" {
+		t.Errorf("unexpected endpoint %q", c.cfg.Endpoint)
+	}
+}
+
+func TestLocale(t *testing.T) {
+	tests := map[string]string{"": "", "auto": "", "en": "en-US", "DE": "de-DE", "en-GB": "en-GB", "eo": "eo"}
+	for in, want := range tests {
+		if got := locale(in); got != want {
+			t.Errorf("locale(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Package cloudasr provides transcription clients for hosted speech-to-text
+// services: Azure Speech, Google Cloud Speech-to-Text, AWS Transcribe,
+// Deepgram and AssemblyAI. They talk to each service's REST API directly, so
+// no SDK is needed; services that transcribe as jobs are polled with a
+// client.AsyncClient.
+package cloudasr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// transport sends the requests of a client.
+type transport struct {
+	httpClient *http.Client
+	// limiter, if set, throttles uploads; onUpload is told about each.
+	limiter  *client.Limiter
+	onUpload func(client.UploadStats)
+}
+
+// Option configures a client.
+type Option func(*transport)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(t *transport) {
+		t.httpClient = hc
+	}
+}
+
+// WithUploadLimit throttles uploads with l, which may be shared with other
+// clients to limit their combined rate.
+func WithUploadLimit(l *client.Limiter) Option {
+	return func(t *transport) {
+		t.limiter = l
+	}
+}
+
+// WithUploadReporter calls f with the size and duration of each upload once
+// the request has been sent.
+func WithUploadReporter(f func(client.UploadStats)) Option {
+	return func(t *transport) {
+		t.onUpload = f
+	}
+}
+
+func newTransport(opts []Option) transport {
+	t := transport{httpClient: &http.Client{Timeout: client.DefaultTimeout}}
+	for _, opt := range opts {
+		opt(&t)
+	}
+	return t
+}
+
+// post sends body to url and returns the body of a successful response, or
+// an *client.APIError for any other. When audioPath is set the request is
+// the upload of that file: it is throttled and reported.
+func (t *transport) post(ctx context.Context, audioPath, url string, header http.Header, body []byte) ([]byte, error) {
+	var r io.Reader = bytes.NewReader(body)
+	if audioPath != "" && t.limiter != nil {
+		r = t.limiter.Reader(ctx, r)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, r)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.ContentLength = int64(len(body))
+	copyHeader(req.Header, header)
+	return t.do(req, audioPath, int64(len(body)))
+}
+
+// get fetches url and returns the body of a successful response.
+func (t *transport) get(ctx context.Context, url string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	copyHeader(req.Header, header)
+	return t.do(req, "", 0)
+}
+
+func (t *transport) do(req *http.Request, audioPath string, size int64) ([]byte, error) {
+	ctx := req.Context()
+	var reused bool
+	req = req.WithContext(httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) { reused = info.Reused },
+	}))
+
+	start := time.Now()
+	resp, err := t.httpClient.Do(req)
+	if audioPath != "" && t.onUpload != nil && err == nil {
+		t.onUpload(client.UploadStats{Path: audioPath, Bytes: size, Elapsed: time.Since(start), Reused: reused})
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("send request: %w", err)
+		}
+		return nil, fmt.Errorf("send request: %w: %w", client.ErrUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &client.APIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+	return data, nil
+}
+
+// copyHeader copies the values of src into dst.
+func copyHeader(dst, src http.Header) {
+	for name, values := range src {
+		dst[name] = values
+	}
+}
+
+// locales maps the languages nota is usually configured with to the
+// region-qualified codes the services require.
+var locales = map[string]string{
+	"ar": "ar-SA", "da": "da-DK", "de": "de-DE", "en": "en-US", "es": "es-ES",
+	"fi": "fi-FI", "fr": "fr-FR", "hi": "hi-IN", "it": "it-IT", "ja": "ja-JP",
+	"ko": "ko-KR", "nb": "nb-NO", "nl": "nl-NL", "pl": "pl-PL", "pt": "pt-BR",
+	"ru": "ru-RU", "sv": "sv-SE", "tr": "tr-TR", "uk": "uk-UA", "zh": "zh-CN",
+}
+
+// locale returns the region-qualified code for language, such as en-US for
+// en, or "" for auto-detection. Codes with a region are kept as they are.
+func locale(language string) string {
+	if language == "" || language == "auto" {
+		return ""
+	}
+	if l, ok := locales[strings.ToLower(language)]; ok {
+		return l
+	}
+	return language
+}
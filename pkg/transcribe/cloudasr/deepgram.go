@@ -0,0 +1,123 @@
+package cloudasr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// DefaultDeepgramModel is the model Deepgram transcribes with.
+const DefaultDeepgramModel = "nova-3"
+
+// DeepgramConfig selects a Deepgram model and API key.
+type DeepgramConfig struct {
+	Key string
+	// Model is the Deepgram model, e.g. nova-3 or whisper-large.
+	Model string
+	// Endpoint replaces https://api.deepgram.com, e.g. for a self-hosted
+	// deployment.
+	Endpoint string
+}
+
+// DeepgramClient transcribes with Deepgram's pre-recorded audio API. It
+// answers synchronously; its asynchronous mode delivers results to a
+// callback URL, which nota has none of, so no job is polled.
+type DeepgramClient struct {
+	cfg DeepgramConfig
+	transport
+}
+
+// NewDeepgram creates a client for the Deepgram account in cfg.
+func NewDeepgram(cfg DeepgramConfig, opts ...Option) *DeepgramClient {
+	if cfg.Model == "" {
+		cfg.Model = DefaultDeepgramModel
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://api.deepgram.com"
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	return &DeepgramClient{cfg: cfg, transport: newTransport(opts)}
+}
+
+// deepgramResponse is a pre-recorded transcription result.
+type deepgramResponse struct {
+	Metadata struct {
+		Duration float64 `json:"duration"`
+	} `json:"metadata"`
+	Results struct {
+		Channels []struct {
+			DetectedLanguage string `json:"detected_language"`
+			Alternatives     []struct {
+				Transcript string `json:"transcript"`
+			} `json:"alternatives"`
+		} `json:"channels"`
+		Utterances []struct {
+			Start      float64 `json:"start"`
+			End        float64 `json:"end"`
+			Transcript string  `json:"transcript"`
+		} `json:"utterances"`
+	} `json:"results"`
+}
+
+// Transcribe uploads an audio file and returns the transcription. The
+// model option does not apply; the configured model is used. Vocabulary is
+// sent as key terms, which Nova-3 models take; the prompt is not sent.
+func (c *DeepgramClient) Transcribe(ctx context.Context, audioPath string, opts client.TranscribeOptions) (*client.TranscriptionResult, error) {
+	audio, err := os.ReadFile(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("open audio file: %w", err)
+	}
+
+	query := url.Values{
+		"model":        {c.cfg.Model},
+		"smart_format": {"true"},
+		"utterances":   {"true"},
+	}
+	if opts.Language == "" || opts.Language == "auto" {
+		query.Set("detect_language", "true")
+	} else {
+		query.Set("language", opts.Language)
+	}
+	for _, term := range opts.Vocabulary {
+		query.Add("keyterm", term)
+	}
+	contentType := mime.TypeByExtension(strings.ToLower(filepath.Ext(audioPath)))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	header.Set("Authorization", "Token "+c.cfg.Key)
+	data, err := c.post(ctx, audioPath, c.cfg.Endpoint+"/v1/listen?"+query.Encode(), header, audio)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp deepgramResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("parse JSON response: %w", err)
+	}
+	result := &client.TranscriptionResult{Duration: resp.Metadata.Duration}
+	if channels := resp.Results.Channels; len(channels) > 0 {
+		result.Language = channels[0].DetectedLanguage
+		if alts := channels[0].Alternatives; len(alts) > 0 {
+			result.Text = alts[0].Transcript
+		}
+	}
+	if result.Language == "" && opts.Language != "auto" {
+		result.Language = opts.Language
+	}
+	for _, u := range resp.Results.Utterances {
+		result.Segments = append(result.Segments, client.Segment{Start: u.Start, End: u.End, Text: u.Transcript})
+	}
+	return result, nil
+}
@@ -0,0 +1,77 @@
+package cloudasr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+func TestDeepgramClient_Transcribe(t *testing.T) {
+	var query url.Values
+	var audio string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/listen" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Token secret" {
+			http.Error(w, `{"err_code": "INVALID_AUTH"}`, http.StatusUnauthorized)
+			return
+		}
+		query = r.URL.Query()
+		data, _ := io.ReadAll(r.Body)
+		audio = string(data)
+		w.Write([]byte(`{
+			"metadata": {"duration": 3.5},
+			"results": {
+				"channels": [{"detected_language": "en", "alternatives": [{"transcript": "Buy milk. And bread."}]}],
+				"utterances": [
+					{"start": 0.2, "end": 1.1, "transcript": "Buy milk."},
+					{"start": 1.6, "end": 3.4, "transcript": "And bread."}
+				]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	c := NewDeepgram(DeepgramConfig{Key: "secret", Endpoint: server.URL})
+	result, err := c.Transcribe(context.Background(), writeAudio(t, "audio"), client.TranscribeOptions{Language: "auto", Vocabulary: []string{"Nota", "Orbis"}})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if audio != "audio" {
+		t.Errorf("expected the audio uploaded, got %q", audio)
+	}
+	if query.Get("model") != DefaultDeepgramModel || query.Get("detect_language") != "true" || query.Has("language") {
+		t.Errorf("unexpected query %v", query)
+	}
+	if terms := query["keyterm"]; len(terms) != 2 || terms[0] != "Nota" || terms[1] != "Orbis" {
+		t.Errorf("expected the vocabulary sent as key terms, got %v", terms)
+	}
+	if result.Text != "Buy milk. And bread." || result.Language != "en" || result.Duration != 3.5 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	want := client.Segment{Start: 1.6, End: 3.4, Text: "And bread."}
+	if len(result.Segments) != 2 || result.Segments[1] != want {
+		t.Errorf("expected second segment %+v, got %+v", want, result.Segments)
+	}
+}
+
+func TestDeepgramClient_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"err_code": "INVALID_AUTH"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c := NewDeepgram(DeepgramConfig{Key: "wrong", Endpoint: server.URL})
+	_, err := c.Transcribe(context.Background(), writeAudio(t, "audio"), client.TranscribeOptions{Language: "de"})
+	if client.StatusCode(err) != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got: %v", err)
+	}
+}
@@ -0,0 +1,320 @@
+package cloudasr
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// Google defaults.
+const (
+	DefaultGoogleLocation = "global"
+	DefaultGoogleModel    = "long"
+)
+
+// googleScope is the OAuth scope requested for service accounts.
+const googleScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// GoogleConfig selects a Google Cloud project and how to authenticate.
+type GoogleConfig struct {
+	// Project is the project ID. Defaults to the service account's.
+	Project string
+	// Location is where requests are processed, e.g. global or eu.
+	Location string
+	// Model is the recognition model, e.g. long or chirp_2.
+	Model string
+	// APIKey authenticates with an API key; otherwise Credentials does.
+	APIKey      string
+	Credentials *ServiceAccount
+	// Endpoint replaces the location's speech.googleapis.com endpoint.
+	Endpoint string
+}
+
+// ServiceAccount is a service account key file as downloaded from the
+// Google Cloud console.
+type ServiceAccount struct {
+	Type        string `json:"type"`
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// LoadServiceAccount reads a service account key file.
+func LoadServiceAccount(path string) (*ServiceAccount, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sa ServiceAccount
+	if err := json.Unmarshal(data, &sa); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if sa.Type != "service_account" || sa.ClientEmail == "" || sa.PrivateKey == "" || sa.TokenURI == "" {
+		return nil, fmt.Errorf("%s is not a service account key file", path)
+	}
+	return &sa, nil
+}
+
+// GoogleClient transcribes with Cloud Speech-to-Text v2's synchronous
+// recognize method, which takes recordings of up to one minute.
+type GoogleClient struct {
+	cfg GoogleConfig
+	key *rsa.PrivateKey
+	transport
+	now func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewGoogle creates a client for the project in cfg.
+func NewGoogle(cfg GoogleConfig, opts ...Option) (*GoogleClient, error) {
+	c := &GoogleClient{cfg: cfg, transport: newTransport(opts), now: time.Now}
+	if cfg.APIKey == "" {
+		if cfg.Credentials == nil {
+			return nil, errors.New("google: an API key or service account credentials are required")
+		}
+		key, err := parsePrivateKey(cfg.Credentials.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("google: service account private key: %w", err)
+		}
+		c.key = key
+		if c.cfg.Project == "" {
+			c.cfg.Project = cfg.Credentials.ProjectID
+		}
+	}
+	if c.cfg.Project == "" {
+		return nil, errors.New("google: project is required")
+	}
+	if c.cfg.Location == "" {
+		c.cfg.Location = DefaultGoogleLocation
+	}
+	if c.cfg.Model == "" {
+		c.cfg.Model = DefaultGoogleModel
+	}
+	if c.cfg.Endpoint == "" {
+		c.cfg.Endpoint = "https://speech.googleapis.com"
+		if c.cfg.Location != DefaultGoogleLocation {
+			c.cfg.Endpoint = "https://" + c.cfg.Location + "-speech.googleapis.com"
+		}
+	}
+	c.cfg.Endpoint = strings.TrimRight(c.cfg.Endpoint, "/")
+	return c, nil
+}
+
+// googleRequest is a recognize request.
+type googleRequest struct {
+	Config struct {
+		AutoDecodingConfig struct{} `json:"autoDecodingConfig"`
+		LanguageCodes      []string `json:"languageCodes"`
+		Model              string   `json:"model"`
+		Features           struct {
+			EnableAutomaticPunctuation bool `json:"enableAutomaticPunctuation"`
+		} `json:"features"`
+		Adaptation *googleAdaptation `json:"adaptation,omitempty"`
+	} `json:"config"`
+	// Content is the audio, base64-encoded by encoding/json.
+	Content []byte `json:"content"`
+}
+
+// googleAdaptation boosts the recognition of phrases.
+type googleAdaptation struct {
+	PhraseSets []googlePhraseSet `json:"phraseSets"`
+}
+
+type googlePhraseSet struct {
+	InlinePhraseSet struct {
+		Phrases []googlePhrase `json:"phrases"`
+	} `json:"inlinePhraseSet"`
+}
+
+type googlePhrase struct {
+	Value string `json:"value"`
+}
+
+// googleResponse is a recognize response; each result covers the audio
+// since the previous one's end.
+type googleResponse struct {
+	Results []struct {
+		Alternatives []struct {
+			Transcript string `json:"transcript"`
+		} `json:"alternatives"`
+		ResultEndOffset string `json:"resultEndOffset"`
+		LanguageCode    string `json:"languageCode"`
+	} `json:"results"`
+}
+
+// Transcribe uploads an audio file and returns the transcription. The
+// model option does not apply; the configured model is used, and the prompt
+// is not sent. Vocabulary is sent as an inline phrase set. Language auto
+// needs a model that detects the language, such as chirp_2.
+func (c *GoogleClient) Transcribe(ctx context.Context, audioPath string, opts client.TranscribeOptions) (*client.TranscriptionResult, error) {
+	audio, err := os.ReadFile(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("open audio file: %w", err)
+	}
+
+	var req googleRequest
+	req.Config.LanguageCodes = []string{"auto"}
+	if l := locale(opts.Language); l != "" {
+		req.Config.LanguageCodes = []string{l}
+	}
+	req.Config.Model = c.cfg.Model
+	req.Config.Features.EnableAutomaticPunctuation = true
+	if len(opts.Vocabulary) > 0 {
+		var set googlePhraseSet
+		for _, term := range opts.Vocabulary {
+			set.InlinePhraseSet.Phrases = append(set.InlinePhraseSet.Phrases, googlePhrase{Value: term})
+		}
+		req.Config.Adaptation = &googleAdaptation{PhraseSets: []googlePhraseSet{set}}
+	}
+	req.Content = audio
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	reqURL := fmt.Sprintf("%s/v2/projects/%s/locations/%s/recognizers/_:recognize",
+		c.cfg.Endpoint, url.PathEscape(c.cfg.Project), url.PathEscape(c.cfg.Location))
+	if c.cfg.APIKey != "" {
+		reqURL += "?key=" + url.QueryEscape(c.cfg.APIKey)
+	} else {
+		token, err := c.accessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		header.Set("Authorization", "Bearer "+token)
+	}
+	data, err := c.post(ctx, audioPath, reqURL, header, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp googleResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("parse JSON response: %w", err)
+	}
+	result := &client.TranscriptionResult{}
+	var texts []string
+	var start float64
+	for _, r := range resp.Results {
+		if len(r.Alternatives) == 0 {
+			continue
+		}
+		text := strings.TrimSpace(r.Alternatives[0].Transcript)
+		end := start
+		if d, err := time.ParseDuration(r.ResultEndOffset); err == nil {
+			end = d.Seconds()
+		}
+		if text != "" {
+			texts = append(texts, text)
+			result.Segments = append(result.Segments, client.Segment{Start: start, End: end, Text: text})
+		}
+		if result.Language == "" {
+			result.Language = r.LanguageCode
+		}
+		start = end
+	}
+	result.Text = strings.Join(texts, " ")
+	result.Duration = start
+	return result, nil
+}
+
+// accessToken returns an OAuth access token for the service account,
+// exchanging a signed JWT for a new one shortly before the last expires.
+func (c *GoogleClient) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if c.token != "" && now.Add(time.Minute).Before(c.expiry) {
+		return c.token, nil
+	}
+
+	sa := c.cfg.Credentials
+	assertion, err := signJWT(c.key, map[string]any{
+		"iss":   sa.ClientEmail,
+		"scope": googleScope,
+		"aud":   sa.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("google: sign token request: %w", err)
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	data, err := c.post(ctx, "", sa.TokenURI, header, []byte(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("google: get access token: %w", err)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &token); err != nil || token.AccessToken == "" {
+		return "", fmt.Errorf("google: get access token: unexpected response %q", data)
+	}
+	c.token = token.AccessToken
+	c.expiry = now.Add(time.Duration(token.ExpiresIn) * time.Second)
+	return c.token, nil
+}
+
+// signJWT returns claims as a JWT signed with RS256.
+func signJWT(key *rsa.PrivateKey, claims map[string]any) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// parsePrivateKey parses a PEM-encoded RSA key in PKCS #8 or PKCS #1 form.
+func parsePrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM data")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA key")
+	}
+	return key, nil
+}
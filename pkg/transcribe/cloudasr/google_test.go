@@ -0,0 +1,137 @@
+package cloudasr
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// googleServer serves recognize and, for service accounts, token requests
+// whose assertions must be signed by key.
+func googleServer(t *testing.T, key *rsa.PrivateKey, got *googleRequest, tokens *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokens.Add(1)
+			parts := strings.Split(r.FormValue("assertion"), ".")
+			sig, _ := base64.RawURLEncoding.DecodeString(parts[len(parts)-1])
+			sum := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+			if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" ||
+				rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, sum[:], sig) != nil {
+				http.Error(w, `{"error": "invalid_grant"}`, http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"access_token": "ya29.token", "expires_in": 3599, "token_type": "Bearer"}`))
+			return
+		}
+		if r.URL.Path != "/v2/projects/memos/locations/global/recognizers/_:recognize" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("key") != "api-key" && r.Header.Get("Authorization") != "Bearer ya29.token" {
+			http.Error(w, `{"error": {"code": 403}}`, http.StatusForbidden)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(got)
+		w.Write([]byte(`{"results": [
+			{"alternatives": [{"transcript": "buy milk", "confidence": 0.9}], "resultEndOffset": "2.500s", "languageCode": "en-us"},
+			{"alternatives": [{"transcript": " and bread"}], "resultEndOffset": "4s", "languageCode": "en-us"}
+		]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGoogleClient_APIKey(t *testing.T) {
+	var got googleRequest
+	server := googleServer(t, nil, &got, nil)
+
+	c, err := NewGoogle(GoogleConfig{Project: "memos", APIKey: "api-key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	result, err := c.Transcribe(context.Background(), writeAudio(t, "audio"), client.TranscribeOptions{Language: "en", Model: "base", Vocabulary: []string{"Nota"}})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if string(got.Content) != "audio" || got.Config.Model != DefaultGoogleModel || got.Config.LanguageCodes[0] != "en-US" {
+		t.Errorf("unexpected request: %+v", got.Config)
+	}
+	if a := got.Config.Adaptation; a == nil || len(a.PhraseSets) != 1 || len(a.PhraseSets[0].InlinePhraseSet.Phrases) != 1 || a.PhraseSets[0].InlinePhraseSet.Phrases[0].Value != "Nota" {
+		t.Errorf("expected the vocabulary as a phrase set, got %+v", got.Config.Adaptation)
+	}
+	if result.Text != "buy milk and bread" || result.Language != "en-us" || result.Duration != 4 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	want := client.Segment{Start: 2.5, End: 4, Text: "and bread"}
+	if len(result.Segments) != 2 || result.Segments[1] != want {
+		t.Errorf("expected second segment %+v, got %+v", want, result.Segments)
+	}
+}
+
+func TestGoogleClient_ServiceAccount(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	var got googleRequest
+	var tokens atomic.Int32
+	server := googleServer(t, key, &got, &tokens)
+
+	c, err := NewGoogle(GoogleConfig{
+		Endpoint: server.URL,
+		Credentials: &ServiceAccount{
+			Type:        "service_account",
+			ProjectID:   "memos",
+			ClientEmail: "nota@memos.iam.gserviceaccount.com",
+			PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+			TokenURI:    server.URL + "/token",
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	for range 2 {
+		if _, err := c.Transcribe(context.Background(), writeAudio(t, "audio"), client.TranscribeOptions{}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+
+	if n := tokens.Load(); n != 1 {
+		t.Errorf("expected the access token reused, got %d token requests", n)
+	}
+	if got.Config.LanguageCodes[0] != "auto" {
+		t.Errorf("expected language auto, got %v", got.Config.LanguageCodes)
+	}
+}
+
+func TestNewGoogle_RequiresCredentialsAndProject(t *testing.T) {
+	if _, err := NewGoogle(GoogleConfig{Project: "memos"}); err == nil {
+		t.Error("expected an error without credentials")
+	}
+	if _, err := NewGoogle(GoogleConfig{APIKey: "api-key"}); err == nil {
+		t.Error("expected an error without a project")
+	}
+	c, err := NewGoogle(GoogleConfig{Project: "memos", APIKey: "api-key", Location: "eu"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if c.cfg.Endpoint != "https://eu-speech.googleapis.com" {
+		t.Errorf("expected the regional endpoint, got %q", c.cfg.Endpoint)
+	}
+}
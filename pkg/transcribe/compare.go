@@ -0,0 +1,187 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/dirs"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/worddiff"
+)
+
+// CompareConfig is the model or endpoint `nota transcribe compare` tries
+// against the configured one, e.g. large-v3 on a GPU box against base on
+// the NAS.
+type CompareConfig struct {
+	// Model is the Whisper model to compare with. Defaults to model.
+	Model string `json:"model,omitempty"`
+	// APIURL is the transcription endpoint to compare with. Defaults to
+	// api_url.
+	APIURL string `json:"api_url,omitempty"`
+}
+
+// validate checks the endpoint URL.
+func (c CompareConfig) validate() error {
+	if c.APIURL == "" {
+		return nil
+	}
+	if _, err := NormalizeAPIURL(c.APIURL); err != nil {
+		return fmt.Errorf("compare: %w", err)
+	}
+	return nil
+}
+
+// CompareOptions configures Service.Compare.
+type CompareOptions struct {
+	// Model and APIURL pick the second transcription, B. Empty fields fall
+	// back to the compare settings, then to model and api_url.
+	Model  string
+	APIURL string
+	// Dir is where the transcripts and the diff are written. Defaults to
+	// the compare folder in nota's state directory.
+	Dir string
+	// Client transcribes B. Nil uses a whisper-asr-webservice client for
+	// B's API URL, or the service's client when that is api_url.
+	Client TranscriptionClient
+}
+
+// CompareRun is one of the two transcriptions of a comparison.
+type CompareRun struct {
+	Model    string
+	APIURL   string
+	Language string
+	// Text is the transcript.
+	Text string
+	// Elapsed is the time spent transcribing.
+	Elapsed time.Duration
+	// Path is the file the transcript was written to.
+	Path string
+}
+
+// Comparison is the outcome of transcribing one file twice.
+type Comparison struct {
+	Audio string
+	// A is the configured transcription, B the one compared with it.
+	A, B CompareRun
+	// Diff compares B's transcript with A's.
+	Diff *worddiff.Summary
+	// DiffPath is the file holding the summary and B's transcript with the
+	// differences from A marked.
+	DiffPath string
+}
+
+// Compare transcribes the audio at path with the configured model and
+// endpoint (A) and again with another (B), writes both transcripts to
+// opts.Dir, and diffs them word by word. Nothing is written to the output
+// directory and the audio is not archived.
+func (s *Service) Compare(ctx context.Context, path string, opts CompareOptions) (*Comparison, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	fileLogger := s.componentLogger("compare")
+
+	a := CompareRun{Model: s.config.Model, APIURL: s.config.APIURL}
+	b := a
+	if s.config.Compare != nil {
+		if s.config.Compare.Model != "" {
+			b.Model = s.config.Compare.Model
+		}
+		if s.config.Compare.APIURL != "" {
+			b.APIURL = s.config.Compare.APIURL
+		}
+	}
+	if opts.Model != "" {
+		b.Model = opts.Model
+	}
+	if opts.APIURL != "" {
+		b.APIURL = opts.APIURL
+	}
+	if b.APIURL != "" {
+		apiURL, err := NormalizeAPIURL(b.APIURL)
+		if err != nil {
+			return nil, err
+		}
+		b.APIURL = apiURL
+	}
+	if a.Model == b.Model && a.APIURL == b.APIURL {
+		return nil, fmt.Errorf("nothing to compare: set a different model or API URL to compare with %s", a.Model)
+	}
+
+	clientB := opts.Client
+	if clientB == nil {
+		clientB = s.client
+		if b.APIURL != a.APIURL {
+			var err error
+			if clientB, err = s.config.newClient(b.APIURL, s.uploads); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// One after the other, so sharing a GPU does not skew the timings
+	if err := s.compareRun(ctx, fileLogger, s.client, path, &a); err != nil {
+		return nil, fmt.Errorf("transcription with %s failed: %w", a.Model, err)
+	}
+	if err := s.compareRun(ctx, fileLogger, clientB, path, &b); err != nil {
+		return nil, fmt.Errorf("transcription with %s failed: %w", b.Model, err)
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		state, err := dirs.State()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(state, "compare")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	labelA, labelB := a.Model, b.Model
+	if labelA == labelB {
+		labelA, labelB = "a", "b"
+	}
+	a.Path = filepath.Join(dir, stem+"."+fileLabel(labelA)+".txt")
+	b.Path = filepath.Join(dir, stem+"."+fileLabel(labelB)+".txt")
+	diff := worddiff.Compare(a.Text, b.Text)
+	c := &Comparison{Audio: path, A: a, B: b, Diff: diff, DiffPath: filepath.Join(dir, stem+".diff.txt")}
+
+	report := fmt.Sprintf("A: %s (%s)\nB: %s (%s)\n%s\n\n%s\n", a.Model, a.APIURL, b.Model, b.APIURL, diff, diff.Render())
+	for file, content := range map[string]string{a.Path: a.Text + "\n", b.Path: b.Text + "\n", c.DiffPath: report} {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// compareRun transcribes path with tc and run's model, filling in run's
+// transcript, language and timing.
+func (s *Service) compareRun(ctx context.Context, fileLogger Logger, tc TranscriptionClient, path string, run *CompareRun) error {
+	start := time.Now()
+	opts := s.transcribeOptions(path)
+	opts.Model = run.Model
+	result, err := s.transcribeWith(ctx, fileLogger, tc, path, opts)
+	if err != nil {
+		return err
+	}
+	run.Elapsed = time.Since(start)
+	run.Language = result.Language
+	run.Text = result.Text
+	return nil
+}
+
+// fileLabel makes a model name safe to use in a file name.
+func fileLabel(model string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, model)
+}
@@ -0,0 +1,62 @@
+package transcribe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// modelClient returns a transcript per model.
+type modelClient map[string]string
+
+func (c modelClient) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	return &TranscriptionResult{Text: c[opts.Model], Language: "en"}, nil
+}
+
+func TestCompare(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.Model = "base"
+	cfg.Compare = &CompareConfig{Model: "large-v3"}
+	transcripts := modelClient{
+		"base":     "remind me to call the dentist on tuesday",
+		"large-v3": "Remind me to call the dentist on Thursday.",
+	}
+	svc, err := NewBuilder(cfg).WithWatcher(&fakeWatcher{}).WithClient(transcripts).Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	audio := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(audio, []byte("audio"), 0644)
+	dir := t.TempDir()
+
+	c, err := svc.Compare(context.Background(), audio, CompareOptions{Dir: dir})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if c.A.Model != "base" || c.B.Model != "large-v3" || c.B.APIURL != cfg.APIURL {
+		t.Errorf("unexpected runs: A %+v, B %+v", c.A, c.B)
+	}
+	if c.Diff.WordsA != 8 || c.Diff.Substituted != 1 || c.Diff.Edits() != 1 {
+		t.Errorf("expected one substituted word, got %s", c.Diff)
+	}
+
+	if c.B.Path != filepath.Join(dir, "memo.large-v3.txt") {
+		t.Errorf("unexpected transcript path %s", c.B.Path)
+	}
+	if data, _ := os.ReadFile(c.A.Path); string(data) != transcripts["base"]+"\n" {
+		t.Errorf("expected A's transcript written, got %q", data)
+	}
+	data, _ := os.ReadFile(c.DiffPath)
+	if !strings.Contains(string(data), "on [-tuesday-] {+Thursday.+}") {
+		t.Errorf("expected the word diff written, got:\n%s", data)
+	}
+
+	// The same model on the same endpoint is nothing to compare
+	if _, err := svc.Compare(context.Background(), audio, CompareOptions{Model: "base", Dir: dir}); err == nil {
+		t.Error("expected an error comparing the configured model with itself")
+	}
+}
@@ -0,0 +1,706 @@
+// Package transcribe provides audio transcription configuration and services.
+package transcribe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/fileperm"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/stabilizer"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/subtitle"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+)
+
+// ConfigFileName is the name of the transcription config file within .nota
+const ConfigFileName = "transcribe.json"
+
+// VaultVariable at the start of a configured path stands for the vault root,
+// so the config keeps working when the vault syncs to a machine with a
+// different home layout.
+const VaultVariable = "${VAULT}"
+
+// Default values for optional configuration fields
+const (
+	DefaultArchiveDir              = "~/.nota/archive/audio"
+	DefaultStabilizationIntervalMs = 2000
+	DefaultStabilizationChecks     = 3
+	DefaultLanguage                = "auto"
+	DefaultModel                   = "base"
+	DefaultLocale                  = writer.DefaultLocale
+	DefaultOutputFormat            = writer.FormatMarkdown
+	DefaultMaxFileSizeMB           = 100
+	DefaultTooLargeAction          = TooLargeSkip
+	DefaultRetryCount              = 3
+	DefaultWorkers                 = 2
+	DefaultQueueOrder              = QueueFIFO
+	DefaultProvider                = ProviderWhisperASR
+	DefaultFileTimeoutMinutes      = 30
+	DefaultWatchBufferSize         = 100
+	DefaultMinFreeSpaceMB          = 100
+)
+
+// DefaultWatchPatterns are the default file patterns to watch
+var DefaultWatchPatterns = []string{"*.m4a", "*.mp3", "*.wav"}
+
+// Config represents the transcription service configuration
+type Config struct {
+	SchemaVersion           int                        `json:"schema_version"`
+	WatchDir                string                     `json:"watch_dir"`
+	APIURL                  string                     `json:"api_url"`
+	Provider                Provider                   `json:"provider,omitempty"`
+	Azure                   *AzureConfig               `json:"azure,omitempty"`
+	Google                  *GoogleConfig              `json:"google,omitempty"`
+	AWS                     *AWSConfig                 `json:"aws,omitempty"`
+	Deepgram                *DeepgramConfig            `json:"deepgram,omitempty"`
+	AssemblyAI              *AssemblyAIConfig          `json:"assemblyai,omitempty"`
+	Fallbacks               []FallbackConfig           `json:"fallbacks,omitempty"`
+	OutputDir               string                     `json:"output_dir"`
+	TemplatePath            *string                    `json:"template_path"`
+	OutputFormat            writer.Format              `json:"output_format"`
+	MetadataFooter          bool                       `json:"metadata_footer"`
+	ArchiveDir              string                     `json:"archive_dir"`
+	FileMode                string                     `json:"file_mode,omitempty"`
+	DirMode                 string                     `json:"dir_mode,omitempty"`
+	Group                   string                     `json:"group,omitempty"`
+	WatchPatterns           []string                   `json:"watch_patterns"`
+	WatchDirs               []WatchDirConfig           `json:"watch_dirs,omitempty"`
+	WatchBufferSize         int                        `json:"watch_buffer_size"`
+	StabilizationIntervalMs int                        `json:"stabilization_interval_ms"`
+	StabilizationChecks     int                        `json:"stabilization_checks"`
+	StabilizationOpenFile   bool                       `json:"stabilization_open_file"`
+	StabilizationLock       string                     `json:"stabilization_lock"`
+	SkipSymlinks            bool                       `json:"skip_symlinks"`
+	Language                string                     `json:"language"`
+	Model                   string                     `json:"model"`
+	InitialPrompt           string                     `json:"initial_prompt,omitempty"`
+	Vocabulary              []string                   `json:"vocabulary,omitempty"`
+	VADFilter               bool                       `json:"vad_filter"`
+	Locale                  string                     `json:"locale"`
+	MinFileSizeKB           int                        `json:"min_file_size_kb"`
+	MinDurationSeconds      int                        `json:"min_duration_seconds"`
+	MaxFileSizeMB           int                        `json:"max_file_size_mb"`
+	TooLargeAction          TooLargeAction             `json:"too_large_action"`
+	SplitChunkMinutes       int                        `json:"split_chunk_minutes"`
+	MinFreeSpaceMB          int                        `json:"min_free_space_mb"`
+	RetryCount              int                        `json:"retry_count"`
+	Workers                 int                        `json:"workers"`
+	UploadLimitKBPerSecond  int                        `json:"upload_limit_kb_per_second,omitempty"`
+	HTTP                    *HTTPConfig                `json:"http,omitempty"`
+	QueueOrder              QueueOrder                 `json:"queue_order"`
+	FileTimeoutMinutes      int                        `json:"file_timeout_minutes"`
+	MergeWindowMinutes      int                        `json:"merge_window_minutes"`
+	Routes                  []RouteRule                `json:"routes,omitempty"`
+	FilenameParsers         []FilenameParser           `json:"filename_parsers,omitempty"`
+	Schedule                *ScheduleConfig            `json:"schedule,omitempty"`
+	Webhook                 *WebhookConfig             `json:"webhook,omitempty"`
+	Syncthing               *SyncthingConfig           `json:"syncthing,omitempty"`
+	Source                  *SourceConfig              `json:"source,omitempty"`
+	TitleStrategy           TitleStrategy              `json:"title_strategy,omitempty"`
+	TagStrategy             TagStrategy                `json:"tag_strategy,omitempty"`
+	MaxTags                 int                        `json:"max_tags"`
+	LLM                     *LLMConfig                 `json:"llm,omitempty"`
+	Redact                  *RedactConfig              `json:"redact,omitempty"`
+	Subtitles               subtitle.Format            `json:"subtitles,omitempty"`
+	Tracing                 *TracingConfig             `json:"tracing,omitempty"`
+	Jobs                    []JobConfig                `json:"jobs,omitempty"`
+	Compare                 *CompareConfig             `json:"compare,omitempty"`
+	Usage                   *UsageConfig               `json:"usage,omitempty"`
+	Hooks                   *HooksConfig               `json:"hooks,omitempty"`
+	Plugins                 bool                       `json:"plugins"`
+	Profiles                map[string]json.RawMessage `json:"profiles,omitempty"`
+
+	// profile is the name of the profile applied when loading.
+	profile string
+	// vaultRoot is the vault the config was loaded from, for vault jobs.
+	vaultRoot string
+	// warnings collects problems found while loading, such as unknown fields.
+	warnings []string
+}
+
+// VaultRoot returns the vault the config was loaded from, or "" for a config
+// not loaded with LoadFromVault.
+func (c *Config) VaultRoot() string {
+	return c.vaultRoot
+}
+
+// Warnings returns problems found while loading the config file that did not
+// prevent it from loading, such as unknown (likely misspelled) fields.
+func (c *Config) Warnings() []string {
+	return c.warnings
+}
+
+// WatchDirConfig is a directory listed in watch_dirs. Patterns override
+// watch_patterns for this directory when set.
+type WatchDirConfig struct {
+	Path     string   `json:"path"`
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// Watches returns every directory the service watches: watch_dir, if set,
+// followed by the watch_dirs entries, each with its effective patterns.
+func (c *Config) Watches() []WatchDirConfig {
+	var watches []WatchDirConfig
+	if c.WatchDir != "" {
+		watches = append(watches, WatchDirConfig{Path: c.WatchDir, Patterns: c.WatchPatterns})
+	}
+	for _, wd := range c.WatchDirs {
+		if len(wd.Patterns) == 0 {
+			wd.Patterns = c.WatchPatterns
+		}
+		watches = append(watches, wd)
+	}
+	return watches
+}
+
+// validateWatchPaths rejects a directory listed twice, as written or through
+// a symlink, as the watcher would see a single directory with one set of
+// patterns.
+func validateWatchPaths(watches []WatchDirConfig) error {
+	for i, wd := range watches {
+		path := filepath.Clean(wd.Path)
+		info, statErr := os.Stat(path)
+		for _, other := range watches[:i] {
+			if filepath.Clean(other.Path) == path {
+				return fmt.Errorf("%w: %s", ErrWatchDirDuplicate, wd.Path)
+			}
+			if statErr != nil {
+				continue
+			}
+			if otherInfo, err := os.Stat(other.Path); err == nil && os.SameFile(info, otherInfo) {
+				return fmt.Errorf("%w: %s and %s", ErrWatchDirDuplicate, other.Path, wd.Path)
+			}
+		}
+	}
+	return nil
+}
+
+// Templates returns the template files notes may be written from: the
+// template_path and those of the routing rules, each listed once.
+func (c *Config) Templates() []string {
+	var templates []string
+	add := func(path string) {
+		if path != "" && !slices.Contains(templates, path) {
+			templates = append(templates, path)
+		}
+	}
+	if c.TemplatePath != nil {
+		add(*c.TemplatePath)
+	}
+	for _, rule := range c.Routes {
+		add(rule.TemplatePath)
+	}
+	return templates
+}
+
+// ErrInvalidConfig wraps every error that stems from the contents of the
+// config file, so callers can tell configuration problems from others.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Validation errors
+var (
+	ErrWatchDirRequired        = errors.New("watch_dir, watch_dirs or source is required")
+	ErrWatchDirPath            = errors.New("every watch_dirs entry needs a path")
+	ErrWatchDirDuplicate       = errors.New("a directory is watched twice; list it once with all its patterns")
+	ErrAPIURLRequired          = errors.New("api_url is required")
+	ErrOutputDirRequired       = errors.New("output_dir is required")
+	ErrInvalidQueueOrder       = errors.New("queue_order must be fifo, newest_first or smallest_first")
+	ErrInvalidLockMode         = errors.New("stabilization_lock must be shared or exclusive")
+	ErrInvalidRoute            = errors.New("invalid route")
+	ErrInvalidAPIURL           = errors.New("api_url must be a URL such as http://localhost:9000/asr")
+	ErrNegativeValue           = errors.New("value must not be negative")
+	ErrInvalidLocale           = errors.New("unsupported locale")
+	ErrInvalidSchedule         = errors.New("invalid schedule")
+	ErrInvalidPermission       = errors.New("invalid file permissions")
+	ErrInvalidWebhook          = errors.New("invalid webhook")
+	ErrInvalidSyncthing        = errors.New("invalid syncthing settings")
+	ErrInvalidSource           = errors.New("invalid source")
+	ErrInvalidTitle            = errors.New("invalid title settings")
+	ErrInvalidTags             = errors.New("invalid tag settings")
+	ErrInvalidLLM              = errors.New("invalid llm settings")
+	ErrInvalidRedaction        = errors.New("invalid redact settings")
+	ErrInvalidSubtitles        = errors.New("subtitles must be srt or vtt")
+	ErrInvalidFilenameParser   = errors.New("invalid filename parser")
+	ErrInvalidTracing          = errors.New("invalid tracing settings")
+	ErrInvalidTooLargeAction   = errors.New("too_large_action must be skip, stub or split")
+	ErrInvalidOutputFormat     = errors.New("output_format must be md, txt or org")
+	ErrInvalidJob              = errors.New("invalid job")
+	ErrInvalidUsage            = errors.New("invalid usage settings")
+	ErrInvalidProvider         = errors.New("provider must be whisper_asr, grpc, azure, google, aws, deepgram or assemblyai")
+	ErrInvalidProviderSettings = errors.New("invalid provider settings")
+	ErrInvalidFallback         = errors.New("invalid fallback")
+)
+
+// Load reads the transcription configuration from the vault's .nota/transcribe.json file.
+// It uses vault.FindVaultRoot to locate the vault, then reads and parses the config.
+// Paths are resolved as in LoadFromVault.
+func Load() (*Config, error) {
+	vaultRoot, err := vault.FindVaultRoot()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFromVault(vaultRoot)
+}
+
+// LoadFromVault reads the transcription configuration from a specific vault path.
+// The profile named by $NOTA_PROFILE, or else the one matching this machine's
+// hostname, is applied over the top-level settings. Paths starting with
+// ${VAULT} are resolved against vaultRoot, template names are resolved
+// against the vault's .nota/templates directory, and paths containing ~ are
+// expanded to the user's home directory.
+func LoadFromVault(vaultRoot string) (*Config, error) {
+	configPath := filepath.Join(vaultRoot, vault.VaultMarkerDir, ConfigFileName)
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if err := cfg.applyProfile(os.Getenv(ProfileEnv)); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	cfg.resolveVaultPaths(vaultRoot)
+	cfg.expandPaths()
+	cfg.vaultRoot = vaultRoot
+	return cfg, nil
+}
+
+// ParseConfig decodes the contents of a transcribe.json file as LoadFromVault
+// does, but applies no profile and leaves ${VAULT} and ~ in paths unresolved,
+// so the result can be saved back as written.
+func ParseConfig(data []byte) (*Config, error) {
+	cfg, warnings, err := decodeConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	cfg.warnings = warnings
+	return cfg, nil
+}
+
+// Save writes the configuration to the vault's .nota/transcribe.json file.
+// It uses vault.FindVaultRoot to locate the vault.
+// The file is created with 0644 permissions.
+func (c *Config) Save() error {
+	vaultRoot, err := vault.FindVaultRoot()
+	if err != nil {
+		return err
+	}
+	return c.SaveToVault(vaultRoot)
+}
+
+// SaveToVault writes the configuration to a specific vault path.
+// The file is created with 0644 permissions.
+func (c *Config) SaveToVault(vaultRoot string) error {
+	configPath := filepath.Join(vaultRoot, vault.VaultMarkerDir, ConfigFileName)
+
+	c.SchemaVersion = CurrentSchemaVersion
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(configPath, data, 0644)
+}
+
+// Validate checks that all required fields are present and values are in
+// range, and normalizes api_url. Returns an error if any check fails.
+func (c *Config) Validate() error {
+	if c.WatchDir == "" && len(c.WatchDirs) == 0 && c.Source == nil {
+		return ErrWatchDirRequired
+	}
+	for _, wd := range c.WatchDirs {
+		if wd.Path == "" {
+			return ErrWatchDirPath
+		}
+	}
+	if err := validateWatchPaths(c.Watches()); err != nil {
+		return err
+	}
+	if err := c.validateAPI(); err != nil {
+		return err
+	}
+	if err := c.validateFallbacks(); err != nil {
+		return err
+	}
+	if c.OutputDir == "" {
+		return ErrOutputDirRequired
+	}
+	if c.QueueOrder != "" && !c.QueueOrder.Valid() {
+		return ErrInvalidQueueOrder
+	}
+	if c.TooLargeAction != "" && !c.TooLargeAction.Valid() {
+		return ErrInvalidTooLargeAction
+	}
+	if !stabilizer.LockMode(c.StabilizationLock).Valid() {
+		return ErrInvalidLockMode
+	}
+	if !c.OutputFormat.Valid() {
+		return ErrInvalidOutputFormat
+	}
+	if _, ok := writer.LookupLocale(c.Locale); !ok {
+		return fmt.Errorf("%w %q (supported: %s)", ErrInvalidLocale, c.Locale, strings.Join(writer.Locales(), ", "))
+	}
+	if err := c.validateRanges(); err != nil {
+		return err
+	}
+	if _, err := newRouter(c.Routes); err != nil {
+		return err
+	}
+	if _, err := newDeviceDetector(c.FilenameParsers); err != nil {
+		return err
+	}
+	if _, err := newSchedule(c.scheduleConfig()); err != nil {
+		return err
+	}
+	if _, err := c.Permissions(); err != nil {
+		return err
+	}
+	if c.Webhook != nil {
+		if err := c.Webhook.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Syncthing != nil {
+		if err := c.Syncthing.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Source != nil {
+		if err := c.Source.validate(); err != nil {
+			return err
+		}
+	}
+	if err := c.validateTitle(); err != nil {
+		return err
+	}
+	if err := c.validateTags(); err != nil {
+		return err
+	}
+	if c.LLM != nil {
+		if err := c.LLM.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Redact != nil {
+		if _, err := c.Redact.redactor(); err != nil {
+			return err
+		}
+	}
+	if c.Subtitles != "" && !c.Subtitles.Valid() {
+		return ErrInvalidSubtitles
+	}
+	if c.Tracing != nil {
+		if err := c.Tracing.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Usage != nil {
+		if err := c.Usage.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Compare != nil {
+		if err := c.Compare.validate(); err != nil {
+			return err
+		}
+	}
+	if err := c.validateJobs(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Permissions returns the mode and group settings for created notes,
+// archived audio and their directories.
+func (c *Config) Permissions() (fileperm.Permissions, error) {
+	fileMode, err := fileperm.ParseMode(c.FileMode)
+	if err != nil {
+		return fileperm.Permissions{}, fmt.Errorf("%w: file_mode: %v", ErrInvalidPermission, err)
+	}
+	dirMode, err := fileperm.ParseMode(c.DirMode)
+	if err != nil {
+		return fileperm.Permissions{}, fmt.Errorf("%w: dir_mode: %v", ErrInvalidPermission, err)
+	}
+	perms := fileperm.Permissions{FileMode: fileMode, DirMode: dirMode, Group: c.Group}
+	if err := perms.Validate(); err != nil {
+		return fileperm.Permissions{}, fmt.Errorf("%w: group: %v", ErrInvalidPermission, err)
+	}
+	return perms, nil
+}
+
+// scheduleConfig returns the schedule settings, or the zero value (always
+// open) when none are configured.
+func (c *Config) scheduleConfig() ScheduleConfig {
+	if c.Schedule == nil {
+		return ScheduleConfig{}
+	}
+	return *c.Schedule
+}
+
+// NormalizeAPIURL checks that raw is an http or https URL with a host and
+// returns it with the scheme and host lowercased and trailing slashes removed.
+func NormalizeAPIURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		return "", fmt.Errorf("%w: %q has no scheme; did you mean %q?", ErrInvalidAPIURL, raw, "http://"+raw)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidAPIURL, err)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%w: scheme %q is not http or https", ErrInvalidAPIURL, u.Scheme)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("%w: %q has no host", ErrInvalidAPIURL, raw)
+	}
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// validateRanges checks that numeric settings are in range. Zero means the
+// default, so only negative values are rejected.
+func (c *Config) validateRanges() error {
+	var sourcePoll int
+	if c.Source != nil {
+		sourcePoll = c.Source.PollIntervalSeconds
+	}
+	var h HTTPConfig
+	if c.HTTP != nil {
+		h = *c.HTTP
+	}
+	values := []struct {
+		name  string
+		value int
+	}{
+		{"watch_buffer_size", c.WatchBufferSize},
+		{"stabilization_interval_ms", c.StabilizationIntervalMs},
+		{"stabilization_checks", c.StabilizationChecks},
+		{"min_file_size_kb", c.MinFileSizeKB},
+		{"min_duration_seconds", c.MinDurationSeconds},
+		{"max_file_size_mb", c.MaxFileSizeMB},
+		{"min_free_space_mb", c.MinFreeSpaceMB},
+		{"retry_count", c.RetryCount},
+		{"workers", c.Workers},
+		{"upload_limit_kb_per_second", c.UploadLimitKBPerSecond},
+		{"http max_idle_conns", h.MaxIdleConns},
+		{"http idle_timeout_seconds", h.IdleTimeoutSeconds},
+		{"http keep_alive_seconds", h.KeepAliveSeconds},
+		{"file_timeout_minutes", c.FileTimeoutMinutes},
+		{"schedule check_interval_seconds", c.scheduleConfig().CheckIntervalSeconds},
+		{"source poll_interval_seconds", sourcePoll},
+		{"max_tags", c.MaxTags},
+		{"merge_window_minutes", c.MergeWindowMinutes},
+		{"split_chunk_minutes", c.SplitChunkMinutes},
+	}
+	for _, v := range values {
+		if v.value < 0 {
+			return fmt.Errorf("%s: %w (got %d)", v.name, ErrNegativeValue, v.value)
+		}
+	}
+	for i, rule := range c.Routes {
+		if rule.MinDurationSeconds < 0 || rule.MaxDurationSeconds < 0 {
+			return fmt.Errorf("route %s duration: %w", rule.label(i), ErrNegativeValue)
+		}
+	}
+	return nil
+}
+
+// ApplyDefaults sets default values for optional fields that are empty or zero.
+// Call this after creating a new Config to ensure all optional fields have sensible defaults.
+func (c *Config) ApplyDefaults() {
+	if c.ArchiveDir == "" {
+		c.ArchiveDir = DefaultArchiveDir
+	}
+	if len(c.WatchPatterns) == 0 {
+		c.WatchPatterns = DefaultWatchPatterns
+	}
+	if c.StabilizationIntervalMs == 0 {
+		c.StabilizationIntervalMs = DefaultStabilizationIntervalMs
+	}
+	if c.StabilizationChecks == 0 {
+		c.StabilizationChecks = DefaultStabilizationChecks
+	}
+	if c.Language == "" {
+		c.Language = DefaultLanguage
+	}
+	if c.Model == "" {
+		c.Model = DefaultModel
+	}
+	if c.Locale == "" {
+		c.Locale = DefaultLocale
+	}
+	if c.OutputFormat == "" {
+		c.OutputFormat = DefaultOutputFormat
+	}
+	if c.MaxFileSizeMB == 0 {
+		c.MaxFileSizeMB = DefaultMaxFileSizeMB
+	}
+	if c.TooLargeAction == "" {
+		c.TooLargeAction = DefaultTooLargeAction
+	}
+	if c.SplitChunkMinutes == 0 {
+		c.SplitChunkMinutes = DefaultSplitChunkMinutes
+	}
+	if c.MinFreeSpaceMB == 0 {
+		c.MinFreeSpaceMB = DefaultMinFreeSpaceMB
+	}
+	if c.RetryCount == 0 {
+		c.RetryCount = DefaultRetryCount
+	}
+	if c.Workers == 0 {
+		c.Workers = DefaultWorkers
+	}
+	if c.QueueOrder == "" {
+		c.QueueOrder = DefaultQueueOrder
+	}
+	if c.Provider == "" {
+		c.Provider = DefaultProvider
+	}
+	if c.FileTimeoutMinutes == 0 {
+		c.FileTimeoutMinutes = DefaultFileTimeoutMinutes
+	}
+	if c.WatchBufferSize == 0 {
+		c.WatchBufferSize = DefaultWatchBufferSize
+	}
+	if c.MaxTags == 0 {
+		c.MaxTags = DefaultMaxTags
+	}
+}
+
+// expandPaths expands ~ to the user's home directory in path fields.
+func (c *Config) expandPaths() {
+	c.mapPaths(expandTilde)
+}
+
+// resolveVaultPaths replaces a leading ${VAULT} in path fields with vaultRoot
+// and bare template names with the matching file in .nota/templates.
+func (c *Config) resolveVaultPaths(vaultRoot string) {
+	c.mapPaths(func(path string) string {
+		return expandVault(path, vaultRoot)
+	})
+	if c.TemplatePath != nil {
+		resolved := ResolveTemplate(*c.TemplatePath, vaultRoot)
+		c.TemplatePath = &resolved
+	}
+	for i := range c.Routes {
+		c.Routes[i].TemplatePath = ResolveTemplate(c.Routes[i].TemplatePath, vaultRoot)
+	}
+}
+
+// mapPaths replaces every path field with fn applied to it.
+func (c *Config) mapPaths(fn func(string) string) {
+	c.WatchDir = fn(c.WatchDir)
+	for i := range c.WatchDirs {
+		c.WatchDirs[i].Path = fn(c.WatchDirs[i].Path)
+	}
+	c.OutputDir = fn(c.OutputDir)
+	c.ArchiveDir = fn(c.ArchiveDir)
+	if c.TemplatePath != nil {
+		expanded := fn(*c.TemplatePath)
+		c.TemplatePath = &expanded
+	}
+	for i := range c.Routes {
+		c.Routes[i].SourceFolder = fn(c.Routes[i].SourceFolder)
+		c.Routes[i].OutputDir = fn(c.Routes[i].OutputDir)
+		c.Routes[i].TemplatePath = fn(c.Routes[i].TemplatePath)
+	}
+	if c.Source != nil {
+		c.Source.DownloadDir = fn(c.Source.DownloadDir)
+	}
+	if c.Google != nil {
+		c.Google.CredentialsFile = fn(c.Google.CredentialsFile)
+	}
+	for _, f := range c.Fallbacks {
+		if f.Google != nil {
+			f.Google.CredentialsFile = fn(f.Google.CredentialsFile)
+		}
+	}
+}
+
+// expandVault replaces a leading ${VAULT} in path with vaultRoot.
+func expandVault(path, vaultRoot string) string {
+	if path == VaultVariable {
+		return vaultRoot
+	}
+	if rest, ok := strings.CutPrefix(path, VaultVariable+"/"); ok {
+		return filepath.Join(vaultRoot, rest)
+	}
+	return path
+}
+
+// ResolvePath resolves ${VAULT} and ~ in a configured path the way
+// LoadFromVault does.
+func ResolvePath(path, vaultRoot string) string {
+	return expandTilde(expandVault(path, vaultRoot))
+}
+
+// ResolveTemplate resolves a template_path value. A bare name such as
+// "voice-note" (no directory separators, optionally ending in .md) refers to
+// the template of that name in the vault's .nota/templates directory; any
+// other value is resolved as a path with ResolvePath.
+func ResolveTemplate(path, vaultRoot string) string {
+	if isTemplateName(path) {
+		return vault.TemplatePath(vaultRoot, strings.TrimSuffix(path, ".md"))
+	}
+	return ResolvePath(path, vaultRoot)
+}
+
+// isTemplateName reports whether a template_path value is a bare template
+// name rather than a path.
+func isTemplateName(path string) bool {
+	if path == "" || path == "." || path == ".." || path == "~" || path == VaultVariable {
+		return false
+	}
+	return !strings.ContainsAny(path, `/\`)
+}
+
+// VaultRelative returns path with the vault root replaced by ${VAULT} when
+// path lies inside the vault, and path unchanged otherwise.
+func VaultRelative(path, vaultRoot string) string {
+	rel, err := filepath.Rel(vaultRoot, path)
+	if err != nil || !filepath.IsAbs(path) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	if rel == "." {
+		return VaultVariable
+	}
+	return VaultVariable + "/" + filepath.ToSlash(rel)
+}
+
+// expandTilde expands ~ at the beginning of a path to the user's home directory.
+func expandTilde(path string) string {
+	if path == "" {
+		return path
+	}
+	if path == "~" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return path
+		}
+		return home
+	}
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return path
+		}
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
@@ -2,9 +2,14 @@ package transcribe
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
 )
 
 func setupTestVault(t *testing.T) string {
@@ -157,6 +162,110 @@ func TestLoadFromVault_ExpandsTildeTemplatePath(t *testing.T) {
 	}
 }
 
+func TestLoadFromVault_ResolvesVaultPaths(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+
+	templatePath := "${VAULT}/Templates/voice-note.md"
+	cfg := &Config{
+		WatchDir:     "/mnt/sync",
+		APIURL:       "http://nas:9000/asr",
+		OutputDir:    "${VAULT}/Inbox",
+		ArchiveDir:   "${VAULT}",
+		TemplatePath: &templatePath,
+		Routes:       []RouteRule{{SourceFolder: "work", OutputDir: "${VAULT}/Areas/Work"}},
+	}
+
+	configPath := filepath.Join(vaultRoot, ".nota", ConfigFileName)
+	data, _ := json.MarshalIndent(cfg, "", "  ")
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	loaded, err := LoadFromVault(vaultRoot)
+	if err != nil {
+		t.Fatalf("LoadFromVault failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"OutputDir", loaded.OutputDir, filepath.Join(vaultRoot, "Inbox")},
+		{"ArchiveDir", loaded.ArchiveDir, vaultRoot},
+		{"TemplatePath", *loaded.TemplatePath, filepath.Join(vaultRoot, "Templates", "voice-note.md")},
+		{"Routes[0].OutputDir", loaded.Routes[0].OutputDir, filepath.Join(vaultRoot, "Areas", "Work")},
+		{"WatchDir", loaded.WatchDir, "/mnt/sync"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("expected %s %q, got %q", tt.name, tt.expected, tt.got)
+		}
+	}
+}
+
+func TestLoadFromVault_ResolvesTemplateNames(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+
+	templateName := "voice-note"
+	cfg := &Config{
+		WatchDir:     "/mnt/sync",
+		APIURL:       "http://nas:9000/asr",
+		OutputDir:    "/tmp/output",
+		TemplatePath: &templateName,
+		Routes: []RouteRule{
+			{SourceFolder: "work", TemplatePath: "meeting.md"},
+			{SourceFolder: "lectures", TemplatePath: "/opt/templates/lecture.md"},
+		},
+	}
+
+	configPath := filepath.Join(vaultRoot, ".nota", ConfigFileName)
+	data, _ := json.MarshalIndent(cfg, "", "  ")
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	loaded, err := LoadFromVault(vaultRoot)
+	if err != nil {
+		t.Fatalf("LoadFromVault failed: %v", err)
+	}
+
+	templatesDir := filepath.Join(vaultRoot, ".nota", "templates")
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"TemplatePath", *loaded.TemplatePath, filepath.Join(templatesDir, "voice-note.md")},
+		{"Routes[0].TemplatePath", loaded.Routes[0].TemplatePath, filepath.Join(templatesDir, "meeting.md")},
+		{"Routes[1].TemplatePath", loaded.Routes[1].TemplatePath, "/opt/templates/lecture.md"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("expected %s %q, got %q", tt.name, tt.expected, tt.got)
+		}
+	}
+}
+
+func TestVaultRelative(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/home/user/vault/Inbox", "${VAULT}/Inbox"},
+		{"/home/user/vault", "${VAULT}"},
+		{"/home/user/vault-old/Inbox", "/home/user/vault-old/Inbox"},
+		{"/mnt/sync", "/mnt/sync"},
+		{"~/.nota/archive/audio", "~/.nota/archive/audio"},
+	}
+
+	for _, tt := range tests {
+		if got := VaultRelative(tt.path, "/home/user/vault"); got != tt.expected {
+			t.Errorf("VaultRelative(%q): expected %q, got %q", tt.path, tt.expected, got)
+		}
+	}
+}
+
 func TestSaveToVault_Success(t *testing.T) {
 	vaultRoot := setupTestVault(t)
 
@@ -270,6 +379,271 @@ func TestValidate_MissingOutputDir(t *testing.T) {
 	}
 }
 
+func TestValidate_InvalidQueueOrder(t *testing.T) {
+	cfg := &Config{
+		WatchDir:   "/mnt/sync/voice-notes",
+		APIURL:     "http://nas:9000/asr",
+		OutputDir:  "/home/user/vault/Inbox",
+		QueueOrder: "largest_first",
+	}
+
+	err := cfg.Validate()
+	if err != ErrInvalidQueueOrder {
+		t.Errorf("expected ErrInvalidQueueOrder, got: %v", err)
+	}
+}
+
+func TestValidate_InvalidStabilizationLock(t *testing.T) {
+	cfg := &Config{
+		WatchDir:          "/mnt/sync/voice-notes",
+		APIURL:            "http://nas:9000/asr",
+		OutputDir:         "/home/user/vault/Inbox",
+		StabilizationLock: "mandatory",
+	}
+
+	err := cfg.Validate()
+	if err != ErrInvalidLockMode {
+		t.Errorf("expected ErrInvalidLockMode, got: %v", err)
+	}
+}
+
+func TestValidate_InvalidOutputFormat(t *testing.T) {
+	cfg := &Config{
+		WatchDir:     "/mnt/sync/voice-notes",
+		APIURL:       "http://nas:9000/asr",
+		OutputDir:    "/home/user/vault/Inbox",
+		OutputFormat: "docx",
+	}
+
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidOutputFormat) {
+		t.Errorf("expected ErrInvalidOutputFormat, got: %v", err)
+	}
+
+	cfg.OutputFormat = writer.FormatOrg
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
+
+func TestValidate_InvalidLocale(t *testing.T) {
+	cfg := &Config{
+		WatchDir:  "/mnt/sync/voice-notes",
+		APIURL:    "http://nas:9000/asr",
+		OutputDir: "/home/user/vault/Inbox",
+		Locale:    "klingon",
+	}
+
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidLocale) {
+		t.Errorf("expected ErrInvalidLocale, got: %v", err)
+	}
+
+	cfg.Locale = "de-AT"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
+
+func TestValidate_InvalidSchedule(t *testing.T) {
+	cfg := &Config{
+		WatchDir:  "/mnt/sync/voice-notes",
+		APIURL:    "http://nas:9000/asr",
+		OutputDir: "/home/user/vault/Inbox",
+		Schedule:  &ScheduleConfig{ActiveHours: "morning"},
+	}
+
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidSchedule) {
+		t.Errorf("expected ErrInvalidSchedule, got: %v", err)
+	}
+
+	cfg.Schedule = &ScheduleConfig{ActiveHours: "07:00-23:00", CheckIntervalSeconds: -1}
+	if err := cfg.Validate(); !errors.Is(err, ErrNegativeValue) {
+		t.Errorf("expected ErrNegativeValue, got: %v", err)
+	}
+}
+
+func TestValidate_InvalidPermissions(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"file_mode", Config{FileMode: "rw-rw-r--"}},
+		{"dir_mode", Config{DirMode: "0888"}},
+		{"group", Config{Group: "no-such-group-nota"}},
+	}
+
+	for _, tt := range tests {
+		cfg := tt.cfg
+		cfg.WatchDir = "/mnt/sync/voice-notes"
+		cfg.APIURL = "http://nas:9000/asr"
+		cfg.OutputDir = "/home/user/vault/Inbox"
+		if err := cfg.Validate(); !errors.Is(err, ErrInvalidPermission) {
+			t.Errorf("%s: expected ErrInvalidPermission, got: %v", tt.name, err)
+		}
+	}
+}
+
+func TestValidate_InvalidAPIURL(t *testing.T) {
+	for _, apiURL := range []string{"nas:9000", "localhost", "http://", "://nas:9000", "ftp://nas/asr"} {
+		cfg := &Config{
+			WatchDir:  "/home/user/recordings",
+			APIURL:    apiURL,
+			OutputDir: "/home/user/vault/Inbox",
+		}
+		if err := cfg.Validate(); !errors.Is(err, ErrInvalidAPIURL) {
+			t.Errorf("%q: expected ErrInvalidAPIURL, got: %v", apiURL, err)
+		}
+	}
+}
+
+func TestNormalizeAPIURL(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"http://nas:9000/asr", "http://nas:9000/asr"},
+		{"http://nas:9000/asr/", "http://nas:9000/asr"},
+		{"HTTPS://NAS.local:9000//", "https://nas.local:9000"},
+		{" http://nas:9000/asr?encode=true ", "http://nas:9000/asr?encode=true"},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeAPIURL(tt.raw)
+		if err != nil {
+			t.Errorf("NormalizeAPIURL(%q): expected no error, got: %v", tt.raw, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("NormalizeAPIURL(%q): expected %q, got %q", tt.raw, tt.expected, got)
+		}
+	}
+}
+
+func TestNormalizeAPIURL_SuggestsScheme(t *testing.T) {
+	_, err := NormalizeAPIURL("nas:9000/asr")
+	if err == nil || !strings.Contains(err.Error(), `"http://nas:9000/asr"`) {
+		t.Errorf("expected error suggesting http://nas:9000/asr, got: %v", err)
+	}
+}
+
+func TestValidate_NegativeValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"workers", func(c *Config) { c.Workers = -1 }},
+		{"retry_count", func(c *Config) { c.RetryCount = -2 }},
+		{"max_file_size_mb", func(c *Config) { c.MaxFileSizeMB = -100 }},
+		{"stabilization_interval_ms", func(c *Config) { c.StabilizationIntervalMs = -1 }},
+		{"file_timeout_minutes", func(c *Config) { c.FileTimeoutMinutes = -5 }},
+		{"upload_limit_kb_per_second", func(c *Config) { c.UploadLimitKBPerSecond = -1 }},
+		{"duration", func(c *Config) {
+			c.Routes = []RouteRule{{MinDurationSeconds: -1, OutputDir: "/vault/x"}}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				WatchDir:  "/home/user/recordings",
+				APIURL:    "http://nas:9000/asr",
+				OutputDir: "/home/user/vault/Inbox",
+			}
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if !errors.Is(err, ErrNegativeValue) {
+				t.Fatalf("expected ErrNegativeValue, got: %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.name) {
+				t.Errorf("expected error to name %s, got: %v", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestValidate_InvalidRoute(t *testing.T) {
+	cfg := &Config{
+		WatchDir:  "/home/user/recordings",
+		APIURL:    "http://nas:9000/asr",
+		OutputDir: "/home/user/vault/Inbox",
+		Routes:    []RouteRule{{Name: "work", FilenameRegex: "[", OutputDir: "/home/user/vault/Work"}},
+	}
+
+	err := cfg.Validate()
+	if !errors.Is(err, ErrInvalidRoute) {
+		t.Errorf("expected ErrInvalidRoute, got: %v", err)
+	}
+}
+
+func TestValidate_WatchDirsOnly(t *testing.T) {
+	cfg := &Config{
+		WatchDirs: []WatchDirConfig{{Path: "/mnt/sync/phone"}},
+		APIURL:    "http://nas:9000/asr",
+		OutputDir: "/home/user/vault/Inbox",
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	cfg.WatchDirs = append(cfg.WatchDirs, WatchDirConfig{Patterns: []string{"*.wav"}})
+	if err := cfg.Validate(); err != ErrWatchDirPath {
+		t.Errorf("expected ErrWatchDirPath, got: %v", err)
+	}
+}
+
+func TestValidate_DuplicateWatchDirs(t *testing.T) {
+	dir := t.TempDir()
+	linked := filepath.Join(t.TempDir(), "phone")
+	if err := os.Symlink(dir, linked); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		watchDir  string
+		watchDirs []WatchDirConfig
+	}{
+		{"watch_dir repeated", dir, []WatchDirConfig{{Path: dir + "/", Patterns: []string{"*.wav"}}}},
+		{"watch_dirs repeated", "", []WatchDirConfig{{Path: "/mnt/sync/phone"}, {Path: "/mnt/sync/./phone"}}},
+		{"symlink to a watched directory", "", []WatchDirConfig{{Path: dir}, {Path: linked}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				WatchDir:  tt.watchDir,
+				WatchDirs: tt.watchDirs,
+				APIURL:    "http://nas:9000/asr",
+				OutputDir: "/home/user/vault/Inbox",
+			}
+			if err := cfg.Validate(); !errors.Is(err, ErrWatchDirDuplicate) {
+				t.Errorf("expected ErrWatchDirDuplicate, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_Watches(t *testing.T) {
+	cfg := &Config{
+		WatchDir:      "/mnt/sync/phone",
+		WatchPatterns: []string{"*.m4a"},
+		WatchDirs: []WatchDirConfig{
+			{Path: "/mnt/sync/tablet", Patterns: []string{"*.wav"}},
+			{Path: "/mnt/sync/laptop"},
+		},
+	}
+
+	watches := cfg.Watches()
+	expected := []WatchDirConfig{
+		{Path: "/mnt/sync/phone", Patterns: []string{"*.m4a"}},
+		{Path: "/mnt/sync/tablet", Patterns: []string{"*.wav"}},
+		{Path: "/mnt/sync/laptop", Patterns: []string{"*.m4a"}},
+	}
+	if !reflect.DeepEqual(watches, expected) {
+		t.Errorf("expected %+v, got %+v", expected, watches)
+	}
+}
+
 func TestApplyDefaults_SetsAllDefaults(t *testing.T) {
 	cfg := &Config{
 		WatchDir:  "/mnt/sync/voice-notes",
@@ -297,12 +671,36 @@ func TestApplyDefaults_SetsAllDefaults(t *testing.T) {
 	if cfg.Model != DefaultModel {
 		t.Errorf("expected Model %q, got %q", DefaultModel, cfg.Model)
 	}
+	if cfg.Locale != DefaultLocale {
+		t.Errorf("expected Locale %q, got %q", DefaultLocale, cfg.Locale)
+	}
 	if cfg.MaxFileSizeMB != DefaultMaxFileSizeMB {
 		t.Errorf("expected MaxFileSizeMB %d, got %d", DefaultMaxFileSizeMB, cfg.MaxFileSizeMB)
 	}
+	if cfg.TooLargeAction != DefaultTooLargeAction {
+		t.Errorf("expected TooLargeAction %q, got %q", DefaultTooLargeAction, cfg.TooLargeAction)
+	}
+	if cfg.SplitChunkMinutes != DefaultSplitChunkMinutes {
+		t.Errorf("expected SplitChunkMinutes %d, got %d", DefaultSplitChunkMinutes, cfg.SplitChunkMinutes)
+	}
+	if cfg.MinFreeSpaceMB != DefaultMinFreeSpaceMB {
+		t.Errorf("expected MinFreeSpaceMB %d, got %d", DefaultMinFreeSpaceMB, cfg.MinFreeSpaceMB)
+	}
 	if cfg.RetryCount != DefaultRetryCount {
 		t.Errorf("expected RetryCount %d, got %d", DefaultRetryCount, cfg.RetryCount)
 	}
+	if cfg.Workers != DefaultWorkers {
+		t.Errorf("expected Workers %d, got %d", DefaultWorkers, cfg.Workers)
+	}
+	if cfg.QueueOrder != DefaultQueueOrder {
+		t.Errorf("expected QueueOrder %q, got %q", DefaultQueueOrder, cfg.QueueOrder)
+	}
+	if cfg.FileTimeoutMinutes != DefaultFileTimeoutMinutes {
+		t.Errorf("expected FileTimeoutMinutes %d, got %d", DefaultFileTimeoutMinutes, cfg.FileTimeoutMinutes)
+	}
+	if cfg.WatchBufferSize != DefaultWatchBufferSize {
+		t.Errorf("expected WatchBufferSize %d, got %d", DefaultWatchBufferSize, cfg.WatchBufferSize)
+	}
 }
 
 func TestApplyDefaults_PreservesExistingValues(t *testing.T) {
@@ -0,0 +1,199 @@
+// Package control serves the running service's live state over a Unix
+// socket in the state directory, so commands such as
+// `nota transcribe status --watch` can show what the daemon is doing now
+// rather than what it last logged.
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/dirs"
+)
+
+// SocketName is the control socket's file name in the state directory.
+const SocketName = "transcribe.sock"
+
+// StatusPath is the endpoint serving the Snapshot as JSON.
+const StatusPath = "/status"
+
+// ErrNotRunning is returned by Fetch when nothing is listening on the socket.
+var ErrNotRunning = errors.New("transcription service is not running")
+
+// SocketPath returns the path to the control socket in the state directory
+// ($XDG_STATE_HOME/nota/transcribe.sock or ~/.nota/transcribe.sock).
+func SocketPath() (string, error) {
+	dir, err := dirs.State()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, SocketName), nil
+}
+
+// FileState is a file in the pipeline.
+type FileState struct {
+	Path  string `json:"path"`
+	Stage string `json:"stage"`
+	// Since is when the file entered the pipeline.
+	Since time.Time `json:"since"`
+}
+
+// Completion is a file that has left the pipeline.
+type Completion struct {
+	Time     time.Time `json:"time"`
+	Path     string    `json:"path"`
+	Status   string    `json:"status"`
+	Category string    `json:"category,omitempty"`
+	Output   string    `json:"output,omitempty"`
+	// Stage is the last pipeline stage the file completed.
+	Stage string `json:"stage,omitempty"`
+}
+
+// JobState is a scheduled vault job.
+type JobState struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Schedule string `json:"schedule"`
+	// Next is when the job next runs; zero if its schedule never fires.
+	Next    time.Time `json:"next"`
+	Running bool      `json:"running"`
+	// LastRun is when the last run finished; zero if it has not run since
+	// the service started.
+	LastRun    time.Time `json:"last_run"`
+	LastResult string    `json:"last_result,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+// Snapshot is the service's state at one moment. Counts cover the time since
+// Started.
+type Snapshot struct {
+	Started time.Time `json:"started"`
+	// Queued is how many files are waiting for a worker or the schedule.
+	Queued    int         `json:"queued"`
+	InFlight  []FileState `json:"in_flight"`
+	Completed int         `json:"completed"`
+	Failed    int         `json:"failed"`
+	Skipped   int         `json:"skipped"`
+	// Errors counts failures by history category.
+	Errors map[string]int `json:"errors,omitempty"`
+	// Recent lists the latest completions, newest first.
+	Recent []Completion `json:"recent"`
+	// Jobs lists the scheduled jobs in configuration order.
+	Jobs []JobState `json:"jobs,omitempty"`
+}
+
+// Server answers GET /status with the state returned by Snapshot.
+type Server struct {
+	Snapshot func() Snapshot
+}
+
+// Listen binds the control socket at path, replacing a socket left behind by
+// a service that did not shut down cleanly. Only the owner may connect.
+func Listen(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("control socket %s is in use", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// Serve handles requests on ln until ctx is cancelled. The socket file is
+// removed when the listener closes.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+		<-errCh
+	case err = <-errCh:
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != StatusPath {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.Snapshot())
+}
+
+// Fetch reads the running service's state from the socket at path.
+func Fetch(ctx context.Context, path string) (*Snapshot, error) {
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://nota"+StatusPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return nil, ErrNotRunning
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("control socket: status %d", resp.StatusCode)
+	}
+	var snap Snapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("decode status: %w", err)
+	}
+	return &snap, nil
+}
@@ -0,0 +1,105 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestServeAndFetch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SocketName)
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	started := time.Date(2026, 1, 22, 9, 0, 0, 0, time.UTC)
+	srv := &Server{Snapshot: func() Snapshot {
+		return Snapshot{
+			Started:   started,
+			Queued:    1,
+			InFlight:  []FileState{{Path: "/in/memo.m4a", Stage: "queued", Since: started}},
+			Completed: 3,
+			Errors:    map[string]int{"api_unreachable": 1},
+		}
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, ln) }()
+
+	snap, err := Fetch(context.Background(), path)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !snap.Started.Equal(started) || snap.Queued != 1 || len(snap.InFlight) != 1 || snap.Completed != 3 || snap.Errors["api_unreachable"] != 1 {
+		t.Errorf("unexpected snapshot: %+v", snap)
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected a socket only the owner can use, got %v, %v", info, err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("expected clean shutdown, got: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected the socket to be removed on shutdown")
+	}
+}
+
+func TestFetch_NotRunning(t *testing.T) {
+	_, err := Fetch(context.Background(), filepath.Join(t.TempDir(), SocketName))
+	if !errors.Is(err, ErrNotRunning) {
+		t.Errorf("expected ErrNotRunning, got: %v", err)
+	}
+}
+
+func TestListen_ReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SocketName)
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer ln.Close()
+
+	if _, err := Listen(path); err == nil {
+		t.Error("expected an error for a socket in use")
+	}
+}
+
+func TestServer_RejectsOtherRequests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SocketName)
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go (&Server{Snapshot: func() Snapshot { return Snapshot{} }}).Serve(ctx, ln)
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Post("http://nota"+StatusPath, "application/json", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,195 @@
+// Package cron parses cron-like schedule expressions and computes when they
+// next fire.
+//
+// An expression has five space-separated fields, minute (0-59), hour (0-23),
+// day of month (1-31), month (1-12) and day of week (0-6, Sunday is 0 or 7),
+// each a *, a number, a range such as 1-5, or a comma-separated list of
+// those, optionally stepped with /n. As in cron, when both day fields are
+// restricted a day matching either one fires. The shorthands @hourly,
+// @daily (or @midnight), @weekly, @monthly and @yearly (or @annually) are
+// accepted, as is "@every <duration>", e.g. "@every 30m".
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalid is returned for expressions that cannot be parsed.
+var ErrInvalid = errors.New("invalid cron expression")
+
+// shorthands maps the @ shorthands to their expressions.
+var shorthands = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field bounds, in the order fields appear.
+var bounds = [5]struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// maxSearch bounds how far ahead Next looks; an expression such as
+// "0 0 31 2 *" never fires.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Schedule is a parsed expression.
+type Schedule struct {
+	expr string
+	// every is the interval of an @every schedule, which ignores the fields.
+	every time.Duration
+	// fields are bit sets of the allowed values, in bounds order.
+	fields [5]uint64
+	// domStar and dowStar record unrestricted day fields, for cron's rule
+	// that restricted day fields match either.
+	domStar, dowStar bool
+}
+
+// Parse parses a cron expression.
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	s := &Schedule{expr: expr}
+
+	if rest, ok := strings.CutPrefix(expr, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || d < time.Minute {
+			return nil, fmt.Errorf("%w %q: @every needs a duration of at least 1m", ErrInvalid, expr)
+		}
+		s.every = d
+		return s, nil
+	}
+	fields := expr
+	if strings.HasPrefix(expr, "@") {
+		var ok bool
+		if fields, ok = shorthands[expr]; !ok {
+			return nil, fmt.Errorf("%w %q: unknown shorthand", ErrInvalid, expr)
+		}
+	}
+
+	parts := strings.Fields(fields)
+	if len(parts) != len(bounds) {
+		return nil, fmt.Errorf("%w %q: want 5 fields, got %d", ErrInvalid, expr, len(parts))
+	}
+	for i, part := range parts {
+		set, err := parseField(part, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %s: %v", ErrInvalid, expr, bounds[i].name, err)
+		}
+		s.fields[i] = set
+	}
+	// Sunday may be written 7
+	if s.fields[4]&(1<<7) != 0 {
+		s.fields[4] |= 1
+	}
+	s.domStar = parts[2] == "*"
+	s.dowStar = parts[4] == "*"
+	return s, nil
+}
+
+// parseField parses one field into a bit set of the values it allows.
+func parseField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, stepStr, stepped := strings.Cut(item, "/")
+		step := 1
+		if stepped {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			from, to, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("invalid value %q", from)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("invalid value %q", to)
+				}
+			} else if stepped {
+				// n/step runs from n to the end of the range
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", item, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// String returns the expression as written.
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// Next returns the first time after t the schedule fires, in t's location,
+// or the zero time if it never does.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Truncate(time.Second).Add(s.every)
+	}
+
+	// Start at the next whole minute
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxSearch)
+	for t.Before(limit) {
+		if !s.has(3, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.has(1, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.has(0, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// has reports whether field i allows v.
+func (s *Schedule) has(i, v int) bool {
+	return s.fields[i]&(1<<v) != 0
+}
+
+// dayMatches applies cron's day rule: with both day fields restricted,
+// either may match.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.has(2, t.Day())
+	dow := s.has(4, int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
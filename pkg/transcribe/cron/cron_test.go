@@ -0,0 +1,79 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// A Friday
+	from := time.Date(2026, 10, 16, 9, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 16, 9, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 16, 9, 45, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)},
+		{"30 9 * * *", time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC)},
+		{"0 8 * * 1-5", time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)},
+		{"0 10 * * 0", time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)},
+		{"0 10 * * 7", time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Either restricted day field may match
+		{"0 0 1 * 0", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"15,45 * * * *", time.Date(2026, 10, 16, 9, 45, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90m", time.Date(2026, 10, 16, 11, 0, 15, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if got := s.Next(from); !got.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNext_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+10", 10*60*60)
+	s, _ := Parse("0 7 * * *")
+	from := time.Date(2026, 10, 16, 8, 0, 0, 0, loc)
+	if got := s.Next(from); !got.Equal(time.Date(2026, 10, 17, 7, 0, 0, 0, loc)) {
+		t.Errorf("expected 07:00 local the next day, got %v", got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@sometimes",
+		"@every 10s",
+		"@every soon",
+	} {
+		if _, err := Parse(expr); !errors.Is(err, ErrInvalid) {
+			t.Errorf("expected ErrInvalid for %q, got: %v", expr, err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package transcribe
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
+)
+
+// noteIndex maps the hash of each transcribed recording to the note written
+// for it, so a file processed again, for instance after a crash between
+// writing its note and archiving it, is archived without a second note.
+// The history and the output directories are read on first use, and notes
+// written since are added as they are written.
+type noteIndex struct {
+	dirs    []string
+	history *history.Store
+
+	mu     sync.Mutex
+	loaded bool
+	notes  map[string]indexedNote
+}
+
+// indexedNote is a note found for a recording.
+type indexedNote struct {
+	path string
+	// archivePath is where the note says the recording was archived to.
+	archivePath string
+}
+
+func newNoteIndex(dirs []string, hist *history.Store) *noteIndex {
+	return &noteIndex{dirs: dirs, history: hist, notes: make(map[string]indexedNote)}
+}
+
+// lookup returns the note written for the recording with hash, if it still
+// exists.
+func (x *noteIndex) lookup(hash string) (indexedNote, bool) {
+	if hash == "" {
+		return indexedNote{}, false
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if !x.loaded {
+		x.load()
+		x.loaded = true
+	}
+
+	note, ok := x.notes[hash]
+	if !ok {
+		return indexedNote{}, false
+	}
+	if _, err := os.Stat(note.path); err != nil {
+		// Deleted or moved; transcribe the recording again
+		delete(x.notes, hash)
+		return indexedNote{}, false
+	}
+	return note, true
+}
+
+// add records the note written for the recording with hash.
+func (x *noteIndex) add(hash, path, archivePath string) {
+	if hash == "" || path == "" {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.notes[hash] = indexedNote{path: path, archivePath: archivePath}
+}
+
+// load finds the notes recorded in the history with the hash of their
+// recording, the latest for each, which covers every output format. Notes
+// the history does not know, such as those of another machine, are found
+// through the source hashes in their frontmatter, which only markdown notes
+// have: source_hash, or source_hashes for notes merged from several
+// recordings.
+func (x *noteIndex) load() {
+	recorded := make(map[string]string)
+	if x.history != nil {
+		records, _ := x.history.Load(time.Time{})
+		for _, rec := range records {
+			if rec.SourceHash != "" && rec.Output != "" {
+				recorded[rec.SourceHash] = rec.Output
+			}
+		}
+	}
+
+	for _, dir := range x.dirs {
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".md") {
+				return nil
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			info, err := writer.ParseProcessingInfo(string(content))
+			if err != nil {
+				return nil
+			}
+			for _, hash := range info.Hashes() {
+				// The archive path is the first recording's
+				note := indexedNote{path: path}
+				if hash == info.SourceHash {
+					note.archivePath = info.ArchivePath
+				}
+				// Prefer the original note over earlier reprocessed versions
+				if prev, ok := x.notes[hash]; !ok || len(path) < len(prev.path) {
+					x.notes[hash] = note
+				}
+			}
+			return nil
+		})
+	}
+
+	// The history's note wins while it exists; its frontmatter still gives
+	// the archive path
+	for hash, path := range recorded {
+		if x.notes[hash].path == path {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			x.notes[hash] = indexedNote{path: path}
+		}
+	}
+}
+
+// skipExisting archives a recording that already has a note, to the
+// archive path the note links to where that is still free, and records it
+// as skipped.
+func (s *Service) skipExisting(ctx context.Context, fileLogger Logger, event FileEvent, archive bool, note indexedNote, startTime time.Time) error {
+	if archive {
+		archivePath := s.freeArchivePath(event.Path, note.archivePath)
+		if err := s.archiveFile(ctx, event.Path, archivePath); err != nil {
+			return s.failFile(fileLogger, "failed to archive file", event,
+				history.Record{Output: note.path, Category: history.CategoryArchive}, err, startTime)
+		}
+	}
+
+	fileLogger.Info("note already written for recording, skipped without transcribing",
+		logging.String("path", event.Path),
+		logging.String("note", note.path),
+	)
+	s.reportSkipped(event, StageSkipped, startTime, "note already written: "+note.path)
+	s.recordOutcome(event, history.Record{
+		Status:   history.StatusSkipped,
+		Category: history.CategoryNoteExists,
+		Output:   note.path,
+	}, nil, startTime)
+	return nil
+}
+
+// hashFile returns the SHA-256 of the file at path as "sha256:<hex>", or ""
+// if it cannot be read.
+func hashFile(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// outputDirs returns the output directory and those of the routing rules.
+func (s *Service) outputDirs() []string {
+	dirs := []string{s.config.OutputDir}
+	for _, rule := range s.config.Routes {
+		if rule.OutputDir != "" {
+			dirs = append(dirs, rule.OutputDir)
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,217 @@
+package transcribe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
+)
+
+type countingClient struct {
+	calls atomic.Int32
+}
+
+func (c *countingClient) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	c.calls.Add(1)
+	return &TranscriptionResult{Text: "Buy milk.", Language: "en"}, nil
+}
+
+// newDedupService returns a service writing real notes to cfg's output
+// directory and archiving into cfg's archive directory.
+func newDedupService(t *testing.T, cfg *Config, tc TranscriptionClient) *Service {
+	t.Helper()
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{},
+		Stabilizer: fakeStabilizer{},
+		Client:     tc,
+		Logger:     &recordingLogger{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	t.Cleanup(func() { svc.Close() })
+	return svc
+}
+
+func TestProcessFile_ArchivesRecordingWithExistingNote(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+	cfg.ArchiveDir = t.TempDir()
+
+	audioPath := writeWAV(t, cfg.WatchDir, "memo.wav", 2*time.Second)
+	// The note of a run that stopped before archiving
+	archivePath := filepath.Join(cfg.ArchiveDir, "2026", "10", "15", "memo.wav")
+	note, err := writer.NewSimpleWriter().Render("Buy milk.", OutputOptions{
+		SourceFile: audioPath,
+		Processing: &ProcessingInfo{SourcePath: audioPath, ArchivePath: archivePath, SourceHash: hashFile(audioPath)},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	notePath := filepath.Join(cfg.OutputDir, "memo.md")
+	if err := os.WriteFile(notePath, []byte(note), 0644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	tc := &countingClient{}
+	svc := newDedupService(t, cfg, tc)
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{archive: true}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if n := tc.calls.Load(); n != 0 {
+		t.Errorf("expected no transcription, got %d", n)
+	}
+	if _, err := os.Stat(archivePath); err != nil {
+		t.Errorf("expected the recording archived to the note's archive path, got: %v", err)
+	}
+	entries, _ := os.ReadDir(cfg.OutputDir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the existing note, got %d files", len(entries))
+	}
+
+	p, _ := history.DefaultPath()
+	records, _ := history.New(p).Load(time.Time{})
+	if len(records) != 1 {
+		t.Fatalf("expected 1 history record, got %d", len(records))
+	}
+	if records[0].Status != history.StatusSkipped || records[0].Category != history.CategoryNoteExists || records[0].Output != notePath {
+		t.Errorf("expected a skipped %s record for %s, got %+v", history.CategoryNoteExists, notePath, records[0])
+	}
+}
+
+func TestProcessFile_SkipsNoteWrittenThisRun(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+	cfg.ArchiveDir = t.TempDir()
+
+	tc := &countingClient{}
+	svc := newDedupService(t, cfg, tc)
+
+	// Kept in place the first time, as by import --keep
+	audioPath := writeWAV(t, cfg.WatchDir, "memo.wav", 2*time.Second)
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{archive: true}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if n := tc.calls.Load(); n != 1 {
+		t.Errorf("expected 1 transcription, got %d", n)
+	}
+	if _, err := os.Stat(audioPath); !os.IsNotExist(err) {
+		t.Errorf("expected the recording to be archived, got: %v", err)
+	}
+	entries, _ := os.ReadDir(cfg.OutputDir)
+	if len(entries) != 1 {
+		t.Errorf("expected 1 note, got %d files", len(entries))
+	}
+}
+
+func TestProcessFile_TranscribesAgainWhenNoteDeleted(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+
+	tc := &countingClient{}
+	svc := newDedupService(t, cfg, tc)
+
+	audioPath := writeWAV(t, cfg.WatchDir, "memo.wav", 2*time.Second)
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	entries, _ := os.ReadDir(cfg.OutputDir)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 note, got %d files", len(entries))
+	}
+	os.Remove(filepath.Join(cfg.OutputDir, entries[0].Name()))
+
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if n := tc.calls.Load(); n != 2 {
+		t.Errorf("expected 2 transcriptions, got %d", n)
+	}
+}
+
+func TestProcessFile_SkipsTextNoteFromHistory(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+	cfg.ArchiveDir = t.TempDir()
+	cfg.OutputFormat = writer.FormatText
+
+	tc := &countingClient{}
+	audioPath := writeWAV(t, cfg.WatchDir, "memo.wav", 2*time.Second)
+	if err := newDedupService(t, cfg, tc).processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// A restarted service has only the history to find the note by, as text
+	// notes have no frontmatter
+	if err := newDedupService(t, cfg, tc).processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{archive: true}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if n := tc.calls.Load(); n != 1 {
+		t.Errorf("expected 1 transcription, got %d", n)
+	}
+	if _, err := os.Stat(audioPath); !os.IsNotExist(err) {
+		t.Errorf("expected the recording to be archived, got: %v", err)
+	}
+	entries, _ := os.ReadDir(cfg.OutputDir)
+	if len(entries) != 1 || filepath.Ext(entries[0].Name()) != ".txt" {
+		t.Errorf("expected 1 text note, got %v", entries)
+	}
+}
+
+func TestProcessFile_SkipsRecordingMergedIntoNote(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+	cfg.ArchiveDir = t.TempDir()
+	cfg.MergeWindowMinutes = 5
+	svc := newDedupService(t, cfg, &countingClient{})
+
+	// Two recordings merged into one note, by a run whose history is gone
+	start := time.Date(2026, 1, 22, 9, 30, 0, 0, time.Local)
+	var recordings []string
+	for i, name := range []string{"first.wav", "second.wav"} {
+		path := writeWAV(t, cfg.WatchDir, name, time.Duration(i+2)*time.Second)
+		at := start.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, at, at); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		opts := OutputOptions{
+			OutputDir:  cfg.OutputDir,
+			SourceFile: path,
+			Timestamp:  at,
+			Processing: &ProcessingInfo{SourcePath: path, SourceHash: hashFile(path)},
+		}
+		if _, err := svc.writeNote(context.Background(), &recordingLogger{}, path, "Buy milk.", opts); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		recordings = append(recordings, path)
+	}
+
+	tc := &countingClient{}
+	if err := newDedupService(t, cfg, tc).processFile(context.Background(), FileEvent{Path: recordings[1]}, processOptions{archive: true}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if n := tc.calls.Load(); n != 0 {
+		t.Errorf("expected the second recording not to be transcribed, got %d transcriptions", n)
+	}
+	entries, _ := os.ReadDir(cfg.OutputDir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the merged note, got %d files", len(entries))
+	}
+}
@@ -0,0 +1,86 @@
+package transcribe
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+)
+
+// FilenameParser recognises a recorder's file naming convention and labels
+// matching recordings with the device and source that made them. Device and
+// RecordingSource may refer to the pattern's capture groups, e.g. "${1}" or
+// "${device}" for a group named device.
+type FilenameParser struct {
+	// Name identifies the parser in errors.
+	Name string `json:"name,omitempty"`
+	// Pattern matches the file's base name.
+	Pattern string `json:"pattern"`
+	// Device labels the device, e.g. "HUAWEI P30" or "${model}".
+	Device string `json:"device,omitempty"`
+	// RecordingSource labels the app or recorder, e.g. "Voice Memos".
+	RecordingSource string `json:"recording_source,omitempty"`
+}
+
+// label names a parser for errors.
+func (p FilenameParser) label(i int) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
+
+// recordingLabels are what filename parsers extract from a recording's name.
+type recordingLabels struct {
+	Device          string
+	RecordingSource string
+}
+
+// deviceDetector matches file names against compiled filename parsers.
+type deviceDetector struct {
+	parsers []FilenameParser
+	regexes []*regexp.Regexp
+}
+
+// newDeviceDetector compiles the parsers' patterns. It returns nil when no
+// parsers are configured.
+func newDeviceDetector(parsers []FilenameParser) (*deviceDetector, error) {
+	if len(parsers) == 0 {
+		return nil, nil
+	}
+	d := &deviceDetector{parsers: parsers, regexes: make([]*regexp.Regexp, len(parsers))}
+	for i, p := range parsers {
+		if p.Pattern == "" {
+			return nil, fmt.Errorf("%w %s: pattern is required", ErrInvalidFilenameParser, p.label(i))
+		}
+		if p.Device == "" && p.RecordingSource == "" {
+			return nil, fmt.Errorf("%w %s: needs device or recording_source", ErrInvalidFilenameParser, p.label(i))
+		}
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: pattern: %v", ErrInvalidFilenameParser, p.label(i), err)
+		}
+		d.regexes[i] = re
+	}
+	return d, nil
+}
+
+// detect returns the labels of the first parser matching path's base name,
+// or empty labels when none match.
+func (d *deviceDetector) detect(path string) recordingLabels {
+	if d == nil {
+		return recordingLabels{}
+	}
+	name := filepath.Base(path)
+	for i, re := range d.regexes {
+		match := re.FindStringSubmatchIndex(name)
+		if match == nil {
+			continue
+		}
+		p := d.parsers[i]
+		return recordingLabels{
+			Device:          string(re.ExpandString(nil, p.Device, name, match)),
+			RecordingSource: string(re.ExpandString(nil, p.RecordingSource, name, match)),
+		}
+	}
+	return recordingLabels{}
+}
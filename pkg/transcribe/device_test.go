@@ -0,0 +1,71 @@
+package transcribe
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDeviceDetector_Detect(t *testing.T) {
+	d, err := newDeviceDetector([]FilenameParser{
+		{Name: "huawei", Pattern: `^Rec_(?P<model>[A-Z]+)_`, Device: "${model}", RecordingSource: "Huawei Recorder"},
+		{Name: "iphone", Pattern: `^Voice \d+\.m4a$`, Device: "iPhone", RecordingSource: "Voice Memos"},
+		{Name: "any voice", Pattern: `^Voice`, Device: "Unknown"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected recordingLabels
+	}{
+		{"capture group", "/sync/Rec_HUAWEI_20260122_093000.m4a", recordingLabels{Device: "HUAWEI", RecordingSource: "Huawei Recorder"}},
+		{"fixed labels", "/sync/Voice 034.m4a", recordingLabels{Device: "iPhone", RecordingSource: "Voice Memos"}},
+		{"first match wins", "/sync/Voice note.wav", recordingLabels{Device: "Unknown"}},
+		{"no match", "/sync/memo.m4a", recordingLabels{}},
+		{"directory is not matched", "/sync/Voice 034/memo.m4a", recordingLabels{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.detect(tt.path); got != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewDeviceDetector_InvalidParsers(t *testing.T) {
+	tests := []struct {
+		name   string
+		parser FilenameParser
+	}{
+		{"no pattern", FilenameParser{Device: "iPhone"}},
+		{"no labels", FilenameParser{Pattern: `^Voice`}},
+		{"bad pattern", FilenameParser{Name: "broken", Pattern: `^Rec_(`, Device: "HUAWEI"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newDeviceDetector([]FilenameParser{tt.parser})
+			if !errors.Is(err, ErrInvalidFilenameParser) {
+				t.Errorf("expected ErrInvalidFilenameParser, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestService_ProcessingInfoRecordsDevice(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.FilenameParsers = []FilenameParser{{Pattern: `^Voice \d+`, Device: "iPhone", RecordingSource: "Voice Memos"}}
+	svc, err := NewServiceWith(cfg, Options{Watcher: &fakeWatcher{}, Logger: &recordingLogger{}})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	info := svc.processingInfo("/sync/Voice 034.m4a", "", &TranscriptionResult{Text: "Buy milk."}, 0)
+	if info.Device != "iPhone" || info.RecordingSource != "Voice Memos" {
+		t.Errorf("expected device labels from the file name, got %+v", info)
+	}
+}
@@ -0,0 +1,131 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrInsufficientDiskSpace is recorded for files that were not processed
+// because an output or archive location was nearly full.
+var ErrInsufficientDiskSpace = errors.New("insufficient disk space")
+
+// diskCheckInterval is how often a paused disk guard re-checks free space.
+const diskCheckInterval = 30 * time.Second
+
+// diskGuard pauses processing while any output or archive location has less
+// free space than the configured minimum.
+type diskGuard struct {
+	dirs     []string
+	minFree  uint64
+	interval time.Duration
+	// freeSpace returns the bytes available to unprivileged users on the
+	// filesystem holding path.
+	freeSpace func(path string) (uint64, error)
+	// onChange, if set, is called when processing pauses, with the reason,
+	// and with nil when it resumes.
+	onChange func(err error)
+
+	mu     sync.Mutex
+	paused bool
+}
+
+// newDiskGuard returns a guard for the config's output, route and archive
+// directories.
+func newDiskGuard(cfg *Config) *diskGuard {
+	seen := make(map[string]bool)
+	var dirs []string
+	add := func(dir string) {
+		if dir != "" && !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	add(cfg.OutputDir)
+	for _, rule := range cfg.Routes {
+		add(rule.OutputDir)
+	}
+	add(cfg.ArchiveDir)
+
+	return &diskGuard{
+		dirs:      dirs,
+		minFree:   uint64(cfg.MinFreeSpaceMB) * 1024 * 1024,
+		interval:  diskCheckInterval,
+		freeSpace: freeSpace,
+	}
+}
+
+// check returns an error wrapping ErrInsufficientDiskSpace for the first
+// location below the minimum. Locations whose free space cannot be read are
+// skipped; the write itself will report the problem.
+func (g *diskGuard) check() error {
+	for _, dir := range g.dirs {
+		free, err := g.freeSpace(dir)
+		if err != nil {
+			continue
+		}
+		if free < g.minFree {
+			return fmt.Errorf("%w: %s has %d MB free, %d MB required",
+				ErrInsufficientDiskSpace, dir, free/(1024*1024), g.minFree/(1024*1024))
+		}
+	}
+	return nil
+}
+
+// wait blocks until every location has enough free space or ctx is
+// cancelled. The pause and the recovery are logged once each, however many
+// files are waiting.
+func (g *diskGuard) wait(ctx context.Context, logger Logger) error {
+	for {
+		err := g.check()
+
+		g.mu.Lock()
+		changed := (err != nil) != g.paused
+		if err != nil && !g.paused {
+			logger.Error("insufficient disk space, pausing processing", err)
+		}
+		if err == nil && g.paused {
+			logger.Info("disk space available, resuming processing")
+		}
+		g.paused = err != nil
+		g.mu.Unlock()
+		if changed && g.onChange != nil {
+			g.onChange(err)
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		timer := time.NewTimer(g.interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// freeSpace returns the bytes available on the filesystem holding path,
+// using the nearest existing parent for paths not created yet.
+func freeSpace(path string) (uint64, error) {
+	for {
+		var st unix.Statfs_t
+		err := unix.Statfs(path, &st)
+		if err == nil {
+			return uint64(st.Bavail) * uint64(st.Bsize), nil
+		}
+		parent := filepath.Dir(path)
+		if !errors.Is(err, os.ErrNotExist) || parent == path {
+			return 0, err
+		}
+		path = parent
+	}
+}
@@ -0,0 +1,101 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewDiskGuard_Dirs(t *testing.T) {
+	cfg := &Config{
+		OutputDir:      "/vault/Inbox",
+		ArchiveDir:     "/archive",
+		MinFreeSpaceMB: 50,
+		Routes: []RouteRule{
+			{OutputDir: "/vault/Work"},
+			{OutputDir: "/vault/Inbox"},
+			{TemplatePath: "meeting"},
+		},
+	}
+
+	g := newDiskGuard(cfg)
+	expected := []string{"/vault/Inbox", "/vault/Work", "/archive"}
+	if !reflect.DeepEqual(g.dirs, expected) {
+		t.Errorf("expected dirs %v, got %v", expected, g.dirs)
+	}
+	if g.minFree != 50*1024*1024 {
+		t.Errorf("expected minFree 50 MB, got %d", g.minFree)
+	}
+}
+
+func TestDiskGuard_Check(t *testing.T) {
+	g := newDiskGuard(&Config{OutputDir: "/vault/Inbox", ArchiveDir: "/archive", MinFreeSpaceMB: 100})
+	free := map[string]uint64{"/vault/Inbox": 500 * 1024 * 1024, "/archive": 20 * 1024 * 1024}
+	g.freeSpace = func(path string) (uint64, error) { return free[path], nil }
+
+	err := g.check()
+	if !errors.Is(err, ErrInsufficientDiskSpace) {
+		t.Fatalf("expected ErrInsufficientDiskSpace, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "/archive has 20 MB free") {
+		t.Errorf("expected error to name the full location, got: %v", err)
+	}
+
+	free["/archive"] = 200 * 1024 * 1024
+	if err := g.check(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
+
+func TestDiskGuard_WaitPausesAndResumes(t *testing.T) {
+	g := newDiskGuard(&Config{OutputDir: "/vault/Inbox", MinFreeSpaceMB: 100})
+	g.interval = 10 * time.Millisecond
+	checks := 0
+	g.freeSpace = func(path string) (uint64, error) {
+		checks++
+		if checks < 3 {
+			return 0, nil
+		}
+		return 1 << 30, nil
+	}
+	var changes []bool
+	g.onChange = func(err error) { changes = append(changes, err != nil) }
+
+	logger := &recordingLogger{}
+	if err := g.wait(context.Background(), logger); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	expected := []string{"insufficient disk space, pausing processing", "disk space available, resuming processing"}
+	if !reflect.DeepEqual(logger.messages, expected) {
+		t.Errorf("expected messages %v, got %v", expected, logger.messages)
+	}
+	if !reflect.DeepEqual(changes, []bool{true, false}) {
+		t.Errorf("expected one pause and one resume, got %v", changes)
+	}
+}
+
+func TestDiskGuard_WaitCancelled(t *testing.T) {
+	g := newDiskGuard(&Config{OutputDir: "/vault/Inbox", MinFreeSpaceMB: 100})
+	g.freeSpace = func(path string) (uint64, error) { return 0, nil }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := g.wait(ctx, &recordingLogger{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got: %v", err)
+	}
+}
+
+func TestFreeSpace_MissingDirectory(t *testing.T) {
+	free, err := freeSpace(filepath.Join(t.TempDir(), "not", "created", "yet"))
+	if err != nil {
+		t.Fatalf("expected free space of the nearest parent, got: %v", err)
+	}
+	if free == 0 {
+		t.Error("expected non-zero free space")
+	}
+}
@@ -0,0 +1,425 @@
+//go:build linux
+
+package transcribe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/mockasr"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/subtitle"
+)
+
+// e2eHarness runs the real Service (inotify watcher, polling stabilizer,
+// whisper client, markdown writer and archiver) against an httptest ASR stub
+// inside a temporary vault and HOME.
+//
+// Run the end-to-end tests alone with:
+//
+//	go test -run TestE2E ./pkg/transcribe/
+type e2eHarness struct {
+	t          *testing.T
+	home       string
+	watchDir   string
+	outputDir  string
+	archiveDir string
+	asr        *httptest.Server
+	requests   atomic.Int32
+
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// newE2EHarness creates the harness directories and ASR stub. A nil handler
+// uses the mock ASR server.
+func newE2EHarness(t *testing.T, handler http.Handler) *e2eHarness {
+	t.Helper()
+
+	home := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", home)
+	t.Cleanup(func() { os.Setenv("HOME", originalHome) })
+
+	vault := filepath.Join(home, "vault")
+	h := &e2eHarness{
+		t:          t,
+		home:       home,
+		watchDir:   filepath.Join(home, "inbox"),
+		outputDir:  filepath.Join(vault, "Inbox"),
+		archiveDir: filepath.Join(home, "archive"),
+	}
+	for _, dir := range []string{h.watchDir, vault} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	if handler == nil {
+		mock := mockasr.New()
+		mock.Text = "Pick up the dry cleaning on Thursday."
+		handler = mock
+	}
+	h.asr = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.requests.Add(1)
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(h.asr.Close)
+
+	return h
+}
+
+// config returns a configuration with fast stabilization for tests.
+func (h *e2eHarness) config() *Config {
+	return &Config{
+		WatchDir:                h.watchDir,
+		APIURL:                  h.asr.URL,
+		OutputDir:               h.outputDir,
+		ArchiveDir:              h.archiveDir,
+		StabilizationIntervalMs: 50,
+		StabilizationChecks:     2,
+		RetryCount:              2,
+	}
+}
+
+// start runs the service in the background until the test ends.
+func (h *e2eHarness) start(cfg *Config) {
+	h.t.Helper()
+
+	svc, err := NewService(cfg)
+	if err != nil {
+		h.t.Fatalf("failed to create service: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.done = make(chan error, 1)
+	go func() { h.done <- svc.Run(ctx) }()
+	h.t.Cleanup(h.stop)
+
+	// Give the watcher time to set up
+	time.Sleep(100 * time.Millisecond)
+}
+
+// stop shuts the service down and waits for in-flight files. Safe to call twice.
+func (h *e2eHarness) stop() {
+	if h.cancel == nil {
+		return
+	}
+	h.cancel()
+	h.cancel = nil
+	select {
+	case err := <-h.done:
+		if err != nil {
+			h.t.Errorf("service shutdown failed: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		h.t.Error("timeout waiting for service shutdown")
+	}
+}
+
+// drop writes an audio file outside the watch directory and moves it in,
+// the way a sync client completes a transfer.
+func (h *e2eHarness) drop(name string) string {
+	h.t.Helper()
+
+	staging := filepath.Join(h.home, "staging")
+	os.MkdirAll(staging, 0755)
+	src := filepath.Join(staging, name)
+	if err := os.WriteFile(src, []byte("fake audio for "+name), 0644); err != nil {
+		h.t.Fatalf("failed to write %s: %v", name, err)
+	}
+	dst := filepath.Join(h.watchDir, name)
+	if err := os.Rename(src, dst); err != nil {
+		h.t.Fatalf("failed to move %s: %v", name, err)
+	}
+	return dst
+}
+
+// waitFor polls cond until it returns true or the timeout expires.
+func (h *e2eHarness) waitFor(what string, cond func() bool) {
+	h.t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+	h.t.Fatalf("timeout waiting for %s", what)
+}
+
+// notes returns the markdown files in the output directory.
+func (h *e2eHarness) notes() []string {
+	matches, _ := filepath.Glob(filepath.Join(h.outputDir, "*.md"))
+	return matches
+}
+
+// archived returns the audio files in the archive directory.
+func (h *e2eHarness) archived() []string {
+	var files []string
+	filepath.WalkDir(h.archiveDir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files
+}
+
+// history returns the processing history records.
+func (h *e2eHarness) history() []history.Record {
+	h.t.Helper()
+
+	path, err := history.DefaultPath()
+	if err != nil {
+		h.t.Fatalf("failed to get history path: %v", err)
+	}
+	records, err := history.New(path).Load(time.Time{})
+	if err != nil {
+		h.t.Fatalf("failed to load history: %v", err)
+	}
+	return records
+}
+
+// logs returns the contents of all service log files.
+func (h *e2eHarness) logs() string {
+	matches, _ := filepath.Glob(filepath.Join(h.home, ".nota", "logs", "*.log"))
+	var b strings.Builder
+	for _, m := range matches {
+		data, _ := os.ReadFile(m)
+		b.Write(data)
+	}
+	return b.String()
+}
+
+func TestE2E_SingleFile(t *testing.T) {
+	h := newE2EHarness(t, nil)
+	h.start(h.config())
+
+	audioPath := h.drop("memo.m4a")
+
+	h.waitFor("file to be archived", func() bool { return len(h.archived()) == 1 })
+	h.stop()
+
+	notes := h.notes()
+	if len(notes) != 1 {
+		t.Fatalf("expected 1 note, got: %v", notes)
+	}
+	content, _ := os.ReadFile(notes[0])
+	if !strings.Contains(string(content), "Pick up the dry cleaning on Thursday.") {
+		t.Errorf("expected note to contain transcription, got:\n%s", content)
+	}
+
+	if _, err := os.Stat(audioPath); !os.IsNotExist(err) {
+		t.Error("expected audio file to be moved out of the watch directory")
+	}
+	if filepath.Base(h.archived()[0]) != "memo.m4a" {
+		t.Errorf("expected memo.m4a in archive, got: %v", h.archived())
+	}
+	for _, want := range []string{
+		"source_path: " + strconv.Quote(audioPath),
+		"archive_path: " + strconv.Quote(h.archived()[0]),
+		"model: \"base\"",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("expected note frontmatter to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	logs := h.logs()
+	for _, want := range []string{"starting transcription service", "effective config", "file processing complete", "transcription service stopped"} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("expected log to contain %q, got:\n%s", want, logs)
+		}
+	}
+
+	records := h.history()
+	if len(records) != 1 || records[0].Status != history.StatusCompleted || records[0].Output != notes[0] {
+		t.Errorf("expected one completed history record for %s, got: %+v", notes[0], records)
+	}
+	if len(records) == 1 && records[0].Words == 0 {
+		t.Errorf("expected word count in history record, got: %+v", records[0])
+	}
+}
+
+func TestE2E_MultipleFiles(t *testing.T) {
+	h := newE2EHarness(t, nil)
+	h.start(h.config())
+
+	names := []string{"first.m4a", "second.mp3", "third.wav"}
+	for _, name := range names {
+		h.drop(name)
+	}
+
+	h.waitFor("all files to be archived", func() bool { return len(h.archived()) == len(names) })
+	h.stop()
+
+	if len(h.notes()) != len(names) {
+		t.Errorf("expected %d notes, got: %v", len(names), h.notes())
+	}
+	if int(h.requests.Load()) != len(names) {
+		t.Errorf("expected %d ASR requests, got: %d", len(names), h.requests.Load())
+	}
+	if len(h.history()) != len(names) {
+		t.Errorf("expected %d history records, got: %d", len(names), len(h.history()))
+	}
+}
+
+func TestE2E_IgnoresUnmatchedFiles(t *testing.T) {
+	h := newE2EHarness(t, nil)
+	h.start(h.config())
+
+	h.drop("notes.txt")
+	h.drop("memo.m4a")
+
+	h.waitFor("audio file to be archived", func() bool { return len(h.archived()) == 1 })
+	h.stop()
+
+	if h.requests.Load() != 1 {
+		t.Errorf("expected only the audio file to be sent, got %d requests", h.requests.Load())
+	}
+	if _, err := os.Stat(filepath.Join(h.watchDir, "notes.txt")); err != nil {
+		t.Error("expected unmatched file to be left in place")
+	}
+}
+
+func TestE2E_RetriesThenSucceeds(t *testing.T) {
+	var calls atomic.Int32
+	mock := mockasr.New()
+	h := newE2EHarness(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			http.Error(w, "model loading", http.StatusServiceUnavailable)
+			return
+		}
+		mock.ServeHTTP(w, r)
+	}))
+	h.start(h.config())
+
+	h.drop("memo.m4a")
+
+	h.waitFor("file to be archived", func() bool { return len(h.archived()) == 1 })
+	h.stop()
+
+	if h.requests.Load() != 2 {
+		t.Errorf("expected 2 ASR requests, got: %d", h.requests.Load())
+	}
+	if !strings.Contains(h.logs(), "transcription failed, retrying") {
+		t.Errorf("expected retry to be logged, got:\n%s", h.logs())
+	}
+	if len(h.notes()) != 1 {
+		t.Errorf("expected 1 note, got: %v", h.notes())
+	}
+}
+
+func TestE2E_ASRFailureLeavesFile(t *testing.T) {
+	h := newE2EHarness(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "out of memory", http.StatusInternalServerError)
+	}))
+	h.start(h.config())
+
+	audioPath := h.drop("memo.m4a")
+
+	h.waitFor("failure to be recorded", func() bool { return len(h.history()) == 1 })
+	h.stop()
+
+	if _, err := os.Stat(audioPath); err != nil {
+		t.Error("expected failed file to remain in the watch directory")
+	}
+	if len(h.notes()) != 0 || len(h.archived()) != 0 {
+		t.Errorf("expected no notes or archives, got notes=%v archived=%v", h.notes(), h.archived())
+	}
+
+	records := h.history()
+	if records[0].Status != history.StatusFailed || !strings.Contains(records[0].Error, "out of memory") {
+		t.Errorf("expected failed record with API error, got: %+v", records[0])
+	}
+	if records[0].Category != history.CategoryTranscription {
+		t.Errorf("expected transcription failure category, got: %q", records[0].Category)
+	}
+	if !strings.Contains(h.logs(), "transcription failed after retries") {
+		t.Errorf("expected failure to be logged, got:\n%s", h.logs())
+	}
+}
+
+func TestE2E_ASRRejectionIsClassified(t *testing.T) {
+	h := newE2EHarness(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unsupported audio format", http.StatusUnprocessableEntity)
+	}))
+	h.start(h.config())
+
+	h.drop("memo.m4a")
+
+	h.waitFor("failure to be recorded", func() bool { return len(h.history()) == 1 })
+	h.stop()
+
+	if got := h.history()[0].Category; got != history.CategoryAPI4xx {
+		t.Errorf("expected api_4xx failure category, got: %q", got)
+	}
+	if !strings.Contains(h.logs(), "category=api_4xx") {
+		t.Errorf("expected the category in the failure log line, got:\n%s", h.logs())
+	}
+}
+
+func TestE2E_MultipleWatchDirs(t *testing.T) {
+	h := newE2EHarness(t, nil)
+
+	tabletDir := filepath.Join(h.home, "tablet")
+	os.MkdirAll(tabletDir, 0755)
+	cfg := h.config()
+	cfg.WatchDirs = []WatchDirConfig{{Path: tabletDir, Patterns: []string{"*.wav"}}}
+	h.start(cfg)
+
+	h.drop("phone.m4a")
+	os.WriteFile(filepath.Join(tabletDir, "ignored.m4a"), []byte("audio"), 0644)
+	os.WriteFile(filepath.Join(tabletDir, "tablet.wav"), []byte("audio"), 0644)
+
+	h.waitFor("both files to be archived", func() bool { return len(h.archived()) == 2 })
+	h.stop()
+
+	if h.requests.Load() != 2 {
+		t.Errorf("expected 2 ASR requests, got: %d", h.requests.Load())
+	}
+	if _, err := os.Stat(filepath.Join(tabletDir, "ignored.m4a")); err != nil {
+		t.Error("expected file not matching the tablet patterns to be left in place")
+	}
+}
+
+func TestE2E_Subtitles(t *testing.T) {
+	h := newE2EHarness(t, nil)
+	cfg := h.config()
+	cfg.Subtitles = subtitle.SRT
+	h.start(cfg)
+
+	h.drop("memo.m4a")
+
+	h.waitFor("subtitles to be written", func() bool { return len(h.archived()) == 2 })
+	h.stop()
+
+	var audio string
+	for _, path := range h.archived() {
+		if filepath.Ext(path) == ".m4a" {
+			audio = path
+		}
+	}
+	if audio == "" {
+		t.Fatalf("expected archived audio, got: %v", h.archived())
+	}
+	content, err := os.ReadFile(strings.TrimSuffix(audio, ".m4a") + ".srt")
+	if err != nil {
+		t.Fatalf("expected subtitles next to the archived audio: %v", err)
+	}
+	expected := "1\n00:00:00,000 --> 00:00:02,500\nPick up the dry cleaning on Thursday.\n\n"
+	if string(content) != expected {
+		t.Errorf("expected subtitles:\n%s\ngot:\n%s", expected, content)
+	}
+}
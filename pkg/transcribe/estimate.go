@@ -0,0 +1,102 @@
+package transcribe
+
+import (
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+const (
+	// speedWindow is how far back the history is read for the speed
+	// recordings are transcribed at.
+	speedWindow = 30 * 24 * time.Hour
+	// speedSamples is the number of recent files the speed is averaged
+	// over, so it follows changes to the ASR box.
+	speedSamples = 20
+)
+
+// speedSample is how long a recording took to transcribe.
+type speedSample struct {
+	audio, transcribe time.Duration
+}
+
+// speedEstimator estimates how long a recording will take to transcribe
+// from the speed recent files of the configured provider were transcribed
+// at.
+type speedEstimator struct {
+	store    *history.Store
+	now      func() time.Time
+	provider string
+
+	mu sync.Mutex
+	// loaded is set once samples were read from the history.
+	loaded  bool
+	samples []speedSample
+}
+
+// newSpeedEstimator returns an estimator reading past files from store.
+func newSpeedEstimator(store *history.Store, provider string, now func() time.Time) *speedEstimator {
+	return &speedEstimator{store: store, now: now, provider: provider}
+}
+
+// estimate returns how long a recording of the given length is expected
+// to take to transcribe, or zero when the length is unknown or no file has
+// been transcribed recently to go by.
+func (e *speedEstimator) estimate(audio time.Duration) time.Duration {
+	if audio <= 0 {
+		return 0
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.load()
+
+	var audioTotal, transcribeTotal time.Duration
+	for _, sample := range e.samples {
+		audioTotal += sample.audio
+		transcribeTotal += sample.transcribe
+	}
+	if audioTotal <= 0 || transcribeTotal <= 0 {
+		return 0
+	}
+	estimate := time.Duration(float64(audio) * transcribeTotal.Seconds() / audioTotal.Seconds())
+	return max(estimate.Round(time.Second), time.Second)
+}
+
+// load reads the most recent samples from the history the first time it
+// is called.
+func (e *speedEstimator) load() {
+	if e.loaded {
+		return
+	}
+	e.loaded = true
+	records, err := e.store.Load(e.now().Add(-speedWindow))
+	if err != nil {
+		// No estimates until files complete in this run
+		return
+	}
+	for _, rec := range records {
+		e.addLocked(rec)
+	}
+}
+
+// add counts a recorded file towards the speed, if it was transcribed.
+func (e *speedEstimator) add(rec history.Record) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.loaded {
+		e.addLocked(rec)
+	}
+}
+
+func (e *speedEstimator) addLocked(rec history.Record) {
+	if rec.Status != history.StatusCompleted || rec.Provider != e.provider ||
+		rec.TranscribeMs <= 0 || rec.AudioSeconds <= 0 {
+		return
+	}
+	e.samples = append(e.samples, speedSample{audio: rec.AudioDuration(), transcribe: rec.TranscribeTime()})
+	if len(e.samples) > speedSamples {
+		e.samples = e.samples[len(e.samples)-speedSamples:]
+	}
+}
@@ -0,0 +1,77 @@
+package transcribe
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+// transcribed returns a completed record of a file with audio seconds of
+// audio transcribed in transcribe seconds by provider.
+func transcribed(provider string, audio, transcribe float64, at time.Time) history.Record {
+	return history.Record{
+		Time:         at,
+		Status:       history.StatusCompleted,
+		Provider:     provider,
+		AudioSeconds: audio,
+		TranscribeMs: int64(transcribe * 1000),
+	}
+}
+
+func TestSpeedEstimator_Estimate(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	store := history.New(filepath.Join(t.TempDir(), "history.jsonl"))
+	for _, rec := range []history.Record{
+		// Too old to count
+		transcribed("asr.local", 600, 600, now.Add(-40*24*time.Hour)),
+		// 20x realtime over the recent files of the provider
+		transcribed("asr.local", 1200, 40, now.Add(-2*time.Hour)),
+		transcribed("asr.local", 600, 50, now.Add(-time.Hour)),
+		// Other providers, failures and files without timings don't count
+		transcribed("openai", 600, 600, now.Add(-time.Hour)),
+		{Time: now, Status: history.StatusFailed, Provider: "asr.local", AudioSeconds: 600, TranscribeMs: 600000},
+		transcribed("asr.local", 600, 0, now.Add(-time.Hour)),
+	} {
+		if err := store.Append(rec); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+
+	e := newSpeedEstimator(store, "asr.local", func() time.Time { return now })
+	if got := e.estimate(38 * time.Minute); got != 114*time.Second {
+		t.Errorf("expected 1m54s, got %v", got)
+	}
+	if got := e.estimate(0); got != 0 {
+		t.Errorf("expected no estimate without a duration, got %v", got)
+	}
+
+	// Files completed since count too, now 2x realtime
+	e.add(transcribed("asr.local", 1800, 1710, now))
+	if got := e.estimate(36 * time.Minute); got != 18*time.Minute {
+		t.Errorf("expected 18m0s, got %v", got)
+	}
+}
+
+func TestSpeedEstimator_RecentSamples(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	e := newSpeedEstimator(history.New(filepath.Join(t.TempDir(), "history.jsonl")), "asr.local", func() time.Time { return now })
+
+	if got := e.estimate(time.Minute); got != 0 {
+		t.Errorf("expected no estimate without history, got %v", got)
+	}
+
+	// A slow ASR box replaced by a fast one: only the latest files count
+	e.add(transcribed("asr.local", 60, 60, now))
+	for range speedSamples {
+		e.add(transcribed("asr.local", 60, 6, now))
+	}
+	if got := e.estimate(10 * time.Minute); got != time.Minute {
+		t.Errorf("expected 1m0s, got %v", got)
+	}
+	// Short estimates round up to a second
+	if got := e.estimate(time.Second); got != time.Second {
+		t.Errorf("expected 1s, got %v", got)
+	}
+}
@@ -0,0 +1,56 @@
+package transcribe
+
+import (
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/events"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// emit appends ev to the events file. Events supplement the logs and
+// history, so a failed write is logged rather than failing the file.
+func (s *Service) emit(ev events.Event) {
+	if err := s.events.Emit(ev); err != nil {
+		s.logger.Error("failed to record event", err, logging.String("type", ev.Type))
+	}
+}
+
+// diskSpaceChanged records processing pausing for low disk space, or
+// resuming when err is nil.
+func (s *Service) diskSpaceChanged(err error) {
+	if err != nil {
+		s.emit(events.Event{Type: events.DiskSpaceLow, Error: err.Error()})
+		return
+	}
+	s.emit(events.Event{Type: events.DiskSpaceAvailable})
+}
+
+// budgetChanged records processing pausing for an exceeded monthly budget,
+// or resuming when err is nil.
+func (s *Service) budgetChanged(err error) {
+	if err != nil {
+		s.emit(events.Event{Type: events.BudgetExceeded, Error: err.Error()})
+		return
+	}
+	s.emit(events.Event{Type: events.BudgetAvailable})
+}
+
+// outcomeEvent returns the event recording a file's outcome.
+func outcomeEvent(event FileEvent, rec history.Record) events.Event {
+	ev := events.Event{
+		Time:      rec.Time,
+		Type:      events.TranscriptionComplete,
+		Path:      event.Path,
+		Size:      event.Size,
+		Output:    rec.Output,
+		Category:  rec.Category,
+		Error:     rec.Error,
+		ElapsedMs: rec.ElapsedMs,
+	}
+	switch rec.Status {
+	case history.StatusFailed:
+		ev.Type = events.FileFailed
+	case history.StatusSkipped:
+		ev.Type = events.FileSkipped
+	}
+	return ev
+}
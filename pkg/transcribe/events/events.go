@@ -0,0 +1,137 @@
+// Package events records machine-readable pipeline events, one JSON object
+// per line in a daily events-YYYY-MM-DD.jsonl file next to the human logs,
+// so status commands and external dashboards can read what happened without
+// parsing log lines.
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// Event types.
+const (
+	ServiceStarted        = "service_started"
+	FileDetected          = "file_detected"
+	TranscriptionComplete = "transcription_complete"
+	FileFailed            = "file_failed"
+	FileSkipped           = "file_skipped"
+	DiskSpaceLow          = "disk_space_low"
+	DiskSpaceAvailable    = "disk_space_available"
+	BudgetExceeded        = "budget_exceeded"
+	BudgetAvailable       = "budget_available"
+	JobCompleted          = "job_completed"
+	JobFailed             = "job_failed"
+)
+
+// Event is one line of an events file.
+type Event struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	Path string    `json:"path,omitempty"`
+	Size int64     `json:"size,omitempty"`
+	// Output is the note written for transcription_complete.
+	Output string `json:"output,omitempty"`
+	// Category classifies file_failed and file_skipped events as in the
+	// processing history.
+	Category  string `json:"category,omitempty"`
+	Error     string `json:"error,omitempty"`
+	ElapsedMs int64  `json:"elapsed_ms,omitempty"`
+	// Job names the scheduled job of job_completed and job_failed.
+	Job string `json:"job,omitempty"`
+	// Result summarizes what a completed job did.
+	Result string `json:"result,omitempty"`
+}
+
+// Log appends events to the file for the UTC day they happen.
+type Log struct {
+	dir   string
+	clock clock.Clock
+
+	mu sync.Mutex
+}
+
+// New creates a log writing to dir, dating events with clk (the system
+// clock if nil).
+func New(dir string, clk clock.Clock) *Log {
+	return &Log{dir: dir, clock: clock.OrReal(clk)}
+}
+
+// Open creates a log in the default log directory.
+func Open(clk clock.Clock) (*Log, error) {
+	dir, err := logging.DefaultLogDir()
+	if err != nil {
+		return nil, err
+	}
+	return New(dir, clk), nil
+}
+
+// PathFor returns the events file for the UTC day of t in dir.
+func PathFor(dir string, t time.Time) string {
+	return filepath.Join(dir, "events-"+t.UTC().Format("2006-01-02")+".jsonl")
+}
+
+// Emit appends ev, stamping it with the current time unless it has one. A
+// nil Log discards events.
+func (l *Log) Emit(ev Event) error {
+	if l == nil {
+		return nil
+	}
+	if ev.Time.IsZero() {
+		ev.Time = l.clock.Now().UTC()
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return fmt.Errorf("create events directory: %w", err)
+	}
+	f, err := os.OpenFile(PathFor(l.dir, ev.Time), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open events file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+	return nil
+}
+
+// Read returns the events in the file at path, oldest first. Returns no
+// events if the file doesn't exist. Lines that fail to parse (e.g. a torn
+// final write) are skipped.
+func Read(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var evs []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			continue
+		}
+		evs = append(evs, ev)
+	}
+	return evs, scanner.Err()
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+)
+
+func TestLog_EmitWritesDailyFiles(t *testing.T) {
+	dir := t.TempDir()
+	clk := clock.NewFake(time.Date(2026, 1, 22, 23, 59, 0, 0, time.UTC))
+	log := New(dir, clk)
+
+	log.Emit(Event{Type: FileDetected, Path: "/in/memo.m4a", Size: 2048})
+	clk.Advance(2 * time.Minute)
+	log.Emit(Event{Type: TranscriptionComplete, Path: "/in/memo.m4a", Output: "/out/memo.md", ElapsedMs: 1500})
+
+	first, err := Read(filepath.Join(dir, "events-2026-01-22.jsonl"))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(first) != 1 || first[0].Type != FileDetected || first[0].Size != 2048 {
+		t.Errorf("unexpected events for the first day: %+v", first)
+	}
+
+	second, _ := Read(PathFor(dir, clk.Now()))
+	if len(second) != 1 || second[0].Output != "/out/memo.md" || !second[0].Time.Equal(clk.Now()) {
+		t.Errorf("unexpected events for the second day: %+v", second)
+	}
+}
+
+func TestRead_SkipsTornLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events-2026-01-22.jsonl")
+	data := `{"time":"2026-01-22T10:00:00Z","type":"file_detected","path":"/in/a.m4a"}
+{"time":"2026-01-22T10:00:05Z","type":"file_fai`
+	os.WriteFile(path, []byte(data), 0644)
+
+	evs, err := Read(path)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(evs) != 1 || evs[0].Path != "/in/a.m4a" {
+		t.Errorf("expected the complete line only, got %+v", evs)
+	}
+}
+
+func TestRead_MissingFile(t *testing.T) {
+	evs, err := Read(filepath.Join(t.TempDir(), "events-2026-01-22.jsonl"))
+	if err != nil || evs != nil {
+		t.Errorf("expected no events and no error, got %v, %v", evs, err)
+	}
+}
+
+func TestLog_NilDiscards(t *testing.T) {
+	var log *Log
+	if err := log.Emit(Event{Type: ServiceStarted}); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
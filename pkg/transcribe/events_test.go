@@ -0,0 +1,62 @@
+package transcribe
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/events"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+func TestProcessFile_EmitsEvents(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	dir := t.TempDir()
+	log := events.New(dir, nil)
+
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{},
+		Stabilizer: fakeStabilizer{},
+		Client:     failingClient{failOn: "bad"},
+		Writer:     &recordingWriter{},
+		Archiver:   &countingArchiver{},
+		Logger:     &recordingLogger{},
+		Events:     log,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	paths := setupImportFiles(t, cfg.WatchDir, "good.m4a", "bad.m4a")
+	for _, path := range paths {
+		svc.processFile(context.Background(), FileEvent{Path: path, Size: 5}, processOptions{stabilize: true, archive: true})
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "events-*.jsonl"))
+	if len(matches) != 1 {
+		t.Fatalf("expected one events file, got %v", matches)
+	}
+	evs, err := events.Read(matches[0])
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	want := []struct{ typ, path string }{
+		{events.FileDetected, paths[0]},
+		{events.TranscriptionComplete, paths[0]},
+		{events.FileDetected, paths[1]},
+		{events.FileFailed, paths[1]},
+	}
+	if len(evs) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), evs)
+	}
+	for i, w := range want {
+		if evs[i].Type != w.typ || evs[i].Path != w.path {
+			t.Errorf("event %d: expected %s for %s, got %+v", i, w.typ, w.path, evs[i])
+		}
+	}
+	if evs[1].Output == "" || evs[3].Category != history.CategoryTranscription || evs[3].Error == "" {
+		t.Errorf("expected output on completion and category and error on failure, got %+v and %+v", evs[1], evs[3])
+	}
+}
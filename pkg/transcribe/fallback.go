@@ -0,0 +1,204 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// FallbackConfig is a provider files are sent to when the ones before it
+// are unreachable or over their monthly budget, e.g. a hosted service behind
+// a Whisper server at home.
+type FallbackConfig struct {
+	// Provider and APIURL select the API as provider and api_url do.
+	// Provider defaults to whisper_asr.
+	Provider Provider `json:"provider,omitempty"`
+	APIURL   string   `json:"api_url,omitempty"`
+	// Model defaults to model.
+	Model      string            `json:"model,omitempty"`
+	Azure      *AzureConfig      `json:"azure,omitempty"`
+	Google     *GoogleConfig     `json:"google,omitempty"`
+	AWS        *AWSConfig        `json:"aws,omitempty"`
+	Deepgram   *DeepgramConfig   `json:"deepgram,omitempty"`
+	AssemblyAI *AssemblyAIConfig `json:"assemblyai,omitempty"`
+	// Usage prices the provider and caps its monthly spend, as usage does
+	// for the primary one. Without it the fallback costs nothing.
+	Usage *UsageConfig `json:"usage,omitempty"`
+}
+
+// withFallback returns a copy of c that uses the fallback's provider, so
+// the rest of the settings apply to it unchanged.
+func (c *Config) withFallback(f FallbackConfig) *Config {
+	fc := *c
+	fc.Provider = f.Provider
+	if fc.Provider == "" {
+		fc.Provider = DefaultProvider
+	}
+	fc.APIURL = f.APIURL
+	if f.Model != "" {
+		fc.Model = f.Model
+	}
+	fc.Azure = f.Azure
+	fc.Google = f.Google
+	fc.AWS = f.AWS
+	fc.Deepgram = f.Deepgram
+	fc.AssemblyAI = f.AssemblyAI
+	fc.Usage = f.Usage
+	fc.Fallbacks = nil
+	return &fc
+}
+
+// validateFallbacks checks each fallback as Validate checks the primary
+// provider, normalizing their API URLs.
+func (c *Config) validateFallbacks() error {
+	for i, f := range c.Fallbacks {
+		fc := c.withFallback(f)
+		if err := fc.validateAPI(); err != nil {
+			return fmt.Errorf("%w %d: %w", ErrInvalidFallback, i+1, err)
+		}
+		c.Fallbacks[i].APIURL = fc.APIURL
+		if f.Usage != nil {
+			if err := f.Usage.validate(); err != nil {
+				return fmt.Errorf("%w %d: %w", ErrInvalidFallback, i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// fallback is a provider the service tries after the configured one.
+type fallback struct {
+	name   string
+	config *Config
+	client TranscriptionClient
+	// budget, if set, caps the provider's monthly spend.
+	budget *budgetGuard
+}
+
+// newFallbacks creates a client and budget guard for each of the config's
+// fallbacks. Budgets only count the files of their own provider.
+func newFallbacks(cfg *Config, store *history.Store, uploads uploadHooks, now func() time.Time) ([]*fallback, error) {
+	var fallbacks []*fallback
+	for i, f := range cfg.Fallbacks {
+		fc := cfg.withFallback(f)
+		tc, err := fc.newClient(fc.APIURL, uploads)
+		if err != nil {
+			return nil, fmt.Errorf("%w %d: %w", ErrInvalidFallback, i+1, err)
+		}
+		fb := &fallback{name: fc.ProviderName(), config: fc, client: tc, budget: newBudgetGuard(fc, store, now)}
+		if fb.budget != nil {
+			fb.budget.provider = fb.name
+		}
+		fallbacks = append(fallbacks, fb)
+	}
+	return fallbacks, nil
+}
+
+// providers returns the configured provider followed by the fallbacks, in
+// the order they are tried.
+func (s *Service) providers() []*fallback {
+	primary := &fallback{name: s.config.ProviderName(), config: s.config, client: s.client, budget: s.budget}
+	return append([]*fallback{primary}, s.fallbacks...)
+}
+
+// provider returns the provider a result was transcribed with, by name, or
+// the configured one when it is not known.
+func (s *Service) provider(result *TranscriptionResult) *fallback {
+	providers := s.providers()
+	if result != nil {
+		for _, p := range providers {
+			if p.name == result.Provider {
+				return p
+			}
+		}
+	}
+	return providers[0]
+}
+
+// fallbackAvailable reports whether a fallback can take files while the
+// configured provider is over its budget.
+func (s *Service) fallbackAvailable() bool {
+	for _, fb := range s.fallbacks {
+		if fb.budget == nil || fb.budget.check() == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// canFallBack reports whether err means the provider could not take the
+// file at all, so the next one should be tried: it is unreachable, a proxy
+// in front of it reports so, or its budget is spent.
+func canFallBack(err error) bool {
+	if errors.Is(err, client.ErrUnreachable) || errors.Is(err, ErrBudgetExceeded) {
+		return true
+	}
+	switch client.StatusCode(err) {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// transcribe sends a file to the transcription API with the options its
+// routing rule selects, as transcribeAudio does, running the hooks around
+// it.
+func (s *Service) transcribe(ctx context.Context, fileLogger Logger, path string) (*TranscriptionResult, error) {
+	return s.withHooks(ctx, fileLogger, path, func(audio string) (*TranscriptionResult, error) {
+		return s.transcribeAudio(ctx, fileLogger, audio, s.transcribeOptions(path))
+	})
+}
+
+// transcribeAudio sends audio to the transcription API, retrying up to
+// RetryCount times, and then to each fallback in turn while the providers
+// are unreachable or over budget. Each provider uses its own model. The
+// result names the provider that transcribed it.
+func (s *Service) transcribeAudio(ctx context.Context, fileLogger Logger, path string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	providers := s.providers()
+	var err error
+	for i, p := range providers {
+		// A lone provider's budget is enforced before files get here
+		if p.budget != nil && len(providers) > 1 {
+			if err = p.budget.check(); err != nil {
+				fileLogger.Info("provider over budget, skipping",
+					logging.String("path", path),
+					logging.String("provider", p.name),
+				)
+				continue
+			}
+		}
+
+		opts.Model = p.config.Model
+		var result *TranscriptionResult
+		result, err = s.transcribeWith(ctx, fileLogger, p.client, path, opts)
+		if err == nil {
+			result.Provider = p.name
+			return result, nil
+		}
+		if !canFallBack(err) || i == len(providers)-1 {
+			return nil, err
+		}
+		fileLogger.Error("provider unavailable, falling back", err,
+			logging.String("path", path),
+			logging.String("provider", p.name),
+			logging.String("next", providers[i+1].name),
+		)
+	}
+	return nil, err
+}
+
+// fallbackNames lists the names of the fallbacks, for logs.
+func (s *Service) fallbackNames() string {
+	names := make([]string, len(s.fallbacks))
+	for i, fb := range s.fallbacks {
+		names[i] = fb.name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,164 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+// unreachableClient fails every call as a server that is switched off.
+type unreachableClient struct {
+	calls int
+}
+
+func (c *unreachableClient) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	c.calls++
+	return nil, fmt.Errorf("send request: %w: connection refused", client.ErrUnreachable)
+}
+
+func TestValidate_Fallbacks(t *testing.T) {
+	tests := []struct {
+		name      string
+		fallbacks []FallbackConfig
+		wantErr   error
+	}{
+		{name: "whisper", fallbacks: []FallbackConfig{{APIURL: "http://gpu:9000/asr/"}}},
+		{name: "hosted", fallbacks: []FallbackConfig{{Provider: ProviderDeepgram}}},
+		{name: "unknown provider", fallbacks: []FallbackConfig{{Provider: "openai"}}, wantErr: ErrInvalidProvider},
+		{name: "without api_url", fallbacks: []FallbackConfig{{Provider: ProviderGRPC}}, wantErr: ErrAPIURLRequired},
+		{name: "provider settings", fallbacks: []FallbackConfig{{Provider: ProviderAWS}}, wantErr: ErrInvalidProviderSettings},
+		{name: "usage", fallbacks: []FallbackConfig{{Provider: ProviderDeepgram, Usage: &UsageConfig{MonthlyBudget: 5}}}, wantErr: ErrInvalidUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{WatchDir: "/tmp/watch", OutputDir: "/tmp/output", APIURL: "http://localhost:9000", Fallbacks: tt.fallbacks}
+			err := cfg.Validate()
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+			if tt.wantErr != nil && (!errors.Is(err, tt.wantErr) || !errors.Is(err, ErrInvalidFallback)) {
+				t.Errorf("expected %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	cfg := Config{WatchDir: "/tmp/watch", OutputDir: "/tmp/output", APIURL: "http://localhost:9000", Fallbacks: []FallbackConfig{{APIURL: "HTTP://GPU:9000/asr/"}}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if got := cfg.Fallbacks[0].APIURL; got != "http://gpu:9000/asr" {
+		t.Errorf("expected the fallback's api_url normalized, got %q", got)
+	}
+}
+
+func TestCanFallBack(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: fmt.Errorf("send request: %w: no route to host", client.ErrUnreachable), want: true},
+		{err: &client.APIError{StatusCode: http.StatusBadGateway}, want: true},
+		{err: &client.APIError{StatusCode: http.StatusServiceUnavailable}, want: true},
+		{err: fmt.Errorf("%w: 5.00 spent of 5.00", ErrBudgetExceeded), want: true},
+		{err: &client.APIError{StatusCode: http.StatusInternalServerError}, want: false},
+		{err: &client.APIError{StatusCode: http.StatusBadRequest}, want: false},
+		{err: context.Canceled, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := canFallBack(tt.err); got != tt.want {
+			t.Errorf("canFallBack(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestProcessFile_FallsBackWhenUnreachable(t *testing.T) {
+	server, _ := asrServer(t, false)
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+	cfg.RetryCount = 1
+	cfg.Fallbacks = []FallbackConfig{{
+		APIURL: server.URL,
+		Model:  "large-v3",
+		Usage:  &UsageConfig{Provider: "gpu-box", CostPerMinute: 0.6},
+	}}
+
+	primary := &unreachableClient{}
+	svc := newDedupService(t, cfg, primary)
+	audioPath := writeWAV(t, cfg.WatchDir, "memo.wav", 2*time.Second)
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if primary.calls != 1 {
+		t.Errorf("expected the primary tried once, got %d", primary.calls)
+	}
+	notes, _ := filepath.Glob(filepath.Join(cfg.OutputDir, "*.md"))
+	if len(notes) != 1 {
+		t.Fatalf("expected a note, got %v", notes)
+	}
+	note, _ := os.ReadFile(notes[0])
+	for _, want := range []string{`provider: "gpu-box"`, `model: "large-v3"`} {
+		if !strings.Contains(string(note), want) {
+			t.Errorf("expected the note to record %s, got:\n%s", want, note)
+		}
+	}
+
+	records, err := svc.history.Load(time.Now().Add(-time.Hour))
+	if err != nil || len(records) != 1 {
+		t.Fatalf("expected one history record, got %v (%v)", records, err)
+	}
+	if rec := records[0]; rec.Provider != "gpu-box" || rec.Cost != 0.02 {
+		t.Errorf("expected the fallback's provider and cost recorded, got %+v", rec)
+	}
+}
+
+func TestTranscribe_SkipsProviderOverBudget(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.Usage = &UsageConfig{CostPerMinute: 0.01, MonthlyBudget: 5}
+	cfg.Fallbacks = []FallbackConfig{{Provider: ProviderDeepgram, Usage: &UsageConfig{CostPerMinute: 0.005, MonthlyBudget: 5}}}
+
+	primary := &countingClient{}
+	svc := newDedupService(t, cfg, primary)
+	fallback := &countingClient{}
+	svc.fallbacks[0].client = fallback
+
+	// The primary's budget is spent; the fallback's spend is its own
+	now := time.Now()
+	svc.history.Append(history.Record{Time: now, Status: history.StatusCompleted, Provider: "localhost:9000", Cost: 5})
+	svc.history.Append(history.Record{Time: now, Status: history.StatusCompleted, Provider: "deepgram", Cost: 1})
+
+	if !svc.fallbackAvailable() {
+		t.Fatal("expected the fallback available")
+	}
+	result, err := svc.transcribe(context.Background(), svc.logger, "memo.wav")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.Provider != "deepgram" {
+		t.Errorf("expected the fallback to transcribe, got %q", result.Provider)
+	}
+	if primary.calls.Load() != 0 || fallback.calls.Load() != 1 {
+		t.Errorf("expected only the fallback called, got %d and %d", primary.calls.Load(), fallback.calls.Load())
+	}
+
+	svc.history.Append(history.Record{Time: now, Status: history.StatusCompleted, Provider: "deepgram", Cost: 4})
+	svc.fallbacks[0].budget.month = time.Time{}
+	if svc.fallbackAvailable() {
+		t.Error("expected no fallback available once its budget is spent")
+	}
+	if _, err := svc.transcribe(context.Background(), svc.logger, "memo.wav"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected ErrBudgetExceeded, got: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+// Package fileperm applies configured permissions and group ownership to the
+// notes, archived audio and directories the transcription service creates.
+package fileperm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+)
+
+// Default modes for files and directories when none are configured. The
+// process umask applies to them as usual.
+const (
+	DefaultFileMode os.FileMode = 0644
+	DefaultDirMode  os.FileMode = 0755
+)
+
+// Permissions describes how created files and directories are set up. The
+// zero value keeps the defaults and does not change group ownership.
+type Permissions struct {
+	// FileMode, when non-zero, is set on created files regardless of umask.
+	FileMode os.FileMode
+	// DirMode, when non-zero, is set on created directories regardless of umask.
+	DirMode os.FileMode
+	// Group, when set, is the group name or numeric ID created files and
+	// directories are assigned to.
+	Group string
+}
+
+// ParseMode parses an octal permission string such as "0664" or "664".
+// An empty string returns 0, meaning the default.
+func ParseMode(s string) (os.FileMode, error) {
+	if s == "" {
+		return 0, nil
+	}
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid mode %q: must be octal permissions such as 0664", s)
+	}
+	return os.FileMode(mode), nil
+}
+
+// Validate checks that Group names an existing group.
+func (p Permissions) Validate() error {
+	_, err := p.gid()
+	return err
+}
+
+// gid returns the numeric ID of Group, or -1 when no group is set.
+func (p Permissions) gid() (int, error) {
+	if p.Group == "" {
+		return -1, nil
+	}
+	if id, err := strconv.Atoi(p.Group); err == nil {
+		return id, nil
+	}
+	g, err := user.LookupGroup(p.Group)
+	if err != nil {
+		return 0, fmt.Errorf("unknown group %q: %w", p.Group, err)
+	}
+	return strconv.Atoi(g.Gid)
+}
+
+// MkdirAll creates dir and any missing parents, applying DirMode and Group
+// to each directory it creates. Existing directories are left untouched.
+func (p Permissions) MkdirAll(dir string) error {
+	var missing []string
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil || !errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		missing = append(missing, d)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+
+	mode := p.DirMode
+	if mode == 0 {
+		mode = DefaultDirMode
+	}
+	if err := os.MkdirAll(dir, mode); err != nil {
+		return err
+	}
+
+	for i := len(missing) - 1; i >= 0; i-- {
+		if err := p.apply(missing[i], p.DirMode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteFile writes data to path like os.WriteFile, then applies FileMode and
+// Group.
+func (p Permissions) WriteFile(path string, data []byte) error {
+	mode := p.FileMode
+	if mode == 0 {
+		mode = DefaultFileMode
+	}
+	if err := os.WriteFile(path, data, mode); err != nil {
+		return err
+	}
+	return p.ApplyFile(path)
+}
+
+// ApplyFile applies FileMode and Group to an existing file, such as one
+// moved into place.
+func (p Permissions) ApplyFile(path string) error {
+	return p.apply(path, p.FileMode)
+}
+
+// apply sets mode, if non-zero, and the configured group on path.
+func (p Permissions) apply(path string, mode os.FileMode) error {
+	if mode != 0 {
+		if err := os.Chmod(path, mode); err != nil {
+			return fmt.Errorf("set mode on %s: %w", path, err)
+		}
+	}
+	gid, err := p.gid()
+	if err != nil {
+		return err
+	}
+	if gid >= 0 {
+		if err := os.Lchown(path, -1, gid); err != nil {
+			return fmt.Errorf("set group on %s: %w", path, err)
+		}
+	}
+	return nil
+}
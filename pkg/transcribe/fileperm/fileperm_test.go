@@ -0,0 +1,107 @@
+package fileperm
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected os.FileMode
+		wantErr  bool
+	}{
+		{"", 0, false},
+		{"0664", 0664, false},
+		{"750", 0750, false},
+		{"0999", 0, true},
+		{"1777", 0, true},
+		{"rw-r--r--", 0, true},
+	}
+
+	for _, tt := range tests {
+		mode, err := ParseMode(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMode(%q): expected error", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMode(%q): expected no error, got: %v", tt.input, err)
+		}
+		if mode != tt.expected {
+			t.Errorf("ParseMode(%q): expected %o, got %o", tt.input, tt.expected, mode)
+		}
+	}
+}
+
+func TestMkdirAll_AppliesDirModeToCreatedDirs(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Chmod(root, 0700); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+
+	p := Permissions{DirMode: 0775}
+	dir := filepath.Join(root, "2026", "01")
+	if err := p.MkdirAll(dir); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	for _, d := range []string{filepath.Join(root, "2026"), dir} {
+		info, err := os.Stat(d)
+		if err != nil {
+			t.Fatalf("stat %s: %v", d, err)
+		}
+		if info.Mode().Perm() != 0775 {
+			t.Errorf("expected %s mode 0775, got %o", d, info.Mode().Perm())
+		}
+	}
+
+	info, _ := os.Stat(root)
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("expected existing directory to keep mode 0700, got %o", info.Mode().Perm())
+	}
+}
+
+func TestWriteFile_AppliesFileModeAndGroup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "note.md")
+	gid := os.Getgid()
+
+	p := Permissions{FileMode: 0666, Group: strconv.Itoa(gid)}
+	if err := p.WriteFile(path, []byte("hello")); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if info.Mode().Perm() != 0666 {
+		t.Errorf("expected mode 0666 regardless of umask, got %o", info.Mode().Perm())
+	}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok && int(st.Gid) != gid {
+		t.Errorf("expected group %d, got %d", gid, st.Gid)
+	}
+}
+
+func TestWriteFile_DefaultMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "note.md")
+	if err := (Permissions{}).WriteFile(path, []byte("hello")); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm()&^DefaultFileMode != 0 {
+		t.Errorf("expected mode within %o, got %o", DefaultFileMode, info.Mode().Perm())
+	}
+}
+
+func TestValidate_UnknownGroup(t *testing.T) {
+	p := Permissions{Group: "no-such-group-nota"}
+	if err := p.Validate(); err == nil {
+		t.Error("expected error for unknown group")
+	}
+}
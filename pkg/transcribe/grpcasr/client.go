@@ -0,0 +1,310 @@
+// Package grpcasr provides a transcription client for servers implementing
+// the gRPC service in asr.proto. Audio is streamed to the server in chunks
+// rather than uploaded as one request body.
+//
+// The client speaks gRPC's HTTP/2 wire protocol directly over net/http:
+// unencrypted HTTP/2 (h2c) for http URLs and HTTP/2 over TLS for https ones.
+package grpcasr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// DefaultChunkSize is the most audio sent in one message.
+const DefaultChunkSize = 64 * 1024
+
+// recognizePath is the HTTP/2 path of the Recognize method.
+const recognizePath = "/nota.asr.v1.Transcriber/Recognize"
+
+// Client implements client.TranscriptionClient over gRPC.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	chunkSize  int
+	// limiter, if set, throttles uploads; onUpload is told about each.
+	limiter  *client.Limiter
+	onUpload func(client.UploadStats)
+}
+
+// Option configures the Client.
+type Option func(*Client)
+
+// WithHTTPClient sets a custom HTTP client. Its transport must speak HTTP/2;
+// see ConfigureTransport.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithChunkSize sets the most audio sent in one message.
+func WithChunkSize(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.chunkSize = n
+		}
+	}
+}
+
+// WithUploadLimit throttles uploads with l, which may be shared with other
+// clients to limit their combined rate.
+func WithUploadLimit(l *client.Limiter) Option {
+	return func(c *Client) {
+		c.limiter = l
+	}
+}
+
+// WithUploadReporter calls f with the size and duration of each upload once
+// the audio has been sent.
+func WithUploadReporter(f func(client.UploadStats)) Option {
+	return func(c *Client) {
+		c.onUpload = f
+	}
+}
+
+// New creates a client for the server at baseURL, such as
+// http://localhost:50051. A path in baseURL is kept as a prefix of the
+// method path, for servers behind a proxy.
+func New(baseURL string, opts ...Option) *Client {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	ConfigureTransport(t, baseURL)
+	c := &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: client.DefaultTimeout, Transport: t},
+		chunkSize:  DefaultChunkSize,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// ConfigureTransport makes t speak only HTTP/2, as gRPC requires: over TLS
+// for https URLs, and unencrypted with prior knowledge (h2c) for http ones.
+func ConfigureTransport(t *http.Transport, baseURL string) {
+	t.Protocols = new(http.Protocols)
+	if u, err := url.Parse(baseURL); err == nil && u.Scheme == "http" {
+		t.Protocols.SetUnencryptedHTTP2(true)
+		return
+	}
+	t.Protocols.SetHTTP2(true)
+}
+
+// Transcribe streams an audio file to the server and returns the
+// transcription.
+func (c *Client) Transcribe(ctx context.Context, audioPath string, opts client.TranscribeOptions) (*client.TranscriptionResult, error) {
+	file, err := os.Open(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("open audio file: %w", err)
+	}
+	defer file.Close()
+
+	language := opts.Language
+	if language == "auto" {
+		language = ""
+	}
+	config := configMessage(language, filepath.Base(audioPath), opts)
+
+	// Stream the messages through a pipe, throttled as configured
+	var audio io.Reader = file
+	if c.limiter != nil {
+		audio = c.limiter.Reader(ctx, audio)
+	}
+	body, w := io.Pipe()
+	var sent upload
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.CloseWithError(c.send(w, config, audio, &sent))
+	}()
+
+	resp, reused, err := c.call(ctx, body)
+	result, err := c.finish(ctx, resp, err)
+
+	// The request is over; stop the sender if the server answered early
+	body.CloseWithError(errors.New("request finished"))
+	<-done
+	if c.onUpload != nil && sent.bytes > 0 {
+		c.onUpload(client.UploadStats{
+			Path:    audioPath,
+			Bytes:   sent.bytes,
+			Elapsed: sent.end.Sub(sent.start),
+			Reused:  reused,
+		})
+	}
+	return result, err
+}
+
+// upload times the audio sent.
+type upload struct {
+	bytes      int64
+	start, end time.Time
+}
+
+// send writes the config message and then the audio, in chunks.
+func (c *Client) send(w io.Writer, config []byte, audio io.Reader, sent *upload) error {
+	if err := writeFrame(w, config); err != nil {
+		return err
+	}
+	sent.start = time.Now()
+	buf := make([]byte, c.chunkSize)
+	for {
+		n, err := io.ReadFull(audio, buf)
+		if n > 0 {
+			if werr := writeFrame(w, audioMessage(buf[:n])); werr != nil {
+				return werr
+			}
+			sent.bytes += int64(n)
+			sent.end = time.Now()
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read audio file: %w", err)
+		}
+	}
+}
+
+// call starts the Recognize call with body as the request stream.
+func (c *Client) call(ctx context.Context, body io.Reader) (*http.Response, bool, error) {
+	reqURL := strings.TrimRight(c.baseURL, "/") + recognizePath
+	var reused bool
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) { reused = info.Reused },
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, body)
+	if err != nil {
+		return nil, false, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/grpc+proto")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := c.httpClient.Do(req)
+	return resp, reused, err
+}
+
+// finish reads the response to the call and its status.
+func (c *Client) finish(ctx context.Context, resp *http.Response, err error) (*client.TranscriptionResult, error) {
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("send request: %w", err)
+		}
+		return nil, fmt.Errorf("send request: %w: %w", client.ErrUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &client.APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/grpc") {
+		return nil, fmt.Errorf("not a gRPC server: response content type %q", resp.Header.Get("Content-Type"))
+	}
+	// A call failing before any response carries its status in the headers
+	if ok, err := callStatus(resp.Header); ok {
+		if err == nil {
+			err = errors.New("server sent no response")
+		}
+		return nil, err
+	}
+
+	var msg []byte
+	for {
+		frame, err := readFrame(resp.Body)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read response: %w", err)
+		}
+		if msg != nil {
+			return nil, errors.New("server sent more than one response")
+		}
+		msg = frame
+	}
+	ok, err := callStatus(resp.Trailer)
+	if !ok {
+		return nil, errors.New("server ended the call without a status")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, errors.New("server sent no response")
+	}
+
+	result, err := parseResponse(msg)
+	if err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return result, nil
+}
+
+// codes names the gRPC status codes and maps them to the HTTP status the
+// rest of nota classifies failures by: 4xx are not retried, 5xx are.
+var codes = map[int]struct {
+	name string
+	http int
+}{
+	1:  {"CANCELLED", 499},
+	2:  {"UNKNOWN", http.StatusInternalServerError},
+	3:  {"INVALID_ARGUMENT", http.StatusBadRequest},
+	4:  {"DEADLINE_EXCEEDED", http.StatusGatewayTimeout},
+	5:  {"NOT_FOUND", http.StatusNotFound},
+	6:  {"ALREADY_EXISTS", http.StatusConflict},
+	7:  {"PERMISSION_DENIED", http.StatusForbidden},
+	8:  {"RESOURCE_EXHAUSTED", http.StatusTooManyRequests},
+	9:  {"FAILED_PRECONDITION", http.StatusBadRequest},
+	10: {"ABORTED", http.StatusConflict},
+	11: {"OUT_OF_RANGE", http.StatusBadRequest},
+	12: {"UNIMPLEMENTED", http.StatusNotImplemented},
+	13: {"INTERNAL", http.StatusInternalServerError},
+	14: {"UNAVAILABLE", http.StatusServiceUnavailable},
+	15: {"DATA_LOSS", http.StatusInternalServerError},
+	16: {"UNAUTHENTICATED", http.StatusUnauthorized},
+}
+
+// callStatus reports whether h has a grpc-status, and returns the error
+// it stands for, or nil if it is OK.
+func callStatus(h http.Header) (bool, error) {
+	raw := h.Get("Grpc-Status")
+	if raw == "" {
+		return false, nil
+	}
+	code, err := strconv.Atoi(raw)
+	if err != nil {
+		return true, fmt.Errorf("invalid grpc-status %q", raw)
+	}
+	if code == 0 {
+		return true, nil
+	}
+	message, err := url.PathUnescape(h.Get("Grpc-Message"))
+	if err != nil {
+		message = h.Get("Grpc-Message")
+	}
+	known, ok := codes[code]
+	if !ok {
+		known = codes[2]
+		known.name = fmt.Sprintf("code %d", code)
+	}
+	return true, &client.APIError{
+		StatusCode: known.http,
+		Body:       fmt.Sprintf("grpc %s: %s", known.name, message),
+	}
+}
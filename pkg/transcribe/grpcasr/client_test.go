@@ -0,0 +1,264 @@
+package grpcasr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// fakeServer implements Recognize, recording what it was sent.
+type fakeServer struct {
+	// response is sent when status is 0.
+	response []byte
+	status   string
+	message  string
+
+	mu       sync.Mutex
+	config   map[int]string
+	terms    []string
+	vad      bool
+	audio    bytes.Buffer
+	messages int
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != recognizePath || r.Header.Get("Content-Type") != "application/grpc+proto" || r.ProtoMajor != 2 {
+		http.Error(w, "not a gRPC call", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc")
+	if s.status != "" {
+		// Trailers-only, as servers fail calls
+		w.Header().Set("Grpc-Status", s.status)
+		w.Header().Set("Grpc-Message", s.message)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = make(map[int]string)
+	s.terms = nil
+	s.vad = false
+	for {
+		msg, err := readFrame(r.Body)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.messages++
+		eachField(msg, func(f field) error {
+			switch f.num {
+			case requestConfig:
+				return eachField(f.data, func(c field) error {
+					switch c.num {
+					case configVocabulary:
+						s.terms = append(s.terms, string(c.data))
+						return nil
+					case configVADFilter:
+						s.vad = c.value == 1
+						return nil
+					}
+					s.config[c.num] = string(c.data)
+					return nil
+				})
+			case requestAudio:
+				s.audio.Write(f.data)
+			}
+			return nil
+		})
+	}
+
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+	writeFrame(w, s.response)
+	w.Header().Set("Grpc-Status", "0")
+}
+
+// startH2C serves h over unencrypted HTTP/2.
+func startH2C(t *testing.T, h http.Handler) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(h)
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeAudio(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "memo.m4a")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write audio: %v", err)
+	}
+	return path
+}
+
+func TestClient_StreamsAudioAndParsesResponse(t *testing.T) {
+	var segment []byte
+	segment = appendDouble(segment, segmentStart, 0.5)
+	segment = appendDouble(segment, segmentEnd, 2.25)
+	segment = appendString(segment, segmentText, "Buy milk.")
+	var response []byte
+	response = appendString(response, responseText, "Buy milk.")
+	response = appendString(response, responseLanguage, "en")
+	response = appendDouble(response, responseDuration, 2.5)
+	response = appendBytes(response, responseSegments, segment)
+
+	fake := &fakeServer{response: response}
+	server := startH2C(t, fake)
+
+	audio := []byte("0123456789abcdef-fake-audio")
+	path := writeAudio(t, audio)
+	var stats []client.UploadStats
+	c := New(server.URL, WithChunkSize(8), WithUploadReporter(func(u client.UploadStats) {
+		stats = append(stats, u)
+	}))
+
+	result, err := c.Transcribe(context.Background(), path, client.TranscribeOptions{
+		Language:   "en",
+		Model:      "small",
+		Prompt:     "Weekly planning.",
+		Vocabulary: []string{"Nota", "Orbis"},
+		VADFilter:  true,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if result.Text != "Buy milk." || result.Language != "en" || result.Duration != 2.5 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	want := []client.Segment{{Start: 0.5, End: 2.25, Text: "Buy milk."}}
+	if len(result.Segments) != 1 || result.Segments[0] != want[0] {
+		t.Errorf("expected segments %+v, got %+v", want, result.Segments)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if !bytes.Equal(fake.audio.Bytes(), audio) {
+		t.Errorf("expected the server to receive the audio, got %q", fake.audio.Bytes())
+	}
+	// One config message, then 27 bytes in chunks of 8
+	if fake.messages != 5 {
+		t.Errorf("expected 5 messages, got %d", fake.messages)
+	}
+	if fake.config[configLanguage] != "en" || fake.config[configModel] != "small" || fake.config[configFilename] != "memo.m4a" || fake.config[configPrompt] != "Weekly planning." {
+		t.Errorf("unexpected config: %v", fake.config)
+	}
+	if len(fake.terms) != 2 || fake.terms[0] != "Nota" || fake.terms[1] != "Orbis" {
+		t.Errorf("expected the vocabulary sent, got %q", fake.terms)
+	}
+	if !fake.vad {
+		t.Error("expected the VAD filter requested")
+	}
+	if len(stats) != 1 || stats[0].Bytes != int64(len(audio)) || stats[0].Path != path {
+		t.Errorf("expected one upload of %d bytes reported, got %+v", len(audio), stats)
+	}
+}
+
+func TestClient_AutoLanguageIsDetected(t *testing.T) {
+	fake := &fakeServer{response: appendString(nil, responseText, "Hallo.")}
+	server := startH2C(t, fake)
+
+	c := New(server.URL)
+	if _, err := c.Transcribe(context.Background(), writeAudio(t, []byte("audio")), client.TranscribeOptions{Language: "auto"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if lang, ok := fake.config[configLanguage]; ok {
+		t.Errorf("expected no language sent, got %q", lang)
+	}
+}
+
+func TestClient_ErrorStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		wantStatus int
+	}{
+		{name: "invalid argument", status: "3", wantStatus: http.StatusBadRequest},
+		{name: "unavailable", status: "14", wantStatus: http.StatusServiceUnavailable},
+		{name: "unknown code", status: "99", wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := startH2C(t, &fakeServer{status: tt.status, message: "model%20not%20loaded"})
+
+			c := New(server.URL)
+			_, err := c.Transcribe(context.Background(), writeAudio(t, []byte("audio")), client.TranscribeOptions{})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := client.StatusCode(err); got != tt.wantStatus {
+				t.Errorf("expected status %d, got %d (%v)", tt.wantStatus, got, err)
+			}
+			if !bytes.Contains([]byte(err.Error()), []byte("model not loaded")) {
+				t.Errorf("expected the decoded message in %q", err)
+			}
+		})
+	}
+}
+
+func TestClient_Unreachable(t *testing.T) {
+	server := startH2C(t, &fakeServer{})
+	server.Close()
+
+	c := New(server.URL)
+	_, err := c.Transcribe(context.Background(), writeAudio(t, []byte("audio")), client.TranscribeOptions{})
+	if !errors.Is(err, client.ErrUnreachable) {
+		t.Errorf("expected ErrUnreachable, got: %v", err)
+	}
+}
+
+func TestClient_RejectsNonGRPCServer(t *testing.T) {
+	server := startH2C(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"text":"hello"}`))
+	}))
+
+	c := New(server.URL)
+	_, err := c.Transcribe(context.Background(), writeAudio(t, []byte("audio")), client.TranscribeOptions{})
+	if err == nil {
+		t.Fatal("expected an error from a server not speaking gRPC")
+	}
+}
+
+func TestParseResponse_SkipsUnknownFields(t *testing.T) {
+	var msg []byte
+	msg = appendString(msg, responseText, "Hello.")
+	msg = appendString(msg, 15, "added by a newer server")
+	msg = appendDouble(msg, 16, 1)
+
+	result, err := parseResponse(msg)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.Text != "Hello." {
+		t.Errorf("expected text Hello., got %q", result.Text)
+	}
+}
+
+func TestParseResponse_Malformed(t *testing.T) {
+	msg := appendString(nil, responseText, "Hello.")
+	for _, b := range [][]byte{msg[:len(msg)-2], {0x0b}, {0x19, 1, 2}} {
+		if _, err := parseResponse(b); err == nil {
+			t.Errorf("expected an error parsing %x", b)
+		}
+	}
+}
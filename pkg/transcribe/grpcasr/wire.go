@@ -0,0 +1,236 @@
+package grpcasr
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// The few protobuf wire types asr.proto uses.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// Field numbers from asr.proto.
+const (
+	requestConfig = 1
+	requestAudio  = 2
+
+	configLanguage   = 1
+	configModel      = 2
+	configFilename   = 3
+	configPrompt     = 4
+	configVocabulary = 5
+	configVADFilter  = 6
+
+	responseText     = 1
+	responseLanguage = 2
+	responseDuration = 3
+	responseSegments = 4
+
+	segmentStart = 1
+	segmentEnd   = 2
+	segmentText  = 3
+)
+
+// maxMessageSize bounds the response accepted, like gRPC's default limit.
+const maxMessageSize = 4 << 20
+
+var errMalformed = errors.New("malformed protobuf message")
+
+// appendBytes appends a length-delimited field.
+func appendBytes(b []byte, num int, v []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(num)<<3|wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// appendString appends a string field, omitted when empty as in proto3.
+func appendString(b []byte, num int, v string) []byte {
+	if v == "" {
+		return b
+	}
+	return appendBytes(b, num, []byte(v))
+}
+
+// appendDouble appends a double field, omitted when zero as in proto3.
+func appendDouble(b []byte, num int, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = binary.AppendUvarint(b, uint64(num)<<3|wireFixed64)
+	return binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+}
+
+// appendBool appends a bool field, omitted when false as in proto3.
+func appendBool(b []byte, num int, v bool) []byte {
+	if !v {
+		return b
+	}
+	b = binary.AppendUvarint(b, uint64(num)<<3|wireVarint)
+	return append(b, 1)
+}
+
+// field is one decoded field of a message. Value holds varints and fixed
+// numbers; data holds length-delimited contents.
+type field struct {
+	num   int
+	typ   int
+	value uint64
+	data  []byte
+}
+
+// eachField calls fn with every field of the message in b, in order.
+func eachField(b []byte, fn func(f field) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errMalformed
+		}
+		b = b[n:]
+		f := field{num: int(key >> 3), typ: int(key & 7)}
+		switch f.typ {
+		case wireVarint:
+			f.value, n = binary.Uvarint(b)
+			if n <= 0 {
+				return errMalformed
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return errMalformed
+			}
+			f.value = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return errMalformed
+			}
+			f.value = uint64(binary.LittleEndian.Uint32(b))
+			b = b[4:]
+		case wireBytes:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || size > uint64(len(b)-n) {
+				return errMalformed
+			}
+			f.data = b[n : n+int(size)]
+			b = b[n+int(size):]
+		default:
+			return fmt.Errorf("%w: wire type %d", errMalformed, f.typ)
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// double returns a fixed64 field as a float64.
+func (f field) double() float64 {
+	return math.Float64frombits(f.value)
+}
+
+// configMessage encodes a RecognizeRequest carrying the config.
+func configMessage(language, filename string, opts client.TranscribeOptions) []byte {
+	var cfg []byte
+	cfg = appendString(cfg, configLanguage, language)
+	cfg = appendString(cfg, configModel, opts.Model)
+	cfg = appendString(cfg, configFilename, filename)
+	cfg = appendString(cfg, configPrompt, opts.Prompt)
+	for _, term := range opts.Vocabulary {
+		cfg = appendString(cfg, configVocabulary, term)
+	}
+	cfg = appendBool(cfg, configVADFilter, opts.VADFilter)
+	return appendBytes(nil, requestConfig, cfg)
+}
+
+// audioMessage encodes a RecognizeRequest carrying a chunk of audio.
+func audioMessage(chunk []byte) []byte {
+	return appendBytes(make([]byte, 0, len(chunk)+8), requestAudio, chunk)
+}
+
+// parseResponse decodes a RecognizeResponse. Unknown fields are skipped, so
+// servers may add to the message.
+func parseResponse(b []byte) (*client.TranscriptionResult, error) {
+	result := &client.TranscriptionResult{}
+	err := eachField(b, func(f field) error {
+		switch {
+		case f.num == responseText && f.typ == wireBytes:
+			result.Text = string(f.data)
+		case f.num == responseLanguage && f.typ == wireBytes:
+			result.Language = string(f.data)
+		case f.num == responseDuration && f.typ == wireFixed64:
+			result.Duration = f.double()
+		case f.num == responseSegments && f.typ == wireBytes:
+			seg, err := parseSegment(f.data)
+			if err != nil {
+				return err
+			}
+			result.Segments = append(result.Segments, seg)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func parseSegment(b []byte) (client.Segment, error) {
+	var seg client.Segment
+	err := eachField(b, func(f field) error {
+		switch {
+		case f.num == segmentStart && f.typ == wireFixed64:
+			seg.Start = f.double()
+		case f.num == segmentEnd && f.typ == wireFixed64:
+			seg.End = f.double()
+		case f.num == segmentText && f.typ == wireBytes:
+			seg.Text = string(f.data)
+		}
+		return nil
+	})
+	return seg, err
+}
+
+// writeFrame writes msg as a gRPC length-prefixed message: an uncompressed
+// flag byte, the length as 4 bytes big-endian, then the message.
+func writeFrame(w io.Writer, msg []byte) error {
+	var header [5]byte
+	binary.BigEndian.PutUint32(header[1:], uint32(len(msg)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(msg)
+	return err
+}
+
+// readFrame reads one gRPC length-prefixed message. It returns io.EOF when
+// the stream ends cleanly between messages.
+func readFrame(r io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("read message header: %w", err)
+		}
+		return nil, err
+	}
+	if header[0] != 0 {
+		return nil, errors.New("compressed messages are not supported")
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxMessageSize {
+		return nil, fmt.Errorf("message of %d bytes exceeds the %d byte limit", size, maxMessageSize)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, fmt.Errorf("read message: %w", err)
+	}
+	return msg, nil
+}
@@ -0,0 +1,333 @@
+// Package history records the outcome of every processed file.
+// Records are appended as JSON lines so the store survives log rotation and
+// can be aggregated across any time window.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/dirs"
+)
+
+// Record statuses
+const (
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	// StatusSkipped is a file deliberately not transcribed, with the reason
+	// in Category.
+	StatusSkipped = "skipped"
+)
+
+// Failure categories, recording which step a failed file stopped at and,
+// where it is known, why
+const (
+	CategoryTooLarge             = "too_large"
+	CategoryUnsafePath           = "unsafe_path"
+	CategoryStabilization        = "stabilization"
+	CategoryStabilizationTimeout = "stabilization_timeout"
+	CategoryDiskSpace            = "disk_space"
+	CategoryAPIUnreachable       = "api_unreachable"
+	// CategoryAPI4xx is a request the transcription API rejected with a
+	// 4xx status, which retrying does not fix.
+	CategoryAPI4xx        = "api_4xx"
+	CategoryTranscription = "transcription"
+	CategoryTimeout       = "timeout"
+	CategoryWrite         = "write_failed"
+	CategoryArchive       = "archive_failed"
+	// CategoryBudget is a file not transcribed because the monthly budget
+	// for a paid transcription API was used up.
+	CategoryBudget = "budget_exceeded"
+	// CategoryHook is a file a pre_upload or post_transcript hook, or a
+	// pipeline stage run before writing, failed for.
+	CategoryHook = "hook_failed"
+	// CategoryOther covers failures recorded without a category, such as
+	// those from older versions.
+	CategoryOther = "other"
+)
+
+// Skip reasons, recorded as the category of skipped files
+const (
+	CategoryTooSmall = "too_small"
+	CategoryTooShort = "too_short"
+	// CategoryNoteExists is a recording a note was already written for,
+	// archived without transcribing it again.
+	CategoryNoteExists = "note_exists"
+)
+
+// Record is the outcome of processing a single file.
+type Record struct {
+	Time      time.Time `json:"time"`
+	Source    string    `json:"source"`
+	Output    string    `json:"output,omitempty"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Category  string    `json:"category,omitempty"`
+	ElapsedMs int64     `json:"elapsed_ms"`
+	// AudioSeconds and Words describe completed files: the length of the
+	// recording and the number of words transcribed.
+	AudioSeconds float64 `json:"audio_seconds,omitempty"`
+	Words        int     `json:"words,omitempty"`
+	// TranscribeMs is the part of ElapsedMs a completed file spent being
+	// transcribed, without waiting to stabilize or for a worker.
+	TranscribeMs int64 `json:"transcribe_ms,omitempty"`
+	// Provider names the transcription API a completed file was sent to, and
+	// Cost is the estimated price of transcribing it, when a rate is
+	// configured.
+	Provider string  `json:"provider,omitempty"`
+	Cost     float64 `json:"cost,omitempty"`
+	// Stage is the last pipeline stage the file completed: archived or
+	// written for completed files, and where a failed one stopped.
+	Stage string `json:"stage,omitempty"`
+	// SourceHash is the SHA-256 of a recording Output was written for, so
+	// the note can be found when the recording is processed again, in any
+	// output format.
+	SourceHash string `json:"source_hash,omitempty"`
+}
+
+// Elapsed returns the processing time of the record.
+func (r Record) Elapsed() time.Duration {
+	return time.Duration(r.ElapsedMs) * time.Millisecond
+}
+
+// TranscribeTime returns the time the record's file spent being
+// transcribed, if known.
+func (r Record) TranscribeTime() time.Duration {
+	return time.Duration(r.TranscribeMs) * time.Millisecond
+}
+
+// Store is an append-only history file.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// DefaultPath returns the default history file path in the state directory
+// ($XDG_STATE_HOME/nota or ~/.nota, under history/transcribe.jsonl)
+func DefaultPath() (string, error) {
+	dir, err := dirs.State()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history", "transcribe.jsonl"), nil
+}
+
+// New creates a store backed by the file at path. The file is created on first append.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Open creates a store at the default path.
+func Open() (*Store, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return New(path), nil
+}
+
+// Path returns the history file path.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append adds a record to the history file.
+func (s *Store) Append(rec Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("create history directory: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open history file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write history record: %w", err)
+	}
+	return nil
+}
+
+// AudioDuration returns the length of the recording, if known.
+func (r Record) AudioDuration() time.Duration {
+	return time.Duration(r.AudioSeconds * float64(time.Second))
+}
+
+// FailureCategory returns the record's failure category, CategoryOther for
+// failures recorded without one.
+func (r Record) FailureCategory() string {
+	if r.Category == "" {
+		return CategoryOther
+	}
+	return r.Category
+}
+
+// Load returns all records at or after since, oldest first.
+// A zero since returns every record. Returns no records if the file doesn't exist.
+// Lines that fail to parse (e.g. a torn final write) are skipped.
+func (s *Store) Load(since time.Time) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		if !since.IsZero() && rec.Time.Before(since) {
+			continue
+		}
+		records = append(records, rec)
+	}
+
+	return records, scanner.Err()
+}
+
+// Summary aggregates a set of records.
+type Summary struct {
+	Processed      int
+	Failed         int
+	AverageElapsed time.Duration
+	LastProcessed  *Record
+	// Elapsed, AudioDuration and Words are totals over completed files.
+	Elapsed       time.Duration
+	AudioDuration time.Duration
+	Words         int
+	// Failures counts failed files by category.
+	Failures map[string]int
+	// Skipped counts files deliberately not transcribed, which are not part
+	// of Total.
+	Skipped int
+	// Usage totals completed files by provider; Cost is their total
+	// estimated cost.
+	Usage map[string]Usage
+	Cost  float64
+}
+
+// Usage is what was transcribed with one provider.
+type Usage struct {
+	Files         int
+	AudioDuration time.Duration
+	Cost          float64
+}
+
+// Total returns the number of files attempted.
+func (s Summary) Total() int {
+	return s.Processed + s.Failed
+}
+
+// Speed returns how many seconds of audio were transcribed per second of
+// processing, or 0 when no audio durations were recorded.
+func (s Summary) Speed() float64 {
+	if s.Elapsed <= 0 || s.AudioDuration <= 0 {
+		return 0
+	}
+	return s.AudioDuration.Seconds() / s.Elapsed.Seconds()
+}
+
+// FailureRate returns the fraction of attempts that failed, between 0 and 1.
+func (s Summary) FailureRate() float64 {
+	if s.Total() == 0 {
+		return 0
+	}
+	return float64(s.Failed) / float64(s.Total())
+}
+
+// Summarize aggregates records. Average processing time covers completed files only.
+func Summarize(records []Record) Summary {
+	var sum Summary
+
+	for i := range records {
+		rec := records[i]
+		switch rec.Status {
+		case StatusCompleted:
+			sum.Processed++
+			sum.Elapsed += rec.Elapsed()
+			sum.AudioDuration += rec.AudioDuration()
+			sum.Words += rec.Words
+			sum.Cost += rec.Cost
+			if rec.Provider != "" {
+				if sum.Usage == nil {
+					sum.Usage = make(map[string]Usage)
+				}
+				u := sum.Usage[rec.Provider]
+				u.Files++
+				u.AudioDuration += rec.AudioDuration()
+				u.Cost += rec.Cost
+				sum.Usage[rec.Provider] = u
+			}
+			if sum.LastProcessed == nil || !rec.Time.Before(sum.LastProcessed.Time) {
+				sum.LastProcessed = &records[i]
+			}
+		case StatusFailed:
+			sum.Failed++
+			if sum.Failures == nil {
+				sum.Failures = make(map[string]int)
+			}
+			sum.Failures[rec.FailureCategory()]++
+		case StatusSkipped:
+			sum.Skipped++
+		}
+	}
+
+	if sum.Processed > 0 {
+		sum.AverageElapsed = sum.Elapsed / time.Duration(sum.Processed)
+	}
+	return sum
+}
+
+// ParseSince parses a look-back window such as "7d", "12h" or "90m".
+// In addition to time.ParseDuration units it accepts d (days) and w (weeks).
+func ParseSince(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	unit := s[len(s)-1]
+	if unit == 'd' || unit == 'w' {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		day := 24 * time.Hour
+		if unit == 'w' {
+			return time.Duration(n) * 7 * day, nil
+		}
+		return time.Duration(n) * day, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,220 @@
+package history
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStore_AppendAndLoad(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), "history", "transcribe.jsonl"))
+
+	base := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Time: base, Source: "/in/a.m4a", Output: "/out/a.md", Status: StatusCompleted, ElapsedMs: 2000},
+		{Time: base.Add(time.Hour), Source: "/in/b.m4a", Status: StatusFailed, Error: "connection refused", ElapsedMs: 500},
+		{Time: base.Add(48 * time.Hour), Source: "/in/c.m4a", Output: "/out/c.md", Status: StatusCompleted, ElapsedMs: 4000},
+	}
+	for _, rec := range records {
+		if err := store.Append(rec); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	all, err := store.Load(time.Time{})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(all))
+	}
+	if all[1].Error != "connection refused" {
+		t.Errorf("expected error to round-trip, got %q", all[1].Error)
+	}
+
+	recent, err := store.Load(base.Add(24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(recent) != 1 || recent[0].Source != "/in/c.m4a" {
+		t.Errorf("expected only c.m4a since cutoff, got %+v", recent)
+	}
+}
+
+func TestStore_LoadMissingFile(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), "missing.jsonl"))
+
+	records, err := store.Load(time.Time{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("expected no records, got %d", len(records))
+	}
+}
+
+func TestStore_LoadSkipsCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transcribe.jsonl")
+	content := `{"time":"2026-01-22T10:00:00Z","source":"/in/a.m4a","status":"completed","elapsed_ms":1000}
+{"time":"2026-01-22T11:00:00Z","sou
+`
+	os.WriteFile(path, []byte(content), 0644)
+
+	records, err := New(path).Load(time.Time{})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Errorf("expected 1 valid record, got %d", len(records))
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	base := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Time: base, Source: "a", Status: StatusCompleted, ElapsedMs: 2000},
+		{Time: base.Add(time.Minute), Source: "b", Status: StatusFailed, ElapsedMs: 100},
+		{Time: base.Add(2 * time.Minute), Source: "c", Status: StatusCompleted, ElapsedMs: 4000},
+		{Time: base.Add(3 * time.Minute), Source: "d", Status: StatusCompleted, ElapsedMs: 6000},
+	}
+
+	sum := Summarize(records)
+
+	if sum.Processed != 3 {
+		t.Errorf("expected 3 processed, got %d", sum.Processed)
+	}
+	if sum.Failed != 1 {
+		t.Errorf("expected 1 failed, got %d", sum.Failed)
+	}
+	if sum.AverageElapsed != 4*time.Second {
+		t.Errorf("expected average 4s, got %v", sum.AverageElapsed)
+	}
+	if sum.FailureRate() != 0.25 {
+		t.Errorf("expected failure rate 0.25, got %v", sum.FailureRate())
+	}
+	if sum.LastProcessed == nil || sum.LastProcessed.Source != "d" {
+		t.Errorf("expected last processed d, got %+v", sum.LastProcessed)
+	}
+}
+
+func TestSummarize_Providers(t *testing.T) {
+	base := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Time: base, Status: StatusCompleted, AudioSeconds: 300, Provider: "api.openai.com", Cost: 0.03},
+		{Time: base, Status: StatusCompleted, AudioSeconds: 600, Provider: "api.openai.com", Cost: 0.06},
+		{Time: base, Status: StatusCompleted, AudioSeconds: 120, Provider: "nas:9000"},
+		{Time: base, Status: StatusCompleted, AudioSeconds: 60},
+	}
+
+	sum := Summarize(records)
+
+	expected := map[string]Usage{
+		"api.openai.com": {Files: 2, AudioDuration: 15 * time.Minute, Cost: 0.09},
+		"nas:9000":       {Files: 1, AudioDuration: 2 * time.Minute},
+	}
+	if len(sum.Usage) != 2 || sum.Usage["nas:9000"] != expected["nas:9000"] {
+		t.Errorf("expected usage %v, got %v", expected, sum.Usage)
+	}
+	openai := sum.Usage["api.openai.com"]
+	if openai.Files != 2 || openai.AudioDuration != 15*time.Minute || math.Abs(openai.Cost-0.09) > 1e-9 {
+		t.Errorf("unexpected usage for api.openai.com: %+v", openai)
+	}
+	if math.Abs(sum.Cost-0.09) > 1e-9 {
+		t.Errorf("expected total cost 0.09, got %v", sum.Cost)
+	}
+}
+
+func TestSummarize_Usage(t *testing.T) {
+	base := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Time: base, Status: StatusCompleted, ElapsedMs: 30000, AudioSeconds: 300, Words: 400},
+		{Time: base, Status: StatusCompleted, ElapsedMs: 30000, AudioSeconds: 600, Words: 900},
+		{Time: base, Status: StatusFailed, Category: CategoryTimeout},
+		{Time: base, Status: StatusFailed, Category: CategoryTimeout},
+		{Time: base, Status: StatusFailed},
+		{Time: base, Status: StatusSkipped, Category: CategoryTooShort},
+	}
+
+	sum := Summarize(records)
+
+	if sum.Skipped != 1 || sum.Total() != 5 {
+		t.Errorf("expected 1 skipped file outside the total of 5, got %d and %d", sum.Skipped, sum.Total())
+	}
+
+	if sum.AudioDuration != 15*time.Minute {
+		t.Errorf("expected 15m of audio, got %v", sum.AudioDuration)
+	}
+	if sum.Words != 1300 {
+		t.Errorf("expected 1300 words, got %d", sum.Words)
+	}
+	if sum.Speed() != 15 {
+		t.Errorf("expected 15x realtime, got %v", sum.Speed())
+	}
+	expected := map[string]int{CategoryTimeout: 2, CategoryOther: 1}
+	if !reflect.DeepEqual(sum.Failures, expected) {
+		t.Errorf("expected failures %v, got %v", expected, sum.Failures)
+	}
+}
+
+func TestRecord_FailureCategory(t *testing.T) {
+	tests := []struct {
+		category string
+		expected string
+	}{
+		{"", CategoryOther},
+		{CategoryWrite, CategoryWrite},
+		{CategoryAPI4xx, CategoryAPI4xx},
+	}
+
+	for _, tt := range tests {
+		rec := Record{Status: StatusFailed, Category: tt.category}
+		if got := rec.FailureCategory(); got != tt.expected {
+			t.Errorf("FailureCategory() for %q = %q, want %q", tt.category, got, tt.expected)
+		}
+	}
+}
+
+func TestSummarize_Empty(t *testing.T) {
+	sum := Summarize(nil)
+
+	if sum.Total() != 0 || sum.FailureRate() != 0 || sum.AverageElapsed != 0 {
+		t.Errorf("expected zero summary, got %+v", sum)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"7d", 7 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"", 0, true},
+		{"d", 0, true},
+		{"-1d", 0, true},
+		{"abc", 0, true},
+	}
+
+	for _, tc := range tests {
+		got, err := ParseSince(tc.input)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseSince(%q) expected error", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSince(%q) unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("ParseSince(%q) = %v, expected %v", tc.input, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// ErrHookFailed is returned when a pre_upload or post_transcript hook exits
+// non-zero or its output cannot be used.
+var ErrHookFailed = errors.New("hook failed")
+
+// HooksConfig lists commands that transform a file on its way through the
+// pipeline, for steps nota has no setting for. Arguments are separated by
+// spaces. Hooks run within file_timeout_minutes, and a failing hook fails
+// the file.
+type HooksConfig struct {
+	// PreUpload is run before a recording is uploaded, with its path as the
+	// last argument and in NOTA_AUDIO_PATH. It runs in a temporary directory,
+	// removed once the file is transcribed, and may print the path of a file
+	// to upload instead, such as a denoised copy written there. Printing
+	// nothing uploads the recording itself.
+	PreUpload string `json:"pre_upload,omitempty"`
+	// PostTranscript receives the transcript on stdin, with the recording's
+	// path in NOTA_AUDIO_PATH and its language in NOTA_LANGUAGE, and prints
+	// the text the note is written with.
+	PostTranscript string `json:"post_transcript,omitempty"`
+}
+
+// withHooks runs the pre_upload hook on path, transcribes the audio it
+// names with transcribe, and passes the transcript through the
+// post_transcript hook and the AfterTranscribe pipeline stages.
+func (s *Service) withHooks(ctx context.Context, fileLogger Logger, path string, transcribe func(audio string) (*TranscriptionResult, error)) (*TranscriptionResult, error) {
+	hooks := s.config.Hooks
+	if hooks == nil {
+		hooks = &HooksConfig{}
+	}
+
+	audio := path
+	if command := strings.Fields(hooks.PreUpload); len(command) > 0 {
+		dir, err := os.MkdirTemp("", "nota-hook-")
+		if err != nil {
+			return nil, fmt.Errorf("%w: pre_upload: %w", ErrHookFailed, err)
+		}
+		defer os.RemoveAll(dir)
+
+		out, err := runHook(ctx, append(command, path), dir, nil, "NOTA_AUDIO_PATH="+path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: pre_upload: %w", ErrHookFailed, err)
+		}
+		if replacement := strings.TrimSpace(out); replacement != "" {
+			if !filepath.IsAbs(replacement) {
+				replacement = filepath.Join(dir, replacement)
+			}
+			if _, err := os.Stat(replacement); err != nil {
+				return nil, fmt.Errorf("%w: pre_upload: %w", ErrHookFailed, err)
+			}
+			fileLogger.Info("pre_upload hook replaced the audio",
+				logging.String("path", path),
+				logging.String("upload", replacement),
+			)
+			audio = replacement
+		}
+	}
+
+	result, err := transcribe(audio)
+	if err != nil {
+		return nil, err
+	}
+
+	if command := strings.Fields(hooks.PostTranscript); len(command) > 0 {
+		out, err := runHook(ctx, command, "", strings.NewReader(result.Text),
+			"NOTA_AUDIO_PATH="+path, "NOTA_LANGUAGE="+result.Language)
+		if err != nil {
+			return nil, fmt.Errorf("%w: post_transcript: %w", ErrHookFailed, err)
+		}
+		text := strings.TrimSpace(out)
+		if text == "" {
+			return nil, fmt.Errorf("%w: post_transcript printed no text", ErrHookFailed)
+		}
+		result.Text = text
+	}
+	if err := s.runTranscribedStages(ctx, fileLogger, path, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// runHook runs a hook command in dir, or the current directory when empty,
+// and returns what it printed. A failure includes what it printed to
+// stderr.
+func runHook(ctx context.Context, command []string, dir string, stdin io.Reader, env ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin = stdin
+	cmd.Env = append(os.Environ(), env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,101 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+// pathClient records the path of the audio it was sent.
+type pathClient struct {
+	path string
+}
+
+func (c *pathClient) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	c.path = audioPath
+	return &TranscriptionResult{Text: "buy milk", Language: "en"}, nil
+}
+
+// writeHook writes an executable shell script and returns its path.
+func writeHook(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTranscribe_PreUploadHookReplacesAudio(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.Hooks = &HooksConfig{PreUpload: writeHook(t, `cp "$1" denoised.wav && echo denoised.wav`)}
+	tc := &pathClient{}
+	svc := newDedupService(t, cfg, tc)
+	audioPath := writeWAV(t, t.TempDir(), "memo.wav", time.Second)
+
+	if _, err := svc.transcribe(context.Background(), svc.logger, audioPath); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if filepath.Base(tc.path) != "denoised.wav" {
+		t.Errorf("expected the hook's file uploaded, got %s", tc.path)
+	}
+	if _, err := os.Stat(tc.path); !os.IsNotExist(err) {
+		t.Errorf("expected the hook's directory removed, got: %v", err)
+	}
+
+	// Printing nothing uploads the recording itself
+	svc.config.Hooks.PreUpload = writeHook(t, `test "$NOTA_AUDIO_PATH" = "$1"`)
+	if _, err := svc.transcribe(context.Background(), svc.logger, audioPath); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if tc.path != audioPath {
+		t.Errorf("expected the recording uploaded, got %s", tc.path)
+	}
+}
+
+func TestTranscribe_PostTranscriptHookReplacesText(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.Hooks = &HooksConfig{PostTranscript: writeHook(t, `tr a-z A-Z; echo " ($NOTA_LANGUAGE)"`)}
+	svc := newDedupService(t, cfg, &pathClient{})
+
+	result, err := svc.transcribe(context.Background(), svc.logger, "memo.wav")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.Text != "BUY MILK (en)" {
+		t.Errorf("expected the hook's text, got %q", result.Text)
+	}
+}
+
+func TestTranscribe_HookFailures(t *testing.T) {
+	tests := []struct {
+		name  string
+		hooks HooksConfig
+	}{
+		{"pre_upload exits non-zero", HooksConfig{PreUpload: writeHook(t, "echo no ffmpeg >&2; exit 1")}},
+		{"pre_upload names a missing file", HooksConfig{PreUpload: writeHook(t, "echo missing.wav")}},
+		{"post_transcript exits non-zero", HooksConfig{PostTranscript: writeHook(t, "exit 2")}},
+		{"post_transcript prints nothing", HooksConfig{PostTranscript: writeHook(t, "cat >/dev/null")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := setupBuilderTest(t)
+			cfg.Hooks = &tt.hooks
+			svc := newDedupService(t, cfg, &pathClient{})
+
+			_, err := svc.transcribe(context.Background(), svc.logger, "memo.wav")
+			if !errors.Is(err, ErrHookFailed) {
+				t.Fatalf("expected ErrHookFailed, got: %v", err)
+			}
+			if got := classifyFailure(history.CategoryTranscription, err); got != history.CategoryHook {
+				t.Errorf("expected category %s, got %s", history.CategoryHook, got)
+			}
+		})
+	}
+}
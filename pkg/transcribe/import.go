@@ -0,0 +1,170 @@
+package transcribe
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/ignore"
+)
+
+// ImportOptions configures a batch import.
+type ImportOptions struct {
+	// Workers is the number of files processed concurrently (default: the configured workers).
+	Workers int
+	// KeepOriginals leaves imported files in place instead of archiving them.
+	KeepOriginals bool
+}
+
+// ImportFailure describes a file that could not be imported.
+type ImportFailure struct {
+	Path string
+	Err  error
+}
+
+// ImportSummary reports the outcome of a batch import.
+type ImportSummary struct {
+	// Total is the number of files submitted.
+	Total int
+	// Completed is the number of files whose note was written.
+	Completed int
+	// Failed lists the files that could not be processed.
+	Failed []ImportFailure
+	// Skipped is the number of files not started because the import was cancelled.
+	Skipped int
+	// Elapsed is the wall time of the import.
+	Elapsed time.Duration
+}
+
+// FindAudioFiles walks dir recursively and returns the files whose names match
+// any of the patterns, sorted by path. Hidden files and directories are
+// skipped, as are the paths dir's .notaignore lists.
+func FindAudioFiles(dir string, patterns []string) ([]string, error) {
+	ignored, err := ignore.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		name := d.Name()
+		rel, _ := filepath.Rel(dir, path)
+		if strings.HasPrefix(name, ".") || ignored.Match(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		for _, pattern := range patterns {
+			if matched, _ := filepath.Match(pattern, name); matched {
+				files = append(files, path)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Import runs the given files through the pipeline with a pool of workers,
+// without watching a directory. Files are picked up in the configured queue
+// order and are assumed to be complete, so stabilization is skipped. Progress
+// is sent to the registered reporter.
+//
+// Import must not be called while Run is active. When ctx is cancelled, files
+// not yet started are counted as skipped.
+func (s *Service) Import(ctx context.Context, paths []string, opts ImportOptions) *ImportSummary {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = s.config.Workers
+	}
+	paths = s.config.QueueOrder.sortPaths(paths)
+
+	s.logger.Info("starting import",
+		logging.Int("files", len(paths)),
+		logging.Int("workers", workers),
+		logging.String("queue_order", string(s.config.QueueOrder)),
+	)
+
+	startTime := time.Now()
+	summary := &ImportSummary{Total: len(paths)}
+	var mu sync.Mutex
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				err := s.importFile(ctx, path, !opts.KeepOriginals)
+
+				mu.Lock()
+				if err != nil {
+					summary.Failed = append(summary.Failed, ImportFailure{Path: path, Err: err})
+				} else {
+					summary.Completed++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for i, path := range paths {
+		if ctx.Err() != nil {
+			summary.Skipped = len(paths) - i
+			break
+		}
+		select {
+		case jobs <- path:
+		case <-ctx.Done():
+			summary.Skipped = len(paths) - i
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	summary.Elapsed = time.Since(startTime)
+	s.logger.Info("import complete",
+		logging.Int("completed", summary.Completed),
+		logging.Int("failed", len(summary.Failed)),
+		logging.Int("skipped", summary.Skipped),
+		logging.Duration("elapsed", summary.Elapsed),
+	)
+	return summary
+}
+
+// importFile processes one imported file.
+func (s *Service) importFile(ctx context.Context, path string, archive bool) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		event := FileEvent{Path: path, Timestamp: time.Now()}
+		s.recordOutcome(event, history.Record{}, err, time.Now())
+		return err
+	}
+
+	event := FileEvent{Path: path, Size: info.Size(), Timestamp: info.ModTime()}
+	return s.processFile(ctx, event, processOptions{archive: archive})
+}
@@ -0,0 +1,191 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFindAudioFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		"a.m4a",
+		"notes.txt",
+		"2024/b.mp3",
+		"2024/06/c.M4A",
+		"2024/06/d.m4a",
+		".hidden/e.m4a",
+		"2024/.f.m4a",
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte("audio"), 0644)
+	}
+
+	found, err := FindAudioFiles(dir, []string{"*.m4a", "*.mp3"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join(dir, "2024/06/d.m4a"),
+		filepath.Join(dir, "2024/b.mp3"),
+		filepath.Join(dir, "a.m4a"),
+	}
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected %v, got: %v", expected, found)
+	}
+}
+
+func TestFindAudioFiles_Notaignore(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"a.m4a", "old/b.m4a", "2024/c.m4a", "2024/keep.m4a"} {
+		path := filepath.Join(dir, f)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte("audio"), 0644)
+	}
+	os.WriteFile(filepath.Join(dir, ".notaignore"), []byte("# archived by hand\nold/\n2024/*.m4a\n!keep.m4a\n"), 0644)
+
+	found, err := FindAudioFiles(dir, []string{"*.m4a"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join(dir, "2024/keep.m4a"),
+		filepath.Join(dir, "a.m4a"),
+	}
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected %v, got: %v", expected, found)
+	}
+}
+
+func TestFindAudioFiles_MissingDir(t *testing.T) {
+	if _, err := FindAudioFiles(filepath.Join(t.TempDir(), "missing"), DefaultWatchPatterns); err == nil {
+		t.Fatal("expected error for missing directory")
+	}
+}
+
+type countingArchiver struct {
+	count atomic.Int32
+}
+
+func (a *countingArchiver) Archive(ctx context.Context, sourcePath, archiveDir string) error {
+	a.count.Add(1)
+	return nil
+}
+
+type failingClient struct {
+	failOn string
+}
+
+func (c failingClient) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	if strings.Contains(audioPath, c.failOn) {
+		return nil, errors.New("corrupt audio")
+	}
+	return &TranscriptionResult{Text: "ok"}, nil
+}
+
+func setupImportFiles(t *testing.T, dir string, names ...string) []string {
+	t.Helper()
+	var paths []string
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte("audio"), 0644)
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestImport_ProcessesAllFiles(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.RetryCount = 1
+
+	arch := &countingArchiver{}
+	ow := &recordingWriter{}
+	svc, err := NewBuilder(cfg).
+		WithWatcher(&fakeWatcher{}).
+		WithClient(failingClient{failOn: "bad"}).
+		WithWriter(ow).
+		WithArchiver(arch).
+		Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	paths := setupImportFiles(t, t.TempDir(), "one.m4a", "two.m4a", "bad.m4a", "three.m4a")
+
+	summary := svc.Import(context.Background(), paths, ImportOptions{Workers: 3})
+
+	if summary.Total != 4 || summary.Completed != 3 {
+		t.Errorf("expected 3 of 4 completed, got: %+v", summary)
+	}
+	if len(summary.Failed) != 1 || filepath.Base(summary.Failed[0].Path) != "bad.m4a" {
+		t.Errorf("expected bad.m4a to fail, got: %+v", summary.Failed)
+	}
+	if arch.count.Load() != 3 {
+		t.Errorf("expected 3 files archived, got: %d", arch.count.Load())
+	}
+	if len(ow.texts) != 3 {
+		t.Errorf("expected 3 notes written, got: %d", len(ow.texts))
+	}
+}
+
+func TestImport_KeepOriginals(t *testing.T) {
+	cfg := setupBuilderTest(t)
+
+	arch := &countingArchiver{}
+	svc, err := NewBuilder(cfg).
+		WithWatcher(&fakeWatcher{}).
+		WithClient(fakeClient{}).
+		WithWriter(&recordingWriter{}).
+		WithArchiver(arch).
+		Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	paths := setupImportFiles(t, t.TempDir(), "one.m4a", "two.m4a")
+
+	summary := svc.Import(context.Background(), paths, ImportOptions{KeepOriginals: true})
+
+	if summary.Completed != 2 {
+		t.Errorf("expected 2 completed, got: %+v", summary)
+	}
+	if arch.count.Load() != 0 {
+		t.Errorf("expected no files archived, got: %d", arch.count.Load())
+	}
+}
+
+func TestImport_CancelledSkipsRemaining(t *testing.T) {
+	cfg := setupBuilderTest(t)
+
+	svc, err := NewBuilder(cfg).
+		WithWatcher(&fakeWatcher{}).
+		WithClient(fakeClient{}).
+		WithWriter(&recordingWriter{}).
+		WithArchiver(&countingArchiver{}).
+		Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	paths := setupImportFiles(t, t.TempDir(), "one.m4a", "two.m4a", "three.m4a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	summary := svc.Import(ctx, paths, ImportOptions{Workers: 1})
+
+	if summary.Skipped != 3 || summary.Completed != 0 {
+		t.Errorf("expected all files to be skipped after cancellation, got: %+v", summary)
+	}
+}
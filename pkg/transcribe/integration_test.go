@@ -10,9 +10,9 @@ import (
 	"testing"
 	"time"
 
-	"github.com/TechnicallyShaun/nota-orbis/internal/transcribe/metadata"
-	"github.com/TechnicallyShaun/nota-orbis/internal/transcribe/stabilizer"
-	"github.com/TechnicallyShaun/nota-orbis/internal/transcribe/watcher"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/metadata"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/stabilizer"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/watcher"
 )
 
 // TestFullFlow_FileDropStableMetadata tests the complete pipeline:
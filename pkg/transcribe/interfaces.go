@@ -0,0 +1,147 @@
+// Package transcribe provides interfaces and types for the audio transcription pipeline.
+//
+// The data types are aliases of the types used by the default component
+// implementations in the watcher, client, writer and logging packages, so
+// those implementations satisfy the interfaces here and custom components can
+// be mixed with them through a Builder.
+package transcribe
+
+import (
+	"context"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/watcher"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
+)
+
+// FileWatcher detects new files in a directory.
+type FileWatcher interface {
+	// Watch starts watching the specified directory for files matching the given patterns.
+	// Returns a channel that emits FileEvent for each detected file.
+	Watch(ctx context.Context, dir string, patterns []string) (<-chan FileEvent, error)
+	// Stop stops the file watcher.
+	Stop() error
+}
+
+// FileEvent represents a detected file.
+type FileEvent = watcher.FileEvent
+
+// Stabilizer waits for a file to finish writing.
+type Stabilizer interface {
+	// WaitForStable blocks until the file at the given path has stopped changing.
+	WaitForStable(ctx context.Context, path string) error
+}
+
+// TranscriptionClient sends audio to a transcription service and receives text.
+type TranscriptionClient interface {
+	// Transcribe sends an audio file for transcription and returns the result.
+	Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error)
+}
+
+// TranscribeOptions configures the transcription request.
+type TranscribeOptions = client.TranscribeOptions
+
+// TranscriptionResult contains the API response.
+type TranscriptionResult = client.TranscriptionResult
+
+// Segment is a timed part of a transcript.
+type Segment = client.Segment
+
+// ErrAPIUnreachable is returned when the transcription API cannot be reached.
+var ErrAPIUnreachable = client.ErrUnreachable
+
+// OutputWriter saves transcriptions to the vault.
+type OutputWriter interface {
+	// Write saves the transcription text and returns the path to the created file.
+	Write(ctx context.Context, text string, opts OutputOptions) (string, error)
+}
+
+// OutputOptions configures output writing.
+type OutputOptions = writer.OutputOptions
+
+// ProcessingInfo records how a note was produced.
+type ProcessingInfo = writer.ProcessingInfo
+
+// NoteRenderer is implemented by output writers that can produce a note
+// without writing it. It is required for Service.Preview and used by dry runs
+// to report the output path.
+type NoteRenderer interface {
+	// Render returns the note content that Write would save.
+	Render(text string, opts OutputOptions) (string, error)
+	// OutputPath returns the path Write would create.
+	OutputPath(opts OutputOptions) string
+}
+
+// Archiver moves processed files to an archive location.
+type Archiver interface {
+	// Archive moves a file from sourcePath to the archiveDir.
+	Archive(ctx context.Context, sourcePath, archiveDir string) error
+}
+
+// ArchivePlanner is implemented by archivers that can report where a file
+// would be archived without moving it, and then move it there. It is used by
+// dry runs and to record the archive path in notes before archiving.
+type ArchivePlanner interface {
+	DestinationPath(sourcePath, archiveDir string) string
+	ArchiveTo(ctx context.Context, sourcePath, destPath string) error
+}
+
+// Logger handles structured logging.
+type Logger interface {
+	// Info logs an informational message with optional fields.
+	Info(msg string, fields ...Field)
+	// Error logs an error message with the error and optional fields.
+	Error(msg string, err error, fields ...Field)
+	// Debug logs a debug message with optional fields.
+	Debug(msg string, fields ...Field)
+}
+
+// Field represents a key-value pair for structured logging.
+type Field = logging.Field
+
+// Stage identifies a step of the per-file pipeline.
+type Stage string
+
+// Pipeline stages in processing order.
+const (
+	StageDetected    Stage = "detected"
+	StageStabilizing Stage = "stabilizing"
+	// StageQueued is reported when a stable file waits for a free worker or
+	// for the processing schedule to open.
+	StageQueued    Stage = "queued"
+	StageUploading Stage = "uploading"
+	StageWriting   Stage = "writing"
+	StageArchived  Stage = "archived"
+	// StageCompleted replaces StageArchived when the original file is kept in place.
+	StageCompleted Stage = "completed"
+	StageFailed    Stage = "failed"
+	// StageSkipped is reported for files below min_file_size_kb or
+	// min_duration_seconds, which are archived without being transcribed.
+	StageSkipped Stage = "skipped"
+	// StageDryRun replaces uploading, writing and archiving in dry-run mode.
+	StageDryRun Stage = "dry-run"
+)
+
+// ProgressEvent reports a file entering a pipeline stage.
+type ProgressEvent struct {
+	Path  string
+	Stage Stage
+	// Size is the file size in bytes.
+	Size int64
+	// Elapsed is the time since the file was picked up.
+	Elapsed time.Duration
+	// Output is the written note path, set once the note exists.
+	Output string
+	// Err is set for StageFailed.
+	Err error
+	// Detail is an optional human-readable description of the stage.
+	Detail string
+}
+
+// ProgressReporter receives pipeline stage transitions.
+// Implementations must be safe for concurrent use; files are processed in parallel.
+type ProgressReporter interface {
+	Report(event ProgressEvent)
+}
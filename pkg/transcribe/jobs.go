@@ -0,0 +1,443 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/archiver"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/control"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/cron"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/events"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/notify"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/backup"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/index"
+)
+
+// JobType is the kind of work a scheduled job does.
+type JobType string
+
+// Job types.
+const (
+	// JobIndexRefresh brings the vault's note index up to date.
+	JobIndexRefresh JobType = "index_refresh"
+	// JobBackup backs the vault up as `nota backup` does, with the
+	// destination and retention in .nota/config.json.
+	JobBackup JobType = "backup"
+	// JobPruneArchive removes archived audio older than max_age_days.
+	JobPruneArchive JobType = "prune_archive"
+	// JobInboxReminder sends a notification when the inbox holds more than
+	// max_count notes or notes older than max_age_days.
+	JobInboxReminder JobType = "inbox_reminder"
+)
+
+// DefaultInboxFolder is the vault folder inbox_reminder watches when folder
+// is not set.
+const DefaultInboxFolder = "Inbox"
+
+// Valid reports whether t is a known job type.
+func (t JobType) Valid() bool {
+	switch t {
+	case JobIndexRefresh, JobBackup, JobPruneArchive, JobInboxReminder:
+		return true
+	}
+	return false
+}
+
+// maxJobWait is the longest the scheduler sleeps before looking at the clock
+// again, so a suspended machine or a clock change delays jobs by at most this.
+const maxJobWait = time.Minute
+
+// JobConfig is a vault job the service runs on a schedule, alongside
+// transcription.
+type JobConfig struct {
+	// Name identifies the job in logs and status. Defaults to the type.
+	Name string  `json:"name,omitempty"`
+	Type JobType `json:"type"`
+	// Schedule is a cron expression such as "0 3 * * *", a shorthand such
+	// as "@daily", or "@every 6h", in local time.
+	Schedule string `json:"schedule"`
+	// MaxAgeDays is how many days of archived audio prune_archive keeps, and
+	// how many days unchanged an inbox note may be before inbox_reminder
+	// reminds.
+	MaxAgeDays int `json:"max_age_days,omitempty"`
+	// MaxCount is how many notes the inbox may hold before inbox_reminder
+	// reminds.
+	MaxCount int `json:"max_count,omitempty"`
+	// Folder is the vault folder inbox_reminder watches. Defaults to
+	// DefaultInboxFolder.
+	Folder string `json:"folder,omitempty"`
+	// Notify is where inbox_reminder sends its reminders.
+	Notify *NotifyConfig `json:"notify,omitempty"`
+}
+
+// NotifyConfig is where a job's notifications go.
+type NotifyConfig struct {
+	// Type is desktop (notify-send), ntfy or command.
+	Type string `json:"type"`
+	// URL is the ntfy topic, e.g. https://ntfy.sh/my-vault.
+	URL string `json:"url,omitempty"`
+	// Token, when set, is sent to ntfy as a bearer token.
+	Token string `json:"token,omitempty"`
+	// Command is run for each notification, with the title and body in
+	// NOTA_NOTIFY_TITLE and NOTA_NOTIFY_BODY and the body on stdin.
+	// Arguments are separated by spaces.
+	Command string `json:"command,omitempty"`
+}
+
+// validate checks the settings the notification type needs.
+func (c NotifyConfig) validate() error {
+	switch c.Type {
+	case "desktop":
+	case "ntfy":
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("notify url %q must be a topic URL such as https://ntfy.sh/my-vault", c.URL)
+		}
+	case "command":
+		if strings.TrimSpace(c.Command) == "" {
+			return fmt.Errorf("notify command is required")
+		}
+	default:
+		return fmt.Errorf("notify type %q must be desktop, ntfy or command", c.Type)
+	}
+	return nil
+}
+
+// sender returns the notification sender the config describes.
+func (c NotifyConfig) sender() notify.Sender {
+	switch c.Type {
+	case "ntfy":
+		return &notify.Ntfy{URL: c.URL, Token: c.Token}
+	case "command":
+		return &notify.Command{Args: strings.Fields(c.Command)}
+	}
+	return &notify.Desktop{}
+}
+
+// label returns the job's name, or its type when unnamed.
+func (j JobConfig) label() string {
+	if j.Name == "" {
+		return string(j.Type)
+	}
+	return j.Name
+}
+
+// validateJobs checks each job's type, schedule and settings, and that no
+// two jobs share a name.
+func (c *Config) validateJobs() error {
+	seen := make(map[string]bool)
+	for _, j := range c.Jobs {
+		name := j.label()
+		if !j.Type.Valid() {
+			return fmt.Errorf("%w %s: type %q must be index_refresh, backup, prune_archive or inbox_reminder", ErrInvalidJob, name, j.Type)
+		}
+		if seen[name] {
+			return fmt.Errorf("%w %s: name is used by another job", ErrInvalidJob, name)
+		}
+		seen[name] = true
+		if _, err := cron.Parse(j.Schedule); err != nil {
+			return fmt.Errorf("%w %s: %v", ErrInvalidJob, name, err)
+		}
+		if j.MaxAgeDays < 0 {
+			return fmt.Errorf("%w %s: max_age_days: %w", ErrInvalidJob, name, ErrNegativeValue)
+		}
+		if j.MaxCount < 0 {
+			return fmt.Errorf("%w %s: max_count: %w", ErrInvalidJob, name, ErrNegativeValue)
+		}
+		if j.Type == JobPruneArchive && j.MaxAgeDays == 0 {
+			return fmt.Errorf("%w %s: prune_archive needs max_age_days", ErrInvalidJob, name)
+		}
+		if j.Type == JobInboxReminder {
+			if j.MaxAgeDays == 0 && j.MaxCount == 0 {
+				return fmt.Errorf("%w %s: inbox_reminder needs max_count or max_age_days", ErrInvalidJob, name)
+			}
+			if j.Notify == nil {
+				return fmt.Errorf("%w %s: inbox_reminder needs notify", ErrInvalidJob, name)
+			}
+			if err := j.Notify.validate(); err != nil {
+				return fmt.Errorf("%w %s: %v", ErrInvalidJob, name, err)
+			}
+			if f := filepath.ToSlash(j.Folder); path.IsAbs(f) || f == ".." || strings.HasPrefix(f, "../") {
+				return fmt.Errorf("%w %s: folder %q must be a folder inside the vault", ErrInvalidJob, name, j.Folder)
+			}
+		}
+	}
+	return nil
+}
+
+// job is a configured job ready to run.
+type job struct {
+	name     string
+	cfg      JobConfig
+	schedule *cron.Schedule
+	// run does the work and summarizes it for logs and status.
+	run func(ctx context.Context, now time.Time) (string, error)
+}
+
+// newJobs prepares the configured jobs. Jobs working on the vault need a
+// config loaded with LoadFromVault.
+func newJobs(cfg *Config) ([]*job, error) {
+	var jobs []*job
+	for _, jc := range cfg.Jobs {
+		sched, err := cron.Parse(jc.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %v", ErrInvalidJob, jc.label(), err)
+		}
+		j := &job{name: jc.label(), cfg: jc, schedule: sched}
+
+		root := cfg.VaultRoot()
+		if root == "" && jc.Type != JobPruneArchive {
+			return nil, fmt.Errorf("%w %s: %s jobs need the config loaded from a vault", ErrInvalidJob, j.name, jc.Type)
+		}
+		switch jc.Type {
+		case JobIndexRefresh:
+			j.run = func(ctx context.Context, now time.Time) (string, error) {
+				return refreshIndex(root)
+			}
+		case JobBackup:
+			j.run = func(ctx context.Context, now time.Time) (string, error) {
+				return backupVault(ctx, root, now)
+			}
+		case JobPruneArchive:
+			archiveDir, maxAge := cfg.ArchiveDir, jc.MaxAgeDays
+			j.run = func(ctx context.Context, now time.Time) (string, error) {
+				removed, err := archiver.Prune(ctx, archiveDir, now.AddDate(0, 0, -maxAge))
+				return fmt.Sprintf("%d files removed", len(removed)), err
+			}
+		case JobInboxReminder:
+			if jc.Notify == nil {
+				return nil, fmt.Errorf("%w %s: inbox_reminder needs notify", ErrInvalidJob, j.name)
+			}
+			r := inboxReminder{
+				folder:     jc.Folder,
+				maxCount:   jc.MaxCount,
+				maxAgeDays: jc.MaxAgeDays,
+				sender:     jc.Notify.sender(),
+			}
+			if r.folder == "" {
+				r.folder = DefaultInboxFolder
+			}
+			j.run = func(ctx context.Context, now time.Time) (string, error) {
+				return r.check(ctx, root, now)
+			}
+		default:
+			return nil, fmt.Errorf("%w %s: unknown type %q", ErrInvalidJob, j.name, jc.Type)
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, nil
+}
+
+// refreshIndex brings the saved index of the vault at root up to date,
+// building it if there is none.
+func refreshIndex(root string) (string, error) {
+	ix, err := index.Load(root)
+	changed := 0
+	if err != nil {
+		if ix, err = index.Build(root); err != nil {
+			return "", err
+		}
+		changed = ix.Len()
+	} else {
+		paths, err := ix.Refresh()
+		if err != nil {
+			return "", err
+		}
+		changed = len(paths)
+	}
+	if err := ix.Save(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d notes, %d changed", ix.Len(), changed), nil
+}
+
+// backupVault backs up the vault at root to the store in its config.
+func backupVault(ctx context.Context, root string, now time.Time) (string, error) {
+	vcfg, err := vault.LoadConfig(root)
+	if err != nil {
+		return "", err
+	}
+	store, err := backup.NewStore(vcfg.Backup)
+	if err != nil {
+		return "", err
+	}
+	result, err := backup.Backup(ctx, root, store, backup.Options{Keep: backup.Keep(vcfg.Backup), Now: now})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s, %d files, %d old backups removed", result.Archive, result.Files, len(result.Removed)), nil
+}
+
+// inboxReminder nudges the weekly review when an inbox folder fills up or
+// goes stale.
+type inboxReminder struct {
+	folder     string
+	maxCount   int
+	maxAgeDays int
+	sender     notify.Sender
+}
+
+// check counts the notes in the inbox of the vault at root and sends a
+// reminder if there are too many or any are older than allowed. A note's
+// age is the time since it last changed.
+func (r inboxReminder) check(ctx context.Context, root string, now time.Time) (string, error) {
+	ix, err := index.Open(root)
+	if err != nil {
+		return "", err
+	}
+
+	prefix := path.Clean(filepath.ToSlash(r.folder)) + "/"
+	cutoff := now.AddDate(0, 0, -r.maxAgeDays)
+	total, old := 0, 0
+	for _, n := range ix.Notes() {
+		if !strings.HasPrefix(n.Path, prefix) {
+			continue
+		}
+		total++
+		if r.maxAgeDays > 0 && n.ModTime.Before(cutoff) {
+			old++
+		}
+	}
+
+	var reasons []string
+	if old > 0 {
+		reasons = append(reasons, fmt.Sprintf("%s older than %d days in %s", countNotes(old), r.maxAgeDays, r.folder))
+	}
+	if r.maxCount > 0 && total > r.maxCount {
+		reasons = append(reasons, fmt.Sprintf("%s in %s (limit %d)", countNotes(total), r.folder, r.maxCount))
+	}
+	if len(reasons) == 0 {
+		return fmt.Sprintf("%s in %s, no reminder needed", countNotes(total), r.folder), nil
+	}
+
+	body := strings.Join(reasons, ", ")
+	msg := notify.Message{Title: "Time to review " + r.folder, Body: body}
+	if err := r.sender.Send(ctx, msg); err != nil {
+		return "", err
+	}
+	return "reminder sent: " + body, nil
+}
+
+// countNotes formats n as "1 note" or "n notes".
+func countNotes(n int) string {
+	if n == 1 {
+		return "1 note"
+	}
+	return fmt.Sprintf("%d notes", n)
+}
+
+// startJobs runs the scheduler, if any jobs are configured, until ctx is
+// cancelled or the service stops.
+func (s *Service) startJobs(ctx context.Context) {
+	if len(s.jobs) == 0 {
+		return
+	}
+	for _, j := range s.jobs {
+		s.live.addJob(control.JobState{Name: j.name, Type: string(j.cfg.Type), Schedule: j.schedule.String()})
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.scheduleJobs(ctx)
+	}()
+}
+
+// scheduleJobs starts each job when its schedule fires. A job whose
+// previous run has not finished skips that run.
+func (s *Service) scheduleJobs(ctx context.Context) {
+	logger := s.componentLogger("jobs")
+	next := make([]time.Time, len(s.jobs))
+	now := s.clock.Now()
+	for i, j := range s.jobs {
+		next[i] = j.schedule.Next(now)
+		s.live.jobScheduled(j.name, next[i])
+		logger.Info("job scheduled",
+			logging.String("job", j.name),
+			logging.String("schedule", j.schedule.String()),
+			logging.String("next", formatNext(next[i])),
+		)
+	}
+
+	for {
+		now := s.clock.Now()
+		wait := maxJobWait
+		for i, j := range s.jobs {
+			if next[i].IsZero() {
+				continue
+			}
+			if !now.Before(next[i]) {
+				s.startJob(ctx, j)
+				next[i] = j.schedule.Next(now)
+				s.live.jobScheduled(j.name, next[i])
+				if next[i].IsZero() {
+					continue
+				}
+			}
+			wait = min(wait, next[i].Sub(now))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		case <-s.after(wait):
+		}
+	}
+}
+
+// formatNext formats a job's next run for logs.
+func formatNext(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// startJob runs j in the background unless its previous run is still going.
+func (s *Service) startJob(ctx context.Context, j *job) {
+	if !s.live.jobStarted(j.name) {
+		s.componentLogger("jobs").Info("job still running, skipping this run", logging.String("job", j.name))
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.runJob(ctx, j)
+	}()
+}
+
+// runJob runs j and records the outcome in the logs, events and live state.
+// In a dry run the job is only logged, as jobs delete, upload and write.
+func (s *Service) runJob(ctx context.Context, j *job) {
+	logger := s.componentLogger("jobs")
+	start := s.clock.Now()
+	if s.dryRun {
+		logger.Info("dry run: would run job", logging.String("job", j.name), logging.String("type", string(j.cfg.Type)))
+		s.live.jobFinished(j.name, "dry run, skipped", nil)
+		return
+	}
+	logger.Info("running job", logging.String("job", j.name), logging.String("type", string(j.cfg.Type)))
+
+	result, err := j.run(ctx, start)
+	elapsed := s.clock.Now().Sub(start)
+	s.live.jobFinished(j.name, result, err)
+	if err != nil {
+		logger.Error("job failed", err, logging.String("job", j.name))
+		s.emit(events.Event{Type: events.JobFailed, Job: j.name, Error: err.Error(), ElapsedMs: elapsed.Milliseconds()})
+		return
+	}
+	logger.Info("job complete",
+		logging.String("job", j.name),
+		logging.String("result", result),
+		logging.Duration("elapsed", elapsed),
+	)
+	s.emit(events.Event{Type: events.JobCompleted, Job: j.name, Result: result, ElapsedMs: elapsed.Milliseconds()})
+}
@@ -0,0 +1,283 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/control"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/notify"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/index"
+)
+
+func TestValidateJobs(t *testing.T) {
+	tests := []struct {
+		name    string
+		jobs    []JobConfig
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"valid", []JobConfig{
+			{Type: JobIndexRefresh, Schedule: "*/30 * * * *"},
+			{Type: JobBackup, Schedule: "@daily"},
+			{Type: JobPruneArchive, Schedule: "0 4 * * 0", MaxAgeDays: 90},
+		}, false},
+		{"named twice", []JobConfig{
+			{Name: "nightly", Type: JobBackup, Schedule: "@daily"},
+			{Name: "weekly", Type: JobBackup, Schedule: "@weekly"},
+		}, false},
+		{"unknown type", []JobConfig{{Type: "defrag", Schedule: "@daily"}}, true},
+		{"bad schedule", []JobConfig{{Type: JobBackup, Schedule: "daily"}}, true},
+		{"duplicate name", []JobConfig{
+			{Type: JobBackup, Schedule: "@daily"},
+			{Type: JobBackup, Schedule: "@weekly"},
+		}, true},
+		{"prune without age", []JobConfig{{Type: JobPruneArchive, Schedule: "@daily"}}, true},
+		{"negative age", []JobConfig{{Type: JobPruneArchive, Schedule: "@daily", MaxAgeDays: -1}}, true},
+		{"inbox reminder", []JobConfig{{Type: JobInboxReminder, Schedule: "0 9 * * 1", MaxAgeDays: 7, Notify: &NotifyConfig{Type: "desktop"}}}, false},
+		{"reminder without threshold", []JobConfig{{Type: JobInboxReminder, Schedule: "@weekly", Notify: &NotifyConfig{Type: "desktop"}}}, true},
+		{"reminder without notify", []JobConfig{{Type: JobInboxReminder, Schedule: "@weekly", MaxCount: 20}}, true},
+		{"reminder with bad ntfy url", []JobConfig{{Type: JobInboxReminder, Schedule: "@weekly", MaxCount: 20, Notify: &NotifyConfig{Type: "ntfy", URL: "ntfy.sh/vault"}}}, true},
+		{"reminder outside vault", []JobConfig{{Type: JobInboxReminder, Schedule: "@weekly", MaxCount: 20, Folder: "../Inbox", Notify: &NotifyConfig{Type: "desktop"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Jobs: tt.jobs}
+			err := cfg.validateJobs()
+			if tt.wantErr && !errors.Is(err, ErrInvalidJob) {
+				t.Errorf("expected ErrInvalidJob, got: %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewJobs_VaultJobsNeedVault(t *testing.T) {
+	cfg := &Config{Jobs: []JobConfig{{Type: JobIndexRefresh, Schedule: "@hourly"}}}
+	if _, err := newJobs(cfg); !errors.Is(err, ErrInvalidJob) {
+		t.Errorf("expected ErrInvalidJob without a vault, got: %v", err)
+	}
+	cfg.vaultRoot = t.TempDir()
+	if _, err := newJobs(cfg); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
+
+func TestScheduleJobs_RunsDueJobs(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, ".nota"), 0755)
+	os.WriteFile(filepath.Join(root, "garden.md"), []byte("# Garden\n"), 0644)
+	cfg.vaultRoot = root
+	cfg.ArchiveDir = filepath.Join(t.TempDir(), "archive")
+	old := filepath.Join(cfg.ArchiveDir, "2026", "01", "05", "memo.m4a")
+	os.MkdirAll(filepath.Dir(old), 0755)
+	os.WriteFile(old, []byte("audio"), 0644)
+	cfg.Jobs = []JobConfig{
+		{Type: JobIndexRefresh, Schedule: "@hourly"},
+		{Name: "prune", Type: JobPruneArchive, Schedule: "0 10 * * *", MaxAgeDays: 30},
+	}
+
+	clk := clock.NewFake(time.Date(2026, 10, 16, 9, 59, 30, 0, time.Local))
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{},
+		Stabilizer: fakeStabilizer{},
+		Client:     fakeClient{},
+		Writer:     &recordingWriter{},
+		Archiver:   &countingArchiver{},
+		Logger:     &recordingLogger{},
+		Clock:      clk,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	runDueJobs(t, svc, clk)
+
+	snap := svc.live.snapshot()
+	if len(snap.Jobs) != 2 {
+		t.Fatalf("expected 2 jobs in the live state, got %+v", snap.Jobs)
+	}
+	for _, j := range snap.Jobs {
+		if j.Running || j.LastRun.IsZero() || j.LastError != "" {
+			t.Errorf("expected %s to have run successfully, got %+v", j.Name, j)
+		}
+	}
+	if snap.Jobs[0].Name != "index_refresh" || snap.Jobs[0].LastResult != "1 notes, 1 changed" {
+		t.Errorf("unexpected index refresh state: %+v", snap.Jobs[0])
+	}
+	if want := time.Date(2026, 10, 16, 11, 0, 0, 0, time.Local); !snap.Jobs[0].Next.Equal(want) {
+		t.Errorf("expected the next refresh at %v, got %v", want, snap.Jobs[0].Next)
+	}
+	if want := time.Date(2026, 10, 17, 10, 0, 0, 0, time.Local); !snap.Jobs[1].Next.Equal(want) {
+		t.Errorf("expected the next prune at %v, got %v", want, snap.Jobs[1].Next)
+	}
+
+	if ix, err := index.Load(root); err != nil || ix.Len() != 1 {
+		t.Errorf("expected the index saved with 1 note, got: %v", err)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("expected the old recording pruned, got: %v", err)
+	}
+}
+
+// runDueJobs starts svc's jobs and waits for those due within the first
+// scheduler wait, which passes instantly on clk, to finish.
+func runDueJobs(t *testing.T, svc *Service, clk *clock.Fake) {
+	t.Helper()
+	// The scheduler parks on its second wait while the jobs due run
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	parked := make(chan struct{})
+	waits := 0
+	svc.after = func(d time.Duration) <-chan time.Time {
+		waits++
+		if waits > 1 {
+			if waits == 2 {
+				close(parked)
+			}
+			return nil
+		}
+		clk.Advance(d)
+		ch := make(chan time.Time, 1)
+		ch <- clk.Now()
+		return ch
+	}
+	svc.startJobs(ctx)
+	<-parked
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+		if !jobsRunning(svc.live.snapshot()) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	svc.wg.Wait()
+}
+
+func TestScheduleJobs_DryRunSkipsJobs(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.ArchiveDir = filepath.Join(t.TempDir(), "archive")
+	old := filepath.Join(cfg.ArchiveDir, "2026", "01", "05", "memo.m4a")
+	os.MkdirAll(filepath.Dir(old), 0755)
+	os.WriteFile(old, []byte("audio"), 0644)
+	cfg.Jobs = []JobConfig{{Name: "prune", Type: JobPruneArchive, Schedule: "0 10 * * *", MaxAgeDays: 30}}
+
+	clk := clock.NewFake(time.Date(2026, 10, 16, 9, 59, 30, 0, time.Local))
+	logger := &recordingLogger{}
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{},
+		Stabilizer: fakeStabilizer{},
+		Client:     fakeClient{},
+		Writer:     &recordingWriter{},
+		Archiver:   &countingArchiver{},
+		Logger:     logger,
+		Clock:      clk,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+	svc.SetDryRun(true)
+
+	runDueJobs(t, svc, clk)
+
+	if _, err := os.Stat(old); err != nil {
+		t.Errorf("expected the archive untouched in a dry run, got: %v", err)
+	}
+	if !slices.Contains(logger.messages, "dry run: would run job") {
+		t.Errorf("expected the skipped job logged, got %v", logger.messages)
+	}
+	if snap := svc.live.snapshot(); len(snap.Jobs) != 1 || snap.Jobs[0].Running || snap.Jobs[0].LastResult != "dry run, skipped" {
+		t.Errorf("unexpected job state: %+v", snap.Jobs)
+	}
+}
+
+// jobsRunning reports whether any job in snap is running.
+func jobsRunning(snap control.Snapshot) bool {
+	for _, j := range snap.Jobs {
+		if j.Running {
+			return true
+		}
+	}
+	return false
+}
+
+// recordingSender records the notifications sent.
+type recordingSender struct {
+	sent []notify.Message
+}
+
+func (s *recordingSender) Send(ctx context.Context, msg notify.Message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestInboxReminder_Check(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	notes := map[string]time.Time{
+		"Inbox/call mum.md":     now.AddDate(0, 0, -10),
+		"Inbox/garden.md":       now.AddDate(0, 0, -8),
+		"Inbox/today.md":        now.Add(-time.Hour),
+		"Projects/old essay.md": now.AddDate(-1, 0, 0),
+	}
+	for rel, mtime := range notes {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		os.MkdirAll(filepath.Dir(p), 0755)
+		os.WriteFile(p, []byte("# Note\n"), 0644)
+		os.Chtimes(p, mtime, mtime)
+	}
+
+	sender := &recordingSender{}
+	r := inboxReminder{folder: "Inbox", maxCount: 2, maxAgeDays: 7, sender: sender}
+	result, err := r.check(context.Background(), root, now)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	want := "2 notes older than 7 days in Inbox, 3 notes in Inbox (limit 2)"
+	if len(sender.sent) != 1 || sender.sent[0].Body != want || sender.sent[0].Title != "Time to review Inbox" {
+		t.Errorf("expected reminder %q, got %+v", want, sender.sent)
+	}
+	if result != "reminder sent: "+want {
+		t.Errorf("unexpected result %q", result)
+	}
+
+	// Within both limits nothing is sent
+	sender.sent = nil
+	r = inboxReminder{folder: "Inbox", maxCount: 5, maxAgeDays: 30, sender: sender}
+	if result, err := r.check(context.Background(), root, now); err != nil || len(sender.sent) != 0 {
+		t.Errorf("expected no reminder, got %+v, %v", sender.sent, err)
+	} else if result != "3 notes in Inbox, no reminder needed" {
+		t.Errorf("unexpected result %q", result)
+	}
+}
+
+func TestLiveState_SkipsRunningJob(t *testing.T) {
+	live := newLiveState(clock.Real{})
+	live.addJob(control.JobState{Name: "backup"})
+
+	if !live.jobStarted("backup") {
+		t.Fatal("expected the job to start")
+	}
+	if live.jobStarted("backup") {
+		t.Error("expected a running job not to start again")
+	}
+	live.jobFinished("backup", "", errors.New("bucket not found"))
+	snap := live.snapshot()
+	if snap.Jobs[0].Running || snap.Jobs[0].LastError != "bucket not found" {
+		t.Errorf("expected the failure recorded, got %+v", snap.Jobs[0])
+	}
+	if !live.jobStarted("backup") {
+		t.Error("expected the job to start again once finished")
+	}
+}
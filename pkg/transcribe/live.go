@@ -0,0 +1,183 @@
+package transcribe
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/control"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// maxRecent is how many completions the control socket lists.
+const maxRecent = 10
+
+// liveState tracks the files in the pipeline and the outcomes since the
+// service started, for the control socket.
+type liveState struct {
+	mu    sync.Mutex
+	clock clock.Clock
+	files map[string]*control.FileState
+	snap  control.Snapshot
+}
+
+// newLiveState creates an empty state started now.
+func newLiveState(clk clock.Clock) *liveState {
+	return &liveState{
+		clock: clk,
+		files: make(map[string]*control.FileState),
+		snap:  control.Snapshot{Started: clk.Now(), Errors: make(map[string]int)},
+	}
+}
+
+// stage records that path has reached stage, adding it to the files in
+// flight if it is new.
+func (l *liveState) stage(path string, stage Stage) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, ok := l.files[path]
+	if !ok {
+		f = &control.FileState{Path: path, Since: l.clock.Now()}
+		l.files[path] = f
+	}
+	f.Stage = string(stage)
+}
+
+// done removes path from the files in flight.
+func (l *liveState) done(path string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.files, path)
+}
+
+// record counts a history record and adds it to the recent completions.
+func (l *liveState) record(rec history.Record) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch rec.Status {
+	case history.StatusFailed:
+		l.snap.Failed++
+		l.snap.Errors[rec.FailureCategory()]++
+	case history.StatusSkipped:
+		l.snap.Skipped++
+	default:
+		l.snap.Completed++
+	}
+
+	c := control.Completion{Time: rec.Time, Path: rec.Source, Status: rec.Status, Category: rec.Category, Output: rec.Output, Stage: rec.Stage}
+	l.snap.Recent = append([]control.Completion{c}, l.snap.Recent...)
+	if len(l.snap.Recent) > maxRecent {
+		l.snap.Recent = l.snap.Recent[:maxRecent]
+	}
+}
+
+// addJob lists a scheduled job.
+func (l *liveState) addJob(job control.JobState) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.snap.Jobs = append(l.snap.Jobs, job)
+}
+
+// job returns the named job's state, or nil. The caller holds l.mu.
+func (l *liveState) job(name string) *control.JobState {
+	for i := range l.snap.Jobs {
+		if l.snap.Jobs[i].Name == name {
+			return &l.snap.Jobs[i]
+		}
+	}
+	return nil
+}
+
+// jobScheduled records when the named job next runs.
+func (l *liveState) jobScheduled(name string, next time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if j := l.job(name); j != nil {
+		j.Next = next
+	}
+}
+
+// jobStarted marks the named job running. It returns false, changing
+// nothing, if the job is already running.
+func (l *liveState) jobStarted(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	j := l.job(name)
+	if j == nil || j.Running {
+		return false
+	}
+	j.Running = true
+	return true
+}
+
+// jobFinished records the outcome of the named job's run.
+func (l *liveState) jobFinished(name string, result string, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	j := l.job(name)
+	if j == nil {
+		return
+	}
+	j.Running = false
+	j.LastRun = l.clock.Now()
+	j.LastResult = result
+	j.LastError = ""
+	if err != nil {
+		j.LastError = err.Error()
+	}
+}
+
+// snapshot returns a copy of the state, with the files in flight oldest
+// first.
+func (l *liveState) snapshot() control.Snapshot {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	snap := l.snap
+	snap.Errors = maps.Clone(l.snap.Errors)
+	snap.Recent = append([]control.Completion(nil), l.snap.Recent...)
+	snap.Jobs = append([]control.JobState(nil), l.snap.Jobs...)
+	snap.InFlight = make([]control.FileState, 0, len(l.files))
+	for _, f := range l.files {
+		snap.InFlight = append(snap.InFlight, *f)
+		if f.Stage == string(StageQueued) {
+			snap.Queued++
+		}
+	}
+	sort.Slice(snap.InFlight, func(i, j int) bool {
+		a, b := snap.InFlight[i], snap.InFlight[j]
+		if !a.Since.Equal(b.Since) {
+			return a.Since.Before(b.Since)
+		}
+		return a.Path < b.Path
+	})
+	return snap
+}
+
+// serveControl serves the live state on the control socket until ctx is
+// cancelled. The socket is a convenience for dashboards, so failing to bind
+// it is logged rather than stopping the service.
+func (s *Service) serveControl(ctx context.Context) {
+	logger := s.componentLogger("control")
+	path, err := control.SocketPath()
+	if err != nil {
+		logger.Error("failed to locate control socket", err)
+		return
+	}
+	ln, err := control.Listen(path)
+	if err != nil {
+		logger.Error("failed to open control socket", err, logging.String("socket", path))
+		return
+	}
+
+	srv := &control.Server{Snapshot: s.live.snapshot}
+	go func() {
+		if err := srv.Serve(ctx, ln); err != nil {
+			logger.Error("control socket stopped", err)
+		}
+	}()
+	logger.Debug("serving live status", logging.String("socket", path))
+}
@@ -0,0 +1,85 @@
+package transcribe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+func TestLiveState_TracksFilesInFlight(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 1, 22, 9, 0, 0, 0, time.UTC))
+	live := newLiveState(clk)
+
+	live.stage("/in/b.m4a", StageDetected)
+	clk.Advance(time.Second)
+	live.stage("/in/a.m4a", StageDetected)
+	live.stage("/in/a.m4a", StageQueued)
+	live.stage("/in/b.m4a", StageUploading)
+
+	snap := live.snapshot()
+	if snap.Queued != 1 || len(snap.InFlight) != 2 {
+		t.Fatalf("expected 2 files in flight with 1 queued, got %+v", snap)
+	}
+	if snap.InFlight[0].Path != "/in/b.m4a" || snap.InFlight[0].Stage != string(StageUploading) {
+		t.Errorf("expected the oldest file first at its latest stage, got %+v", snap.InFlight)
+	}
+
+	live.done("/in/b.m4a")
+	if snap := live.snapshot(); len(snap.InFlight) != 1 {
+		t.Errorf("expected 1 file in flight, got %+v", snap.InFlight)
+	}
+}
+
+func TestLiveState_KeepsRecentCompletions(t *testing.T) {
+	live := newLiveState(clock.Real{})
+	for i := 0; i < maxRecent+2; i++ {
+		live.record(history.Record{Source: "/in/memo.m4a", Status: history.StatusCompleted})
+	}
+	live.record(history.Record{Source: "/in/bad.m4a", Status: history.StatusFailed, Category: history.CategoryAPIUnreachable})
+	live.record(history.Record{Source: "/in/tap.m4a", Status: history.StatusSkipped, Category: history.CategoryTooSmall})
+
+	snap := live.snapshot()
+	if snap.Completed != maxRecent+2 || snap.Failed != 1 || snap.Skipped != 1 {
+		t.Errorf("unexpected counts: %+v", snap)
+	}
+	if snap.Errors[history.CategoryAPIUnreachable] != 1 {
+		t.Errorf("expected 1 api_unreachable error, got %v", snap.Errors)
+	}
+	if len(snap.Recent) != maxRecent || snap.Recent[0].Path != "/in/tap.m4a" || snap.Recent[1].Category != history.CategoryAPIUnreachable {
+		t.Errorf("expected the %d newest completions, newest first, got %+v", maxRecent, snap.Recent)
+	}
+}
+
+func TestProcessFile_UpdatesLiveState(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{},
+		Stabilizer: fakeStabilizer{},
+		Client:     failingClient{failOn: "bad"},
+		Writer:     &recordingWriter{},
+		Archiver:   &countingArchiver{},
+		Logger:     &recordingLogger{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	for _, path := range setupImportFiles(t, cfg.WatchDir, "good.m4a", "bad.m4a") {
+		svc.processFile(context.Background(), FileEvent{Path: path}, processOptions{stabilize: true, archive: true, queue: svc.queue})
+	}
+
+	snap := svc.live.snapshot()
+	if len(snap.InFlight) != 0 {
+		t.Errorf("expected no files in flight, got %+v", snap.InFlight)
+	}
+	if snap.Completed != 1 || snap.Failed != 1 || snap.Errors[history.CategoryTranscription] != 1 {
+		t.Errorf("expected 1 completed and 1 transcription failure, got %+v", snap)
+	}
+	if len(snap.Recent) != 2 || snap.Recent[0].Status != history.StatusFailed {
+		t.Errorf("expected the failure listed first, got %+v", snap.Recent)
+	}
+}
@@ -0,0 +1,34 @@
+package transcribe
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/llm"
+)
+
+// LLMConfig is the OpenAI-compatible chat completions endpoint used by the
+// llm title and tag strategies, e.g. a local Ollama.
+type LLMConfig struct {
+	// URL is the chat completions endpoint, e.g.
+	// http://localhost:11434/v1/chat/completions.
+	URL string `json:"url"`
+	// Model is the model name sent with each request.
+	Model string `json:"model,omitempty"`
+	// APIKey, when set, is sent as a bearer token.
+	APIKey string `json:"api_key,omitempty"`
+}
+
+// validate checks the endpoint URL.
+func (c LLMConfig) validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url %q must be a URL such as http://localhost:11434/v1/chat/completions", ErrInvalidLLM, c.URL)
+	}
+	return nil
+}
+
+// client returns a chat client for the endpoint.
+func (c LLMConfig) client() *llm.Client {
+	return &llm.Client{URL: c.URL, Model: c.Model, APIKey: c.APIKey}
+}
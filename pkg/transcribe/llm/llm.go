@@ -0,0 +1,102 @@
+// Package llm is a minimal client for OpenAI-compatible chat completions
+// endpoints, such as Ollama, llama.cpp or OpenAI itself, used to derive
+// titles and tags from transcripts.
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout bounds a request when HTTPClient is not set.
+const DefaultTimeout = 30 * time.Second
+
+// MaxInputChars limits how much of a long transcript is sent.
+const MaxInputChars = 4000
+
+// ErrEmptyResponse is returned when the model's reply has no content.
+var ErrEmptyResponse = errors.New("empty response from model")
+
+// Client sends single-turn chat requests.
+type Client struct {
+	// URL is the chat completions endpoint, e.g.
+	// http://localhost:11434/v1/chat/completions.
+	URL    string
+	Model  string
+	APIKey string
+	// HTTPClient is used for requests; nil means a client with
+	// DefaultTimeout.
+	HTTPClient *http.Client
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type request struct {
+	Model    string    `json:"model,omitempty"`
+	Messages []message `json:"messages"`
+}
+
+type response struct {
+	Choices []struct {
+		Message message `json:"message"`
+	} `json:"choices"`
+}
+
+// Complete sends instruction as the system message and input, cut to
+// MaxInputChars, as the user message, and returns the reply.
+func (c *Client) Complete(ctx context.Context, instruction, input string) (string, error) {
+	if len(input) > MaxInputChars {
+		input = input[:MaxInputChars]
+	}
+	body, err := json.Marshal(request{
+		Model: c.Model,
+		Messages: []message{
+			{Role: "system", Content: instruction},
+			{Role: "user", Content: input},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("chat request failed: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	var r response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return "", fmt.Errorf("decode chat response: %w", err)
+	}
+	if len(r.Choices) == 0 || r.Choices[0].Message.Content == "" {
+		return "", ErrEmptyResponse
+	}
+	return r.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,56 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Complete(t *testing.T) {
+	var got request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "Dentist reminder"}}]}`))
+	}))
+	defer server.Close()
+
+	c := &Client{URL: server.URL, Model: "llama3.2", APIKey: "key"}
+	reply, err := c.Complete(context.Background(), "Title this.", strings.Repeat("a", MaxInputChars+10))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if reply != "Dentist reminder" {
+		t.Errorf("expected reply, got %q", reply)
+	}
+	if got.Model != "llama3.2" || len(got.Messages) != 2 || got.Messages[0].Content != "Title this." {
+		t.Errorf("unexpected request: %+v", got)
+	}
+	if len(got.Messages[1].Content) != MaxInputChars {
+		t.Errorf("expected input cut to %d chars, got %d", MaxInputChars, len(got.Messages[1].Content))
+	}
+}
+
+func TestClient_CompleteErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/empty" {
+			w.Write([]byte(`{"choices": []}`))
+			return
+		}
+		http.Error(w, "model not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if _, err := (&Client{URL: server.URL + "/missing"}).Complete(context.Background(), "", "text"); err == nil || !strings.Contains(err.Error(), "model not found") {
+		t.Errorf("expected error with server message, got: %v", err)
+	}
+	if _, err := (&Client{URL: server.URL + "/empty"}).Complete(context.Background(), "", "text"); err != ErrEmptyResponse {
+		t.Errorf("expected ErrEmptyResponse, got: %v", err)
+	}
+}
@@ -5,9 +5,15 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/dirs"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
 )
 
 // Level represents a log severity level
@@ -73,7 +79,7 @@ type Logger interface {
 
 // Config configures the logger
 type Config struct {
-	// LogDir is the directory where log files are stored (default: ~/.nota/logs)
+	// LogDir is the directory where log files are stored (default: DefaultLogDir)
 	LogDir string
 	// Prefix is the log file prefix (e.g., "transcribe" produces transcribe-YYYY-MM-DD.log)
 	Prefix string
@@ -83,6 +89,8 @@ type Config struct {
 	Component string
 	// MinLevel is the minimum log level to write (default: LevelInfo)
 	MinLevel Level
+	// Clock timestamps lines and picks the day's file (default: the system clock)
+	Clock clock.Clock
 	// minLevelSet tracks whether MinLevel was explicitly configured
 	minLevelSet bool
 }
@@ -94,11 +102,21 @@ func (c Config) WithMinLevel(level Level) Config {
 	return c
 }
 
+// DefaultLogDir returns the logs directory under the state directory
+// ($XDG_STATE_HOME/nota/logs or ~/.nota/logs).
+func DefaultLogDir() (string, error) {
+	dir, err := dirs.State()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "logs"), nil
+}
+
 // DefaultConfig returns a Config with sensible defaults
 func DefaultConfig() Config {
-	homeDir, _ := os.UserHomeDir()
+	logDir, _ := DefaultLogDir()
 	return Config{
-		LogDir:        filepath.Join(homeDir, ".nota", "logs"),
+		LogDir:        logDir,
 		Prefix:        "transcribe",
 		RetentionDays: 30,
 		Component:     "",
@@ -117,11 +135,11 @@ type FileLogger struct {
 // New creates a new FileLogger with the given configuration
 func New(config Config) (*FileLogger, error) {
 	if config.LogDir == "" {
-		homeDir, err := os.UserHomeDir()
+		logDir, err := DefaultLogDir()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
+			return nil, fmt.Errorf("failed to get log directory: %w", err)
 		}
-		config.LogDir = filepath.Join(homeDir, ".nota", "logs")
+		config.LogDir = logDir
 	}
 	if config.Prefix == "" {
 		config.Prefix = "transcribe"
@@ -132,6 +150,7 @@ func New(config Config) (*FileLogger, error) {
 	if !config.minLevelSet {
 		config.MinLevel = LevelInfo
 	}
+	config.Clock = clock.OrReal(config.Clock)
 
 	// Create log directory if it doesn't exist
 	if err := os.MkdirAll(config.LogDir, 0755); err != nil {
@@ -211,7 +230,7 @@ func (l *FileLogger) log(level Level, msg string, err error, fields ...Field) {
 }
 
 func (l *FileLogger) writeLog(level Level, msg string, err error, fields ...Field) {
-	timestamp := time.Now().UTC().Format(time.RFC3339)
+	timestamp := l.config.Clock.Now().UTC().Format(time.RFC3339)
 
 	var sb strings.Builder
 	sb.WriteString(timestamp)
@@ -246,22 +265,39 @@ func (l *FileLogger) writeLog(level Level, msg string, err error, fields ...Fiel
 	}
 }
 
+// formatValue formats a field value. Values that are empty or contain
+// whitespace, quotes, '=' or unprintable characters are Go-quoted, so every
+// field reads back as the value that was logged.
 func formatValue(v any) string {
+	var s string
 	switch val := v.(type) {
 	case string:
-		if strings.ContainsAny(val, " \t\n") {
-			return fmt.Sprintf("%q", val)
-		}
-		return val
+		s = val
 	case time.Duration:
-		return val.String()
+		s = val.String()
 	default:
-		return fmt.Sprintf("%v", v)
+		s = fmt.Sprintf("%v", v)
+	}
+	if needsQuoting(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func needsQuoting(s string) bool {
+	if s == "" || !utf8.ValidString(s) {
+		return true
+	}
+	for _, r := range s {
+		if r == '"' || r == '=' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
 	}
+	return false
 }
 
 func (l *FileLogger) rotateIfNeeded() error {
-	today := time.Now().UTC().Format("2006-01-02")
+	today := l.config.Clock.Now().UTC().Format("2006-01-02")
 
 	if l.currentDate == today && l.file != nil {
 		return nil
@@ -295,7 +331,7 @@ func (l *FileLogger) cleanOldLogs() error {
 	}
 
 	prefix := l.config.Prefix + "-"
-	cutoff := time.Now().UTC().AddDate(0, 0, -l.config.RetentionDays)
+	cutoff := l.config.Clock.Now().UTC().AddDate(0, 0, -l.config.RetentionDays)
 
 	var toDelete []string
 
@@ -344,7 +380,7 @@ func (l *FileLogger) LogPath() string {
 		return l.file.Name()
 	}
 
-	today := time.Now().UTC().Format("2006-01-02")
+	today := l.config.Clock.Now().UTC().Format("2006-01-02")
 	filename := fmt.Sprintf("%s-%s.log", l.config.Prefix, today)
 	return filepath.Join(l.config.LogDir, filename)
 }
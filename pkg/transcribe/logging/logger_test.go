@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
 )
 
 func TestNew_CreatesLogDirectory(t *testing.T) {
@@ -375,6 +377,38 @@ func TestFileLogger_LogPath(t *testing.T) {
 	}
 }
 
+func TestFileLogger_RotatesAtMidnight(t *testing.T) {
+	logDir := filepath.Join(t.TempDir(), "logs")
+	clk := clock.NewFake(time.Date(2026, 1, 22, 23, 59, 59, 0, time.UTC))
+
+	logger, err := New(Config{LogDir: logDir, Prefix: "test", Clock: clk})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer logger.Close()
+	component := logger.WithComponent("pipeline")
+
+	logger.Info("before midnight")
+	clk.Advance(2 * time.Second)
+	component.Info("after midnight")
+
+	before, err := os.ReadFile(filepath.Join(logDir, "test-2026-01-22.log"))
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	after, err := os.ReadFile(filepath.Join(logDir, "test-2026-01-23.log"))
+	if err != nil {
+		t.Fatalf("expected a new log file after midnight: %v", err)
+	}
+
+	if string(before) != "2026-01-22T23:59:59Z INFO  before midnight\n" {
+		t.Errorf("unexpected first day's log: %q", before)
+	}
+	if string(after) != "2026-01-23T00:00:01Z INFO  [pipeline] after midnight\n" {
+		t.Errorf("unexpected second day's log: %q", after)
+	}
+}
+
 func TestFileLogger_WithComponentMethod(t *testing.T) {
 	tmpDir := t.TempDir()
 	logDir := filepath.Join(tmpDir, "logs")
@@ -467,6 +501,31 @@ func TestFormatValue_QuotesSpaces(t *testing.T) {
 	}
 }
 
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"/in/memo.m4a", `/in/memo.m4a`},
+		{"réunion-会議.m4a", `réunion-会議.m4a`},
+		{"", `""`},
+		{"hello world", `"hello world"`},
+		{`say"hi".m4a`, `"say\"hi\".m4a"`},
+		{"a=b.m4a", `"a=b.m4a"`},
+		{"\x1b[31mred.m4a", `"\x1b[31mred.m4a"`},
+		{"line\nbreak", `"line\nbreak"`},
+		{"bad\xffutf8", `"bad\xffutf8"`},
+		{5 * time.Second, `5s`},
+		{[]string{"a", "b"}, `"[a b]"`},
+	}
+
+	for _, tt := range tests {
+		if got := formatValue(tt.value); got != tt.want {
+			t.Errorf("formatValue(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
 func TestNew_ErrorOnInvalidLogDir(t *testing.T) {
 	if os.Getuid() == 0 {
 		t.Skip("skipping permission test when running as root")
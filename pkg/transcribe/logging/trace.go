@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// TraceKey is the field key trace IDs are logged under.
+const TraceKey = "trace"
+
+// traceKey is the context key for trace IDs.
+type traceKey struct{}
+
+// NewTraceID returns a short random ID for correlating the log lines of one
+// unit of work, such as one file going through the pipeline.
+func NewTraceID() string {
+	var b [4]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// WithTraceID returns a copy of ctx carrying the trace ID.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceKey{}, id)
+}
+
+// TraceID returns the trace ID carried by ctx, or "" if it has none.
+func TraceID(ctx context.Context) string {
+	id, _ := ctx.Value(traceKey{}).(string)
+	return id
+}
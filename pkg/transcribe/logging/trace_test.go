@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+func TestNewTraceID(t *testing.T) {
+	a, b := NewTraceID(), NewTraceID()
+	if !regexp.MustCompile(`^[0-9a-f]{8}$`).MatchString(a) {
+		t.Errorf("expected 8 hex characters, got %q", a)
+	}
+	if a == b {
+		t.Errorf("expected distinct trace IDs, got %q twice", a)
+	}
+}
+
+func TestTraceID_Context(t *testing.T) {
+	if id := TraceID(context.Background()); id != "" {
+		t.Errorf("expected no trace ID, got %q", id)
+	}
+	ctx := WithTraceID(context.Background(), "ab12cd34")
+	if id := TraceID(ctx); id != "ab12cd34" {
+		t.Errorf("expected ab12cd34, got %q", id)
+	}
+}
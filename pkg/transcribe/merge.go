@@ -0,0 +1,158 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/metadata"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
+)
+
+// noteMerger groups recordings made within a time window of each other
+// into one note per output directory. Groups live in memory, so a restart
+// starts new notes.
+type noteMerger struct {
+	window time.Duration
+
+	mu sync.Mutex
+	// last maps an output directory to the note most recently written there.
+	last map[string]*mergedNote
+}
+
+// mergedNote is a note that later recordings may be appended to.
+type mergedNote struct {
+	path string
+	// recorded is the latest recording time in the note; the group grows as
+	// long as each new recording is within the window of it.
+	recorded time.Time
+}
+
+// newNoteMerger returns nil when merging is disabled.
+func newNoteMerger(windowMinutes int) *noteMerger {
+	if windowMinutes <= 0 {
+		return nil
+	}
+	return &noteMerger{
+		window: time.Duration(windowMinutes) * time.Minute,
+		last:   make(map[string]*mergedNote),
+	}
+}
+
+// target returns the note in dir a recording made at recorded belongs to.
+func (m *noteMerger) target(dir string, recorded time.Time) (string, bool) {
+	note := m.last[dir]
+	if note == nil {
+		return "", false
+	}
+	gap := recorded.Sub(note.recorded)
+	if gap < 0 {
+		gap = -gap
+	}
+	return note.path, gap <= m.window
+}
+
+// remember records that a recording made at recorded is in the note at path.
+func (m *noteMerger) remember(dir, path string, recorded time.Time) {
+	note := m.last[dir]
+	if note == nil || note.path != path {
+		m.last[dir] = &mergedNote{path: path, recorded: recorded}
+		return
+	}
+	if recorded.After(note.recorded) {
+		note.recorded = recorded
+	}
+}
+
+// writeNote writes the transcript as a new note, or, when merging is
+// enabled and the recording was made within the window of the previous
+// one, appends it to that note. With merging, each recording's transcript
+// goes under a heading with its time and file name.
+func (s *Service) writeNote(ctx context.Context, fileLogger Logger, path, text string, opts OutputOptions) (string, error) {
+	if s.merger == nil {
+		return s.writer.Write(ctx, text, opts)
+	}
+
+	// Each section carries its own footer rather than the note ending with
+	// the first recording's
+	if opts.Footer {
+		text = writer.AppendFooter(text, opts.Processing, opts.Format)
+		opts.Footer = false
+	}
+	recorded := recordingTime(path)
+	text = mergeSection(opts.Format, recorded, path, text)
+
+	// Hold the lock while writing so recordings of one group processed
+	// concurrently end up in the same note
+	s.merger.mu.Lock()
+	defer s.merger.mu.Unlock()
+
+	if note, ok := s.merger.target(opts.OutputDir, recorded); ok {
+		var hash string
+		if opts.Processing != nil {
+			hash = opts.Processing.SourceHash
+		}
+		err := appendToNote(note, text, hash)
+		if err == nil {
+			s.merger.remember(opts.OutputDir, note, recorded)
+			fileLogger.Info("transcript merged into note",
+				logging.String("path", path),
+				logging.String("note", note),
+			)
+			return note, nil
+		}
+		fileLogger.Error("failed to merge into note, writing a new one", err,
+			logging.String("note", note),
+		)
+	}
+
+	note, err := s.writer.Write(ctx, text, opts)
+	if err != nil {
+		return "", err
+	}
+	s.merger.remember(opts.OutputDir, note, recorded)
+	return note, nil
+}
+
+// mergeSection puts a heading with the recording's time and file name above
+// its transcript, in the note's format.
+func mergeSection(format writer.Format, recorded time.Time, path, text string) string {
+	heading := fmt.Sprintf("%s · %s", recorded.Format("15:04"), filepath.Base(path))
+	return format.Heading(2, heading) + "\n" + text
+}
+
+// appendToNote adds a section to the end of an existing note, and the hash
+// of its recording to the note's source_hashes where it has frontmatter, so
+// every recording merged into it can be matched to it.
+func appendToNote(note, section, hash string) error {
+	content, err := os.ReadFile(note)
+	if err != nil {
+		return err
+	}
+	sep := "\n"
+	if !strings.HasSuffix(string(content), "\n") {
+		sep = "\n\n"
+	}
+	updated := writer.AddSourceHash(string(content), hash) + sep + section + "\n"
+	// The file exists, so its mode is kept
+	return os.WriteFile(note, []byte(updated), 0)
+}
+
+// recordingTime returns when the audio was recorded: the creation time in
+// an M4A file's metadata, or else its modification time.
+func recordingTime(path string) time.Time {
+	if strings.EqualFold(filepath.Ext(path), ".m4a") {
+		if meta, err := metadata.ExtractM4A(path); err == nil && !meta.CreationTime.IsZero() {
+			return meta.CreationTime.Local()
+		}
+	}
+	if info, err := os.Stat(path); err == nil {
+		return info.ModTime()
+	}
+	return time.Now()
+}
@@ -0,0 +1,153 @@
+package transcribe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordAt creates an audio file whose modification time is its recording
+// time.
+func recordAt(t *testing.T, dir, name string, at time.Time) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("audio"), 0644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := os.Chtimes(path, at, at); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return path
+}
+
+func TestService_MergesMemosWithinWindow(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.MergeWindowMinutes = 5
+	svc, err := NewServiceWith(cfg, Options{Watcher: &fakeWatcher{}, Logger: &recordingLogger{}})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	audioDir := t.TempDir()
+	start := time.Date(2026, 1, 22, 9, 30, 0, 0, time.Local)
+	memos := []struct {
+		path string
+		text string
+	}{
+		{recordAt(t, audioDir, "first.wav", start), "Buy milk."},
+		{recordAt(t, audioDir, "second.wav", start.Add(4*time.Minute)), "And eggs."},
+		// Within the window of the second memo, so the group keeps growing.
+		{recordAt(t, audioDir, "third.wav", start.Add(8*time.Minute)), "And bread."},
+		{recordAt(t, audioDir, "later.wav", start.Add(time.Hour)), "Call the dentist."},
+	}
+
+	var notes []string
+	for i, memo := range memos {
+		opts := OutputOptions{OutputDir: cfg.OutputDir, Timestamp: start.Add(time.Duration(i) * time.Minute)}
+		note, err := svc.writeNote(context.Background(), &recordingLogger{}, memo.path, memo.text, opts)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		notes = append(notes, note)
+	}
+
+	if notes[1] != notes[0] || notes[2] != notes[0] {
+		t.Errorf("expected the first three memos in one note, got %v", notes[:3])
+	}
+	if notes[3] == notes[0] {
+		t.Errorf("expected a memo outside the window to get its own note")
+	}
+
+	content, err := os.ReadFile(notes[0])
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	text := string(content)
+	for _, want := range []string{"## 09:30 · first.wav\n\nBuy milk.", "## 09:34 · second.wav\n\nAnd eggs.", "## 09:38 · third.wav\n\nAnd bread."} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected merged note to contain %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, "Buy milk.") > strings.Index(text, "And bread.") {
+		t.Errorf("expected sections in recording order, got:\n%s", text)
+	}
+	if strings.Contains(text, "Call the dentist.") {
+		t.Errorf("expected the later memo to be left out of the merged note")
+	}
+}
+
+func TestService_MergeWritesNewNoteWhenPreviousIsGone(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.MergeWindowMinutes = 5
+	svc, err := NewServiceWith(cfg, Options{Watcher: &fakeWatcher{}, Logger: &recordingLogger{}})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	audioDir := t.TempDir()
+	start := time.Date(2026, 1, 22, 9, 30, 0, 0, time.Local)
+	first := recordAt(t, audioDir, "first.wav", start)
+	second := recordAt(t, audioDir, "second.wav", start.Add(time.Minute))
+
+	note, err := svc.writeNote(context.Background(), &recordingLogger{}, first, "Buy milk.",
+		OutputOptions{OutputDir: cfg.OutputDir, Timestamp: start})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := os.Remove(note); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	next, err := svc.writeNote(context.Background(), &recordingLogger{}, second, "And eggs.",
+		OutputOptions{OutputDir: cfg.OutputDir, Timestamp: start.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	content, err := os.ReadFile(next)
+	if err != nil {
+		t.Fatalf("expected the second memo to be written to a new note, got: %v", err)
+	}
+	if !strings.Contains(string(content), "And eggs.") {
+		t.Errorf("expected new note to contain the transcript, got:\n%s", content)
+	}
+}
+
+func TestService_MergeFooterPerSection(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.MergeWindowMinutes = 5
+	svc, err := NewServiceWith(cfg, Options{Watcher: &fakeWatcher{}, Logger: &recordingLogger{}})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	audioDir := t.TempDir()
+	start := time.Date(2026, 1, 22, 9, 30, 0, 0, time.Local)
+	var note string
+	for i, model := range []string{"base", "large-v3"} {
+		path := recordAt(t, audioDir, model+".wav", start.Add(time.Duration(i)*time.Minute))
+		opts := OutputOptions{
+			OutputDir:  cfg.OutputDir,
+			Timestamp:  start,
+			Footer:     true,
+			Processing: &ProcessingInfo{Model: model},
+		}
+		if note, err = svc.writeNote(context.Background(), &recordingLogger{}, path, "Buy milk.", opts); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(note)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	text := string(content)
+	base := strings.Index(text, "- **Model:** base\n")
+	large := strings.Index(text, "- **Model:** large-v3\n")
+	second := strings.Index(text, "## 09:31 · large-v3.wav")
+	if base < 0 || large < 0 || second < 0 || !(base < second && second < large) {
+		t.Errorf("expected a footer at the end of each section, got:\n%s", text)
+	}
+}
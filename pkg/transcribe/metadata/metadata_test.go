@@ -0,0 +1,230 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// box returns an MP4 box of the given type holding content, with its size
+// in the header.
+func box(boxType string, content ...[]byte) []byte {
+	body := bytes.Join(content, nil)
+	b := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(b, boxType...), body...)
+}
+
+// boxHeader returns just a box header claiming size bytes.
+func boxHeader(boxType string, size uint32) []byte {
+	return append(binary.BigEndian.AppendUint32(nil, size), boxType...)
+}
+
+// largeBoxHeader returns a box header with a 64-bit size of size bytes.
+func largeBoxHeader(boxType string, size uint64) []byte {
+	return binary.BigEndian.AppendUint64(boxHeader(boxType, 1), size)
+}
+
+var ftypBox = box("ftyp", []byte("M4A \x00\x00\x00\x00M4A "))
+
+func TestParseM4A_Malformed(t *testing.T) {
+	valid := m4aFile(time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC), 120)
+	mvhd := valid[len(ftypBox)+8:]
+
+	tests := map[string][]byte{
+		"empty":                    nil,
+		"short header":             []byte{0, 0, 0},
+		"zero size box":            append(boxHeader("free", 0), valid...),
+		"size below header":        append(boxHeader("free", 4), valid...),
+		"huge skipped box":         append(append(ftypBox, boxHeader("free", 0xFFFFFFFF)...), valid[len(ftypBox):]...),
+		"moov overruns file":       append(ftypBox, boxHeader("moov", 0xFFFFFFFF)...),
+		"child overruns moov":      append(ftypBox, box("moov", boxHeader("mvhd", 0x7FFFFFFF))...),
+		"short ftyp":               append(box("ftyp", []byte("M4")), valid[len(ftypBox):]...),
+		"short mvhd":               append(ftypBox, box("moov", box("mvhd", mvhd[8:20]))...),
+		"mvhd without fields":      append(ftypBox, box("moov", box("mvhd"))...),
+		"truncated moov":           valid[:len(valid)-50],
+		"64-bit size below header": append(largeBoxHeader("free", 12), valid...),
+		"64-bit size overflows":    append(ftypBox, largeBoxHeader("moov", 1<<63)...),
+		"64-bit moov overruns":     append(ftypBox, box("moov", largeBoxHeader("mvhd", 1<<40))...),
+		"short 64-bit size":        append(ftypBox, boxHeader("moov", 1)...),
+		"short mvhd version 1":     append(ftypBox, box("moov", box("mvhd", []byte{1, 0, 0, 0}, make([]byte, 20)))...),
+		"too many boxes":           append(ftypBox, bytes.Repeat(box("free"), maxBoxes)...),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			meta, err := parseM4A(bytes.NewReader(data))
+			if err != ErrInvalidFormat {
+				t.Errorf("expected ErrInvalidFormat, got: %v, %+v", err, meta)
+			}
+		})
+	}
+}
+
+func TestParseM4A_TruncatedAudio(t *testing.T) {
+	// A recording cut short keeps its metadata when the moov box comes
+	// before the audio data
+	data := append(m4aFile(time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC), 120), boxHeader("mdat", 1<<20)...)
+	data = append(data, make([]byte, 100)...)
+
+	meta, err := parseM4A(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if meta.Duration != 2*time.Minute {
+		t.Errorf("expected 2m0s, got %v", meta.Duration)
+	}
+}
+
+func TestParseM4A_BoxToEndOfFile(t *testing.T) {
+	// A size of zero runs the box to the end of the file
+	data := append(m4aFile(time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC), 120), boxHeader("mdat", 0)...)
+	data = append(data, make([]byte, 100)...)
+
+	if _, err := parseM4A(bytes.NewReader(data)); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
+
+// sparseFile is a ReadSeeker over a file of the given size holding data at
+// the given offsets and zeros elsewhere, standing in for recordings too
+// large to build in memory.
+type sparseFile struct {
+	size   int64
+	chunks map[int64][]byte
+	pos    int64
+}
+
+func (f *sparseFile) Read(p []byte) (int, error) {
+	if f.pos >= f.size {
+		return 0, io.EOF
+	}
+	p = p[:min(int64(len(p)), f.size-f.pos)]
+	clear(p)
+	for off, data := range f.chunks {
+		// Copy the part of the chunk overlapping p
+		lo, hi := max(off, f.pos), min(off+int64(len(data)), f.pos+int64(len(p)))
+		if lo < hi {
+			copy(p[lo-f.pos:], data[lo-off:hi-off])
+		}
+	}
+	f.pos += int64(len(p))
+	return len(p), nil
+}
+
+func (f *sparseFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.pos
+	case io.SeekEnd:
+		offset += f.size
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	f.pos = offset
+	return offset, nil
+}
+
+func TestParseM4A_LargeBoxes(t *testing.T) {
+	created := time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)
+	moov := m4aFile(created, 120)[len(ftypBox):]
+	const audioSize = 5 << 30 // over 4 GB
+
+	mdat := largeBoxHeader("mdat", audioSize)
+	// The moov box, rewritten with a 64-bit size
+	largeMoov := append(largeBoxHeader("moov", uint64(len(moov)+8)), moov[8:]...)
+
+	tests := map[string]*sparseFile{
+		"audio before moov": {
+			size: int64(len(ftypBox)) + audioSize + int64(len(moov)),
+			chunks: map[int64][]byte{
+				0:                               append(append([]byte{}, ftypBox...), mdat...),
+				int64(len(ftypBox)) + audioSize: moov,
+			},
+		},
+		"moov with a 64-bit size": {
+			size: int64(len(ftypBox)+len(largeMoov)) + audioSize,
+			chunks: map[int64][]byte{
+				0: append(append(append([]byte{}, ftypBox...), largeMoov...), mdat...),
+			},
+		},
+	}
+	for name, file := range tests {
+		t.Run(name, func(t *testing.T) {
+			meta, err := parseM4A(file)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if meta.Duration != 2*time.Minute {
+				t.Errorf("expected 2m0s, got %v", meta.Duration)
+			}
+			if !meta.CreationTime.Equal(created) {
+				t.Errorf("expected %v, got %v", created, meta.CreationTime)
+			}
+		})
+	}
+}
+
+func TestParseM4A_MvhdVersion1(t *testing.T) {
+	created := time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)
+	// Version 1 uses 64-bit fields, here a duration beyond 32 bits of
+	// milliseconds: 60 days
+	mvhd := make([]byte, 4+28+80)
+	mvhd[0] = 1
+	binary.BigEndian.PutUint64(mvhd[4:12], uint64(created.Unix()+macEpochOffset))
+	binary.BigEndian.PutUint32(mvhd[20:24], 1000)
+	binary.BigEndian.PutUint64(mvhd[24:32], 60*24*3600*1000)
+
+	meta, err := parseM4A(bytes.NewReader(append(ftypBox, box("moov", box("mvhd", mvhd))...)))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if meta.Duration != 60*24*time.Hour {
+		t.Errorf("expected 1440h0m0s, got %v", meta.Duration)
+	}
+	if !meta.CreationTime.Equal(created) {
+		t.Errorf("expected %v, got %v", created, meta.CreationTime)
+	}
+}
+
+// countingReader counts the reads and seeks made of a ReadSeeker.
+type countingReader struct {
+	io.ReadSeeker
+	calls int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	c.calls++
+	return c.ReadSeeker.Read(p)
+}
+
+func (c *countingReader) Seek(offset int64, whence int) (int64, error) {
+	c.calls++
+	return c.ReadSeeker.Seek(offset, whence)
+}
+
+func FuzzParseM4A(f *testing.F) {
+	valid := m4aFile(time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC), 120)
+	f.Add(valid)
+	f.Add(append(valid, boxHeader("mdat", 0)...))
+	f.Add(append(ftypBox, box("moov", box("mvhd", make([]byte, 4)), box("udta"))...))
+	f.Add(append(boxHeader("free", 0), valid...))
+	f.Add(append(largeBoxHeader("free", 16), valid...))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := &countingReader{ReadSeeker: bytes.NewReader(data)}
+		meta, err := parseM4A(r)
+		if err != nil && !errors.Is(err, ErrInvalidFormat) {
+			t.Fatalf("expected nil or ErrInvalidFormat, got: %v", err)
+		}
+		if err == nil && meta == nil {
+			t.Fatal("expected metadata without an error")
+		}
+		// Each box costs a bounded number of reads and seeks
+		if limit := 10*maxBoxes + 10; r.calls > limit {
+			t.Fatalf("expected at most %d reads and seeks, got %d", limit, r.calls)
+		}
+	})
+}
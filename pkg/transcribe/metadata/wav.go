@@ -0,0 +1,70 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// ErrInvalidWAV indicates the file is not a valid RIFF WAVE file.
+var ErrInvalidWAV = errors.New("invalid WAV format")
+
+// ExtractWAV reads the duration of a WAV file from its fmt and data chunks.
+// WAV files carry no creation time or title, so only Duration is set.
+func ExtractWAV(path string) (*AudioMetadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseWAV(f)
+}
+
+func parseWAV(r io.ReadSeeker) (*AudioMetadata, error) {
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrInvalidWAV
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, ErrInvalidWAV
+	}
+
+	// Chunks are an ID and a little-endian size, padded to an even length
+	var byteRate uint32
+	chunk := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return nil, ErrInvalidWAV
+		}
+		id := string(chunk[0:4])
+		size := binary.LittleEndian.Uint32(chunk[4:8])
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, ErrInvalidWAV
+			}
+			format := make([]byte, 16)
+			if _, err := io.ReadFull(r, format); err != nil {
+				return nil, ErrInvalidWAV
+			}
+			byteRate = binary.LittleEndian.Uint32(format[8:12])
+			if _, err := r.Seek(int64(size-16+size%2), io.SeekCurrent); err != nil {
+				return nil, err
+			}
+		case "data":
+			if byteRate == 0 {
+				return nil, ErrInvalidWAV
+			}
+			seconds := float64(size) / float64(byteRate)
+			return &AudioMetadata{Duration: time.Duration(seconds * float64(time.Second))}, nil
+		default:
+			if _, err := r.Seek(int64(size+size%2), io.SeekCurrent); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
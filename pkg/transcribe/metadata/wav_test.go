@@ -0,0 +1,74 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// wavFile returns a 16-bit mono WAV file of the given sample rate holding
+// samples samples, with a LIST chunk before the data as many recorders write.
+func wavFile(sampleRate, samples uint32) []byte {
+	var b bytes.Buffer
+	le := func(v any) { binary.Write(&b, binary.LittleEndian, v) }
+
+	b.WriteString("RIFF")
+	le(uint32(0)) // overall size, not checked
+	b.WriteString("WAVE")
+
+	b.WriteString("fmt ")
+	le(uint32(16))
+	le(uint16(1)) // PCM
+	le(uint16(1)) // mono
+	le(sampleRate)
+	le(sampleRate * 2) // byte rate
+	le(uint16(2))      // block align
+	le(uint16(16))     // bits per sample
+
+	b.WriteString("LIST")
+	le(uint32(3))
+	b.Write([]byte{'a', 'b', 'c', 0}) // odd size plus padding
+
+	b.WriteString("data")
+	le(samples * 2)
+	b.Write(make([]byte, samples*2))
+	return b.Bytes()
+}
+
+func TestExtractWAV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memo.wav")
+	if err := os.WriteFile(path, wavFile(8000, 12000), 0644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	meta, err := ExtractWAV(path)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if meta.Duration != 1500*time.Millisecond {
+		t.Errorf("expected 1.5s, got %v", meta.Duration)
+	}
+}
+
+func TestExtractWAV_InvalidFormat(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":      nil,
+		"not riff":   []byte("ID3\x03\x00\x00\x00\x00\x00\x00\x00\x00"),
+		"no data":    wavFile(8000, 10)[:36],
+		"data first": append([]byte("RIFF\x00\x00\x00\x00WAVEdata\x02\x00\x00\x00"), 0, 0),
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "bad.wav")
+			os.WriteFile(path, data, 0644)
+			if _, err := ExtractWAV(path); !errors.Is(err, ErrInvalidWAV) {
+				t.Errorf("expected ErrInvalidWAV, got: %v", err)
+			}
+		})
+	}
+}
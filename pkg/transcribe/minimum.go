@@ -0,0 +1,72 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// belowMinimum returns the skip category and a description when path is
+// smaller than min_file_size_kb or shorter than min_duration_seconds, and ""
+// otherwise. Recordings whose length cannot be read are only checked by size.
+func (s *Service) belowMinimum(path string) (string, string) {
+	if s.config.MinFileSizeKB > 0 {
+		if info, err := os.Stat(path); err == nil && info.Size() < int64(s.config.MinFileSizeKB)*1024 {
+			return history.CategoryTooSmall, fmt.Sprintf("%d bytes, under min_file_size_kb %d", info.Size(), s.config.MinFileSizeKB)
+		}
+	}
+	if s.config.MinDurationSeconds > 0 {
+		minDuration := time.Duration(s.config.MinDurationSeconds) * time.Second
+		if d := audioDuration(path); d > 0 && d < minDuration {
+			return history.CategoryTooShort, fmt.Sprintf("%s long, under min_duration_seconds %d", d.Round(100*time.Millisecond), s.config.MinDurationSeconds)
+		}
+	}
+	return "", ""
+}
+
+// skipBelowMinimum archives event's file, if archive is set, without
+// transcribing it and records it as skipped for reason.
+func (s *Service) skipBelowMinimum(ctx context.Context, fileLogger Logger, event FileEvent, archive bool, reason, detail string, startTime time.Time) error {
+	if s.dryRun {
+		fileLogger.Info("dry run: recording below minimum, would skip",
+			logging.String("path", event.Path),
+			logging.String("reason", detail),
+		)
+		if archive {
+			detail += ", would archive without transcribing"
+		}
+		s.reportSkipped(event, StageDryRun, startTime, detail)
+		return nil
+	}
+
+	if archive {
+		if err := s.archiveFile(ctx, event.Path, s.planArchive(event.Path)); err != nil {
+			return s.failFile(fileLogger, "failed to archive file", event,
+				history.Record{Category: history.CategoryArchive}, err, startTime)
+		}
+	}
+
+	fileLogger.Info("recording below minimum, skipped without transcribing",
+		logging.String("path", event.Path),
+		logging.String("reason", detail),
+	)
+	s.reportSkipped(event, StageSkipped, startTime, detail)
+	s.recordOutcome(event, history.Record{Status: history.StatusSkipped, Category: reason}, nil, startTime)
+	return nil
+}
+
+// reportSkipped reports a file leaving the pipeline at stage without being
+// transcribed.
+func (s *Service) reportSkipped(event FileEvent, stage Stage, startTime time.Time, detail string) {
+	s.report(ProgressEvent{
+		Path:    event.Path,
+		Stage:   stage,
+		Size:    event.Size,
+		Elapsed: time.Since(startTime),
+		Detail:  detail,
+	})
+}
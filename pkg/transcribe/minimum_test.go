@@ -0,0 +1,133 @@
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+// writeWAV creates a 16-bit mono 8 kHz WAV file of the given length.
+func writeWAV(t *testing.T, dir, name string, length time.Duration) string {
+	t.Helper()
+	const sampleRate = 8000
+	dataSize := uint32(length.Seconds() * sampleRate * 2)
+
+	var b bytes.Buffer
+	le := func(v any) { binary.Write(&b, binary.LittleEndian, v) }
+	b.WriteString("RIFF")
+	le(36 + dataSize)
+	b.WriteString("WAVEfmt ")
+	le(uint32(16))
+	le(uint16(1))
+	le(uint16(1))
+	le(uint32(sampleRate))
+	le(uint32(sampleRate * 2))
+	le(uint16(2))
+	le(uint16(16))
+	b.WriteString("data")
+	le(dataSize)
+	b.Write(make([]byte, dataSize))
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return path
+}
+
+func TestProcessFile_SkipsRecordingsBelowMinimum(t *testing.T) {
+	tests := []struct {
+		name        string
+		minSizeKB   int
+		minDuration int
+		length      time.Duration
+		category    string
+	}{
+		{name: "too small", minSizeKB: 64, length: time.Second, category: history.CategoryTooSmall},
+		{name: "too short", minDuration: 3, length: time.Second, category: history.CategoryTooShort},
+		{name: "long enough", minSizeKB: 8, minDuration: 3, length: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := setupBuilderTest(t)
+			cfg.MinFileSizeKB = tt.minSizeKB
+			cfg.MinDurationSeconds = tt.minDuration
+
+			w := &recordingWriter{}
+			arch := &countingArchiver{}
+			svc, err := NewServiceWith(cfg, Options{
+				Watcher:    &fakeWatcher{},
+				Stabilizer: fakeStabilizer{},
+				Client:     fakeClient{},
+				Writer:     w,
+				Archiver:   arch,
+				Logger:     &recordingLogger{},
+			})
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			defer svc.Close()
+
+			audioPath := writeWAV(t, cfg.WatchDir, "tap.wav", tt.length)
+			if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{stabilize: true, archive: true}); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+
+			if arch.count.Load() != 1 {
+				t.Errorf("expected the recording to be archived, got %d archives", arch.count.Load())
+			}
+			p, _ := history.DefaultPath()
+			records, _ := history.New(p).Load(time.Time{})
+			if len(records) != 1 {
+				t.Fatalf("expected 1 history record, got %d", len(records))
+			}
+
+			if tt.category == "" {
+				if len(w.texts) != 1 || records[0].Status != history.StatusCompleted {
+					t.Errorf("expected the recording to be transcribed, got notes %q and %+v", w.texts, records[0])
+				}
+				return
+			}
+			if len(w.texts) != 0 {
+				t.Errorf("expected no note, got %q", w.texts)
+			}
+			if records[0].Status != history.StatusSkipped || records[0].Category != tt.category {
+				t.Errorf("expected a skipped %s record, got %+v", tt.category, records[0])
+			}
+		})
+	}
+}
+
+func TestProcessFile_DryRunDoesNotArchiveShortRecordings(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.MinDurationSeconds = 3
+
+	arch := &countingArchiver{}
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{},
+		Stabilizer: fakeStabilizer{},
+		Client:     fakeClient{},
+		Archiver:   arch,
+		Logger:     &recordingLogger{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+	svc.SetDryRun(true)
+
+	audioPath := writeWAV(t, cfg.WatchDir, "tap.wav", time.Second)
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{stabilize: true, archive: true}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if arch.count.Load() != 0 {
+		t.Errorf("expected no archive in dry-run mode, got %d", arch.count.Load())
+	}
+}
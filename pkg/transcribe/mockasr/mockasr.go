@@ -0,0 +1,97 @@
+// Package mockasr provides a fake whisper-asr-webservice for local development and tests.
+package mockasr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultText is the transcription returned when no text is configured.
+const DefaultText = "This is a mock transcription from the nota mock ASR server."
+
+// SegmentSeconds is the end time reported for the single segment holding
+// the whole transcription.
+const SegmentSeconds = 2.5
+
+// Server serves a canned /asr endpoint compatible with WhisperASRClient.
+type Server struct {
+	// Text is returned as the transcription of every upload.
+	Text string
+	// Language is reported as the detected language.
+	Language string
+	// Delay simulates transcription time before responding.
+	Delay time.Duration
+	// OnRequest is called after each successfully received upload, if set.
+	OnRequest func(filename string, size int64)
+
+	requests atomic.Int64
+}
+
+// New creates a Server that returns the default text in English.
+func New() *Server {
+	return &Server{
+		Text:     DefaultText,
+		Language: "en",
+	}
+}
+
+// Requests returns the number of uploads handled so far.
+func (s *Server) Requests() int64 {
+	return s.requests.Load()
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/asr" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	file, header, err := r.FormFile("audio_file")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("missing audio_file: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+	file.Close()
+
+	s.requests.Add(1)
+	if s.OnRequest != nil {
+		s.OnRequest(header.Filename, header.Size)
+	}
+
+	if s.Delay > 0 {
+		select {
+		case <-time.After(s.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	language := s.Language
+	if lang := r.URL.Query().Get("language"); lang != "" {
+		language = lang
+	}
+
+	if r.URL.Query().Get("output") == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, s.Text)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"text":     s.Text,
+		"language": language,
+		"segments": []map[string]any{
+			{"start": 0.0, "end": SegmentSeconds, "text": s.Text},
+		},
+	})
+}
@@ -0,0 +1,94 @@
+package mockasr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+func TestServer_WhisperClientRoundTrip(t *testing.T) {
+	mock := New()
+	mock.Text = "hello from the mock"
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	audioPath := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(audioPath, []byte("fake audio"), 0644)
+
+	tests := []struct {
+		name   string
+		format client.OutputFormat
+	}{
+		{name: "json", format: client.OutputFormatJSON},
+		{name: "text", format: client.OutputFormatText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := client.NewWhisperASRClient(server.URL, client.WithOutputFormat(tt.format))
+			result, err := c.Transcribe(context.Background(), audioPath, client.TranscribeOptions{})
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if result.Text != "hello from the mock" {
+				t.Errorf("expected canned text, got: %q", result.Text)
+			}
+		})
+	}
+
+	if mock.Requests() != 2 {
+		t.Errorf("expected 2 requests, got: %d", mock.Requests())
+	}
+}
+
+func TestServer_RequestedLanguage(t *testing.T) {
+	server := httptest.NewServer(New())
+	defer server.Close()
+
+	audioPath := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(audioPath, []byte("fake audio"), 0644)
+
+	c := client.NewWhisperASRClient(server.URL)
+	result, err := c.Transcribe(context.Background(), audioPath, client.TranscribeOptions{Language: "de"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.Language != "de" {
+		t.Errorf("expected language de, got: %s", result.Language)
+	}
+}
+
+func TestServer_RejectsBadRequests(t *testing.T) {
+	server := httptest.NewServer(New())
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{name: "unknown path", method: http.MethodPost, path: "/other", want: http.StatusNotFound},
+		{name: "wrong method", method: http.MethodGet, path: "/asr", want: http.StatusMethodNotAllowed},
+		{name: "no audio file", method: http.MethodPost, path: "/asr", want: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, server.URL+tt.path, nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("expected status %d, got: %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Package notify sends short notifications as desktop notifications, to an
+// ntfy topic, or to a hook command, for reminders from scheduled jobs.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds an ntfy request when HTTPClient is not set.
+const DefaultTimeout = 30 * time.Second
+
+// Message is a notification.
+type Message struct {
+	Title string
+	Body  string
+}
+
+// Sender delivers notifications.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Desktop shows notifications on the desktop with notify-send.
+type Desktop struct {
+	// run runs the command; nil runs it with exec.
+	run func(ctx context.Context, name string, args ...string) error
+}
+
+// Send runs notify-send with the title and body.
+func (d *Desktop) Send(ctx context.Context, msg Message) error {
+	run := d.run
+	if run == nil {
+		run = func(ctx context.Context, name string, args ...string) error {
+			return exec.CommandContext(ctx, name, args...).Run()
+		}
+	}
+	if err := run(ctx, "notify-send", "--app-name=nota", msg.Title, msg.Body); err != nil {
+		return fmt.Errorf("notify-send: %w", err)
+	}
+	return nil
+}
+
+// Ntfy publishes notifications to an ntfy topic.
+type Ntfy struct {
+	// URL is the topic, e.g. https://ntfy.sh/my-vault.
+	URL string
+	// Token, when set, is sent as a bearer token.
+	Token string
+	// HTTPClient is used for requests; nil means a client with
+	// DefaultTimeout.
+	HTTPClient *http.Client
+}
+
+// Send posts the body to the topic, with the title in the Title header.
+func (n *Ntfy) Send(ctx context.Context, msg Message) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, strings.NewReader(msg.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", msg.Title)
+	if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+
+	httpClient := n.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("ntfy request failed: %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// Command runs a hook command for each notification, with the title and
+// body in NOTA_NOTIFY_TITLE and NOTA_NOTIFY_BODY and the body on stdin.
+type Command struct {
+	// Args is the command and its arguments.
+	Args []string
+}
+
+// Send runs the command and fails if it exits non-zero.
+func (c *Command) Send(ctx context.Context, msg Message) error {
+	if len(c.Args) == 0 {
+		return fmt.Errorf("notify command is empty")
+	}
+	cmd := exec.CommandContext(ctx, c.Args[0], c.Args[1:]...)
+	cmd.Env = append(os.Environ(), "NOTA_NOTIFY_TITLE="+msg.Title, "NOTA_NOTIFY_BODY="+msg.Body)
+	cmd.Stdin = strings.NewReader(msg.Body)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notify command: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNtfy_Send(t *testing.T) {
+	var title, auth, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		title, auth = r.Header.Get("Title"), r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		if r.URL.Path == "/missing" {
+			http.Error(w, "topic not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	n := &Ntfy{URL: server.URL + "/vault", Token: "tk_123"}
+	if err := n.Send(context.Background(), Message{Title: "Inbox review", Body: "12 notes older than 7 days in Inbox"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if title != "Inbox review" || auth != "Bearer tk_123" || body != "12 notes older than 7 days in Inbox" {
+		t.Errorf("unexpected request: title %q, auth %q, body %q", title, auth, body)
+	}
+
+	n.URL = server.URL + "/missing"
+	if err := n.Send(context.Background(), Message{}); err == nil || !strings.Contains(err.Error(), "topic not found") {
+		t.Errorf("expected error with server message, got: %v", err)
+	}
+}
+
+func TestDesktop_Send(t *testing.T) {
+	var got []string
+	d := &Desktop{run: func(ctx context.Context, name string, args ...string) error {
+		got = append([]string{name}, args...)
+		return nil
+	}}
+	if err := d.Send(context.Background(), Message{Title: "Inbox review", Body: "31 notes in Inbox"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(got) != 4 || got[0] != "notify-send" || got[2] != "Inbox review" || got[3] != "31 notes in Inbox" {
+		t.Errorf("unexpected command: %q", got)
+	}
+}
+
+func TestCommand_Send(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	c := &Command{Args: []string{"sh", "-c", `printf '%s|' "$NOTA_NOTIFY_TITLE" > "$0"; cat >> "$0"`, out}}
+	if err := c.Send(context.Background(), Message{Title: "Inbox review", Body: "31 notes in Inbox"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	data, _ := os.ReadFile(out)
+	if string(data) != "Inbox review|31 notes in Inbox" {
+		t.Errorf("unexpected hook input: %q", data)
+	}
+
+	failing := &Command{Args: []string{"sh", "-c", "echo no display >&2; exit 1"}}
+	if err := failing.Send(context.Background(), Message{}); err == nil || !strings.Contains(err.Error(), "no display") {
+		t.Errorf("expected error with command output, got: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package output
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/internal/golden"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
+)
+
+// TestWriter_Write_Golden compares written notes with testdata/golden; run
+// with -update to rewrite them.
+func TestWriter_Write_Golden(t *testing.T) {
+	text := "Remind me to call the dentist on Monday.\n\nAlso buy milk."
+	base := transcribe.OutputOptions{
+		SourceFile: "/sync/phone/memo.m4a",
+		Timestamp:  time.Date(2026, 1, 22, 9, 30, 0, 0, time.UTC),
+		Processing: &transcribe.ProcessingInfo{
+			Provider:       "whisper-asr",
+			Model:          "base",
+			Language:       "en",
+			Duration:       83 * time.Second,
+			ProcessingTime: 12500 * time.Millisecond,
+			Version:        "1.2.3",
+		},
+	}
+
+	tests := []struct {
+		golden string
+		opts   func(*transcribe.OutputOptions)
+	}{
+		{"plain.md", func(o *transcribe.OutputOptions) {}},
+		{"plain-title.md", func(o *transcribe.OutputOptions) { o.Title = "Call the dentist" }},
+		{"plain-de.md", func(o *transcribe.OutputOptions) { o.Locale = "de" }},
+		{"plain-footer.md", func(o *transcribe.OutputOptions) { o.Footer = true }},
+		{"plain.txt", func(o *transcribe.OutputOptions) { o.Format = writer.FormatText }},
+		{"plain.org", func(o *transcribe.OutputOptions) { o.Format = writer.FormatOrg }},
+		{"template-frontmatter.md", func(o *transcribe.OutputOptions) {
+			o.TemplatePath = filepath.Join("testdata", "templates", "meeting.md")
+		}},
+		{"template-plain.md", func(o *transcribe.OutputOptions) {
+			o.TemplatePath = filepath.Join("testdata", "templates", "plain.md")
+			o.Footer = true
+		}},
+	}
+
+	w := NewWriter()
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			opts := base
+			opts.OutputDir = t.TempDir()
+			tt.opts(&opts)
+			path, err := w.Write(context.Background(), text, opts)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			note, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden.Assert(t, tt.golden, string(note))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Package output provides file writing functionality for transcription results.
+package output
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/notes"
+)
+
+// Compile-time check that Writer implements transcribe.OutputWriter.
+var _ transcribe.OutputWriter = (*Writer)(nil)
+
+// Writer implements transcribe.OutputWriter for saving transcriptions to markdown files.
+type Writer struct {
+	clock clock.Clock
+}
+
+// Option configures a Writer.
+type Option func(*Writer)
+
+// WithClock sets the clock used to name and date notes written without a
+// timestamp.
+func WithClock(c clock.Clock) Option {
+	return func(w *Writer) {
+		w.clock = clock.OrReal(c)
+	}
+}
+
+// NewWriter creates a new OutputWriter.
+func NewWriter(opts ...Option) *Writer {
+	w := &Writer{clock: clock.Real{}}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Write saves the transcription text and returns the path to the created file.
+// If opts.TemplatePath is set, the template is read and transcription is appended.
+// Otherwise, a plain note with the transcription is written in opts.Format.
+func (w *Writer) Write(ctx context.Context, text string, opts transcribe.OutputOptions) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	default:
+	}
+
+	if opts.OutputDir == "" {
+		return "", fmt.Errorf("output directory is required")
+	}
+
+	return notes.CreateNote(w.note(text, opts))
+}
+
+// note describes the note for the transcription: named
+// YYYY-MM-DD-HHmm-voice-note, or YYYY-MM-DD-HHmm-<title slug> when
+// opts.Title is set, with the extension of opts.Format. With a template the
+// transcription is appended to it; otherwise the note is a plain document.
+// With opts.Footer, the processing stats follow the transcription.
+func (w *Writer) note(text string, opts transcribe.OutputOptions) notes.Options {
+	ts := opts.Timestamp
+	if ts.IsZero() {
+		ts = w.clock.Now()
+	}
+	name := ts.Format("2006-01-02-1504") + "-voice-note"
+	if slug := writer.Slug(opts.Title); slug != "" {
+		name = ts.Format("2006-01-02-1504") + "-" + slug
+	}
+
+	if opts.Footer {
+		text = writer.AppendFooter(text, opts.Processing, opts.Format)
+	}
+	body := text
+	if opts.TemplatePath == "" {
+		body = w.generatePlain(text, opts)
+	}
+
+	return notes.Options{
+		Dir:          opts.OutputDir,
+		Name:         name,
+		Ext:          opts.Format.Extension(),
+		TemplatePath: opts.TemplatePath,
+		Body:         body,
+	}
+}
+
+// generatePlain creates a simple document with the transcription in
+// opts.Format. Headings and the date format follow opts.Locale, falling back
+// to English for unsupported locales.
+func (w *Writer) generatePlain(text string, opts transcribe.OutputOptions) string {
+	ts := opts.Timestamp
+	if ts.IsZero() {
+		ts = w.clock.Now()
+	}
+
+	locale, ok := writer.LookupLocale(opts.Locale)
+	if !ok {
+		locale, _ = writer.LookupLocale(writer.DefaultLocale)
+	}
+
+	heading := locale.Title
+	if opts.Title != "" {
+		heading = opts.Title
+	}
+
+	format := opts.Format
+	var sb strings.Builder
+	sb.WriteString(format.Heading(1, heading) + "\n")
+	sb.WriteString(fmt.Sprintf("%s %s\n\n", format.Label(locale.Date), ts.Format(locale.DateLayout)))
+
+	if opts.SourceFile != "" {
+		sb.WriteString(fmt.Sprintf("%s %s\n\n", format.Label(locale.Source), filepath.Base(opts.SourceFile)))
+	}
+
+	sb.WriteString(format.Heading(2, locale.Transcription) + "\n")
+	sb.WriteString(text)
+	sb.WriteString("\n")
+
+	return sb.String()
+}
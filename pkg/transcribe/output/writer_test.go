@@ -8,7 +8,9 @@ import (
 	"testing"
 	"time"
 
-	"github.com/TechnicallyShaun/nota-orbis/internal/transcribe"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
 )
 
 func TestWriter_Write_PlainMarkdown(t *testing.T) {
@@ -57,6 +59,98 @@ func TestWriter_Write_PlainMarkdown(t *testing.T) {
 	}
 }
 
+func TestWriter_Write_PlainMarkdownLocale(t *testing.T) {
+	tmpDir := t.TempDir()
+	writer := NewWriter()
+
+	opts := transcribe.OutputOptions{
+		OutputDir:  tmpDir,
+		SourceFile: "/path/to/audio.m4a",
+		Timestamp:  time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC),
+		Locale:     "de",
+	}
+
+	path, err := writer.Write(context.Background(), "Milch kaufen.", opts)
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+
+	contentStr := string(content)
+	for _, want := range []string{"# Sprachnotiz", "**Datum:** 15.03.2024 14:30", "**Quelle:** audio.m4a", "## Transkription"} {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, contentStr)
+		}
+	}
+	if strings.Contains(contentStr, "Voice Note") {
+		t.Errorf("expected no English header, got:\n%s", contentStr)
+	}
+}
+
+func TestWriter_Write_PlainFormats(t *testing.T) {
+	tests := []struct {
+		format   writer.Format
+		filename string
+		expected string
+	}{
+		{writer.FormatText, "2024-03-15-1430-voice-note.txt", "Voice Note\n==========\n\nDate: 2024-03-15 14:30\n\nSource: audio.m4a\n\nTranscription\n-------------\n\nBuy milk.\n"},
+		{writer.FormatOrg, "2024-03-15-1430-voice-note.org", "* Voice Note\n\n*Date:* 2024-03-15 14:30\n\n*Source:* audio.m4a\n\n** Transcription\n\nBuy milk.\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			path, err := NewWriter().Write(context.Background(), "Buy milk.", transcribe.OutputOptions{
+				OutputDir:  t.TempDir(),
+				SourceFile: "/path/to/audio.m4a",
+				Timestamp:  time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC),
+				Format:     tt.format,
+			})
+			if err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			if filepath.Base(path) != tt.filename {
+				t.Errorf("unexpected filename: got %s, want %s", filepath.Base(path), tt.filename)
+			}
+			content, _ := os.ReadFile(path)
+			if string(content) != tt.expected {
+				t.Errorf("unexpected content:\n%s\nwant:\n%s", content, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriter_Write_Title(t *testing.T) {
+	tmpDir := t.TempDir()
+	writer := NewWriter()
+
+	opts := transcribe.OutputOptions{
+		OutputDir:  tmpDir,
+		SourceFile: "/path/to/audio.m4a",
+		Timestamp:  time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC),
+		Title:      "Buy milk",
+	}
+
+	path, err := writer.Write(context.Background(), "Buy milk.", opts)
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if filepath.Base(path) != "2024-03-15-1430-buy-milk.md" {
+		t.Errorf("expected filename from title, got %s", filepath.Base(path))
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "# Buy milk\n") {
+		t.Errorf("expected title heading, got:\n%s", content)
+	}
+}
+
 func TestWriter_Write_WithTemplate(t *testing.T) {
 	tmpDir := t.TempDir()
 	writer := NewWriter()
@@ -247,37 +341,22 @@ func TestWriter_Write_ContextCancellation(t *testing.T) {
 
 func TestWriter_Write_DefaultTimestamp(t *testing.T) {
 	tmpDir := t.TempDir()
-	writer := NewWriter()
+	now := time.Date(2026, 1, 22, 23, 59, 30, 0, time.Local)
+	writer := NewWriter(WithClock(clock.NewFake(now)))
 
 	opts := transcribe.OutputOptions{
 		OutputDir: tmpDir,
-		// Timestamp is zero, should use current time
+		// Timestamp is zero, should use the clock's time
 	}
 
-	before := time.Now()
 	path, err := writer.Write(context.Background(), "Test.", opts)
 	if err != nil {
 		t.Fatalf("Write failed: %v", err)
 	}
-	after := time.Now()
-
-	// Extract date from filename
-	filename := filepath.Base(path)
-	// Format: YYYY-MM-DD-HHmm-voice-note.md
-	parts := strings.Split(filename, "-voice-note")
-	if len(parts) != 2 {
-		t.Fatalf("unexpected filename format: %s", filename)
-	}
-
-	dateStr := parts[0]
-	parsed, err := time.Parse("2006-01-02-1504", dateStr)
-	if err != nil {
-		t.Fatalf("failed to parse date from filename: %v", err)
-	}
 
-	// Verify the timestamp is within the expected range
-	if parsed.Before(before.Truncate(time.Minute)) || parsed.After(after.Add(time.Minute)) {
-		t.Errorf("filename timestamp %v not in expected range [%v, %v]", parsed, before, after)
+	expected := "2026-01-22-2359-voice-note.md"
+	if filepath.Base(path) != expected {
+		t.Errorf("expected %s, got %s", expected, filepath.Base(path))
 	}
 }
 
@@ -0,0 +1,197 @@
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// DefaultSplitChunkMinutes is the length of the pieces oversized files are
+// split into when split_chunk_minutes is not set.
+const DefaultSplitChunkMinutes = 10
+
+// ErrFileTooLarge is recorded for files over max_file_size_mb that were not
+// transcribed.
+var ErrFileTooLarge = errors.New("file too large")
+
+// TooLargeAction selects what happens to files over max_file_size_mb.
+type TooLargeAction string
+
+// Actions for oversized files.
+const (
+	// TooLargeSkip leaves the file where it is and only logs it.
+	TooLargeSkip TooLargeAction = "skip"
+	// TooLargeStub writes a note saying the recording was too large and
+	// where it is stored, then archives it.
+	TooLargeStub TooLargeAction = "stub"
+	// TooLargeSplit cuts the file into split_chunk_minutes pieces with
+	// ffmpeg, transcribes each and writes one note.
+	TooLargeSplit TooLargeAction = "split"
+)
+
+// Valid reports whether a is a known action.
+func (a TooLargeAction) Valid() bool {
+	switch a {
+	case TooLargeSkip, TooLargeStub, TooLargeSplit:
+		return true
+	}
+	return false
+}
+
+// maxFileSize returns max_file_size_mb in bytes.
+func (s *Service) maxFileSize() int64 {
+	return int64(s.config.MaxFileSizeMB) * 1024 * 1024
+}
+
+// skipTooLarge records event's file as skipped for its size.
+func (s *Service) skipTooLarge(fileLogger Logger, event FileEvent, startTime time.Time) error {
+	err := fmt.Errorf("%w: %d bytes", ErrFileTooLarge, event.Size)
+	return s.failFile(fileLogger, "file too large, skipping", event,
+		history.Record{Category: history.CategoryTooLarge}, err, startTime,
+		logging.Int64("max_size", s.maxFileSize()),
+	)
+}
+
+// writeStub writes a note saying event's file was too large to transcribe
+// and where the audio is kept, then archives the file if archive is set. The
+// file is still recorded as failed, with the stub as its output.
+func (s *Service) writeStub(ctx context.Context, fileLogger Logger, event FileEvent, archive bool, startTime time.Time) error {
+	writeOpts, _ := s.outputOptions(event, nil)
+	archivePath := ""
+	if archive {
+		archivePath = s.planArchive(event.Path)
+	}
+	writeOpts.Processing = s.processingInfo(event.Path, archivePath, nil, time.Since(startTime))
+
+	storedAt := writeOpts.Processing.SourcePath
+	if archivePath != "" {
+		storedAt = archivePath
+	} else if archive {
+		storedAt = s.config.ArchiveDir
+	}
+	text := fmt.Sprintf("Recording too large to transcribe: %s, %d MB (limit %d MB). Stored at %s",
+		filepath.Base(event.Path), event.Size/(1024*1024), s.config.MaxFileSizeMB, storedAt)
+
+	outputPath, err := s.writer.Write(ctx, text, writeOpts)
+	if err != nil {
+		return s.failFile(fileLogger, "failed to write output", event,
+			history.Record{Category: history.CategoryWrite}, err, startTime)
+	}
+	if archive {
+		if err := s.archiveFile(ctx, event.Path, archivePath); err != nil {
+			return s.failFile(fileLogger, "failed to archive file", event,
+				history.Record{Output: outputPath, Category: history.CategoryArchive}, err, startTime)
+		}
+	}
+
+	err = fmt.Errorf("%w: %d bytes", ErrFileTooLarge, event.Size)
+	return s.failFile(fileLogger, "file too large, wrote stub note", event,
+		history.Record{Output: outputPath, Category: history.CategoryTooLarge}, err, startTime,
+		logging.String("output", outputPath),
+	)
+}
+
+// transcribeSplit transcribes an oversized file piece by piece and joins the
+// results, shifting each piece's segments by the audio before it. The hooks
+// run around the whole file.
+func (s *Service) transcribeSplit(ctx context.Context, fileLogger Logger, path string) (*TranscriptionResult, error) {
+	return s.withHooks(ctx, fileLogger, path, func(audio string) (*TranscriptionResult, error) {
+		return s.transcribeChunks(ctx, fileLogger, path, audio)
+	})
+}
+
+// transcribeChunks splits audio, the recording at path or a replacement for
+// it, and transcribes the pieces.
+func (s *Service) transcribeChunks(ctx context.Context, fileLogger Logger, path, audio string) (*TranscriptionResult, error) {
+	dir, err := os.MkdirTemp("", "nota-split-")
+	if err != nil {
+		return nil, fmt.Errorf("%w: split into chunks: %w", ErrFileTooLarge, err)
+	}
+	defer os.RemoveAll(dir)
+
+	chunkLength := time.Duration(s.config.SplitChunkMinutes) * time.Minute
+	chunks, err := s.splitAudio(ctx, audio, dir, chunkLength)
+	if err == nil && len(chunks) == 0 {
+		err = errors.New("no chunks produced")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: split into chunks: %w", ErrFileTooLarge, err)
+	}
+	fileLogger.Info("file too large, transcribing in chunks",
+		logging.String("path", path),
+		logging.Int("chunks", len(chunks)),
+	)
+
+	// Chunks are named after the file, so route by the file itself
+	transcribeOpts := s.transcribeOptions(path)
+	merged := &TranscriptionResult{}
+	var texts []string
+	var offset float64
+	durationKnown := true
+	for i, chunk := range chunks {
+		result, err := s.transcribeAudio(ctx, fileLogger, chunk, transcribeOpts)
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+		if text := strings.TrimSpace(result.Text); text != "" {
+			texts = append(texts, text)
+		}
+		if merged.Language == "" {
+			merged.Language = result.Language
+		}
+		// A fallback may have taken some of the chunks
+		if merged.Provider == "" {
+			merged.Provider = result.Provider
+		} else if !slices.Contains(strings.Split(merged.Provider, ", "), result.Provider) {
+			merged.Provider += ", " + result.Provider
+		}
+		for _, seg := range result.Segments {
+			seg.Start += offset
+			seg.End += offset
+			merged.Segments = append(merged.Segments, seg)
+		}
+
+		length := result.Duration
+		if length <= 0 {
+			length = chunkLength.Seconds()
+			durationKnown = false
+		}
+		offset += length
+	}
+
+	merged.Text = strings.Join(texts, "\n\n")
+	if durationKnown {
+		merged.Duration = offset
+	}
+	return merged, nil
+}
+
+// ffmpegSplit cuts path into pieces of at most chunk length in dir without
+// re-encoding, and returns them in order.
+func ffmpegSplit(ctx context.Context, path, dir string, chunk time.Duration) ([]string, error) {
+	ext := filepath.Ext(path)
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-hide_banner", "-loglevel", "error",
+		"-i", path,
+		"-f", "segment",
+		"-segment_time", strconv.Itoa(int(chunk.Seconds())),
+		"-reset_timestamps", "1",
+		"-c", "copy",
+		filepath.Join(dir, "chunk-%03d"+ext),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("ffmpeg: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return filepath.Glob(filepath.Join(dir, "chunk-*"+ext))
+}
@@ -0,0 +1,177 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+// chunkClient transcribes each chunk as its file name, ten minutes long
+// with one segment at its start.
+type chunkClient struct{}
+
+func (chunkClient) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	name := filepath.Base(audioPath)
+	return &TranscriptionResult{
+		Text:     name,
+		Language: "en",
+		Duration: 600,
+		Segments: []Segment{{Start: 0, End: 5, Text: name}},
+	}, nil
+}
+
+// writeOversized creates a file of 2 MB, over a max_file_size_mb of 1.
+func writeOversized(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, make([]byte, 2*1024*1024), 0644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return path
+}
+
+func TestProcessFile_TooLargeStub(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.MaxFileSizeMB = 1
+	cfg.TooLargeAction = TooLargeStub
+	cfg.ArchiveDir = filepath.Join(t.TempDir(), "archive")
+
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{},
+		Stabilizer: fakeStabilizer{},
+		Client:     fakeClient{},
+		Logger:     &recordingLogger{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	audioPath := writeOversized(t, cfg.WatchDir, "meeting.m4a")
+	err = svc.processFile(context.Background(), FileEvent{Path: audioPath, Size: 2 * 1024 * 1024}, processOptions{stabilize: true, archive: true})
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("expected ErrFileTooLarge, got: %v", err)
+	}
+
+	if _, err := os.Stat(audioPath); !os.IsNotExist(err) {
+		t.Error("expected the recording to be archived")
+	}
+
+	p, _ := history.DefaultPath()
+	records, _ := history.New(p).Load(time.Time{})
+	if len(records) != 1 || records[0].Category != history.CategoryTooLarge || records[0].Output == "" {
+		t.Fatalf("expected a too_large record with the stub as output, got %+v", records)
+	}
+
+	note, err := os.ReadFile(records[0].Output)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	want := "Recording too large to transcribe: meeting.m4a, 2 MB (limit 1 MB). Stored at " + cfg.ArchiveDir
+	if !strings.Contains(string(note), want) {
+		t.Errorf("expected stub note to contain %q, got:\n%s", want, note)
+	}
+}
+
+func TestProcessFile_TooLargeSplit(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.MaxFileSizeMB = 1
+	cfg.TooLargeAction = TooLargeSplit
+
+	w := &recordingWriter{}
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{},
+		Stabilizer: fakeStabilizer{},
+		Client:     chunkClient{},
+		Writer:     w,
+		Archiver:   &countingArchiver{},
+		Logger:     &recordingLogger{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	var gotChunk time.Duration
+	svc.splitAudio = func(ctx context.Context, path, dir string, chunk time.Duration) ([]string, error) {
+		gotChunk = chunk
+		var chunks []string
+		for _, name := range []string{"chunk-000.m4a", "chunk-001.m4a"} {
+			p := filepath.Join(dir, name)
+			os.WriteFile(p, []byte("audio"), 0644)
+			chunks = append(chunks, p)
+		}
+		return chunks, nil
+	}
+
+	audioPath := writeOversized(t, cfg.WatchDir, "lecture.m4a")
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath, Size: 5}, processOptions{stabilize: true, archive: true}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if gotChunk != time.Duration(DefaultSplitChunkMinutes)*time.Minute {
+		t.Errorf("expected %d minute chunks, got %s", DefaultSplitChunkMinutes, gotChunk)
+	}
+	if len(w.texts) != 1 || w.texts[0] != "chunk-000.m4a\n\nchunk-001.m4a" {
+		t.Errorf("expected one note joining both chunks, got %q", w.texts)
+	}
+}
+
+func TestTranscribeSplit_OffsetsSegments(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	svc, err := NewServiceWith(cfg, Options{Watcher: &fakeWatcher{}, Client: chunkClient{}, Logger: &recordingLogger{}})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	svc.splitAudio = func(ctx context.Context, path, dir string, chunk time.Duration) ([]string, error) {
+		return []string{filepath.Join(dir, "a.m4a"), filepath.Join(dir, "b.m4a"), filepath.Join(dir, "c.m4a")}, nil
+	}
+
+	result, err := svc.transcribeSplit(context.Background(), &recordingLogger{}, "/in/long.m4a")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.Duration != 1800 {
+		t.Errorf("expected 1800s, got %v", result.Duration)
+	}
+	if len(result.Segments) != 3 || result.Segments[2].Start != 1200 || result.Segments[2].End != 1205 {
+		t.Errorf("expected segments shifted by the chunks before them, got %+v", result.Segments)
+	}
+}
+
+func TestTranscribeSplit_SplitFailure(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	svc, err := NewServiceWith(cfg, Options{Watcher: &fakeWatcher{}, Client: chunkClient{}, Logger: &recordingLogger{}})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	svc.splitAudio = func(ctx context.Context, path, dir string, chunk time.Duration) ([]string, error) {
+		return nil, errors.New("ffmpeg: executable file not found")
+	}
+
+	_, err = svc.transcribeSplit(context.Background(), &recordingLogger{}, "/in/long.m4a")
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("expected ErrFileTooLarge, got: %v", err)
+	}
+	if got := classifyFailure(history.CategoryTranscription, err); got != history.CategoryTooLarge {
+		t.Errorf("expected too_large category, got %q", got)
+	}
+}
+
+func TestConfig_InvalidTooLargeAction(t *testing.T) {
+	cfg := &Config{WatchDir: "/in", APIURL: "http://localhost:9000", OutputDir: "/out", TooLargeAction: "truncate"}
+	cfg.ApplyDefaults()
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidTooLargeAction) {
+		t.Errorf("expected ErrInvalidTooLargeAction, got: %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package transcribe
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/ignore"
+)
+
+// ErrUnsafePath is recorded for watched files that were not processed
+// because they are symlinks, with skip_symlinks set, or resolve to a
+// location outside the directory they were found in.
+var ErrUnsafePath = errors.New("unsafe path")
+
+// checkPath verifies that a file found by the watchers stays where it was
+// found: after resolving symlinks it must be a regular file under a watched
+// directory or the remote source's download directory. It is run before
+// stabilizing and again before uploading, so a link swapped in between is
+// caught too.
+func (s *Service) checkPath(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 && s.config.SkipSymlinks {
+		return fmt.Errorf("%w: %s is a symlink", ErrUnsafePath, path)
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+	if info, err = os.Stat(resolved); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%w: %s is not a regular file", ErrUnsafePath, path)
+	}
+
+	for _, root := range s.watchRoots() {
+		if !within(root, filepath.Clean(path)) {
+			continue
+		}
+		// The watched directory itself may be a symlink, e.g. to a mount
+		resolvedRoot, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			return err
+		}
+		if within(resolvedRoot, resolved) {
+			return nil
+		}
+		return fmt.Errorf("%w: %s resolves to %s, outside %s", ErrUnsafePath, path, resolved, root)
+	}
+	return fmt.Errorf("%w: %s is not in a watched directory", ErrUnsafePath, path)
+}
+
+// rejectUnsafePath records a failure and returns an error wrapping
+// ErrUnsafePath when checkPath rejects the event's file. Other errors, such
+// as the file having been removed, are left for the pipeline's own steps to
+// report.
+func (s *Service) rejectUnsafePath(fileLogger Logger, event FileEvent, startTime time.Time) error {
+	err := s.checkPath(event.Path)
+	if !errors.Is(err, ErrUnsafePath) {
+		return nil
+	}
+	return s.failFile(fileLogger, "unsafe path, skipping", event,
+		history.Record{Category: history.CategoryUnsafePath}, err, startTime)
+}
+
+// watchRoots returns the directories watched files may come from.
+func (s *Service) watchRoots() []string {
+	var roots []string
+	for _, wd := range s.config.Watches() {
+		roots = append(roots, filepath.Clean(wd.Path))
+	}
+	if s.config.Source != nil {
+		if dir, err := s.config.Source.downloadDir(); err == nil {
+			roots = append(roots, filepath.Clean(dir))
+		}
+	}
+	return roots
+}
+
+// WatchFor returns the index in watches of the directory path was found in,
+// or -1 when it is in none. Of nested directories the deepest is chosen.
+// Directories are compared as written and after resolving symlinks, so a
+// file recorded under a directory's real location is still matched.
+func WatchFor(watches []WatchDirConfig, path string) int {
+	path = filepath.Clean(path)
+	resolved, err := resolveExisting(path)
+	if err != nil {
+		resolved = path
+	}
+
+	match, depth := -1, -1
+	for i, wd := range watches {
+		root := filepath.Clean(wd.Path)
+		found := within(root, path)
+		if !found {
+			if resolvedRoot, err := filepath.EvalSymlinks(root); err == nil {
+				found = within(resolvedRoot, resolved)
+			}
+		}
+		if found && len(root) > depth {
+			match, depth = i, len(root)
+		}
+	}
+	return match
+}
+
+// resolveExisting resolves the symlinks of path's deepest existing
+// ancestor, so paths that do not exist yet, or no longer do, are resolved
+// too.
+func resolveExisting(path string) (string, error) {
+	existing, rest := path, ""
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return "", err
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+}
+
+// ignoredPath reports whether the .notaignore of the watched directory path
+// was found in lists it. An unreadable ignore file is logged and ignores
+// nothing.
+func (s *Service) ignoredPath(path string) bool {
+	path = filepath.Clean(path)
+	for _, wd := range s.config.Watches() {
+		root := filepath.Clean(wd.Path)
+		if !within(root, path) {
+			continue
+		}
+		ignored, err := ignore.Load(root)
+		if err != nil {
+			s.logger.Error("failed to read ignore file", err, logging.String("watch_dir", root))
+			return false
+		}
+		rel, _ := filepath.Rel(root, path)
+		return ignored.Match(rel, false)
+	}
+	return false
+}
+
+// within reports whether path is dir or lies below it. Both must be clean.
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
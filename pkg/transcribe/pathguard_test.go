@@ -0,0 +1,193 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+func TestService_CheckPath(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	watchDir := t.TempDir()
+	outside := t.TempDir()
+	cfg.WatchDir = watchDir
+
+	// A watched directory reached through a symlink, as with a mount
+	linkedDir := filepath.Join(t.TempDir(), "phone")
+	if err := os.Symlink(outside, linkedDir); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	cfg.WatchDirs = []WatchDirConfig{{Path: linkedDir}}
+
+	write := func(path string) string {
+		if err := os.WriteFile(path, []byte("audio"), 0644); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		return path
+	}
+	link := func(target, path string) string {
+		if err := os.Symlink(target, path); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		return path
+	}
+
+	memo := write(filepath.Join(watchDir, "memo.m4a"))
+	secret := write(filepath.Join(outside, "secret.m4a"))
+	inside := link(memo, filepath.Join(watchDir, "alias.m4a"))
+	escaping := link(secret, filepath.Join(watchDir, "escape.m4a"))
+	relative := link("../"+filepath.Base(outside)+"/secret.m4a", filepath.Join(watchDir, "relative.m4a"))
+	dir := link(outside, filepath.Join(watchDir, "folder.m4a"))
+
+	tests := []struct {
+		name         string
+		path         string
+		skipSymlinks bool
+		wantErr      bool
+	}{
+		{"regular file", memo, false, false},
+		{"symlink inside watch dir", inside, false, false},
+		{"symlink leaving watch dir", escaping, false, true},
+		{"relative symlink leaving watch dir", relative, false, true},
+		{"symlink to a directory", dir, false, true},
+		{"symlink with skip_symlinks", inside, true, true},
+		{"file outside watched directories", secret, false, true},
+		{"file in symlinked watch dir", filepath.Join(linkedDir, "secret.m4a"), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.SkipSymlinks = tt.skipSymlinks
+			svc := &Service{config: cfg}
+			err := svc.checkPath(tt.path)
+			if tt.wantErr && !errors.Is(err, ErrUnsafePath) {
+				t.Errorf("expected ErrUnsafePath, got: %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestHandleFileEvent_RejectsEscapingSymlink(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+
+	tc := &gatedClient{release: make(chan struct{})}
+	close(tc.release)
+	arch := &countingArchiver{}
+	svc, err := NewBuilder(cfg).
+		WithWatcher(&fakeWatcher{}).
+		WithStabilizer(fakeStabilizer{}).
+		WithClient(tc).
+		WithWriter(&recordingWriter{}).
+		WithArchiver(arch).
+		Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	secret := filepath.Join(t.TempDir(), "secret.m4a")
+	os.WriteFile(secret, []byte("audio"), 0644)
+	audioPath := filepath.Join(cfg.WatchDir, "memo.m4a")
+	if err := os.Symlink(secret, audioPath); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	svc.handleFileEvent(context.Background(), FileEvent{Path: audioPath, Size: 5})
+	svc.wg.Wait()
+
+	if tc.calls.Load() != 0 {
+		t.Error("expected the escaping symlink not to be uploaded")
+	}
+	if arch.count.Load() != 0 {
+		t.Error("expected the escaping symlink not to be archived")
+	}
+
+	historyPath, _ := history.DefaultPath()
+	records, _ := history.New(historyPath).Load(time.Time{})
+	if len(records) != 1 || records[0].Category != history.CategoryUnsafePath {
+		t.Errorf("expected an unsafe_path failure in history, got: %+v", records)
+	}
+}
+
+func TestHandleFileEvent_IgnoresNotaignoreEntries(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	os.WriteFile(filepath.Join(cfg.WatchDir, ".notaignore"), []byte("drafts/\n*.part.m4a\n"), 0644)
+
+	tc := &gatedClient{release: make(chan struct{})}
+	close(tc.release)
+	svc, err := NewBuilder(cfg).
+		WithWatcher(&fakeWatcher{}).
+		WithStabilizer(fakeStabilizer{}).
+		WithClient(tc).
+		WithWriter(&recordingWriter{}).
+		WithArchiver(&countingArchiver{}).
+		Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	for _, name := range []string{"memo.part.m4a", "drafts/memo.m4a", "memo.m4a"} {
+		path := filepath.Join(cfg.WatchDir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte("audio"), 0644)
+		svc.handleFileEvent(context.Background(), FileEvent{Path: path, Size: 5})
+	}
+	svc.wg.Wait()
+
+	if tc.calls.Load() != 1 {
+		t.Errorf("expected only memo.m4a to be uploaded, got %d uploads", tc.calls.Load())
+	}
+	historyPath, _ := history.DefaultPath()
+	if records, _ := history.New(historyPath).Load(time.Time{}); len(records) != 1 {
+		t.Errorf("expected ignored files to leave no history, got: %+v", records)
+	}
+}
+
+func TestWatchFor(t *testing.T) {
+	root := t.TempDir()
+	phone := filepath.Join(root, "phone")
+	calls := filepath.Join(phone, "calls")
+	os.MkdirAll(calls, 0755)
+	linked := filepath.Join(t.TempDir(), "tablet")
+	if err := os.Symlink(phone, linked); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	watches := []WatchDirConfig{{Path: phone + "/"}, {Path: calls}, {Path: filepath.Join(root, "missing")}}
+	tests := []struct {
+		path string
+		want int
+	}{
+		{filepath.Join(phone, "memo.m4a"), 0},
+		{filepath.Join(phone, "sub", "memo.m4a"), 0},
+		{filepath.Join(calls, "memo.m4a"), 1},
+		{filepath.Join(root, "missing", "memo.m4a"), 2},
+		{filepath.Join(root, "phone2", "memo.m4a"), -1},
+		{filepath.Join(phone, "..", "other", "memo.m4a"), -1},
+	}
+	for _, tt := range tests {
+		if got := WatchFor(watches, tt.path); got != tt.want {
+			t.Errorf("WatchFor(%s) = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+
+	// A directory watched through a symlink matches files recorded under
+	// its real location, and the other way around
+	if got := WatchFor([]WatchDirConfig{{Path: linked}}, filepath.Join(phone, "memo.m4a")); got != 0 {
+		t.Errorf("expected the linked directory matched, got %d", got)
+	}
+	if got := WatchFor([]WatchDirConfig{{Path: phone}}, filepath.Join(linked, "memo.m4a")); got != 0 {
+		t.Errorf("expected the real directory matched, got %d", got)
+	}
+}
@@ -0,0 +1,278 @@
+// Package pidfile provides PID file management for daemon lifecycle.
+package pidfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/dirs"
+)
+
+// Common errors
+var (
+	ErrNoPIDFile       = errors.New("no PID file found")
+	ErrNoStateFile     = errors.New("no state file found")
+	ErrInvalidPID      = errors.New("invalid PID in file")
+	ErrProcessNotFound = errors.New("process not found")
+	ErrAlreadyRunning  = errors.New("transcription service is already running")
+)
+
+const (
+	pidFileName = "transcribe.pid"
+	dirPerm     = 0755
+	filePerm    = 0644
+)
+
+// Path returns the path to the PID file in the state directory
+// ($XDG_STATE_HOME/nota/transcribe.pid or ~/.nota/transcribe.pid)
+func Path() (string, error) {
+	dir, err := dirs.State()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, pidFileName), nil
+}
+
+// Write creates the PID file with the given process ID.
+// Creates parent directories if needed.
+func Write(pid int) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	// Create parent directory if needed
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	// Write PID to file
+	content := strconv.Itoa(pid) + "\n"
+	if err := os.WriteFile(path, []byte(content), filePerm); err != nil {
+		return fmt.Errorf("write PID file: %w", err)
+	}
+
+	return nil
+}
+
+// Lock is an exclusively held PID file. The flock is held for as long as the
+// owning process keeps the file open, so the kernel releases it on exit even
+// if the process crashes before calling Release.
+type Lock struct {
+	file *os.File
+	path string
+}
+
+// Acquire atomically claims the PID file for the given process ID.
+// The file is opened and locked with a non-blocking exclusive flock, so exactly
+// one process can hold it at a time. A stale file left behind by a dead process
+// is not locked and is simply taken over.
+// Returns an error wrapping ErrAlreadyRunning if another process holds the lock.
+func Acquire(pid int) (*Lock, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), dirPerm); err != nil {
+		return nil, fmt.Errorf("create directory: %w", err)
+	}
+
+	for {
+		file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, filePerm)
+		if err != nil {
+			return nil, fmt.Errorf("open PID file: %w", err)
+		}
+
+		if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+			file.Close()
+			if errors.Is(err, syscall.EWOULDBLOCK) {
+				if owner, readErr := Read(); readErr == nil {
+					return nil, fmt.Errorf("%w (PID %d)", ErrAlreadyRunning, owner)
+				}
+				return nil, ErrAlreadyRunning
+			}
+			return nil, fmt.Errorf("lock PID file: %w", err)
+		}
+
+		// The previous owner may have removed the file between our open and
+		// flock, leaving us holding a lock on an unlinked inode. Retry until
+		// the locked file is the one at path.
+		if !sameFile(file, path) {
+			file.Close()
+			continue
+		}
+
+		if err := file.Truncate(0); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("truncate PID file: %w", err)
+		}
+		if _, err := file.WriteAt([]byte(strconv.Itoa(pid)+"\n"), 0); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("write PID file: %w", err)
+		}
+		if err := file.Sync(); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("sync PID file: %w", err)
+		}
+
+		return &Lock{file: file, path: path}, nil
+	}
+}
+
+// Release removes the PID file and drops the lock.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+
+	// Only remove the file if it is still ours; stop may already have removed it.
+	var removeErr error
+	if sameFile(l.file, l.path) {
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			removeErr = fmt.Errorf("remove PID file: %w", err)
+		}
+	}
+
+	closeErr := l.file.Close()
+	l.file = nil
+	if removeErr != nil {
+		return removeErr
+	}
+	return closeErr
+}
+
+// sameFile reports whether the open file is the file currently at path.
+func sameFile(file *os.File, path string) bool {
+	openInfo, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	pathInfo, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(openInfo, pathInfo)
+}
+
+// Read reads the PID from the PID file.
+// Returns ErrNoPIDFile if the file doesn't exist.
+// Returns ErrInvalidPID if the file contains invalid data.
+func Read() (int, error) {
+	path, err := Path()
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, ErrNoPIDFile
+		}
+		return 0, fmt.Errorf("read PID file: %w", err)
+	}
+
+	pidStr := strings.TrimSpace(string(data))
+	pid, err := strconv.Atoi(pidStr)
+	if err != nil || pid <= 0 {
+		return 0, ErrInvalidPID
+	}
+
+	return pid, nil
+}
+
+// Remove deletes the PID file.
+// Returns nil if the file doesn't exist.
+func Remove() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove PID file: %w", err)
+	}
+
+	return nil
+}
+
+// IsRunning checks if the process with the PID in the file is alive.
+// Returns (running, pid, error).
+// If there's no PID file, returns (false, 0, nil).
+// If the PID file exists but the process is not running (stale), returns (false, pid, nil).
+// If the process is running, returns (true, pid, nil).
+func IsRunning() (bool, int, error) {
+	pid, err := Read()
+	if err != nil {
+		if errors.Is(err, ErrNoPIDFile) {
+			return false, 0, nil
+		}
+		return false, 0, err
+	}
+
+	// Check if process is alive using signal 0
+	// This doesn't send a signal but checks if the process exists
+	err = syscall.Kill(pid, 0)
+	if err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			// Process not found - stale PID file
+			return false, pid, nil
+		}
+		if errors.Is(err, syscall.EPERM) {
+			// Permission denied means process exists but we can't signal it
+			return true, pid, nil
+		}
+		return false, pid, fmt.Errorf("check process: %w", err)
+	}
+
+	return true, pid, nil
+}
+
+// CleanStale removes the PID file if it's stale: the process it names is
+// not running and no service holds its lock. The lock is held while the
+// file is removed, so a service starting at the same time keeps its file.
+// Returns the PID the stale file named, or 0 if nothing was removed.
+func CleanStale() (int, error) {
+	running, pid, err := IsRunning()
+	if err != nil || running || pid == 0 {
+		return 0, err
+	}
+
+	path, err := Path()
+	if err != nil {
+		return 0, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("open PID file: %w", err)
+	}
+	defer file.Close()
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			// A service holds it, so it is not stale after all
+			return 0, nil
+		}
+		return 0, fmt.Errorf("lock PID file: %w", err)
+	}
+	// Another process may have replaced the file since it was read
+	if !sameFile(file, path) {
+		return 0, nil
+	}
+	if current, err := Read(); err != nil || current != pid {
+		return 0, nil
+	}
+	if err := Remove(); err != nil {
+		return 0, err
+	}
+	return pid, nil
+}
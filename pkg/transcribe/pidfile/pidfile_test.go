@@ -0,0 +1,591 @@
+package pidfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestMain clears XDG_STATE_HOME so tests that point HOME at a temporary
+// directory keep their state files there too.
+func TestMain(m *testing.M) {
+	os.Unsetenv("XDG_STATE_HOME")
+	os.Exit(m.Run())
+}
+
+func TestPath(t *testing.T) {
+	path, err := Path()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if path == "" {
+		t.Error("expected non-empty path")
+	}
+
+	// Should end with .nota/transcribe.pid
+	if filepath.Base(path) != "transcribe.pid" {
+		t.Errorf("expected path to end with transcribe.pid, got: %s", path)
+	}
+
+	dir := filepath.Base(filepath.Dir(path))
+	if dir != ".nota" {
+		t.Errorf("expected parent directory to be .nota, got: %s", dir)
+	}
+}
+
+func TestPath_XDGStateHome(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.Setenv("XDG_STATE_HOME", tmpDir)
+	defer os.Unsetenv("XDG_STATE_HOME")
+
+	path, err := Path()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	expected := filepath.Join(tmpDir, "nota", "transcribe.pid")
+	if path != expected {
+		t.Errorf("expected %s, got: %s", expected, path)
+	}
+
+	statePath, err := StatePath()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if filepath.Dir(statePath) != filepath.Join(tmpDir, "nota") {
+		t.Errorf("expected state file next to the PID file, got: %s", statePath)
+	}
+}
+
+func TestWriteAndRead(t *testing.T) {
+	// Use a temp directory for testing
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	testPID := 12345
+
+	// Write PID
+	err := Write(testPID)
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	// Read PID
+	pid, err := Read()
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	if pid != testPID {
+		t.Errorf("expected PID %d, got %d", testPID, pid)
+	}
+
+	// Verify file permissions
+	path, _ := Path()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+
+	expectedPerm := os.FileMode(0644)
+	if info.Mode().Perm() != expectedPerm {
+		t.Errorf("expected permissions %o, got %o", expectedPerm, info.Mode().Perm())
+	}
+}
+
+func TestReadNoPIDFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	_, err := Read()
+	if err != ErrNoPIDFile {
+		t.Errorf("expected ErrNoPIDFile, got: %v", err)
+	}
+}
+
+func TestReadInvalidPID(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	// Create directory structure
+	notaDir := filepath.Join(tmpDir, ".nota")
+	os.MkdirAll(notaDir, 0755)
+
+	// Write invalid content
+	path := filepath.Join(notaDir, "transcribe.pid")
+	os.WriteFile(path, []byte("not-a-number\n"), 0644)
+
+	_, err := Read()
+	if err != ErrInvalidPID {
+		t.Errorf("expected ErrInvalidPID, got: %v", err)
+	}
+}
+
+func TestReadNegativePID(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	// Create directory structure
+	notaDir := filepath.Join(tmpDir, ".nota")
+	os.MkdirAll(notaDir, 0755)
+
+	// Write negative PID
+	path := filepath.Join(notaDir, "transcribe.pid")
+	os.WriteFile(path, []byte("-1\n"), 0644)
+
+	_, err := Read()
+	if err != ErrInvalidPID {
+		t.Errorf("expected ErrInvalidPID, got: %v", err)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	// Create a PID file
+	Write(12345)
+
+	// Remove it
+	err := Remove()
+	if err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+
+	// Verify it's gone
+	path, _ := Path()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected PID file to be removed")
+	}
+}
+
+func TestRemoveNonexistent(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	// Remove should succeed even if file doesn't exist
+	err := Remove()
+	if err != nil {
+		t.Errorf("expected no error removing nonexistent file, got: %v", err)
+	}
+}
+
+func TestWriteCreatesDirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	// .nota directory doesn't exist yet
+	notaDir := filepath.Join(tmpDir, ".nota")
+	if _, err := os.Stat(notaDir); !os.IsNotExist(err) {
+		t.Fatal("expected .nota directory to not exist initially")
+	}
+
+	// Write should create it
+	err := Write(12345)
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	if _, err := os.Stat(notaDir); err != nil {
+		t.Error("expected .nota directory to be created")
+	}
+}
+
+func TestIsRunningWithCurrentProcess(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	// Write our own PID
+	currentPID := os.Getpid()
+	Write(currentPID)
+
+	running, pid, err := IsRunning()
+	if err != nil {
+		t.Fatalf("IsRunning failed: %v", err)
+	}
+
+	if !running {
+		t.Error("expected process to be running")
+	}
+
+	if pid != currentPID {
+		t.Errorf("expected PID %d, got %d", currentPID, pid)
+	}
+}
+
+func TestIsRunningWithNoPIDFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	running, pid, err := IsRunning()
+	if err != nil {
+		t.Fatalf("IsRunning failed: %v", err)
+	}
+
+	if running {
+		t.Error("expected running to be false with no PID file")
+	}
+
+	if pid != 0 {
+		t.Errorf("expected PID 0, got %d", pid)
+	}
+}
+
+func TestIsRunningWithStalePID(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	// Write a PID that's very unlikely to be running
+	// Use a very high PID number that's almost certainly not in use
+	stalePID := 4194300 // Near max PID on most Linux systems
+
+	notaDir := filepath.Join(tmpDir, ".nota")
+	os.MkdirAll(notaDir, 0755)
+	path := filepath.Join(notaDir, "transcribe.pid")
+	os.WriteFile(path, []byte(strconv.Itoa(stalePID)+"\n"), 0644)
+
+	running, pid, err := IsRunning()
+	if err != nil {
+		t.Fatalf("IsRunning failed: %v", err)
+	}
+
+	if running {
+		t.Skip("stale PID is unexpectedly running, skipping test")
+	}
+
+	if pid != stalePID {
+		t.Errorf("expected PID %d, got %d", stalePID, pid)
+	}
+}
+
+func TestCleanStaleRemovesFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	// Write a stale PID
+	stalePID := 4194300
+	notaDir := filepath.Join(tmpDir, ".nota")
+	os.MkdirAll(notaDir, 0755)
+	path := filepath.Join(notaDir, "transcribe.pid")
+	os.WriteFile(path, []byte(strconv.Itoa(stalePID)+"\n"), 0644)
+
+	removed, err := CleanStale()
+	if err != nil {
+		t.Fatalf("CleanStale failed: %v", err)
+	}
+
+	if removed != stalePID {
+		t.Errorf("expected stale PID %d to be removed, got %d", stalePID, removed)
+	}
+
+	// Verify file is gone
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected PID file to be removed")
+	}
+}
+
+func TestCleanStaleDoesNotRemoveRunning(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	// Write current process PID
+	Write(os.Getpid())
+
+	removed, err := CleanStale()
+	if err != nil {
+		t.Fatalf("CleanStale failed: %v", err)
+	}
+
+	if removed != 0 {
+		t.Error("expected running process PID file to not be removed")
+	}
+
+	// Verify file still exists
+	path, _ := Path()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		t.Error("expected PID file to still exist")
+	}
+}
+
+func TestCleanStaleDoesNotRemoveLocked(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	// A service holding the lock, whatever PID the file names
+	lock, err := Acquire(4194300)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer lock.Release()
+
+	removed, err := CleanStale()
+	if err != nil {
+		t.Fatalf("CleanStale failed: %v", err)
+	}
+	if removed != 0 {
+		t.Errorf("expected locked PID file to not be removed, got %d", removed)
+	}
+	path, _ := Path()
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected PID file to still exist, got: %v", err)
+	}
+}
+
+func TestAcquireWritesPID(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	lock, err := Acquire(os.Getpid())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer lock.Release()
+
+	pid, err := Read()
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if pid != os.Getpid() {
+		t.Errorf("expected PID %d, got %d", os.Getpid(), pid)
+	}
+}
+
+func TestAcquireFailsWhenHeld(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	lock, err := Acquire(os.Getpid())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer lock.Release()
+
+	// flock locks are per open file description, so a second open in the
+	// same process contends just like a second daemon would
+	_, err = Acquire(12345)
+	if !errors.Is(err, ErrAlreadyRunning) {
+		t.Fatalf("expected ErrAlreadyRunning, got: %v", err)
+	}
+
+	// The losing caller must not overwrite the owner's PID
+	pid, _ := Read()
+	if pid != os.Getpid() {
+		t.Errorf("expected PID %d to be preserved, got %d", os.Getpid(), pid)
+	}
+}
+
+func TestAcquireTakesOverStaleFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	// A PID file without a lock holder is stale
+	Write(4194300)
+
+	lock, err := Acquire(os.Getpid())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer lock.Release()
+
+	pid, _ := Read()
+	if pid != os.Getpid() {
+		t.Errorf("expected PID %d, got %d", os.Getpid(), pid)
+	}
+}
+
+func TestAcquireAfterRelease(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	lock, err := Acquire(os.Getpid())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	path, _ := Path()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected PID file to be removed on release")
+	}
+
+	lock, err = Acquire(os.Getpid())
+	if err != nil {
+		t.Fatalf("expected second Acquire to succeed, got: %v", err)
+	}
+	lock.Release()
+}
+
+func TestRecordStartAndStop(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	started := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	state, err := RecordStart(100, 0, started)
+	if err != nil {
+		t.Fatalf("RecordStart failed: %v", err)
+	}
+	if state.Restarts != 0 {
+		t.Errorf("expected 0 restarts on first start, got %d", state.Restarts)
+	}
+
+	if got := state.Uptime(started.Add(90 * time.Second)); got != 90*time.Second {
+		t.Errorf("expected uptime 90s, got %v", got)
+	}
+
+	if err := RecordStop(started.Add(time.Hour), Stop{Reason: StopSignal, Signal: "SIGTERM"}); err != nil {
+		t.Fatalf("RecordStop failed: %v", err)
+	}
+
+	read, err := ReadState()
+	if err != nil {
+		t.Fatalf("ReadState failed: %v", err)
+	}
+	if read.StoppedAt == nil {
+		t.Fatal("expected StoppedAt to be set")
+	}
+	if read.Stop == nil || read.Stop.String() != "SIGTERM" {
+		t.Errorf("expected stop by SIGTERM, got %+v", read.Stop)
+	}
+
+	// A start after a clean stop is not a restart
+	state, err = RecordStart(101, 0, started.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("RecordStart failed: %v", err)
+	}
+	if state.Restarts != 0 {
+		t.Errorf("expected 0 restarts after clean stop, got %d", state.Restarts)
+	}
+}
+
+func TestRecordStartCountsCrashRestarts(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	now := time.Now()
+	RecordStart(100, 0, now)
+
+	// No RecordStop: the previous run crashed
+	state, err := RecordStart(101, 0, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("RecordStart failed: %v", err)
+	}
+	if state.Restarts != 1 {
+		t.Errorf("expected 1 restart, got %d", state.Restarts)
+	}
+
+	state, _ = RecordStart(102, 0, now.Add(2*time.Minute))
+	if state.Restarts != 2 {
+		t.Errorf("expected 2 restarts, got %d", state.Restarts)
+	}
+}
+
+func TestRecordStartCountsErrorStops(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	now := time.Now()
+	RecordStart(100, 0, now)
+	if err := RecordStop(now, Stop{Reason: StopError, Error: "file watcher stopped unexpectedly"}); err != nil {
+		t.Fatalf("RecordStop failed: %v", err)
+	}
+
+	// A run that stopped with an error did not stop cleanly
+	state, err := RecordStart(101, 0, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("RecordStart failed: %v", err)
+	}
+	if state.Restarts != 1 {
+		t.Errorf("expected 1 restart, got %d", state.Restarts)
+	}
+	if state.LastError != "file watcher stopped unexpectedly" || state.LastErrorAt == nil {
+		t.Errorf("expected the error carried over, got %q at %v", state.LastError, state.LastErrorAt)
+	}
+
+	RecordStop(now.Add(2*time.Minute), Stop{Reason: StopDrained})
+	state, _ = RecordStart(102, 0, now.Add(3*time.Minute))
+	if state.Restarts != 0 {
+		t.Errorf("expected 0 restarts after a drained stop, got %d", state.Restarts)
+	}
+	if state.LastError != "file watcher stopped unexpectedly" {
+		t.Errorf("expected the last error kept, got %q", state.LastError)
+	}
+
+	// No RecordStop: the run crashed
+	state, _ = RecordStart(103, 0, now.Add(4*time.Minute))
+	if state.LastError != "PID 102 exited without recording a stop" || state.LastErrorAt != nil {
+		t.Errorf("expected the crash recorded, got %q at %v", state.LastError, state.LastErrorAt)
+	}
+}
+
+func TestStopString(t *testing.T) {
+	tests := []struct {
+		stop     Stop
+		expected string
+	}{
+		{Stop{Reason: StopSignal, Signal: "SIGTERM"}, "SIGTERM"},
+		{Stop{Reason: StopSignal}, "signal"},
+		{Stop{Reason: StopError, Error: "watcher failed"}, "error: watcher failed"},
+		{Stop{Reason: StopDrained}, "drained"},
+	}
+	for _, tt := range tests {
+		if got := tt.stop.String(); got != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestReadStateNoFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", originalHome)
+
+	if _, err := ReadState(); err != ErrNoStateFile {
+		t.Errorf("expected ErrNoStateFile, got: %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package pidfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const stateFileName = "transcribe.state.json"
+
+// State records the lifecycle of the most recent service run.
+// Unlike the PID file it survives a clean shutdown, so the next start can tell
+// whether the previous run exited cleanly or crashed.
+type State struct {
+	PID       int        `json:"pid"`
+	StartedAt time.Time  `json:"started_at"`
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+	// SupervisorPID is the PID of the supervising process, or 0 if unsupervised.
+	SupervisorPID int `json:"supervisor_pid,omitempty"`
+	// Restarts counts consecutive starts that followed an unclean exit.
+	Restarts int `json:"restarts"`
+	// Stop records why the run ended, once it has. A run that ended without
+	// recording one crashed or was killed.
+	Stop *Stop `json:"stop,omitempty"`
+	// LastError is how the most recent run that did not stop cleanly
+	// ended, carried over to the runs after it, and LastErrorAt when, if
+	// known.
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// Stop reasons
+const (
+	// StopSignal is a run stopped by a signal, such as the SIGTERM sent by
+	// nota transcribe stop.
+	StopSignal = "signal"
+	// StopError is a run that ended with a fatal error.
+	StopError = "error"
+	// StopDrained is a run that finished its work and ended without a
+	// signal or an error, such as when its context was cancelled.
+	StopDrained = "drained"
+)
+
+// Stop describes why a run ended.
+type Stop struct {
+	// Reason is StopSignal, StopError or StopDrained.
+	Reason string `json:"reason"`
+	// Signal names the signal a StopSignal run received, e.g. "SIGTERM".
+	Signal string `json:"signal,omitempty"`
+	// Error is the error a StopError run ended with.
+	Error string `json:"error,omitempty"`
+}
+
+// Clean reports whether the run was stopped on purpose rather than by an
+// error.
+func (s Stop) Clean() bool {
+	return s.Reason != StopError
+}
+
+// String describes the stop, e.g. "SIGTERM" or "error: watcher stopped".
+func (s Stop) String() string {
+	switch s.Reason {
+	case StopSignal:
+		if s.Signal != "" {
+			return s.Signal
+		}
+	case StopError:
+		if s.Error != "" {
+			return "error: " + s.Error
+		}
+	}
+	return s.Reason
+}
+
+// Uptime returns how long the run has been up as of now.
+func (s *State) Uptime(now time.Time) time.Duration {
+	if s.StoppedAt != nil {
+		return s.StoppedAt.Sub(s.StartedAt)
+	}
+	return now.Sub(s.StartedAt)
+}
+
+// StoppedCleanly reports whether the run recorded a stop that was not a
+// fatal error.
+func (s *State) StoppedCleanly() bool {
+	return s.StoppedAt != nil && (s.Stop == nil || s.Stop.Clean())
+}
+
+// StatePath returns the path to the state file, next to the PID file
+func StatePath() (string, error) {
+	path, err := Path()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), stateFileName), nil
+}
+
+// ReadState reads the state file.
+// Returns ErrNoStateFile if the state file doesn't exist.
+func ReadState() (*State, error) {
+	path, err := StatePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoStateFile
+		}
+		return nil, fmt.Errorf("read state file: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parse state file: %w", err)
+	}
+	return &state, nil
+}
+
+// WriteState writes the state file, creating parent directories if needed.
+func WriteState(state *State) error {
+	path, err := StatePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), dirPerm); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write via rename so status never reads a half-written file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, filePerm); err != nil {
+		return fmt.Errorf("write state file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write state file: %w", err)
+	}
+	return nil
+}
+
+// RecordStart records that the process with the given PID started at now.
+// supervisorPID is the supervising process, or 0 if the service is unsupervised.
+// If the previous run never recorded a stop, it crashed, and if it stopped
+// with an error it failed; either way the restart count is carried forward
+// and incremented. Otherwise it resets to zero. The previous run's last
+// error is carried forward, or set if it crashed.
+func RecordStart(pid, supervisorPID int, now time.Time) (*State, error) {
+	state := &State{PID: pid, StartedAt: now.UTC(), SupervisorPID: supervisorPID}
+
+	prev, err := ReadState()
+	if err != nil && !errors.Is(err, ErrNoStateFile) {
+		return nil, err
+	}
+	if prev != nil && prev.PID != pid {
+		state.LastError, state.LastErrorAt = prev.LastError, prev.LastErrorAt
+		if prev.StoppedAt == nil {
+			state.LastError = fmt.Sprintf("PID %d exited without recording a stop", prev.PID)
+			state.LastErrorAt = nil
+		}
+		if !prev.StoppedCleanly() {
+			state.Restarts = prev.Restarts + 1
+		}
+	}
+
+	if err := WriteState(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// RecordStop marks the current run as stopped at now, for the given reason.
+func RecordStop(now time.Time, stop Stop) error {
+	state, err := ReadState()
+	if err != nil {
+		return err
+	}
+
+	stopped := now.UTC()
+	state.StoppedAt = &stopped
+	state.Stop = &stop
+	if !stop.Clean() {
+		state.LastError, state.LastErrorAt = stop.Error, &stopped
+	}
+	return WriteState(state)
+}
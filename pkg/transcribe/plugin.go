@@ -0,0 +1,122 @@
+package transcribe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+)
+
+// PluginsDir is the directory within the vault's .nota directory that
+// plugins are loaded from.
+const PluginsDir = "plugins"
+
+// pluginDescribeTimeout bounds how long a plugin may take to describe
+// itself when the service starts.
+const pluginDescribeTimeout = 10 * time.Second
+
+// PluginDir returns the plugins directory of the vault at root.
+func PluginDir(root string) string {
+	return filepath.Join(root, vault.VaultMarkerDir, PluginsDir)
+}
+
+// pluginStage is an executable run as a pipeline stage. Run as
+// "plugin describe" it prints its name and stage point as JSON, e.g.
+// {"name": "summarize", "point": "after_transcribe"}; run as "plugin run" it
+// reads a StageInput as JSON on stdin and prints a StageOutput, or nothing.
+type pluginStage struct {
+	path  string
+	name  string
+	point StagePoint
+}
+
+func (p *pluginStage) Name() string      { return p.name }
+func (p *pluginStage) Point() StagePoint { return p.point }
+
+// Run runs the plugin for one file.
+func (p *pluginStage) Run(ctx context.Context, in StageInput) (StageOutput, error) {
+	input, err := json.Marshal(in)
+	if err != nil {
+		return StageOutput{}, err
+	}
+	out, err := runHook(ctx, []string{p.path, "run"}, "", strings.NewReader(string(input)))
+	if err != nil {
+		return StageOutput{}, err
+	}
+	var output StageOutput
+	if strings.TrimSpace(out) == "" {
+		return output, nil
+	}
+	if err := json.Unmarshal([]byte(out), &output); err != nil {
+		return StageOutput{}, fmt.Errorf("parse output: %w", err)
+	}
+	return output, nil
+}
+
+// describePlugin asks the executable at path for its name and stage point.
+// The name defaults to the file name.
+func describePlugin(ctx context.Context, path string) (*pluginStage, error) {
+	ctx, cancel := context.WithTimeout(ctx, pluginDescribeTimeout)
+	defer cancel()
+	out, err := runHook(ctx, []string{path, "describe"}, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	var desc struct {
+		Name  string     `json:"name"`
+		Point StagePoint `json:"point"`
+	}
+	if err := json.Unmarshal([]byte(out), &desc); err != nil {
+		return nil, fmt.Errorf("parse description: %w", err)
+	}
+	if !desc.Point.Valid() {
+		return nil, fmt.Errorf("point %q must be after_transcribe or after_write", desc.Point)
+	}
+	if desc.Name == "" {
+		desc.Name = filepath.Base(path)
+	}
+	return &pluginStage{path: path, name: desc.Name, point: desc.Point}, nil
+}
+
+// loadPlugins returns a stage for each executable in dir, in name order. A
+// plugin other users can modify, or that cannot describe itself, is logged
+// and skipped.
+func loadPlugins(ctx context.Context, dir string, logger Logger) []PipelineStage {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Error("failed to read plugins directory", err, logging.String("dir", dir))
+		}
+		return nil
+	}
+
+	var plugins []PipelineStage
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil || strings.HasPrefix(entry.Name(), ".") || !info.Mode().IsRegular() || info.Mode()&0o111 == 0 {
+			continue
+		}
+		if info.Mode()&0o022 != 0 {
+			logger.Error("skipping plugin writable by other users", fmt.Errorf("mode %s", info.Mode()),
+				logging.String("plugin", path),
+			)
+			continue
+		}
+		plugin, err := describePlugin(ctx, path)
+		if err != nil {
+			logger.Error("skipping plugin that failed to describe itself", err,
+				logging.String("plugin", path),
+			)
+			continue
+		}
+		plugins = append(plugins, plugin)
+	}
+	return plugins
+}
@@ -0,0 +1,107 @@
+package transcribe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ProfileEnv selects a profile by name, overriding hostname matching. The
+// nota command sets it from --profile so daemon children inherit the choice.
+const ProfileEnv = "NOTA_PROFILE"
+
+// ErrUnknownProfile is returned when the requested profile is not defined.
+var ErrUnknownProfile = errors.New("unknown profile")
+
+// profileHostnames is the profile key listing the machines it applies to.
+const profileHostnames = "hostnames"
+
+// applyProfile overrides settings with those of the named profile or, when
+// name is empty, of the first profile (by name) matching this machine's
+// hostname. A profile matches when its hostnames list contains the hostname,
+// or, without a list, when its name equals the hostname. Each profile holds
+// any top-level settings plus hostnames.
+func (c *Config) applyProfile(name string) error {
+	if name == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil
+		}
+		name = c.matchProfile(hostname)
+		if name == "" {
+			return nil
+		}
+	}
+
+	raw, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownProfile, name)
+	}
+
+	// Unmarshalling over the config replaces only the settings the profile
+	// lists; keep the profile table itself intact
+	profiles := c.Profiles
+	if err := json.Unmarshal(raw, c); err != nil {
+		return fmt.Errorf("profile %s: %w", name, err)
+	}
+	c.Profiles = profiles
+	c.profile = name
+	return nil
+}
+
+// matchProfile returns the name of the first profile matching hostname.
+func (c *Config) matchProfile(hostname string) string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var p struct {
+			Hostnames []string `json:"hostnames"`
+		}
+		json.Unmarshal(c.Profiles[name], &p)
+		if len(p.Hostnames) == 0 && strings.EqualFold(name, hostname) {
+			return name
+		}
+		for _, h := range p.Hostnames {
+			if strings.EqualFold(h, hostname) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// Profile returns the name of the profile applied when loading, or "".
+func (c *Config) Profile() string {
+	return c.profile
+}
+
+// profileWarnings checks each profile for unknown settings.
+func profileWarnings(profiles map[string]json.RawMessage) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var warnings []string
+	for _, name := range names {
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(profiles[name], &fields) != nil {
+			warnings = append(warnings, fmt.Sprintf("profile %s is not an object", name))
+			continue
+		}
+		delete(fields, profileHostnames)
+		delete(fields, "profiles")
+		data, _ := json.Marshal(fields)
+		warnings = append(warnings, unknownFields(data, reflect.TypeOf(Config{}), "profiles."+name)...)
+	}
+	return warnings
+}
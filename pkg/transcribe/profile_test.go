@@ -0,0 +1,116 @@
+package transcribe
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// profileConfig shares the vault between a desktop, matched by hostname
+// list, and a laptop, matched by profile name.
+func profileConfig(hostname string) string {
+	return `{
+  "watch_dir": "/mnt/sync",
+  "api_url": "http://localhost:9000/asr",
+  "output_dir": "/vault/Inbox",
+  "model": "base",
+  "profiles": {
+    "desktop": {"hostnames": ["` + hostname + `"], "watch_dir": "/data/recordings", "api_url": "http://gpu-box:9000/asr"},
+    "laptop": {"watch_dir": "/home/me/Sync", "model": "tiny"}
+  }
+}`
+}
+
+func setProfileEnv(t *testing.T, value string) {
+	t.Helper()
+	original := os.Getenv(ProfileEnv)
+	os.Setenv(ProfileEnv, value)
+	t.Cleanup(func() { os.Setenv(ProfileEnv, original) })
+}
+
+func TestLoadFromVault_ProfileByHostname(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skipf("no hostname: %v", err)
+	}
+	setProfileEnv(t, "")
+	vaultRoot := setupTestVault(t)
+	writeRawConfig(t, vaultRoot, profileConfig(hostname))
+
+	cfg, err := LoadFromVault(vaultRoot)
+	if err != nil {
+		t.Fatalf("LoadFromVault failed: %v", err)
+	}
+	if cfg.Profile() != "desktop" {
+		t.Fatalf("expected desktop profile, got %q", cfg.Profile())
+	}
+	if cfg.WatchDir != "/data/recordings" || cfg.APIURL != "http://gpu-box:9000/asr" {
+		t.Errorf("expected desktop overrides, got watch_dir %q api_url %q", cfg.WatchDir, cfg.APIURL)
+	}
+	if cfg.Model != "base" || cfg.OutputDir != "/vault/Inbox" {
+		t.Errorf("expected shared settings to be kept, got model %q output_dir %q", cfg.Model, cfg.OutputDir)
+	}
+	if len(cfg.Warnings()) != 0 {
+		t.Errorf("expected no warnings, got: %v", cfg.Warnings())
+	}
+}
+
+func TestLoadFromVault_ProfileByName(t *testing.T) {
+	setProfileEnv(t, "laptop")
+	vaultRoot := setupTestVault(t)
+	writeRawConfig(t, vaultRoot, profileConfig("some-other-host"))
+
+	cfg, err := LoadFromVault(vaultRoot)
+	if err != nil {
+		t.Fatalf("LoadFromVault failed: %v", err)
+	}
+	if cfg.Profile() != "laptop" || cfg.WatchDir != "/home/me/Sync" || cfg.Model != "tiny" {
+		t.Errorf("expected laptop overrides, got profile %q watch_dir %q model %q", cfg.Profile(), cfg.WatchDir, cfg.Model)
+	}
+	if cfg.APIURL != "http://localhost:9000/asr" {
+		t.Errorf("expected shared api_url, got %q", cfg.APIURL)
+	}
+	if len(cfg.Profiles) != 2 {
+		t.Errorf("expected profiles to be kept, got %d", len(cfg.Profiles))
+	}
+}
+
+func TestLoadFromVault_NoMatchingProfile(t *testing.T) {
+	setProfileEnv(t, "")
+	vaultRoot := setupTestVault(t)
+	writeRawConfig(t, vaultRoot, profileConfig("some-other-host"))
+
+	cfg, err := LoadFromVault(vaultRoot)
+	if err != nil {
+		t.Fatalf("LoadFromVault failed: %v", err)
+	}
+	if cfg.Profile() != "" || cfg.WatchDir != "/mnt/sync" {
+		t.Errorf("expected base settings, got profile %q watch_dir %q", cfg.Profile(), cfg.WatchDir)
+	}
+}
+
+func TestLoadFromVault_UnknownProfile(t *testing.T) {
+	setProfileEnv(t, "tablet")
+	vaultRoot := setupTestVault(t)
+	writeRawConfig(t, vaultRoot, profileConfig("some-other-host"))
+
+	if _, err := LoadFromVault(vaultRoot); !errors.Is(err, ErrUnknownProfile) {
+		t.Errorf("expected ErrUnknownProfile, got: %v", err)
+	}
+}
+
+func TestParseConfig_ProfileWarnings(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{
+  "watch_dir": "/mnt/sync",
+  "profiles": {"laptop": {"hostnames": ["lap"], "modle": "tiny"}}
+}`))
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	expected := []string{"unknown field profiles.laptop.modle (did you mean model?)"}
+	if !reflect.DeepEqual(cfg.Warnings(), expected) {
+		t.Errorf("expected warnings %q, got %q", expected, cfg.Warnings())
+	}
+}
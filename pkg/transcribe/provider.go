@@ -0,0 +1,245 @@
+package transcribe
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/cloudasr"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/grpcasr"
+)
+
+// Provider selects the kind of transcription API at api_url.
+type Provider string
+
+// Transcription providers.
+const (
+	// ProviderWhisperASR uploads each file to a whisper-asr-webservice
+	// compatible /asr endpoint.
+	ProviderWhisperASR Provider = "whisper_asr"
+	// ProviderGRPC streams each file to a server implementing the
+	// Transcriber service in grpcasr/asr.proto.
+	ProviderGRPC Provider = "grpc"
+	// ProviderAzure, ProviderGoogle, ProviderAWS, ProviderDeepgram and
+	// ProviderAssemblyAI use hosted speech services, configured in the
+	// block of the same name.
+	ProviderAzure      Provider = "azure"
+	ProviderGoogle     Provider = "google"
+	ProviderAWS        Provider = "aws"
+	ProviderDeepgram   Provider = "deepgram"
+	ProviderAssemblyAI Provider = "assemblyai"
+)
+
+// Valid reports whether p is a known provider.
+func (p Provider) Valid() bool {
+	switch p {
+	case ProviderWhisperASR, ProviderGRPC:
+		return true
+	}
+	return p.hosted()
+}
+
+// hosted reports whether p is a hosted service, whose endpoint follows from
+// its settings so api_url is optional.
+func (p Provider) hosted() bool {
+	switch p {
+	case ProviderAzure, ProviderGoogle, ProviderAWS, ProviderDeepgram, ProviderAssemblyAI:
+		return true
+	}
+	return false
+}
+
+// AzureConfig selects the Azure Speech resource for provider azure.
+type AzureConfig struct {
+	// Region is the resource's region, e.g. westeurope. Not needed when
+	// api_url points at the resource's endpoint.
+	Region string `json:"region,omitempty"`
+	// Key defaults to the AZURE_SPEECH_KEY environment variable.
+	Key string `json:"key,omitempty"`
+}
+
+// GoogleConfig selects the Google Cloud project for provider google.
+type GoogleConfig struct {
+	// Project defaults to the service account's project.
+	Project string `json:"project,omitempty"`
+	// Location defaults to global, and Model to long.
+	Location string `json:"location,omitempty"`
+	Model    string `json:"model,omitempty"`
+	// APIKey authenticates with an API key; otherwise CredentialsFile, a
+	// service account key file, does. It defaults to the
+	// GOOGLE_APPLICATION_CREDENTIALS environment variable.
+	APIKey          string `json:"api_key,omitempty"`
+	CredentialsFile string `json:"credentials_file,omitempty"`
+}
+
+// AWSConfig selects the region and staging bucket for provider aws.
+type AWSConfig struct {
+	Region string `json:"region"`
+	// Bucket and Prefix are where recordings wait while AWS Transcribe reads
+	// them; each is deleted once transcribed.
+	Bucket string `json:"bucket"`
+	Prefix string `json:"prefix,omitempty"`
+	// AccessKeyID and SecretAccessKey default to the AWS_ACCESS_KEY_ID and
+	// AWS_SECRET_ACCESS_KEY environment variables.
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+}
+
+// DeepgramConfig selects the model for provider deepgram.
+type DeepgramConfig struct {
+	// Key defaults to the DEEPGRAM_API_KEY environment variable.
+	Key string `json:"key,omitempty"`
+	// Model defaults to nova-3.
+	Model string `json:"model,omitempty"`
+}
+
+// AssemblyAIConfig selects the speech model for provider assemblyai.
+type AssemblyAIConfig struct {
+	// Key defaults to the ASSEMBLYAI_API_KEY environment variable.
+	Key string `json:"key,omitempty"`
+	// SpeechModel defaults to the service's.
+	SpeechModel string `json:"speech_model,omitempty"`
+}
+
+// validateAPI checks the provider and normalizes api_url, which only
+// hosted providers can do without.
+func (c *Config) validateAPI() error {
+	if c.Provider != "" && !c.Provider.Valid() {
+		return ErrInvalidProvider
+	}
+	if c.APIURL == "" {
+		if !c.Provider.hosted() {
+			return ErrAPIURLRequired
+		}
+	} else {
+		apiURL, err := NormalizeAPIURL(c.APIURL)
+		if err != nil {
+			return err
+		}
+		c.APIURL = apiURL
+	}
+	return c.validateProvider()
+}
+
+// validateProvider checks the settings the provider requires.
+func (c *Config) validateProvider() error {
+	switch c.Provider {
+	case ProviderAzure:
+		if c.APIURL == "" && (c.Azure == nil || c.Azure.Region == "") {
+			return fmt.Errorf("%w: azure needs a region", ErrInvalidProviderSettings)
+		}
+	case ProviderAWS:
+		if c.AWS == nil || c.AWS.Region == "" || c.AWS.Bucket == "" {
+			return fmt.Errorf("%w: aws needs a region and a bucket", ErrInvalidProviderSettings)
+		}
+	}
+	return nil
+}
+
+// target describes where files are sent, for logs: api_url, or the
+// provider for a hosted service at its default endpoint.
+func (c *Config) target() string {
+	if c.APIURL == "" {
+		return string(c.Provider)
+	}
+	return c.APIURL
+}
+
+// newClient returns a client for the configured provider at apiURL, with
+// the configured transport and the service's upload hooks. For hosted
+// services an empty apiURL selects their default endpoint.
+func (c *Config) newClient(apiURL string, uploads uploadHooks) (client.TranscriptionClient, error) {
+	t := c.httpTransport(apiURL)
+	httpClient := &http.Client{Timeout: client.DefaultTimeout, Transport: t}
+	cloudOpts := append(uploads.cloudOptions(), cloudasr.WithHTTPClient(httpClient))
+
+	switch c.Provider {
+	case ProviderGRPC:
+		// gRPC needs HTTP/2 whatever http2 says
+		grpcasr.ConfigureTransport(t, apiURL)
+		return grpcasr.New(apiURL, append(uploads.grpcOptions(), grpcasr.WithHTTPClient(httpClient))...), nil
+
+	case ProviderAzure:
+		var az AzureConfig
+		if c.Azure != nil {
+			az = *c.Azure
+		}
+		return cloudasr.NewAzure(cloudasr.AzureConfig{
+			Region:   az.Region,
+			Key:      orEnv(az.Key, "AZURE_SPEECH_KEY"),
+			Endpoint: apiURL,
+		}, cloudOpts...), nil
+
+	case ProviderGoogle:
+		var g GoogleConfig
+		if c.Google != nil {
+			g = *c.Google
+		}
+		cfg := cloudasr.GoogleConfig{
+			Project:  g.Project,
+			Location: g.Location,
+			Model:    g.Model,
+			APIKey:   g.APIKey,
+			Endpoint: apiURL,
+		}
+		if cfg.APIKey == "" {
+			path := orEnv(g.CredentialsFile, "GOOGLE_APPLICATION_CREDENTIALS")
+			if path == "" {
+				return nil, fmt.Errorf("%w: google needs api_key or credentials_file", ErrInvalidProviderSettings)
+			}
+			sa, err := cloudasr.LoadServiceAccount(path)
+			if err != nil {
+				return nil, fmt.Errorf("%w: google credentials: %v", ErrInvalidProviderSettings, err)
+			}
+			cfg.Credentials = sa
+		}
+		return cloudasr.NewGoogle(cfg, cloudOpts...)
+
+	case ProviderAWS:
+		var a AWSConfig
+		if c.AWS != nil {
+			a = *c.AWS
+		}
+		return cloudasr.NewAWS(cloudasr.AWSConfig{
+			Region:          a.Region,
+			Bucket:          a.Bucket,
+			Prefix:          a.Prefix,
+			AccessKeyID:     orEnv(a.AccessKeyID, "AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: orEnv(a.SecretAccessKey, "AWS_SECRET_ACCESS_KEY"),
+			Endpoint:        apiURL,
+		}, cloudOpts...)
+
+	case ProviderDeepgram:
+		var d DeepgramConfig
+		if c.Deepgram != nil {
+			d = *c.Deepgram
+		}
+		return cloudasr.NewDeepgram(cloudasr.DeepgramConfig{
+			Key:      orEnv(d.Key, "DEEPGRAM_API_KEY"),
+			Model:    d.Model,
+			Endpoint: apiURL,
+		}, cloudOpts...), nil
+
+	case ProviderAssemblyAI:
+		var a AssemblyAIConfig
+		if c.AssemblyAI != nil {
+			a = *c.AssemblyAI
+		}
+		return cloudasr.NewAssemblyAI(cloudasr.AssemblyAIConfig{
+			Key:         orEnv(a.Key, "ASSEMBLYAI_API_KEY"),
+			SpeechModel: a.SpeechModel,
+			Endpoint:    apiURL,
+		}, cloudOpts...), nil
+	}
+	return client.NewWhisperASRClient(apiURL, append(uploads.whisperOptions(), client.WithHTTPClient(httpClient))...), nil
+}
+
+// orEnv returns value, or the environment variable name when value is
+// empty.
+func orEnv(value, name string) string {
+	if value == "" {
+		return os.Getenv(name)
+	}
+	return value
+}
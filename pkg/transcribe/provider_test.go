@@ -0,0 +1,126 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+func TestValidate_Provider(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr error
+	}{
+		{name: "unknown", cfg: Config{APIURL: "http://localhost:9000", Provider: "openai"}, wantErr: ErrInvalidProvider},
+		{name: "grpc", cfg: Config{APIURL: "http://localhost:50051", Provider: ProviderGRPC}},
+		{name: "grpc without api_url", cfg: Config{Provider: ProviderGRPC}, wantErr: ErrAPIURLRequired},
+		{name: "azure", cfg: Config{Provider: ProviderAzure, Azure: &AzureConfig{Region: "westeurope"}}},
+		{name: "azure at api_url", cfg: Config{Provider: ProviderAzure, APIURL: "https://memos.cognitiveservices.azure.com"}},
+		{name: "azure without region", cfg: Config{Provider: ProviderAzure}, wantErr: ErrInvalidProviderSettings},
+		{name: "google", cfg: Config{Provider: ProviderGoogle}},
+		{name: "aws", cfg: Config{Provider: ProviderAWS, AWS: &AWSConfig{Region: "eu-west-1", Bucket: "memos"}}},
+		{name: "deepgram", cfg: Config{Provider: ProviderDeepgram}},
+		{name: "assemblyai", cfg: Config{Provider: ProviderAssemblyAI}},
+		{name: "aws without bucket", cfg: Config{Provider: ProviderAWS, AWS: &AWSConfig{Region: "eu-west-1"}}, wantErr: ErrInvalidProviderSettings},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.WatchDir = "/tmp/watch"
+			cfg.OutputDir = "/tmp/output"
+			err := cfg.Validate()
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewClient_Provider(t *testing.T) {
+	tests := []struct {
+		cfg  Config
+		url  string
+		want string
+	}{
+		{cfg: Config{}, url: "http://nas:9000/asr", want: "*client.WhisperASRClient"},
+		{cfg: Config{Provider: ProviderGRPC}, url: "http://nas:50051", want: "*grpcasr.Client"},
+		{cfg: Config{Provider: ProviderAzure, Azure: &AzureConfig{Region: "westeurope", Key: "key"}}, want: "*cloudasr.AzureClient"},
+		{cfg: Config{Provider: ProviderGoogle, Google: &GoogleConfig{Project: "memos", APIKey: "key"}}, want: "*cloudasr.GoogleClient"},
+		{cfg: Config{Provider: ProviderAWS, AWS: &AWSConfig{Region: "eu-west-1", Bucket: "memos"}}, want: "*cloudasr.AWSClient"},
+		{cfg: Config{Provider: ProviderDeepgram}, want: "*cloudasr.DeepgramClient"},
+		{cfg: Config{Provider: ProviderAssemblyAI, AssemblyAI: &AssemblyAIConfig{SpeechModel: "universal"}}, want: "*cloudasr.AssemblyAIClient"},
+	}
+
+	for _, tt := range tests {
+		c, err := tt.cfg.newClient(tt.url, uploadHooks{})
+		if err != nil {
+			t.Errorf("%q: expected no error, got: %v", tt.cfg.Provider, err)
+			continue
+		}
+		if got := fmt.Sprintf("%T", c); got != tt.want {
+			t.Errorf("%q: expected a %s, got %s", tt.cfg.Provider, tt.want, got)
+		}
+	}
+}
+
+func TestNewClient_GoogleNeedsCredentials(t *testing.T) {
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	cfg := &Config{Provider: ProviderGoogle, Google: &GoogleConfig{Project: "memos"}}
+	if _, err := cfg.newClient("", uploadHooks{}); !errors.Is(err, ErrInvalidProviderSettings) {
+		t.Errorf("expected ErrInvalidProviderSettings, got: %v", err)
+	}
+
+	cfg.Google.CredentialsFile = filepath.Join(t.TempDir(), "missing.json")
+	if _, err := cfg.newClient("", uploadHooks{}); !errors.Is(err, ErrInvalidProviderSettings) {
+		t.Errorf("expected ErrInvalidProviderSettings for a missing key file, got: %v", err)
+	}
+}
+
+func TestConfig_ProviderNameOfHostedService(t *testing.T) {
+	cfg := &Config{Provider: ProviderAzure, APIURL: "https://memos.cognitiveservices.azure.com"}
+	if name := cfg.ProviderName(); name != "azure" {
+		t.Errorf("expected azure, got %q", name)
+	}
+}
+
+func TestNewClient_GRPCSpeaksH2C(t *testing.T) {
+	// Fails every call, as a server without a model loaded
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			http.Error(w, "HTTP/2 required", http.StatusHTTPVersionNotSupported)
+			return
+		}
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Grpc-Status", "14")
+		w.Header().Set("Grpc-Message", "loading model")
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+	audio := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(audio, []byte("audio"), 0644)
+
+	// Without http2 set, as gRPC needs it regardless
+	cfg := &Config{Provider: ProviderGRPC}
+	c, err := cfg.newClient(server.URL, uploadHooks{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	_, err = c.Transcribe(context.Background(), audio, TranscribeOptions{})
+	if status := client.StatusCode(err); status != http.StatusServiceUnavailable {
+		t.Errorf("expected the server's UNAVAILABLE as status 503, got %d (%v)", status, err)
+	}
+}
@@ -0,0 +1,185 @@
+package transcribe
+
+import (
+	"container/heap"
+	"context"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// QueueOrder selects which waiting file a free worker picks up next.
+type QueueOrder string
+
+// Queue ordering policies.
+const (
+	// QueueFIFO processes files in the order they became ready.
+	QueueFIFO QueueOrder = "fifo"
+	// QueueNewestFirst processes the most recently modified file first.
+	QueueNewestFirst QueueOrder = "newest_first"
+	// QueueSmallestFirst processes the smallest file first.
+	QueueSmallestFirst QueueOrder = "smallest_first"
+)
+
+// Valid reports whether o is a known ordering policy.
+func (o QueueOrder) Valid() bool {
+	switch o {
+	case QueueFIFO, QueueNewestFirst, QueueSmallestFirst:
+		return true
+	}
+	return false
+}
+
+// queueItem is a file waiting for a worker.
+type queueItem struct {
+	path    string
+	size    int64
+	modTime time.Time
+	seq     uint64
+	ready   chan struct{}
+	index   int
+}
+
+// newQueueItem creates an item for path, reading its size and modification time.
+func newQueueItem(path string) *queueItem {
+	item := &queueItem{path: path, ready: make(chan struct{}), index: -1}
+	if info, err := os.Stat(path); err == nil {
+		item.size = info.Size()
+		item.modTime = info.ModTime()
+	}
+	return item
+}
+
+// before reports whether a should be processed before b under the given order.
+// Ties fall back to arrival order.
+func (o QueueOrder) before(a, b *queueItem) bool {
+	switch o {
+	case QueueNewestFirst:
+		if !a.modTime.Equal(b.modTime) {
+			return a.modTime.After(b.modTime)
+		}
+	case QueueSmallestFirst:
+		if a.size != b.size {
+			return a.size < b.size
+		}
+	}
+	return a.seq < b.seq
+}
+
+// sortPaths orders paths by the policy, keeping the input order for ties.
+func (o QueueOrder) sortPaths(paths []string) []string {
+	items := make([]*queueItem, len(paths))
+	for i, path := range paths {
+		items[i] = newQueueItem(path)
+		items[i].seq = uint64(i)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return o.before(items[i], items[j]) })
+
+	sorted := make([]string, len(items))
+	for i, item := range items {
+		sorted[i] = item.path
+	}
+	return sorted
+}
+
+// itemHeap implements heap.Interface over waiting items.
+type itemHeap struct {
+	items []*queueItem
+	order QueueOrder
+}
+
+func (h *itemHeap) Len() int           { return len(h.items) }
+func (h *itemHeap) Less(i, j int) bool { return h.order.before(h.items[i], h.items[j]) }
+
+func (h *itemHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *itemHeap) Push(x any) {
+	item := x.(*queueItem)
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *itemHeap) Pop() any {
+	old := h.items
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	item.index = -1
+	h.items = old[:len(old)-1]
+	return item
+}
+
+// workQueue limits how many files are processed at once and hands free
+// worker slots to waiting files in policy order.
+type workQueue struct {
+	mu      sync.Mutex
+	free    int
+	seq     uint64
+	waiting itemHeap
+}
+
+// newWorkQueue creates a queue with the given number of worker slots.
+func newWorkQueue(workers int, order QueueOrder) *workQueue {
+	return &workQueue{
+		free:    workers,
+		waiting: itemHeap{order: order},
+	}
+}
+
+// tryAcquire takes a worker slot if one is free and nothing is waiting.
+func (q *workQueue) tryAcquire() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.free > 0 && q.waiting.Len() == 0 {
+		q.free--
+		return true
+	}
+	return false
+}
+
+// acquire blocks until item is granted a worker slot or ctx is cancelled.
+// Every successful acquire must be paired with a release.
+func (q *workQueue) acquire(ctx context.Context, item *queueItem) error {
+	q.mu.Lock()
+	if q.free > 0 && q.waiting.Len() == 0 {
+		q.free--
+		q.mu.Unlock()
+		return nil
+	}
+	q.seq++
+	item.seq = q.seq
+	heap.Push(&q.waiting, item)
+	q.mu.Unlock()
+
+	select {
+	case <-item.ready:
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		if item.index >= 0 {
+			heap.Remove(&q.waiting, item.index)
+			q.mu.Unlock()
+			return ctx.Err()
+		}
+		q.mu.Unlock()
+		// The slot was granted while cancelling; pass it on
+		q.release()
+		return ctx.Err()
+	}
+}
+
+// release frees a worker slot, handing it to the next waiting item if any.
+func (q *workQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.waiting.Len() > 0 {
+		item := heap.Pop(&q.waiting).(*queueItem)
+		close(item.ready)
+		return
+	}
+	q.free++
+}
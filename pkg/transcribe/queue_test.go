@@ -0,0 +1,148 @@
+package transcribe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueueOrder_Valid(t *testing.T) {
+	tests := []struct {
+		order QueueOrder
+		valid bool
+	}{
+		{QueueFIFO, true},
+		{QueueNewestFirst, true},
+		{QueueSmallestFirst, true},
+		{"largest_first", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.order.Valid(); got != tt.valid {
+			t.Errorf("Valid(%q): expected %v, got %v", tt.order, tt.valid, got)
+		}
+	}
+}
+
+// setupQueueFiles creates files with distinct sizes and modification times:
+// old.m4a is the oldest and largest, new.m4a the newest and mid-sized.
+func setupQueueFiles(t *testing.T) (oldest, middle, newest string) {
+	t.Helper()
+	dir := t.TempDir()
+	now := time.Now()
+
+	files := []struct {
+		name string
+		size int
+		age  time.Duration
+	}{
+		{"old.m4a", 300, 48 * time.Hour},
+		{"mid.m4a", 100, 24 * time.Hour},
+		{"new.m4a", 200, time.Hour},
+	}
+	var paths []string
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		os.WriteFile(path, make([]byte, f.size), 0644)
+		mtime := now.Add(-f.age)
+		os.Chtimes(path, mtime, mtime)
+		paths = append(paths, path)
+	}
+	return paths[0], paths[1], paths[2]
+}
+
+func TestQueueOrder_SortPaths(t *testing.T) {
+	oldest, middle, newest := setupQueueFiles(t)
+	paths := []string{oldest, middle, newest}
+
+	tests := []struct {
+		order    QueueOrder
+		expected []string
+	}{
+		{QueueFIFO, []string{oldest, middle, newest}},
+		{QueueNewestFirst, []string{newest, middle, oldest}},
+		{QueueSmallestFirst, []string{middle, newest, oldest}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			got := tt.order.sortPaths(paths)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWorkQueue_GrantsInPolicyOrder(t *testing.T) {
+	oldest, middle, newest := setupQueueFiles(t)
+
+	q := newWorkQueue(1, QueueNewestFirst)
+	if !q.tryAcquire() {
+		t.Fatal("expected free slot")
+	}
+
+	granted := make(chan string, 3)
+	for _, path := range []string{oldest, middle, newest} {
+		item := newQueueItem(path)
+		go func() {
+			if err := q.acquire(context.Background(), item); err != nil {
+				t.Errorf("acquire failed: %v", err)
+				return
+			}
+			granted <- item.path
+		}()
+	}
+
+	// Wait for all three to be queued
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		q.mu.Lock()
+		n := q.waiting.Len()
+		q.mu.Unlock()
+		if n == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for items to queue")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	var order []string
+	for i := 0; i < 3; i++ {
+		q.release()
+		order = append(order, <-granted)
+	}
+
+	expected := []string{newest, middle, oldest}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %v, got %v", expected, order)
+	}
+}
+
+func TestWorkQueue_CancelWhileWaiting(t *testing.T) {
+	q := newWorkQueue(1, QueueFIFO)
+	if !q.tryAcquire() {
+		t.Fatal("expected free slot")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- q.acquire(ctx, newQueueItem("/missing.m4a")) }()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+
+	q.release()
+	if !q.tryAcquire() {
+		t.Error("expected slot to be free after cancelled waiter was removed")
+	}
+}
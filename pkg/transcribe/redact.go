@@ -0,0 +1,75 @@
+package transcribe
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/redact"
+)
+
+// RedactConfig removes personal data and sensitive terms from transcripts
+// before notes and subtitles are written, titled or tagged. Only the number of
+// redactions is logged, never the redacted text.
+type RedactConfig struct {
+	// Types lists the built-in kinds of personal data to remove: email,
+	// phone and credit_card.
+	Types []string `json:"types,omitempty"`
+	// Terms are words or phrases to remove, matched case-insensitively as
+	// whole words, e.g. client or project names.
+	Terms []string `json:"terms,omitempty"`
+	// Patterns are regular expressions whose matches are removed.
+	Patterns []string `json:"patterns,omitempty"`
+	// Replacement is the text put in place of each match; "[redacted]" when
+	// empty.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// redactor compiles the configured rules.
+func (c RedactConfig) redactor() (*redact.Redactor, error) {
+	r, err := redact.New(c.Types, c.Terms, c.Patterns, c.Replacement)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRedaction, err)
+	}
+	return r, nil
+}
+
+// redact applies the configured redaction to result, logging how many
+// matches of each kind were replaced. It returns result unchanged when no
+// redaction is configured.
+func (s *Service) redact(fileLogger Logger, path string, result *TranscriptionResult) *TranscriptionResult {
+	if s.redactor == nil {
+		return result
+	}
+
+	text, counts := s.redactor.Redact(result.Text)
+	if len(counts) == 0 {
+		return result
+	}
+
+	kinds := make([]string, 0, len(counts))
+	total := 0
+	for kind, n := range counts {
+		kinds = append(kinds, kind)
+		total += n
+	}
+	sort.Strings(kinds)
+	fields := []logging.Field{
+		logging.String("path", path),
+		logging.Int("redactions", total),
+	}
+	for _, kind := range kinds {
+		fields = append(fields, logging.Int(kind, counts[kind]))
+	}
+	fileLogger.Info("transcript redacted", fields...)
+
+	// Segments hold the same text, so only the transcript's counts are logged
+	redacted := *result
+	redacted.Text = text
+	redacted.Segments = make([]Segment, len(result.Segments))
+	for i, seg := range result.Segments {
+		seg.Text, _ = s.redactor.Redact(seg.Text)
+		redacted.Segments[i] = seg
+	}
+	return &redacted
+}
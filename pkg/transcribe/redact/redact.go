@@ -0,0 +1,158 @@
+// Package redact removes personal data and configured terms from
+// transcripts before they are written to the vault.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Built-in types of personal data.
+const (
+	Email      = "email"
+	Phone      = "phone"
+	CreditCard = "credit_card"
+)
+
+// Names under which custom terms and patterns are counted.
+const (
+	Term    = "term"
+	Pattern = "pattern"
+)
+
+// DefaultReplacement replaces redacted text when no replacement is set.
+const DefaultReplacement = "[redacted]"
+
+// builtins are the patterns for each built-in type. Credit cards come
+// before phone numbers so card numbers are not counted as phones.
+var builtins = []struct {
+	name  string
+	re    *regexp.Regexp
+	valid func(match string) bool
+}{
+	{CreditCard, regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), luhn},
+	{Email, regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b|\b[a-z0-9._%+-]+ at [a-z0-9-]+(?: dot [a-z]{2,})+\b`), nil},
+	{Phone, regexp.MustCompile(`\+?\(?\d[\d ().-]{6,}\d`), phoneDigits},
+}
+
+// Types returns the built-in types in the order they are applied.
+func Types() []string {
+	types := make([]string, len(builtins))
+	for i, b := range builtins {
+		types[i] = b.name
+	}
+	return types
+}
+
+// rule is one pattern and the name its matches are counted under.
+type rule struct {
+	name  string
+	re    *regexp.Regexp
+	valid func(match string) bool
+}
+
+// Redactor replaces matches of its rules with Replacement.
+type Redactor struct {
+	rules       []rule
+	replacement string
+}
+
+// New returns a redactor for the given built-in types, literal terms
+// (matched case-insensitively as whole words) and regular expressions.
+// An empty replacement means DefaultReplacement.
+func New(types, terms, patterns []string, replacement string) (*Redactor, error) {
+	if replacement == "" {
+		replacement = DefaultReplacement
+	}
+	r := &Redactor{replacement: replacement}
+
+	for _, t := range types {
+		found := false
+		for _, b := range builtins {
+			if b.name == t {
+				r.rules = append(r.rules, rule{b.name, b.re, b.valid})
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown type %q (supported: %s)", t, strings.Join(Types(), ", "))
+		}
+	}
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		expr := `(?i)` + wordBoundary(term[0]) + regexp.QuoteMeta(term) + wordBoundary(term[len(term)-1])
+		r.rules = append(r.rules, rule{Term, regexp.MustCompile(expr), nil})
+	}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", p, err)
+		}
+		r.rules = append(r.rules, rule{Pattern, re, nil})
+	}
+	return r, nil
+}
+
+// Redact returns text with every match replaced, and the number of
+// replacements per type, term or pattern.
+func (r *Redactor) Redact(text string) (string, map[string]int) {
+	counts := make(map[string]int)
+	for _, rl := range r.rules {
+		text = rl.re.ReplaceAllStringFunc(text, func(match string) string {
+			if rl.valid != nil && !rl.valid(match) {
+				return match
+			}
+			counts[rl.name]++
+			return r.replacement
+		})
+	}
+	return text, counts
+}
+
+// wordBoundary returns `\b` when c is an ASCII word character, so terms
+// match whole words only. Other characters, for which `\b` would never
+// match, get no boundary.
+func wordBoundary(c byte) string {
+	if c == '_' || (c >= '0' && c <= '9') || (c|0x20 >= 'a' && c|0x20 <= 'z') {
+		return `\b`
+	}
+	return ""
+}
+
+// luhn reports whether the digits in s pass the Luhn checksum used by card
+// numbers.
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+// phoneDigits reports whether s has as many digits as a phone number,
+// which excludes dates and times.
+func phoneDigits(s string) bool {
+	n := 0
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			n++
+		}
+	}
+	return n >= 9 && n <= 15
+}
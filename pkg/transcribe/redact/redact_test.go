@@ -0,0 +1,82 @@
+package redact
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactor_Builtins(t *testing.T) {
+	r, err := New(Types(), nil, nil, "")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+		counts   map[string]int
+	}{
+		{
+			"email",
+			"Mail jane.doe@example.com or bob at example dot co dot uk today.",
+			"Mail [redacted] or [redacted] today.",
+			map[string]int{Email: 2},
+		},
+		{
+			"phone",
+			"Call +44 20 7946 0958 or (555) 123-4567 before 2026-01-22 at 10:30.",
+			"Call [redacted] or [redacted] before 2026-01-22 at 10:30.",
+			map[string]int{Phone: 2},
+		},
+		{
+			"credit card",
+			"The card is 4111 1111 1111 1111, not 1234 5678 9012 3456.",
+			"The card is [redacted], not 1234 5678 9012 3456.",
+			map[string]int{CreditCard: 1},
+		},
+		{
+			"nothing",
+			"Buy milk and 12 eggs.",
+			"Buy milk and 12 eggs.",
+			map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, counts := r.Redact(tt.text)
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			if !reflect.DeepEqual(counts, tt.counts) {
+				t.Errorf("expected counts %v, got %v", tt.counts, counts)
+			}
+		})
+	}
+}
+
+func TestRedactor_TermsAndPatterns(t *testing.T) {
+	r, err := New(nil, []string{"Project Falcon", "Müller", " "}, []string{`ACME-\d+`}, "███")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	got, counts := r.Redact("project falcon is late; Müller filed ACME-1234. Falconry is fine.")
+	expected := "███ is late; ███ filed ███. Falconry is fine."
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	if !reflect.DeepEqual(counts, map[string]int{Term: 2, Pattern: 1}) {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	if _, err := New([]string{"ssn"}, nil, nil, ""); err == nil {
+		t.Error("expected error for unknown type")
+	}
+	if _, err := New(nil, nil, []string{"("}, ""); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
@@ -0,0 +1,90 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fieldLogger records messages with their fields.
+type fieldLogger struct {
+	lines []string
+}
+
+func (l *fieldLogger) Info(msg string, fields ...Field)             { l.add(msg, fields) }
+func (l *fieldLogger) Error(msg string, err error, fields ...Field) { l.add(msg, fields) }
+func (l *fieldLogger) Debug(msg string, fields ...Field)            { l.add(msg, fields) }
+
+func (l *fieldLogger) add(msg string, fields []Field) {
+	line := msg
+	for _, f := range fields {
+		line += fmt.Sprintf(" %s=%v", f.Key, f.Value)
+	}
+	l.lines = append(l.lines, line)
+}
+
+// piiClient returns a transcript containing personal data.
+type piiClient struct{}
+
+func (piiClient) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	return &TranscriptionResult{Text: "Email jane@example.com about Project Falcon, or call 555-123-4567.", Language: "en"}, nil
+}
+
+func TestService_RedactsTranscript(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.Redact = &RedactConfig{Types: []string{"email", "phone"}, Terms: []string{"project falcon"}}
+
+	logger := &fieldLogger{}
+	svc, err := NewServiceWith(cfg, Options{Watcher: &fakeWatcher{}, Client: piiClient{}, Logger: logger})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	result, err := svc.transcribe(context.Background(), logger, "/sync/memo.m4a")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	expected := "Email [redacted] about [redacted], or call [redacted]."
+	if result.Text != expected {
+		t.Errorf("expected %q, got %q", expected, result.Text)
+	}
+
+	logged := strings.Join(logger.lines, "\n")
+	if !strings.Contains(logged, "transcript redacted path=/sync/memo.m4a redactions=3 email=1 phone=1 term=1") {
+		t.Errorf("expected redaction counts to be logged, got:\n%s", logged)
+	}
+	for _, secret := range []string{"jane@example.com", "Falcon", "4567"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("expected %q not to be logged, got:\n%s", secret, logged)
+		}
+	}
+}
+
+func TestConfig_ValidateRedact(t *testing.T) {
+	tests := []struct {
+		name    string
+		redact  RedactConfig
+		wantErr bool
+	}{
+		{"builtins", RedactConfig{Types: []string{"email", "phone", "credit_card"}}, false},
+		{"terms only", RedactConfig{Terms: []string{"Acme"}}, false},
+		{"unknown type", RedactConfig{Types: []string{"ssn"}}, true},
+		{"bad pattern", RedactConfig{Patterns: []string{"[a-"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{WatchDir: "/tmp/watch", APIURL: "http://localhost:9000", OutputDir: "/tmp/out", Redact: &tt.redact}
+			cfg.ApplyDefaults()
+			err := cfg.Validate()
+			if tt.wantErr && !errors.Is(err, ErrInvalidRedaction) {
+				t.Errorf("expected ErrInvalidRedaction, got: %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+// Package remote polls object storage or a WebDAV directory for new
+// recordings and downloads them so the transcription pipeline can process
+// them like files from a watched directory.
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/watcher"
+)
+
+// Object is a file in a remote store.
+type Object struct {
+	// Key identifies the object within the store: the S3 key or the WebDAV
+	// path.
+	Key  string
+	Size int64
+	// ETag changes when the object's contents change. Stores that do not
+	// report one leave it empty.
+	ETag string
+}
+
+// Name returns the object's file name, the last element of its key.
+func (o Object) Name() string {
+	return path.Base(o.Key)
+}
+
+// Store is a remote location holding recordings.
+type Store interface {
+	// List returns the files in the store.
+	List(ctx context.Context) ([]Object, error)
+	// Fetch writes the object's contents to w.
+	Fetch(ctx context.Context, obj Object, w io.Writer) error
+	// Delete removes the object from the store.
+	Delete(ctx context.Context, obj Object) error
+}
+
+// SeenFile is the file in the download directory recording which objects
+// have already been downloaded, so restarts do not download them again.
+const SeenFile = ".nota-remote-seen.json"
+
+// DefaultInterval is how often the store is listed when Interval is not set.
+const DefaultInterval = time.Minute
+
+// Poller periodically lists a store and downloads new objects whose names
+// match Patterns into Dir.
+type Poller struct {
+	Store    Store
+	Dir      string
+	Patterns []string
+	Interval time.Duration
+	// DeleteAfterDownload removes each object from the store once it has
+	// been downloaded.
+	DeleteAfterDownload bool
+	// OnDownload, if set, is called after each object is downloaded.
+	OnDownload func(obj Object, path string)
+	// OnError, if set, is called when listing, downloading or deleting
+	// fails. The object is retried on the next poll.
+	OnError func(err error)
+	// DryRun only lists the store: new objects are passed to
+	// OnWouldDownload, once each, and nothing is downloaded, deleted or
+	// written to Dir.
+	DryRun          bool
+	OnWouldDownload func(obj Object)
+
+	// seen maps downloaded keys to their ETag.
+	seen map[string]string
+}
+
+// Poll creates Dir and starts polling, returning the downloaded files as
+// events. The channel is closed when ctx is cancelled. A dry run sends no
+// events.
+func (p *Poller) Poll(ctx context.Context, buffer int) (<-chan watcher.FileEvent, error) {
+	if !p.DryRun {
+		if err := os.MkdirAll(p.Dir, 0755); err != nil {
+			return nil, fmt.Errorf("create download directory: %w", err)
+		}
+	}
+	p.seen = p.loadSeen()
+
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	events := make(chan watcher.FileEvent, buffer)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			p.poll(ctx, events)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return events, nil
+}
+
+// poll downloads every new matching object and sends an event for each.
+func (p *Poller) poll(ctx context.Context, events chan<- watcher.FileEvent) {
+	objects, err := p.Store.List(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.reportError(fmt.Errorf("list remote files: %w", err))
+		}
+		return
+	}
+
+	listed := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		listed[obj.Key] = true
+		if !p.matches(obj.Name()) {
+			continue
+		}
+		if etag, ok := p.seen[obj.Key]; ok && etag == obj.ETag {
+			continue
+		}
+		if p.DryRun {
+			p.wouldDownload(obj)
+			continue
+		}
+
+		event, err := p.download(ctx, obj)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			p.reportError(err)
+			continue
+		}
+		if event == nil {
+			continue
+		}
+
+		select {
+		case events <- *event:
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	if p.DryRun {
+		return
+	}
+
+	// Forget objects no longer in the store so the seen file does not grow
+	// without bound.
+	changed := false
+	for key := range p.seen {
+		if !listed[key] {
+			delete(p.seen, key)
+			changed = true
+		}
+	}
+	if changed {
+		p.saveSeen()
+	}
+}
+
+// download fetches obj into Dir and records it as seen. It returns nil
+// without downloading when a file of the same name is still waiting in Dir,
+// leaving the object to be fetched on a later poll.
+func (p *Poller) download(ctx context.Context, obj Object) (*watcher.FileEvent, error) {
+	dest := filepath.Join(p.Dir, obj.Name())
+	if _, err := os.Stat(dest); err == nil {
+		return nil, nil
+	}
+
+	tmp := filepath.Join(p.Dir, "."+obj.Name()+".part")
+	f, err := os.Create(tmp)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", obj.Key, err)
+	}
+	if err := p.Store.Fetch(ctx, obj, f); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return nil, fmt.Errorf("download %s: %w", obj.Key, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("download %s: %w", obj.Key, err)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("download %s: %w", obj.Key, err)
+	}
+
+	p.seen[obj.Key] = obj.ETag
+	p.saveSeen()
+
+	if p.OnDownload != nil {
+		p.OnDownload(obj, dest)
+	}
+	if p.DeleteAfterDownload {
+		if err := p.Store.Delete(ctx, obj); err != nil {
+			p.reportError(fmt.Errorf("delete %s after download: %w", obj.Key, err))
+		}
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", obj.Key, err)
+	}
+	return &watcher.FileEvent{Path: dest, Size: info.Size(), Timestamp: time.Now()}, nil
+}
+
+// wouldDownload passes obj to OnWouldDownload, if set, unless it already
+// was. The object is recorded in memory only, so the seen file is left as
+// it was.
+func (p *Poller) wouldDownload(obj Object) {
+	if etag, ok := p.seen[obj.Key]; ok && etag == obj.ETag {
+		return
+	}
+	p.seen[obj.Key] = obj.ETag
+	if p.OnWouldDownload != nil {
+		p.OnWouldDownload(obj)
+	}
+}
+
+// matches reports whether name matches any of Patterns.
+func (p *Poller) matches(name string) bool {
+	for _, pattern := range p.Patterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// reportError passes err to OnError, if set.
+func (p *Poller) reportError(err error) {
+	if p.OnError != nil {
+		p.OnError(err)
+	}
+}
+
+// loadSeen reads the seen file; a missing or unreadable file starts empty.
+func (p *Poller) loadSeen() map[string]string {
+	seen := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(p.Dir, SeenFile))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			p.reportError(fmt.Errorf("read %s: %w", SeenFile, err))
+		}
+		return seen
+	}
+	if err := json.Unmarshal(data, &seen); err != nil {
+		p.reportError(fmt.Errorf("read %s: %w", SeenFile, err))
+		return make(map[string]string)
+	}
+	return seen
+}
+
+// saveSeen writes the seen file.
+func (p *Poller) saveSeen() {
+	data, err := json.MarshalIndent(p.seen, "", "  ")
+	if err != nil {
+		p.reportError(fmt.Errorf("write %s: %w", SeenFile, err))
+		return
+	}
+	if err := os.WriteFile(filepath.Join(p.Dir, SeenFile), data, 0644); err != nil {
+		p.reportError(fmt.Errorf("write %s: %w", SeenFile, err))
+	}
+}
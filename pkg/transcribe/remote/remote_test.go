@@ -0,0 +1,225 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/watcher"
+)
+
+// fakeStore serves objects from memory.
+type fakeStore struct {
+	mu       sync.Mutex
+	objects  map[string]string
+	etags    map[string]string
+	deleted  []string
+	fetched  []string
+	fetchErr error
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{objects: make(map[string]string), etags: make(map[string]string)}
+}
+
+func (f *fakeStore) put(key, contents, etag string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[key] = contents
+	f.etags[key] = etag
+}
+
+func (f *fakeStore) List(ctx context.Context) ([]Object, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var objects []Object
+	for key, contents := range f.objects {
+		objects = append(objects, Object{Key: key, Size: int64(len(contents)), ETag: f.etags[key]})
+	}
+	return objects, nil
+}
+
+func (f *fakeStore) Fetch(ctx context.Context, obj Object, w io.Writer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetched = append(f.fetched, obj.Key)
+	if f.fetchErr != nil {
+		return f.fetchErr
+	}
+	_, err := io.WriteString(w, f.objects[obj.Key])
+	return err
+}
+
+func (f *fakeStore) Delete(ctx context.Context, obj Object) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.objects, obj.Key)
+	f.deleted = append(f.deleted, obj.Key)
+	return nil
+}
+
+func TestPoller_DryRunOnlyLists(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "downloads")
+	store := newFakeStore()
+	store.put("phone/memo.m4a", "audio", `"1"`)
+	store.put("phone/notes.txt", "text", `"2"`)
+
+	var would []string
+	p := &Poller{
+		Store:               store,
+		Dir:                 dir,
+		Patterns:            []string{"*.m4a"},
+		DeleteAfterDownload: true,
+		DryRun:              true,
+		OnWouldDownload:     func(obj Object) { would = append(would, obj.Key) },
+	}
+	p.seen = p.loadSeen()
+	events := make(chan watcher.FileEvent, 10)
+	p.poll(context.Background(), events)
+	p.poll(context.Background(), events)
+
+	if len(would) != 1 || would[0] != "phone/memo.m4a" {
+		t.Errorf("expected memo.m4a reported once, got %v", would)
+	}
+	if len(events) != 0 {
+		t.Errorf("expected no events, got %d", len(events))
+	}
+	if len(store.fetched) != 0 || len(store.deleted) != 0 {
+		t.Errorf("expected no fetches or deletes, got %v and %v", store.fetched, store.deleted)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("expected the download directory left alone, got: %v", err)
+	}
+}
+
+func TestPoller_DownloadsMatchingObjectsOnce(t *testing.T) {
+	dir := t.TempDir()
+	store := newFakeStore()
+	store.put("phone/memo.m4a", "audio", `"1"`)
+	store.put("phone/notes.txt", "text", `"2"`)
+
+	p := &Poller{Store: store, Dir: dir, Patterns: []string{"*.m4a"}}
+	p.seen = p.loadSeen()
+	events := make(chan watcher.FileEvent, 10)
+	p.poll(context.Background(), events)
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	event := <-events
+	if event.Path != filepath.Join(dir, "memo.m4a") || event.Size != 5 {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	data, err := os.ReadFile(event.Path)
+	if err != nil || string(data) != "audio" {
+		t.Fatalf("expected downloaded contents, got %q (%v)", data, err)
+	}
+
+	// Once processed (archived), the file is gone but must not be fetched again,
+	// even by a new poller after a restart.
+	os.Remove(event.Path)
+	p = &Poller{Store: store, Dir: dir, Patterns: []string{"*.m4a"}}
+	p.seen = p.loadSeen()
+	p.poll(context.Background(), events)
+	if len(events) != 0 {
+		t.Errorf("expected seen object to be skipped, got %d events", len(events))
+	}
+
+	// A changed object is downloaded again.
+	store.put("phone/memo.m4a", "audio v2", `"3"`)
+	p.poll(context.Background(), events)
+	if len(events) != 1 {
+		t.Errorf("expected changed object to be downloaded, got %d events", len(events))
+	}
+}
+
+func TestPoller_WaitsForPendingLocalFile(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "memo.m4a"), []byte("earlier"), 0644)
+	store := newFakeStore()
+	store.put("memo.m4a", "audio", `"1"`)
+
+	p := &Poller{Store: store, Dir: dir, Patterns: []string{"*.m4a"}}
+	p.seen = p.loadSeen()
+	events := make(chan watcher.FileEvent, 10)
+	p.poll(context.Background(), events)
+
+	if len(events) != 0 {
+		t.Fatalf("expected no event while a file of the same name is pending, got %d", len(events))
+	}
+	if _, ok := p.seen["memo.m4a"]; ok {
+		t.Error("expected object not to be marked seen")
+	}
+}
+
+func TestPoller_DeleteAfterDownloadAndErrors(t *testing.T) {
+	dir := t.TempDir()
+	store := newFakeStore()
+	store.put("memo.m4a", "audio", `"1"`)
+
+	var errs []error
+	p := &Poller{Store: store, Dir: dir, Patterns: []string{"*.m4a"}, DeleteAfterDownload: true,
+		OnError: func(err error) { errs = append(errs, err) }}
+	p.seen = p.loadSeen()
+	events := make(chan watcher.FileEvent, 10)
+
+	store.fetchErr = errors.New("connection reset")
+	p.poll(context.Background(), events)
+	if len(events) != 0 || len(errs) != 1 {
+		t.Fatalf("expected a reported error and no event, got %d events, errors %v", len(events), errs)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, ".*.part")); len(matches) != 0 {
+		t.Errorf("expected partial download to be removed, found %v", matches)
+	}
+
+	store.fetchErr = nil
+	p.poll(context.Background(), events)
+	if len(events) != 1 {
+		t.Fatalf("expected download on retry, got %d events", len(events))
+	}
+	if len(store.deleted) != 1 || store.deleted[0] != "memo.m4a" {
+		t.Errorf("expected object to be deleted, got %v", store.deleted)
+	}
+
+	// The deleted object drops out of the seen file on the next poll.
+	p.poll(context.Background(), events)
+	if len(p.seen) != 0 {
+		t.Errorf("expected seen entries to be pruned, got %v", p.seen)
+	}
+}
+
+func TestPoller_PollClosesEventsOnCancel(t *testing.T) {
+	store := newFakeStore()
+	store.put("memo.m4a", "audio", `"1"`)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := &Poller{Store: store, Dir: filepath.Join(t.TempDir(), "downloads"), Patterns: []string{"*.m4a"}, Interval: time.Hour}
+	events, err := p.Poll(ctx, 10)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	select {
+	case event := <-events:
+		if filepath.Base(event.Path) != "memo.m4a" {
+			t.Errorf("unexpected event: %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the first poll")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("expected no further events")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("events channel not closed after cancel")
+	}
+}
@@ -0,0 +1,38 @@
+package remote
+
+import (
+	"context"
+	"io"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/s3"
+)
+
+// S3Store is an S3 bucket, or the part of it under Prefix.
+type S3Store struct {
+	Client *s3.Client
+	Bucket string
+	Prefix string
+}
+
+// List returns the objects under Prefix.
+func (s *S3Store) List(ctx context.Context) ([]Object, error) {
+	listed, err := s.Client.List(ctx, s.Bucket, s.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]Object, 0, len(listed))
+	for _, o := range listed {
+		objects = append(objects, Object{Key: o.Key, Size: o.Size, ETag: o.ETag})
+	}
+	return objects, nil
+}
+
+// Fetch downloads the object.
+func (s *S3Store) Fetch(ctx context.Context, obj Object, w io.Writer) error {
+	return s.Client.Get(ctx, s.Bucket, obj.Key, w)
+}
+
+// Delete removes the object from the bucket.
+func (s *S3Store) Delete(ctx context.Context, obj Object) error {
+	return s.Client.Delete(ctx, s.Bucket, obj.Key)
+}
@@ -0,0 +1,164 @@
+package remote
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// propfindBody requests the properties List needs.
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:resourcetype/><d:getcontentlength/><d:getetag/></d:prop></d:propfind>`
+
+// WebDAVStore is a directory on a WebDAV server, such as Nextcloud. Only
+// files directly inside the directory are listed.
+type WebDAVStore struct {
+	// URL is the directory's URL.
+	URL      string
+	Username string
+	Password string
+	// HTTPClient is used for requests; nil means http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// multistatus is the PROPFIND response.
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Status string `xml:"status"`
+			Prop   struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+				ContentLength string `xml:"getcontentlength"`
+				ETag          string `xml:"getetag"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// List returns the files in the directory. Object keys are the files'
+// paths on the server.
+func (s *WebDAVStore) List(ctx context.Context) ([]Object, error) {
+	req, err := s.request(ctx, "PROPFIND", s.URL, strings.NewReader(propfindBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Depth", "1")
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("PROPFIND %s: unexpected status %s", s.URL, resp.Status)
+	}
+
+	var ms multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("PROPFIND %s: parse response: %w", s.URL, err)
+	}
+
+	var objects []Object
+	for _, r := range ms.Responses {
+		key, err := url.PathUnescape(r.Href)
+		if err != nil {
+			continue
+		}
+		if u, err := url.Parse(key); err == nil && u.IsAbs() {
+			key = u.Path
+		}
+		for _, ps := range r.Propstat {
+			if !strings.Contains(ps.Status, " 200 ") || ps.Prop.ResourceType.Collection != nil {
+				continue
+			}
+			size, _ := strconv.ParseInt(ps.Prop.ContentLength, 10, 64)
+			objects = append(objects, Object{Key: key, Size: size, ETag: ps.Prop.ETag})
+		}
+	}
+	return objects, nil
+}
+
+// Fetch downloads the file.
+func (s *WebDAVStore) Fetch(ctx context.Context, obj Object, w io.Writer) error {
+	u, err := s.fileURL(obj)
+	if err != nil {
+		return err
+	}
+	req, err := s.request(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", u, resp.Status)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// Delete removes the file from the server.
+func (s *WebDAVStore) Delete(ctx context.Context, obj Object) error {
+	u, err := s.fileURL(obj)
+	if err != nil {
+		return err
+	}
+	req, err := s.request(ctx, http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("DELETE %s: unexpected status %s", u, resp.Status)
+	}
+	return nil
+}
+
+// fileURL returns the URL of obj on the same server as the directory.
+func (s *WebDAVStore) fileURL(obj Object) (string, error) {
+	base, err := url.Parse(s.URL)
+	if err != nil {
+		return "", err
+	}
+	u := *base
+	u.Path = path.Clean(obj.Key)
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// request builds a request with basic authentication when a username is set.
+func (s *WebDAVStore) request(ctx context.Context, method, u string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if s.Username != "" {
+		req.SetBasicAuth(s.Username, s.Password)
+	}
+	return req, nil
+}
+
+// client returns the HTTP client to use.
+func (s *WebDAVStore) client() *http.Client {
+	if s.HTTPClient != nil {
+		return s.HTTPClient
+	}
+	return http.DefaultClient
+}
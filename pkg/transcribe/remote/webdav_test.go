@@ -0,0 +1,89 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebDAVStore_ListFetchDelete(t *testing.T) {
+	var deleted string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "shaun" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == "PROPFIND" && r.URL.Path == "/dav/Recordings/":
+			if r.Header.Get("Depth") != "1" {
+				t.Errorf("expected Depth: 1, got %q", r.Header.Get("Depth"))
+			}
+			w.WriteHeader(http.StatusMultiStatus)
+			fmt.Fprint(w, `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:">
+  <d:response><d:href>/dav/Recordings/</d:href>
+    <d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  </d:response>
+  <d:response><d:href>/dav/Recordings/Voice%20034.m4a</d:href>
+    <d:propstat><d:prop><d:resourcetype/><d:getcontentlength>5</d:getcontentlength><d:getetag>"abc"</d:getetag></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  </d:response>
+  <d:response><d:href>/dav/Recordings/Old/</d:href>
+    <d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  </d:response>
+</d:multistatus>`)
+		case r.Method == http.MethodGet && r.URL.Path == "/dav/Recordings/Voice 034.m4a":
+			w.Write([]byte("audio"))
+		case r.Method == http.MethodDelete:
+			deleted = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	store := &WebDAVStore{URL: server.URL + "/dav/Recordings/", Username: "shaun", Password: "secret"}
+	ctx := context.Background()
+
+	objects, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(objects) != 1 {
+		t.Fatalf("expected 1 file, got %+v", objects)
+	}
+	obj := objects[0]
+	if obj.Key != "/dav/Recordings/Voice 034.m4a" || obj.Name() != "Voice 034.m4a" || obj.Size != 5 || obj.ETag != `"abc"` {
+		t.Errorf("unexpected object: %+v", obj)
+	}
+
+	var buf bytes.Buffer
+	if err := store.Fetch(ctx, obj, &buf); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if buf.String() != "audio" {
+		t.Errorf("expected file contents, got %q", buf.String())
+	}
+
+	if err := store.Delete(ctx, obj); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if deleted != "/dav/Recordings/Voice 034.m4a" {
+		t.Errorf("expected file to be deleted, got %q", deleted)
+	}
+}
+
+func TestWebDAVStore_ListError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	store := &WebDAVStore{URL: server.URL + "/dav/"}
+	if _, err := store.List(context.Background()); err == nil {
+		t.Error("expected error for unauthorized listing")
+	}
+}
@@ -0,0 +1,248 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/frontmatter"
+)
+
+// ErrAudioNotFound is returned when neither the archive path nor the source
+// path recorded in a note exists.
+var ErrAudioNotFound = errors.New("audio for note not found")
+
+// ReprocessOptions configures Service.Reprocess.
+type ReprocessOptions struct {
+	// Replace rewrites the transcript section of the existing note instead
+	// of writing a new version next to it.
+	Replace bool
+}
+
+// ReprocessResult describes a regenerated note.
+type ReprocessResult struct {
+	// Audio is the file that was transcribed.
+	Audio string
+	// Note is the note that was written or updated.
+	Note string
+	// Previous is the note that was reprocessed, or "" if the audio had no
+	// note and a new one was written.
+	Previous string
+	// Language is the language reported by the transcription API.
+	Language string
+	// Elapsed is the time spent transcribing and writing.
+	Elapsed time.Duration
+}
+
+// Reprocess transcribes the audio behind a note again, typically after
+// changing the model. path is either a note, whose frontmatter locates the
+// archived audio, or an audio file, whose note is looked up in the output
+// directories.
+//
+// By default the new transcript is written as a new version next to the
+// note, rendered the same way so the two diff cleanly. With opts.Replace the
+// note's "# Transcription" section and processing details are rewritten in
+// place. Audio without a note gets a new note in the usual output directory.
+// The audio file is never moved.
+func (s *Service) Reprocess(ctx context.Context, path string, opts ReprocessOptions) (*ReprocessResult, error) {
+	renderer, ok := s.writer.(NoteRenderer)
+	if !ok {
+		return nil, fmt.Errorf("output writer %T does not support reprocessing", s.writer)
+	}
+
+	fileLogger := s.componentLogger("reprocess")
+	startTime := time.Now()
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReprocessResult{}
+	var note string
+	var info *ProcessingInfo
+	if strings.EqualFold(filepath.Ext(path), ".md") {
+		result.Previous = path
+	} else {
+		result.Audio = path
+		result.Previous = s.findNote(path)
+	}
+
+	if result.Previous != "" {
+		content, err := os.ReadFile(result.Previous)
+		if err != nil {
+			return nil, err
+		}
+		note = string(content)
+		if info, err = writer.ParseProcessingInfo(note); err != nil {
+			return nil, fmt.Errorf("%s: %w", result.Previous, err)
+		}
+		if result.Audio == "" {
+			if result.Audio, err = locateAudio(info); err != nil {
+				return nil, fmt.Errorf("%s: %w", result.Previous, err)
+			}
+		}
+	}
+
+	if _, err := os.Stat(result.Audio); err != nil {
+		return nil, err
+	}
+
+	fileLogger.Info("reprocessing file",
+		logging.String("path", result.Audio),
+		logging.String("note", result.Previous),
+		logging.String("model", s.config.Model),
+	)
+
+	transcription, err := s.transcribe(ctx, fileLogger, result.Audio)
+	if err != nil {
+		return nil, fmt.Errorf("transcription failed: %w", err)
+	}
+	result.Language = transcription.Language
+
+	// Without a note, write a fresh one as the pipeline would
+	if result.Previous == "" {
+		event := FileEvent{Path: result.Audio, Timestamp: time.Now()}
+		writeOpts, _ := s.outputOptions(event, transcription)
+		s.describeNote(ctx, fileLogger, &writeOpts, transcription.Text)
+		writeOpts.Processing = s.processingInfo(result.Audio, "", transcription, time.Since(startTime))
+		writeOpts.Processing.SourceHash = hashFile(result.Audio)
+
+		if result.Note, err = s.writer.Write(ctx, transcription.Text, writeOpts); err != nil {
+			return nil, err
+		}
+		return s.reprocessed(fileLogger, result, startTime), nil
+	}
+
+	// Keep the original source and archive paths so the note still traces
+	// back to the same audio
+	sourcePath := info.SourcePath
+	if sourcePath == "" {
+		sourcePath = result.Audio
+	}
+	processing := s.processingInfo(sourcePath, info.ArchivePath, transcription, time.Since(startTime))
+	processing.SourceHash = hashFile(result.Audio)
+
+	var content string
+	if opts.Replace {
+		// The footer sits in the transcription section, so it is replaced
+		// along with the text
+		text := transcription.Text
+		if s.config.MetadataFooter {
+			text = writer.AppendFooter(text, processing, writer.FormatMarkdown)
+		}
+		content, err = writer.ReplaceTranscription(note, text)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", result.Previous, err)
+		}
+		content = writer.SetProcessingInfo(content, processing)
+		result.Note = result.Previous
+	} else {
+		event := FileEvent{Path: sourcePath, Timestamp: noteTimestamp(note)}
+		writeOpts, _ := s.outputOptions(event, transcription)
+		s.describeNote(ctx, fileLogger, &writeOpts, transcription.Text)
+		writeOpts.Processing = processing
+		if content, err = renderer.Render(transcription.Text, writeOpts); err != nil {
+			return nil, fmt.Errorf("render note: %w", err)
+		}
+		result.Note = nextVersionPath(result.Previous)
+	}
+
+	if err := s.perms.WriteFile(result.Note, []byte(content)); err != nil {
+		return nil, fmt.Errorf("write note: %w", err)
+	}
+	return s.reprocessed(fileLogger, result, startTime), nil
+}
+
+// reprocessed logs the completion of Reprocess and fills in the elapsed time.
+func (s *Service) reprocessed(fileLogger Logger, result *ReprocessResult, startTime time.Time) *ReprocessResult {
+	result.Elapsed = time.Since(startTime)
+	fileLogger.Info("reprocessing complete",
+		logging.String("path", result.Audio),
+		logging.String("output", result.Note),
+		logging.Duration("elapsed", result.Elapsed),
+	)
+	return result
+}
+
+// findNote returns the note in the output directories whose frontmatter
+// records audioPath as its source or archive path, or "" if there is none.
+func (s *Service) findNote(audioPath string) string {
+	found := ""
+	for _, dir := range s.outputDirs() {
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".md") {
+				return nil
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			info, err := writer.ParseProcessingInfo(string(content))
+			if err != nil || (info.SourcePath != audioPath && info.ArchivePath != audioPath) {
+				return nil
+			}
+			// Prefer the original note over earlier reprocessed versions
+			if found == "" || len(path) < len(found) {
+				found = path
+			}
+			return nil
+		})
+		if found != "" {
+			return found
+		}
+	}
+	return ""
+}
+
+// locateAudio returns the recorded archive path if it exists, and otherwise
+// the source path if the audio was never archived.
+func locateAudio(info *ProcessingInfo) (string, error) {
+	for _, path := range []string{info.ArchivePath, info.SourcePath} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", ErrAudioNotFound
+}
+
+// noteTimestamp returns the transcribed time recorded in a note, or the
+// current time if it has none.
+func noteTimestamp(note string) time.Time {
+	if fields, ok := frontmatter.Parse(note); ok {
+		if t, err := time.Parse(time.RFC3339, fields["transcribed"]); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
+
+// nextVersionPath returns the first unused "-vN" sibling of a note, starting
+// at v2: memo.md becomes memo-v2.md, and memo-v2.md becomes memo-v3.md.
+func nextVersionPath(notePath string) string {
+	ext := filepath.Ext(notePath)
+	base := strings.TrimSuffix(notePath, ext)
+	if i := strings.LastIndex(base, "-v"); i >= 0 {
+		if _, err := strconv.Atoi(base[i+2:]); err == nil {
+			base = base[:i]
+		}
+	}
+
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-v%d%s", base, n, ext)
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
+)
+
+// setupReprocessTest writes an archived audio file and the note the pipeline
+// would have written for it with the given model, and returns their paths.
+func setupReprocessTest(t *testing.T, cfg *Config) (audioPath, notePath string) {
+	t.Helper()
+	archiveDir := filepath.Join(t.TempDir(), "archive")
+	os.MkdirAll(archiveDir, 0755)
+	audioPath = filepath.Join(archiveDir, "memo.m4a")
+	os.WriteFile(audioPath, []byte("audio"), 0644)
+
+	notePath, err := writer.NewSimpleWriter().Write(context.Background(), "old transcript", OutputOptions{
+		OutputDir:  cfg.OutputDir,
+		SourceFile: "/sync/memo.m4a",
+		Timestamp:  time.Date(2026, 1, 22, 14, 30, 0, 0, time.UTC),
+		Processing: &ProcessingInfo{SourcePath: "/sync/memo.m4a", ArchivePath: audioPath, Model: "base"},
+	})
+	if err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+	return audioPath, notePath
+}
+
+func newReprocessService(t *testing.T, cfg *Config) *Service {
+	t.Helper()
+	svc, err := NewBuilder(cfg).
+		WithWatcher(&fakeWatcher{}).
+		WithClient(fakeClient{}).
+		Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	t.Cleanup(func() { svc.Close() })
+	return svc
+}
+
+func TestReprocess_NewVersion(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.Model = "large-v3"
+	audioPath, notePath := setupReprocessTest(t, cfg)
+	svc := newReprocessService(t, cfg)
+
+	result, err := svc.Reprocess(context.Background(), notePath, ReprocessOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if result.Audio != audioPath || result.Previous != notePath {
+		t.Errorf("expected audio %s from note %s, got: %+v", audioPath, notePath, result)
+	}
+	if result.Note != strings.TrimSuffix(notePath, ".md")+"-v2.md" {
+		t.Errorf("expected a -v2 note next to the original, got: %s", result.Note)
+	}
+
+	content, _ := os.ReadFile(result.Note)
+	for _, want := range []string{
+		"transcribed: 2026-01-22T14:30:00Z",
+		`source_path: "/sync/memo.m4a"`,
+		`archive_path: "` + audioPath + `"`,
+		`model: "large-v3"`,
+		"transcribed " + audioPath,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("expected new version to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	original, _ := os.ReadFile(notePath)
+	if !strings.Contains(string(original), "old transcript") {
+		t.Errorf("expected original note to be untouched, got:\n%s", original)
+	}
+	if _, err := os.Stat(audioPath); err != nil {
+		t.Errorf("expected audio to stay in the archive, got: %v", err)
+	}
+}
+
+func TestReprocess_Replace(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.Model = "large-v3"
+	audioPath, notePath := setupReprocessTest(t, cfg)
+	svc := newReprocessService(t, cfg)
+
+	result, err := svc.Reprocess(context.Background(), notePath, ReprocessOptions{Replace: true})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.Note != notePath {
+		t.Errorf("expected note to be updated in place, got: %s", result.Note)
+	}
+
+	content, _ := os.ReadFile(notePath)
+	if strings.Contains(string(content), "old transcript") || !strings.Contains(string(content), "transcribed "+audioPath) {
+		t.Errorf("expected transcript to be replaced, got:\n%s", content)
+	}
+	if strings.Count(string(content), "model:") != 1 || !strings.Contains(string(content), `model: "large-v3"`) {
+		t.Errorf("expected model to be updated once, got:\n%s", content)
+	}
+}
+
+func TestReprocess_AudioFindsNote(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	audioPath, notePath := setupReprocessTest(t, cfg)
+	svc := newReprocessService(t, cfg)
+
+	result, err := svc.Reprocess(context.Background(), audioPath, ReprocessOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.Previous != notePath {
+		t.Errorf("expected note %s to be found, got: %+v", notePath, result)
+	}
+
+	// The original note is still preferred over the version just written
+	result, err = svc.Reprocess(context.Background(), audioPath, ReprocessOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.Previous != notePath || !strings.HasSuffix(result.Note, "-v3.md") {
+		t.Errorf("expected a -v3 version of %s, got: %+v", notePath, result)
+	}
+}
+
+func TestReprocess_AudioNotFound(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	audioPath, notePath := setupReprocessTest(t, cfg)
+	os.Remove(audioPath)
+	svc := newReprocessService(t, cfg)
+
+	_, err := svc.Reprocess(context.Background(), notePath, ReprocessOptions{})
+	if !errors.Is(err, ErrAudioNotFound) {
+		t.Errorf("expected ErrAudioNotFound, got: %v", err)
+	}
+}
+
+func TestNextVersionPath(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "memo-v2.md"), nil, 0644)
+
+	tests := []struct {
+		note     string
+		expected string
+	}{
+		{"memo.md", "memo-v3.md"},
+		{"memo-v2.md", "memo-v3.md"},
+		{"standup-vacation.md", "standup-vacation-v2.md"},
+	}
+
+	for _, tt := range tests {
+		got := nextVersionPath(filepath.Join(dir, tt.note))
+		if got != filepath.Join(dir, tt.expected) {
+			t.Errorf("nextVersionPath(%s): expected %s, got %s", tt.note, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package transcribe
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
+)
+
+// ErrArchivedAudioNotFound is returned when Restore cannot find the audio
+// in the archive.
+var ErrArchivedAudioNotFound = errors.New("archived audio not found")
+
+// ErrNoRestoreTarget is returned when Restore has no target and the config
+// has no watch directory to restore into.
+var ErrNoRestoreTarget = errors.New("no watch directory to restore into, give a target path")
+
+// Restore copies archived audio back into the watch directory, or to target
+// when it is set, and returns the path of the copy. The archived file is
+// left in place.
+//
+// file is a note, whose frontmatter records the archived audio's path, the
+// path of an archived file, or an archived file's name, which is looked up
+// in archive_dir; when several recordings share the name, the most recently
+// archived one is restored. A target that is an existing directory receives
+// the file under its archived name. An existing file is never overwritten.
+func Restore(cfg *Config, file, target string) (string, error) {
+	cfg.ApplyDefaults()
+	cfg.expandPaths()
+
+	src, err := findArchived(cfg, file)
+	if err != nil {
+		return "", err
+	}
+
+	dest := target
+	if dest == "" {
+		watches := cfg.Watches()
+		if len(watches) == 0 {
+			return "", ErrNoRestoreTarget
+		}
+		dest = watches[0].Path
+	}
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		dest = filepath.Join(dest, filepath.Base(src))
+	}
+
+	perms, err := cfg.Permissions()
+	if err != nil {
+		return "", err
+	}
+	if err := perms.MkdirAll(filepath.Dir(dest)); err != nil {
+		return "", fmt.Errorf("create directory: %w", err)
+	}
+	if err := copyNew(src, dest); err != nil {
+		return "", err
+	}
+	if err := perms.ApplyFile(dest); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+// findArchived resolves Restore's file argument to an archived audio file.
+func findArchived(cfg *Config, file string) (string, error) {
+	if strings.EqualFold(filepath.Ext(file), ".md") {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		info, err := writer.ParseProcessingInfo(string(content))
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", file, err)
+		}
+		if info.ArchivePath == "" {
+			return "", fmt.Errorf("%s: %w: the note records no archive_path", file, ErrArchivedAudioNotFound)
+		}
+		if _, err := os.Stat(info.ArchivePath); err != nil {
+			return "", fmt.Errorf("%s: %w: %v", file, ErrArchivedAudioNotFound, err)
+		}
+		return info.ArchivePath, nil
+	}
+
+	if strings.ContainsRune(file, filepath.Separator) {
+		if _, err := os.Stat(file); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrArchivedAudioNotFound, err)
+		}
+		return file, nil
+	}
+
+	// Archived files live in YYYY/MM/DD directories, so the last match in
+	// walk order is the most recent
+	var matches []string
+	err := filepath.WalkDir(cfg.ArchiveDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() && d.Name() == file {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("search archive: %w", err)
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%w: no %s in %s", ErrArchivedAudioNotFound, file, cfg.ArchiveDir)
+	}
+	sort.Strings(matches)
+	return matches[len(matches)-1], nil
+}
+
+// copyNew copies src to dest, failing if dest already exists. A partial
+// copy is removed.
+func copyNew(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("%s already exists", dest)
+		}
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return fmt.Errorf("copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dest)
+		return fmt.Errorf("copy %s: %w", src, err)
+	}
+	return nil
+}
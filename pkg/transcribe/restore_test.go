@@ -0,0 +1,83 @@
+package transcribe
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// archiveFile writes an archived recording under archiveDir/day.
+func archiveFile(t *testing.T, archiveDir, day, name, content string) string {
+	t.Helper()
+	dir := filepath.Join(archiveDir, day)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return path
+}
+
+func TestRestore(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	cfg.ArchiveDir = t.TempDir()
+
+	older := archiveFile(t, cfg.ArchiveDir, "2026/01/21", "memo.m4a", "older")
+	newer := archiveFile(t, cfg.ArchiveDir, "2026/01/22", "memo.m4a", "newer")
+	note := filepath.Join(t.TempDir(), "memo.md")
+	os.WriteFile(note, []byte("---\narchive_path: \""+older+"\"\n---\n\n# Transcription\n\nBuy milk.\n"), 0644)
+
+	tests := []struct {
+		name     string
+		file     string
+		target   string
+		expected string
+		content  string
+	}{
+		{"name restores the most recent", "memo.m4a", "", filepath.Join(cfg.WatchDir, "memo.m4a"), "newer"},
+		{"note", note, t.TempDir(), "memo.m4a", "older"},
+		{"archived path to file", newer, filepath.Join(t.TempDir(), "listen.m4a"), "listen.m4a", "newer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restored, err := Restore(cfg, tt.file, tt.target)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if !strings.HasSuffix(restored, tt.expected) {
+				t.Errorf("expected %s, got %s", tt.expected, restored)
+			}
+			content, _ := os.ReadFile(restored)
+			if string(content) != tt.content {
+				t.Errorf("expected %q restored, got %q", tt.content, content)
+			}
+		})
+	}
+
+	if _, err := os.Stat(newer); err != nil {
+		t.Errorf("expected archived file to be kept, got: %v", err)
+	}
+	if _, err := Restore(cfg, "memo.m4a", ""); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected restoring over an existing file to fail, got: %v", err)
+	}
+}
+
+func TestRestore_NotFound(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.ArchiveDir = t.TempDir()
+
+	note := filepath.Join(t.TempDir(), "memo.md")
+	os.WriteFile(note, []byte("---\nsource_path: \"/sync/memo.m4a\"\n---\n\nBuy milk.\n"), 0644)
+
+	for _, file := range []string{"missing.m4a", note, filepath.Join(cfg.ArchiveDir, "2026/01/22/missing.m4a")} {
+		if _, err := Restore(cfg, file, ""); !errors.Is(err, ErrArchivedAudioNotFound) {
+			t.Errorf("expected ErrArchivedAudioNotFound for %s, got: %v", file, err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package transcribe
+
+import (
+	"context"
+	"os"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/checkpoint"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// resumeCheckpoint returns the progress an earlier run saved for path when
+// the audio has not changed since it became stable, and otherwise a fresh
+// state.
+func (s *Service) resumeCheckpoint(fileLogger Logger, path string) *checkpoint.State {
+	fresh := &checkpoint.State{Source: path}
+	if s.checkpoints == nil {
+		return fresh
+	}
+	st, err := s.checkpoints.Load(path)
+	if err != nil {
+		fileLogger.Error("failed to read pipeline checkpoint, starting over", err,
+			logging.String("path", path),
+		)
+		return fresh
+	}
+	if st == nil || !st.Stage.Reached(checkpoint.Stable) || st.Hash == "" {
+		return fresh
+	}
+	if st.Stage.Reached(checkpoint.Transcribed) && st.Transcript == nil {
+		return fresh
+	}
+	if hashFile(path) != st.Hash {
+		fileLogger.Info("audio changed since last run, starting over",
+			logging.String("path", path),
+			logging.String("stage", string(st.Stage)),
+		)
+		return fresh
+	}
+	return st
+}
+
+// advance records that the file reached stage, so a restart resumes after
+// it. Nothing is saved in dry-run mode.
+func (s *Service) advance(st *checkpoint.State, stage checkpoint.Stage) {
+	st.Stage = stage
+	st.Updated = s.clock.Now().UTC()
+	if s.dryRun || s.checkpoints == nil {
+		return
+	}
+	if err := s.checkpoints.Save(st); err != nil {
+		s.logger.Error("failed to save pipeline checkpoint", err,
+			logging.String("path", st.Source),
+			logging.String("stage", string(stage)),
+		)
+	}
+}
+
+// finishCheckpoint fills in the stage rec's file reached, unless already
+// set, and drops its saved progress once it no longer needs resuming. A
+// failed file keeps it, so retrying resumes where it stopped.
+func (s *Service) finishCheckpoint(rec *history.Record) {
+	if s.checkpoints == nil {
+		return
+	}
+	if rec.Stage == "" {
+		if st, _ := s.checkpoints.Load(rec.Source); st != nil {
+			rec.Stage = string(st.Stage)
+		}
+	}
+	if rec.Status != history.StatusFailed {
+		if err := s.checkpoints.Remove(rec.Source); err != nil {
+			s.logger.Error("failed to remove pipeline checkpoint", err,
+				logging.String("path", rec.Source),
+			)
+		}
+	}
+}
+
+// pruneCheckpoints drops saved progress for files that no longer exist,
+// such as failed files since deleted by hand.
+func (s *Service) pruneCheckpoints() {
+	if s.checkpoints == nil {
+		return
+	}
+	states, err := s.checkpoints.List()
+	if err != nil {
+		s.logger.Error("failed to list pipeline checkpoints", err)
+		return
+	}
+	for _, st := range states {
+		if _, err := os.Stat(st.Source); os.IsNotExist(err) {
+			s.checkpoints.Remove(st.Source)
+		}
+	}
+}
+
+// resumeUnfinished sends the files an earlier run left unfinished back
+// through the pipeline, so they resume at the stage they reached. The
+// watcher only reports files as they change, and would not see them again.
+func (s *Service) resumeUnfinished(ctx context.Context) {
+	if s.checkpoints == nil {
+		return
+	}
+	states, err := s.checkpoints.List()
+	if err != nil {
+		s.logger.Error("failed to list pipeline checkpoints", err)
+		return
+	}
+	for _, st := range states {
+		info, err := os.Stat(st.Source)
+		if err != nil {
+			continue
+		}
+		s.logger.Info("resuming unfinished file",
+			logging.String("path", st.Source),
+			logging.String("stage", string(st.Stage)),
+		)
+		s.handleFileEvent(ctx, FileEvent{Path: st.Source, Size: info.Size(), Timestamp: s.clock.Now()})
+	}
+}
+
+// freeArchivePath returns planned, the archive path recorded for path by an
+// earlier run, if nothing has taken it since, and otherwise a newly planned
+// one.
+func (s *Service) freeArchivePath(path, planned string) string {
+	if planned != "" {
+		if _, err := os.Stat(planned); os.IsNotExist(err) {
+			return planned
+		}
+	}
+	return s.planArchive(path)
+}
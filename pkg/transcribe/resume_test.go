@@ -0,0 +1,265 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/checkpoint"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+// flakyArchiver fails while fail is set.
+type flakyArchiver struct {
+	fail     bool
+	archived []string
+}
+
+func (a *flakyArchiver) Archive(ctx context.Context, sourcePath, archiveDir string) error {
+	if a.fail {
+		return errors.New("archive unavailable")
+	}
+	a.archived = append(a.archived, sourcePath)
+	return nil
+}
+
+func TestProcessFile_ResumesFromSavedTranscript(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+
+	tc := &countingClient{}
+	w := &recordingWriter{}
+	arch := &countingArchiver{}
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{},
+		Stabilizer: fakeStabilizer{},
+		Client:     tc,
+		Writer:     w,
+		Archiver:   arch,
+		Logger:     &recordingLogger{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	// An earlier run transcribed the file, then stopped
+	audioPath := writeWAV(t, cfg.WatchDir, "memo.wav", 2*time.Second)
+	svc.checkpoints.Save(&checkpoint.State{
+		Source:     audioPath,
+		Stage:      checkpoint.Transcribed,
+		Hash:       hashFile(audioPath),
+		Transcript: &checkpoint.Transcript{Text: "Saved transcript.", Language: "en"},
+	})
+
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{stabilize: true, archive: true}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if n := tc.calls.Load(); n != 0 {
+		t.Errorf("expected no transcription, got %d", n)
+	}
+	if len(w.texts) != 1 || !strings.Contains(w.texts[0], "Saved transcript.") {
+		t.Errorf("expected the saved transcript written, got %q", w.texts)
+	}
+	if arch.count.Load() != 1 {
+		t.Errorf("expected the recording to be archived, got %d archives", arch.count.Load())
+	}
+	if st, _ := svc.checkpoints.Load(audioPath); st != nil {
+		t.Errorf("expected the checkpoint removed once done, got %+v", st)
+	}
+
+	p, _ := history.DefaultPath()
+	records, _ := history.New(p).Load(time.Time{})
+	if len(records) != 1 || records[0].Status != history.StatusCompleted || records[0].Stage != string(checkpoint.Archived) {
+		t.Errorf("expected a completed record at stage archived, got %+v", records)
+	}
+}
+
+func TestProcessFile_RetryAfterArchiveFailureOnlyArchives(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+
+	tc := &countingClient{}
+	w := &recordingWriter{}
+	arch := &flakyArchiver{fail: true}
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{},
+		Stabilizer: fakeStabilizer{},
+		Client:     tc,
+		Writer:     w,
+		Archiver:   arch,
+		Logger:     &recordingLogger{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	audioPath := writeWAV(t, cfg.WatchDir, "memo.wav", 2*time.Second)
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{archive: true}); err == nil {
+		t.Fatal("expected the archive failure")
+	}
+	st, _ := svc.checkpoints.Load(audioPath)
+	if st == nil || st.Stage != checkpoint.Written || st.Output != "/notes/out.md" {
+		t.Fatalf("expected a written checkpoint, got %+v", st)
+	}
+
+	arch.fail = false
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{archive: true}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if n := tc.calls.Load(); n != 1 {
+		t.Errorf("expected 1 transcription, got %d", n)
+	}
+	if len(w.texts) != 1 {
+		t.Errorf("expected 1 note, got %d", len(w.texts))
+	}
+	if len(arch.archived) != 1 {
+		t.Errorf("expected the retry to archive, got %v", arch.archived)
+	}
+
+	p, _ := history.DefaultPath()
+	records, _ := history.New(p).Load(time.Time{})
+	if len(records) != 2 {
+		t.Fatalf("expected 2 history records, got %d", len(records))
+	}
+	if records[0].Stage != string(checkpoint.Written) || records[0].Category != history.CategoryArchive {
+		t.Errorf("expected the failure recorded after the written stage, got %+v", records[0])
+	}
+	if records[1].Status != history.StatusCompleted || records[1].Output != "/notes/out.md" {
+		t.Errorf("expected the retry to complete with the note, got %+v", records[1])
+	}
+}
+
+func TestProcessFile_StartsOverWhenAudioChanged(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+
+	tc := &countingClient{}
+	svc := newDedupService(t, cfg, tc)
+
+	audioPath := writeWAV(t, cfg.WatchDir, "memo.wav", 2*time.Second)
+	svc.checkpoints.Save(&checkpoint.State{
+		Source:     audioPath,
+		Stage:      checkpoint.Transcribed,
+		Hash:       "sha256:of-an-earlier-recording",
+		Transcript: &checkpoint.Transcript{Text: "Stale transcript."},
+	})
+
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if n := tc.calls.Load(); n != 1 {
+		t.Errorf("expected the changed audio transcribed, got %d transcriptions", n)
+	}
+}
+
+func TestPruneCheckpoints_DropsMissingFiles(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	svc := newDedupService(t, cfg, &countingClient{})
+
+	kept := writeWAV(t, cfg.WatchDir, "kept.wav", time.Second)
+	gone := writeWAV(t, cfg.WatchDir, "gone.wav", time.Second)
+	svc.checkpoints.Save(&checkpoint.State{Source: kept, Stage: checkpoint.Stable})
+	svc.checkpoints.Save(&checkpoint.State{Source: gone, Stage: checkpoint.Stable})
+	os.Remove(gone)
+
+	svc.pruneCheckpoints()
+	states, _ := svc.checkpoints.List()
+	if len(states) != 1 || states[0].Source != kept {
+		t.Errorf("expected only %s kept, got %+v", kept, states)
+	}
+}
+
+// blockingWriter reports each write on started and blocks it until ctx
+// is cancelled, like a service stopped while writing.
+type blockingWriter struct {
+	started chan struct{}
+}
+
+func (w *blockingWriter) Write(ctx context.Context, text string, opts OutputOptions) (string, error) {
+	w.started <- struct{}{}
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestRun_ResumesUnfinishedFilesOnStart(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+	audioPath := writeWAV(t, cfg.WatchDir, "memo.wav", 2*time.Second)
+
+	// The first run transcribes the file and is stopped while writing it
+	firstClient := &countingClient{}
+	bw := &blockingWriter{started: make(chan struct{}, 1)}
+	fw := &fakeWatcher{events: make(chan FileEvent, 1)}
+	first, err := NewServiceWith(cfg, Options{
+		Watcher:    fw,
+		Stabilizer: fakeStabilizer{},
+		Client:     firstClient,
+		Writer:     bw,
+		Archiver:   &countingArchiver{},
+		Logger:     &recordingLogger{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- first.Run(ctx) }()
+	fw.events <- FileEvent{Path: audioPath, Timestamp: time.Now()}
+	select {
+	case <-bw.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the note to be written")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected clean shutdown, got: %v", err)
+	}
+	if n := firstClient.calls.Load(); n != 1 {
+		t.Fatalf("expected 1 transcription in the first run, got %d", n)
+	}
+
+	// The next run picks it up without the watcher reporting it
+	secondClient := &countingClient{}
+	w := &recordingWriter{}
+	arch := &recordingArchiver{archived: make(chan string, 1)}
+	second, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{events: make(chan FileEvent)},
+		Stabilizer: fakeStabilizer{},
+		Client:     secondClient,
+		Writer:     w,
+		Archiver:   arch,
+		Logger:     &recordingLogger{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() { done <- second.Run(ctx) }()
+	select {
+	case <-arch.archived:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the unfinished file to be resumed")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected clean shutdown, got: %v", err)
+	}
+
+	if n := secondClient.calls.Load(); n != 0 {
+		t.Errorf("expected no second upload, got %d", n)
+	}
+	if len(w.texts) != 1 || !strings.Contains(w.texts[0], "Buy milk.") {
+		t.Errorf("expected the saved transcript written, got %q", w.texts)
+	}
+}
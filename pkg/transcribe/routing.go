@@ -0,0 +1,179 @@
+package transcribe
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/metadata"
+)
+
+// RouteRule selects the output directory, template and transcription prompt
+// for files matching all of its conditions. Empty conditions match
+// everything; the first matching rule in the config wins, and files matching
+// no rule use output_dir, template_path, initial_prompt and vocabulary.
+type RouteRule struct {
+	// Name identifies the rule in logs.
+	Name string `json:"name,omitempty"`
+
+	// SourceFolder matches files whose directory contains a folder with this
+	// name (e.g. "work"), or, for an absolute path, files under that path.
+	SourceFolder string `json:"source_folder,omitempty"`
+	// FilenameRegex matches the file's base name.
+	FilenameRegex string `json:"filename_regex,omitempty"`
+	// MinDurationSeconds and MaxDurationSeconds bound the recording length.
+	// Files whose duration cannot be read do not match duration conditions.
+	MinDurationSeconds float64 `json:"min_duration_seconds,omitempty"`
+	MaxDurationSeconds float64 `json:"max_duration_seconds,omitempty"`
+	// Language matches the language detected by the transcription API.
+	Language string `json:"language,omitempty"`
+	// Device and RecordingSource match the labels filename_parsers extract
+	// from the file's name.
+	Device          string `json:"device,omitempty"`
+	RecordingSource string `json:"recording_source,omitempty"`
+
+	// OutputDir and TemplatePath replace the defaults for matching files.
+	OutputDir    string `json:"output_dir,omitempty"`
+	TemplatePath string `json:"template_path,omitempty"`
+	// InitialPrompt replaces initial_prompt for matching files, and
+	// Vocabulary is added to vocabulary. They are chosen before the
+	// language is detected, so rules with a language condition never set
+	// them.
+	InitialPrompt string   `json:"initial_prompt,omitempty"`
+	Vocabulary    []string `json:"vocabulary,omitempty"`
+}
+
+// routeFile is what routing rules are matched against.
+type routeFile struct {
+	Path     string
+	Language string
+	// Duration is zero when unknown.
+	Duration time.Duration
+	recordingLabels
+}
+
+// router matches files against compiled routing rules.
+type router struct {
+	rules   []RouteRule
+	regexes []*regexp.Regexp
+}
+
+// newRouter compiles the rules' filename patterns.
+func newRouter(rules []RouteRule) (*router, error) {
+	r := &router{rules: rules, regexes: make([]*regexp.Regexp, len(rules))}
+	for i, rule := range rules {
+		if rule.OutputDir == "" && rule.TemplatePath == "" && rule.InitialPrompt == "" && len(rule.Vocabulary) == 0 {
+			return nil, fmt.Errorf("%w %s: needs output_dir, template_path, initial_prompt or vocabulary", ErrInvalidRoute, rule.label(i))
+		}
+		if rule.MaxDurationSeconds > 0 && rule.MaxDurationSeconds < rule.MinDurationSeconds {
+			return nil, fmt.Errorf("%w %s: max_duration_seconds is below min_duration_seconds", ErrInvalidRoute, rule.label(i))
+		}
+		if rule.FilenameRegex == "" {
+			continue
+		}
+		re, err := regexp.Compile(rule.FilenameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: filename_regex: %v", ErrInvalidRoute, rule.label(i), err)
+		}
+		r.regexes[i] = re
+	}
+	return r, nil
+}
+
+// needsDuration reports whether any rule has a duration condition.
+func (r *router) needsDuration() bool {
+	for _, rule := range r.rules {
+		if rule.MinDurationSeconds > 0 || rule.MaxDurationSeconds > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns the first rule matching f and its index, or nil and -1.
+func (r *router) match(f routeFile) (*RouteRule, int) {
+	for i := range r.rules {
+		if r.matches(i, f) {
+			return &r.rules[i], i
+		}
+	}
+	return nil, -1
+}
+
+func (r *router) matches(i int, f routeFile) bool {
+	rule := r.rules[i]
+
+	if rule.SourceFolder != "" && !inSourceFolder(f.Path, rule.SourceFolder) {
+		return false
+	}
+	if re := r.regexes[i]; re != nil && !re.MatchString(filepath.Base(f.Path)) {
+		return false
+	}
+	if rule.MinDurationSeconds > 0 || rule.MaxDurationSeconds > 0 {
+		if f.Duration == 0 {
+			return false
+		}
+		seconds := f.Duration.Seconds()
+		if rule.MinDurationSeconds > 0 && seconds < rule.MinDurationSeconds {
+			return false
+		}
+		if rule.MaxDurationSeconds > 0 && seconds > rule.MaxDurationSeconds {
+			return false
+		}
+	}
+	if rule.Language != "" && !strings.EqualFold(rule.Language, f.Language) {
+		return false
+	}
+	if rule.Device != "" && !strings.EqualFold(rule.Device, f.Device) {
+		return false
+	}
+	if rule.RecordingSource != "" && !strings.EqualFold(rule.RecordingSource, f.RecordingSource) {
+		return false
+	}
+	return true
+}
+
+// label names a rule for logs and errors.
+func (rule RouteRule) label(i int) string {
+	if rule.Name != "" {
+		return rule.Name
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
+
+// inSourceFolder reports whether path lies under folder: an absolute folder
+// is a path prefix, otherwise any directory component must equal folder.
+func inSourceFolder(path, folder string) bool {
+	dir := filepath.Dir(path)
+	if filepath.IsAbs(folder) {
+		rel, err := filepath.Rel(filepath.Clean(folder), dir)
+		return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+	}
+	for _, part := range strings.Split(dir, string(filepath.Separator)) {
+		if part == folder {
+			return true
+		}
+	}
+	return false
+}
+
+// audioDuration reads the recording length of an M4A or WAV file, or
+// returns zero.
+func audioDuration(path string) time.Duration {
+	var meta *metadata.AudioMetadata
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".m4a":
+		meta, err = metadata.ExtractM4A(path)
+	case ".wav":
+		meta, err = metadata.ExtractWAV(path)
+	default:
+		return 0
+	}
+	if err != nil {
+		return 0
+	}
+	return meta.Duration
+}
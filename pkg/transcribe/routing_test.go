@@ -0,0 +1,134 @@
+package transcribe
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRouter_Match(t *testing.T) {
+	rt, err := newRouter([]RouteRule{
+		{Name: "work", SourceFolder: "work", OutputDir: "/vault/Areas/Work/Inbox", TemplatePath: "/vault/meeting.md"},
+		{Name: "standup", FilenameRegex: `^standup-`, OutputDir: "/vault/Standups"},
+		{Name: "long", MinDurationSeconds: 1800, OutputDir: "/vault/Long"},
+		{Name: "german", Language: "de", OutputDir: "/vault/Deutsch"},
+		{Name: "phone", SourceFolder: "/mnt/sync/phone", OutputDir: "/vault/Phone"},
+		{Name: "memos", RecordingSource: "Voice Memos", OutputDir: "/vault/Memos"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		file     routeFile
+		expected string
+	}{
+		{"source folder component", routeFile{Path: "/sync/work/memo.m4a"}, "work"},
+		{"folder name is not a substring match", routeFile{Path: "/sync/homework/memo.m4a"}, ""},
+		{"filename regex", routeFile{Path: "/sync/standup-0915.m4a"}, "standup"},
+		{"first match wins", routeFile{Path: "/sync/work/standup-0915.m4a"}, "work"},
+		{"duration above minimum", routeFile{Path: "/sync/talk.m4a", Duration: time.Hour}, "long"},
+		{"duration below minimum", routeFile{Path: "/sync/talk.m4a", Duration: time.Minute}, ""},
+		{"unknown duration", routeFile{Path: "/sync/talk.m4a"}, ""},
+		{"language is case-insensitive", routeFile{Path: "/sync/memo.m4a", Language: "DE"}, "german"},
+		{"absolute source folder", routeFile{Path: "/mnt/sync/phone/2026/memo.m4a"}, "phone"},
+		{"absolute source folder sibling", routeFile{Path: "/mnt/sync/phones/memo.m4a"}, ""},
+		{"recording source is case-insensitive", routeFile{Path: "/sync/Voice 034.m4a", recordingLabels: recordingLabels{RecordingSource: "voice memos"}}, "memos"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, i := rt.match(tt.file)
+			got := ""
+			if rule != nil {
+				got = rule.label(i)
+			}
+			if got != tt.expected {
+				t.Errorf("expected route %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewRouter_InvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule RouteRule
+	}{
+		{"bad regex", RouteRule{FilenameRegex: "(", OutputDir: "/vault/x"}},
+		{"no destination", RouteRule{SourceFolder: "work"}},
+		{"inverted duration range", RouteRule{MinDurationSeconds: 60, MaxDurationSeconds: 30, OutputDir: "/vault/x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newRouter([]RouteRule{tt.rule})
+			if !errors.Is(err, ErrInvalidRoute) {
+				t.Errorf("expected ErrInvalidRoute, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestService_OutputOptionsRouted(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	template := "/vault/default.md"
+	cfg.TemplatePath = &template
+	cfg.Routes = []RouteRule{
+		{Name: "meetings", SourceFolder: "work", OutputDir: "/vault/Areas/Work/Inbox", TemplatePath: "/vault/meeting.md"},
+		{Name: "german", Language: "de", OutputDir: "/vault/Deutsch"},
+	}
+
+	svc, err := NewBuilder(cfg).WithWatcher(&fakeWatcher{}).Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	opts, route := svc.outputOptions(FileEvent{Path: "/sync/work/memo.m4a"}, nil)
+	if route != "meetings" || opts.OutputDir != "/vault/Areas/Work/Inbox" || opts.TemplatePath != "/vault/meeting.md" {
+		t.Errorf("expected meetings route, got %q with %+v", route, opts)
+	}
+
+	opts, route = svc.outputOptions(FileEvent{Path: "/sync/memo.m4a"}, &TranscriptionResult{Language: "de"})
+	if route != "german" || opts.OutputDir != "/vault/Deutsch" || opts.TemplatePath != template {
+		t.Errorf("expected german route keeping the default template, got %q with %+v", route, opts)
+	}
+
+	opts, route = svc.outputOptions(FileEvent{Path: "/sync/memo.m4a"}, &TranscriptionResult{Language: "en"})
+	if route != "" || opts.OutputDir != cfg.OutputDir {
+		t.Errorf("expected default output, got %q with %+v", route, opts)
+	}
+}
+
+func TestService_TranscribeOptionsRouted(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.InitialPrompt = "Voice memos about the garden."
+	cfg.Vocabulary = []string{"Nota"}
+	cfg.VADFilter = true
+	cfg.Routes = []RouteRule{
+		{Name: "german", Language: "de", InitialPrompt: "Sprachnotiz."},
+		{Name: "meetings", SourceFolder: "work", InitialPrompt: "Standup: Priya, Tomás.", Vocabulary: []string{"Kubernetes"}},
+	}
+
+	svc, err := NewBuilder(cfg).WithWatcher(&fakeWatcher{}).Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	opts := svc.transcribeOptions("/sync/work/memo.m4a")
+	if opts.Prompt != "Standup: Priya, Tomás." || !reflect.DeepEqual(opts.Vocabulary, []string{"Nota", "Kubernetes"}) || !opts.VADFilter {
+		t.Errorf("expected the meetings prompt and both vocabularies, got %+v", opts)
+	}
+	if !reflect.DeepEqual(cfg.Vocabulary, []string{"Nota"}) {
+		t.Errorf("expected the configured vocabulary unchanged, got %v", cfg.Vocabulary)
+	}
+
+	opts = svc.transcribeOptions("/sync/memo.m4a")
+	if opts.Prompt != cfg.InitialPrompt || !reflect.DeepEqual(opts.Vocabulary, cfg.Vocabulary) || opts.Language != cfg.Language {
+		t.Errorf("expected the configured prompt, got %+v", opts)
+	}
+}
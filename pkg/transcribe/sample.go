@@ -0,0 +1,248 @@
+package transcribe
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// SampleConfig returns an example transcribe.json listing every setting with
+// its default and an explanation. Placeholders mark the values that must be
+// filled in. Lines starting with // are comments, which LoadFromVault
+// ignores.
+func SampleConfig() string {
+	quoted := func(values []string) string {
+		return `"` + strings.Join(values, `", "`) + `"`
+	}
+
+	return fmt.Sprintf(`{
+  // Config file layout version; leave as is
+  "schema_version": %d,
+
+  // Directory to watch for new recordings [required unless watch_dirs is set]
+  "watch_dir": "/path/to/recordings",
+
+  // Additional directories to watch, each with optional patterns, e.g.
+  // [{"path": "~/Sync/tablet", "patterns": ["*.wav"]}]
+  "watch_dirs": [],
+
+  // File patterns to watch
+  "watch_patterns": [%s],
+
+  // Whisper ASR service endpoint [required]
+  "api_url": "http://localhost:9000/asr",
+
+  // Kind of API at api_url: "whisper_asr" (a whisper-asr-webservice /asr endpoint),
+  // "grpc" (a server implementing nota's Transcriber service, e.g. http://localhost:50051),
+  // or the hosted "azure", "google", "aws", "deepgram" or "assemblyai", for which
+  // api_url is optional
+  "provider": "%s",
+
+  // Hosted provider settings, e.g. {"region": "westeurope", "key": "..."} for azure,
+  // {"project": "memos", "credentials_file": "~/gcp-key.json"} for google and
+  // {"region": "eu-west-1", "bucket": "nota-staging"} for aws,
+  // {"model": "nova-3"} for deepgram and {"speech_model": "universal"} for assemblyai
+  "azure": null,
+  "google": null,
+  "aws": null,
+  "deepgram": null,
+  "assemblyai": null,
+
+  // Providers tried in order when the one above is unreachable or over its
+  // monthly budget, each with the settings above and its own usage, e.g.
+  // [{"provider": "deepgram", "usage": {"cost_per_minute": 0.0043, "monthly_budget": 10}}]
+  "fallbacks": [],
+
+  // Where notes are written; ${VAULT} is the vault root [required]
+  "output_dir": "${VAULT}/Inbox",
+
+  // Markdown file the transcript is appended to; null for the built-in layout
+  "template_path": null,
+
+  // Note format: "md" (markdown with YAML frontmatter), "txt" or "org"; only markdown
+  // notes carry frontmatter
+  "output_format": "%s",
+
+  // Add a footer listing the model, language, duration, processing time and nota
+  // version to each note, e.g. to compare models over time
+  "metadata_footer": false,
+
+  // How notes are titled: "none" keeps the generic heading and names notes after the
+  // audio, "first_sentence" uses the transcript's first sentence, "llm" asks the llm
+  "title_strategy": "none",
+
+  // Frontmatter tags added to notes: "none", "keywords" (the transcript's most
+  // frequent words) or "llm", and how many at most
+  "tag_strategy": "none",
+  "max_tags": %d,
+
+  // OpenAI-compatible chat endpoint for the llm title and tag strategies, e.g.
+  // {"url": "http://localhost:11434/v1/chat/completions", "model": "llama3.2"}
+  "llm": null,
+
+  // Personal data and terms removed from transcripts before notes are written, e.g.
+  // {"types": ["email", "phone", "credit_card"], "terms": ["Project Falcon"], "patterns": ["ACME-\\d+"]}
+  "redact": null,
+
+  // Also write timed subtitles, "srt" or "vtt", next to the archived audio; empty for none
+  "subtitles": "",
+
+  // Memos recorded within this many minutes of each other become sections of one
+  // note; 0 writes a note per memo
+  "merge_window_minutes": 0,
+
+  // Where processed audio is moved, in YYYY/MM/DD subdirectories
+  "archive_dir": "%s",
+
+  // Octal modes for created notes, archived audio and their directories, and the
+  // group (name or ID) they are assigned to; empty keeps 0644/0755 and your group
+  "file_mode": "",
+  "dir_mode": "",
+  "group": "",
+
+  // Rules choosing output_dir, template_path, initial_prompt and vocabulary per file;
+  // the first match wins, e.g.
+  // [{"name": "work", "source_folder": "work", "output_dir": "${VAULT}/Areas/Work/Inbox"}]
+  "routes": [],
+
+  // Recorder naming conventions labelling notes with the device and app that made
+  // them, usable in routes; the first match wins, e.g.
+  // [{"pattern": "^Rec_([A-Z]+)_", "device": "${1}"}, {"pattern": "^Voice \\d+", "recording_source": "Voice Memos"}]
+  "filename_parsers": [],
+
+  // When files may be processed; files arriving outside it wait until it opens, e.g.
+  // {"active_hours": "07:00-23:00", "check_command": "is-unmetered", "check_interval_seconds": 60}
+  "schedule": null,
+
+  // HTTP endpoint sync tools can POST new files to, alongside the watcher, e.g.
+  // {"listen": "%s", "token": "secret"}; other than loopback requires a token
+  "webhook": null,
+
+  // Syncthing REST API used to wait until files have finished syncing instead of
+  // polling their size, e.g. {"api_url": "http://127.0.0.1:8384", "api_key": "..."}
+  "syncthing": null,
+
+  // Remote location polled for new recordings, downloaded to download_dir first, e.g.
+  // {"type": "s3", "bucket": "recordings", "prefix": "phone/"} or
+  // {"type": "webdav", "url": "https://cloud.example.com/remote.php/dav/files/me/Recordings/"}
+  "source": null,
+
+  // Commands transforming each file: pre_upload gets the recording's path and may print
+  // the path of a file to upload instead; post_transcript gets the transcript on stdin
+  // and prints the text to write, e.g.
+  // {"pre_upload": "/home/me/bin/denoise.sh", "post_transcript": "/home/me/bin/fix-names.py"}
+  "hooks": null,
+
+  // Run the executables in the vault's .nota/plugins directory as pipeline stages
+  "plugins": false,
+
+  // OTLP/HTTP endpoint receiving a trace per file with stabilize, transcribe, write and
+  // archive spans, e.g. {"endpoint": "http://localhost:4318/v1/traces"}
+  "tracing": null,
+
+  // Detected-file events buffered between the watcher and the pipeline
+  "watch_buffer_size": %d,
+
+  // A file is stable once its size is unchanged for this many checks, this many ms apart
+  "stabilization_interval_ms": %d,
+  "stabilization_checks": %d,
+
+  // Measure size through an open handle (for CIFS/SMB mounts where stat lags)
+  "stabilization_open_file": false,
+
+  // Require a "shared" or "exclusive" advisory lock before a file counts as stable
+  "stabilization_lock": "",
+
+  // Skip symlinks in watched directories; otherwise they are followed only when they
+  // point to a file inside the same directory
+  "skip_symlinks": false,
+
+  // Transcription language ("auto" to detect) and Whisper model
+  "language": "%s",
+  "model": "%s",
+
+  // Text the transcript is expected to follow on from, steering the spelling of names
+  // and jargon (Whisper's initial_prompt), and terms the recordings often contain, e.g.
+  // "Weekly planning with Priya and Tomás." and ["Kubernetes", "nota"]. Hosted
+  // providers that take word hints get the vocabulary only; azure and aws get neither
+  "initial_prompt": "",
+  "vocabulary": [],
+
+  // Have the Whisper server skip stretches without speech, so wind and traffic in
+  // outdoor memos are not transcribed as words (whisper_asr with the faster_whisper
+  // engine, or grpc servers supporting it)
+  "vad_filter": false,
+
+  // Language of note headings and date format, e.g. "de" for "Sprachnotiz"
+  "locale": "%s",
+
+  // Smaller or shorter recordings, such as accidental taps, are archived without being
+  // transcribed; 0 disables each check. Length is read from M4A and WAV files
+  "min_file_size_kb": 0,
+  "min_duration_seconds": 0,
+
+  // Larger files are handled by too_large_action: "skip" leaves them in place, "stub"
+  // writes a note saying where the audio is kept and archives it, "split" transcribes
+  // them in split_chunk_minutes pieces (requires ffmpeg)
+  "max_file_size_mb": %d,
+  "too_large_action": "%s",
+  "split_chunk_minutes": %d,
+
+  // Processing pauses while an output or archive location has less free space
+  "min_free_space_mb": %d,
+
+  // Attempts per file before it is recorded as failed
+  "retry_count": %d,
+
+  // Files transcribed concurrently, and which waiting file goes next:
+  // "fifo", "newest_first" or "smallest_first"
+  "workers": %d,
+  "queue_order": "%s",
+
+  // Upload bandwidth for all workers together, in KB per second; 0 for no limit
+  "upload_limit_kb_per_second": 0,
+
+  // Connections to the transcription API: idle ones kept open (default: workers),
+  // for how long, TCP keep-alive, and HTTP/2 (h2c for http:// URLs), e.g.
+  // {"max_idle_conns": 4, "idle_timeout_seconds": 90, "keep_alive_seconds": 30, "http2": true}
+  "http": null,
+
+  // Longest a file may spend uploading, writing and archiving
+  "file_timeout_minutes": %d
+}
+`,
+		CurrentSchemaVersion,
+		quoted(DefaultWatchPatterns),
+		DefaultProvider,
+		DefaultOutputFormat,
+		DefaultMaxTags,
+		DefaultArchiveDir,
+		DefaultWebhookListen,
+		DefaultWatchBufferSize,
+		DefaultStabilizationIntervalMs,
+		DefaultStabilizationChecks,
+		DefaultLanguage,
+		DefaultModel,
+		DefaultLocale,
+		DefaultMaxFileSizeMB,
+		DefaultTooLargeAction,
+		DefaultSplitChunkMinutes,
+		DefaultMinFreeSpaceMB,
+		DefaultRetryCount,
+		DefaultWorkers,
+		DefaultQueueOrder,
+		DefaultFileTimeoutMinutes,
+	)
+}
+
+// stripComments blanks out lines whose first non-space characters are //, so
+// a commented file such as SampleConfig parses as plain JSON.
+func stripComments(data []byte) []byte {
+	lines := bytes.Split(data, []byte("\n"))
+	for i, line := range lines {
+		if bytes.HasPrefix(bytes.TrimSpace(line), []byte("//")) {
+			lines[i] = nil
+		}
+	}
+	return bytes.Join(lines, []byte("\n"))
+}
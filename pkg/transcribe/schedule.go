@@ -0,0 +1,173 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultScheduleCheckIntervalSeconds is how often a closed schedule is
+// re-evaluated when check_interval_seconds is not set.
+const DefaultScheduleCheckIntervalSeconds = 60
+
+// ScheduleConfig limits when detected files are transcribed. Files that
+// become ready outside the schedule wait and are processed once it opens.
+type ScheduleConfig struct {
+	// ActiveHours is a daily local-time window such as "07:00-23:00". A
+	// window whose end is before its start spans midnight.
+	ActiveHours string `json:"active_hours,omitempty"`
+	// CheckCommand is run before processing; a non-zero exit pauses
+	// processing, e.g. while on a metered network. Arguments are separated
+	// by spaces.
+	CheckCommand string `json:"check_command,omitempty"`
+	// CheckIntervalSeconds is how often a closed schedule is re-evaluated.
+	CheckIntervalSeconds int `json:"check_interval_seconds,omitempty"`
+}
+
+// window is a daily time range, in minutes after midnight.
+type window struct {
+	start, end int
+}
+
+// parseWindow parses "HH:MM-HH:MM".
+func parseWindow(s string) (window, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return window{}, fmt.Errorf("%w: active_hours %q must look like 07:00-23:00", ErrInvalidSchedule, s)
+	}
+	start, err := parseClock(strings.TrimSpace(from))
+	if err != nil {
+		return window{}, fmt.Errorf("%w: active_hours %q: %v", ErrInvalidSchedule, s, err)
+	}
+	end, err := parseClock(strings.TrimSpace(to))
+	if err != nil {
+		return window{}, fmt.Errorf("%w: active_hours %q: %v", ErrInvalidSchedule, s, err)
+	}
+	if start == end {
+		return window{}, fmt.Errorf("%w: active_hours %q is empty", ErrInvalidSchedule, s)
+	}
+	return window{start: start, end: end}, nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// contains reports whether t's local time of day falls inside the window.
+func (w window) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.start < w.end {
+		return minute >= w.start && minute < w.end
+	}
+	return minute >= w.start || minute < w.end
+}
+
+// untilOpen returns how long after t the window next opens.
+func (w window) untilOpen(t time.Time) time.Duration {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	open := midnight.Add(time.Duration(w.start) * time.Minute)
+	if !open.After(t) {
+		open = open.AddDate(0, 0, 1)
+	}
+	return open.Sub(t)
+}
+
+// schedule decides whether files may be processed now.
+type schedule struct {
+	hours    *window
+	command  []string
+	interval time.Duration
+	now      func() time.Time
+	// runCheck runs the check command and reports whether it succeeded.
+	runCheck func(ctx context.Context, command []string) bool
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	checkOK   bool
+}
+
+// newSchedule returns nil when cfg imposes no restrictions.
+func newSchedule(cfg ScheduleConfig) (*schedule, error) {
+	if cfg.ActiveHours == "" && strings.TrimSpace(cfg.CheckCommand) == "" {
+		return nil, nil
+	}
+
+	s := &schedule{
+		command:  strings.Fields(cfg.CheckCommand),
+		interval: time.Duration(cfg.CheckIntervalSeconds) * time.Second,
+		now:      time.Now,
+		runCheck: runCheckCommand,
+	}
+	if s.interval <= 0 {
+		s.interval = DefaultScheduleCheckIntervalSeconds * time.Second
+	}
+	if cfg.ActiveHours != "" {
+		w, err := parseWindow(cfg.ActiveHours)
+		if err != nil {
+			return nil, err
+		}
+		s.hours = &w
+	}
+	return s, nil
+}
+
+// open reports whether processing is allowed now, and if not, why and how
+// long to wait before asking again. The check command's result is reused
+// for one interval so waiting files do not each run it.
+func (s *schedule) open(ctx context.Context) (bool, string, time.Duration) {
+	now := s.now()
+	if s.hours != nil && !s.hours.contains(now) {
+		return false, "outside active hours", min(s.hours.untilOpen(now), s.interval)
+	}
+	if len(s.command) == 0 {
+		return true, "", 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.checkedAt.IsZero() || now.Sub(s.checkedAt) >= s.interval {
+		s.checkOK = s.runCheck(ctx, s.command)
+		s.checkedAt = now
+	}
+	if !s.checkOK {
+		return false, "check command failed", s.interval
+	}
+	return true, "", 0
+}
+
+// wait blocks until processing is allowed or ctx is cancelled. onWait is
+// called once, with the reason, if the schedule is closed on entry.
+func (s *schedule) wait(ctx context.Context, onWait func(reason string)) error {
+	waited := false
+	for {
+		ok, reason, delay := s.open(ctx)
+		if ok {
+			return nil
+		}
+		if !waited {
+			onWait(reason)
+			waited = true
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// runCheckCommand runs command and reports whether it exited successfully.
+func runCheckCommand(ctx context.Context, command []string) bool {
+	return exec.CommandContext(ctx, command[0], command[1:]...).Run() == nil
+}
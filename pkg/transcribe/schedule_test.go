@@ -0,0 +1,153 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{"07:00-23:00", false},
+		{"22:30 - 06:00", false},
+		{"07:00", true},
+		{"7am-11pm", true},
+		{"25:00-06:00", true},
+		{"08:00-08:00", true},
+	}
+
+	for _, tt := range tests {
+		_, err := parseWindow(tt.input)
+		if tt.wantErr && !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("parseWindow(%q): expected ErrInvalidSchedule, got: %v", tt.input, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("parseWindow(%q): expected no error, got: %v", tt.input, err)
+		}
+	}
+}
+
+func TestWindow_Contains(t *testing.T) {
+	day, _ := parseWindow("07:00-23:00")
+	night, _ := parseWindow("22:00-06:00")
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 1, 22, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name     string
+		w        window
+		t        time.Time
+		expected bool
+	}{
+		{"day start", day, at(7, 0), true},
+		{"day middle", day, at(12, 0), true},
+		{"day end", day, at(23, 0), false},
+		{"day early", day, at(6, 59), false},
+		{"night late", night, at(23, 30), true},
+		{"night early", night, at(5, 59), true},
+		{"night noon", night, at(12, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.w.contains(tt.t); got != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestWindow_UntilOpen(t *testing.T) {
+	w, _ := parseWindow("07:00-23:00")
+
+	late := time.Date(2026, 1, 22, 23, 30, 0, 0, time.Local)
+	if got := w.untilOpen(late); got != 7*time.Hour+30*time.Minute {
+		t.Errorf("expected 7h30m, got: %v", got)
+	}
+	early := time.Date(2026, 1, 22, 6, 0, 0, 0, time.Local)
+	if got := w.untilOpen(early); got != time.Hour {
+		t.Errorf("expected 1h, got: %v", got)
+	}
+}
+
+func TestNewSchedule_EmptyIsNil(t *testing.T) {
+	s, err := newSchedule(ScheduleConfig{CheckIntervalSeconds: 30})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if s != nil {
+		t.Errorf("expected no schedule, got: %+v", s)
+	}
+}
+
+func TestSchedule_CheckCommandCached(t *testing.T) {
+	s, err := newSchedule(ScheduleConfig{CheckCommand: "is-unmetered --quiet", CheckIntervalSeconds: 60})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	now := time.Date(2026, 1, 22, 12, 0, 0, 0, time.Local)
+	s.now = func() time.Time { return now }
+	runs := 0
+	result := false
+	s.runCheck = func(ctx context.Context, command []string) bool {
+		runs++
+		if len(command) != 2 || command[0] != "is-unmetered" {
+			t.Errorf("unexpected command: %v", command)
+		}
+		return result
+	}
+
+	if ok, reason, delay := s.open(context.Background()); ok || reason == "" || delay != time.Minute {
+		t.Errorf("expected closed for 1m with a reason, got: %v %q %v", ok, reason, delay)
+	}
+	result = true
+	if ok, _, _ := s.open(context.Background()); ok {
+		t.Error("expected cached failure within the interval")
+	}
+	now = now.Add(time.Minute)
+	if ok, _, _ := s.open(context.Background()); !ok {
+		t.Error("expected open after the check passes")
+	}
+	if runs != 2 {
+		t.Errorf("expected 2 check runs, got: %d", runs)
+	}
+}
+
+func TestSchedule_WaitUntilOpen(t *testing.T) {
+	s, err := newSchedule(ScheduleConfig{ActiveHours: "07:00-23:00"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	s.interval = 10 * time.Millisecond
+
+	calls := 0
+	s.now = func() time.Time {
+		calls++
+		if calls < 3 {
+			return time.Date(2026, 1, 22, 3, 0, 0, 0, time.Local)
+		}
+		return time.Date(2026, 1, 22, 7, 0, 0, 0, time.Local)
+	}
+
+	var reasons []string
+	if err := s.wait(context.Background(), func(reason string) { reasons = append(reasons, reason) }); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(reasons) != 1 || reasons[0] != "outside active hours" {
+		t.Errorf("expected one wait notice, got: %v", reasons)
+	}
+}
+
+func TestSchedule_WaitCancelled(t *testing.T) {
+	s, _ := newSchedule(ScheduleConfig{ActiveHours: "07:00-23:00"})
+	s.now = func() time.Time { return time.Date(2026, 1, 22, 3, 0, 0, 0, time.Local) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.wait(ctx, func(string) {}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got: %v", err)
+	}
+}
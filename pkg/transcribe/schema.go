@@ -0,0 +1,180 @@
+package transcribe
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// CurrentSchemaVersion is the transcribe.json layout written by Save. Files
+// without schema_version predate versioning and are treated as version 0.
+const CurrentSchemaVersion = 1
+
+// migrations upgrade the raw fields of a config file: migrations[i] turns
+// version i into version i+1. Renaming a field means adding a migration that
+// moves the old key to the new one, so configs written by older releases
+// keep working.
+var migrations = []func(fields map[string]json.RawMessage) error{
+	// 0 -> 1: introduces schema_version; no fields changed
+	func(fields map[string]json.RawMessage) error { return nil },
+}
+
+// decodeConfig parses a config file, ignoring // comment lines, and migrates
+// it to CurrentSchemaVersion.
+// It returns warnings for unknown fields and for files written by a newer
+// release, whose unknown settings are ignored rather than rejected.
+func decodeConfig(data []byte) (*Config, []string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(stripComments(data), &fields); err != nil {
+		return nil, nil, err
+	}
+
+	version := 0
+	if raw, ok := fields["schema_version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, nil, fmt.Errorf("schema_version: %w", err)
+		}
+	}
+
+	var warnings []string
+	if version > CurrentSchemaVersion {
+		warnings = append(warnings, fmt.Sprintf(
+			"schema_version %d is newer than this release supports (%d); upgrade nota to use every setting",
+			version, CurrentSchemaVersion))
+	}
+	for v := version; v >= 0 && v < CurrentSchemaVersion; v++ {
+		if err := migrations[v](fields); err != nil {
+			return nil, nil, fmt.Errorf("migrate config from schema_version %d: %w", v, err)
+		}
+	}
+	if version < CurrentSchemaVersion {
+		fields["schema_version"] = json.RawMessage(fmt.Sprint(CurrentSchemaVersion))
+	}
+
+	migrated, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	warnings = append(warnings, unknownFields(migrated, reflect.TypeOf(Config{}), "")...)
+
+	var cfg Config
+	if err := json.Unmarshal(migrated, &cfg); err != nil {
+		return nil, nil, err
+	}
+	warnings = append(warnings, profileWarnings(cfg.Profiles)...)
+	return &cfg, warnings, nil
+}
+
+// unknownFields returns a warning for every key in data, and in nested
+// objects and arrays of objects, that t has no field for.
+func unknownFields(data json.RawMessage, t reflect.Type, prefix string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return nil
+		}
+		var warnings []string
+		for i, item := range items {
+			warnings = append(warnings, unknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", prefix, i))...)
+		}
+		return warnings
+	case reflect.Struct:
+	default:
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return nil
+	}
+	known := jsonFields(t)
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var warnings []string
+	for _, key := range keys {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		fieldType, ok := known[key]
+		if !ok {
+			warning := fmt.Sprintf("unknown field %s", name)
+			if suggestion := closestField(key, known); suggestion != "" {
+				warning += fmt.Sprintf(" (did you mean %s?)", suggestion)
+			}
+			warnings = append(warnings, warning)
+			continue
+		}
+		warnings = append(warnings, unknownFields(fields[key], fieldType, name)...)
+	}
+	return warnings
+}
+
+// jsonFields maps the JSON names of a struct's exported fields to their types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// closestField returns the known field within two edits of key, if any.
+func closestField(key string, known map[string]reflect.Type) string {
+	best, bestDist := "", 3
+	for name := range known {
+		if d := editDistance(key, name); d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b, counting an
+// adjacent transposition as one edit.
+func editDistance(a, b string) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,135 @@
+package transcribe
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeRawConfig(t *testing.T, vaultRoot, data string) {
+	t.Helper()
+	configPath := filepath.Join(vaultRoot, ".nota", ConfigFileName)
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+}
+
+func TestLoadFromVault_MigratesUnversionedConfig(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	writeRawConfig(t, vaultRoot, `{"watch_dir": "/mnt/sync", "api_url": "http://nas:9000/asr", "output_dir": "/vault/Inbox"}`)
+
+	cfg, err := LoadFromVault(vaultRoot)
+	if err != nil {
+		t.Fatalf("LoadFromVault failed: %v", err)
+	}
+	if cfg.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("expected schema_version %d, got %d", CurrentSchemaVersion, cfg.SchemaVersion)
+	}
+	if cfg.WatchDir != "/mnt/sync" {
+		t.Errorf("expected WatchDir to survive migration, got %q", cfg.WatchDir)
+	}
+	if len(cfg.Warnings()) != 0 {
+		t.Errorf("expected no warnings, got: %v", cfg.Warnings())
+	}
+}
+
+func TestLoadFromVault_WarnsOnUnknownFields(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	writeRawConfig(t, vaultRoot, `{
+  "schema_version": 1,
+  "wacth_dir": "/mnt/sync",
+  "api_url": "http://nas:9000/asr",
+  "output_dir": "/vault/Inbox",
+  "colour": "blue",
+  "routes": [{"source_folder": "work", "outputdir": "/vault/Work"}]
+}`)
+
+	cfg, err := LoadFromVault(vaultRoot)
+	if err != nil {
+		t.Fatalf("LoadFromVault failed: %v", err)
+	}
+
+	expected := []string{
+		"unknown field colour",
+		"unknown field routes[0].outputdir (did you mean output_dir?)",
+		"unknown field wacth_dir (did you mean watch_dir?)",
+	}
+	if !reflect.DeepEqual(cfg.Warnings(), expected) {
+		t.Errorf("expected warnings %q, got %q", expected, cfg.Warnings())
+	}
+}
+
+func TestLoadFromVault_NewerSchemaVersion(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	writeRawConfig(t, vaultRoot, `{"schema_version": 99, "watch_dir": "/mnt/sync", "api_url": "http://nas:9000/asr", "output_dir": "/vault/Inbox"}`)
+
+	cfg, err := LoadFromVault(vaultRoot)
+	if err != nil {
+		t.Fatalf("LoadFromVault failed: %v", err)
+	}
+	if len(cfg.Warnings()) != 1 || !strings.Contains(cfg.Warnings()[0], "schema_version 99 is newer") {
+		t.Errorf("expected a newer-version warning, got: %v", cfg.Warnings())
+	}
+	if cfg.WatchDir != "/mnt/sync" {
+		t.Errorf("expected known fields to load, got WatchDir %q", cfg.WatchDir)
+	}
+}
+
+func TestSaveToVault_WritesSchemaVersion(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	cfg := &Config{WatchDir: "/mnt/sync", APIURL: "http://nas:9000/asr", OutputDir: "/vault/Inbox"}
+
+	if err := cfg.SaveToVault(vaultRoot); err != nil {
+		t.Fatalf("SaveToVault failed: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultRoot, ".nota", ConfigFileName))
+	if !strings.Contains(string(data), `"schema_version": 1`) {
+		t.Errorf("expected schema_version in saved config, got:\n%s", data)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"watch_dir", "watch_dir", 0},
+		{"wacth_dir", "watch_dir", 1},
+		{"outputdir", "output_dir", 1},
+		{"model", "models", 1},
+		{"", "api_url", 7},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%q, %q): expected %d, got %d", tt.a, tt.b, tt.expected, got)
+		}
+	}
+}
+
+func TestSampleConfig_LoadsAsDefaults(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	writeRawConfig(t, vaultRoot, SampleConfig())
+
+	cfg, err := LoadFromVault(vaultRoot)
+	if err != nil {
+		t.Fatalf("LoadFromVault failed: %v", err)
+	}
+	if len(cfg.Warnings()) != 0 {
+		t.Errorf("expected no warnings, got: %v", cfg.Warnings())
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected sample to validate, got: %v", err)
+	}
+
+	defaults := *cfg
+	defaults.ApplyDefaults()
+	if !reflect.DeepEqual(*cfg, defaults) {
+		t.Errorf("expected sample to spell out every default, got %+v, defaults %+v", *cfg, defaults)
+	}
+	if cfg.OutputDir != filepath.Join(vaultRoot, "Inbox") {
+		t.Errorf("expected output_dir in the vault inbox, got %q", cfg.OutputDir)
+	}
+}
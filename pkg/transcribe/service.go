@@ -0,0 +1,1435 @@
+// Package transcribe provides the main transcription service orchestrator.
+package transcribe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/archiver"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/checkpoint"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/events"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/fileperm"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/redact"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/stabilizer"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/tracing"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/watcher"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/writer"
+)
+
+// Service orchestrates the transcription pipeline.
+type Service struct {
+	config     *Config
+	clock      clock.Clock
+	logger     Logger
+	ownsLogger bool
+	watcher    FileWatcher
+	stabilizer Stabilizer
+	client     TranscriptionClient
+	// uploads throttle and report uploads of clients the service makes.
+	uploads  uploadHooks
+	writer   OutputWriter
+	archiver Archiver
+	history  *history.Store
+	events   *events.Log
+	queue    *workQueue
+	router   *router
+	devices  *deviceDetector
+	schedule *schedule
+	disk     *diskGuard
+	budget   *budgetGuard
+	// speed estimates how long files take to transcribe, for the logs.
+	speed *speedEstimator
+	// fallbacks are tried in turn when the client cannot take a file.
+	fallbacks []*fallback
+	// stages are the registered pipeline stages and plugins.
+	stages   []PipelineStage
+	redactor *redact.Redactor
+	merger   *noteMerger
+	notes    *noteIndex
+	// checkpoints saves each file's progress so a restart resumes it.
+	checkpoints *checkpoint.Store
+	// splitAudio cuts an oversized file into pieces for TooLargeSplit.
+	splitAudio func(ctx context.Context, path, dir string, chunk time.Duration) ([]string, error)
+	tracer     *tracing.Tracer
+	perms      fileperm.Permissions
+	// fileTimeout bounds the upload, write and archive steps of each file.
+	fileTimeout time.Duration
+	progress    ProgressReporter
+	// live is the pipeline state served on the control socket.
+	live   *liveState
+	dryRun bool
+	// jobs are the scheduled vault jobs; after waits between their runs.
+	jobs  []*job
+	after func(time.Duration) <-chan time.Time
+
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	inFlight map[string]struct{}
+	// sidecars caches the sidecar of each file being processed, so it is
+	// parsed once.
+	sidecars map[string]cachedSidecar
+	stopCh   chan struct{}
+	eventsCh <-chan FileEvent
+	// stopSignal is the signal that stopped Run, if one did.
+	stopSignal os.Signal
+}
+
+// ErrFileTimeout is recorded for files that exceed file_timeout_minutes.
+var ErrFileTimeout = errors.New("file processing timed out")
+
+// ErrWatcherStopped is returned by Run when the watched sources end while
+// the service is still running, such as when inotify fails.
+var ErrWatcherStopped = errors.New("file watcher stopped unexpectedly")
+
+// Version is recorded in the frontmatter of generated notes. The nota
+// command sets it to its build version.
+var Version = "dev"
+
+// NewService creates a new transcription service with the default components:
+// an inotify watcher, a polling stabilizer, a whisper-asr-webservice client,
+// a markdown writer and a file-moving archiver.
+func NewService(cfg *Config) (*Service, error) {
+	return NewServiceWith(cfg, Options{})
+}
+
+// Options supplies alternative pipeline components to NewServiceWith.
+// Nil fields use the default implementation.
+type Options struct {
+	Watcher    FileWatcher
+	Stabilizer Stabilizer
+	Client     TranscriptionClient
+	Writer     OutputWriter
+	Archiver   Archiver
+	// Logger receives service logs. The default writes to ~/.nota/logs.
+	// An injected logger is not closed by the service.
+	Logger Logger
+	// Events receives machine-readable pipeline events. The default writes
+	// events-YYYY-MM-DD.jsonl files next to the default logger's; with an
+	// injected Logger and no Events, no events are written.
+	Events *events.Log
+	// Clock dates the default logger's lines and files, the default
+	// writer's note names, the default archiver's directories and history
+	// records. The default is the system clock.
+	Clock clock.Clock
+}
+
+// NewServiceWith creates a transcription service from the given components,
+// using the default implementation for any that are nil.
+func NewServiceWith(cfg *Config, opts Options) (*Service, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("invalid config: config is required")
+	}
+
+	// Apply defaults for optional fields, then expand ~ in defaulted paths
+	cfg.ApplyDefaults()
+	cfg.expandPaths()
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	clk := clock.OrReal(opts.Clock)
+
+	// Initialize logger
+	logger := opts.Logger
+	ownsLogger := false
+	if logger == nil {
+		logConfig := logging.DefaultConfig()
+		logConfig.Component = "service"
+		logConfig.Clock = clk
+		fl, err := logging.New(logConfig)
+		if err != nil {
+			return nil, fmt.Errorf("create logger: %w", err)
+		}
+		logger = fl
+		ownsLogger = true
+	}
+	closeLogger := func() {
+		if ownsLogger {
+			closeIfCloser(logger)
+		}
+	}
+
+	// Initialize file watcher
+	fw := opts.Watcher
+	if fw == nil {
+		iw, err := watcher.NewInotifyWatcher(
+			watcher.WithBufferSize(cfg.WatchBufferSize),
+			watcher.WithOverflowHandler(func(reason string) {
+				logger.Error("watcher overflow", nil,
+					logging.String("reason", reason),
+				)
+			}),
+		)
+		if err != nil {
+			closeLogger()
+			return nil, fmt.Errorf("create watcher: %w", err)
+		}
+		fw = iw
+	}
+
+	// Initialize stabilizer
+	stab := opts.Stabilizer
+	if stab == nil {
+		interval := time.Duration(cfg.StabilizationIntervalMs) * time.Millisecond
+		ps := stabilizer.NewPollStabilizer(interval, cfg.StabilizationChecks)
+		ps.OpenFile = cfg.StabilizationOpenFile
+		ps.Lock = stabilizer.LockMode(cfg.StabilizationLock)
+		stab = ps
+		if cfg.Syncthing != nil {
+			stab = newSyncthingStabilizer(*cfg.Syncthing, ps, logger)
+		}
+	}
+
+	// Initialize transcription client
+	uploads := newUploadHooks(cfg, logger)
+	tc := opts.Client
+	if tc == nil {
+		var err error
+		if tc, err = cfg.newClient(cfg.APIURL, uploads); err != nil {
+			fw.Stop()
+			closeLogger()
+			return nil, err
+		}
+	}
+
+	// Modes and group for created notes, archived audio and directories;
+	// Validate has already checked them
+	perms, _ := cfg.Permissions()
+
+	// Initialize output writer
+	ow := opts.Writer
+	if ow == nil {
+		ow = writer.NewSimpleWriter(writer.WithPermissions(perms), writer.WithClock(clk))
+	}
+
+	// Initialize archiver
+	arch := opts.Archiver
+	if arch == nil {
+		arch = archiver.NewSimpleArchiver(archiver.WithPermissions(perms), archiver.WithClock(clk))
+	}
+
+	// Compile output routing rules
+	rt, err := newRouter(cfg.Routes)
+	if err != nil {
+		fw.Stop()
+		closeLogger()
+		return nil, err
+	}
+
+	// Compile filename parsers for device labels
+	devices, err := newDeviceDetector(cfg.FilenameParsers)
+	if err != nil {
+		fw.Stop()
+		closeLogger()
+		return nil, err
+	}
+
+	// Parse the processing schedule
+	sched, err := newSchedule(cfg.scheduleConfig())
+	if err != nil {
+		fw.Stop()
+		closeLogger()
+		return nil, err
+	}
+
+	// Compile transcript redaction rules
+	var red *redact.Redactor
+	if cfg.Redact != nil {
+		if red, err = cfg.Redact.redactor(); err != nil {
+			fw.Stop()
+			closeLogger()
+			return nil, err
+		}
+	}
+
+	// Prepare scheduled vault jobs
+	jobs, err := newJobs(cfg)
+	if err != nil {
+		fw.Stop()
+		closeLogger()
+		return nil, err
+	}
+
+	// Initialize processing history
+	hist, err := history.Open()
+	if err != nil {
+		fw.Stop()
+		closeLogger()
+		return nil, fmt.Errorf("open history: %w", err)
+	}
+	// Prepare the providers to fall back to
+	fallbacks, err := newFallbacks(cfg, hist, uploads, clk.Now)
+	if err != nil {
+		fw.Stop()
+		closeLogger()
+		return nil, err
+	}
+	// Save per-file progress so a restart resumes files where they stopped
+	checkpoints, err := checkpoint.Open()
+	if err != nil {
+		fw.Stop()
+		closeLogger()
+		return nil, fmt.Errorf("open checkpoints: %w", err)
+	}
+	// Record pipeline events next to the default logger's files
+	evs := opts.Events
+	if evs == nil && ownsLogger {
+		if evs, err = events.Open(clk); err != nil {
+			fw.Stop()
+			closeLogger()
+			return nil, fmt.Errorf("open events: %w", err)
+		}
+	}
+
+	// Export pipeline traces, if configured
+	var tracer *tracing.Tracer
+	if cfg.Tracing != nil {
+		tracer = cfg.Tracing.tracer(clk, func(err error) {
+			logger.Error("failed to export traces", err)
+		})
+	}
+
+	// Add the registered pipeline stages, then the vault's plugins
+	stages := registeredStages()
+	if cfg.Plugins && cfg.vaultRoot != "" {
+		stages = append(stages, loadPlugins(context.Background(), PluginDir(cfg.vaultRoot), logger)...)
+	}
+
+	s := &Service{
+		config:      cfg,
+		clock:       clk,
+		logger:      logger,
+		ownsLogger:  ownsLogger,
+		watcher:     fw,
+		stabilizer:  stab,
+		client:      tc,
+		uploads:     uploads,
+		writer:      ow,
+		archiver:    arch,
+		history:     hist,
+		checkpoints: checkpoints,
+		events:      evs,
+		queue:       newWorkQueue(cfg.Workers, cfg.QueueOrder),
+		router:      rt,
+		devices:     devices,
+		schedule:    sched,
+		disk:        newDiskGuard(cfg),
+		budget:      newBudgetGuard(cfg, hist, clk.Now),
+		speed:       newSpeedEstimator(hist, cfg.ProviderName(), clk.Now),
+		fallbacks:   fallbacks,
+		stages:      stages,
+		redactor:    red,
+		merger:      newNoteMerger(cfg.MergeWindowMinutes),
+		splitAudio:  ffmpegSplit,
+		tracer:      tracer,
+		perms:       perms,
+		fileTimeout: time.Duration(cfg.FileTimeoutMinutes) * time.Minute,
+		live:        newLiveState(clk),
+		jobs:        jobs,
+		after:       time.After,
+		inFlight:    make(map[string]struct{}),
+		stopCh:      make(chan struct{}),
+	}
+	s.notes = newNoteIndex(s.outputDirs(), hist)
+	s.disk.onChange = s.diskSpaceChanged
+	if s.budget != nil {
+		s.budget.onChange = s.budgetChanged
+		if len(fallbacks) > 0 {
+			s.budget.provider = cfg.ProviderName()
+		}
+	}
+	return s, nil
+}
+
+// componentLogger returns a logger tagged with the given component when the
+// service logger supports it, and the service logger otherwise.
+func (s *Service) componentLogger(component string) Logger {
+	if fl, ok := s.logger.(*logging.FileLogger); ok {
+		return fl.WithComponent(component)
+	}
+	return s.logger
+}
+
+// closeLogger closes the service logger if the service created it.
+func (s *Service) closeLogger() error {
+	if !s.ownsLogger {
+		return nil
+	}
+	return closeIfCloser(s.logger)
+}
+
+// closeIfCloser closes v if it implements io.Closer.
+func closeIfCloser(v any) error {
+	if c, ok := v.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// SetProgressReporter registers a reporter for per-file stage transitions.
+// Must be called before Run.
+func (s *Service) SetProgressReporter(p ProgressReporter) {
+	s.progress = p
+}
+
+// SetDryRun enables dry-run mode: files are detected and stabilized, and the
+// upload, output and archive steps are logged instead of performed.
+// Must be called before Run.
+func (s *Service) SetDryRun(dryRun bool) {
+	s.dryRun = dryRun
+}
+
+// Run starts the transcription service and blocks until stopped.
+// It handles SIGINT and SIGTERM for graceful shutdown, after which
+// StopSignal returns the signal. Sources that end while it runs stop it
+// with ErrWatcherStopped.
+func (s *Service) Run(ctx context.Context) error {
+	// Set up signal handling
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	// Create cancellable context
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Start file watcher
+	s.logger.Info("starting transcription service",
+		logging.String("api_url", s.config.APIURL),
+		logging.String("provider", string(s.config.Provider)),
+		logging.String("fallbacks", s.fallbackNames()),
+		logging.String("stages", s.stageNames()),
+		logging.String("output_dir", s.config.OutputDir),
+		logging.Int("workers", s.config.Workers),
+		logging.String("queue_order", string(s.config.QueueOrder)),
+		logging.String("profile", s.config.Profile()),
+	)
+	if s.dryRun {
+		s.logger.Info("dry run: no files will be uploaded, written or archived")
+	}
+	for _, warning := range s.config.Warnings() {
+		s.logger.Info("config warning", logging.String("warning", warning))
+	}
+	s.emit(events.Event{Type: events.ServiceStarted})
+	if effective, err := json.Marshal(s.config.Effective()); err == nil {
+		s.logger.Info("effective config", logging.String("config", string(effective)))
+	}
+
+	if !s.dryRun {
+		s.pruneCheckpoints()
+	}
+
+	events, err := s.watchAll(ctx)
+	if err != nil {
+		return err
+	}
+	s.eventsCh = events
+	s.serveControl(ctx)
+	s.startJobs(ctx)
+	if !s.dryRun {
+		s.resumeUnfinished(ctx)
+	}
+
+	// Main event loop
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("context cancelled, shutting down")
+			return s.shutdown()
+
+		case sig := <-sigCh:
+			s.logger.Info("received signal, shutting down",
+				logging.String("signal", sig.String()),
+			)
+			s.stopSignal = sig
+			cancel()
+			return s.shutdown()
+
+		case event, ok := <-events:
+			if !ok {
+				s.logger.Error("watcher channel closed, shutting down", ErrWatcherStopped)
+				return errors.Join(ErrWatcherStopped, s.shutdown())
+			}
+			s.handleFileEvent(ctx, event)
+		}
+	}
+}
+
+// StopSignal returns the signal that stopped Run, or nil when it returned
+// for another reason.
+func (s *Service) StopSignal() os.Signal {
+	return s.stopSignal
+}
+
+// watchAll starts watching every configured directory, and the webhook
+// receiver if configured, and merges their events into one channel, which is
+// closed once every source has ended.
+func (s *Service) watchAll(ctx context.Context) (<-chan FileEvent, error) {
+	var sources []<-chan FileEvent
+	for _, wd := range s.config.Watches() {
+		events, err := s.watcher.Watch(ctx, wd.Path, wd.Patterns)
+		if err != nil {
+			return nil, fmt.Errorf("start watcher for %s: %w", wd.Path, err)
+		}
+
+		s.logger.Info("watching for files",
+			logging.String("watch_dir", wd.Path),
+			logging.String("patterns", fmt.Sprintf("%v", wd.Patterns)),
+		)
+		sources = append(sources, events)
+	}
+
+	notified, err := s.listenWebhook(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if notified != nil {
+		sources = append(sources, notified)
+	}
+
+	downloaded, err := s.pollSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if downloaded != nil {
+		sources = append(sources, downloaded)
+	}
+
+	merged := make(chan FileEvent)
+	var wg sync.WaitGroup
+	for _, events := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for event := range events {
+				select {
+				case merged <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	return merged, nil
+}
+
+// handleFileEvent processes a single file through the transcription pipeline.
+// Events for a file that is already being processed are ignored; the watcher
+// repeats events after a rescan and when a file is closed more than once.
+// Files the watched directory's .notaignore lists are ignored too, as are
+// sidecar files that watch patterns happen to match.
+func (s *Service) handleFileEvent(ctx context.Context, event FileEvent) {
+	if strings.HasSuffix(event.Path, SidecarSuffix) {
+		return
+	}
+	if s.ignoredPath(event.Path) {
+		s.logger.Debug("file listed in .notaignore, ignoring event",
+			logging.String("path", event.Path),
+		)
+		return
+	}
+
+	s.mu.Lock()
+	if _, busy := s.inFlight[event.Path]; busy {
+		s.mu.Unlock()
+		s.logger.Debug("file already being processed, ignoring event",
+			logging.String("path", event.Path),
+		)
+		return
+	}
+	s.inFlight[event.Path] = struct{}{}
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.inFlight, event.Path)
+			s.mu.Unlock()
+		}()
+		s.processFile(ctx, event, processOptions{stabilize: true, archive: true, scheduled: true, confined: true, queue: s.queue})
+	}()
+}
+
+// processOptions selects the optional steps of processFile.
+type processOptions struct {
+	// stabilize waits for the file to stop changing before uploading.
+	stabilize bool
+	// archive moves the file to the archive directory once its note is written.
+	archive bool
+	// scheduled holds the file until the configured schedule allows processing.
+	scheduled bool
+	// confined rejects files that are, or resolve to, files outside the
+	// watched directories.
+	confined bool
+	// queue, if set, limits concurrent uploads and orders waiting files.
+	queue *workQueue
+}
+
+// processFile runs the transcription pipeline for a single file and returns
+// the outcome that was recorded to history.
+func (s *Service) processFile(ctx context.Context, event FileEvent, opts processOptions) (err error) {
+	// Tag everything logged for this file so concurrent files' lines can
+	// be told apart
+	ctx = logging.WithTraceID(ctx, logging.NewTraceID())
+	fileLogger := withTrace(ctx, s.componentLogger("pipeline"))
+	startTime := time.Now()
+	defer s.live.done(event.Path)
+	defer s.forgetSidecar(event.Path)
+
+	ctx, span := s.tracer.Start(ctx, "process_file",
+		tracing.String("file.path", event.Path),
+		tracing.Int64("file.size", event.Size),
+		tracing.String("nota.trace", logging.TraceID(ctx)),
+	)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	fileLogger.Info("processing file",
+		logging.String("path", event.Path),
+		logging.Int64("size", event.Size),
+	)
+	s.reportProgress(event, StageDetected, startTime, "")
+	s.emit(events.Event{Type: events.FileDetected, Path: event.Path, Size: event.Size})
+
+	// Check file size; files handled by too_large_action are checked again
+	// once stable
+	if event.Size > s.maxFileSize() && s.config.TooLargeAction == TooLargeSkip {
+		return s.skipTooLarge(fileLogger, event, startTime)
+	}
+
+	// Refuse symlinks leading out of the watched directories before reading
+	// anything through them
+	if opts.confined {
+		if err := s.rejectUnsafePath(fileLogger, event, startTime); err != nil {
+			return err
+		}
+	}
+
+	// Pick up where an earlier run left off: a file it stabilized,
+	// transcribed or wrote a note for is not uploaded again
+	st := s.resumeCheckpoint(fileLogger, event.Path)
+	if !st.Stage.Reached(checkpoint.Stable) {
+		s.advance(st, checkpoint.Detected)
+	}
+
+	// Step 1: Wait for file to stabilize
+	if opts.stabilize && !st.Stage.Reached(checkpoint.Stable) {
+		fileLogger.Debug("waiting for file to stabilize",
+			logging.String("path", event.Path),
+		)
+		s.reportProgress(event, StageStabilizing, startTime, "")
+
+		_, stabilizeSpan := s.tracer.Start(ctx, "stabilize")
+		err := s.stabilizer.WaitForStable(ctx, event.Path)
+		stabilizeSpan.RecordError(err)
+		stabilizeSpan.End()
+		if err != nil {
+			return s.failFile(fileLogger, "stabilization failed", event,
+				history.Record{Category: history.CategoryStabilization}, err, startTime)
+		}
+
+		fileLogger.Debug("file stabilized",
+			logging.String("path", event.Path),
+		)
+	}
+
+	// Archive accidental taps and other tiny recordings without
+	// transcribing them
+	if reason, detail := s.belowMinimum(event.Path); reason != "" {
+		return s.skipBelowMinimum(ctx, fileLogger, event, opts.archive, reason, detail, startTime)
+	}
+
+	if s.dryRun {
+		s.logDryRun(fileLogger, event, startTime, opts.archive)
+		return nil
+	}
+
+	if st.Hash == "" {
+		st.Hash = hashFile(event.Path)
+	}
+	transcribed := st.Stage.Reached(checkpoint.Transcribed)
+	if !transcribed {
+		// A note already written for this recording means an earlier run
+		// stopped before archiving it; finish that rather than transcribing
+		// again
+		if note, ok := s.notes.lookup(st.Hash); ok {
+			return s.skipExisting(ctx, fileLogger, event, opts.archive, note, startTime)
+		}
+		if !st.Stage.Reached(checkpoint.Stable) {
+			s.advance(st, checkpoint.Stable)
+		}
+	}
+
+	// A saved transcript needs neither an open schedule nor budget
+	if opts.scheduled && s.schedule != nil && !transcribed {
+		err := s.schedule.wait(ctx, func(reason string) {
+			fileLogger.Info("outside processing schedule, holding file",
+				logging.String("path", event.Path),
+				logging.String("reason", reason),
+			)
+			s.reportProgress(event, StageQueued, startTime, "")
+		})
+		if err != nil {
+			fileLogger.Info("shutting down before file was processed",
+				logging.String("path", event.Path),
+			)
+			return err
+		}
+	}
+
+	// Pause watched files while output or archive space is low; fail others
+	// rather than leaving a half-written note on a full disk
+	if s.disk != nil {
+		var err error
+		if opts.scheduled {
+			err = s.disk.wait(ctx, fileLogger)
+		} else if err = s.disk.check(); err != nil {
+			s.failFile(fileLogger, "insufficient disk space, skipping", event,
+				history.Record{Category: history.CategoryDiskSpace}, err, startTime)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Likewise hold watched files once a paid API's monthly budget is spent,
+	// unless a fallback can take them
+	if s.budget != nil && !transcribed && !s.fallbackAvailable() {
+		var err error
+		if opts.scheduled {
+			err = s.budget.wait(ctx, fileLogger)
+		} else if err = s.budget.check(); err != nil {
+			s.failFile(fileLogger, "monthly budget exceeded, skipping", event,
+				history.Record{Category: history.CategoryBudget}, err, startTime)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.queue != nil {
+		if !opts.queue.tryAcquire() {
+			fileLogger.Debug("waiting for a free worker",
+				logging.String("path", event.Path),
+			)
+			s.reportProgress(event, StageQueued, startTime, "")
+			if err := opts.queue.acquire(ctx, newQueueItem(event.Path)); err != nil {
+				fileLogger.Info("shutting down before file was processed",
+					logging.String("path", event.Path),
+				)
+				return err
+			}
+		}
+		defer opts.queue.release()
+	}
+
+	// Bound the remaining steps so one pathological file cannot hold a
+	// worker forever
+	fileCtx, cancel := context.WithTimeout(ctx, s.fileTimeout)
+	defer cancel()
+
+	// Check again in case the file was replaced by a link while waiting
+	if opts.confined {
+		if err := s.rejectUnsafePath(fileLogger, event, startTime); err != nil {
+			return err
+		}
+	}
+
+	// Step 2: Transcribe the file, unless an earlier run saved the transcript
+	var result *TranscriptionResult
+	// How long transcribing took, and was expected to take from the speed
+	// of recent files
+	var transcribeTime, estimate time.Duration
+	if transcribed {
+		fileLogger.Info("resuming from saved transcript",
+			logging.String("path", event.Path),
+			logging.String("stage", string(st.Stage)),
+		)
+		result = st.Transcript.Result()
+	} else {
+		fields := []Field{logging.String("path", event.Path)}
+		if audio := audioDuration(event.Path); audio > 0 {
+			fields = append(fields, logging.Duration("audio_duration", audio))
+			if estimate = s.speed.estimate(audio); estimate > 0 {
+				fields = append(fields, logging.Duration("estimate", estimate))
+			}
+		}
+		fileLogger.Info("sending for transcription", fields...)
+		if info, err := os.Stat(event.Path); err == nil {
+			// Report the stabilized size rather than the size at detection
+			event.Size = info.Size()
+		}
+		transcribe := s.transcribe
+		if event.Size > s.maxFileSize() {
+			switch s.config.TooLargeAction {
+			case TooLargeStub:
+				return s.writeStub(fileCtx, fileLogger, event, opts.archive, startTime)
+			case TooLargeSplit:
+				transcribe = s.transcribeSplit
+			default:
+				return s.skipTooLarge(fileLogger, event, startTime)
+			}
+		}
+		s.reportProgress(event, StageUploading, startTime, "")
+
+		_, transcribeSpan := s.tracer.Start(fileCtx, "transcribe",
+			tracing.Int64("file.size", event.Size),
+			tracing.String("model", s.config.Model),
+			tracing.String("language", s.config.Language),
+		)
+		var transcribeErr error
+		transcribeStart := time.Now()
+		result, transcribeErr = transcribe(fileCtx, fileLogger, event.Path)
+		transcribeTime = time.Since(transcribeStart)
+		transcribeSpan.RecordError(transcribeErr)
+		if transcribeErr == nil {
+			transcribeSpan.SetAttributes(
+				tracing.String("detected_language", result.Language),
+				tracing.Float64("audio.duration_seconds", result.Duration),
+			)
+		}
+		transcribeSpan.End()
+		if transcribeErr != nil {
+			if err := s.timeoutError(ctx, fileCtx); err != nil {
+				return s.failFile(fileLogger, "file processing timed out", event,
+					history.Record{Category: history.CategoryTimeout}, err, startTime,
+					logging.Duration("timeout", s.fileTimeout),
+				)
+			}
+			return s.failFile(fileLogger, "transcription failed after retries", event,
+				history.Record{Category: history.CategoryTranscription}, transcribeErr, startTime,
+				logging.Int("attempts", s.config.RetryCount),
+			)
+		}
+
+		fileLogger.Info("transcription complete",
+			logging.String("path", event.Path),
+			logging.String("language", result.Language),
+		)
+		st.Transcript = checkpoint.NewTranscript(result)
+		s.advance(st, checkpoint.Transcribed)
+	}
+
+	// Step 3: Write output, unless an earlier run wrote the note
+	var outputPath, archivePath string
+	var processing *ProcessingInfo
+	if st.Stage.Reached(checkpoint.Written) {
+		outputPath = st.Output
+		if opts.archive {
+			archivePath = s.freeArchivePath(event.Path, st.ArchivePath)
+		}
+		processing = s.processingInfo(event.Path, archivePath, result, time.Since(startTime))
+		fileLogger.Info("note already written, resuming at archive",
+			logging.String("path", event.Path),
+			logging.String("output", outputPath),
+		)
+	} else {
+		s.reportProgress(event, StageWriting, startTime, "")
+		writeOpts, route := s.outputOptions(event, result)
+		s.describeNote(fileCtx, fileLogger, &writeOpts, result.Text)
+		if route != "" {
+			fileLogger.Info("output routed",
+				logging.String("path", event.Path),
+				logging.String("route", route),
+				logging.String("output_dir", writeOpts.OutputDir),
+			)
+		}
+
+		// Plan the archive path up front so the note can link to it
+		if opts.archive {
+			archivePath = s.planArchive(event.Path)
+		}
+		processing = s.processingInfo(event.Path, archivePath, result, time.Since(startTime))
+		processing.SourceHash = st.Hash
+		writeOpts.Processing = processing
+
+		_, writeSpan := s.tracer.Start(fileCtx, "write",
+			tracing.String("output_dir", writeOpts.OutputDir),
+		)
+		var err error
+		outputPath, err = s.writeNote(fileCtx, fileLogger, event.Path, result.Text, writeOpts)
+		writeSpan.RecordError(err)
+		writeSpan.SetAttributes(tracing.String("output.path", outputPath))
+		writeSpan.End()
+		if err != nil {
+			if timeoutErr := s.timeoutError(ctx, fileCtx); timeoutErr != nil {
+				err = timeoutErr
+			}
+			return s.failFile(fileLogger, "failed to write output", event,
+				history.Record{Category: history.CategoryWrite}, err, startTime)
+		}
+
+		s.notes.add(st.Hash, outputPath, archivePath)
+		st.Output, st.ArchivePath = outputPath, archivePath
+		s.advance(st, checkpoint.Written)
+		fileLogger.Info("output written",
+			logging.String("source", event.Path),
+			logging.String("output", outputPath),
+		)
+		s.runWrittenStages(fileCtx, fileLogger, event.Path, outputPath, result)
+	}
+	span.SetAttributes(tracing.Float64("audio.duration_seconds", processing.Duration.Seconds()))
+
+	// Step 4: Archive the original file
+	finalStage := StageCompleted
+	if opts.archive {
+		_, archiveSpan := s.tracer.Start(fileCtx, "archive",
+			tracing.String("archive.path", archivePath),
+		)
+		err = s.archiveFile(fileCtx, event.Path, archivePath)
+		archiveSpan.RecordError(err)
+		archiveSpan.End()
+		if err != nil {
+			if timeoutErr := s.timeoutError(ctx, fileCtx); timeoutErr != nil {
+				err = timeoutErr
+			}
+			return s.failFile(fileLogger, "failed to archive file", event,
+				history.Record{Output: outputPath, Category: history.CategoryArchive, SourceHash: st.Hash}, err, startTime)
+		}
+		finalStage = StageArchived
+	}
+
+	// Step 5: Write subtitles next to the audio's final location
+	if !opts.archive {
+		s.writeSubtitles(fileLogger, event.Path, result)
+	} else if archivePath != "" {
+		s.writeSubtitles(fileLogger, archivePath, result)
+	}
+
+	elapsed := time.Since(startTime)
+	fields := []Field{
+		logging.String("path", event.Path),
+		logging.String("output", outputPath),
+		logging.Duration("elapsed", elapsed),
+	}
+	if transcribeTime > 0 {
+		fields = append(fields, logging.Duration("transcribe_time", transcribeTime))
+	}
+	if estimate > 0 {
+		fields = append(fields, logging.Duration("estimate", estimate))
+	}
+	fileLogger.Info("file processing complete", fields...)
+	s.reportProgress(event, finalStage, startTime, outputPath)
+	completed := checkpoint.Written
+	if opts.archive {
+		completed = checkpoint.Archived
+	}
+	audioSeconds := processing.Duration.Seconds()
+	provider := s.provider(result)
+	s.recordOutcome(event, history.Record{
+		Output:       outputPath,
+		AudioSeconds: audioSeconds,
+		Words:        len(strings.Fields(result.Text)),
+		TranscribeMs: transcribeTime.Milliseconds(),
+		Provider:     provider.name,
+		Cost:         provider.config.cost(audioSeconds),
+		Stage:        string(completed),
+		SourceHash:   st.Hash,
+	}, nil, startTime)
+	return nil
+}
+
+// planArchive returns where path will be archived, or "" when the archiver
+// cannot say in advance.
+func (s *Service) planArchive(path string) string {
+	if planner, ok := s.archiver.(ArchivePlanner); ok {
+		return planner.DestinationPath(path, s.config.ArchiveDir)
+	}
+	return ""
+}
+
+// archiveFile moves path to archivePath, as returned by planArchive, or into
+// the archive directory when it is empty. Its sidecar file goes with it.
+func (s *Service) archiveFile(ctx context.Context, path, archivePath string) error {
+	var err error
+	if planner, ok := s.archiver.(ArchivePlanner); ok && archivePath != "" {
+		err = planner.ArchiveTo(ctx, path, archivePath)
+	} else {
+		err = s.archiver.Archive(ctx, path, s.config.ArchiveDir)
+	}
+	if err != nil {
+		return err
+	}
+	s.archiveSidecar(ctx, path, archivePath)
+	return nil
+}
+
+// timeoutError returns ErrFileTimeout if fileCtx hit its deadline while the
+// service context is still live, and nil otherwise.
+func (s *Service) timeoutError(ctx, fileCtx context.Context) error {
+	if ctx.Err() == nil && errors.Is(fileCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s (file_timeout_minutes)", ErrFileTimeout, s.fileTimeout)
+	}
+	return nil
+}
+
+// transcribeWith transcribes path with tc and opts, retrying as configured.
+func (s *Service) transcribeWith(ctx context.Context, fileLogger Logger, tc TranscriptionClient, path string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	var result *TranscriptionResult
+	var err error
+
+	for attempt := 1; attempt <= s.config.RetryCount; attempt++ {
+		result, err = tc.Transcribe(ctx, path, opts)
+		if err == nil {
+			return s.redact(fileLogger, path, result), nil
+		}
+
+		if attempt < s.config.RetryCount {
+			fileLogger.Error("transcription failed, retrying", err,
+				logging.String("path", path),
+				logging.Int("attempt", attempt),
+				logging.Int("max_attempts", s.config.RetryCount),
+			)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+	}
+
+	return nil, err
+}
+
+// Preview is the result of running a single file through the pipeline without side effects.
+type Preview struct {
+	// Note is the rendered note content.
+	Note string
+	// OutputPath is where the note would be written.
+	OutputPath string
+	// Language is the language reported by the transcription API.
+	Language string
+	// Elapsed is the time spent transcribing.
+	Elapsed time.Duration
+}
+
+// Preview transcribes a single file against the configured API and renders the
+// note that would be written, without writing output or archiving the audio.
+// The file is assumed to be complete, so stabilization is skipped.
+func (s *Service) Preview(ctx context.Context, path string) (*Preview, error) {
+	renderer, ok := s.writer.(NoteRenderer)
+	if !ok {
+		return nil, fmt.Errorf("output writer %T does not support previews", s.writer)
+	}
+
+	fileLogger := s.componentLogger("preview")
+	startTime := time.Now()
+
+	info, err := s.statAudio(path)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.transcribe(ctx, fileLogger, path)
+	if err != nil {
+		return nil, fmt.Errorf("transcription failed: %w", err)
+	}
+
+	event := FileEvent{Path: path, Size: info.Size(), Timestamp: time.Now()}
+	writeOpts, _ := s.outputOptions(event, result)
+	s.describeNote(ctx, fileLogger, &writeOpts, result.Text)
+	writeOpts.Processing = s.processingInfo(path, "", result, time.Since(startTime))
+
+	note, err := renderer.Render(result.Text, writeOpts)
+	if err != nil {
+		return nil, fmt.Errorf("render note: %w", err)
+	}
+
+	return &Preview{
+		Note:       note,
+		OutputPath: renderer.OutputPath(writeOpts),
+		Language:   result.Language,
+		Elapsed:    time.Since(startTime),
+	}, nil
+}
+
+// Transcript transcribes a single file against the configured API and
+// returns the transcript the note would be written with, after hooks,
+// pipeline stages and redaction, without writing output or archiving the
+// audio.
+func (s *Service) Transcript(ctx context.Context, path string) (*TranscriptionResult, error) {
+	if _, err := s.statAudio(path); err != nil {
+		return nil, err
+	}
+	result, err := s.transcribe(ctx, s.componentLogger("transcript"), path)
+	if err != nil {
+		return nil, fmt.Errorf("transcription failed: %w", err)
+	}
+	return result, nil
+}
+
+// statAudio returns the FileInfo of an audio file transcribed outside the
+// pipeline, rejecting files over max_file_size_mb.
+func (s *Service) statAudio(path string) (os.FileInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSize := int64(s.config.MaxFileSizeMB) * 1024 * 1024
+	if info.Size() > maxSize {
+		return nil, fmt.Errorf("file too large: %d bytes exceeds max_file_size_mb (%d MB)", info.Size(), s.config.MaxFileSizeMB)
+	}
+	return info, nil
+}
+
+// outputOptions builds the writer options for a file event, applying the
+// first routing rule that matches and then the file's sidecar. It also
+// returns the matched rule's name, or "" when the defaults apply. result may
+// be nil before transcription, in which case language conditions never
+// match.
+func (s *Service) outputOptions(event FileEvent, result *TranscriptionResult) (OutputOptions, string) {
+	opts := OutputOptions{
+		OutputDir:  s.config.OutputDir,
+		SourceFile: event.Path,
+		Timestamp:  event.Timestamp,
+		Locale:     s.config.Locale,
+		Format:     s.config.OutputFormat,
+		Footer:     s.config.MetadataFooter,
+	}
+	if s.config.TemplatePath != nil {
+		opts.TemplatePath = *s.config.TemplatePath
+	}
+	route := s.routeOutput(&opts, event, result)
+	if sc := s.sidecar(event.Path); sc != nil {
+		if sc.OutputDir != "" {
+			opts.OutputDir = sc.OutputDir
+		}
+		if sc.TemplatePath != "" {
+			opts.TemplatePath = sc.TemplatePath
+		}
+		opts.Title = sc.Title
+	}
+	return opts, route
+}
+
+// routeOutput applies the first routing rule matching the file to opts and
+// returns its name, or "" when none does.
+func (s *Service) routeOutput(opts *OutputOptions, event FileEvent, result *TranscriptionResult) string {
+	if s.router == nil {
+		return ""
+	}
+
+	file := routeFile{Path: event.Path, recordingLabels: s.devices.detect(event.Path)}
+	if result != nil {
+		file.Language = result.Language
+		file.Duration = time.Duration(result.Duration * float64(time.Second))
+	}
+	if file.Duration == 0 && s.router.needsDuration() {
+		file.Duration = audioDuration(event.Path)
+	}
+
+	rule, i := s.router.match(file)
+	if rule == nil {
+		return ""
+	}
+	if rule.OutputDir != "" {
+		opts.OutputDir = rule.OutputDir
+	}
+	if rule.TemplatePath != "" {
+		opts.TemplatePath = rule.TemplatePath
+	}
+	return rule.label(i)
+}
+
+// transcribeOptions builds the options path is transcribed with, applying
+// the prompt and vocabulary of the first routing rule that matches and the
+// language of the file's sidecar. The language is not known yet, so rules
+// with a language condition never match.
+func (s *Service) transcribeOptions(path string) TranscribeOptions {
+	opts := TranscribeOptions{
+		Language:   s.config.Language,
+		Prompt:     s.config.InitialPrompt,
+		Vocabulary: s.config.Vocabulary,
+		VADFilter:  s.config.VADFilter,
+	}
+	if sc := s.sidecar(path); sc != nil && sc.Language != "" {
+		opts.Language = sc.Language
+	}
+	if s.router == nil {
+		return opts
+	}
+
+	file := routeFile{Path: path, recordingLabels: s.devices.detect(path)}
+	if s.router.needsDuration() {
+		file.Duration = audioDuration(path)
+	}
+	rule, _ := s.router.match(file)
+	if rule == nil {
+		return opts
+	}
+	if rule.InitialPrompt != "" {
+		opts.Prompt = rule.InitialPrompt
+	}
+	if len(rule.Vocabulary) > 0 {
+		opts.Vocabulary = append(slices.Clip(opts.Vocabulary), rule.Vocabulary...)
+	}
+	return opts
+}
+
+// describeNote sets the note's title and tags from its transcript, as
+// title_strategy and tag_strategy select.
+func (s *Service) describeNote(ctx context.Context, fileLogger Logger, opts *OutputOptions, text string) {
+	// A title from the sidecar file stands
+	if opts.Title == "" {
+		opts.Title = s.noteTitle(ctx, fileLogger, text)
+	}
+	opts.Tags = s.noteTags(ctx, fileLogger, text)
+}
+
+// processingInfo describes how the note for path was produced. A nil
+// result describes a note written without transcribing the audio.
+func (s *Service) processingInfo(path, archivePath string, result *TranscriptionResult, elapsed time.Duration) *ProcessingInfo {
+	info := &ProcessingInfo{
+		SourcePath:     path,
+		ArchivePath:    archivePath,
+		ProcessingTime: elapsed,
+		Version:        Version,
+	}
+	if result != nil {
+		provider := s.provider(result)
+		info.Provider = provider.name
+		info.Model = provider.config.Model
+		info.Language = result.Language
+		info.Duration = time.Duration(result.Duration * float64(time.Second))
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		info.SourcePath = abs
+	}
+	labels := s.devices.detect(path)
+	info.Device = labels.Device
+	info.RecordingSource = labels.RecordingSource
+	if result != nil && info.Language == "" && s.config.Language != DefaultLanguage {
+		info.Language = s.config.Language
+	}
+	if info.Duration == 0 {
+		info.Duration = audioDuration(path)
+	}
+	return info
+}
+
+// logDryRun logs the upload, output and archive steps the file would go
+// through, with resolved paths, without performing any of them.
+func (s *Service) logDryRun(fileLogger Logger, event FileEvent, startTime time.Time, archive bool) {
+	if info, err := os.Stat(event.Path); err == nil {
+		event.Size = info.Size()
+	}
+
+	writeOpts, route := s.outputOptions(event, nil)
+	outputPath := "unknown"
+	if renderer, ok := s.writer.(NoteRenderer); ok {
+		outputPath = renderer.OutputPath(writeOpts)
+	}
+	archivePath := "unknown"
+	if planner, ok := s.archiver.(ArchivePlanner); ok {
+		archivePath = planner.DestinationPath(event.Path, s.config.ArchiveDir)
+	}
+
+	fileLogger.Info("dry run: would send for transcription",
+		logging.String("path", event.Path),
+		logging.Int64("size", event.Size),
+		logging.String("api_url", s.config.target()),
+		logging.String("language", s.config.Language),
+		logging.String("model", s.config.Model),
+	)
+	writeFields := []logging.Field{
+		logging.String("source", event.Path),
+		logging.String("output", outputPath),
+	}
+	if writeOpts.TemplatePath != "" {
+		writeFields = append(writeFields, logging.String("template", writeOpts.TemplatePath))
+	}
+	if route != "" {
+		writeFields = append(writeFields, logging.String("route", route))
+	}
+	fileLogger.Info("dry run: would write output", writeFields...)
+
+	detail := fmt.Sprintf("would upload to %s, write %s", s.config.target(), outputPath)
+	if archive {
+		fileLogger.Info("dry run: would archive file",
+			logging.String("path", event.Path),
+			logging.String("archive", archivePath),
+		)
+		detail += ", archive to " + archivePath
+	}
+
+	s.report(ProgressEvent{
+		Path:    event.Path,
+		Stage:   StageDryRun,
+		Size:    event.Size,
+		Elapsed: time.Since(startTime),
+		Output:  outputPath,
+		Detail:  detail,
+	})
+}
+
+// reportProgress notifies the progress reporter, if any, of a stage transition.
+func (s *Service) reportProgress(event FileEvent, stage Stage, startTime time.Time, outputPath string) {
+	s.report(ProgressEvent{
+		Path:    event.Path,
+		Stage:   stage,
+		Size:    event.Size,
+		Elapsed: time.Since(startTime),
+		Output:  outputPath,
+	})
+}
+
+// report records a stage transition in the live state and passes it to the
+// progress reporter, if any.
+func (s *Service) report(ev ProgressEvent) {
+	s.live.stage(ev.Path, ev.Stage)
+	if s.progress != nil {
+		s.progress.Report(ev)
+	}
+}
+
+// failFile logs msg as an error tagged with the failure's category, records
+// the failure to history and returns err.
+func (s *Service) failFile(fileLogger Logger, msg string, event FileEvent, rec history.Record, err error, startTime time.Time, fields ...Field) error {
+	rec.Category = classifyFailure(rec.Category, err)
+	fields = append([]Field{logging.String("path", event.Path)}, fields...)
+	fields = append(fields, logging.String("category", rec.Category))
+	fileLogger.Error(msg, err, fields...)
+	s.recordOutcome(event, rec, err, startTime)
+	return err
+}
+
+// classifyFailure returns the history category for err, a failure in the
+// step whose category is step. Timeouts, an unreachable API, requests the
+// API rejected and low disk space are categorized as such whichever step
+// they hit.
+func classifyFailure(step string, err error) string {
+	switch {
+	case errors.Is(err, ErrFileTimeout):
+		return history.CategoryTimeout
+	case errors.Is(err, ErrAPIUnreachable):
+		return history.CategoryAPIUnreachable
+	case errors.Is(err, ErrInsufficientDiskSpace):
+		return history.CategoryDiskSpace
+	case errors.Is(err, ErrBudgetExceeded):
+		return history.CategoryBudget
+	case errors.Is(err, ErrHookFailed), errors.Is(err, ErrStageFailed):
+		return history.CategoryHook
+	case errors.Is(err, ErrFileTooLarge):
+		return history.CategoryTooLarge
+	case errors.Is(err, stabilizer.ErrStabilizationTimeout):
+		return history.CategoryStabilizationTimeout
+	case client.StatusCode(err)/100 == 4:
+		return history.CategoryAPI4xx
+	}
+	return step
+}
+
+// recordOutcome appends the result of processing a file to the history store.
+// rec carries the output path and, for failures, the category of the step
+// that failed, refined by classifyFailure; a nil err records a completed
+// file unless rec already has a status.
+func (s *Service) recordOutcome(event FileEvent, rec history.Record, err error, startTime time.Time) {
+	rec.Time = s.clock.Now().UTC()
+	rec.Source = event.Path
+	if rec.Status == "" {
+		rec.Status = history.StatusCompleted
+	}
+	rec.ElapsedMs = time.Since(startTime).Milliseconds()
+	if err != nil {
+		rec.Status = history.StatusFailed
+		rec.Error = err.Error()
+		rec.Category = classifyFailure(rec.Category, err)
+		s.report(ProgressEvent{
+			Path:    event.Path,
+			Stage:   StageFailed,
+			Size:    event.Size,
+			Elapsed: time.Since(startTime),
+			Output:  rec.Output,
+			Err:     err,
+		})
+	}
+	s.finishCheckpoint(&rec)
+	s.live.record(rec)
+	s.emit(outcomeEvent(event, rec))
+
+	if err := s.history.Append(rec); err != nil {
+		s.logger.Error("failed to record history", err,
+			logging.String("path", event.Path),
+		)
+	}
+	s.speed.add(rec)
+	if s.budget != nil {
+		s.budget.add(rec)
+	}
+	for _, fb := range s.fallbacks {
+		if fb.budget != nil {
+			fb.budget.add(rec)
+		}
+	}
+}
+
+// shutdown performs graceful shutdown of the service.
+func (s *Service) shutdown() error {
+	close(s.stopCh)
+
+	// Stop the watcher
+	if err := s.watcher.Stop(); err != nil {
+		s.logger.Error("error stopping watcher", err)
+	}
+
+	// Wait for in-flight file processing and jobs to complete
+	s.logger.Info("waiting for in-flight processing to complete")
+	s.wg.Wait()
+
+	s.flushTraces()
+
+	// Close the logger
+	s.logger.Info("transcription service stopped")
+	return s.closeLogger()
+}
+
+// flushTraces exports any spans still waiting to be sent.
+func (s *Service) flushTraces() {
+	ctx, cancel := context.WithTimeout(context.Background(), tracing.DefaultTimeout)
+	defer cancel()
+	if err := s.tracer.Shutdown(ctx); err != nil {
+		s.logger.Error("failed to export traces", err)
+	}
+}
+
+// Close releases the resources of a service that was not started with Run.
+func (s *Service) Close() error {
+	s.flushTraces()
+	if err := s.watcher.Stop(); err != nil {
+		s.closeLogger()
+		return err
+	}
+	return s.closeLogger()
+}
+
+// Stop signals the service to stop.
+func (s *Service) Stop() {
+	close(s.stopCh)
+}
@@ -0,0 +1,250 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/stabilizer"
+)
+
+type recordingLogger struct {
+	mu       sync.Mutex
+	messages []string
+	closed   bool
+}
+
+func (l *recordingLogger) Info(msg string, fields ...Field)             { l.add(msg) }
+func (l *recordingLogger) Error(msg string, err error, fields ...Field) { l.add(msg) }
+func (l *recordingLogger) Debug(msg string, fields ...Field)            { l.add(msg) }
+
+func (l *recordingLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	return nil
+}
+
+func (l *recordingLogger) add(msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, msg)
+}
+
+func TestNewServiceWith_InjectedComponents(t *testing.T) {
+	cfg := setupBuilderTest(t)
+
+	fw := &fakeWatcher{events: make(chan FileEvent, 1)}
+	ow := &recordingWriter{}
+	arch := &recordingArchiver{archived: make(chan string, 1)}
+	logger := &recordingLogger{}
+
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    fw,
+		Stabilizer: fakeStabilizer{},
+		Client:     fakeClient{},
+		Writer:     ow,
+		Archiver:   arch,
+		Logger:     logger,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- svc.Run(ctx) }()
+
+	fw.events <- FileEvent{Path: "/audio/memo.m4a", Size: 10, Timestamp: time.Now()}
+
+	select {
+	case <-arch.archived:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for file to be archived")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected clean shutdown, got: %v", err)
+	}
+
+	logged := strings.Join(logger.messages, "\n")
+	if !strings.Contains(logged, "file processing complete") {
+		t.Errorf("expected pipeline logs on injected logger, got:\n%s", logged)
+	}
+	if logger.closed {
+		t.Error("expected injected logger not to be closed by the service")
+	}
+
+	// Nothing is logged to the default log directory
+	if _, err := os.Stat(filepath.Join(os.Getenv("HOME"), ".nota", "logs")); !os.IsNotExist(err) {
+		t.Error("expected default log directory not to be created")
+	}
+}
+
+func TestRun_WatcherStopped(t *testing.T) {
+	cfg := setupBuilderTest(t)
+
+	fw := &fakeWatcher{events: make(chan FileEvent)}
+	svc, err := NewServiceWith(cfg, Options{Watcher: fw, Logger: &recordingLogger{}})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- svc.Run(context.Background()) }()
+	close(fw.events)
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrWatcherStopped) {
+			t.Errorf("expected ErrWatcherStopped, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the service to stop")
+	}
+	if sig := svc.StopSignal(); sig != nil {
+		t.Errorf("expected no stop signal, got %v", sig)
+	}
+}
+
+func TestNewServiceWith_DefaultsForNilComponents(t *testing.T) {
+	cfg := setupBuilderTest(t)
+
+	svc, err := NewServiceWith(cfg, Options{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	if _, ok := svc.writer.(NoteRenderer); !ok {
+		t.Errorf("expected default writer to support rendering, got: %T", svc.writer)
+	}
+	if _, ok := svc.archiver.(ArchivePlanner); !ok {
+		t.Errorf("expected default archiver to support planning, got: %T", svc.archiver)
+	}
+}
+
+type hangingClient struct{}
+
+func (hangingClient) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestProcessFile_Timeout(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.RetryCount = 1
+
+	arch := &countingArchiver{}
+	svc, err := NewBuilder(cfg).
+		WithWatcher(&fakeWatcher{}).
+		WithClient(hangingClient{}).
+		WithWriter(&recordingWriter{}).
+		WithArchiver(arch).
+		Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+	svc.fileTimeout = 50 * time.Millisecond
+
+	audioPath := filepath.Join(cfg.WatchDir, "corrupt.m4a")
+	os.WriteFile(audioPath, []byte("audio"), 0644)
+
+	err = svc.processFile(context.Background(), FileEvent{Path: audioPath, Size: 5}, processOptions{archive: true})
+	if !errors.Is(err, ErrFileTimeout) {
+		t.Fatalf("expected ErrFileTimeout, got: %v", err)
+	}
+	if arch.count.Load() != 0 {
+		t.Error("expected timed out file not to be archived")
+	}
+
+	historyPath, _ := history.DefaultPath()
+	records, _ := history.New(historyPath).Load(time.Time{})
+	if len(records) != 1 || records[0].Status != history.StatusFailed || !strings.Contains(records[0].Error, "timed out") {
+		t.Errorf("expected failed history record with timeout error, got: %+v", records)
+	}
+	if len(records) == 1 && records[0].Category != history.CategoryTimeout {
+		t.Errorf("expected timeout category, got: %q", records[0].Category)
+	}
+}
+
+type gatedClient struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (c *gatedClient) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	c.calls.Add(1)
+	<-c.release
+	return &TranscriptionResult{Text: "ok"}, nil
+}
+
+func TestHandleFileEvent_IgnoresDuplicateWhileInFlight(t *testing.T) {
+	cfg := setupBuilderTest(t)
+
+	tc := &gatedClient{release: make(chan struct{})}
+	svc, err := NewBuilder(cfg).
+		WithWatcher(&fakeWatcher{}).
+		WithStabilizer(fakeStabilizer{}).
+		WithClient(tc).
+		WithWriter(&recordingWriter{}).
+		WithArchiver(&countingArchiver{}).
+		Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	audioPath := filepath.Join(cfg.WatchDir, "memo.m4a")
+	os.WriteFile(audioPath, []byte("audio"), 0644)
+	event := FileEvent{Path: audioPath, Size: 5}
+
+	svc.handleFileEvent(context.Background(), event)
+	for tc.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	svc.handleFileEvent(context.Background(), event)
+
+	close(tc.release)
+	svc.wg.Wait()
+
+	if tc.calls.Load() != 1 {
+		t.Errorf("expected 1 transcription, got: %d", tc.calls.Load())
+	}
+}
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		step     string
+		err      error
+		expected string
+	}{
+		{"step category kept", history.CategoryWrite, errors.New("permission denied"), history.CategoryWrite},
+		{"timeout in any step", history.CategoryArchive, fmt.Errorf("%w after 30m", ErrFileTimeout), history.CategoryTimeout},
+		{"unreachable API", history.CategoryTranscription, fmt.Errorf("send request: %w", ErrAPIUnreachable), history.CategoryAPIUnreachable},
+		{"rejected request", history.CategoryTranscription, &client.APIError{StatusCode: 413, Body: "too large"}, history.CategoryAPI4xx},
+		{"server error", history.CategoryTranscription, &client.APIError{StatusCode: 503}, history.CategoryTranscription},
+		{"stabilization timeout", history.CategoryStabilization, stabilizer.ErrStabilizationTimeout, history.CategoryStabilizationTimeout},
+		{"disk space", history.CategoryWrite, fmt.Errorf("%w: /out", ErrInsufficientDiskSpace), history.CategoryDiskSpace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyFailure(tt.step, tt.err); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package transcribe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// SidecarSuffix is appended to a recording's name to name its sidecar file,
+// e.g. memo.m4a.nota.json.
+const SidecarSuffix = ".nota.json"
+
+// Sidecar holds overrides for one recording, read from a JSON file next to
+// it. Phone shortcuts can write one at capture time to tag a recording for
+// a meeting folder or another language. Empty fields keep the configured
+// and routed settings.
+type Sidecar struct {
+	// Language replaces language when transcribing.
+	Language string `json:"language,omitempty"`
+	// TemplatePath and OutputDir replace those of the config and routing
+	// rules. A relative output_dir is within the vault; values leading out
+	// of the vault are ignored.
+	TemplatePath string `json:"template_path,omitempty"`
+	OutputDir    string `json:"output_dir,omitempty"`
+	// Title is the note's title, in place of title_strategy's.
+	Title string `json:"title,omitempty"`
+}
+
+// SidecarPath returns the path of the sidecar file for audioPath.
+func SidecarPath(audioPath string) string {
+	return audioPath + SidecarSuffix
+}
+
+// ReadSidecar reads the sidecar file of audioPath. It returns nil and no
+// error when there is none, and an error wrapping ErrUnsafePath when it is a
+// symlink or not a regular file, so a sidecar cannot point at a file outside
+// the watched directory.
+func ReadSidecar(audioPath string) (*Sidecar, error) {
+	data, _, err := readSidecarFile(SidecarPath(audioPath))
+	if data == nil || err != nil {
+		return nil, err
+	}
+	return parseSidecar(audioPath, data)
+}
+
+// readSidecarFile reads the sidecar file at path along with its file info.
+// It returns no data and no error when there is none.
+func readSidecarFile(path string) ([]byte, os.FileInfo, error) {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, nil, fmt.Errorf("%w: %s is not a regular file", ErrUnsafePath, path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	// A link swapped in after the Lstat is caught here
+	opened, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !os.SameFile(info, opened) {
+		return nil, nil, fmt.Errorf("%w: %s changed while being read", ErrUnsafePath, path)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, info, nil
+}
+
+func parseSidecar(audioPath string, data []byte) (*Sidecar, error) {
+	var sc Sidecar
+	if err := json.Unmarshal(data, &sc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", SidecarPath(audioPath), err)
+	}
+	return &sc, nil
+}
+
+// cachedSidecar is a sidecar as read and resolved by Service.sidecar, kept
+// until its file changes or the recording is done.
+type cachedSidecar struct {
+	modTime time.Time
+	size    int64
+	sidecar *Sidecar
+}
+
+// sidecar returns the overrides for audioPath with their paths resolved, or
+// nil when it has none. An unreadable sidecar is logged and overrides
+// nothing. The file is parsed once per recording; the cached overrides are
+// dropped by forgetSidecar.
+func (s *Service) sidecar(audioPath string) *Sidecar {
+	data, info, err := readSidecarFile(SidecarPath(audioPath))
+	if err != nil {
+		s.logger.Error("failed to read sidecar file, ignoring it", err, logging.String("path", audioPath))
+		return nil
+	}
+	if data == nil {
+		s.forgetSidecar(audioPath)
+		return nil
+	}
+
+	s.mu.Lock()
+	cached, ok := s.sidecars[audioPath]
+	s.mu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return copySidecar(cached.sidecar)
+	}
+
+	sc, err := parseSidecar(audioPath, data)
+	if err != nil {
+		s.logger.Error("failed to read sidecar file, ignoring it", err, logging.String("path", audioPath))
+		return nil
+	}
+	s.resolveSidecar(audioPath, sc)
+
+	s.mu.Lock()
+	if s.sidecars == nil {
+		s.sidecars = make(map[string]cachedSidecar)
+	}
+	s.sidecars[audioPath] = cachedSidecar{modTime: info.ModTime(), size: info.Size(), sidecar: sc}
+	s.mu.Unlock()
+	return copySidecar(sc)
+}
+
+// forgetSidecar drops the cached sidecar of audioPath.
+func (s *Service) forgetSidecar(audioPath string) {
+	s.mu.Lock()
+	delete(s.sidecars, audioPath)
+	s.mu.Unlock()
+}
+
+func copySidecar(sc *Sidecar) *Sidecar {
+	c := *sc
+	return &c
+}
+
+// resolveSidecar resolves the paths of sc, which was read for audioPath.
+// The sidecar comes from wherever the recording did, so output_dir must lie
+// within the vault, or the output directory when there is none, and
+// template_path must name a vault template or lie within the vault. Other
+// values are logged and ignored.
+func (s *Service) resolveSidecar(audioPath string, sc *Sidecar) {
+	vaultRoot := s.config.vaultRoot
+	if sc.OutputDir != "" {
+		root := vaultRoot
+		if root == "" {
+			root = s.config.OutputDir
+		}
+		dir := ResolvePath(sc.OutputDir, vaultRoot)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(root, dir)
+		}
+		dir = filepath.Clean(dir)
+		if root != "" && resolvedWithin(filepath.Clean(root), dir) {
+			sc.OutputDir = dir
+		} else {
+			s.logger.Error("sidecar output_dir is outside the vault, ignoring it",
+				fmt.Errorf("%w: %s is outside %s", ErrUnsafePath, dir, root),
+				logging.String("path", audioPath))
+			sc.OutputDir = ""
+		}
+	}
+
+	if sc.TemplatePath != "" {
+		if isTemplateName(sc.TemplatePath) {
+			sc.TemplatePath = ResolveTemplate(sc.TemplatePath, vaultRoot)
+			return
+		}
+		path := ResolvePath(sc.TemplatePath, vaultRoot)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(vaultRoot, path)
+		}
+		path = filepath.Clean(path)
+		if vaultRoot != "" && resolvedWithin(filepath.Clean(vaultRoot), path) {
+			sc.TemplatePath = path
+		} else {
+			s.logger.Error("sidecar template_path is outside the vault, ignoring it",
+				fmt.Errorf("%w: %s is outside %s", ErrUnsafePath, path, vaultRoot),
+				logging.String("path", audioPath))
+			sc.TemplatePath = ""
+		}
+	}
+}
+
+// resolvedWithin reports whether path lies within dir both as written and
+// after resolving symlinks, so a link inside dir cannot lead out of it. path
+// need not exist yet. Both must be clean.
+func resolvedWithin(dir, path string) bool {
+	if !within(dir, path) {
+		return false
+	}
+	resolvedDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		// Nothing below a missing dir can be a link out of it
+		return errors.Is(err, os.ErrNotExist)
+	}
+	resolved, err := resolveExisting(path)
+	return err == nil && within(resolvedDir, resolved)
+}
+
+// archiveSidecar moves the sidecar of path, if any, next to where path was
+// archived, so reprocessing the archived audio applies it again. Failing to
+// is logged; the recording itself is archived.
+func (s *Service) archiveSidecar(ctx context.Context, path, archivePath string) {
+	src := SidecarPath(path)
+	if _, err := os.Lstat(src); err != nil {
+		return
+	}
+	var err error
+	if planner, ok := s.archiver.(ArchivePlanner); ok && archivePath != "" {
+		err = planner.ArchiveTo(ctx, src, SidecarPath(archivePath))
+	} else {
+		err = s.archiver.Archive(ctx, src, s.config.ArchiveDir)
+	}
+	if err != nil {
+		s.logger.Error("failed to archive sidecar file", err, logging.String("path", src))
+	}
+}
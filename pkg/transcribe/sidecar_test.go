@@ -0,0 +1,193 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// languageClient records the language each file was transcribed in.
+type languageClient struct {
+	language string
+}
+
+func (c *languageClient) Transcribe(ctx context.Context, audioPath string, opts TranscribeOptions) (*TranscriptionResult, error) {
+	c.language = opts.Language
+	return &TranscriptionResult{Text: "Kauf Milch.", Language: opts.Language}, nil
+}
+
+func TestReadSidecar(t *testing.T) {
+	dir := t.TempDir()
+	audioPath := filepath.Join(dir, "memo.m4a")
+
+	sc, err := ReadSidecar(audioPath)
+	if sc != nil || err != nil {
+		t.Errorf("expected no sidecar and no error, got %+v, %v", sc, err)
+	}
+
+	os.WriteFile(SidecarPath(audioPath), []byte(`{"language": "de", "title": "Standup"}`), 0644)
+	sc, err = ReadSidecar(audioPath)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if sc.Language != "de" || sc.Title != "Standup" {
+		t.Errorf("unexpected sidecar %+v", sc)
+	}
+
+	os.WriteFile(SidecarPath(audioPath), []byte(`{"language": `), 0644)
+	if _, err := ReadSidecar(audioPath); err == nil {
+		t.Error("expected an error for a malformed sidecar")
+	}
+}
+
+func TestReadSidecar_RejectsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	audioPath := filepath.Join(dir, "memo.m4a")
+	target := filepath.Join(t.TempDir(), "elsewhere.json")
+	os.WriteFile(target, []byte(`{"language": "de"}`), 0644)
+	if err := os.Symlink(target, SidecarPath(audioPath)); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	sc, err := ReadSidecar(audioPath)
+	if !errors.Is(err, ErrUnsafePath) {
+		t.Errorf("expected ErrUnsafePath, got %+v, %v", sc, err)
+	}
+}
+
+func TestProcessFile_AppliesSidecar(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+	cfg.ArchiveDir = t.TempDir()
+	meetings := filepath.Join(cfg.OutputDir, "Meetings")
+	cfg.Routes = []RouteRule{{Name: "all", OutputDir: filepath.Join(cfg.OutputDir, "routed")}}
+
+	tc := &languageClient{}
+	svc := newDedupService(t, cfg, tc)
+	audioPath := writeWAV(t, cfg.WatchDir, "memo.wav", 2*time.Second)
+	sidecar := `{"language": "de", "output_dir": "` + meetings + `", "title": "Standup Montag"}`
+	if err := os.WriteFile(SidecarPath(audioPath), []byte(sidecar), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{archive: true}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if tc.language != "de" {
+		t.Errorf("expected the sidecar's language, got %q", tc.language)
+	}
+	if len(svc.sidecars) != 0 {
+		t.Errorf("expected the cached sidecar dropped once the file is done, got %v", svc.sidecars)
+	}
+	notes, _ := filepath.Glob(filepath.Join(meetings, "*.md"))
+	if len(notes) != 1 {
+		t.Fatalf("expected the note in the sidecar's output_dir, got %v", notes)
+	}
+	note, _ := os.ReadFile(notes[0])
+	if !strings.Contains(string(note), "Standup Montag") {
+		t.Errorf("expected the sidecar's title, got:\n%s", note)
+	}
+
+	if _, err := os.Stat(SidecarPath(audioPath)); !os.IsNotExist(err) {
+		t.Errorf("expected the sidecar moved out of the watch directory, got: %v", err)
+	}
+	archived, _ := filepath.Glob(filepath.Join(cfg.ArchiveDir, "*", "*", "*", "memo.wav"+SidecarSuffix))
+	if len(archived) != 1 {
+		t.Errorf("expected the sidecar archived next to the recording, got %v", archived)
+	}
+}
+
+func TestService_SidecarIgnoredWhenMalformed(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.Language = "en"
+	svc := newDedupService(t, cfg, &countingClient{})
+
+	audioPath := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(SidecarPath(audioPath), []byte(`{"language": "de",`), 0644)
+
+	if opts := svc.transcribeOptions(audioPath); opts.Language != "en" {
+		t.Errorf("expected the configured language, got %q", opts.Language)
+	}
+	if opts, _ := svc.outputOptions(FileEvent{Path: audioPath}, nil); opts.OutputDir != cfg.OutputDir || opts.Title != "" {
+		t.Errorf("expected the configured output, got %+v", opts)
+	}
+}
+
+func TestService_SidecarRelativeOutputDir(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.vaultRoot = t.TempDir()
+	svc := newDedupService(t, cfg, &countingClient{})
+
+	audioPath := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(SidecarPath(audioPath), []byte(`{"output_dir": "Areas/Work", "template_path": "meeting"}`), 0644)
+
+	opts, _ := svc.outputOptions(FileEvent{Path: audioPath}, nil)
+	if want := filepath.Join(cfg.vaultRoot, "Areas", "Work"); opts.OutputDir != want {
+		t.Errorf("expected output_dir %s, got %s", want, opts.OutputDir)
+	}
+	if want := ResolveTemplate("meeting", cfg.vaultRoot); opts.TemplatePath != want {
+		t.Errorf("expected template %s, got %s", want, opts.TemplatePath)
+	}
+}
+
+func TestService_SidecarOverridesOutsideVault(t *testing.T) {
+	outside := t.TempDir()
+	tests := []struct {
+		name    string
+		sidecar string
+	}{
+		{"parent output_dir", `{"output_dir": "../Elsewhere"}`},
+		{"nested parent output_dir", `{"output_dir": "Areas/../../Elsewhere"}`},
+		{"absolute output_dir", `{"output_dir": "` + outside + `"}`},
+		{"vault variable output_dir", `{"output_dir": "${VAULT}/.."}`},
+		{"symlinked output_dir", `{"output_dir": "Link/Notes"}`},
+		{"parent template_path", `{"template_path": "../secret.md"}`},
+		{"absolute template_path", `{"template_path": "` + filepath.Join(outside, "t.md") + `"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := setupBuilderTest(t)
+			cfg.vaultRoot = t.TempDir()
+			if err := os.Symlink(outside, filepath.Join(cfg.vaultRoot, "Link")); err != nil {
+				t.Skipf("symlinks not supported: %v", err)
+			}
+			svc := newDedupService(t, cfg, &countingClient{})
+
+			audioPath := filepath.Join(t.TempDir(), "memo.m4a")
+			os.WriteFile(SidecarPath(audioPath), []byte(tt.sidecar), 0644)
+
+			opts, _ := svc.outputOptions(FileEvent{Path: audioPath}, nil)
+			if opts.OutputDir != cfg.OutputDir {
+				t.Errorf("expected the configured output_dir %s, got %s", cfg.OutputDir, opts.OutputDir)
+			}
+			if opts.TemplatePath != "" {
+				t.Errorf("expected no template, got %s", opts.TemplatePath)
+			}
+		})
+	}
+}
+
+func TestService_SidecarTemplatePathInVault(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.vaultRoot = t.TempDir()
+	svc := newDedupService(t, cfg, &countingClient{})
+
+	audioPath := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(SidecarPath(audioPath), []byte(`{"template_path": "Templates/meeting.md"}`), 0644)
+
+	opts, _ := svc.outputOptions(FileEvent{Path: audioPath}, nil)
+	if want := filepath.Join(cfg.vaultRoot, "Templates", "meeting.md"); opts.TemplatePath != want {
+		t.Errorf("expected template %s, got %s", want, opts.TemplatePath)
+	}
+
+	os.WriteFile(SidecarPath(audioPath), []byte(`{"template_path": "Templates/standup.md", "language": "de"}`), 0644)
+	if opts, _ := svc.outputOptions(FileEvent{Path: audioPath}, nil); !strings.HasSuffix(opts.TemplatePath, "standup.md") {
+		t.Errorf("expected the changed sidecar read again, got %s", opts.TemplatePath)
+	}
+}
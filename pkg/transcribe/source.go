@@ -0,0 +1,180 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/dirs"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/s3"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/remote"
+)
+
+// Remote source types.
+const (
+	SourceS3     = "s3"
+	SourceWebDAV = "webdav"
+)
+
+// DefaultSourcePollIntervalSeconds is how often a remote source is listed
+// when poll_interval_seconds is not set.
+const DefaultSourcePollIntervalSeconds = 60
+
+// SourceConfig adds a remote location that is polled for new recordings,
+// alongside any watched directories. New files matching watch_patterns are
+// downloaded to download_dir and go through the normal pipeline.
+type SourceConfig struct {
+	// Type is "s3" or "webdav".
+	Type string `json:"type"`
+	// URL is the S3 endpoint (empty for AWS) or the WebDAV directory URL.
+	URL string `json:"url,omitempty"`
+	// Bucket and Prefix select the S3 objects to poll.
+	Bucket string `json:"bucket,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	// Region is the S3 signing region; us-east-1 when empty.
+	Region string `json:"region,omitempty"`
+	// AccessKeyID and SecretAccessKey default to the AWS_ACCESS_KEY_ID and
+	// AWS_SECRET_ACCESS_KEY environment variables.
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+	// Username and Password are WebDAV basic authentication credentials.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// PollIntervalSeconds is how often the source is listed.
+	PollIntervalSeconds int `json:"poll_interval_seconds,omitempty"`
+	// DownloadDir holds downloaded files until they are processed; defaults
+	// to downloads in the state directory.
+	DownloadDir string `json:"download_dir,omitempty"`
+	// DeleteAfterDownload removes files from the source once downloaded.
+	DeleteAfterDownload bool `json:"delete_after_download,omitempty"`
+}
+
+// validate checks the type and the settings it requires.
+func (c SourceConfig) validate() error {
+	if c.URL != "" {
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: url %q must be an http or https URL", ErrInvalidSource, c.URL)
+		}
+	}
+	switch c.Type {
+	case SourceS3:
+		if c.Bucket == "" {
+			return fmt.Errorf("%w: bucket is required for s3", ErrInvalidSource)
+		}
+	case SourceWebDAV:
+		if c.URL == "" {
+			return fmt.Errorf("%w: url is required for webdav", ErrInvalidSource)
+		}
+	default:
+		return fmt.Errorf("%w: type must be %s or %s (got %q)", ErrInvalidSource, SourceS3, SourceWebDAV, c.Type)
+	}
+	return nil
+}
+
+// downloadDir returns the configured download directory or the default.
+func (c SourceConfig) downloadDir() (string, error) {
+	if c.DownloadDir != "" {
+		return c.DownloadDir, nil
+	}
+	dir, err := dirs.State()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "downloads"), nil
+}
+
+// pollInterval returns the configured poll interval or the default.
+func (c SourceConfig) pollInterval() time.Duration {
+	if c.PollIntervalSeconds <= 0 {
+		return DefaultSourcePollIntervalSeconds * time.Second
+	}
+	return time.Duration(c.PollIntervalSeconds) * time.Second
+}
+
+// String describes the source for logs and the startup banner.
+func (c SourceConfig) String() string {
+	if c.Type == SourceS3 {
+		return "s3://" + c.Bucket + "/" + c.Prefix
+	}
+	return c.URL
+}
+
+// store returns the remote store the config describes.
+func (c SourceConfig) store() (remote.Store, error) {
+	if c.Type == SourceWebDAV {
+		return &remote.WebDAVStore{URL: c.URL, Username: c.Username, Password: c.Password}, nil
+	}
+
+	accessKey, secretKey := c.AccessKeyID, c.SecretAccessKey
+	if accessKey == "" {
+		accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if secretKey == "" {
+		secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	client, err := s3.New(c.URL, c.Region, accessKey, secretKey)
+	if err != nil {
+		return nil, err
+	}
+	return &remote.S3Store{Client: client, Bucket: c.Bucket, Prefix: c.Prefix}, nil
+}
+
+// pollSource starts polling the remote source, if configured, and returns
+// the downloaded files as events; nil when no source is configured. A dry
+// run only lists the source and logs what would be downloaded.
+func (s *Service) pollSource(ctx context.Context) (<-chan FileEvent, error) {
+	if s.config.Source == nil {
+		return nil, nil
+	}
+	cfg := *s.config.Source
+
+	store, err := cfg.store()
+	if err != nil {
+		return nil, fmt.Errorf("start remote source: %w", err)
+	}
+	dir, err := cfg.downloadDir()
+	if err != nil {
+		return nil, fmt.Errorf("start remote source: %w", err)
+	}
+
+	logger := s.componentLogger("remote")
+	poller := &remote.Poller{
+		Store:               store,
+		Dir:                 dir,
+		Patterns:            s.config.WatchPatterns,
+		Interval:            cfg.pollInterval(),
+		DeleteAfterDownload: cfg.DeleteAfterDownload,
+		OnDownload: func(obj remote.Object, path string) {
+			logger.Info("downloaded remote file",
+				logging.String("key", obj.Key),
+				logging.String("path", path),
+			)
+		},
+		OnError: func(err error) {
+			logger.Error("remote source error", err)
+		},
+		DryRun: s.dryRun,
+		OnWouldDownload: func(obj remote.Object) {
+			logger.Info("dry run: would download remote file",
+				logging.String("key", obj.Key),
+				logging.Int64("size", obj.Size),
+			)
+		},
+	}
+	events, err := poller.Poll(ctx, s.config.WatchBufferSize)
+	if err != nil {
+		return nil, fmt.Errorf("start remote source: %w", err)
+	}
+
+	s.logger.Info("polling remote source",
+		logging.String("source", cfg.String()),
+		logging.String("download_dir", poller.Dir),
+		logging.String("patterns", fmt.Sprintf("%v", poller.Patterns)),
+	)
+	return events, nil
+}
@@ -0,0 +1,178 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSourceConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SourceConfig
+		wantErr bool
+	}{
+		{"s3 on AWS", SourceConfig{Type: SourceS3, Bucket: "recordings"}, false},
+		{"s3 endpoint", SourceConfig{Type: SourceS3, URL: "http://minio.lan:9000", Bucket: "recordings"}, false},
+		{"webdav", SourceConfig{Type: SourceWebDAV, URL: "https://cloud.example.com/dav/Recordings/"}, false},
+		{"s3 without bucket", SourceConfig{Type: SourceS3}, true},
+		{"webdav without url", SourceConfig{Type: SourceWebDAV}, true},
+		{"url without scheme", SourceConfig{Type: SourceS3, URL: "minio.lan:9000", Bucket: "b"}, true},
+		{"unknown type", SourceConfig{Type: "ftp", URL: "https://example.com"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validate()
+			if tt.wantErr && !errors.Is(err, ErrInvalidSource) {
+				t.Errorf("expected ErrInvalidSource, got: %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateSourceWithoutWatchDir(t *testing.T) {
+	cfg := &Config{
+		APIURL:    "http://localhost:9000",
+		OutputDir: "/tmp/out",
+		Source:    &SourceConfig{Type: SourceS3, Bucket: "recordings"},
+	}
+	cfg.ApplyDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected a source to stand in for a watch dir, got: %v", err)
+	}
+
+	cfg.Source.PollIntervalSeconds = -1
+	if err := cfg.Validate(); !errors.Is(err, ErrNegativeValue) {
+		t.Errorf("expected ErrNegativeValue, got: %v", err)
+	}
+}
+
+func TestService_RemoteSourceFeedsPipeline(t *testing.T) {
+	cfg := setupBuilderTest(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PROPFIND":
+			w.WriteHeader(http.StatusMultiStatus)
+			fmt.Fprint(w, `<d:multistatus xmlns:d="DAV:"><d:response><d:href>/Recordings/memo.m4a</d:href>
+				<d:propstat><d:prop><d:resourcetype/><d:getcontentlength>5</d:getcontentlength><d:getetag>"1"</d:getetag></d:prop>
+				<d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response></d:multistatus>`)
+		case http.MethodGet:
+			w.Write([]byte("audio"))
+		}
+	}))
+	defer server.Close()
+
+	downloads := t.TempDir()
+	cfg.Source = &SourceConfig{Type: SourceWebDAV, URL: server.URL + "/Recordings/", DownloadDir: downloads}
+
+	arch := &recordingArchiver{archived: make(chan string, 1)}
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{events: make(chan FileEvent)},
+		Stabilizer: fakeStabilizer{},
+		Client:     fakeClient{},
+		Writer:     &recordingWriter{},
+		Archiver:   arch,
+		Logger:     &recordingLogger{},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- svc.Run(ctx) }()
+
+	select {
+	case path := <-arch.archived:
+		if path != filepath.Join(downloads, "memo.m4a") {
+			t.Errorf("expected downloaded file to be archived, got %s", path)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for remote file to be processed")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected clean shutdown, got: %v", err)
+	}
+}
+
+func TestService_RemoteSourceDryRun(t *testing.T) {
+	cfg := setupBuilderTest(t)
+
+	var mu sync.Mutex
+	var changes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PROPFIND" {
+			w.WriteHeader(http.StatusMultiStatus)
+			fmt.Fprint(w, `<d:multistatus xmlns:d="DAV:"><d:response><d:href>/Recordings/memo.m4a</d:href>
+				<d:propstat><d:prop><d:resourcetype/><d:getcontentlength>5</d:getcontentlength><d:getetag>"1"</d:getetag></d:prop>
+				<d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response></d:multistatus>`)
+			return
+		}
+		mu.Lock()
+		changes = append(changes, r.Method)
+		mu.Unlock()
+		w.Write([]byte("audio"))
+	}))
+	defer server.Close()
+
+	downloads := filepath.Join(t.TempDir(), "downloads")
+	cfg.Source = &SourceConfig{Type: SourceWebDAV, URL: server.URL + "/Recordings/", DownloadDir: downloads, DeleteAfterDownload: true}
+
+	logger := &recordingLogger{}
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{events: make(chan FileEvent)},
+		Stabilizer: fakeStabilizer{},
+		Client:     fakeClient{},
+		Writer:     &recordingWriter{},
+		Archiver:   &countingArchiver{},
+		Logger:     logger,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	svc.SetDryRun(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- svc.Run(ctx) }()
+
+	logged := func() bool {
+		logger.mu.Lock()
+		defer logger.mu.Unlock()
+		return slices.Contains(logger.messages, "dry run: would download remote file")
+	}
+	for deadline := time.Now().Add(5 * time.Second); !logged(); {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for the remote file to be listed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected clean shutdown, got: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) != 0 {
+		t.Errorf("expected no downloads or deletes in a dry run, got %v", changes)
+	}
+	if _, err := os.Stat(downloads); !os.IsNotExist(err) {
+		t.Errorf("expected nothing downloaded, got: %v", err)
+	}
+}
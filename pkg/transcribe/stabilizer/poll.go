@@ -0,0 +1,180 @@
+package stabilizer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrStabilizationTimeout is returned when the file does not stabilize within the timeout.
+var ErrStabilizationTimeout = errors.New("stabilization timeout: file did not stabilize in time")
+
+// LockMode selects the advisory lock a file must accept before it is
+// considered stable.
+type LockMode string
+
+// Lock modes.
+const (
+	// LockNone does not check locks.
+	LockNone LockMode = ""
+	// LockShared requires that no writer holds an exclusive lock.
+	LockShared LockMode = "shared"
+	// LockExclusive requires that no other process holds any lock.
+	LockExclusive LockMode = "exclusive"
+)
+
+// Valid reports whether m is a known lock mode.
+func (m LockMode) Valid() bool {
+	switch m {
+	case LockNone, LockShared, LockExclusive:
+		return true
+	}
+	return false
+}
+
+// PollStabilizer implements Stabilizer using polling.
+type PollStabilizer struct {
+	// Interval is the duration between file size checks.
+	Interval time.Duration
+
+	// Checks is the number of consecutive stable checks required.
+	Checks int
+
+	// Timeout is the maximum duration to wait for stabilization.
+	// If zero, no timeout is applied (relies on context).
+	Timeout time.Duration
+
+	// OpenFile measures the size by opening the file and seeking to its end
+	// instead of calling stat on the path. On network mounts such as CIFS the
+	// size reported by stat can lag behind writes.
+	OpenFile bool
+
+	// Lock, if set, requires the file to accept a non-blocking advisory lock
+	// of this mode on each check. A file whose writer holds a lock is not
+	// counted as stable.
+	Lock LockMode
+}
+
+// NewPollStabilizer creates a new polling-based stabilizer.
+func NewPollStabilizer(interval time.Duration, checks int) *PollStabilizer {
+	return &PollStabilizer{
+		Interval: interval,
+		Checks:   checks,
+	}
+}
+
+// WaitForStable waits until the file size remains constant for the configured
+// number of consecutive checks.
+//
+// The method respects both the provided context and the Timeout field. If Timeout
+// is set and the context has no deadline, a timeout context is created internally.
+// Returns ErrStabilizationTimeout if the internal timeout expires before stability is achieved.
+func (s *PollStabilizer) WaitForStable(ctx context.Context, path string) error {
+	// Apply timeout if configured and context has no deadline
+	usingInternalTimeout := false
+	if s.Timeout > 0 {
+		_, hasDeadline := ctx.Deadline()
+		if !hasDeadline {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+			defer cancel()
+			usingInternalTimeout = true
+		}
+	}
+
+	var lastSize int64 = -1
+	stableCount := 0
+
+	for stableCount < s.Checks {
+		select {
+		case <-ctx.Done():
+			if usingInternalTimeout && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ErrStabilizationTimeout
+			}
+			return ctx.Err()
+		case <-time.After(s.Interval):
+		}
+
+		currentSize, locked, err := s.check(path)
+		if err != nil {
+			return err
+		}
+
+		if !locked {
+			// Another process still holds the file
+			stableCount = 0
+			lastSize = -1
+			continue
+		}
+
+		if currentSize == lastSize {
+			stableCount++
+		} else {
+			stableCount = 0
+			lastSize = currentSize
+		}
+	}
+
+	return nil
+}
+
+// check returns the current file size and whether the file accepted the
+// configured lock.
+func (s *PollStabilizer) check(path string) (int64, bool, error) {
+	if !s.OpenFile && s.Lock == LockNone {
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, false, err
+		}
+		return info.Size(), true, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false, err
+	}
+	defer f.Close()
+
+	if s.Lock != LockNone {
+		locked, err := tryLock(f, s.Lock)
+		if err != nil || !locked {
+			return 0, false, err
+		}
+	}
+
+	if !s.OpenFile {
+		info, err := f.Stat()
+		if err != nil {
+			return 0, false, err
+		}
+		return info.Size(), true, nil
+	}
+
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, false, err
+	}
+	return size, true, nil
+}
+
+// tryLock attempts a non-blocking advisory lock of the given mode on f and
+// releases it immediately. It reports false if another process holds a
+// conflicting lock.
+func tryLock(f *os.File, mode LockMode) (bool, error) {
+	how := unix.LOCK_SH
+	if mode == LockExclusive {
+		how = unix.LOCK_EX
+	}
+
+	if err := unix.Flock(int(f.Fd()), how|unix.LOCK_NB); err != nil {
+		if errors.Is(err, unix.EWOULDBLOCK) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, unix.Flock(int(f.Fd()), unix.LOCK_UN)
+}
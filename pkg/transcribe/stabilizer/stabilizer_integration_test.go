@@ -7,6 +7,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"golang.org/x/sys/unix"
 )
 
 func TestPollStabilizer_WaitsForStableFile(t *testing.T) {
@@ -286,3 +288,74 @@ func TestPollStabilizer_TimeoutNotAppliedWithContextDeadline(t *testing.T) {
 		t.Errorf("took too long, expected ~100ms but got: %v", elapsed)
 	}
 }
+
+func TestPollStabilizer_OpenFile(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "memo.m4a")
+	if err := os.WriteFile(testFile, []byte("complete audio"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	stabilizer := NewPollStabilizer(20*time.Millisecond, 2)
+	stabilizer.OpenFile = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := stabilizer.WaitForStable(ctx, testFile); err != nil {
+		t.Fatalf("WaitForStable failed: %v", err)
+	}
+}
+
+func TestPollStabilizer_WaitsForLockRelease(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "memo.m4a")
+	if err := os.WriteFile(testFile, []byte("audio"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	// Simulate a writer holding an exclusive lock
+	writer, err := os.OpenFile(testFile, os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	if err := unix.Flock(int(writer.Fd()), unix.LOCK_EX); err != nil {
+		t.Fatalf("failed to lock file: %v", err)
+	}
+
+	stabilizer := NewPollStabilizer(20*time.Millisecond, 2)
+	stabilizer.Lock = LockShared
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const holdFor = 200 * time.Millisecond
+	go func() {
+		time.Sleep(holdFor)
+		writer.Close()
+	}()
+
+	start := time.Now()
+	if err := stabilizer.WaitForStable(ctx, testFile); err != nil {
+		t.Fatalf("WaitForStable failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < holdFor {
+		t.Errorf("expected stabilizer to wait for the lock to be released, returned after %v", elapsed)
+	}
+}
+
+func TestLockMode_Valid(t *testing.T) {
+	tests := []struct {
+		mode  LockMode
+		valid bool
+	}{
+		{LockNone, true},
+		{LockShared, true},
+		{LockExclusive, true},
+		{"mandatory", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.mode.Valid(); got != tt.valid {
+			t.Errorf("Valid(%q): expected %v, got %v", tt.mode, tt.valid, got)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package stabilizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSyncthingEventTimeout is how long each wait for a Syncthing event
+// lasts before the file's sync state is checked again.
+const DefaultSyncthingEventTimeout = 30 * time.Second
+
+// ErrNotInSyncthing is passed to OnFallback for files Syncthing does not
+// manage, such as files outside its folders or ignored by them.
+var ErrNotInSyncthing = errors.New("file is not managed by syncthing")
+
+// SyncthingStabilizer implements Stabilizer using Syncthing's REST API. A
+// file in a Syncthing folder is stable once the local copy is the latest
+// version known to the cluster; between checks it waits for Syncthing's
+// ItemFinished events rather than polling the file. Files Syncthing does not
+// manage, and all files while the API is unreachable, are handed to Fallback.
+type SyncthingStabilizer struct {
+	// URL is the Syncthing GUI/API address, e.g. http://127.0.0.1:8384.
+	URL string
+	// APIKey is sent as X-API-Key.
+	APIKey string
+	// Fallback stabilizes files Syncthing cannot vouch for.
+	Fallback Stabilizer
+	// EventTimeout bounds each wait for an event between sync state checks.
+	EventTimeout time.Duration
+	// OnFallback, if set, is called with the reason whenever a file is
+	// handed to Fallback.
+	OnFallback func(path string, reason error)
+
+	client *http.Client
+}
+
+// NewSyncthingStabilizer creates a stabilizer for the Syncthing instance at
+// apiURL, using fallback for files it cannot vouch for.
+func NewSyncthingStabilizer(apiURL, apiKey string, fallback Stabilizer) *SyncthingStabilizer {
+	return &SyncthingStabilizer{
+		URL:          strings.TrimRight(apiURL, "/"),
+		APIKey:       apiKey,
+		Fallback:     fallback,
+		EventTimeout: DefaultSyncthingEventTimeout,
+		client:       &http.Client{},
+	}
+}
+
+// WaitForStable waits until Syncthing reports the file fully synced.
+func (s *SyncthingStabilizer) WaitForStable(ctx context.Context, path string) error {
+	folder, item, err := s.locate(ctx, path)
+	if err != nil {
+		return s.fallback(ctx, path, err)
+	}
+
+	since := 0
+	for {
+		synced, err := s.synced(ctx, folder, item)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return s.fallback(ctx, path, err)
+		}
+		if synced {
+			return nil
+		}
+
+		since, err = s.waitForEvent(ctx, since)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return s.fallback(ctx, path, err)
+		}
+	}
+}
+
+// fallback hands path to the fallback stabilizer.
+func (s *SyncthingStabilizer) fallback(ctx context.Context, path string, reason error) error {
+	if s.OnFallback != nil {
+		s.OnFallback(path, reason)
+	}
+	if s.Fallback == nil {
+		return nil
+	}
+	return s.Fallback.WaitForStable(ctx, path)
+}
+
+// syncthingFolder is an entry of /rest/config/folders.
+type syncthingFolder struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+}
+
+// locate returns the ID of the Syncthing folder holding path and the path
+// relative to it, in Syncthing's slash-separated form.
+func (s *SyncthingStabilizer) locate(ctx context.Context, path string) (string, string, error) {
+	var folders []syncthingFolder
+	if err := s.get(ctx, "/rest/config/folders", nil, &folders); err != nil {
+		return "", "", err
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Prefer the deepest folder in case folders are nested
+	bestID, bestRel, bestLen := "", "", -1
+	for _, f := range folders {
+		root := filepath.Clean(expandHome(f.Path))
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if len(root) > bestLen {
+			bestID, bestRel, bestLen = f.ID, filepath.ToSlash(rel), len(root)
+		}
+	}
+	if bestLen < 0 {
+		return "", "", fmt.Errorf("%w: %s is outside its folders", ErrNotInSyncthing, path)
+	}
+	return bestID, bestRel, nil
+}
+
+// syncthingFile is the part of /rest/db/file used to compare versions.
+type syncthingFile struct {
+	Global struct {
+		Version json.RawMessage `json:"version"`
+		Deleted bool            `json:"deleted"`
+	} `json:"global"`
+	Local struct {
+		Version json.RawMessage `json:"version"`
+		Invalid bool            `json:"invalid"`
+	} `json:"local"`
+}
+
+// synced reports whether the local copy of item is the global version.
+func (s *SyncthingStabilizer) synced(ctx context.Context, folder, item string) (bool, error) {
+	query := url.Values{"folder": {folder}, "file": {item}}
+	var file syncthingFile
+	if err := s.get(ctx, "/rest/db/file", query, &file); err != nil {
+		return false, err
+	}
+	if file.Global.Deleted || file.Local.Invalid {
+		return false, nil
+	}
+	return versionsEqual(file.Global.Version, file.Local.Version), nil
+}
+
+// versionsEqual compares two JSON version vectors.
+func versionsEqual(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if len(a) == 0 || len(b) == 0 || json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return false
+	}
+	if ca.String() == "null" || ca.String() == "[]" {
+		return false
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// syncthingEvent is an entry of /rest/events.
+type syncthingEvent struct {
+	ID int `json:"id"`
+}
+
+// waitForEvent long-polls for ItemFinished events after since and returns
+// the last event ID seen. It returns as soon as any item finishes syncing,
+// or after EventTimeout, and the caller re-checks the file's sync state.
+func (s *SyncthingStabilizer) waitForEvent(ctx context.Context, since int) (int, error) {
+	timeout := s.EventTimeout
+	if timeout <= 0 {
+		timeout = DefaultSyncthingEventTimeout
+	}
+	query := url.Values{
+		"events":  {"ItemFinished"},
+		"since":   {strconv.Itoa(since)},
+		"timeout": {strconv.Itoa(max(1, int(timeout.Seconds())))},
+	}
+
+	var events []syncthingEvent
+	if err := s.get(ctx, "/rest/events", query, &events); err != nil {
+		return since, err
+	}
+	for _, ev := range events {
+		since = max(since, ev.ID)
+	}
+	return since, nil
+}
+
+// get fetches an API endpoint and decodes its JSON response into v. A 404
+// means Syncthing does not know the file.
+func (s *SyncthingStabilizer) get(ctx context.Context, endpoint string, query url.Values, v any) error {
+	u := s.URL + endpoint
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-API-Key", s.APIKey)
+
+	client := s.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("syncthing API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: not in the index", ErrNotInSyncthing)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("syncthing API %s: status %d", endpoint, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("syncthing API %s: %w", endpoint, err)
+	}
+	return nil
+}
+
+// expandHome expands a leading ~ in a Syncthing folder path.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
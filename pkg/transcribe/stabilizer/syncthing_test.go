@@ -0,0 +1,185 @@
+package stabilizer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSyncthing serves the Syncthing endpoints the stabilizer uses. The
+// file is in sync once finished is set; an ItemFinished event is published
+// when it is.
+type fakeSyncthing struct {
+	folder   string
+	mu       sync.Mutex
+	finished bool
+	eventCh  chan struct{}
+}
+
+func (f *fakeSyncthing) finish() {
+	f.mu.Lock()
+	f.finished = true
+	f.mu.Unlock()
+	close(f.eventCh)
+}
+
+func (f *fakeSyncthing) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-API-Key") != "key" {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	switch r.URL.Path {
+	case "/rest/config/folders":
+		json.NewEncoder(w).Encode([]map[string]string{{"id": "phone", "path": f.folder}})
+	case "/rest/db/file":
+		if r.URL.Query().Get("folder") != "phone" || r.URL.Query().Get("file") != "sub/memo.m4a" {
+			http.Error(w, "no such object in the index", http.StatusNotFound)
+			return
+		}
+		f.mu.Lock()
+		local := `["A:1"]`
+		if f.finished {
+			local = `["A:1", "B:2"]`
+		}
+		f.mu.Unlock()
+		w.Write([]byte(`{"global": {"version": ["A:1","B:2"], "deleted": false}, "local": {"version": ` + local + `, "invalid": false}}`))
+	case "/rest/events":
+		select {
+		case <-f.eventCh:
+			w.Write([]byte(`[{"id": 7, "type": "ItemFinished", "data": {"folder": "phone", "item": "sub/memo.m4a", "error": null}}]`))
+		case <-time.After(100 * time.Millisecond):
+			w.Write([]byte(`[]`))
+		case <-r.Context().Done():
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// countingStabilizer records the files handed to it.
+type countingStabilizer struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (c *countingStabilizer) WaitForStable(ctx context.Context, path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paths = append(c.paths, path)
+	return nil
+}
+
+func newFakeSyncthing(t *testing.T) (*fakeSyncthing, *httptest.Server) {
+	t.Helper()
+	fake := &fakeSyncthing{folder: t.TempDir(), eventCh: make(chan struct{})}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func TestSyncthingStabilizer_WaitsForSync(t *testing.T) {
+	fake, server := newFakeSyncthing(t)
+	fallback := &countingStabilizer{}
+	s := NewSyncthingStabilizer(server.URL+"/", "key", fallback)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.WaitForStable(context.Background(), filepath.Join(fake.folder, "sub", "memo.m4a"))
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("expected to wait for sync, returned: %v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	fake.finish()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for stabilizer to return after sync finished")
+	}
+	if len(fallback.paths) != 0 {
+		t.Errorf("expected no fallback, got: %v", fallback.paths)
+	}
+}
+
+func TestSyncthingStabilizer_FallsBack(t *testing.T) {
+	fake, server := newFakeSyncthing(t)
+
+	tests := []struct {
+		name   string
+		url    string
+		key    string
+		path   string
+		reason error
+	}{
+		{"outside folders", server.URL, "key", filepath.Join(t.TempDir(), "memo.m4a"), ErrNotInSyncthing},
+		{"not in index", server.URL, "key", filepath.Join(fake.folder, "other.m4a"), ErrNotInSyncthing},
+		{"wrong API key", server.URL, "wrong", filepath.Join(fake.folder, "sub", "memo.m4a"), nil},
+		{"unreachable", "http://127.0.0.1:1", "key", filepath.Join(fake.folder, "sub", "memo.m4a"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fallback := &countingStabilizer{}
+			s := NewSyncthingStabilizer(tt.url, tt.key, fallback)
+			var reason error
+			s.OnFallback = func(path string, err error) { reason = err }
+
+			if err := s.WaitForStable(context.Background(), tt.path); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if len(fallback.paths) != 1 || fallback.paths[0] != tt.path {
+				t.Errorf("expected %s to be handed to the fallback, got: %v", tt.path, fallback.paths)
+			}
+			if reason == nil {
+				t.Error("expected OnFallback to be called with a reason")
+			}
+			if tt.reason != nil && !errors.Is(reason, tt.reason) {
+				t.Errorf("expected reason %v, got: %v", tt.reason, reason)
+			}
+		})
+	}
+}
+
+func TestSyncthingStabilizer_ContextCancelled(t *testing.T) {
+	fake, server := newFakeSyncthing(t)
+	s := NewSyncthingStabilizer(server.URL, "key", &countingStabilizer{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	err := s.WaitForStable(ctx, filepath.Join(fake.folder, "sub", "memo.m4a"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got: %v", err)
+	}
+}
+
+func TestVersionsEqual(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{`["A:1","B:2"]`, `["A:1", "B:2"]`, true},
+		{`["A:1"]`, `["A:1","B:2"]`, false},
+		{`[]`, `[]`, false},
+		{`null`, `null`, false},
+		{``, `["A:1"]`, false},
+	}
+
+	for _, tt := range tests {
+		if got := versionsEqual(json.RawMessage(tt.a), json.RawMessage(tt.b)); got != tt.expected {
+			t.Errorf("versionsEqual(%s, %s): expected %v, got %v", tt.a, tt.b, tt.expected, got)
+		}
+	}
+}
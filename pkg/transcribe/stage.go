@@ -0,0 +1,144 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// ErrStageFailed is returned when a pipeline stage run after transcription
+// fails, failing the file.
+var ErrStageFailed = errors.New("pipeline stage failed")
+
+// StagePoint is where in the pipeline a PipelineStage runs.
+type StagePoint string
+
+// Stage points.
+const (
+	// AfterTranscribe stages run on each transcript before its note is
+	// written, after redaction and the post_transcript hook, and may
+	// replace the text, e.g. to append a summary.
+	AfterTranscribe StagePoint = "after_transcribe"
+	// AfterWrite stages run once a note is written, e.g. to upload it
+	// somewhere. Their output is ignored, and failures are only logged.
+	AfterWrite StagePoint = "after_write"
+)
+
+// Valid reports whether p is a known stage point.
+func (p StagePoint) Valid() bool {
+	return p == AfterTranscribe || p == AfterWrite
+}
+
+// StageInput describes the file a stage runs for.
+type StageInput struct {
+	Point     StagePoint `json:"point"`
+	AudioPath string     `json:"audio_path"`
+	Text      string     `json:"text"`
+	Language  string     `json:"language,omitempty"`
+	// DurationSeconds is zero when unknown.
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+	// NotePath is the written note, for AfterWrite stages.
+	NotePath string `json:"note_path,omitempty"`
+}
+
+// StageOutput is what a stage returns. An empty Text keeps the transcript.
+type StageOutput struct {
+	Text string `json:"text,omitempty"`
+}
+
+// PipelineStage is a step added to the pipeline, such as a summarizer or an
+// uploader. Programs embedding the service register stages with
+// RegisterStage; executables in the vault's plugins directory become stages
+// when plugins is enabled.
+type PipelineStage interface {
+	// Name identifies the stage in logs.
+	Name() string
+	// Point returns where the stage runs.
+	Point() StagePoint
+	// Run processes one file.
+	Run(ctx context.Context, in StageInput) (StageOutput, error)
+}
+
+var (
+	stagesMu sync.Mutex
+	stages   []PipelineStage
+)
+
+// RegisterStage adds a stage to the services created afterwards, usually
+// from an init function. Stages run in the order they are registered,
+// before any plugins.
+func RegisterStage(stage PipelineStage) {
+	stagesMu.Lock()
+	defer stagesMu.Unlock()
+	stages = append(stages, stage)
+}
+
+// registeredStages returns a copy of the registered stages.
+func registeredStages() []PipelineStage {
+	stagesMu.Lock()
+	defer stagesMu.Unlock()
+	return append([]PipelineStage(nil), stages...)
+}
+
+// runTranscribedStages passes result through the AfterTranscribe stages.
+func (s *Service) runTranscribedStages(ctx context.Context, fileLogger Logger, path string, result *TranscriptionResult) error {
+	for _, stage := range s.stages {
+		if stage.Point() != AfterTranscribe {
+			continue
+		}
+		out, err := stage.Run(ctx, StageInput{
+			Point:           AfterTranscribe,
+			AudioPath:       path,
+			Text:            result.Text,
+			Language:        result.Language,
+			DurationSeconds: result.Duration,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrStageFailed, stage.Name(), err)
+		}
+		if out.Text != "" {
+			result.Text = out.Text
+		}
+		fileLogger.Debug("pipeline stage complete",
+			logging.String("path", path),
+			logging.String("stage", stage.Name()),
+		)
+	}
+	return nil
+}
+
+// runWrittenStages runs the AfterWrite stages for a note, logging failures.
+func (s *Service) runWrittenStages(ctx context.Context, fileLogger Logger, path, notePath string, result *TranscriptionResult) {
+	for _, stage := range s.stages {
+		if stage.Point() != AfterWrite {
+			continue
+		}
+		_, err := stage.Run(ctx, StageInput{
+			Point:           AfterWrite,
+			AudioPath:       path,
+			Text:            result.Text,
+			Language:        result.Language,
+			DurationSeconds: result.Duration,
+			NotePath:        notePath,
+		})
+		if err != nil {
+			fileLogger.Error("pipeline stage failed", err,
+				logging.String("path", path),
+				logging.String("stage", stage.Name()),
+			)
+		}
+	}
+}
+
+// stageNames lists the names of the service's stages, for logs.
+func (s *Service) stageNames() string {
+	names := make([]string, len(s.stages))
+	for i, stage := range s.stages {
+		names[i] = stage.Name()
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,143 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+// fakeStage records its inputs and appends to the transcript.
+type fakeStage struct {
+	point  StagePoint
+	err    error
+	inputs []StageInput
+}
+
+func (f *fakeStage) Name() string      { return "fake " + string(f.point) }
+func (f *fakeStage) Point() StagePoint { return f.point }
+
+func (f *fakeStage) Run(ctx context.Context, in StageInput) (StageOutput, error) {
+	f.inputs = append(f.inputs, in)
+	if f.err != nil {
+		return StageOutput{}, f.err
+	}
+	return StageOutput{Text: in.Text + "\n\nSummary: milk."}, nil
+}
+
+// registerTestStage registers stage for the rest of the test.
+func registerTestStage(t *testing.T, stage PipelineStage) {
+	t.Helper()
+	saved := registeredStages()
+	RegisterStage(stage)
+	t.Cleanup(func() {
+		stagesMu.Lock()
+		stages = saved
+		stagesMu.Unlock()
+	})
+}
+
+func TestProcessFile_RunsRegisteredStages(t *testing.T) {
+	transcribed := &fakeStage{point: AfterTranscribe}
+	written := &fakeStage{point: AfterWrite, err: errors.New("upload failed")}
+	registerTestStage(t, transcribed)
+	registerTestStage(t, written)
+
+	cfg := setupBuilderTest(t)
+	cfg.WatchDir = t.TempDir()
+	cfg.OutputDir = t.TempDir()
+	svc := newDedupService(t, cfg, &countingClient{})
+	audioPath := writeWAV(t, cfg.WatchDir, "memo.wav", time.Second)
+
+	// A failing after_write stage leaves the note in place
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath}, processOptions{}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if len(transcribed.inputs) != 1 || transcribed.inputs[0].Text != "Buy milk." || transcribed.inputs[0].AudioPath != audioPath {
+		t.Errorf("unexpected after_transcribe inputs %+v", transcribed.inputs)
+	}
+	notes, _ := filepath.Glob(filepath.Join(cfg.OutputDir, "*.md"))
+	if len(notes) != 1 {
+		t.Fatalf("expected a note, got %v", notes)
+	}
+	note, _ := os.ReadFile(notes[0])
+	if !strings.Contains(string(note), "Summary: milk.") {
+		t.Errorf("expected the stage's text in the note, got:\n%s", note)
+	}
+	if len(written.inputs) != 1 || written.inputs[0].NotePath != notes[0] || !strings.Contains(written.inputs[0].Text, "Summary") {
+		t.Errorf("unexpected after_write inputs %+v", written.inputs)
+	}
+}
+
+func TestTranscribe_FailingStageFailsFile(t *testing.T) {
+	registerTestStage(t, &fakeStage{point: AfterTranscribe, err: errors.New("model offline")})
+	svc := newDedupService(t, setupBuilderTest(t), &countingClient{})
+
+	_, err := svc.transcribe(context.Background(), svc.logger, "memo.wav")
+	if !errors.Is(err, ErrStageFailed) || !strings.Contains(err.Error(), "model offline") {
+		t.Errorf("expected ErrStageFailed, got: %v", err)
+	}
+	if got := classifyFailure(history.CategoryTranscription, err); got != history.CategoryHook {
+		t.Errorf("expected category %s, got %s", history.CategoryHook, got)
+	}
+}
+
+func TestLoadPlugins(t *testing.T) {
+	vaultRoot := setupTestVault(t)
+	dir := PluginDir(vaultRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	plugins := map[string]struct {
+		script string
+		mode   os.FileMode
+	}{
+		"summarize": {`case "$1" in
+describe) echo '{"name": "summarizer", "point": "after_transcribe"}' ;;
+run) echo '{"text": "shouted"}' ;;
+esac`, 0755},
+		"upload":     {`echo '{"point": "after_write"}'`, 0755},
+		"bad-point":  {`echo '{"point": "before_upload"}'`, 0755},
+		"shared":     {`echo '{"point": "after_write"}'`, 0777},
+		"notes.txt":  {"not a plugin", 0644},
+		".hidden.sh": {`echo '{"point": "after_write"}'`, 0755},
+	}
+	for name, p := range plugins {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"+p.script+"\n"), p.mode); err != nil {
+			t.Fatal(err)
+		}
+		os.Chmod(path, p.mode)
+	}
+
+	logger := &recordingLogger{}
+	loaded := loadPlugins(context.Background(), dir, logger)
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 plugins, got %d", len(loaded))
+	}
+	if loaded[0].Name() != "summarizer" || loaded[0].Point() != AfterTranscribe {
+		t.Errorf("unexpected first plugin %s at %s", loaded[0].Name(), loaded[0].Point())
+	}
+	if loaded[1].Name() != "upload" || loaded[1].Point() != AfterWrite {
+		t.Errorf("expected the upload plugin named after its file, got %s at %s", loaded[1].Name(), loaded[1].Point())
+	}
+
+	out, err := loaded[0].Run(context.Background(), StageInput{Point: AfterTranscribe, Text: "buy milk"})
+	if err != nil || out.Text != "shouted" {
+		t.Errorf("expected the plugin's text, got %+v, %v", out, err)
+	}
+	out, err = loaded[1].Run(context.Background(), StageInput{Point: AfterWrite, NotePath: "/vault/Inbox/memo.md"})
+	if err != nil || out.Text != "" {
+		t.Errorf("expected no text, got %+v, %v", out, err)
+	}
+	logged := strings.Join(logger.messages, "\n")
+	if !strings.Contains(logged, "skipping plugin writable by other users") || !strings.Contains(logged, "skipping plugin that failed to describe itself") {
+		t.Errorf("expected skipped plugins logged, got:\n%s", logged)
+	}
+}
@@ -0,0 +1,213 @@
+// Package status reads today's pipeline events, or for services that predate
+// the events file today's log, for transcription service status display.
+package status
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/events"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// Stats holds parsed statistics from the log file.
+type Stats struct {
+	FilesProcessed int
+	Errors         int
+	LastProcessed  *ProcessedFile
+	// DiskSpaceLow is the reason processing is paused for lack of disk
+	// space, or "" if it is not.
+	DiskSpaceLow string
+	// BudgetExceeded is the reason processing is paused for an exceeded
+	// monthly budget, or "" if it is not.
+	BudgetExceeded string
+}
+
+// ProcessedFile holds information about the last processed file.
+type ProcessedFile struct {
+	Timestamp time.Time
+	Path      string
+	Output    string
+}
+
+// logDir returns the default log directory path
+func logDir() (string, error) {
+	return logging.DefaultLogDir()
+}
+
+// TodayLogPath returns the path to today's transcribe log file.
+func TodayLogPath() (string, error) {
+	return TodayLogPathWith(clock.Real{})
+}
+
+// TodayLogPathWith returns the path to the transcribe log file for the UTC
+// day c reports, the file the logger writes to at that time.
+func TodayLogPathWith(c clock.Clock) (string, error) {
+	dir, err := logDir()
+	if err != nil {
+		return "", err
+	}
+	today := c.Now().UTC().Format("2006-01-02")
+	return filepath.Join(dir, "transcribe-"+today+".log"), nil
+}
+
+// ParseTodayStats parses today's log file and returns statistics.
+// Returns empty stats if the log file doesn't exist.
+func ParseTodayStats() (*Stats, error) {
+	return ParseTodayStatsWith(clock.Real{})
+}
+
+// ParseTodayStatsWith reads the events file for the day c reports, falling
+// back to parsing that day's log when there is no events file.
+func ParseTodayStatsWith(c clock.Clock) (*Stats, error) {
+	dir, err := logDir()
+	if err != nil {
+		return nil, err
+	}
+	eventsPath := events.PathFor(dir, c.Now())
+	if _, err := os.Stat(eventsPath); err == nil {
+		return ParseEventsFile(eventsPath)
+	}
+
+	logPath, err := TodayLogPathWith(c)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLogFile(logPath)
+}
+
+// ParseEventsFile reads an events file and returns statistics. Errors counts
+// failed files. Returns empty stats if the file doesn't exist.
+func ParseEventsFile(path string) (*Stats, error) {
+	evs, err := events.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{}
+	for _, ev := range evs {
+		switch ev.Type {
+		case events.TranscriptionComplete:
+			stats.FilesProcessed++
+			stats.LastProcessed = &ProcessedFile{
+				Timestamp: ev.Time,
+				Path:      ev.Path,
+				Output:    ev.Output,
+			}
+		case events.FileFailed:
+			stats.Errors++
+		case events.DiskSpaceLow:
+			stats.DiskSpaceLow = ev.Error
+		case events.DiskSpaceAvailable:
+			stats.DiskSpaceLow = ""
+		case events.BudgetExceeded:
+			stats.BudgetExceeded = ev.Error
+		case events.BudgetAvailable:
+			stats.BudgetExceeded = ""
+		case events.ServiceStarted:
+			stats.DiskSpaceLow, stats.BudgetExceeded = "", ""
+		}
+	}
+	return stats, nil
+}
+
+// ParseLogFile parses a log file and returns statistics.
+// Returns empty stats if the file doesn't exist.
+func ParseLogFile(path string) (*Stats, error) {
+	stats := &Stats{}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return stats, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	// Regex patterns for parsing log lines
+	// Format: 2026-01-22T14:30:00Z INFO  [pipeline] file processing complete path=/path/to/file output="/path/with spaces.md" elapsed=1.5s
+	completedPattern := regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z)\s+INFO\s+\[pipeline\]\s+file processing complete\s(.*)$`)
+	errorPattern := regexp.MustCompile(`\s+ERROR\s+`)
+	diskLowPattern := regexp.MustCompile(`\s+ERROR\s+\[pipeline\]\s+insufficient disk space, pausing processing error=(.*?)(?: trace=\S+)?$`)
+	diskResumedPattern := regexp.MustCompile(`\s+INFO\s+(\[pipeline\]\s+disk space available, resuming processing|\[service\]\s+starting transcription service)`)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Check for completed files
+		if matches := completedPattern.FindStringSubmatch(line); matches != nil {
+			stats.FilesProcessed++
+			timestamp, err := time.Parse(time.RFC3339, matches[1])
+			fields := parseFields(matches[2])
+			if err == nil {
+				stats.LastProcessed = &ProcessedFile{
+					Timestamp: timestamp,
+					Path:      fields["path"],
+					Output:    fields["output"],
+				}
+			}
+		}
+
+		// Check for errors
+		if errorPattern.MatchString(line) {
+			stats.Errors++
+		}
+
+		// Track whether processing is paused for disk space
+		if matches := diskLowPattern.FindStringSubmatch(line); matches != nil {
+			stats.DiskSpaceLow = matches[1]
+		} else if diskResumedPattern.MatchString(line) {
+			stats.DiskSpaceLow = ""
+		}
+	}
+
+	return stats, scanner.Err()
+}
+
+// parseFields parses the key=value fields the logger appends to a message.
+// Values that would not read back plainly are written Go-quoted, so a quoted
+// value is read up to its closing quote and unescaped. Parsing stops at
+// anything that is not a field, keeping the fields before it.
+func parseFields(s string) map[string]string {
+	fields := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " ")
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \t\"") {
+			return fields
+		}
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return fields
+			}
+			value, _ = strconv.Unquote(quoted)
+			rest = rest[len(quoted):]
+		} else {
+			value, rest, _ = strings.Cut(rest, " ")
+			rest = " " + rest
+		}
+		fields[key] = value
+		s = rest
+	}
+}
+
+// FormatTimestamp formats a timestamp for display.
+func FormatTimestamp(t time.Time) string {
+	return t.Local().Format("2006-01-02T15:04:05")
+}
+
+// BaseName returns just the filename from a path.
+func BaseName(path string) string {
+	return filepath.Base(strings.TrimSuffix(path, "/"))
+}
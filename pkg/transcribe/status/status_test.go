@@ -0,0 +1,303 @@
+package status
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/events"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+func TestParseLogFile_Empty(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "transcribe-test.log")
+
+	// Create empty file
+	os.WriteFile(logPath, []byte(""), 0644)
+
+	stats, err := ParseLogFile(logPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.FilesProcessed != 0 {
+		t.Errorf("expected 0 files processed, got %d", stats.FilesProcessed)
+	}
+	if stats.Errors != 0 {
+		t.Errorf("expected 0 errors, got %d", stats.Errors)
+	}
+	if stats.LastProcessed != nil {
+		t.Error("expected LastProcessed to be nil")
+	}
+}
+
+func TestParseLogFile_NonExistent(t *testing.T) {
+	stats, err := ParseLogFile("/nonexistent/path/transcribe.log")
+	if err != nil {
+		t.Fatalf("unexpected error for nonexistent file: %v", err)
+	}
+
+	if stats.FilesProcessed != 0 {
+		t.Errorf("expected 0 files processed, got %d", stats.FilesProcessed)
+	}
+}
+
+func TestParseLogFile_WithCompletedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "transcribe-test.log")
+
+	logContent := `2026-01-22T10:00:00Z INFO  [service] starting transcription service watch_dir=/mnt/sync/voice-notes
+2026-01-22T10:00:01Z INFO  [pipeline] processing file path=/mnt/sync/voice-notes/meeting.m4a size=1234567
+2026-01-22T10:00:05Z INFO  [pipeline] transcription complete path=/mnt/sync/voice-notes/meeting.m4a language=en
+2026-01-22T10:00:06Z INFO  [pipeline] file processing complete path=/mnt/sync/voice-notes/meeting.m4a output=/vault/Inbox/meeting.md elapsed=5s
+2026-01-22T11:00:00Z INFO  [pipeline] processing file path=/mnt/sync/voice-notes/notes.m4a size=2345678
+2026-01-22T11:00:10Z INFO  [pipeline] file processing complete path=/mnt/sync/voice-notes/notes.m4a output=/vault/Inbox/notes.md elapsed=10s
+`
+
+	os.WriteFile(logPath, []byte(logContent), 0644)
+
+	stats, err := ParseLogFile(logPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.FilesProcessed != 2 {
+		t.Errorf("expected 2 files processed, got %d", stats.FilesProcessed)
+	}
+
+	if stats.LastProcessed == nil {
+		t.Fatal("expected LastProcessed to be non-nil")
+	}
+
+	expectedTime, _ := time.Parse(time.RFC3339, "2026-01-22T11:00:10Z")
+	if !stats.LastProcessed.Timestamp.Equal(expectedTime) {
+		t.Errorf("expected timestamp %v, got %v", expectedTime, stats.LastProcessed.Timestamp)
+	}
+
+	if stats.LastProcessed.Path != "/mnt/sync/voice-notes/notes.m4a" {
+		t.Errorf("expected path /mnt/sync/voice-notes/notes.m4a, got %s", stats.LastProcessed.Path)
+	}
+
+	if stats.LastProcessed.Output != "/vault/Inbox/notes.md" {
+		t.Errorf("expected output /vault/Inbox/notes.md, got %s", stats.LastProcessed.Output)
+	}
+}
+
+func TestParseLogFile_WithErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "transcribe-test.log")
+
+	logContent := `2026-01-22T10:00:00Z INFO  [service] starting transcription service
+2026-01-22T10:00:01Z ERROR [pipeline] transcription failed error=connection refused path=/mnt/sync/voice-notes/meeting.m4a
+2026-01-22T10:01:00Z INFO  [pipeline] file processing complete path=/mnt/sync/voice-notes/notes.m4a output=/vault/Inbox/notes.md elapsed=5s
+2026-01-22T10:02:00Z ERROR [pipeline] failed to archive file error=permission denied path=/mnt/sync/voice-notes/audio.m4a
+`
+
+	os.WriteFile(logPath, []byte(logContent), 0644)
+
+	stats, err := ParseLogFile(logPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.FilesProcessed != 1 {
+		t.Errorf("expected 1 file processed, got %d", stats.FilesProcessed)
+	}
+
+	if stats.Errors != 2 {
+		t.Errorf("expected 2 errors, got %d", stats.Errors)
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected map[string]string
+	}{
+		{`path=/in/memo.m4a output=/out/memo.md`, map[string]string{"path": "/in/memo.m4a", "output": "/out/memo.md"}},
+		{`path="/in/quoted string.m4a" size=3`, map[string]string{"path": "/in/quoted string.m4a", "size": "3"}},
+		{`path="/in/say \"hi\".m4a"`, map[string]string{"path": `/in/say "hi".m4a`}},
+		{`path="/in/tab\there.m4a"`, map[string]string{"path": "/in/tab\there.m4a"}},
+		{`path=""`, map[string]string{"path": ""}},
+		{`path="partial`, map[string]string{}},
+		{`path=/in/a.m4a not a field`, map[string]string{"path": "/in/a.m4a"}},
+	}
+
+	for _, tc := range tests {
+		result := parseFields(tc.input)
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("parseFields(%q) = %q, expected %q", tc.input, result, tc.expected)
+		}
+	}
+}
+
+func TestParseLogFile_QuotedPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	clk := clock.NewFake(time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC))
+	logger, err := logging.New(logging.Config{LogDir: tmpDir, Prefix: "transcribe", Component: "pipeline", Clock: clk})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer logger.Close()
+
+	tests := []struct {
+		path   string
+		output string
+	}{
+		{"/mnt/sync/voice notes/team meeting.m4a", "/vault/Inbox/team meeting.md"},
+		{"/mnt/sync/voice-notes/réunion-café-会議.m4a", "/vault/Inbox/réunion café 会議.md"},
+		{"/mnt/sync/voice notes/say \"hi\".m4a", "/vault/Inbox/say \"hi\".md"},
+		{"/mnt/sync/voice-notes/say\"hi\".m4a", "/vault/Inbox/say\"hi\".md"},
+		{"/mnt/sync/voice-notes/a=b.m4a", "/vault/Inbox/key=value.md"},
+		{"/mnt/sync/voice-notes/\x1b[31mred\x00.m4a", "/vault/Inbox/line\nbreak.md"},
+		{"/mnt/sync/voice-notes/bad\xffutf8.m4a", ""},
+		{"/mnt/sync/voice-notes/draft.m4a", `"draft".md`},
+	}
+	for _, tc := range tests {
+		logger.Info("file processing complete",
+			logging.String("path", tc.path),
+			logging.String("output", tc.output),
+			logging.Duration("elapsed", 5*time.Second),
+		)
+
+		stats, err := ParseLogFile(filepath.Join(tmpDir, "transcribe-2026-01-22.log"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stats.LastProcessed == nil || stats.LastProcessed.Path != tc.path || stats.LastProcessed.Output != tc.output {
+			t.Errorf("expected %q written to %q, got %+v", tc.path, tc.output, stats.LastProcessed)
+		}
+	}
+}
+
+func TestBaseName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"/path/to/file.m4a", "file.m4a"},
+		{"/path/to/file", "file"},
+		{"file.m4a", "file.m4a"},
+		{"/path/to/dir/", "dir"},
+	}
+
+	for _, tc := range tests {
+		result := BaseName(tc.input)
+		if result != tc.expected {
+			t.Errorf("BaseName(%q) = %q, expected %q", tc.input, result, tc.expected)
+		}
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	ts, _ := time.Parse(time.RFC3339, "2026-01-22T14:30:00Z")
+	result := FormatTimestamp(ts)
+
+	// Just verify it doesn't panic and returns something reasonable
+	if result == "" {
+		t.Error("expected non-empty formatted timestamp")
+	}
+}
+
+func TestParseLogFile_DiskSpaceLow(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "transcribe-test.log")
+
+	logContent := `2026-01-22T10:00:00Z INFO  [service] starting transcription service api_url=http://nas:9000/asr
+2026-01-22T10:00:01Z ERROR [pipeline] insufficient disk space, pausing processing error=insufficient disk space: /vault/Inbox has 12 MB free, 100 MB required
+`
+	os.WriteFile(logPath, []byte(logContent), 0644)
+
+	stats, err := ParseLogFile(logPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "insufficient disk space: /vault/Inbox has 12 MB free, 100 MB required"
+	if stats.DiskSpaceLow != expected {
+		t.Errorf("expected DiskSpaceLow %q, got %q", expected, stats.DiskSpaceLow)
+	}
+
+	logContent += "2026-01-22T10:30:00Z INFO  [pipeline] disk space available, resuming processing\n"
+	os.WriteFile(logPath, []byte(logContent), 0644)
+
+	stats, err = ParseLogFile(logPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.DiskSpaceLow != "" {
+		t.Errorf("expected DiskSpaceLow to be cleared, got %q", stats.DiskSpaceLow)
+	}
+	// Lines logged while processing a file end with its trace ID
+	logContent += "2026-01-22T11:00:00Z ERROR [pipeline] insufficient disk space, pausing processing error=insufficient disk space: /vault/Inbox has 12 MB free, 100 MB required trace=ab12cd34\n"
+	os.WriteFile(logPath, []byte(logContent), 0644)
+
+	stats, err = ParseLogFile(logPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.DiskSpaceLow != expected {
+		t.Errorf("expected DiskSpaceLow %q without the trace ID, got %q", expected, stats.DiskSpaceLow)
+	}
+}
+
+func TestTodayLogPathWith(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	// 23:30 in UTC-5 is already the next day in UTC, where the logger writes
+	clk := clock.NewFake(time.Date(2026, 1, 22, 23, 30, 0, 0, time.FixedZone("EST", -5*3600)))
+	path, err := TodayLogPathWith(clk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filepath.Base(path) != "transcribe-2026-01-23.log" {
+		t.Errorf("expected the UTC day's log file, got %s", path)
+	}
+}
+
+func TestParseTodayStatsWith_PrefersEventsFile(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateDir)
+	clk := clock.NewFake(time.Date(2026, 1, 22, 14, 0, 0, 0, time.UTC))
+
+	logPath, err := TodayLogPathWith(clk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	os.MkdirAll(filepath.Dir(logPath), 0755)
+	os.WriteFile(logPath, []byte("2026-01-22T10:00:00Z ERROR [pipeline] something else went wrong\n"), 0644)
+
+	log := events.New(filepath.Dir(logPath), clk)
+	for _, ev := range []events.Event{
+		{Type: events.ServiceStarted},
+		{Type: events.FileDetected, Path: "/in/memo.m4a"},
+		{Type: events.TranscriptionComplete, Path: "/in/memo.m4a", Output: "/out/memo.md"},
+		{Type: events.FileFailed, Path: "/in/bad.m4a", Category: "transcription"},
+		{Type: events.DiskSpaceLow, Error: "insufficient disk space: /out has 10 MB free"},
+		{Type: events.BudgetExceeded, Error: "monthly transcription budget exceeded"},
+	} {
+		if err := log.Emit(ev); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	stats, err := ParseTodayStatsWith(clk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.FilesProcessed != 1 || stats.Errors != 1 {
+		t.Errorf("expected 1 processed and 1 failed file from the events, got %+v", stats)
+	}
+	if stats.LastProcessed == nil || stats.LastProcessed.Output != "/out/memo.md" || !stats.LastProcessed.Timestamp.Equal(clk.Now()) {
+		t.Errorf("unexpected last processed file: %+v", stats.LastProcessed)
+	}
+	if stats.DiskSpaceLow != "insufficient disk space: /out has 10 MB free" {
+		t.Errorf("expected processing paused for disk space, got %q", stats.DiskSpaceLow)
+	}
+	if stats.BudgetExceeded != "monthly transcription budget exceeded" {
+		t.Errorf("expected processing paused for the budget, got %q", stats.BudgetExceeded)
+	}
+}
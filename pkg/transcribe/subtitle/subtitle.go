@@ -0,0 +1,59 @@
+// Package subtitle renders timed transcript segments as SRT or WebVTT
+// subtitles, so recordings can be reviewed in a media player.
+package subtitle
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+// Format is a subtitle file format, named after its file extension.
+type Format string
+
+// Supported formats.
+const (
+	SRT Format = "srt"
+	VTT Format = "vtt"
+)
+
+// Valid reports whether f is a supported format.
+func (f Format) Valid() bool {
+	return f == SRT || f == VTT
+}
+
+// Render returns the segments as a subtitle file. Segments without text are
+// skipped.
+func Render(format Format, segments []client.Segment) string {
+	var sb strings.Builder
+	if format == VTT {
+		sb.WriteString("WEBVTT\n\n")
+	}
+
+	n := 0
+	for _, seg := range segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		n++
+		if format == SRT {
+			fmt.Fprintf(&sb, "%d\n", n)
+		}
+		fmt.Fprintf(&sb, "%s --> %s\n%s\n\n", timestamp(format, seg.Start), timestamp(format, seg.End), text)
+	}
+	return sb.String()
+}
+
+// timestamp formats seconds as HH:MM:SS,mmm for SRT or HH:MM:SS.mmm for
+// WebVTT.
+func timestamp(format Format, seconds float64) string {
+	ms := int64(math.Round(max(seconds, 0) * 1000))
+	sep := ","
+	if format == VTT {
+		sep = "."
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
@@ -0,0 +1,43 @@
+package subtitle
+
+import (
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+var segments = []client.Segment{
+	{Start: 0, End: 2.5, Text: " Remind me to call the dentist."},
+	{Start: 2.5, End: 2.5, Text: " "},
+	{Start: 3661.0015, End: 3663.2, Text: " And buy milk."},
+}
+
+func TestRender_SRT(t *testing.T) {
+	expected := `1
+00:00:00,000 --> 00:00:02,500
+Remind me to call the dentist.
+
+2
+01:01:01,002 --> 01:01:03,200
+And buy milk.
+
+`
+	if got := Render(SRT, segments); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestRender_VTT(t *testing.T) {
+	expected := `WEBVTT
+
+00:00:00.000 --> 00:00:02.500
+Remind me to call the dentist.
+
+01:01:01.002 --> 01:01:03.200
+And buy milk.
+
+`
+	if got := Render(VTT, segments); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
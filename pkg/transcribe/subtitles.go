@@ -0,0 +1,42 @@
+package transcribe
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/subtitle"
+)
+
+// subtitlePath returns the sidecar subtitle path for audioPath: the same
+// name with the format's extension.
+func subtitlePath(audioPath string, format subtitle.Format) string {
+	return strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + "." + string(format)
+}
+
+// writeSubtitles writes the transcript's timed segments as a subtitle file
+// next to audioPath when subtitles are configured. Failures are logged but
+// do not fail the file; the note has already been written.
+func (s *Service) writeSubtitles(fileLogger Logger, audioPath string, result *TranscriptionResult) {
+	format := s.config.Subtitles
+	if format == "" {
+		return
+	}
+	if len(result.Segments) == 0 {
+		fileLogger.Debug("transcription has no timed segments, skipping subtitles",
+			logging.String("path", audioPath),
+		)
+		return
+	}
+
+	path := subtitlePath(audioPath, format)
+	if err := s.perms.WriteFile(path, []byte(subtitle.Render(format, result.Segments))); err != nil {
+		fileLogger.Error("failed to write subtitles", err,
+			logging.String("path", path),
+		)
+		return
+	}
+	fileLogger.Info("subtitles written",
+		logging.String("path", path),
+	)
+}
@@ -0,0 +1,56 @@
+package transcribe
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/subtitle"
+)
+
+func TestService_WriteSubtitles(t *testing.T) {
+	dir := t.TempDir()
+	audioPath := filepath.Join(dir, "memo.m4a")
+	result := &TranscriptionResult{
+		Text:     "Buy milk.",
+		Segments: []Segment{{Start: 0, End: 1.5, Text: " Buy milk."}},
+	}
+
+	logger := &recordingLogger{}
+	svc := &Service{config: &Config{Subtitles: subtitle.VTT}}
+	svc.writeSubtitles(logger, audioPath, result)
+
+	data, err := os.ReadFile(filepath.Join(dir, "memo.vtt"))
+	if err != nil {
+		t.Fatalf("expected subtitles next to the audio, got: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "WEBVTT\n\n00:00:00.000 --> 00:00:01.500\nBuy milk.\n") {
+		t.Errorf("unexpected subtitles:\n%s", data)
+	}
+
+	// Without segments, or without subtitles configured, nothing is written
+	svc.config.Subtitles = subtitle.SRT
+	svc.writeSubtitles(logger, audioPath, &TranscriptionResult{Text: "Buy milk."})
+	svc.config.Subtitles = ""
+	svc.writeSubtitles(logger, audioPath, result)
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.srt")); len(matches) != 0 {
+		t.Errorf("expected no SRT file, found %v", matches)
+	}
+}
+
+func TestConfig_ValidateSubtitles(t *testing.T) {
+	for _, format := range []subtitle.Format{"", subtitle.SRT, subtitle.VTT, "ass"} {
+		cfg := &Config{WatchDir: "/tmp/watch", APIURL: "http://localhost:9000", OutputDir: "/tmp/out", Subtitles: format}
+		cfg.ApplyDefaults()
+		err := cfg.Validate()
+		if format == "ass" {
+			if !errors.Is(err, ErrInvalidSubtitles) {
+				t.Errorf("expected ErrInvalidSubtitles for %q, got: %v", format, err)
+			}
+		} else if err != nil {
+			t.Errorf("expected no error for %q, got: %v", format, err)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Package supervisor restarts the transcription service when it exits abnormally.
+// It provides crash recovery on systems without systemd or another init supervisor.
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// Default backoff settings
+const (
+	DefaultMinBackoff   = 1 * time.Second
+	DefaultMaxBackoff   = 1 * time.Minute
+	DefaultHealthyAfter = 30 * time.Second
+	DefaultStartupGrace = 5 * time.Second
+)
+
+// ErrStartupFailed is returned when the first run exits abnormally within the startup grace period.
+// Restarting would only loop on the same failure (e.g. invalid configuration).
+var ErrStartupFailed = errors.New("service failed during startup")
+
+// Supervisor runs a child process and restarts it with exponential backoff
+// whenever it exits abnormally.
+type Supervisor struct {
+	// Command builds a fresh command for each run.
+	Command func() *exec.Cmd
+	// Logger receives restart reasons. May be nil.
+	Logger logging.Logger
+	// MinBackoff is the delay before the first restart.
+	MinBackoff time.Duration
+	// MaxBackoff caps the exponential backoff.
+	MaxBackoff time.Duration
+	// HealthyAfter is how long a run must last before the backoff resets.
+	HealthyAfter time.Duration
+	// StartupGrace is how long the first run must last before a failure counts as a crash.
+	StartupGrace time.Duration
+}
+
+// New creates a supervisor with default backoff settings.
+func New(command func() *exec.Cmd, logger logging.Logger) *Supervisor {
+	return &Supervisor{
+		Command:      command,
+		Logger:       logger,
+		MinBackoff:   DefaultMinBackoff,
+		MaxBackoff:   DefaultMaxBackoff,
+		HealthyAfter: DefaultHealthyAfter,
+		StartupGrace: DefaultStartupGrace,
+	}
+}
+
+// Run starts the child and supervises it until it exits cleanly or ctx is cancelled.
+// On cancellation the child receives SIGTERM and Run waits for it to exit.
+// A clean exit is exit status 0 or termination by SIGTERM/SIGINT (e.g. nota transcribe stop).
+func (s *Supervisor) Run(ctx context.Context) error {
+	backoff := s.MinBackoff
+	restarts := 0
+
+	for {
+		startedAt := time.Now()
+		err := s.runOnce(ctx)
+		uptime := time.Since(startedAt)
+
+		if ctx.Err() != nil {
+			s.info("supervisor stopping")
+			return nil
+		}
+		if isCleanExit(err) {
+			s.info("service exited cleanly, supervisor stopping")
+			return nil
+		}
+
+		if restarts == 0 && uptime < s.StartupGrace {
+			return fmt.Errorf("%w: %v", ErrStartupFailed, err)
+		}
+
+		if uptime >= s.HealthyAfter {
+			backoff = s.MinBackoff
+		}
+
+		restarts++
+		if s.Logger != nil {
+			s.Logger.Error("service exited abnormally, restarting", err,
+				logging.String("reason", exitReason(err)),
+				logging.Duration("uptime", uptime.Round(time.Millisecond)),
+				logging.Duration("backoff", backoff),
+				logging.Int("restart", restarts),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.info("supervisor stopping")
+			return nil
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > s.MaxBackoff {
+			backoff = s.MaxBackoff
+		}
+	}
+}
+
+// runOnce starts the child and waits for it, forwarding cancellation as SIGTERM.
+func (s *Supervisor) runOnce(ctx context.Context) error {
+	cmd := s.Command()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start service: %w", err)
+	}
+	s.info("service started", logging.Int("pid", cmd.Process.Pid))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		cmd.Process.Signal(syscall.SIGTERM)
+		return <-done
+	}
+}
+
+func (s *Supervisor) info(msg string, fields ...logging.Field) {
+	if s.Logger != nil {
+		s.Logger.Info(msg, fields...)
+	}
+}
+
+// isCleanExit reports whether the child exited successfully or was asked to stop.
+func isCleanExit(err error) bool {
+	if err == nil {
+		return true
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			sig := ws.Signal()
+			return sig == syscall.SIGTERM || sig == syscall.SIGINT
+		}
+	}
+	return false
+}
+
+// exitReason describes why the child exited, for the restart log line.
+func exitReason(err error) string {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			return "signal " + ws.Signal().String()
+		}
+		return fmt.Sprintf("exit code %d", exitErr.ExitCode())
+	}
+	return "start failed"
+}
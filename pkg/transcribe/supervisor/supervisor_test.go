@@ -0,0 +1,104 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSupervisor(script string) *Supervisor {
+	return &Supervisor{
+		Command: func() *exec.Cmd {
+			return exec.Command("sh", "-c", script)
+		},
+		MinBackoff:   10 * time.Millisecond,
+		MaxBackoff:   40 * time.Millisecond,
+		HealthyAfter: 50 * time.Millisecond,
+		StartupGrace: 50 * time.Millisecond,
+	}
+}
+
+func TestSupervisor_CleanExitStops(t *testing.T) {
+	s := newTestSupervisor("exit 0")
+
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestSupervisor_StartupFailureNotRestarted(t *testing.T) {
+	s := newTestSupervisor("exit 3")
+
+	err := s.Run(context.Background())
+	if !errors.Is(err, ErrStartupFailed) {
+		t.Fatalf("expected ErrStartupFailed, got: %v", err)
+	}
+}
+
+func TestSupervisor_RestartsAfterCrash(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "runs")
+
+	// First run stays up past HealthyAfter then crashes; second run exits cleanly
+	script := `echo x >> ` + counter + `
+if [ "$(wc -l < ` + counter + `)" -lt 2 ]; then sleep 0.1; exit 1; fi
+exit 0`
+	s := newTestSupervisor(script)
+
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatalf("failed to read counter: %v", err)
+	}
+	if runs := strings.Count(string(data), "x"); runs != 2 {
+		t.Errorf("expected 2 runs, got %d", runs)
+	}
+}
+
+func TestSupervisor_CancelStopsChild(t *testing.T) {
+	s := newTestSupervisor("sleep 10")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor did not stop after cancellation")
+	}
+}
+
+func TestIsCleanExit(t *testing.T) {
+	if !isCleanExit(nil) {
+		t.Error("expected nil error to be a clean exit")
+	}
+
+	err := exec.Command("sh", "-c", "exit 1").Run()
+	if isCleanExit(err) {
+		t.Error("expected exit status 1 to be abnormal")
+	}
+	if reason := exitReason(err); reason != "exit code 1" {
+		t.Errorf("expected reason 'exit code 1', got %q", reason)
+	}
+
+	err = exec.Command("sh", "-c", "kill -TERM $$").Run()
+	if !isCleanExit(err) {
+		t.Errorf("expected SIGTERM to be a clean exit, got: %v", err)
+	}
+}
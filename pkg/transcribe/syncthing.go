@@ -0,0 +1,52 @@
+package transcribe
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/stabilizer"
+)
+
+// SyncthingConfig makes stabilization completion-aware: files in Syncthing
+// folders are processed once Syncthing reports them fully synced, instead of
+// after a run of unchanged sizes. Other files, and all files while the API is
+// unreachable, are stabilized by polling as usual.
+type SyncthingConfig struct {
+	// APIURL is Syncthing's GUI/API address, e.g. http://127.0.0.1:8384.
+	APIURL string `json:"api_url"`
+	// APIKey is the API key from Syncthing's settings.
+	APIKey string `json:"api_key"`
+}
+
+// validate checks that the API URL and key are set.
+func (c SyncthingConfig) validate() error {
+	u, err := url.Parse(c.APIURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: api_url %q must be a URL such as http://127.0.0.1:8384", ErrInvalidSyncthing, c.APIURL)
+	}
+	if c.APIKey == "" {
+		return fmt.Errorf("%w: api_key is required", ErrInvalidSyncthing)
+	}
+	return nil
+}
+
+// newSyncthingStabilizer wraps fallback in a Syncthing-aware stabilizer that
+// logs whenever it has to fall back to polling.
+func newSyncthingStabilizer(cfg SyncthingConfig, fallback stabilizer.Stabilizer, logger Logger) *stabilizer.SyncthingStabilizer {
+	st := stabilizer.NewSyncthingStabilizer(cfg.APIURL, cfg.APIKey, fallback)
+	st.OnFallback = func(path string, reason error) {
+		if errors.Is(reason, stabilizer.ErrNotInSyncthing) {
+			logger.Debug("file not managed by syncthing, polling for stability",
+				logging.String("path", path),
+				logging.String("reason", reason.Error()),
+			)
+			return
+		}
+		logger.Error("syncthing API unavailable, polling for stability", reason,
+			logging.String("path", path),
+		)
+	}
+	return st
+}
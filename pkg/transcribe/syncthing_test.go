@@ -0,0 +1,54 @@
+package transcribe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/stabilizer"
+)
+
+func TestSyncthingConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SyncthingConfig
+		wantErr bool
+	}{
+		{"valid", SyncthingConfig{APIURL: "http://127.0.0.1:8384", APIKey: "key"}, false},
+		{"https", SyncthingConfig{APIURL: "https://nas.lan:8384", APIKey: "key"}, false},
+		{"missing key", SyncthingConfig{APIURL: "http://127.0.0.1:8384"}, true},
+		{"missing URL", SyncthingConfig{APIKey: "key"}, true},
+		{"no scheme", SyncthingConfig{APIURL: "127.0.0.1:8384", APIKey: "key"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validate()
+			if tt.wantErr && !errors.Is(err, ErrInvalidSyncthing) {
+				t.Errorf("expected ErrInvalidSyncthing, got: %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewServiceWith_SyncthingStabilizer(t *testing.T) {
+	cfg := setupBuilderTest(t)
+	cfg.Syncthing = &SyncthingConfig{APIURL: "http://127.0.0.1:8384", APIKey: "key"}
+	cfg.StabilizationOpenFile = true
+
+	svc, err := NewServiceWith(cfg, Options{Watcher: &fakeWatcher{}, Logger: &recordingLogger{}})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	st, ok := svc.stabilizer.(*stabilizer.SyncthingStabilizer)
+	if !ok {
+		t.Fatalf("expected a SyncthingStabilizer, got %T", svc.stabilizer)
+	}
+	poll, ok := st.Fallback.(*stabilizer.PollStabilizer)
+	if !ok || !poll.OpenFile {
+		t.Errorf("expected the configured poll stabilizer as fallback, got %#v", st.Fallback)
+	}
+}
@@ -0,0 +1,68 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/tags"
+)
+
+// DefaultMaxTags is how many tags a note gets when max_tags is not set.
+const DefaultMaxTags = 5
+
+// TagStrategy selects how tags are extracted from a note's transcript.
+type TagStrategy string
+
+// Tag strategies.
+const (
+	// TagNone adds no tags beyond those in the template.
+	TagNone TagStrategy = "none"
+	// TagKeywords uses the transcript's most frequent words.
+	TagKeywords TagStrategy = "keywords"
+	// TagLLM asks the model configured in llm, falling back to keywords
+	// when the request fails.
+	TagLLM TagStrategy = "llm"
+)
+
+// Valid reports whether t is a known strategy. Empty means TagNone.
+func (t TagStrategy) Valid() bool {
+	switch t {
+	case "", TagNone, TagKeywords, TagLLM:
+		return true
+	}
+	return false
+}
+
+// validateTags checks tag_strategy and that the llm strategy has an
+// endpoint to use.
+func (c *Config) validateTags() error {
+	if !c.TagStrategy.Valid() {
+		return fmt.Errorf("%w: tag_strategy must be none, keywords or llm (got %q)", ErrInvalidTags, c.TagStrategy)
+	}
+	if c.TagStrategy == TagLLM && c.LLM == nil {
+		return fmt.Errorf("%w: tag_strategy llm requires an llm endpoint", ErrInvalidTags)
+	}
+	return nil
+}
+
+// noteTags returns the tags for a note with the given transcript, or nil
+// to add none.
+func (s *Service) noteTags(ctx context.Context, fileLogger Logger, text string) []string {
+	max := s.config.MaxTags
+	if max <= 0 {
+		max = DefaultMaxTags
+	}
+
+	switch s.config.TagStrategy {
+	case TagKeywords:
+		return tags.Keywords(text, max)
+	case TagLLM:
+		t, err := tags.Generate(ctx, s.config.LLM.client(), text, max)
+		if err != nil {
+			fileLogger.Error("tag extraction failed, using keywords", err)
+			return tags.Keywords(text, max)
+		}
+		return t
+	}
+	return nil
+}
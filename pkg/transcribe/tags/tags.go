@@ -0,0 +1,147 @@
+// Package tags extracts keyword tags from transcripts, either by word
+// frequency or by asking a language model.
+package tags
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/llm"
+)
+
+// minWordLen is the shortest word, in letters, Keywords considers.
+const minWordLen = 4
+
+// Prompt is the instruction sent to the model ahead of the transcript.
+const Prompt = "List up to %d short topic keywords for the following voice note transcript, " +
+	"most relevant first, in the transcript's language. Reply with the keywords only, separated by commas."
+
+// stopwords are common English words, including spoken filler, that make
+// poor tags. Words shorter than minWordLen are skipped anyway.
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		about above actually after again against also anyway anything back
+		basically because been before being below between both come could
+		cant couldnt didnt does doesnt doing done down during each even every everything
+		from further going gonna good have having here hers herself himself
+		into itself just kind know like made make many maybe mean more most
+		much myself need only other ours ourselves over probably really right
+		said same should some something sort still stuff such sure take than
+		that their theirs them themselves then there these they thing things
+		think this those through under until very wanna want well were what
+		when where which while will with would yeah your yours yourself
+		yourselves okay dont isnt thats theres theyre wasnt whats wont
+		wouldnt shouldnt youre`) {
+		stopwords[w] = true
+	}
+}
+
+// apostrophes are removed so "don't" and "dont" count as one word.
+var apostrophes = strings.NewReplacer("'", "", "’", "")
+
+// Keywords returns up to n words the transcript uses at least twice, most
+// frequent first, skipping short words and common English words. Ties keep
+// the order the words first appear in.
+func Keywords(text string, n int) []string {
+	type word struct {
+		tag   string
+		count int
+	}
+	counts := make(map[string]*word)
+	var order []*word
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’'
+	}) {
+		w = apostrophes.Replace(w)
+		if utf8.RuneCountInString(w) < minWordLen || stopwords[w] {
+			continue
+		}
+		tag := Normalize(w)
+		if tag == "" {
+			continue
+		}
+		if counts[tag] == nil {
+			counts[tag] = &word{tag: tag}
+			order = append(order, counts[tag])
+		}
+		counts[tag].count++
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return order[i].count > order[j].count })
+	var keywords []string
+	for _, w := range order {
+		if w.count < 2 || len(keywords) == n {
+			break
+		}
+		keywords = append(keywords, w.tag)
+	}
+	return keywords
+}
+
+// Generate asks the model behind client for up to n tags for the
+// transcript.
+func Generate(ctx context.Context, client *llm.Client, transcript string, n int) ([]string, error) {
+	reply, err := client.Complete(ctx, fmt.Sprintf(Prompt, n), transcript)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(reply, n), nil
+}
+
+// Parse splits a comma- or newline-separated list of keywords into up to n
+// normalized, distinct tags. List markers and hashes are ignored.
+func Parse(s string, n int) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' || r == ';' }) {
+		item = strings.TrimLeft(strings.TrimSpace(item), "-*•0123456789. ")
+		tag := Normalize(item)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+		if len(tags) == n {
+			break
+		}
+	}
+	return tags
+}
+
+// Normalize turns a keyword into an Obsidian-compatible tag: lowercase,
+// without a leading #, with spaces as hyphens and only letters, digits,
+// hyphens, underscores and slashes kept. Tags of digits only are not valid
+// and return "".
+func Normalize(s string) string {
+	s = strings.TrimLeft(strings.TrimSpace(strings.ToLower(s)), "#")
+	var sb strings.Builder
+	hasLetter := false
+	hyphen := false
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r) || r == '_' || r == '/':
+			hasLetter = true
+		case unicode.IsDigit(r):
+		case unicode.IsSpace(r) || r == '-':
+			hyphen = sb.Len() > 0
+			continue
+		default:
+			continue
+		}
+		if hyphen {
+			sb.WriteByte('-')
+			hyphen = false
+		}
+		sb.WriteRune(r)
+	}
+	if !hasLetter {
+		return ""
+	}
+	return sb.String()
+}
@@ -0,0 +1,75 @@
+package tags
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/llm"
+)
+
+func TestKeywords(t *testing.T) {
+	text := "The budget meeting is on Friday. I think the budget for the garden project " +
+		"needs another look, and the garden needs watering. Budget, budget, budget. " +
+		"Don't forget, don't forget the Friday meeting."
+
+	expected := []string{"budget", "meeting", "friday", "garden"}
+	if got := Keywords(text, 4); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if got := Keywords(text, 2); !reflect.DeepEqual(got, expected[:2]) {
+		t.Errorf("expected %v, got %v", expected[:2], got)
+	}
+	if got := Keywords("Buy milk.", 5); len(got) != 0 {
+		t.Errorf("expected no keywords from a short note, got %v", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		reply    string
+		expected []string
+	}{
+		{"budget, Garden Project, #friday", []string{"budget", "garden-project", "friday"}},
+		{"1. Budget\n2. Budget\n- 2024\n* Q3 planning", []string{"budget", "q3-planning"}},
+		{"a, b, c, d", []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.reply, 3); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Parse(%q): expected %v, got %v", tt.reply, tt.expected, got)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"#Work":           "work",
+		"Garden  Project": "garden-project",
+		"réunion":         "réunion",
+		"2024":            "",
+		"q3/planning!":    "q3/planning",
+	}
+	for in, expected := range tests {
+		if got := Normalize(in); got != expected {
+			t.Errorf("Normalize(%q): expected %q, got %q", in, expected, got)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices": [{"message": {"content": "Dentist, Health, appointments"}}]}`))
+	}))
+	defer server.Close()
+
+	got, err := Generate(context.Background(), &llm.Client{URL: server.URL}, "remind me to call the dentist", 5)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	expected := []string{"dentist", "health", "appointments"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,79 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestConfig_ValidateTagsAndLLM(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy TagStrategy
+		llm      *LLMConfig
+		wantErr  error
+	}{
+		{"unset", "", nil, nil},
+		{"keywords", TagKeywords, nil, nil},
+		{"llm", TagLLM, &LLMConfig{URL: "http://localhost:11434/v1/chat/completions"}, nil},
+		{"llm without endpoint", TagLLM, nil, ErrInvalidTags},
+		{"unknown", "topics", nil, ErrInvalidTags},
+		{"bad llm url", TagLLM, &LLMConfig{URL: "localhost:11434"}, ErrInvalidLLM},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				WatchDir:    "/tmp/watch",
+				APIURL:      "http://localhost:9000",
+				OutputDir:   "/tmp/out",
+				TagStrategy: tt.strategy,
+				LLM:         tt.llm,
+			}
+			cfg.ApplyDefaults()
+			err := cfg.Validate()
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestService_NoteTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"choices": [{"message": {"content": "Dentist, health"}}]}`))
+	}))
+	defer server.Close()
+
+	text := "Call the dentist about the dentist bill. The bill is due Friday."
+	tests := []struct {
+		name     string
+		strategy TagStrategy
+		url      string
+		expected []string
+	}{
+		{"none", TagNone, "", nil},
+		{"keywords", TagKeywords, "", []string{"dentist", "bill"}},
+		{"llm", TagLLM, server.URL, []string{"dentist", "health"}},
+		{"llm falls back", TagLLM, server.URL + "/down", []string{"dentist", "bill"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &Service{config: &Config{TagStrategy: tt.strategy, LLM: &LLMConfig{URL: tt.url}}}
+			if got := svc.noteTags(context.Background(), &recordingLogger{}, text); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/title"
+)
+
+// TitleStrategy selects how a note's title is derived from its transcript.
+type TitleStrategy string
+
+// Title strategies.
+const (
+	// TitleNone keeps the generic heading and names notes after their audio.
+	TitleNone TitleStrategy = "none"
+	// TitleFirstSentence uses the transcript's first sentence.
+	TitleFirstSentence TitleStrategy = "first_sentence"
+	// TitleLLM asks the model configured in llm, falling back to the first
+	// sentence when the request fails.
+	TitleLLM TitleStrategy = "llm"
+)
+
+// Valid reports whether t is a known strategy. Empty means TitleNone.
+func (t TitleStrategy) Valid() bool {
+	switch t {
+	case "", TitleNone, TitleFirstSentence, TitleLLM:
+		return true
+	}
+	return false
+}
+
+// validateTitle checks title_strategy and that the llm strategy has an
+// endpoint to use.
+func (c *Config) validateTitle() error {
+	if !c.TitleStrategy.Valid() {
+		return fmt.Errorf("%w: title_strategy must be none, first_sentence or llm (got %q)", ErrInvalidTitle, c.TitleStrategy)
+	}
+	if c.TitleStrategy == TitleLLM && c.LLM == nil {
+		return fmt.Errorf("%w: title_strategy llm requires an llm endpoint", ErrInvalidTitle)
+	}
+	return nil
+}
+
+// noteTitle returns the title for a note with the given transcript, or ""
+// to keep the writer's default heading and file name.
+func (s *Service) noteTitle(ctx context.Context, fileLogger Logger, text string) string {
+	switch s.config.TitleStrategy {
+	case TitleFirstSentence:
+		return title.FirstSentence(text)
+	case TitleLLM:
+		t, err := title.Generate(ctx, s.config.LLM.client(), text)
+		if err != nil {
+			fileLogger.Error("title generation failed, using first sentence", err)
+			return title.FirstSentence(text)
+		}
+		return t
+	}
+	return ""
+}
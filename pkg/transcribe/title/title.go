@@ -0,0 +1,67 @@
+// Package title derives a short note title from a transcript, either from
+// its first sentence or by asking a language model.
+package title
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/llm"
+)
+
+// MaxWords is the longest title, in words, FirstSentence returns.
+const MaxWords = 10
+
+// Prompt is the instruction sent to the model ahead of the transcript.
+const Prompt = "Write a short title, at most eight words, for the following voice note transcript. " +
+	"Reply with the title only, in the transcript's language, without quotes."
+
+// ErrNoTitle is returned when the model's reply holds no usable title.
+var ErrNoTitle = errors.New("no title in response")
+
+// FirstSentence returns the transcript's first sentence, cut to MaxWords
+// words, without trailing punctuation and with its first letter
+// capitalized. It returns "" for a transcript with no words.
+func FirstSentence(text string) string {
+	text = strings.TrimSpace(text)
+	if end := strings.IndexAny(text, ".!?\n"); end >= 0 {
+		text = text[:end]
+	}
+	words := strings.Fields(text)
+	if len(words) > MaxWords {
+		words = words[:MaxWords]
+	}
+	return Clean(strings.Join(words, " "))
+}
+
+// Clean tidies a candidate title: it keeps the first line, strips
+// surrounding quotes, markdown heading marks and trailing punctuation, and
+// capitalizes the first letter.
+func Clean(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	s = strings.TrimLeft(s, "# ")
+	s = strings.Trim(s, "\"'`“”‘’ \t")
+	s = strings.TrimRightFunc(s, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSpace(r)
+	})
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// Generate asks the model behind client for a title for the transcript.
+func Generate(ctx context.Context, client *llm.Client, transcript string) (string, error) {
+	reply, err := client.Complete(ctx, Prompt, transcript)
+	if err != nil {
+		return "", err
+	}
+	if title := Clean(reply); title != "" {
+		return title, nil
+	}
+	return "", ErrNoTitle
+}
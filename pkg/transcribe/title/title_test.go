@@ -0,0 +1,55 @@
+package title
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/llm"
+)
+
+func TestFirstSentence(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"first sentence", "remind me to call the dentist. Also buy milk.", "Remind me to call the dentist"},
+		{"question", "  What did we decide about the budget? I think we agreed.", "What did we decide about the budget"},
+		{"long sentence", "one two three four five six seven eight nine ten eleven twelve", "One two three four five six seven eight nine ten"},
+		{"trailing comma", "okay, so,\nthe plan is", "Okay, so"},
+		{"non-ascii", "éclair recipe notes", "Éclair recipe notes"},
+		{"empty", "  ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FirstSentence(tt.text); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/blank" {
+			w.Write([]byte(`{"choices": [{"message": {"content": "\"...\""}}]}`))
+			return
+		}
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "\"Dentist appointment reminder.\"\n"}}]}`))
+	}))
+	defer server.Close()
+
+	title, err := Generate(context.Background(), &llm.Client{URL: server.URL}, "remind me to call the dentist")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if title != "Dentist appointment reminder" {
+		t.Errorf("expected cleaned title, got %q", title)
+	}
+
+	if _, err := Generate(context.Background(), &llm.Client{URL: server.URL + "/blank"}, "text"); err != ErrNoTitle {
+		t.Errorf("expected ErrNoTitle, got: %v", err)
+	}
+}
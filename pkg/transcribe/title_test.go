@@ -0,0 +1,71 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConfig_ValidateTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy TitleStrategy
+		llm      *LLMConfig
+		wantErr  bool
+	}{
+		{"unset", "", nil, false},
+		{"first sentence", TitleFirstSentence, nil, false},
+		{"llm", TitleLLM, &LLMConfig{URL: "http://localhost:11434/v1/chat/completions"}, false},
+		{"llm without endpoint", TitleLLM, nil, true},
+		{"unknown", "summary", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{TitleStrategy: tt.strategy, LLM: tt.llm}
+			err := cfg.validateTitle()
+			if tt.wantErr && !errors.Is(err, ErrInvalidTitle) {
+				t.Errorf("expected ErrInvalidTitle, got: %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestService_NoteTitle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"choices": [{"message": {"content": "Dentist reminder"}}]}`))
+	}))
+	defer server.Close()
+
+	text := "Remind me to call the dentist. And buy milk."
+	tests := []struct {
+		name     string
+		strategy TitleStrategy
+		url      string
+		expected string
+	}{
+		{"none", TitleNone, "", ""},
+		{"first sentence", TitleFirstSentence, "", "Remind me to call the dentist"},
+		{"llm", TitleLLM, server.URL, "Dentist reminder"},
+		{"llm falls back", TitleLLM, server.URL + "/down", "Remind me to call the dentist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			svc := &Service{config: &Config{TitleStrategy: tt.strategy, LLM: &LLMConfig{URL: tt.url}}}
+			if got := svc.noteTitle(context.Background(), logger, text); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package transcribe
+
+import (
+	"context"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// tracedLogger adds a trace ID field to every line logged through it, after
+// the line's own fields so log parsers matching on leading fields still work.
+type tracedLogger struct {
+	Logger
+	trace Field
+}
+
+// withTrace returns a logger adding ctx's trace ID to every line, or logger
+// itself when ctx has none.
+func withTrace(ctx context.Context, logger Logger) Logger {
+	id := logging.TraceID(ctx)
+	if id == "" {
+		return logger
+	}
+	return tracedLogger{Logger: logger, trace: logging.String(logging.TraceKey, id)}
+}
+
+func (l tracedLogger) Info(msg string, fields ...Field) {
+	l.Logger.Info(msg, l.with(fields)...)
+}
+
+func (l tracedLogger) Error(msg string, err error, fields ...Field) {
+	l.Logger.Error(msg, err, l.with(fields)...)
+}
+
+func (l tracedLogger) Debug(msg string, fields ...Field) {
+	l.Logger.Debug(msg, l.with(fields)...)
+}
+
+// with returns fields followed by the trace ID, without modifying fields.
+func (l tracedLogger) with(fields []Field) []Field {
+	return append(fields[:len(fields):len(fields)], l.trace)
+}
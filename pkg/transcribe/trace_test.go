@@ -0,0 +1,49 @@
+package transcribe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestProcessFile_TracesEveryLine(t *testing.T) {
+	cfg := setupBuilderTest(t)
+
+	logger := &fieldLogger{}
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{},
+		Stabilizer: fakeStabilizer{},
+		Client:     fakeClient{},
+		Writer:     &recordingWriter{},
+		Archiver:   &countingArchiver{},
+		Logger:     logger,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer svc.Close()
+
+	for _, name := range []string{"first.m4a", "second.m4a"} {
+		audioPath := filepath.Join(cfg.WatchDir, name)
+		os.WriteFile(audioPath, []byte("audio"), 0644)
+		if err := svc.processFile(context.Background(), FileEvent{Path: audioPath, Size: 5}, processOptions{stabilize: true, archive: true}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+
+	trace := regexp.MustCompile(` trace=([0-9a-f]{8})$`)
+	ids := make(map[string]int)
+	for _, line := range logger.lines {
+		m := trace.FindStringSubmatch(line)
+		if m == nil {
+			t.Errorf("expected line to end with a trace ID, got %q", line)
+			continue
+		}
+		ids[m[1]]++
+	}
+	if len(ids) != 2 {
+		t.Errorf("expected one trace ID per file, got %v", ids)
+	}
+}
@@ -0,0 +1,57 @@
+package transcribe
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/tracing"
+)
+
+// TracingConfig exports a trace per file, with spans for the stabilize,
+// transcribe, write and archive stages, to an OTLP/HTTP endpoint such as an
+// OpenTelemetry collector or Grafana Tempo.
+type TracingConfig struct {
+	// Endpoint is the OTLP/HTTP traces URL, e.g.
+	// http://localhost:4318/v1/traces. An endpoint without a path has
+	// /v1/traces appended.
+	Endpoint string `json:"endpoint"`
+	// ServiceName is reported as service.name; the default is
+	// nota-transcribe.
+	ServiceName string `json:"service_name,omitempty"`
+	// Headers are sent with every export, e.g. for authentication.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// validate checks the endpoint URL.
+func (c TracingConfig) validate() error {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: endpoint %q must be a URL such as http://localhost:4318/v1/traces", ErrInvalidTracing, c.Endpoint)
+	}
+	return nil
+}
+
+// endpoint returns the traces URL, adding the standard path when the
+// endpoint has none.
+func (c TracingConfig) endpoint() string {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || strings.Trim(u.Path, "/") != "" {
+		return c.Endpoint
+	}
+	u.Path = tracing.TracesPath
+	return u.String()
+}
+
+// tracer returns a tracer exporting to the endpoint; onError is called when
+// an export fails.
+func (c TracingConfig) tracer(clk clock.Clock, onError func(error)) *tracing.Tracer {
+	return tracing.New(tracing.Config{
+		Endpoint:    c.endpoint(),
+		ServiceName: c.ServiceName,
+		Headers:     c.Headers,
+		Clock:       clk,
+		OnError:     onError,
+	})
+}
@@ -0,0 +1,359 @@
+// Package tracing records spans for the stages of the transcription pipeline
+// and exports them to an OpenTelemetry collector, Grafana Tempo or Jaeger
+// over OTLP/HTTP, using the protocol's JSON encoding.
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+)
+
+// DefaultServiceName is reported as service.name when none is configured.
+const DefaultServiceName = "nota-transcribe"
+
+// DefaultTimeout bounds an export request when HTTPClient is not set.
+const DefaultTimeout = 10 * time.Second
+
+// TracesPath is appended to an endpoint given without a path, as for
+// OTEL_EXPORTER_OTLP_ENDPOINT.
+const TracesPath = "/v1/traces"
+
+// scopeName identifies the instrumentation in exported spans.
+const scopeName = "github.com/TechnicallyShaun/nota-orbis/pkg/transcribe"
+
+// OTLP span kind and status codes.
+const (
+	kindInternal = 1
+	statusError  = 2
+)
+
+// Config configures a Tracer.
+type Config struct {
+	// Endpoint is the OTLP/HTTP traces URL, e.g.
+	// http://localhost:4318/v1/traces.
+	Endpoint string
+	// ServiceName is reported as the service.name resource attribute.
+	ServiceName string
+	// Headers are sent with every export, e.g. for authentication.
+	Headers map[string]string
+	// HTTPClient is used for exports; nil means a client with
+	// DefaultTimeout.
+	HTTPClient *http.Client
+	// Clock times spans. The default is the system clock.
+	Clock clock.Clock
+	// OnError, if set, is called when an export fails. Failed spans are
+	// dropped.
+	OnError func(err error)
+}
+
+// Tracer creates spans and exports each trace once its root span ends. A
+// nil Tracer records nothing, so callers need not check whether tracing is
+// enabled.
+type Tracer struct {
+	cfg Config
+
+	mu      sync.Mutex
+	pending []spanData
+	wg      sync.WaitGroup
+}
+
+// New returns a Tracer exporting to cfg.Endpoint.
+func New(cfg Config) *Tracer {
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = DefaultServiceName
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	cfg.Clock = clock.OrReal(cfg.Clock)
+	return &Tracer{cfg: cfg}
+}
+
+// Attribute is a key-value pair attached to a span.
+type Attribute struct {
+	Key   string `json:"key"`
+	Value Value  `json:"value"`
+}
+
+// Value is an attribute value; exactly one field is set.
+type Value struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: Value{StringValue: &value}}
+}
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: Value{BoolValue: &value}}
+}
+
+// Int64 returns an integer attribute. OTLP JSON encodes 64-bit integers as
+// strings.
+func Int64(key string, value int64) Attribute {
+	s := strconv.FormatInt(value, 10)
+	return Attribute{Key: key, Value: Value{IntValue: &s}}
+}
+
+// Float64 returns a floating-point attribute.
+func Float64(key string, value float64) Attribute {
+	return Attribute{Key: key, Value: Value{DoubleValue: &value}}
+}
+
+// Span is a timed pipeline stage. A nil Span ignores every call.
+type Span struct {
+	tracer *Tracer
+	data   spanData
+	start  time.Time
+	root   bool
+	ended  bool
+	mu     sync.Mutex
+}
+
+type spanKey struct{}
+
+// Start begins a span named name, a child of the span in ctx if there is
+// one, and returns a context carrying it.
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		tracer: t,
+		start:  t.cfg.Clock.Now(),
+		data: spanData{
+			SpanID:     newID(8),
+			Name:       name,
+			Kind:       kindInternal,
+			Attributes: attrs,
+		},
+	}
+	if parent := FromContext(ctx); parent != nil {
+		span.data.TraceID = parent.data.TraceID
+		span.data.ParentSpanID = parent.data.SpanID
+	} else {
+		span.data.TraceID = newID(16)
+		span.root = true
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// FromContext returns the span carried by ctx, or nil.
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// TraceID returns the span's trace ID in hex, as shown by trace viewers.
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return s.data.TraceID
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+}
+
+// RecordError marks the span as failed with err's message. A nil err is
+// ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Status = status{Code: statusError, Message: err.Error()}
+}
+
+// End finishes the span. Ending a root span exports its trace along with
+// any other finished spans. Only the first call has any effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	end := s.tracer.cfg.Clock.Now()
+	s.data.StartTimeUnixNano = strconv.FormatInt(s.start.UnixNano(), 10)
+	s.data.EndTimeUnixNano = strconv.FormatInt(end.UnixNano(), 10)
+	data := s.data
+	s.mu.Unlock()
+
+	s.tracer.finish(data, s.root)
+}
+
+// finish queues a finished span and, for a root span, exports the queue in
+// the background.
+func (t *Tracer) finish(data spanData, root bool) {
+	t.mu.Lock()
+	t.pending = append(t.pending, data)
+	if !root {
+		t.mu.Unlock()
+		return
+	}
+	batch := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		t.report(t.export(context.Background(), batch))
+	}()
+}
+
+// Shutdown exports any finished spans not yet sent and waits for exports in
+// progress, or until ctx is done.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	batch := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+
+	var err error
+	if len(batch) > 0 {
+		err = t.export(ctx, batch)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+	return err
+}
+
+// report passes err to OnError, if both are set.
+func (t *Tracer) report(err error) {
+	if err != nil && t.cfg.OnError != nil {
+		t.cfg.OnError(err)
+	}
+}
+
+// export sends spans to the endpoint as one OTLP request.
+func (t *Tracer) export(ctx context.Context, spans []spanData) error {
+	body, err := json.Marshal(exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{Attributes: []Attribute{
+				String("service.name", t.cfg.ServiceName),
+			}},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: scopeName},
+				Spans: spans,
+			}},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("encode spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("export spans: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("export spans: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("export spans: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// newID returns n random bytes in hex, the OTLP JSON form of trace and span
+// IDs.
+func newID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// OTLP/HTTP JSON request body, following
+// opentelemetry/proto/collector/trace/v1/trace_service.proto.
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []Attribute `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanData `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type spanData struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []Attribute `json:"attributes,omitempty"`
+	Status            status      `json:"status"`
+}
+
+type status struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,176 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+)
+
+// collector records the spans posted to it.
+type collector struct {
+	mu       sync.Mutex
+	requests []exportRequest
+	headers  []http.Header
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req exportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	c.requests = append(c.requests, req)
+	c.headers = append(c.headers, r.Header.Clone())
+	c.mu.Unlock()
+}
+
+func (c *collector) spans() []spanData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var spans []spanData
+	for _, req := range c.requests {
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}
+	return spans
+}
+
+func TestTracerExportsTraceWhenRootEnds(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	clk := clock.NewFake(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+	tracer := New(Config{
+		Endpoint:    srv.URL + TracesPath,
+		ServiceName: "test",
+		Headers:     map[string]string{"Authorization": "Bearer secret"},
+		Clock:       clk,
+	})
+
+	ctx, root := tracer.Start(context.Background(), "process_file", Int64("file.size", 2048))
+	_, child := tracer.Start(ctx, "transcribe", String("model", "base"))
+	clk.Advance(3 * time.Second)
+	child.SetAttributes(Float64("audio.duration_seconds", 61.5))
+	child.RecordError(errors.New("api unreachable"))
+	child.End()
+	root.End()
+
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	spans := c.spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	gotChild, gotRoot := spans[0], spans[1]
+	if gotRoot.Name != "process_file" || gotRoot.ParentSpanID != "" {
+		t.Errorf("unexpected root span: %+v", gotRoot)
+	}
+	if gotChild.TraceID != gotRoot.TraceID || len(gotChild.TraceID) != 32 {
+		t.Errorf("expected child in root's trace, got %q and %q", gotChild.TraceID, gotRoot.TraceID)
+	}
+	if gotChild.ParentSpanID != gotRoot.SpanID || len(gotChild.SpanID) != 16 {
+		t.Errorf("expected child of %q, got parent %q", gotRoot.SpanID, gotChild.ParentSpanID)
+	}
+	if gotChild.Status.Code != statusError || gotChild.Status.Message != "api unreachable" {
+		t.Errorf("expected error status, got %+v", gotChild.Status)
+	}
+	wantStart := "1772355600000000000"
+	wantEnd := "1772355603000000000"
+	if gotChild.StartTimeUnixNano != wantStart || gotChild.EndTimeUnixNano != wantEnd {
+		t.Errorf("expected %s-%s, got %s-%s", wantStart, wantEnd, gotChild.StartTimeUnixNano, gotChild.EndTimeUnixNano)
+	}
+	if len(gotChild.Attributes) != 2 || *gotChild.Attributes[1].Value.DoubleValue != 61.5 {
+		t.Errorf("unexpected attributes: %+v", gotChild.Attributes)
+	}
+	if got := *gotRoot.Attributes[0].Value.IntValue; got != "2048" {
+		t.Errorf("expected file.size 2048, got %s", got)
+	}
+
+	req := c.requests[0]
+	if got := *req.ResourceSpans[0].Resource.Attributes[0].Value.StringValue; got != "test" {
+		t.Errorf("expected service.name test, got %q", got)
+	}
+	if got := c.headers[0].Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("expected Authorization header, got %q", got)
+	}
+	if got := c.headers[0].Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected JSON content type, got %q", got)
+	}
+}
+
+func TestTracerShutdownFlushesUnfinishedTraces(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	tracer := New(Config{Endpoint: srv.URL})
+	ctx, _ := tracer.Start(context.Background(), "process_file")
+	_, child := tracer.Start(ctx, "stabilize")
+	child.End()
+	child.End()
+
+	if len(c.spans()) != 0 {
+		t.Fatal("expected no export before the root span ends")
+	}
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if spans := c.spans(); len(spans) != 1 || spans[0].Name != "stabilize" {
+		t.Errorf("expected the stabilize span once, got %+v", spans)
+	}
+}
+
+func TestTracerReportsExportErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad token", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var reported []error
+	tracer := New(Config{Endpoint: srv.URL, OnError: func(err error) {
+		mu.Lock()
+		reported = append(reported, err)
+		mu.Unlock()
+	}})
+	_, root := tracer.Start(context.Background(), "process_file")
+	root.End()
+	tracer.Shutdown(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 1 {
+		t.Fatalf("expected 1 reported error, got %d", len(reported))
+	}
+}
+
+func TestNilTracerIsNoop(t *testing.T) {
+	var tracer *Tracer
+	ctx, span := tracer.Start(context.Background(), "process_file")
+	if span != nil || FromContext(ctx) != nil {
+		t.Fatal("expected no span from a nil tracer")
+	}
+	span.SetAttributes(String("k", "v"))
+	span.RecordError(errors.New("boom"))
+	span.End()
+	if span.TraceID() != "" {
+		t.Error("expected no trace ID")
+	}
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package transcribe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// exportedSpan is the part of an OTLP JSON span the tests look at.
+type exportedSpan struct {
+	TraceID      string `json:"traceId"`
+	SpanID       string `json:"spanId"`
+	ParentSpanID string `json:"parentSpanId"`
+	Name         string `json:"name"`
+	Attributes   []struct {
+		Key   string `json:"key"`
+		Value struct {
+			StringValue string `json:"stringValue"`
+		} `json:"value"`
+	} `json:"attributes"`
+}
+
+// spanCollector is an OTLP/HTTP endpoint recording the spans posted to it.
+type spanCollector struct {
+	mu    sync.Mutex
+	spans []exportedSpan
+}
+
+func (c *spanCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []exportedSpan `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			c.spans = append(c.spans, ss.Spans...)
+		}
+	}
+}
+
+func TestProcessFile_ExportsStageSpans(t *testing.T) {
+	collector := &spanCollector{}
+	srv := httptest.NewServer(collector)
+	defer srv.Close()
+
+	cfg := setupBuilderTest(t)
+	cfg.Tracing = &TracingConfig{Endpoint: srv.URL}
+
+	logger := &fieldLogger{}
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{},
+		Stabilizer: fakeStabilizer{},
+		Client:     fakeClient{},
+		Writer:     &recordingWriter{},
+		Archiver:   &countingArchiver{},
+		Logger:     logger,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	audioPath := filepath.Join(cfg.WatchDir, "memo.m4a")
+	os.WriteFile(audioPath, []byte("audio"), 0644)
+	if err := svc.processFile(context.Background(), FileEvent{Path: audioPath, Size: 5}, processOptions{stabilize: true, archive: true}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := svc.Close(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	byName := make(map[string]exportedSpan)
+	for _, span := range collector.spans {
+		byName[span.Name] = span
+	}
+	root, ok := byName["process_file"]
+	if !ok {
+		t.Fatalf("expected a process_file span, got %+v", collector.spans)
+	}
+	for _, stage := range []string{"stabilize", "transcribe", "write", "archive"} {
+		span, ok := byName[stage]
+		if !ok {
+			t.Errorf("expected a %s span", stage)
+			continue
+		}
+		if span.TraceID != root.TraceID || span.ParentSpanID != root.SpanID {
+			t.Errorf("expected %s span under process_file, got %+v", stage, span)
+		}
+	}
+
+	trace := ""
+	for _, attr := range root.Attributes {
+		if attr.Key == "nota.trace" {
+			trace = attr.Value.StringValue
+		}
+	}
+	if trace == "" || len(logger.lines) == 0 {
+		t.Fatalf("expected the log trace ID on the root span, got %+v", root.Attributes)
+	}
+	if want := " trace=" + trace; logger.lines[0][len(logger.lines[0])-len(want):] != want {
+		t.Errorf("expected log lines tagged %q, got %q", want, logger.lines[0])
+	}
+}
+
+func TestTracingConfig(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		{endpoint: "http://tempo:4318", want: "http://tempo:4318/v1/traces"},
+		{endpoint: "http://tempo:4318/", want: "http://tempo:4318/v1/traces"},
+		{endpoint: "https://otel.example.com/otlp/v1/traces", want: "https://otel.example.com/otlp/v1/traces"},
+		{endpoint: "tempo:4318", wantErr: true},
+		{endpoint: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			cfg := TracingConfig{Endpoint: tt.endpoint}
+			err := cfg.validate()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTracing) {
+					t.Fatalf("expected ErrInvalidTracing, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if got := cfg.endpoint(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package transcribe
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Connection defaults, matching Go's default transport except for the idle
+// connections kept per host, which follow workers.
+const (
+	DefaultIdleTimeoutSeconds = 90
+	DefaultKeepAliveSeconds   = 30
+)
+
+// HTTPConfig tunes the connections to the transcription API. Keeping a
+// connection per worker open between files saves a TCP and TLS handshake
+// per upload, which dominates for bursts of short memos.
+type HTTPConfig struct {
+	// MaxIdleConns is how many idle connections to the API are kept open.
+	// Defaults to workers.
+	MaxIdleConns int `json:"max_idle_conns,omitempty"`
+	// IdleTimeoutSeconds is how long an idle connection is kept.
+	IdleTimeoutSeconds int `json:"idle_timeout_seconds,omitempty"`
+	// KeepAliveSeconds is the interval of TCP keep-alive probes on open
+	// connections.
+	KeepAliveSeconds int `json:"keep_alive_seconds,omitempty"`
+	// HTTP2 negotiates HTTP/2 with https endpoints, and speaks it
+	// unencrypted (h2c) to http ones, which must support it.
+	HTTP2 bool `json:"http2,omitempty"`
+}
+
+// httpTransport returns the transport for requests to apiURL.
+func (c *Config) httpTransport(apiURL string) *http.Transport {
+	var h HTTPConfig
+	if c.HTTP != nil {
+		h = *c.HTTP
+	}
+	if h.MaxIdleConns == 0 {
+		h.MaxIdleConns = max(c.Workers, 2)
+	}
+	if h.IdleTimeoutSeconds == 0 {
+		h.IdleTimeoutSeconds = DefaultIdleTimeoutSeconds
+	}
+	if h.KeepAliveSeconds == 0 {
+		h.KeepAliveSeconds = DefaultKeepAliveSeconds
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: time.Duration(h.KeepAliveSeconds) * time.Second}
+	t.DialContext = dialer.DialContext
+	t.MaxIdleConns = h.MaxIdleConns
+	t.MaxIdleConnsPerHost = h.MaxIdleConns
+	t.IdleConnTimeout = time.Duration(h.IdleTimeoutSeconds) * time.Second
+
+	t.Protocols = new(http.Protocols)
+	t.Protocols.SetHTTP1(true)
+	if h.HTTP2 {
+		t.Protocols.SetHTTP2(true)
+		if u, err := url.Parse(apiURL); err == nil && u.Scheme == "http" {
+			// Prior knowledge: without HTTP/1, http URLs use h2c
+			t.Protocols.SetHTTP1(false)
+			t.Protocols.SetUnencryptedHTTP2(true)
+		}
+	}
+	return t
+}
@@ -0,0 +1,100 @@
+package transcribe
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+func TestHTTPTransport_Defaults(t *testing.T) {
+	cfg := &Config{Workers: 4}
+	tr := cfg.httpTransport("http://nas:9000")
+	if tr.MaxIdleConnsPerHost != 4 {
+		t.Errorf("expected an idle connection per worker, got %d", tr.MaxIdleConnsPerHost)
+	}
+	if tr.IdleConnTimeout != DefaultIdleTimeoutSeconds*time.Second {
+		t.Errorf("expected idle timeout %ds, got %v", DefaultIdleTimeoutSeconds, tr.IdleConnTimeout)
+	}
+	if !tr.Protocols.HTTP1() || tr.Protocols.HTTP2() {
+		t.Errorf("expected HTTP/1 only, got %v", tr.Protocols)
+	}
+}
+
+func TestHTTPTransport_HTTP2(t *testing.T) {
+	cfg := &Config{HTTP: &HTTPConfig{MaxIdleConns: 8, IdleTimeoutSeconds: 10, HTTP2: true}}
+
+	tr := cfg.httpTransport("https://api.example.com")
+	if !tr.Protocols.HTTP1() || !tr.Protocols.HTTP2() {
+		t.Errorf("expected HTTP/2 negotiated with HTTP/1 fallback for https, got %v", tr.Protocols)
+	}
+	if tr.MaxIdleConnsPerHost != 8 || tr.IdleConnTimeout != 10*time.Second {
+		t.Errorf("expected configured idle connections, got %d and %v", tr.MaxIdleConnsPerHost, tr.IdleConnTimeout)
+	}
+
+	tr = cfg.httpTransport("http://nas:9000")
+	if tr.Protocols.HTTP1() || !tr.Protocols.UnencryptedHTTP2() {
+		t.Errorf("expected h2c for http, got %v", tr.Protocols)
+	}
+}
+
+// asrServer answers every request with a transcript and reports the HTTP
+// version it was made with.
+func asrServer(t *testing.T, h2c bool) (*httptest.Server, chan int) {
+	t.Helper()
+	protos := make(chan int, 10)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		protos <- r.ProtoMajor
+		w.Write([]byte(`{"text": "hello", "language": "en"}`))
+	}))
+	if h2c {
+		server.Config.Protocols = new(http.Protocols)
+		server.Config.Protocols.SetHTTP1(true)
+		server.Config.Protocols.SetUnencryptedHTTP2(true)
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	return server, protos
+}
+
+func TestNewClient_ReusesConnections(t *testing.T) {
+	server, _ := asrServer(t, false)
+	audio := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(audio, []byte("audio"), 0644)
+
+	var reused []bool
+	cfg := &Config{Workers: 2}
+	c, _ := cfg.newClient(server.URL, uploadHooks{
+		report: func(u client.UploadStats) { reused = append(reused, u.Reused) },
+	})
+	for range 3 {
+		if _, err := c.Transcribe(context.Background(), audio, TranscribeOptions{}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+	if len(reused) != 3 || reused[0] || !reused[1] || !reused[2] {
+		t.Errorf("expected a new connection, then reuse, got %v", reused)
+	}
+}
+
+func TestNewClient_H2C(t *testing.T) {
+	server, protos := asrServer(t, true)
+	audio := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(audio, []byte("audio"), 0644)
+
+	cfg := &Config{HTTP: &HTTPConfig{HTTP2: true}}
+	c, _ := cfg.newClient(server.URL, uploadHooks{})
+	if _, err := c.Transcribe(context.Background(), audio, TranscribeOptions{}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if proto := <-protos; proto != 2 {
+		t.Errorf("expected HTTP/2, got HTTP/%d", proto)
+	}
+}
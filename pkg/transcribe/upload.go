@@ -0,0 +1,100 @@
+package transcribe
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/cloudasr"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/grpcasr"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+)
+
+// uploadHooks are what every client the service makes shares for uploads:
+// one limiter for upload_limit_kb_per_second, so concurrent workers stay
+// under it together, and a reporter logging each upload.
+type uploadHooks struct {
+	limiter *client.Limiter
+	report  func(client.UploadStats)
+}
+
+// newUploadHooks returns the upload hooks for cfg. The reporter logs a
+// debug line with each upload's throughput and whether it reused a
+// connection, with a running count.
+func newUploadHooks(cfg *Config, logger Logger) uploadHooks {
+	if fl, ok := logger.(*logging.FileLogger); ok {
+		logger = fl.WithComponent("upload")
+	}
+	limit := cfg.UploadLimitKBPerSecond
+	var uploads, reused atomic.Int64
+	hooks := uploadHooks{report: func(u client.UploadStats) {
+		connection := "new"
+		if u.Reused {
+			connection = "reused"
+			reused.Add(1)
+		}
+		fields := []Field{
+			logging.String("path", u.Path),
+			logging.Int64("bytes", u.Bytes),
+			logging.Duration("elapsed", u.Elapsed),
+			logging.String("throughput", formatThroughput(u.Throughput())),
+			logging.String("connection", connection),
+			logging.String("connections_reused", fmt.Sprintf("%d of %d", reused.Load(), uploads.Add(1))),
+		}
+		if limit > 0 {
+			fields = append(fields, logging.Int("limit_kb_per_second", limit))
+		}
+		logger.Debug("upload complete", fields...)
+	}}
+	if limit > 0 {
+		hooks.limiter = client.NewLimiter(int64(limit) * 1024)
+	}
+	return hooks
+}
+
+// whisperOptions returns the hooks as whisper-asr-webservice client options.
+func (h uploadHooks) whisperOptions() []client.WhisperASROption {
+	var opts []client.WhisperASROption
+	if h.report != nil {
+		opts = append(opts, client.WithUploadReporter(h.report))
+	}
+	if h.limiter != nil {
+		opts = append(opts, client.WithUploadLimit(h.limiter))
+	}
+	return opts
+}
+
+// cloudOptions returns the hooks as hosted service client options.
+func (h uploadHooks) cloudOptions() []cloudasr.Option {
+	var opts []cloudasr.Option
+	if h.report != nil {
+		opts = append(opts, cloudasr.WithUploadReporter(h.report))
+	}
+	if h.limiter != nil {
+		opts = append(opts, cloudasr.WithUploadLimit(h.limiter))
+	}
+	return opts
+}
+
+// grpcOptions returns the hooks as gRPC client options.
+func (h uploadHooks) grpcOptions() []grpcasr.Option {
+	var opts []grpcasr.Option
+	if h.report != nil {
+		opts = append(opts, grpcasr.WithUploadReporter(h.report))
+	}
+	if h.limiter != nil {
+		opts = append(opts, grpcasr.WithUploadLimit(h.limiter))
+	}
+	return opts
+}
+
+// formatThroughput formats a rate in bytes per second, e.g. "1.4 MB/s".
+func formatThroughput(bytesPerSecond float64) string {
+	switch {
+	case bytesPerSecond >= 1024*1024:
+		return fmt.Sprintf("%.1f MB/s", bytesPerSecond/(1024*1024))
+	case bytesPerSecond >= 1024:
+		return fmt.Sprintf("%.1f KB/s", bytesPerSecond/1024)
+	}
+	return fmt.Sprintf("%.0f B/s", bytesPerSecond)
+}
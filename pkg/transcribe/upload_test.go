@@ -0,0 +1,49 @@
+package transcribe
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/client"
+)
+
+func TestFormatThroughput(t *testing.T) {
+	tests := []struct {
+		rate float64
+		want string
+	}{
+		{512, "512 B/s"},
+		{200 * 1024, "200.0 KB/s"},
+		{1.5 * 1024 * 1024, "1.5 MB/s"},
+	}
+	for _, tt := range tests {
+		if got := formatThroughput(tt.rate); got != tt.want {
+			t.Errorf("formatThroughput(%v) = %q, want %q", tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestUploadHooks_LogsThroughput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"text": "hello"}`))
+	}))
+	defer server.Close()
+	audio := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(audio, make([]byte, 4096), 0644)
+
+	logger := &recordingLogger{}
+	c := client.NewWhisperASRClient(server.URL, newUploadHooks(&Config{UploadLimitKBPerSecond: 1024}, logger).whisperOptions()...)
+	if _, err := c.Transcribe(context.Background(), audio, TranscribeOptions{}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(strings.Join(logger.messages, "\n"), "upload complete") {
+		t.Errorf("expected the upload logged, got %q", logger.messages)
+	}
+}
@@ -0,0 +1,196 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+// ErrBudgetExceeded is recorded for files that were not transcribed because
+// this month's estimated cost reached monthly_budget.
+var ErrBudgetExceeded = errors.New("monthly transcription budget exceeded")
+
+// budgetCheckInterval is the longest a paused budget guard waits before
+// checking again; it resumes at the start of the next month.
+const budgetCheckInterval = time.Hour
+
+// UsageConfig prices transcription by a paid API so the history tracks
+// what it costs, and optionally caps the monthly spend.
+type UsageConfig struct {
+	// Provider names the API in the history and stats. Defaults to the
+	// hosted provider or the host of api_url.
+	Provider string `json:"provider,omitempty"`
+	// CostPerMinute is the price of transcribing one minute of audio.
+	CostPerMinute float64 `json:"cost_per_minute"`
+	// Currency labels costs in stats, e.g. USD.
+	Currency string `json:"currency,omitempty"`
+	// MonthlyBudget, when set, pauses processing once the estimated cost of
+	// the files transcribed this calendar month reaches it.
+	MonthlyBudget float64 `json:"monthly_budget,omitempty"`
+}
+
+// validate checks the rate and budget are not negative.
+func (c UsageConfig) validate() error {
+	if c.CostPerMinute < 0 {
+		return fmt.Errorf("%w: cost_per_minute: %w", ErrInvalidUsage, ErrNegativeValue)
+	}
+	if c.MonthlyBudget < 0 {
+		return fmt.Errorf("%w: monthly_budget: %w", ErrInvalidUsage, ErrNegativeValue)
+	}
+	if c.MonthlyBudget > 0 && c.CostPerMinute == 0 {
+		return fmt.Errorf("%w: monthly_budget needs cost_per_minute", ErrInvalidUsage)
+	}
+	return nil
+}
+
+// ProviderName returns the name transcriptions are recorded under: usage's
+// provider when set, else the hosted service or the host of api_url.
+func (c *Config) ProviderName() string {
+	if c.Usage != nil && c.Usage.Provider != "" {
+		return c.Usage.Provider
+	}
+	if c.Provider.hosted() {
+		return string(c.Provider)
+	}
+	if u, err := url.Parse(c.APIURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return c.APIURL
+}
+
+// cost returns the estimated price of transcribing audioSeconds of audio,
+// or 0 without a configured rate.
+func (c *Config) cost(audioSeconds float64) float64 {
+	if c.Usage == nil {
+		return 0
+	}
+	return audioSeconds / 60 * c.Usage.CostPerMinute
+}
+
+// FormatCost formats an amount in the configured currency, e.g. "4.20 USD".
+func (c UsageConfig) FormatCost(amount float64) string {
+	if c.Currency == "" {
+		return fmt.Sprintf("%.2f", amount)
+	}
+	return fmt.Sprintf("%.2f %s", amount, c.Currency)
+}
+
+// budgetGuard pauses processing while this month's estimated cost is at or
+// over the monthly budget.
+type budgetGuard struct {
+	budget float64
+	usage  UsageConfig
+	store  *history.Store
+	now    func() time.Time
+	// provider, if set, limits the spend counted to the files of that
+	// provider, for a config with fallbacks.
+	provider string
+	// onChange, if set, is called when processing pauses, with the reason,
+	// and with nil when it resumes.
+	onChange func(err error)
+
+	mu sync.Mutex
+	// month is the start of the month spent covers; zero until loaded.
+	month  time.Time
+	spent  float64
+	paused bool
+}
+
+// newBudgetGuard returns a guard for the config's monthly budget, or nil
+// when there is none.
+func newBudgetGuard(cfg *Config, store *history.Store, now func() time.Time) *budgetGuard {
+	if cfg.Usage == nil || cfg.Usage.MonthlyBudget <= 0 {
+		return nil
+	}
+	return &budgetGuard{budget: cfg.Usage.MonthlyBudget, usage: *cfg.Usage, store: store, now: now}
+}
+
+// monthStart returns the local start of t's month.
+func monthStart(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+}
+
+// check returns an error wrapping ErrBudgetExceeded while the budget is
+// used up. The month's spend is read from the history when the month
+// changes, and kept up to date by add in between.
+func (g *budgetGuard) check() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	month := monthStart(g.now())
+	if !month.Equal(g.month) {
+		records, err := g.store.Load(month)
+		if err != nil {
+			// Unknown spend; let files through rather than stall
+			return nil
+		}
+		g.month, g.spent = month, 0
+		for _, rec := range records {
+			if g.counts(rec) {
+				g.spent += rec.Cost
+			}
+		}
+	}
+	if g.spent >= g.budget {
+		return fmt.Errorf("%w: %s spent of %s in %s", ErrBudgetExceeded,
+			g.usage.FormatCost(g.spent), g.usage.FormatCost(g.budget), month.Format("January 2006"))
+	}
+	return nil
+}
+
+// add counts a recorded file's cost towards the month it belongs to.
+func (g *budgetGuard) add(rec history.Record) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if rec.Cost > 0 && g.counts(rec) && monthStart(rec.Time).Equal(g.month) {
+		g.spent += rec.Cost
+	}
+}
+
+// counts reports whether rec's cost is spent from this budget.
+func (g *budgetGuard) counts(rec history.Record) bool {
+	return g.provider == "" || rec.Provider == g.provider
+}
+
+// wait blocks until the budget allows processing or ctx is cancelled. The
+// pause and the recovery are logged once each, however many files are
+// waiting.
+func (g *budgetGuard) wait(ctx context.Context, logger Logger) error {
+	for {
+		err := g.check()
+
+		g.mu.Lock()
+		changed := (err != nil) != g.paused
+		if err != nil && !g.paused {
+			logger.Error("monthly budget exceeded, pausing processing", err)
+		}
+		if err == nil && g.paused {
+			logger.Info("monthly budget available, resuming processing")
+		}
+		g.paused = err != nil
+		g.mu.Unlock()
+		if changed && g.onChange != nil {
+			g.onChange(err)
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		now := g.now()
+		delay := min(monthStart(now).AddDate(0, 1, 0).Sub(now), budgetCheckInterval)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
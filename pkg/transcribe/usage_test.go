@@ -0,0 +1,83 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/history"
+)
+
+func TestConfig_ProviderAndCost(t *testing.T) {
+	cfg := &Config{APIURL: "https://api.example.com/v1/audio"}
+	if cfg.ProviderName() != "api.example.com" || cfg.cost(600) != 0 {
+		t.Errorf("expected the API host and no cost without usage, got %q and %v", cfg.ProviderName(), cfg.cost(600))
+	}
+
+	cfg.Usage = &UsageConfig{Provider: "openai", CostPerMinute: 0.006}
+	if cfg.ProviderName() != "openai" || cfg.cost(600) != 0.06 {
+		t.Errorf("expected openai at 0.06 for ten minutes, got %q and %v", cfg.ProviderName(), cfg.cost(600))
+	}
+}
+
+func TestUsageConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		usage   UsageConfig
+		wantErr bool
+	}{
+		{"rate only", UsageConfig{CostPerMinute: 0.006}, false},
+		{"rate and budget", UsageConfig{CostPerMinute: 0.006, MonthlyBudget: 10}, false},
+		{"negative rate", UsageConfig{CostPerMinute: -1}, true},
+		{"negative budget", UsageConfig{CostPerMinute: 0.006, MonthlyBudget: -1}, true},
+		{"budget without rate", UsageConfig{MonthlyBudget: 10}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.usage.validate()
+			if tt.wantErr != errors.Is(err, ErrInvalidUsage) {
+				t.Errorf("expected error %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBudgetGuard(t *testing.T) {
+	store := history.New(filepath.Join(t.TempDir(), "transcribe.jsonl"))
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	// Last month's spend does not count
+	store.Append(history.Record{Time: now.AddDate(0, -1, 0), Status: history.StatusCompleted, Cost: 50})
+	store.Append(history.Record{Time: now.Add(-time.Hour), Status: history.StatusCompleted, Cost: 0.9})
+
+	cfg := &Config{Usage: &UsageConfig{CostPerMinute: 0.006, MonthlyBudget: 1, Currency: "USD"}}
+	g := newBudgetGuard(cfg, store, func() time.Time { return now })
+	if err := g.check(); err != nil {
+		t.Fatalf("expected budget left, got: %v", err)
+	}
+
+	g.add(history.Record{Time: now, Cost: 0.1})
+	err := g.check()
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected ErrBudgetExceeded, got: %v", err)
+	}
+	if want := "monthly transcription budget exceeded: 1.00 USD spent of 1.00 USD in October 2026"; err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+
+	// A new month starts from nothing
+	now = now.AddDate(0, 1, 0)
+	var changes []error
+	g.onChange = func(err error) { changes = append(changes, err) }
+	if err := g.wait(context.Background(), &recordingLogger{}); err != nil {
+		t.Errorf("expected the budget to reset next month, got: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no pause to be reported, got %v", changes)
+	}
+
+	if newBudgetGuard(&Config{Usage: &UsageConfig{CostPerMinute: 0.006}}, store, time.Now) != nil {
+		t.Error("expected no guard without a budget")
+	}
+}
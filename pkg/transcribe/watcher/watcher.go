@@ -0,0 +1,352 @@
+// Package watcher provides file watching capabilities for the transcription service.
+package watcher
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// FileEvent represents a detected file.
+type FileEvent struct {
+	Path      string
+	Size      int64
+	Timestamp time.Time
+}
+
+// FileWatcher detects new files in a directory.
+type FileWatcher interface {
+	Watch(ctx context.Context, dir string, patterns []string) (<-chan FileEvent, error)
+	Stop() error
+}
+
+// DefaultBufferSize is the default capacity of the events channel.
+const DefaultBufferSize = 100
+
+// Overflow reasons passed to the overflow handler.
+const (
+	// OverflowKernelQueue means the kernel dropped events; the directory is rescanned.
+	OverflowKernelQueue = "kernel event queue overflowed"
+	// OverflowBufferFull means the events channel is full and the reader is blocked
+	// until the consumer catches up. Events queue in the kernel meanwhile.
+	OverflowBufferFull = "event buffer full"
+)
+
+// InotifyWatcher implements FileWatcher using Linux inotify.
+// Watch may be called once per directory; all directories share one inotify
+// instance and each gets its own events channel.
+type InotifyWatcher struct {
+	fd         int
+	stopCh     chan struct{}
+	stopped    bool
+	bufferSize int
+	onOverflow func(reason string)
+
+	mu      sync.Mutex
+	watches map[int]*watch
+	reading bool
+	doneCh  chan struct{}
+}
+
+// watch is one watched directory.
+type watch struct {
+	dir      string
+	patterns []string
+	events   chan FileEvent
+}
+
+// Option configures an InotifyWatcher.
+type Option func(*InotifyWatcher)
+
+// WithBufferSize sets the capacity of the events channel.
+func WithBufferSize(n int) Option {
+	return func(w *InotifyWatcher) {
+		if n > 0 {
+			w.bufferSize = n
+		}
+	}
+}
+
+// WithOverflowHandler registers a function called when events may be delayed
+// or lost. It is called from the watcher goroutine and must not block.
+func WithOverflowHandler(fn func(reason string)) Option {
+	return func(w *InotifyWatcher) {
+		w.onOverflow = fn
+	}
+}
+
+// NewInotifyWatcher creates a new inotify-based file watcher.
+func NewInotifyWatcher(opts ...Option) (*InotifyWatcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &InotifyWatcher{
+		fd:         fd,
+		stopCh:     make(chan struct{}),
+		bufferSize: DefaultBufferSize,
+		watches:    make(map[int]*watch),
+		doneCh:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w, nil
+}
+
+// Watch starts watching the specified directory for files matching the patterns.
+// The events of every watched directory stop when the first context passed to
+// Watch is cancelled or Stop is called.
+func (w *InotifyWatcher) Watch(ctx context.Context, dir string, patterns []string) (<-chan FileEvent, error) {
+	// Add watch for the directory
+	wd, err := unix.InotifyAddWatch(w.fd, dir, unix.IN_CLOSE_WRITE|unix.IN_MOVED_TO)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if existing, ok := w.watches[wd]; ok {
+		// The same directory was watched twice; share its channel and
+		// match the patterns of both
+		existing.patterns = mergePatterns(existing.patterns, patterns)
+		return existing.events, nil
+	}
+
+	wt := &watch{
+		dir:      dir,
+		patterns: patterns,
+		events:   make(chan FileEvent, w.bufferSize),
+	}
+	w.watches[wd] = wt
+
+	if !w.reading {
+		w.reading = true
+		go w.readEvents(ctx)
+	}
+
+	return wt.events, nil
+}
+
+// Stop stops the watcher and releases resources.
+func (w *InotifyWatcher) Stop() error {
+	if w.stopped {
+		return nil
+	}
+	w.stopped = true
+	close(w.stopCh)
+
+	w.mu.Lock()
+	for wd := range w.watches {
+		unix.InotifyRmWatch(w.fd, uint32(wd))
+	}
+	reading := w.reading
+	w.mu.Unlock()
+
+	// Wait for the reader to finish before closing the descriptor it reads
+	if reading {
+		<-w.doneCh
+	}
+	return unix.Close(w.fd)
+}
+
+func (w *InotifyWatcher) readEvents(ctx context.Context) {
+	defer func() {
+		w.mu.Lock()
+		for _, wt := range w.watches {
+			close(wt.events)
+		}
+		w.mu.Unlock()
+		close(w.doneCh)
+	}()
+
+	buf := make([]byte, 4096)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		default:
+		}
+
+		n, err := unix.Read(w.fd, buf)
+		if err != nil {
+			if err == unix.EAGAIN || err == unix.EWOULDBLOCK {
+				// No events available, sleep briefly and retry
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			return
+		}
+
+		for _, event := range parseEvents(buf[:n]) {
+			if event.mask&unix.IN_Q_OVERFLOW != 0 {
+				w.overflow(OverflowKernelQueue)
+				for _, wt := range w.snapshot() {
+					if !w.rescan(ctx, wt) {
+						return
+					}
+				}
+				continue
+			}
+			if event.name == "" {
+				continue
+			}
+
+			w.mu.Lock()
+			wt := w.watches[event.wd]
+			w.mu.Unlock()
+
+			if wt != nil && wt.matches(event.name) {
+				if !w.emit(ctx, filepath.Join(wt.dir, event.name), wt.events) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// inotifyEvent is one event read from the inotify descriptor.
+type inotifyEvent struct {
+	wd   int
+	mask uint32
+	// name is the file the event is about, or "" for events on the watched
+	// directory itself.
+	name string
+}
+
+// parseEvents decodes the events in buf, as filled by a read from an
+// inotify descriptor. A truncated trailing event is dropped.
+func parseEvents(buf []byte) []inotifyEvent {
+	var events []inotifyEvent
+	for offset := 0; offset+unix.SizeofInotifyEvent <= len(buf); {
+		raw := buf[offset:]
+		nameLen := int(binary.NativeEndian.Uint32(raw[12:16]))
+		end := unix.SizeofInotifyEvent + nameLen
+		if end > len(raw) {
+			break
+		}
+		events = append(events, inotifyEvent{
+			wd:   int(int32(binary.NativeEndian.Uint32(raw[0:4]))),
+			mask: binary.NativeEndian.Uint32(raw[4:8]),
+			// The name is padded with NULs to an alignment boundary
+			name: strings.TrimRight(string(raw[unix.SizeofInotifyEvent:end]), "\x00"),
+		})
+		offset += end
+	}
+	return events
+}
+
+// snapshot returns the current watches.
+func (w *InotifyWatcher) snapshot() []*watch {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	watches := make([]*watch, 0, len(w.watches))
+	for _, wt := range w.watches {
+		watches = append(watches, wt)
+	}
+	return watches
+}
+
+// emit sends an event for path if it still exists. It blocks while the
+// channel is full, reporting the overflow once, and returns false if the
+// watcher is stopping.
+func (w *InotifyWatcher) emit(ctx context.Context, path string, events chan<- FileEvent) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return true
+	}
+	event := FileEvent{
+		Path:      path,
+		Size:      info.Size(),
+		Timestamp: time.Now(),
+	}
+
+	select {
+	case events <- event:
+		return true
+	default:
+	}
+
+	w.overflow(OverflowBufferFull)
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-w.stopCh:
+		return false
+	}
+}
+
+// rescan emits events for every matching file in the watched directory, used after the kernel
+// has dropped events. Consumers must tolerate events for files they have
+// already seen. Returns false if the watcher is stopping.
+func (w *InotifyWatcher) rescan(ctx context.Context, wt *watch) bool {
+	entries, err := os.ReadDir(wt.dir)
+	if err != nil {
+		return true
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !wt.matches(entry.Name()) {
+			continue
+		}
+		if !w.emit(ctx, filepath.Join(wt.dir, entry.Name()), wt.events) {
+			return false
+		}
+	}
+	return true
+}
+
+// overflow notifies the overflow handler, if any.
+func (w *InotifyWatcher) overflow(reason string) {
+	if w.onOverflow != nil {
+		w.onOverflow(reason)
+	}
+}
+
+// matches reports whether name matches the watch's patterns.
+func (wt *watch) matches(name string) bool {
+	return matchesPatterns(name, wt.patterns)
+}
+
+// mergePatterns returns the patterns matching what either a or b matches.
+func mergePatterns(a, b []string) []string {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	merged := slices.Clone(a)
+	for _, pattern := range b {
+		if !slices.Contains(merged, pattern) {
+			merged = append(merged, pattern)
+		}
+	}
+	return merged
+}
+
+// matchesPatterns reports whether name matches any of patterns. An empty
+// pattern list matches every file.
+func matchesPatterns(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		matched, err := filepath.Match(pattern, name)
+		if err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,327 @@
+//go:build linux
+
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInotifyWatcher_DetectsNewFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	watcher, err := NewInotifyWatcher()
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer watcher.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := watcher.Watch(ctx, tmpDir, []string{"*.txt"})
+	if err != nil {
+		t.Fatalf("failed to start watch: %v", err)
+	}
+
+	// Give the watcher time to set up
+	time.Sleep(50 * time.Millisecond)
+
+	// Create a new file
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("hello"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	// Wait for the event
+	select {
+	case event := <-events:
+		if event.Path != testFile {
+			t.Errorf("expected path %s, got %s", testFile, event.Path)
+		}
+		if event.Size != 5 {
+			t.Errorf("expected size 5, got %d", event.Size)
+		}
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for file event")
+	}
+}
+
+func TestInotifyWatcher_IgnoresNonMatchingPatterns(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	watcher, err := NewInotifyWatcher()
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer watcher.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	events, err := watcher.Watch(ctx, tmpDir, []string{"*.m4a"})
+	if err != nil {
+		t.Fatalf("failed to start watch: %v", err)
+	}
+
+	// Give the watcher time to set up
+	time.Sleep(50 * time.Millisecond)
+
+	// Create a file that doesn't match the pattern
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("hello"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	// Should not receive any event
+	select {
+	case event := <-events:
+		t.Errorf("unexpected event for non-matching file: %v", event)
+	case <-time.After(500 * time.Millisecond):
+		// Expected: no event for non-matching pattern
+	}
+}
+
+func TestInotifyWatcher_DetectsMovedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	srcDir := t.TempDir()
+
+	watcher, err := NewInotifyWatcher()
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer watcher.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := watcher.Watch(ctx, tmpDir, []string{"*.m4a"})
+	if err != nil {
+		t.Fatalf("failed to start watch: %v", err)
+	}
+
+	// Give the watcher time to set up
+	time.Sleep(50 * time.Millisecond)
+
+	// Create a file in source directory and move it to watched directory
+	srcFile := filepath.Join(srcDir, "audio.m4a")
+	if err := os.WriteFile(srcFile, []byte("fake audio content"), 0644); err != nil {
+		t.Fatalf("failed to create source file: %v", err)
+	}
+
+	dstFile := filepath.Join(tmpDir, "audio.m4a")
+	if err := os.Rename(srcFile, dstFile); err != nil {
+		t.Fatalf("failed to move file: %v", err)
+	}
+
+	// Wait for the event
+	select {
+	case event := <-events:
+		if event.Path != dstFile {
+			t.Errorf("expected path %s, got %s", dstFile, event.Path)
+		}
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for moved file event")
+	}
+}
+
+func TestInotifyWatcher_StopCleansUp(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	watcher, err := NewInotifyWatcher()
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+
+	ctx := context.Background()
+	events, err := watcher.Watch(ctx, tmpDir, nil)
+	if err != nil {
+		t.Fatalf("failed to start watch: %v", err)
+	}
+
+	// Stop the watcher
+	if err := watcher.Stop(); err != nil {
+		t.Errorf("stop failed: %v", err)
+	}
+
+	// Events channel should be closed
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("expected events channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Error("events channel not closed after stop")
+	}
+
+	// Double stop should not error
+	if err := watcher.Stop(); err != nil {
+		t.Errorf("double stop failed: %v", err)
+	}
+}
+
+func TestInotifyWatcher_ReportsFullBuffer(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	reasons := make(chan string, 10)
+	watcher, err := NewInotifyWatcher(
+		WithBufferSize(1),
+		WithOverflowHandler(func(reason string) { reasons <- reason }),
+	)
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer watcher.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := watcher.Watch(ctx, tmpDir, []string{"*.txt"})
+	if err != nil {
+		t.Fatalf("failed to start watch: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// Write files without consuming events
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		os.WriteFile(filepath.Join(tmpDir, name), []byte("hello"), 0644)
+	}
+
+	select {
+	case reason := <-reasons:
+		if reason != OverflowBufferFull {
+			t.Errorf("expected %q, got %q", OverflowBufferFull, reason)
+		}
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for overflow report")
+	}
+
+	// No events are lost once the consumer catches up
+	for i := 0; i < 3; i++ {
+		select {
+		case <-events:
+		case <-ctx.Done():
+			t.Fatalf("timeout waiting for event %d", i+1)
+		}
+	}
+}
+
+func TestInotifyWatcher_Rescan(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "a.m4a"), []byte("audio"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "b.txt"), []byte("text"), 0644)
+	os.Mkdir(filepath.Join(tmpDir, "sub.m4a"), 0755)
+
+	watcher, err := NewInotifyWatcher()
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer watcher.Stop()
+
+	events := make(chan FileEvent, 10)
+	wt := &watch{dir: tmpDir, patterns: []string{"*.m4a"}, events: events}
+	if !watcher.rescan(context.Background(), wt) {
+		t.Fatal("expected rescan to complete")
+	}
+	close(events)
+
+	var paths []string
+	for event := range events {
+		paths = append(paths, event.Path)
+	}
+	if len(paths) != 1 || paths[0] != filepath.Join(tmpDir, "a.m4a") {
+		t.Errorf("expected only a.m4a, got: %v", paths)
+	}
+}
+
+func TestInotifyWatcher_MultipleDirectories(t *testing.T) {
+	phoneDir := t.TempDir()
+	tabletDir := t.TempDir()
+
+	watcher, err := NewInotifyWatcher()
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer watcher.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	phoneEvents, err := watcher.Watch(ctx, phoneDir, []string{"*.m4a"})
+	if err != nil {
+		t.Fatalf("failed to watch phone dir: %v", err)
+	}
+	tabletEvents, err := watcher.Watch(ctx, tabletDir, []string{"*.wav"})
+	if err != nil {
+		t.Fatalf("failed to watch tablet dir: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// Each directory applies its own patterns
+	os.WriteFile(filepath.Join(phoneDir, "ignored.wav"), []byte("audio"), 0644)
+	os.WriteFile(filepath.Join(phoneDir, "phone.m4a"), []byte("audio"), 0644)
+	os.WriteFile(filepath.Join(tabletDir, "tablet.wav"), []byte("audio"), 0644)
+
+	select {
+	case event := <-phoneEvents:
+		if event.Path != filepath.Join(phoneDir, "phone.m4a") {
+			t.Errorf("expected phone.m4a on phone channel, got %s", event.Path)
+		}
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for phone event")
+	}
+
+	select {
+	case event := <-tabletEvents:
+		if event.Path != filepath.Join(tabletDir, "tablet.wav") {
+			t.Errorf("expected tablet.wav on tablet channel, got %s", event.Path)
+		}
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for tablet event")
+	}
+}
+
+func TestInotifyWatcher_SameDirectoryTwiceMergesPatterns(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	watcher, err := NewInotifyWatcher()
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer watcher.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	first, err := watcher.Watch(ctx, tmpDir, []string{"*.m4a"})
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+	second, err := watcher.Watch(ctx, tmpDir+"/", []string{"*.wav"})
+	if err != nil {
+		t.Fatalf("failed to watch again: %v", err)
+	}
+	if first != second {
+		t.Fatal("expected the directory's channel to be shared")
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	os.WriteFile(filepath.Join(tmpDir, "a.m4a"), []byte("audio"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "b.wav"), []byte("audio"), 0644)
+
+	seen := map[string]bool{}
+	for len(seen) < 2 {
+		select {
+		case event := <-first:
+			seen[filepath.Base(event.Path)] = true
+		case <-ctx.Done():
+			t.Fatalf("timeout, got only %v", seen)
+		}
+	}
+	if !seen["a.m4a"] || !seen["b.wav"] {
+		t.Errorf("expected files matching either pattern, got %v", seen)
+	}
+}
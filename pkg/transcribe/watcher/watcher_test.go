@@ -0,0 +1,102 @@
+package watcher
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// rawEvent encodes an inotify event as the kernel does, padding the name
+// with NULs to padTo bytes.
+func rawEvent(wd int32, mask uint32, name string, padTo int) []byte {
+	nameLen := 0
+	if name != "" {
+		nameLen = max(len(name)+1, padTo)
+	}
+	buf := make([]byte, unix.SizeofInotifyEvent+nameLen)
+	binary.NativeEndian.PutUint32(buf[0:4], uint32(wd))
+	binary.NativeEndian.PutUint32(buf[4:8], mask)
+	binary.NativeEndian.PutUint32(buf[12:16], uint32(nameLen))
+	copy(buf[unix.SizeofInotifyEvent:], name)
+	return buf
+}
+
+func concat(parts ...[]byte) []byte {
+	var buf []byte
+	for _, p := range parts {
+		buf = append(buf, p...)
+	}
+	return buf
+}
+
+func TestParseEvents(t *testing.T) {
+	memo := rawEvent(1, unix.IN_CLOSE_WRITE, "memo.m4a", 16)
+
+	tests := []struct {
+		name     string
+		buf      []byte
+		expected []inotifyEvent
+	}{
+		{"empty", nil, nil},
+		{"single", memo, []inotifyEvent{{wd: 1, mask: unix.IN_CLOSE_WRITE, name: "memo.m4a"}}},
+		{
+			"several",
+			concat(memo, rawEvent(2, unix.IN_MOVED_TO, "a-much-longer-recording-name.wav", 16)),
+			[]inotifyEvent{
+				{wd: 1, mask: unix.IN_CLOSE_WRITE, name: "memo.m4a"},
+				{wd: 2, mask: unix.IN_MOVED_TO, name: "a-much-longer-recording-name.wav"},
+			},
+		},
+		{
+			"overflow without name",
+			concat(rawEvent(-1, unix.IN_Q_OVERFLOW, "", 0), memo),
+			[]inotifyEvent{
+				{wd: -1, mask: unix.IN_Q_OVERFLOW},
+				{wd: 1, mask: unix.IN_CLOSE_WRITE, name: "memo.m4a"},
+			},
+		},
+		{"truncated header", memo[:unix.SizeofInotifyEvent-1], nil},
+		{
+			"truncated name",
+			concat(memo, rawEvent(1, unix.IN_CLOSE_WRITE, "second.m4a", 16)[:unix.SizeofInotifyEvent+4]),
+			[]inotifyEvent{{wd: 1, mask: unix.IN_CLOSE_WRITE, name: "memo.m4a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseEvents(tt.buf)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatchesPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		patterns []string
+		expected bool
+	}{
+		{"no patterns match everything", "notes.txt", nil, true},
+		{"extension", "memo.m4a", []string{"*.m4a"}, true},
+		{"second pattern", "memo.wav", []string{"*.m4a", "*.wav"}, true},
+		{"no match", "memo.txt", []string{"*.m4a", "*.wav"}, false},
+		{"case-sensitive", "MEMO.M4A", []string{"*.m4a"}, false},
+		{"prefix", "standup-0915.m4a", []string{"standup-*"}, true},
+		{"malformed pattern", "memo.m4a", []string{"[", "*.m4a"}, true},
+		{"partial files", ".memo.m4a.part", []string{"*.m4a"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesPatterns(tt.file, tt.patterns); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
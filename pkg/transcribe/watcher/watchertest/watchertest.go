@@ -0,0 +1,134 @@
+// Package watchertest provides a fake watcher.FileWatcher for testing code
+// that consumes file events, without inotify or real files.
+package watchertest
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/watcher"
+)
+
+// Watcher is a watcher.FileWatcher whose events are sent by the test with
+// Send. Patterns passed to Watch are recorded but not applied; the test
+// decides which events to send.
+type Watcher struct {
+	// Err, if set, is returned by Watch.
+	Err error
+
+	mu      sync.Mutex
+	watches map[string]*watch
+	stopped bool
+}
+
+// watch is one watched directory.
+type watch struct {
+	patterns []string
+	events   chan watcher.FileEvent
+	closed   bool
+}
+
+// New returns a Watcher with no watched directories.
+func New() *Watcher {
+	return &Watcher{watches: make(map[string]*watch)}
+}
+
+// Watch records dir and returns its events channel, which is closed when
+// ctx is cancelled or Stop is called. Watching a directory twice returns the
+// same channel.
+func (w *Watcher) Watch(ctx context.Context, dir string, patterns []string) (<-chan watcher.FileEvent, error) {
+	if w.Err != nil {
+		return nil, w.Err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	dir = filepath.Clean(dir)
+	if existing, ok := w.watches[dir]; ok {
+		existing.patterns = patterns
+		return existing.events, nil
+	}
+
+	wt := &watch{patterns: patterns, events: make(chan watcher.FileEvent, watcher.DefaultBufferSize)}
+	w.watches[dir] = wt
+	if w.stopped {
+		w.close(wt)
+	}
+	go func() {
+		<-ctx.Done()
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.close(wt)
+	}()
+	return wt.events, nil
+}
+
+// Send delivers an event for path to the channel of its directory, stamped
+// with the current time. It fails if the directory is not watched or its
+// channel has been closed, and blocks while the channel is full.
+func (w *Watcher) Send(path string, size int64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	wt, ok := w.watches[filepath.Dir(filepath.Clean(path))]
+	if !ok || wt.closed {
+		return fmt.Errorf("watchertest: %s is not in a watched directory", path)
+	}
+	// Sending under the lock keeps the channel from being closed meanwhile
+	wt.events <- watcher.FileEvent{Path: path, Size: size, Timestamp: time.Now()}
+	return nil
+}
+
+// Dirs returns the watched directories.
+func (w *Watcher) Dirs() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	dirs := make([]string, 0, len(w.watches))
+	for dir := range w.watches {
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// Patterns returns the patterns dir was watched with, and whether it is
+// watched.
+func (w *Watcher) Patterns(dir string) ([]string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	wt, ok := w.watches[filepath.Clean(dir)]
+	if !ok {
+		return nil, false
+	}
+	return wt.patterns, true
+}
+
+// Stop closes every events channel.
+func (w *Watcher) Stop() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopped = true
+	for _, wt := range w.watches {
+		w.close(wt)
+	}
+	return nil
+}
+
+// Stopped reports whether Stop has been called.
+func (w *Watcher) Stopped() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stopped
+}
+
+// close closes wt's channel once. w.mu must be held.
+func (w *Watcher) close(wt *watch) {
+	if !wt.closed {
+		wt.closed = true
+		close(wt.events)
+	}
+}
+
+var _ watcher.FileWatcher = (*Watcher)(nil)
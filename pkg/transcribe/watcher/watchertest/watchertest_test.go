@@ -0,0 +1,64 @@
+package watchertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestWatcher_SendDeliversToDirectory(t *testing.T) {
+	w := New()
+	phone, err := w.Watch(context.Background(), "/sync/phone", []string{"*.m4a"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	tablet, _ := w.Watch(context.Background(), "/sync/tablet/", nil)
+
+	if err := w.Send("/sync/tablet/memo.wav", 5); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if event := <-tablet; event.Path != "/sync/tablet/memo.wav" || event.Size != 5 || event.Timestamp.IsZero() {
+		t.Errorf("expected event for memo.wav, got %+v", event)
+	}
+	if len(phone) != 0 {
+		t.Error("expected no event for the other directory")
+	}
+
+	if err := w.Send("/elsewhere/memo.m4a", 5); err == nil {
+		t.Error("expected an error sending to an unwatched directory")
+	}
+	if patterns, ok := w.Patterns("/sync/phone"); !ok || len(patterns) != 1 || patterns[0] != "*.m4a" {
+		t.Errorf("expected recorded patterns, got %v", patterns)
+	}
+}
+
+func TestWatcher_ClosesChannels(t *testing.T) {
+	w := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled, _ := w.Watch(ctx, "/sync/phone", nil)
+	stopped, _ := w.Watch(context.Background(), "/sync/tablet", nil)
+
+	cancel()
+	if _, ok := <-cancelled; ok {
+		t.Error("expected channel to close when its context is cancelled")
+	}
+
+	w.Stop()
+	if _, ok := <-stopped; ok {
+		t.Error("expected channel to close on Stop")
+	}
+	if !w.Stopped() {
+		t.Error("expected Stopped to report true")
+	}
+	if err := w.Send("/sync/tablet/memo.m4a", 5); err == nil {
+		t.Error("expected an error sending after Stop")
+	}
+}
+
+func TestWatcher_Err(t *testing.T) {
+	w := New()
+	w.Err = errors.New("no such directory")
+	if _, err := w.Watch(context.Background(), "/missing", nil); !errors.Is(err, w.Err) {
+		t.Errorf("expected Err, got: %v", err)
+	}
+}
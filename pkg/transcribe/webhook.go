@@ -0,0 +1,126 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/logging"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/webhook"
+)
+
+// DefaultWebhookListen is the address the webhook receiver listens on when
+// listen is not set.
+const DefaultWebhookListen = "127.0.0.1:8790"
+
+// WebhookConfig enables an HTTP endpoint that sync tools can notify of new
+// files, alongside the directory watcher. Notified files go through the same
+// pipeline, but only files directly inside a watched directory that match its
+// patterns are accepted.
+type WebhookConfig struct {
+	// Listen is the host:port to listen on. Addresses other than loopback
+	// require a token.
+	Listen string `json:"listen,omitempty"`
+	// Token, when set, must be sent as "Authorization: Bearer <token>".
+	Token string `json:"token,omitempty"`
+}
+
+// address returns the configured listen address or the default.
+func (w WebhookConfig) address() string {
+	if w.Listen == "" {
+		return DefaultWebhookListen
+	}
+	return w.Listen
+}
+
+// validate checks the listen address, and that a token protects any
+// endpoint reachable from other machines.
+func (w WebhookConfig) validate() error {
+	host, _, err := net.SplitHostPort(w.address())
+	if err != nil {
+		return fmt.Errorf("%w: listen %q: %v", ErrInvalidWebhook, w.Listen, err)
+	}
+	if w.Token != "" {
+		return nil
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("%w: listen %q is not a loopback address, so a token is required", ErrInvalidWebhook, w.Listen)
+}
+
+// listenWebhook starts the webhook receiver, if configured, and returns its
+// events; nil when no webhook is configured. The listener is bound before
+// returning so an address in use fails startup.
+func (s *Service) listenWebhook(ctx context.Context) (<-chan FileEvent, error) {
+	if s.config.Webhook == nil {
+		return nil, nil
+	}
+
+	addr := s.config.Webhook.address()
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("start webhook receiver: %w", err)
+	}
+
+	logger := s.componentLogger("webhook")
+	srv := webhook.New(s.config.WatchBufferSize)
+	srv.Token = s.config.Webhook.Token
+	srv.Resolve = s.resolveNotifiedPath
+	srv.OnNotify = func(path string) {
+		logger.Info("file notified by webhook", logging.String("path", path))
+	}
+
+	go func() {
+		if err := srv.Serve(ctx, ln); err != nil {
+			logger.Error("webhook receiver stopped", err)
+		}
+	}()
+
+	s.logger.Info("listening for webhook notifications",
+		logging.String("address", "http://"+ln.Addr().String()+webhook.Path),
+	)
+	return srv.Events(), nil
+}
+
+// resolveNotifiedPath maps a notified path to a file directly inside a
+// watched directory that matches its patterns. Relative paths are looked up
+// in each watched directory in turn.
+func (s *Service) resolveNotifiedPath(path string) (string, error) {
+	watches := s.config.Watches()
+
+	if !filepath.IsAbs(path) {
+		found := ""
+		for _, wd := range watches {
+			candidate := filepath.Join(wd.Path, path)
+			if _, err := os.Stat(candidate); err == nil {
+				found = candidate
+				break
+			}
+		}
+		if found == "" {
+			return "", fmt.Errorf("%s: %w in any watched directory", path, os.ErrNotExist)
+		}
+		path = found
+	}
+
+	path = filepath.Clean(path)
+	name := filepath.Base(path)
+	for _, wd := range watches {
+		if filepath.Dir(path) != filepath.Clean(wd.Path) {
+			continue
+		}
+		for _, pattern := range wd.Patterns {
+			if matched, _ := filepath.Match(pattern, name); matched {
+				return path, nil
+			}
+		}
+		return "", fmt.Errorf("%w: %s does not match the watch patterns", webhook.ErrRejected, path)
+	}
+	return "", fmt.Errorf("%w: %s is not in a watched directory", webhook.ErrRejected, path)
+}
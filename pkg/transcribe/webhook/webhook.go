@@ -0,0 +1,202 @@
+// Package webhook receives file-ready notifications over HTTP, so sync tools
+// that can push "file added" callbacks (a Syncthing event relay, Nextcloud
+// flows) feed the transcription pipeline without relying on inotify alone.
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/watcher"
+)
+
+// Path is the endpoint notifications are posted to.
+const Path = "/files"
+
+// DefaultBufferSize is the default capacity of the events channel.
+const DefaultBufferSize = 100
+
+// maxBodySize bounds a notification body.
+const maxBodySize = 64 * 1024
+
+// ErrRejected is returned by a Resolve function for paths the service will
+// not process, such as files outside the watched directories.
+var ErrRejected = errors.New("file rejected")
+
+// Notification is the JSON body of a notification. A form or query
+// parameter named path is accepted as well.
+type Notification struct {
+	Path string `json:"path"`
+}
+
+// Server accepts POST /files notifications and emits them as file events.
+type Server struct {
+	// Token, when set, must be sent as "Authorization: Bearer <token>".
+	Token string
+	// Resolve maps a notified path to the file to process. It returns an
+	// error wrapping ErrRejected for files that must not be processed and
+	// os.ErrNotExist for missing ones. Nil accepts existing absolute paths.
+	Resolve func(path string) (string, error)
+	// OnNotify is called for each accepted file, if set.
+	OnNotify func(path string)
+
+	events chan watcher.FileEvent
+}
+
+// New creates a Server whose events channel buffers bufferSize events, or
+// DefaultBufferSize if bufferSize is not positive.
+func New(bufferSize int) *Server {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Server{events: make(chan watcher.FileEvent, bufferSize)}
+}
+
+// Events returns the channel accepted files are sent on. It is closed when
+// Serve returns.
+func (s *Server) Events() <-chan watcher.FileEvent {
+	return s.events
+}
+
+// Serve handles notifications on ln until ctx is cancelled, then closes the
+// events channel.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+		<-errCh
+	case err = <-errCh:
+	}
+	close(s.events)
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != Path {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	path, err := notifiedPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	path, size, err := s.resolve(path)
+	switch {
+	case errors.Is(err, ErrRejected):
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	case errors.Is(err, os.ErrNotExist):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	event := watcher.FileEvent{Path: path, Size: size, Timestamp: time.Now()}
+	select {
+	case s.events <- event:
+	case <-r.Context().Done():
+		return
+	}
+	if s.OnNotify != nil {
+		s.OnNotify(path)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"status": "accepted", "path": path})
+}
+
+// authorized reports whether r carries the configured token.
+func (s *Server) authorized(r *http.Request) bool {
+	if s.Token == "" {
+		return true
+	}
+	want := "Bearer " + s.Token
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(want)) == 1
+}
+
+// notifiedPath reads the path from a JSON body, or from a form or query
+// parameter.
+func notifiedPath(r *http.Request) (string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		var n Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			return "", fmt.Errorf("invalid JSON body: %v", err)
+		}
+		if n.Path == "" {
+			return "", errors.New("path is required")
+		}
+		return n.Path, nil
+	}
+
+	path := r.FormValue("path")
+	if path == "" {
+		return "", errors.New("path is required")
+	}
+	return path, nil
+}
+
+// resolve applies Resolve and returns the file's path and size.
+func (s *Server) resolve(path string) (string, int64, error) {
+	if s.Resolve != nil {
+		resolved, err := s.Resolve(path)
+		if err != nil {
+			return "", 0, err
+		}
+		path = resolved
+	} else if !filepath.IsAbs(path) {
+		return "", 0, fmt.Errorf("%w: %s is not an absolute path", ErrRejected, path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	if !info.Mode().IsRegular() {
+		return "", 0, fmt.Errorf("%w: %s is not a regular file", ErrRejected, path)
+	}
+	return path, info.Size(), nil
+}
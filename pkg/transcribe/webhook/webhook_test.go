@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func post(s *Server, contentType, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, Path, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_AcceptsJSONNotification(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(path, []byte("audio"), 0644)
+
+	s := New(1)
+	rec := post(s, "application/json", fmt.Sprintf(`{"path": %q}`, path), nil)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	select {
+	case event := <-s.Events():
+		if event.Path != path || event.Size != 5 {
+			t.Errorf("unexpected event: %+v", event)
+		}
+	default:
+		t.Fatal("expected an event")
+	}
+}
+
+func TestServer_AcceptsFormNotification(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memo.m4a")
+	os.WriteFile(path, []byte("audio"), 0644)
+
+	s := New(1)
+	rec := post(s, "application/x-www-form-urlencoded", url.Values{"path": {path}}.Encode(), nil)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if event := <-s.Events(); event.Path != path {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+func TestServer_Rejections(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "memo.m4a")
+	os.WriteFile(existing, []byte("audio"), 0644)
+
+	tests := []struct {
+		name     string
+		body     string
+		header   map[string]string
+		resolve  func(string) (string, error)
+		expected int
+	}{
+		{"missing path", `{}`, nil, nil, http.StatusBadRequest},
+		{"invalid JSON", `{`, nil, nil, http.StatusBadRequest},
+		{"relative path", `{"path": "memo.m4a"}`, nil, nil, http.StatusForbidden},
+		{"missing file", fmt.Sprintf(`{"path": %q}`, filepath.Join(dir, "gone.m4a")), nil, nil, http.StatusNotFound},
+		{"directory", fmt.Sprintf(`{"path": %q}`, dir), nil, nil, http.StatusForbidden},
+		{"rejected by resolver", fmt.Sprintf(`{"path": %q}`, existing), nil,
+			func(string) (string, error) { return "", fmt.Errorf("%w: not watched", ErrRejected) }, http.StatusForbidden},
+		{"bad token", fmt.Sprintf(`{"path": %q}`, existing), map[string]string{"Authorization": "Bearer wrong"}, nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(1)
+			s.Token = "secret"
+			s.Resolve = tt.resolve
+			header := tt.header
+			if header == nil {
+				header = map[string]string{"Authorization": "Bearer secret"}
+			}
+			rec := post(s, "application/json", tt.body, header)
+			if rec.Code != tt.expected {
+				t.Errorf("expected %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+			}
+			if len(s.Events()) != 0 {
+				t.Error("expected no event")
+			}
+		})
+	}
+}
+
+func TestServer_MethodAndPath(t *testing.T) {
+	s := New(1)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/other", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown path, got %d", rec.Code)
+	}
+}
+
+func TestServer_ServeClosesEventsOnCancel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	s := New(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx, ln) }()
+
+	resp, err := http.Post("http://"+ln.Addr().String()+Path, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("expected server to be reachable, got: %v", err)
+	}
+	resp.Body.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil && !errors.Is(err, context.Canceled) {
+			t.Errorf("expected clean shutdown, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for Serve to return")
+	}
+	if _, ok := <-s.Events(); ok {
+		t.Error("expected events channel to be closed")
+	}
+}
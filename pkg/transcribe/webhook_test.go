@@ -0,0 +1,150 @@
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/webhook"
+)
+
+func TestWebhookConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     WebhookConfig
+		wantErr bool
+	}{
+		{"default address", WebhookConfig{}, false},
+		{"loopback", WebhookConfig{Listen: "127.0.0.1:9999"}, false},
+		{"localhost", WebhookConfig{Listen: "localhost:9999"}, false},
+		{"ipv6 loopback", WebhookConfig{Listen: "[::1]:9999"}, false},
+		{"all interfaces without token", WebhookConfig{Listen: ":9999"}, true},
+		{"lan without token", WebhookConfig{Listen: "192.168.1.10:9999"}, true},
+		{"lan with token", WebhookConfig{Listen: "0.0.0.0:9999", Token: "secret"}, false},
+		{"no port", WebhookConfig{Listen: "127.0.0.1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validate()
+			if tt.wantErr && !errors.Is(err, ErrInvalidWebhook) {
+				t.Errorf("expected ErrInvalidWebhook, got: %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestResolveNotifiedPath(t *testing.T) {
+	watchDir := t.TempDir()
+	otherDir := t.TempDir()
+	os.WriteFile(filepath.Join(watchDir, "memo.m4a"), nil, 0644)
+	os.WriteFile(filepath.Join(watchDir, "notes.txt"), nil, 0644)
+
+	svc := &Service{config: &Config{WatchDir: watchDir, WatchPatterns: []string{"*.m4a"}}}
+
+	got, err := svc.resolveNotifiedPath(filepath.Join(watchDir, "memo.m4a"))
+	if err != nil || got != filepath.Join(watchDir, "memo.m4a") {
+		t.Errorf("expected absolute watched file to be accepted, got %q, %v", got, err)
+	}
+
+	got, err = svc.resolveNotifiedPath("memo.m4a")
+	if err != nil || got != filepath.Join(watchDir, "memo.m4a") {
+		t.Errorf("expected relative path to resolve in the watch dir, got %q, %v", got, err)
+	}
+
+	if _, err := svc.resolveNotifiedPath("missing.m4a"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist for missing relative path, got: %v", err)
+	}
+	if _, err := svc.resolveNotifiedPath(filepath.Join(watchDir, "notes.txt")); !errors.Is(err, webhook.ErrRejected) {
+		t.Errorf("expected ErrRejected for pattern mismatch, got: %v", err)
+	}
+	if _, err := svc.resolveNotifiedPath(filepath.Join(otherDir, "memo.m4a")); !errors.Is(err, webhook.ErrRejected) {
+		t.Errorf("expected ErrRejected outside watch dirs, got: %v", err)
+	}
+	if _, err := svc.resolveNotifiedPath("../" + filepath.Base(otherDir) + "/memo.m4a"); err == nil {
+		t.Error("expected error for path escaping the watch dir")
+	}
+}
+
+func TestService_WebhookFeedsPipeline(t *testing.T) {
+	cfg := setupBuilderTest(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	cfg.Webhook = &WebhookConfig{Listen: addr}
+
+	arch := &recordingArchiver{archived: make(chan string, 1)}
+	logger := &recordingLogger{}
+	svc, err := NewServiceWith(cfg, Options{
+		Watcher:    &fakeWatcher{events: make(chan FileEvent)},
+		Stabilizer: fakeStabilizer{},
+		Client:     fakeClient{},
+		Writer:     &recordingWriter{},
+		Archiver:   arch,
+		Logger:     logger,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- svc.Run(ctx) }()
+
+	audioPath := filepath.Join(cfg.WatchDir, "memo.m4a")
+	os.WriteFile(audioPath, []byte("audio"), 0644)
+
+	body := fmt.Sprintf(`{"path": %q}`, audioPath)
+	var resp *http.Response
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err = http.Post("http://"+addr+webhook.Path, "application/json", bytes.NewBufferString(body))
+		if err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("expected webhook to be reachable, got: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", resp.StatusCode)
+	}
+
+	select {
+	case path := <-arch.archived:
+		if path != audioPath {
+			t.Errorf("expected %s to be archived, got %s", audioPath, path)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for notified file to be archived")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected clean shutdown, got: %v", err)
+	}
+
+	logger.mu.Lock()
+	logged := strings.Join(logger.messages, "\n")
+	logger.mu.Unlock()
+	if !strings.Contains(logged, "listening for webhook notifications") {
+		t.Errorf("expected webhook startup to be logged, got:\n%s", logged)
+	}
+}
@@ -0,0 +1,229 @@
+// Package worddiff compares two transcripts word by word, for judging
+// whether one model transcribes noticeably differently from another.
+// Words are compared case-insensitively and without surrounding
+// punctuation, so "Hello," and "hello" match.
+package worddiff
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Change is a run of differing words: A's words replaced by B's. Either
+// side is empty for pure insertions and deletions.
+type Change struct {
+	A []string
+	B []string
+}
+
+// Summary is the word-level difference between transcripts A and B.
+type Summary struct {
+	WordsA int
+	WordsB int
+	// Same counts the words both transcripts share, in order.
+	Same int
+	// Substituted, Inserted and Deleted count the edits turning A into B:
+	// words of A replaced by a word of B, words only B has, and words only
+	// A has.
+	Substituted int
+	Inserted    int
+	Deleted     int
+	// Changes lists the differing runs in transcript order.
+	Changes []Change
+	// ops is the edit script, for Render.
+	ops []op
+}
+
+// Edits returns the number of word edits turning A into B.
+func (s *Summary) Edits() int {
+	return s.Substituted + s.Inserted + s.Deleted
+}
+
+// Rate returns Edits as a fraction of A's words, the word error rate of B
+// if A were the reference.
+func (s *Summary) Rate() float64 {
+	if s.WordsA == 0 {
+		if s.WordsB == 0 {
+			return 0
+		}
+		return 1
+	}
+	return float64(s.Edits()) / float64(s.WordsA)
+}
+
+// String summarizes the counts, e.g. "37 of 812 words differ (4.6%):
+// 21 substituted, 9 inserted, 7 deleted".
+func (s *Summary) String() string {
+	return fmt.Sprintf("%d of %d words differ (%.1f%%): %d substituted, %d inserted, %d deleted",
+		s.Edits(), s.WordsA, 100*s.Rate(), s.Substituted, s.Inserted, s.Deleted)
+}
+
+// opKind is an edit script step.
+type opKind int
+
+const (
+	keep opKind = iota
+	del
+	ins
+)
+
+// op is one word of the edit script.
+type op struct {
+	kind opKind
+	word string
+}
+
+// Compare diffs transcripts a and b.
+func Compare(a, b string) *Summary {
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	ka, kb := keys(wa), keys(wb)
+	s := &Summary{WordsA: len(wa), WordsB: len(wb), ops: script(wa, wb, ka, kb)}
+
+	// Adjacent deletions and insertions form one change; the overlap counts
+	// as substitutions
+	var cur Change
+	flush := func() {
+		if len(cur.A) == 0 && len(cur.B) == 0 {
+			return
+		}
+		sub := min(len(cur.A), len(cur.B))
+		s.Substituted += sub
+		s.Deleted += len(cur.A) - sub
+		s.Inserted += len(cur.B) - sub
+		s.Changes = append(s.Changes, cur)
+		cur = Change{}
+	}
+	for _, o := range s.ops {
+		switch o.kind {
+		case keep:
+			flush()
+			s.Same++
+		case del:
+			cur.A = append(cur.A, o.word)
+		case ins:
+			cur.B = append(cur.B, o.word)
+		}
+	}
+	flush()
+	return s
+}
+
+// Render returns B's transcript with the changes from A marked inline in
+// the style of git's word diff: [-removed-]{+added+}.
+func (s *Summary) Render() string {
+	var b strings.Builder
+	var removed, added []string
+	write := func(text string) {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(text)
+	}
+	flush := func() {
+		if len(removed) > 0 {
+			write("[-" + strings.Join(removed, " ") + "-]")
+		}
+		if len(added) > 0 {
+			write("{+" + strings.Join(added, " ") + "+}")
+		}
+		removed, added = nil, nil
+	}
+	for _, o := range s.ops {
+		switch o.kind {
+		case keep:
+			flush()
+			write(o.word)
+		case del:
+			removed = append(removed, o.word)
+		case ins:
+			added = append(added, o.word)
+		}
+	}
+	flush()
+	return b.String()
+}
+
+// keys returns the words as compared: lowercased, without surrounding
+// punctuation.
+func keys(words []string) []string {
+	k := make([]string, len(words))
+	for i, w := range words {
+		k[i] = strings.ToLower(strings.TrimFunc(w, unicode.IsPunct))
+	}
+	return k
+}
+
+// script returns the shortest edit script turning wa into wb, by Myers'
+// algorithm on the comparison keys. Kept words are taken from wb.
+func script(wa, wb, ka, kb []string) []op {
+	n, m := len(ka), len(kb)
+	// trace[d] holds, for each diagonal k in -d..d, the furthest x reached
+	// with d edits, at index k+d
+	var trace [][]int
+	v := []int{0}
+	found := false
+	for d := 0; !found; d++ {
+		next := make([]int, 2*d+1)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			switch {
+			case d == 0:
+				x = 0
+			case k == -d || (k != d && v[k-1+(d-1)] < v[k+1+(d-1)]):
+				// Down from diagonal k+1: an insertion
+				x = v[k+1+(d-1)]
+			default:
+				// Right from diagonal k-1: a deletion
+				x = v[k-1+(d-1)] + 1
+			}
+			y := x - k
+			for x < n && y < m && ka[x] == kb[y] {
+				x, y = x+1, y+1
+			}
+			next[k+d] = x
+			if x >= n && y >= m {
+				found = true
+			}
+		}
+		trace = append(trace, next)
+		v = next
+	}
+
+	// Walk back from the end, collecting the steps in reverse
+	var rev []op
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		k := x - y
+		var prevX, prevY int
+		if d > 0 {
+			prev := trace[d-1]
+			var prevK int
+			if k == -d || (k != d && prev[k-1+(d-1)] < prev[k+1+(d-1)]) {
+				prevK = k + 1
+			} else {
+				prevK = k - 1
+			}
+			prevX = prev[prevK+(d-1)]
+			prevY = prevX - prevK
+		}
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			rev = append(rev, op{keep, wb[y]})
+		}
+		if d > 0 {
+			if x == prevX {
+				rev = append(rev, op{ins, wb[prevY]})
+			} else {
+				rev = append(rev, op{del, wa[prevX]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	ops := make([]op, len(rev))
+	for i, o := range rev {
+		ops[len(rev)-1-i] = o
+	}
+	return ops
+}
@@ -0,0 +1,56 @@
+package worddiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name                string
+		a, b                string
+		same, sub, ins, del int
+		render              string
+	}{
+		{"identical", "Call the dentist.", "call the dentist", 3, 0, 0, 0, "call the dentist"},
+		{"substitution", "we're gonna ship friday", "we're going to ship Friday", 3, 1, 1, 0, "we're [-gonna-] {+going to+} ship Friday"},
+		{"deletion", "um so the plan is simple", "so the plan is simple", 5, 0, 0, 1, "[-um-] so the plan is simple"},
+		{"insertion", "buy milk", "buy oat milk", 2, 0, 1, 0, "buy {+oat+} milk"},
+		{"empty a", "", "hello there", 0, 0, 2, 0, "{+hello there+}"},
+		{"both empty", "", "", 0, 0, 0, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Compare(tt.a, tt.b)
+			if s.Same != tt.same || s.Substituted != tt.sub || s.Inserted != tt.ins || s.Deleted != tt.del {
+				t.Errorf("expected same %d, sub %d, ins %d, del %d, got %+v", tt.same, tt.sub, tt.ins, tt.del, s)
+			}
+			if got := s.Render(); got != tt.render {
+				t.Errorf("expected render %q, got %q", tt.render, got)
+			}
+		})
+	}
+}
+
+func TestSummary_Rate(t *testing.T) {
+	s := Compare("one two three four", "one too three four five")
+	if s.Edits() != 2 || s.Rate() != 0.5 {
+		t.Errorf("expected 2 edits at rate 0.5, got %d at %v", s.Edits(), s.Rate())
+	}
+	if want := "2 of 4 words differ (50.0%): 1 substituted, 1 inserted, 0 deleted"; s.String() != want {
+		t.Errorf("expected %q, got %q", want, s.String())
+	}
+	if len(s.Changes) != 2 || s.Changes[0].A[0] != "two" || s.Changes[0].B[0] != "too" || len(s.Changes[1].A) != 0 {
+		t.Errorf("unexpected changes: %+v", s.Changes)
+	}
+}
+
+func TestCompare_LongTranscripts(t *testing.T) {
+	a := strings.Repeat("the quick brown fox jumps over the lazy dog ", 500)
+	b := strings.Replace(a, "lazy", "sleepy", 3)
+	s := Compare(a, b)
+	if s.WordsA != 4500 || s.Substituted != 3 || s.Inserted != 0 || s.Deleted != 0 {
+		t.Errorf("expected 3 substitutions in 4500 words, got %+v", s.String())
+	}
+}
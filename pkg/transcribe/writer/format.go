@@ -0,0 +1,68 @@
+package writer
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Format is the markup of written notes. Only markdown notes carry YAML
+// frontmatter.
+type Format string
+
+// Supported note formats.
+const (
+	FormatMarkdown Format = "md"
+	FormatText     Format = "txt"
+	FormatOrg      Format = "org"
+)
+
+// Valid reports whether f is a supported format. Empty selects markdown.
+func (f Format) Valid() bool {
+	switch f {
+	case "", FormatMarkdown, FormatText, FormatOrg:
+		return true
+	}
+	return false
+}
+
+// Markdown reports whether f is markdown, the format for an empty f.
+func (f Format) Markdown() bool {
+	return f == "" || f == FormatMarkdown
+}
+
+// Extension returns the file extension of notes in the format, with the dot.
+func (f Format) Extension() string {
+	if f.Markdown() {
+		return ".md"
+	}
+	return "." + string(f)
+}
+
+// Heading returns a heading line of the given level, 1 being the top, e.g.
+// "## Transcription" in markdown or "** Transcription" in org. Plain text
+// headings are underlined with = at level 1 and - below it.
+func (f Format) Heading(level int, text string) string {
+	switch f {
+	case FormatText:
+		underline := "-"
+		if level <= 1 {
+			underline = "="
+		}
+		return text + "\n" + strings.Repeat(underline, max(utf8.RuneCountInString(text), 1)) + "\n"
+	case FormatOrg:
+		return strings.Repeat("*", max(level, 1)) + " " + text + "\n"
+	}
+	return strings.Repeat("#", max(level, 1)) + " " + text + "\n"
+}
+
+// Label returns label emphasized for a "Label: value" line, e.g. "**Date:**"
+// in markdown.
+func (f Format) Label(label string) string {
+	switch f {
+	case FormatText:
+		return label + ":"
+	case FormatOrg:
+		return "*" + label + ":*"
+	}
+	return "**" + label + ":**"
+}
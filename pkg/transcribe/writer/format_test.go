@@ -0,0 +1,40 @@
+package writer
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		format  Format
+		ext     string
+		heading string
+		sub     string
+		label   string
+	}{
+		{"", ".md", "# Meeting notes\n", "## Meeting notes\n", "**Date:**"},
+		{FormatMarkdown, ".md", "# Meeting notes\n", "## Meeting notes\n", "**Date:**"},
+		{FormatText, ".txt", "Meeting notes\n=============\n", "Meeting notes\n-------------\n", "Date:"},
+		{FormatOrg, ".org", "* Meeting notes\n", "** Meeting notes\n", "*Date:*"},
+	}
+
+	for _, tt := range tests {
+		if !tt.format.Valid() {
+			t.Errorf("%q: expected valid", tt.format)
+		}
+		if got := tt.format.Extension(); got != tt.ext {
+			t.Errorf("%q: expected extension %q, got %q", tt.format, tt.ext, got)
+		}
+		if got := tt.format.Heading(1, "Meeting notes"); got != tt.heading {
+			t.Errorf("%q: expected heading %q, got %q", tt.format, tt.heading, got)
+		}
+		if got := tt.format.Heading(2, "Meeting notes"); got != tt.sub {
+			t.Errorf("%q: expected subheading %q, got %q", tt.format, tt.sub, got)
+		}
+		if got := tt.format.Label("Date"); got != tt.label {
+			t.Errorf("%q: expected label %q, got %q", tt.format, tt.label, got)
+		}
+	}
+
+	if Format("docx").Valid() {
+		t.Error("expected docx to be invalid")
+	}
+}
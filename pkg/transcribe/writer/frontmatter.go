@@ -0,0 +1,142 @@
+package writer
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/frontmatter"
+)
+
+var (
+	// ErrNoProcessingInfo is returned for notes whose frontmatter does not
+	// record where their audio came from.
+	ErrNoProcessingInfo = errors.New("note has no processing information in its frontmatter")
+	// ErrNoTranscriptionSection is returned for notes without a
+	// "# Transcription" heading.
+	ErrNoTranscriptionSection = errors.New("note has no # Transcription section")
+)
+
+// processingKeys are the frontmatter keys written for ProcessingInfo.
+var processingKeys = []string{
+	"source_path", "archive_path", "provider", "model", "language",
+	"duration_seconds", "processing_seconds", "nota_version",
+	"device", "recording_source", "source_hash", "source_hashes",
+}
+
+// ParseProcessingInfo reads the processing information Render records in a
+// note's frontmatter.
+func ParseProcessingInfo(note string) (*ProcessingInfo, error) {
+	fields, ok := frontmatter.Parse(note)
+	if !ok || (fields["source_path"] == "" && fields["archive_path"] == "") {
+		return nil, ErrNoProcessingInfo
+	}
+
+	seconds := func(key string) time.Duration {
+		f, _ := strconv.ParseFloat(fields[key], 64)
+		return time.Duration(f * float64(time.Second))
+	}
+	return &ProcessingInfo{
+		SourcePath:      fields["source_path"],
+		ArchivePath:     fields["archive_path"],
+		Provider:        fields["provider"],
+		Model:           fields["model"],
+		Language:        fields["language"],
+		Duration:        seconds("duration_seconds"),
+		ProcessingTime:  seconds("processing_seconds"),
+		Version:         fields["nota_version"],
+		Device:          fields["device"],
+		RecordingSource: fields["recording_source"],
+		SourceHash:      fields["source_hash"],
+		SourceHashes:    parseHashes(fields["source_hashes"]),
+	}, nil
+}
+
+// Hashes returns the hashes of every recording in the note: SourceHashes,
+// or SourceHash for a note of one recording.
+func (p *ProcessingInfo) Hashes() []string {
+	if len(p.SourceHashes) > 0 {
+		return p.SourceHashes
+	}
+	if p.SourceHash != "" {
+		return []string{p.SourceHash}
+	}
+	return nil
+}
+
+// AddSourceHash adds the hash of a recording merged into a note to the
+// source_hashes list in its frontmatter, starting the list with the note's
+// source_hash. Notes without frontmatter are returned as is.
+func AddSourceHash(note, hash string) string {
+	fields, ok := frontmatter.Parse(note)
+	if !ok || hash == "" {
+		return note
+	}
+	info := ProcessingInfo{SourceHash: fields["source_hash"], SourceHashes: parseHashes(fields["source_hashes"])}
+	hashes := info.Hashes()
+	if slices.Contains(hashes, hash) {
+		return note
+	}
+	return frontmatter.Set(note, "source_hashes", formatHashes(append(hashes, hash)))
+}
+
+// formatHashes returns hashes as a YAML flow list of quoted strings.
+func formatHashes(hashes []string) string {
+	quoted := make([]string, len(hashes))
+	for i, h := range hashes {
+		quoted[i] = strconv.Quote(h)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// parseHashes reads a flow list written by formatHashes.
+func parseHashes(value string) []string {
+	inner, ok := strings.CutPrefix(value, "[")
+	if !ok {
+		return nil
+	}
+	var hashes []string
+	for _, item := range strings.Split(strings.TrimSuffix(inner, "]"), ",") {
+		item = strings.TrimSpace(item)
+		if unquoted, err := strconv.Unquote(item); err == nil {
+			item = unquoted
+		}
+		if item != "" {
+			hashes = append(hashes, item)
+		}
+	}
+	return hashes
+}
+
+// SetProcessingInfo replaces the processing information in a note's
+// frontmatter with p, keeping every other key.
+func SetProcessingInfo(note string, p *ProcessingInfo) string {
+	note = frontmatter.Remove(note, processingKeys...)
+	return frontmatter.Append(note, p.frontmatter())
+}
+
+// ReplaceTranscription replaces the content of the note's "# Transcription"
+// section, up to the next top-level heading, with text.
+func ReplaceTranscription(note, text string) (string, error) {
+	const heading = "# Transcription\n"
+
+	start := -1
+	if strings.HasPrefix(note, heading) {
+		start = 0
+	} else if i := strings.Index(note, "\n"+heading); i >= 0 {
+		start = i + 1
+	}
+	if start < 0 {
+		return "", ErrNoTranscriptionSection
+	}
+
+	bodyStart := start + len(heading)
+	rest := note[bodyStart:]
+	tail := ""
+	if end := strings.Index(rest, "\n# "); end >= 0 {
+		tail = "\n" + rest[end+1:]
+	}
+	return note[:bodyStart] + "\n" + text + "\n" + tail, nil
+}
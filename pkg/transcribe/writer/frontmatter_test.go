@@ -0,0 +1,102 @@
+package writer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseProcessingInfo_RoundTrip(t *testing.T) {
+	want := &ProcessingInfo{
+		SourcePath:      "/sync/voice: memo #1.m4a",
+		ArchivePath:     "/archive/2026/01/22/memo.m4a",
+		Provider:        "nas:9000",
+		Model:           "base",
+		Language:        "en",
+		Duration:        83 * time.Second,
+		ProcessingTime:  12500 * time.Millisecond,
+		Version:         "1.2.3",
+		Device:          "HUAWEI",
+		RecordingSource: "Huawei Recorder",
+		SourceHash:      "sha256:9f86d081884c7d65",
+		SourceHashes:    []string{"sha256:9f86d081884c7d65", "sha256:e3b0c44298fc1c14"},
+	}
+	note, _ := NewSimpleWriter().Render("Buy milk.", OutputOptions{SourceFile: want.SourcePath, Processing: want})
+
+	got, err := ParseProcessingInfo(note)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestParseProcessingInfo_Missing(t *testing.T) {
+	for _, note := range []string{"# Just a note\n", "---\ntype: transcription\n---\n\nText\n"} {
+		if _, err := ParseProcessingInfo(note); !errors.Is(err, ErrNoProcessingInfo) {
+			t.Errorf("expected ErrNoProcessingInfo for %q, got: %v", note, err)
+		}
+	}
+}
+
+func TestReplaceTranscription(t *testing.T) {
+	note := "---\ntype: transcription\n---\n\n# Transcription\n\nold text\n\n# Follow-ups\n\n- call Sam\n"
+
+	got, err := ReplaceTranscription(note, "new text")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	expected := "---\ntype: transcription\n---\n\n# Transcription\n\nnew text\n\n# Follow-ups\n\n- call Sam\n"
+	if got != expected {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, got)
+	}
+
+	if _, err := ReplaceTranscription("# Meeting\n\ntext\n", "new"); !errors.Is(err, ErrNoTranscriptionSection) {
+		t.Errorf("expected ErrNoTranscriptionSection, got: %v", err)
+	}
+}
+
+func TestSetProcessingInfo(t *testing.T) {
+	note := "---\ntags: [meeting]\nmodel: \"base\"\nlanguage: \"en\"\n---\n# Meeting\n"
+
+	got := SetProcessingInfo(note, &ProcessingInfo{Model: "large-v3"})
+	expected := "---\ntags: [meeting]\nmodel: \"large-v3\"\n---\n# Meeting\n"
+	if got != expected {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, got)
+	}
+	if !strings.HasPrefix(SetProcessingInfo("# Meeting\n", &ProcessingInfo{Model: "base"}), "---\nmodel:") {
+		t.Error("expected frontmatter to be added to a note without one")
+	}
+}
+
+func TestAddSourceHash(t *testing.T) {
+	note, _ := NewSimpleWriter().Render("Buy milk.", OutputOptions{
+		SourceFile: "/sync/memo.wav",
+		Processing: &ProcessingInfo{SourcePath: "/sync/memo.wav", SourceHash: "sha256:aa"},
+	})
+
+	note = AddSourceHash(note, "sha256:bb")
+	note = AddSourceHash(note, "sha256:cc")
+	if again := AddSourceHash(note, "sha256:bb"); again != note {
+		t.Errorf("expected a listed hash to leave the note as is, got:\n%s", again)
+	}
+
+	info, err := ParseProcessingInfo(note)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	expected := []string{"sha256:aa", "sha256:bb", "sha256:cc"}
+	if !reflect.DeepEqual(info.Hashes(), expected) {
+		t.Errorf("expected hashes %v, got %v", expected, info.Hashes())
+	}
+	if info.SourceHash != "sha256:aa" {
+		t.Errorf("expected source_hash kept, got %q", info.SourceHash)
+	}
+
+	if got := AddSourceHash("# Memo\n", "sha256:bb"); got != "# Memo\n" {
+		t.Errorf("expected a note without frontmatter unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+package writer
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/internal/golden"
+)
+
+// goldenProcessing is the processing info of the golden notes.
+var goldenProcessing = &ProcessingInfo{
+	SourcePath:     "/sync/phone/memo.m4a",
+	ArchivePath:    "/archive/2026/01/22/memo.m4a",
+	Provider:       "whisper-asr",
+	Model:          "base",
+	Language:       "en",
+	Duration:       83 * time.Second,
+	ProcessingTime: 12500 * time.Millisecond,
+	Version:        "1.2.3",
+	SourceHash:     "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+}
+
+// TestRender_Golden compares rendered notes with testdata/golden; run with
+// -update to rewrite them.
+func TestRender_Golden(t *testing.T) {
+	text := "Remind me to call the dentist on Monday.\n\nAlso buy milk."
+	base := OutputOptions{
+		OutputDir:  "/vault/Inbox",
+		SourceFile: "/sync/phone/memo.m4a",
+		Timestamp:  time.Date(2026, 1, 22, 9, 30, 0, 0, time.UTC),
+		Title:      "Call the dentist",
+		Tags:       []string{"dentist", "health"},
+		Processing: goldenProcessing,
+	}
+
+	tests := []struct {
+		golden string
+		opts   func(*OutputOptions)
+	}{
+		{"plain.md", func(o *OutputOptions) {}},
+		{"plain-untitled.md", func(o *OutputOptions) { o.Title, o.Tags, o.Processing = "", nil, nil }},
+		{"plain-footer.md", func(o *OutputOptions) { o.Footer = true }},
+		{"plain.txt", func(o *OutputOptions) { o.Format = FormatText; o.Footer = true }},
+		{"plain.org", func(o *OutputOptions) { o.Format = FormatOrg; o.Footer = true }},
+		{"template-frontmatter.md", func(o *OutputOptions) { o.TemplatePath = filepath.Join("testdata", "templates", "meeting.md") }},
+		{"template-plain.md", func(o *OutputOptions) { o.TemplatePath = filepath.Join("testdata", "templates", "plain.md") }},
+		{"template.org", func(o *OutputOptions) {
+			o.TemplatePath = filepath.Join("testdata", "templates", "plain.md")
+			o.Format = FormatOrg
+		}},
+	}
+
+	w := NewSimpleWriter()
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			opts := base
+			tt.opts(&opts)
+			note, err := w.Render(text, opts)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			golden.Assert(t, tt.golden, note)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package writer
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is the locale used when none is configured.
+const DefaultLocale = "en"
+
+// Locale holds the language-specific text and date layout of a plain note.
+type Locale struct {
+	// Title is the note's top-level heading.
+	Title string
+	// Date, Source and Transcription label the note's sections.
+	Date          string
+	Source        string
+	Transcription string
+	// DateLayout formats the recording date, in time.Format layout.
+	DateLayout string
+}
+
+var locales = map[string]Locale{
+	"en": {Title: "Voice Note", Date: "Date", Source: "Source", Transcription: "Transcription", DateLayout: "2006-01-02 15:04"},
+	"de": {Title: "Sprachnotiz", Date: "Datum", Source: "Quelle", Transcription: "Transkription", DateLayout: "02.01.2006 15:04"},
+	"es": {Title: "Nota de voz", Date: "Fecha", Source: "Origen", Transcription: "Transcripción", DateLayout: "02/01/2006 15:04"},
+	"fr": {Title: "Note vocale", Date: "Date", Source: "Source", Transcription: "Transcription", DateLayout: "02/01/2006 15:04"},
+	"it": {Title: "Nota vocale", Date: "Data", Source: "Origine", Transcription: "Trascrizione", DateLayout: "02/01/2006 15:04"},
+	"nl": {Title: "Spraaknotitie", Date: "Datum", Source: "Bron", Transcription: "Transcriptie", DateLayout: "02-01-2006 15:04"},
+	"pt": {Title: "Nota de voz", Date: "Data", Source: "Origem", Transcription: "Transcrição", DateLayout: "02/01/2006 15:04"},
+}
+
+// LookupLocale returns the locale for a language tag such as "de" or
+// "de-AT"; region subtags fall back to the language. An empty tag selects
+// DefaultLocale. ok is false for unsupported languages.
+func LookupLocale(tag string) (locale Locale, ok bool) {
+	if tag == "" {
+		tag = DefaultLocale
+	}
+	lang, _, _ := strings.Cut(strings.ToLower(tag), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	locale, ok = locales[lang]
+	return locale, ok
+}
+
+// Locales returns the supported language tags in sorted order.
+func Locales() []string {
+	tags := make([]string, 0, len(locales))
+	for tag := range locales {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,28 @@
+package writer
+
+import "testing"
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		tag   string
+		title string
+		ok    bool
+	}{
+		{"", "Voice Note", true},
+		{"en", "Voice Note", true},
+		{"de", "Sprachnotiz", true},
+		{"de-AT", "Sprachnotiz", true},
+		{"DE_ch", "Sprachnotiz", true},
+		{"xx", "", false},
+	}
+
+	for _, tt := range tests {
+		locale, ok := LookupLocale(tt.tag)
+		if ok != tt.ok {
+			t.Errorf("LookupLocale(%q): expected ok %v, got %v", tt.tag, tt.ok, ok)
+		}
+		if locale.Title != tt.title {
+			t.Errorf("LookupLocale(%q): expected title %q, got %q", tt.tag, tt.title, locale.Title)
+		}
+	}
+}
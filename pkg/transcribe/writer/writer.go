@@ -0,0 +1,286 @@
+// Package writer provides output writing capabilities for the transcription service.
+package writer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/clock"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/fileperm"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/frontmatter"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/notes"
+)
+
+// OutputOptions configures output writing.
+type OutputOptions struct {
+	OutputDir    string
+	TemplatePath string
+	SourceFile   string
+	Timestamp    time.Time
+	// Locale selects the language of a plain note's headings and date
+	// format, as a tag such as "de"; empty means DefaultLocale.
+	Locale string
+	// Format selects the note's markup and file extension; empty means
+	// markdown.
+	Format Format
+	// Title, when set, is the note's heading and, as a slug, the start of
+	// its file name in place of the audio file's name.
+	Title string
+	// Tags, when set, are added to the note's frontmatter tags, merged with
+	// any the template already lists.
+	Tags []string
+	// Processing, when set, is recorded in the note's frontmatter.
+	Processing *ProcessingInfo
+	// Footer adds a section listing the Processing stats to the end of the
+	// note, readable in any format.
+	Footer bool
+}
+
+// ProcessingInfo records how a note was produced so tooling can trace the
+// note back to its audio. Empty fields are left out of the note.
+type ProcessingInfo struct {
+	SourcePath  string
+	ArchivePath string
+	// Provider names the transcription provider, which may be a fallback.
+	Provider       string
+	Model          string
+	Language       string
+	Duration       time.Duration
+	ProcessingTime time.Duration
+	Version        string
+	// Device and RecordingSource label what made the recording, as
+	// detected from its file name.
+	Device          string
+	RecordingSource string
+	// SourceHash is the SHA-256 of the audio, "sha256:<hex>", so a file
+	// processed again can be matched to the note already written for it.
+	SourceHash string
+	// SourceHashes lists the hashes of every recording in a note merged
+	// from several, the first included; see AddSourceHash.
+	SourceHashes []string
+}
+
+// frontmatter returns the YAML lines for the populated fields.
+func (p *ProcessingInfo) frontmatter() string {
+	if p == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeString := func(key, value string) {
+		if value != "" {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", key, strconv.Quote(value)))
+		}
+	}
+	writeSeconds := func(key string, d time.Duration) {
+		if d > 0 {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", key, strconv.FormatFloat(d.Seconds(), 'f', 1, 64)))
+		}
+	}
+
+	writeString("source_path", p.SourcePath)
+	writeString("archive_path", p.ArchivePath)
+	writeString("provider", p.Provider)
+	writeString("model", p.Model)
+	writeString("language", p.Language)
+	writeSeconds("duration_seconds", p.Duration)
+	writeSeconds("processing_seconds", p.ProcessingTime)
+	writeString("nota_version", p.Version)
+	writeString("device", p.Device)
+	writeString("recording_source", p.RecordingSource)
+	writeString("source_hash", p.SourceHash)
+	if len(p.SourceHashes) > 0 {
+		sb.WriteString("source_hashes: " + formatHashes(p.SourceHashes) + "\n")
+	}
+	return sb.String()
+}
+
+// AppendFooter returns text followed by a Processing section listing the
+// provider, model, language, audio duration, processing time and nota version in
+// format f. Empty fields are left out, and a nil p leaves text as is.
+func AppendFooter(text string, p *ProcessingInfo, f Format) string {
+	if p == nil {
+		return text
+	}
+
+	var items []string
+	add := func(label, value string) {
+		if value != "" {
+			items = append(items, "- "+f.Label(label)+" "+value)
+		}
+	}
+	duration := func(d time.Duration) string {
+		if d <= 0 {
+			return ""
+		}
+		return d.Round(100 * time.Millisecond).String()
+	}
+
+	add("Provider", p.Provider)
+	add("Model", p.Model)
+	add("Language", p.Language)
+	add("Duration", duration(p.Duration))
+	add("Processing time", duration(p.ProcessingTime))
+	add("nota version", p.Version)
+	if len(items) == 0 {
+		return text
+	}
+	return text + "\n\n" + f.Heading(2, "Processing") + "\n" + strings.Join(items, "\n")
+}
+
+// OutputWriter saves transcriptions to the vault.
+type OutputWriter interface {
+	Write(ctx context.Context, text string, opts OutputOptions) (string, error)
+}
+
+// SimpleWriter implements OutputWriter with basic file writing.
+type SimpleWriter struct {
+	perms fileperm.Permissions
+	clock clock.Clock
+}
+
+// Option configures a SimpleWriter.
+type Option func(*SimpleWriter)
+
+// WithPermissions sets the mode and group of written notes and the
+// directories created for them.
+func WithPermissions(p fileperm.Permissions) Option {
+	return func(w *SimpleWriter) {
+		w.perms = p
+	}
+}
+
+// WithClock sets the clock used to name notes written without a timestamp.
+func WithClock(c clock.Clock) Option {
+	return func(w *SimpleWriter) {
+		w.clock = clock.OrReal(c)
+	}
+}
+
+// NewSimpleWriter creates a new simple output writer.
+func NewSimpleWriter(opts ...Option) *SimpleWriter {
+	w := &SimpleWriter{clock: clock.Real{}}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Write saves the transcription text to a note in opts.Format.
+// The file is named based on the source audio file with the format's
+// extension, with a numbered suffix if that name is taken. The content is
+// produced by Render.
+func (w *SimpleWriter) Write(ctx context.Context, text string, opts OutputOptions) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	default:
+	}
+
+	outputPath, err := notes.CreateNote(w.note(text, opts))
+	if err != nil {
+		return "", fmt.Errorf("write transcription file: %w", err)
+	}
+	return outputPath, nil
+}
+
+// OutputPath returns the path Write would create for the given options
+// when the name is free. The file is named after the title's slug, or the
+// source audio file, plus a timestamp for uniqueness.
+func (w *SimpleWriter) OutputPath(opts OutputOptions) string {
+	return filepath.Join(opts.OutputDir, w.noteName(opts)+opts.Format.Extension())
+}
+
+// noteName returns the note's file name without extension.
+func (w *SimpleWriter) noteName(opts OutputOptions) string {
+	baseName := filepath.Base(opts.SourceFile)
+	nameWithoutExt := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+	if slug := Slug(opts.Title); slug != "" {
+		nameWithoutExt = slug
+	}
+
+	timestamp := opts.Timestamp
+	if timestamp.IsZero() {
+		timestamp = w.clock.Now()
+	}
+	return nameWithoutExt + "-" + timestamp.Format("2006-01-02-150405")
+}
+
+// Render returns the note content Write would save for the transcription.
+// If opts.TemplatePath is set, the transcription is appended to the template;
+// otherwise the note has a Transcription heading, under YAML frontmatter in
+// markdown. For markdown notes, processing information is added to the
+// template's frontmatter, or to a new frontmatter block if the template has
+// none; other formats use the template as written. With opts.Footer, the
+// processing stats also follow the transcription.
+func (w *SimpleWriter) Render(text string, opts OutputOptions) (string, error) {
+	return notes.Render(w.note(text, opts))
+}
+
+// note describes the note for the transcription to the notes package.
+func (w *SimpleWriter) note(text string, opts OutputOptions) notes.Options {
+	if opts.Footer {
+		text = AppendFooter(text, opts.Processing, opts.Format)
+	}
+	note := notes.Options{
+		Dir:          opts.OutputDir,
+		Name:         w.noteName(opts),
+		Ext:          opts.Format.Extension(),
+		TemplatePath: opts.TemplatePath,
+		Body:         text,
+		Perms:        w.perms,
+	}
+
+	heading := "Transcription"
+	if opts.Title != "" {
+		heading = opts.Title
+	}
+	switch {
+	case opts.TemplatePath != "":
+		if opts.Format.Markdown() {
+			note.Tags = opts.Tags
+			note.Frontmatter = titleFrontmatter(opts.Title) + opts.Processing.frontmatter()
+		}
+	case opts.Format.Markdown():
+		note.Frontmatter = transcriptionFrontmatter(opts)
+		note.Body = fmt.Sprintf("# %s\n\n%s", heading, text)
+	default:
+		note.Body = opts.Format.Heading(1, heading) + "\n" + text
+	}
+	return note
+}
+
+// transcriptionFrontmatter returns the YAML lines of a note written without
+// a template.
+func transcriptionFrontmatter(opts OutputOptions) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("source: %s\n", filepath.Base(opts.SourceFile)))
+	if !opts.Timestamp.IsZero() {
+		sb.WriteString(fmt.Sprintf("transcribed: %s\n", opts.Timestamp.Format(time.RFC3339)))
+	}
+	sb.WriteString("type: transcription\n")
+	sb.WriteString(titleFrontmatter(opts.Title))
+	if len(opts.Tags) > 0 {
+		sb.WriteString(frontmatter.FormatTags(opts.Tags))
+	}
+	sb.WriteString(opts.Processing.frontmatter())
+	return sb.String()
+}
+
+// titleFrontmatter returns the YAML title line, or "" when there is no title.
+func titleFrontmatter(title string) string {
+	if title == "" {
+		return ""
+	}
+	return fmt.Sprintf("title: %s\n", strconv.Quote(title))
+}
+
+// Slug turns a title into a lowercase, hyphen-separated file name part; see
+// notes.Slug.
+func Slug(title string) string {
+	return notes.Slug(title)
+}
@@ -0,0 +1,288 @@
+package writer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/transcribe/fileperm"
+)
+
+func TestRender_ProcessingFrontmatter(t *testing.T) {
+	w := NewSimpleWriter()
+	opts := OutputOptions{
+		OutputDir:  t.TempDir(),
+		SourceFile: "/sync/memo.m4a",
+		Processing: &ProcessingInfo{
+			SourcePath:     "/sync/memo.m4a",
+			ArchivePath:    "/archive/2026/01/22/memo.m4a",
+			Model:          "base",
+			Language:       "en",
+			Duration:       83 * time.Second,
+			ProcessingTime: 12500 * time.Millisecond,
+			Version:        "1.2.3",
+		},
+	}
+
+	note, err := w.Render("Buy milk.", opts)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	expected := `---
+source: memo.m4a
+type: transcription
+source_path: "/sync/memo.m4a"
+archive_path: "/archive/2026/01/22/memo.m4a"
+model: "base"
+language: "en"
+duration_seconds: 83.0
+processing_seconds: 12.5
+nota_version: "1.2.3"
+---
+`
+	if !strings.HasPrefix(note, expected) {
+		t.Errorf("expected frontmatter:\n%s\ngot:\n%s", expected, note)
+	}
+}
+
+func TestRender_ProcessingOmitsEmptyFields(t *testing.T) {
+	w := NewSimpleWriter()
+	note, err := w.Render("Buy milk.", OutputOptions{
+		SourceFile: "/sync/memo.m4a",
+		Processing: &ProcessingInfo{SourcePath: "/sync/memo.m4a"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	for _, unwanted := range []string{"archive_path", "language", "duration_seconds"} {
+		if strings.Contains(note, unwanted) {
+			t.Errorf("expected no %s in note, got:\n%s", unwanted, note)
+		}
+	}
+}
+
+func TestRender_TitleAndTags(t *testing.T) {
+	w := NewSimpleWriter()
+	opts := OutputOptions{
+		OutputDir:  "/vault/Inbox",
+		SourceFile: "/sync/memo.m4a",
+		Timestamp:  time.Date(2026, 1, 22, 9, 30, 0, 0, time.UTC),
+		Title:      "Call the dentist",
+		Tags:       []string{"dentist", "health"},
+	}
+
+	note, err := w.Render("Remind me to call the dentist.", opts)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(note, "title: \"Call the dentist\"\n") || !strings.Contains(note, "# Call the dentist\n") {
+		t.Errorf("expected title in frontmatter and heading, got:\n%s", note)
+	}
+	if !strings.Contains(note, "tags: [dentist, health]\n") {
+		t.Errorf("expected tags in frontmatter, got:\n%s", note)
+	}
+
+	expected := "/vault/Inbox/call-the-dentist-2026-01-22-093000.md"
+	if got := w.OutputPath(opts); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{"Call the dentist", "call-the-dentist"},
+		{"Don't forget: Q3 budget!", "dont-forget-q3-budget"},
+		{"Réunion d'équipe", "réunion-déquipe"},
+		{"  --- ", ""},
+		{strings.Repeat("word ", 20), "word-word-word-word-word-word-word-word-word-word-word-word"},
+	}
+	for _, tt := range tests {
+		if got := Slug(tt.title); got != tt.expected {
+			t.Errorf("Slug(%q): expected %q, got %q", tt.title, tt.expected, got)
+		}
+	}
+}
+
+func TestRender_TemplateFrontmatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "merged into existing frontmatter",
+			template: "---\ntags: [meeting]\n---\n# Meeting\n",
+			expected: "---\ntags: [meeting]\nmodel: \"base\"\n---\n# Meeting\n\nBuy milk.\n",
+		},
+		{
+			name:     "empty frontmatter",
+			template: "---\n---\n# Meeting\n",
+			expected: "---\nmodel: \"base\"\n---\n# Meeting\n\nBuy milk.\n",
+		},
+		{
+			name:     "new frontmatter",
+			template: "# Meeting\n",
+			expected: "---\nmodel: \"base\"\n---\n\n# Meeting\n\nBuy milk.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templatePath := filepath.Join(t.TempDir(), "template.md")
+			if err := os.WriteFile(templatePath, []byte(tt.template), 0644); err != nil {
+				t.Fatalf("failed to write template: %v", err)
+			}
+
+			note, err := NewSimpleWriter().Render("Buy milk.", OutputOptions{
+				TemplatePath: templatePath,
+				SourceFile:   "/sync/memo.m4a",
+				Processing:   &ProcessingInfo{Model: "base"},
+			})
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if note != tt.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", tt.expected, note)
+			}
+		})
+	}
+}
+
+func TestWrite_Permissions(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "Inbox")
+	w := NewSimpleWriter(WithPermissions(fileperm.Permissions{FileMode: 0660, DirMode: 0770}))
+
+	path, err := w.Write(context.Background(), "hello", OutputOptions{
+		OutputDir:  outputDir,
+		SourceFile: "/tmp/memo.m4a",
+		Timestamp:  time.Date(2026, 1, 22, 14, 30, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0660 {
+		t.Errorf("expected note mode 0660, got %o", info.Mode().Perm())
+	}
+	info, _ = os.Stat(outputDir)
+	if info.Mode().Perm() != 0770 {
+		t.Errorf("expected directory mode 0770, got %o", info.Mode().Perm())
+	}
+}
+
+func TestWrite_TextFormat(t *testing.T) {
+	outputDir := t.TempDir()
+	path, err := NewSimpleWriter().Write(context.Background(), "Buy milk.", OutputOptions{
+		OutputDir:  outputDir,
+		SourceFile: "/sync/memo.m4a",
+		Timestamp:  time.Date(2026, 1, 22, 9, 30, 0, 0, time.UTC),
+		Format:     FormatText,
+		Tags:       []string{"groceries"},
+		Processing: &ProcessingInfo{Model: "base"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if want := filepath.Join(outputDir, "memo-2026-01-22-093000.txt"); path != want {
+		t.Errorf("expected path %s, got %s", want, path)
+	}
+	content, _ := os.ReadFile(path)
+	expected := "Transcription\n=============\n\nBuy milk.\n"
+	if string(content) != expected {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, content)
+	}
+}
+
+func TestRender_OrgTemplateHasNoFrontmatter(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "template.org")
+	if err := os.WriteFile(templatePath, []byte("* Meeting\n"), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	note, err := NewSimpleWriter().Render("Buy milk.", OutputOptions{
+		TemplatePath: templatePath,
+		SourceFile:   "/sync/memo.m4a",
+		Format:       FormatOrg,
+		Processing:   &ProcessingInfo{Model: "base"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if expected := "* Meeting\n\nBuy milk.\n"; note != expected {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, note)
+	}
+}
+
+func TestAppendFooter(t *testing.T) {
+	info := &ProcessingInfo{
+		SourcePath:     "/sync/memo.m4a",
+		Model:          "base",
+		Language:       "en",
+		Duration:       83 * time.Second,
+		ProcessingTime: 12530 * time.Millisecond,
+		Version:        "1.2.3",
+	}
+
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{FormatMarkdown, "Buy milk.\n\n## Processing\n\n- **Model:** base\n- **Language:** en\n- **Duration:** 1m23s\n- **Processing time:** 12.5s\n- **nota version:** 1.2.3"},
+		{FormatOrg, "Buy milk.\n\n** Processing\n\n- *Model:* base\n- *Language:* en\n- *Duration:* 1m23s\n- *Processing time:* 12.5s\n- *nota version:* 1.2.3"},
+	}
+	for _, tt := range tests {
+		if got := AppendFooter("Buy milk.", info, tt.format); got != tt.expected {
+			t.Errorf("%s: expected:\n%q\ngot:\n%q", tt.format, tt.expected, got)
+		}
+	}
+
+	if got := AppendFooter("Buy milk.", &ProcessingInfo{Model: "base"}, FormatText); got != "Buy milk.\n\nProcessing\n----------\n\n- Model: base" {
+		t.Errorf("expected empty fields left out, got %q", got)
+	}
+	if got := AppendFooter("Buy milk.", nil, FormatMarkdown); got != "Buy milk." {
+		t.Errorf("expected no footer without processing info, got %q", got)
+	}
+}
+
+func TestRender_Footer(t *testing.T) {
+	opts := OutputOptions{
+		SourceFile: "/sync/memo.m4a",
+		Processing: &ProcessingInfo{Model: "base"},
+	}
+
+	note, err := NewSimpleWriter().Render("Buy milk.", opts)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if strings.Contains(note, "## Processing") {
+		t.Errorf("expected no footer unless enabled, got:\n%s", note)
+	}
+
+	opts.Footer = true
+	note, err = NewSimpleWriter().Render("Buy milk.", opts)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.HasSuffix(note, "# Transcription\n\nBuy milk.\n\n## Processing\n\n- **Model:** base\n") {
+		t.Errorf("expected footer after the transcription, got:\n%s", note)
+	}
+
+	// Reprocessing replaces the footer along with the transcription
+	replaced, err := ReplaceTranscription(note, AppendFooter("Buy bread.", &ProcessingInfo{Model: "large-v3"}, FormatMarkdown))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if strings.Contains(replaced, "Buy milk.") || strings.Count(replaced, "## Processing") != 1 || !strings.Contains(replaced, "large-v3") {
+		t.Errorf("expected the footer replaced with the transcription, got:\n%s", replaced)
+	}
+}
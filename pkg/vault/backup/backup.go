@@ -0,0 +1,364 @@
+// Package backup snapshots a vault into timestamped, zstd-compressed tar
+// archives, keeps the newest few in a directory or an S3 bucket, and
+// restores them.
+package backup
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/ignore"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Ext is the file extension of backup archives.
+const Ext = ".tar.zst"
+
+// DefaultKeep is how many backups are kept when no retention is given.
+const DefaultKeep = 7
+
+// stampLayout is the layout of the time in archive names. It sorts the same
+// as the times.
+const stampLayout = "20060102T150405Z"
+
+// Errors returned by the package.
+var (
+	ErrNotEmpty    = errors.New("restore directory is not empty")
+	ErrUnsafePath  = errors.New("archive entry escapes the restore directory")
+	ErrInvalidName = errors.New("not a backup archive name")
+)
+
+// Options configures a backup.
+type Options struct {
+	// Keep is how many backups of the vault, including the new one, are
+	// kept; older ones are removed. 0 keeps them all.
+	Keep int
+	// Exclude lists absolute paths inside the vault left out of the archive.
+	// A DirStore's directory is always left out.
+	Exclude []string
+	// Now is the time the backup is named after. Defaults to time.Now.
+	Now time.Time
+}
+
+// Result describes a backup.
+type Result struct {
+	// Archive is the name of the new archive in the store.
+	Archive string `json:"archive"`
+	// Files is the number of files archived.
+	Files int `json:"files"`
+	// Size is the size of the archive in bytes.
+	Size int64 `json:"size"`
+	// Removed lists the old archives removed by rotation.
+	Removed []string `json:"removed"`
+}
+
+// Prefix returns the prefix of the names of the vault at root's archives.
+func Prefix(root string) string {
+	return filepath.Base(filepath.Clean(root))
+}
+
+// Name returns the name of the vault at root's archive taken at t, e.g.
+// notes-20261016T093000Z.tar.zst.
+func Name(root string, t time.Time) string {
+	return Prefix(root) + "-" + t.UTC().Format(stampLayout) + Ext
+}
+
+// Time returns the time the archive named name with the given prefix was
+// taken.
+func Time(name, prefix string) (time.Time, error) {
+	stamp, ok := strings.CutPrefix(name, prefix+"-")
+	if ok {
+		stamp, ok = strings.CutSuffix(stamp, Ext)
+	}
+	if !ok {
+		return time.Time{}, fmt.Errorf("%s: %w", name, ErrInvalidName)
+	}
+	t, err := time.Parse(stampLayout, stamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", name, ErrInvalidName)
+	}
+	return t, nil
+}
+
+// Backup archives the vault at root into store and then removes all but the
+// newest opts.Keep of its archives there.
+func Backup(ctx context.Context, root string, store Store, opts Options) (*Result, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	f, err := os.CreateTemp("", "nota-backup-*"+Ext)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	exclude := append([]string(nil), opts.Exclude...)
+	if ds, ok := store.(*DirStore); ok {
+		// A backup folder inside the vault must not back itself up
+		exclude = append(exclude, ds.Dir)
+	}
+	files, err := Write(root, f, exclude...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive %s: %w", root, err)
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Archive: Name(root, now), Files: files, Size: size}
+	if err := store.Put(ctx, result.Archive, f); err != nil {
+		return nil, fmt.Errorf("failed to store %s: %w", result.Archive, err)
+	}
+
+	result.Removed, err = Rotate(ctx, store, Prefix(root), opts.Keep)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// Rotate removes all but the newest keep archives with the given prefix
+// from store and returns their names. Other files are left alone. A keep of
+// 0 removes nothing.
+func Rotate(ctx context.Context, store Store, prefix string, keep int) ([]string, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+	archives, err := List(ctx, store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if len(archives) <= keep {
+		return nil, nil
+	}
+
+	var removed []string
+	for _, name := range archives[:len(archives)-keep] {
+		if err := store.Delete(ctx, name); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+		removed = append(removed, name)
+	}
+	return removed, nil
+}
+
+// List returns the names of the archives with the given prefix in store,
+// oldest first.
+func List(ctx context.Context, store Store, prefix string) ([]string, error) {
+	names, err := store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var archives []string
+	for _, name := range names {
+		if _, err := Time(name, prefix); err == nil {
+			archives = append(archives, name)
+		}
+	}
+	sort.Strings(archives)
+	return archives, nil
+}
+
+// Write writes a zstd-compressed tar archive of the tree at root to w and
+// returns the number of files in it. Paths matched by the root's
+// .notaignore, and the absolute paths in exclude, are left out.
+func Write(root string, w io.Writer, exclude ...string) (int, error) {
+	ignored, err := ignore.Load(root)
+	if err != nil {
+		return 0, err
+	}
+	excluded := make(map[string]bool)
+	for _, p := range exclude {
+		excluded[filepath.Clean(p)] = true
+	}
+
+	zw, err := zstd.NewWriter(w)
+	if err != nil {
+		return 0, err
+	}
+	tw := tar.NewWriter(zw)
+
+	files := 0
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if excluded[p] || ignored.Match(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		case !info.Mode().IsRegular() && !info.IsDir():
+			// Sockets, pipes and devices have no place in a backup
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := io.Copy(tw, f); err != nil {
+			return err
+		}
+		files++
+		return nil
+	})
+	if err != nil {
+		zw.Close()
+		return 0, err
+	}
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+	return files, nil
+}
+
+// Restore extracts the archive read from r into dir and returns the number
+// of files restored. dir must be missing or empty unless overwrite is set,
+// in which case files in the archive replace those already there.
+func Restore(r io.Reader, dir string, overwrite bool) (int, error) {
+	if !overwrite {
+		entries, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+		if len(entries) > 0 {
+			return 0, fmt.Errorf("%s: %w", dir, ErrNotEmpty)
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+
+	zr, err := zstd.NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	defer zr.Close()
+	tr := tar.NewReader(zr)
+
+	type symlink struct{ target, link string }
+	var symlinks []symlink
+	files := 0
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return files, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		name := filepath.FromSlash(strings.TrimSuffix(hdr.Name, "/"))
+		if !filepath.IsLocal(name) {
+			return files, fmt.Errorf("%s: %w", hdr.Name, ErrUnsafePath)
+		}
+		target := filepath.Join(dir, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, hdr.FileInfo().Mode().Perm()); err != nil {
+				return files, err
+			}
+		case tar.TypeReg:
+			if err := restoreFile(tr, target, hdr); err != nil {
+				return files, err
+			}
+			files++
+		case tar.TypeSymlink:
+			// Created last, so no file is written through a link
+			symlinks = append(symlinks, symlink{target, hdr.Linkname})
+		}
+	}
+
+	for _, s := range symlinks {
+		if overwrite {
+			os.Remove(s.target)
+		}
+		if err := os.MkdirAll(filepath.Dir(s.target), 0755); err != nil {
+			return files, err
+		}
+		if err := os.Symlink(s.link, s.target); err != nil {
+			return files, err
+		}
+	}
+	return files, nil
+}
+
+// restoreFile writes the file in hdr, keeping its mode and modification
+// time.
+func restoreFile(r io.Reader, target string, hdr *tar.Header) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	// Replace rather than write through whatever is in the way
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, hdr.FileInfo().Mode().Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+}
@@ -0,0 +1,173 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "notes")
+	writeFile(t, filepath.Join(root, ".nota", "vault.json"), `{"name": "notes"}`)
+	writeFile(t, filepath.Join(root, "Projects", "garden.md"), "# Garden\n")
+	writeFile(t, filepath.Join(root, "Archive", "old.md"), "# Old\n")
+	writeFile(t, filepath.Join(root, "Scratch", "tmp.md"), "scratch")
+	writeFile(t, filepath.Join(root, "Backups", "previous"+Ext), "archive")
+	writeFile(t, filepath.Join(root, ".notaignore"), "Scratch/\n")
+	os.MkdirAll(filepath.Join(root, "Inbox"), 0755)
+	os.Symlink("Projects/garden.md", filepath.Join(root, "garden.md"))
+	mtime := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	os.Chtimes(filepath.Join(root, "Projects", "garden.md"), mtime, mtime)
+
+	// A store inside the vault is left out of the archive
+	store := &DirStore{Dir: filepath.Join(root, "Backups")}
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	result, err := Backup(context.Background(), root, store, Options{Now: now})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.Archive != "notes-20261016T093000Z.tar.zst" {
+		t.Errorf("unexpected archive name: %s", result.Archive)
+	}
+	if result.Files != 4 {
+		t.Errorf("expected 4 files archived, got %d", result.Files)
+	}
+	if info, err := os.Stat(filepath.Join(store.Dir, result.Archive)); err != nil || info.Size() != result.Size {
+		t.Fatalf("expected the archive in the store, got: %v", err)
+	}
+
+	f, _ := os.Open(filepath.Join(store.Dir, result.Archive))
+	defer f.Close()
+	dir := filepath.Join(t.TempDir(), "restored")
+	files, err := Restore(f, dir, false)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if files != 4 {
+		t.Errorf("expected 4 files restored, got %d", files)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "Projects", "garden.md"))
+	if err != nil || !info.ModTime().Equal(mtime) {
+		t.Errorf("expected the note with its modification time, got: %v", err)
+	}
+	if link, _ := os.Readlink(filepath.Join(dir, "garden.md")); link != "Projects/garden.md" {
+		t.Errorf("expected the symlink restored, got %q", link)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "Inbox")); err != nil || !info.IsDir() {
+		t.Error("expected empty folders restored")
+	}
+	for _, p := range []string{"Scratch", "Backups"} {
+		if _, err := os.Stat(filepath.Join(dir, p)); err == nil {
+			t.Errorf("expected %s to be left out", p)
+		}
+	}
+
+	f.Seek(0, 0)
+	if _, err := Restore(f, dir, false); !errors.Is(err, ErrNotEmpty) {
+		t.Errorf("expected ErrNotEmpty, got: %v", err)
+	}
+	os.WriteFile(filepath.Join(dir, "Archive", "old.md"), []byte("changed"), 0644)
+	f.Seek(0, 0)
+	if _, err := Restore(f, dir, true); err != nil {
+		t.Fatalf("expected no error when overwriting, got: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "Archive", "old.md")); string(data) != "# Old\n" {
+		t.Errorf("expected the note to be overwritten, got %q", data)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	store := &DirStore{Dir: t.TempDir()}
+	for _, name := range []string{
+		"notes-20261014T090000Z.tar.zst",
+		"notes-20261016T090000Z.tar.zst",
+		"notes-20261015T090000Z.tar.zst",
+		"other-20261001T090000Z.tar.zst",
+		"notes-readme.txt",
+	} {
+		writeFile(t, filepath.Join(store.Dir, name), "archive")
+	}
+
+	removed, err := Rotate(context.Background(), store, "notes", 2)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != "notes-20261014T090000Z.tar.zst" {
+		t.Errorf("expected the oldest archive removed, got %v", removed)
+	}
+	names, _ := store.List(context.Background())
+	if len(names) != 4 {
+		t.Errorf("expected other files left alone, got %v", names)
+	}
+
+	if removed, _ := Rotate(context.Background(), store, "notes", 0); len(removed) != 0 {
+		t.Errorf("expected keep 0 to remove nothing, got %v", removed)
+	}
+}
+
+func TestRestore_UnsafePath(t *testing.T) {
+	var buf bytes.Buffer
+	zw, _ := zstd.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	tw.WriteHeader(&tar.Header{Name: "../escape.md", Mode: 0644, Size: 1, Typeflag: tar.TypeReg})
+	tw.Write([]byte("x"))
+	tw.Close()
+	zw.Close()
+
+	dir := t.TempDir()
+	if _, err := Restore(&buf, filepath.Join(dir, "restored"), false); !errors.Is(err, ErrUnsafePath) {
+		t.Errorf("expected ErrUnsafePath, got: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape.md")); err == nil {
+		t.Error("expected nothing written outside the restore directory")
+	}
+}
+
+func TestParseS3URI(t *testing.T) {
+	tests := []struct {
+		uri, bucket, prefix string
+		wantErr             bool
+	}{
+		{"s3://backups", "backups", "", false},
+		{"s3://backups/vault/", "backups", "vault/", false},
+		{"s3://backups/a/b", "backups", "a/b/", false},
+		{"https://backups/vault", "", "", true},
+		{"s3:///vault", "", "", true},
+	}
+	for _, tt := range tests {
+		bucket, prefix, err := ParseS3URI(tt.uri)
+		if (err != nil) != tt.wantErr || bucket != tt.bucket || prefix != tt.prefix {
+			t.Errorf("ParseS3URI(%q) = %q, %q, %v", tt.uri, bucket, prefix, err)
+		}
+	}
+}
+
+func TestTime(t *testing.T) {
+	got, err := Time("notes-20261016T093000Z.tar.zst", "notes")
+	if err != nil || !got.Equal(time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected time %v, err %v", got, err)
+	}
+	for _, name := range []string{"notes-latest.tar.zst", "other-20261016T093000Z.tar.zst", "notes-20261016T093000Z.tar"} {
+		if _, err := Time(name, "notes"); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("expected ErrInvalidName for %s, got: %v", name, err)
+		}
+	}
+}
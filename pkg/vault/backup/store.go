@@ -0,0 +1,200 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/dirs"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/s3"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+)
+
+// Store is where archives are kept, by name.
+type Store interface {
+	// List returns the names of the files in the store.
+	List(ctx context.Context) ([]string, error)
+	// Put stores the contents of r under name.
+	Put(ctx context.Context, name string, r io.ReadSeeker) error
+	// Get writes the contents of the named file to w.
+	Get(ctx context.Context, name string, w io.Writer) error
+	// Delete removes the named file.
+	Delete(ctx context.Context, name string) error
+}
+
+// NewStore returns the store cfg names: the S3 bucket when S3 is set, else
+// the directory To, else the backups folder in nota's state directory. S3
+// requests are signed with AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+func NewStore(cfg vault.BackupConfig) (Store, error) {
+	if cfg.S3 != "" {
+		bucket, prefix, err := ParseS3URI(cfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		client, err := s3.New(cfg.S3Endpoint, cfg.S3Region, os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"))
+		if err != nil {
+			return nil, err
+		}
+		return &S3Store{Client: client, Bucket: bucket, Prefix: prefix}, nil
+	}
+
+	to := cfg.To
+	if to == "" {
+		dir, err := dirs.State()
+		if err != nil {
+			return nil, err
+		}
+		to = filepath.Join(dir, "backups")
+	}
+	to, err := filepath.Abs(to)
+	if err != nil {
+		return nil, err
+	}
+	return &DirStore{Dir: to}, nil
+}
+
+// Keep returns the retention cfg sets, or DefaultKeep.
+func Keep(cfg vault.BackupConfig) int {
+	if cfg.Keep == 0 {
+		return DefaultKeep
+	}
+	return cfg.Keep
+}
+
+// DirStore keeps archives in a local directory.
+type DirStore struct {
+	Dir string
+}
+
+// String describes the store for messages, as its directory.
+func (s *DirStore) String() string {
+	return s.Dir
+}
+
+// List returns the names of the regular files in the directory; a missing
+// directory holds none.
+func (s *DirStore) List(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// Put writes the file through a temporary file, so a partly written archive
+// never carries an archive's name.
+func (s *DirStore) Put(ctx context.Context, name string, r io.ReadSeeker) error {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(s.Dir, "."+name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	_, err = io.Copy(f, r)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.Dir, name))
+}
+
+// Get copies the named file to w.
+func (s *DirStore) Get(ctx context.Context, name string, w io.Writer) error {
+	f, err := os.Open(filepath.Join(s.Dir, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// Delete removes the named file.
+func (s *DirStore) Delete(ctx context.Context, name string) error {
+	return os.Remove(filepath.Join(s.Dir, name))
+}
+
+// S3Store keeps archives in an S3 bucket, under Prefix.
+type S3Store struct {
+	Client *s3.Client
+	Bucket string
+	// Prefix is prepended to archive names to form object keys, e.g.
+	// "backups/".
+	Prefix string
+}
+
+// ParseS3URI splits an s3://bucket/prefix URI into its bucket and key
+// prefix. A non-empty prefix always ends in a slash.
+func ParseS3URI(uri string) (bucket, prefix string, err error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "s3" || u.Host == "" {
+		return "", "", fmt.Errorf("invalid S3 URI %q: want s3://bucket/prefix", uri)
+	}
+	prefix = strings.Trim(u.Path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return u.Host, prefix, nil
+}
+
+// List returns the names of the objects directly under Prefix.
+func (s *S3Store) List(ctx context.Context) ([]string, error) {
+	objects, err := s.Client.List(ctx, s.Bucket, s.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, o := range objects {
+		name := strings.TrimPrefix(o.Key, s.Prefix)
+		if name != "" && !strings.Contains(name, "/") {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// Put uploads the archive.
+func (s *S3Store) Put(ctx context.Context, name string, r io.ReadSeeker) error {
+	return s.Client.Put(ctx, s.Bucket, s.key(name), r)
+}
+
+// Get downloads the archive.
+func (s *S3Store) Get(ctx context.Context, name string, w io.Writer) error {
+	return s.Client.Get(ctx, s.Bucket, s.key(name), w)
+}
+
+// Delete removes the archive from the bucket.
+func (s *S3Store) Delete(ctx context.Context, name string) error {
+	return s.Client.Delete(ctx, s.Bucket, s.key(name))
+}
+
+// String describes the store for messages, as an s3:// URI.
+func (s *S3Store) String() string {
+	return "s3://" + path.Join(s.Bucket, s.Prefix)
+}
+
+func (s *S3Store) key(name string) string {
+	return s.Prefix + name
+}
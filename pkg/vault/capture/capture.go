@@ -0,0 +1,120 @@
+// Package capture writes quick notes, typed or pasted from the terminal,
+// into the vault's Inbox.
+package capture
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/frontmatter"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/notes"
+)
+
+// InboxFolder is the vault folder captured notes are written to.
+const InboxFolder = "Inbox"
+
+// Source is the source recorded in captured notes' frontmatter.
+const Source = "capture"
+
+var (
+	// ErrEmpty is returned when there is no text to capture.
+	ErrEmpty = errors.New("nothing to capture")
+	// ErrNoClipboard is returned when no clipboard tool is installed.
+	ErrNoClipboard = errors.New("no clipboard tool found (install wl-clipboard, xclip or xsel)")
+)
+
+// Options describes a captured note.
+type Options struct {
+	// Title, when set, names the note and heads its body.
+	Title string
+	// Time dates the note; zero means now.
+	Time time.Time
+	// Perms, when set, sets up the created directories and note.
+	Perms notes.Permissions
+}
+
+// Capture writes text as a new note in the Inbox of the vault at root and
+// returns its path. The note is named YYYY-MM-DD-HHmm-<title slug>, or
+// YYYY-MM-DD-HHmm-capture without a title, with a numbered suffix when the
+// name is taken, and its frontmatter records the title, creation time and
+// source.
+func Capture(root, text string, opts Options) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", ErrEmpty
+	}
+	ts := opts.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	name := ts.Format("2006-01-02-1504") + "-" + Source
+	if slug := notes.Slug(opts.Title); slug != "" {
+		name = ts.Format("2006-01-02-1504") + "-" + slug
+	}
+
+	var fm strings.Builder
+	if opts.Title != "" {
+		fmt.Fprintf(&fm, "title: %s\n", frontmatter.Quote(opts.Title))
+	}
+	fmt.Fprintf(&fm, "created: %s\n", ts.Format(time.RFC3339))
+	fmt.Fprintf(&fm, "source: %s\n", Source)
+
+	body := text
+	if opts.Title != "" {
+		body = "# " + opts.Title + "\n\n" + text
+	}
+
+	return notes.CreateNote(notes.Options{
+		Dir:         filepath.Join(root, InboxFolder),
+		Name:        name,
+		Frontmatter: fm.String(),
+		Body:        body,
+		Perms:       opts.Perms,
+	})
+}
+
+// clipboardCommands are the commands that print the clipboard, in the
+// order they are tried, by operating system.
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbpaste"}},
+	"windows": {{"powershell", "-NoProfile", "-Command", "Get-Clipboard"}},
+	"linux": {
+		{"wl-paste", "--no-newline"},
+		{"xclip", "-selection", "clipboard", "-o"},
+		{"xsel", "--clipboard", "--output"},
+	},
+}
+
+// ReadClipboard returns the text on the system clipboard, read with the
+// first clipboard tool installed.
+func ReadClipboard(ctx context.Context) (string, error) {
+	commands, ok := clipboardCommands[runtime.GOOS]
+	if !ok {
+		commands = clipboardCommands["linux"]
+	}
+	for _, command := range commands {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+				return "", fmt.Errorf("%s: %w: %s", command[0], err, msg)
+			}
+			return "", fmt.Errorf("%s: %w", command[0], err)
+		}
+		return string(out), nil
+	}
+	return "", ErrNoClipboard
+}
@@ -0,0 +1,52 @@
+package capture
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCapture(t *testing.T) {
+	root := t.TempDir()
+	ts := time.Date(2026, 1, 22, 14, 30, 0, 0, time.UTC)
+
+	path, err := Capture(root, "  Call the plumber about the leak.\n", Options{Title: "Plumber", Time: ts})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if path != filepath.Join(root, InboxFolder, "2026-01-22-1430-plumber.md") {
+		t.Errorf("unexpected path %s", path)
+	}
+	content, _ := os.ReadFile(path)
+	expected := "---\ntitle: \"Plumber\"\ncreated: 2026-01-22T14:30:00Z\nsource: capture\n---\n\n# Plumber\n\nCall the plumber about the leak.\n"
+	if string(content) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, content)
+	}
+
+	// A taken name gets a numbered suffix
+	path, err = Capture(root, "Also the gutter.", Options{Title: "Plumber", Time: ts})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if filepath.Base(path) != "2026-01-22-1430-plumber-2.md" {
+		t.Errorf("expected a numbered name, got %s", path)
+	}
+
+	// Without a title the note is named after its source
+	path, err = Capture(root, "Buy milk.", Options{Time: ts})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	content, _ = os.ReadFile(path)
+	if filepath.Base(path) != "2026-01-22-1430-capture.md" || string(content) != "---\ncreated: 2026-01-22T14:30:00Z\nsource: capture\n---\n\nBuy milk.\n" {
+		t.Errorf("unexpected untitled note %s:\n%s", path, content)
+	}
+}
+
+func TestCapture_Empty(t *testing.T) {
+	if _, err := Capture(t.TempDir(), " \n\t", Options{}); !errors.Is(err, ErrEmpty) {
+		t.Errorf("expected ErrEmpty, got: %v", err)
+	}
+}
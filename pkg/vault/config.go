@@ -0,0 +1,89 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConfigFile is the vault-wide settings file within the marker directory.
+const ConfigFile = "config.json"
+
+// DefaultIdentityFile is where the age identities that decrypt notes are
+// read from when the config does not say. It is outside the vault so the
+// keys never sync alongside the notes they unlock.
+const DefaultIdentityFile = "~/.nota/age/keys.txt"
+
+// Config holds vault-wide settings shared by nota's commands.
+type Config struct {
+	Encryption EncryptionConfig `json:"encryption"`
+	Backup     BackupConfig     `json:"backup"`
+}
+
+// EncryptionConfig configures encrypting notes at rest with age.
+type EncryptionConfig struct {
+	// Recipients are the age public keys (age1...) notes are encrypted to.
+	Recipients []string `json:"recipients"`
+	// IdentityFile is the file holding the age identities (AGE-SECRET-KEY-1...)
+	// that decrypt notes. Defaults to DefaultIdentityFile.
+	IdentityFile string `json:"identity_file,omitempty"`
+}
+
+// BackupConfig configures nota backup. Flags override each setting.
+type BackupConfig struct {
+	// To is the directory backups are written to.
+	To string `json:"to,omitempty"`
+	// S3 is an s3://bucket/prefix URI backups are uploaded to instead.
+	S3 string `json:"s3,omitempty"`
+	// S3Endpoint and S3Region locate the S3 service; the endpoint defaults
+	// to AWS for the region.
+	S3Endpoint string `json:"s3_endpoint,omitempty"`
+	S3Region   string `json:"s3_region,omitempty"`
+	// Keep is how many backups are kept; unset keeps the default number.
+	Keep int `json:"keep,omitempty"`
+}
+
+// ConfigPath returns the path of the vault config in the vault at root.
+func ConfigPath(root string) string {
+	return filepath.Join(root, VaultMarkerDir, ConfigFile)
+}
+
+// LoadConfig reads the vault config of the vault at root. A missing file
+// gives the defaults.
+func LoadConfig(root string) (*Config, error) {
+	cfg := &Config{}
+	data, err := os.ReadFile(ConfigPath(root))
+	if err != nil {
+		if os.IsNotExist(err) {
+			cfg.ApplyDefaults()
+			return cfg, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ConfigPath(root), err)
+	}
+	cfg.ApplyDefaults()
+	return cfg, nil
+}
+
+// ApplyDefaults fills in unset optional fields.
+func (c *Config) ApplyDefaults() {
+	if c.Encryption.IdentityFile == "" {
+		c.Encryption.IdentityFile = DefaultIdentityFile
+	}
+	c.Encryption.IdentityFile = expandHome(c.Encryption.IdentityFile)
+	c.Backup.To = expandHome(c.Backup.To)
+}
+
+// expandHome expands a leading ~/ in path to the home directory.
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
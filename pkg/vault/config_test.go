@@ -0,0 +1,41 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	home, _ := os.UserHomeDir()
+
+	cfg, err := LoadConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("expected no error for a missing config, got: %v", err)
+	}
+	if expected := filepath.Join(home, ".nota/age/keys.txt"); cfg.Encryption.IdentityFile != expected {
+		t.Errorf("expected the default identity file %s, got %s", expected, cfg.Encryption.IdentityFile)
+	}
+
+	os.MkdirAll(filepath.Join(tmpDir, VaultMarkerDir), 0755)
+	data := `{"encryption": {"recipients": ["age1example"], "identity_file": "/keys/nota.txt"}, "backup": {"to": "~/Backups", "keep": 3}}`
+	if err := os.WriteFile(ConfigPath(tmpDir), []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err = LoadConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(cfg.Encryption.Recipients) != 1 || cfg.Encryption.IdentityFile != "/keys/nota.txt" {
+		t.Errorf("expected the configured encryption settings, got %+v", cfg.Encryption)
+	}
+	if cfg.Backup.To != filepath.Join(home, "Backups") || cfg.Backup.Keep != 3 {
+		t.Errorf("expected the configured backup settings, got %+v", cfg.Backup)
+	}
+
+	os.WriteFile(ConfigPath(tmpDir), []byte("{"), 0644)
+	if _, err := LoadConfig(tmpDir); err == nil {
+		t.Error("expected a parse error")
+	}
+}
@@ -0,0 +1,189 @@
+// Package conflicts finds the conflict copies Syncthing leaves when a file
+// changed on two devices at once, such as
+// garden.sync-conflict-20261016-093000-ABCDEFG.md next to garden.md, and
+// resolves them by keeping either version or merging the two.
+package conflicts
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/ignore"
+)
+
+// Choice is how a conflict is resolved.
+type Choice string
+
+const (
+	// KeepOriginal keeps the original file and deletes the conflict copy.
+	KeepOriginal Choice = "original"
+	// KeepConflict replaces the original file with the conflict copy.
+	KeepConflict Choice = "conflict"
+	// KeepBoth merges the conflict copy into the original note, keeping the
+	// lines of both, and deletes the copy.
+	KeepBoth Choice = "both"
+)
+
+// Valid reports whether c is a known choice.
+func (c Choice) Valid() bool {
+	switch c {
+	case KeepOriginal, KeepConflict, KeepBoth:
+		return true
+	}
+	return false
+}
+
+// Errors returned by the package.
+var (
+	ErrInvalidChoice = errors.New("invalid choice: must be original, conflict or both")
+	ErrNotConflict   = errors.New("not a sync conflict file")
+	ErrNotNote       = errors.New("only notes can be merged")
+)
+
+// noteExt is the extension of the files that can be merged.
+const noteExt = ".md"
+
+// conflictName matches Syncthing's conflict file names:
+// <name>.sync-conflict-<date>-<time>-<device id>[.<ext>].
+var conflictName = regexp.MustCompile(`^(.*)\.sync-conflict-(\d{8}-\d{6})-([A-Z0-9]{7})(\.[^.]*)?$`)
+
+// Conflict is a conflict copy of a file.
+type Conflict struct {
+	// Path is the conflict copy's path relative to the vault root.
+	Path string `json:"path"`
+	// Original is the path of the file it conflicts with.
+	Original string `json:"original"`
+	// Device is the short ID of the device whose change lost.
+	Device string `json:"device"`
+	// Time is when the conflict was detected, in local time.
+	Time time.Time `json:"time"`
+}
+
+// IsNote reports whether the conflicting file is a note, which can be
+// merged.
+func (c Conflict) IsNote() bool {
+	return strings.EqualFold(filepath.Ext(c.Original), noteExt)
+}
+
+// Parse returns the conflict the file at the vault-relative path rel is, or
+// ErrNotConflict.
+func Parse(rel string) (Conflict, error) {
+	m := conflictName.FindStringSubmatch(filepath.Base(rel))
+	if m == nil {
+		return Conflict{}, fmt.Errorf("%s: %w", rel, ErrNotConflict)
+	}
+	t, err := time.ParseInLocation("20060102-150405", m[2], time.Local)
+	if err != nil {
+		return Conflict{}, fmt.Errorf("%s: %w", rel, ErrNotConflict)
+	}
+	return Conflict{
+		Path:     rel,
+		Original: filepath.Join(filepath.Dir(rel), m[1]+m[4]),
+		Device:   m[3],
+		Time:     t,
+	}, nil
+}
+
+// Find returns the conflicts in the vault at root, sorted by path. Hidden
+// folders, such as Syncthing's .stversions, and paths listed in .notaignore
+// are skipped.
+func Find(root string) ([]Conflict, error) {
+	ignored, err := ignore.Load(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Conflict
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") || ignored.Match(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || ignored.Match(rel, false) {
+			return nil
+		}
+		if c, err := Parse(rel); err == nil {
+			found = append(found, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Path < found[j].Path })
+	return found, nil
+}
+
+// Diff returns a unified diff from the original note to its conflict copy.
+// A missing original diffs as empty.
+func (c Conflict) Diff(root string) (string, error) {
+	original, copy, err := c.read(root)
+	if err != nil {
+		return "", err
+	}
+	return Diff(c.Original, c.Path, original, copy), nil
+}
+
+// Resolve resolves the conflict in the vault at root with choice.
+func Resolve(root string, c Conflict, choice Choice) error {
+	conflictPath := filepath.Join(root, c.Path)
+	originalPath := filepath.Join(root, c.Original)
+
+	switch choice {
+	case KeepOriginal:
+		return os.Remove(conflictPath)
+	case KeepConflict:
+		return os.Rename(conflictPath, originalPath)
+	case KeepBoth:
+		if !c.IsNote() {
+			return fmt.Errorf("%s: %w", c.Path, ErrNotNote)
+		}
+		original, copy, err := c.read(root)
+		if err != nil {
+			return err
+		}
+		perm := os.FileMode(0644)
+		if info, err := os.Stat(originalPath); err == nil {
+			perm = info.Mode().Perm()
+		}
+		if err := os.WriteFile(originalPath, []byte(Merge(original, copy)), perm); err != nil {
+			return fmt.Errorf("failed to write %s: %w", c.Original, err)
+		}
+		return os.Remove(conflictPath)
+	}
+	return ErrInvalidChoice
+}
+
+// read returns the contents of the original and the conflict copy.
+func (c Conflict) read(root string) (original, copy string, err error) {
+	data, err := os.ReadFile(filepath.Join(root, c.Original))
+	if err != nil && !os.IsNotExist(err) {
+		return "", "", err
+	}
+	original = string(data)
+	data, err = os.ReadFile(filepath.Join(root, c.Path))
+	if err != nil {
+		return "", "", err
+	}
+	return original, string(data), nil
+}
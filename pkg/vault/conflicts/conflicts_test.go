@@ -0,0 +1,156 @@
+package conflicts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	c, err := Parse(filepath.Join("Projects", "garden.sync-conflict-20261016-093000-ABCDEFG.md"))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if c.Original != filepath.Join("Projects", "garden.md") || c.Device != "ABCDEFG" {
+		t.Errorf("unexpected conflict: %+v", c)
+	}
+	if !c.Time.Equal(time.Date(2026, 10, 16, 9, 30, 0, 0, time.Local)) {
+		t.Errorf("unexpected time: %v", c.Time)
+	}
+
+	c, err = Parse("Makefile.sync-conflict-20261016-093000-ABCDEFG")
+	if err != nil || c.Original != "Makefile" || c.IsNote() {
+		t.Errorf("expected a conflict without extension, got %+v, %v", c, err)
+	}
+
+	for _, name := range []string{"garden.md", "garden.sync-conflict-2026-ABCDEFG.md", "garden.sync-conflict-20261016-093000-abc.md"} {
+		if _, err := Parse(name); !errors.Is(err, ErrNotConflict) {
+			t.Errorf("expected ErrNotConflict for %s, got: %v", name, err)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	files := []string{
+		"Projects/garden.md",
+		"Projects/garden.sync-conflict-20261016-093000-ABCDEFG.md",
+		"Inbox/memo.sync-conflict-20261015-120000-HIJKLMN.md",
+		".stversions/Projects/garden.sync-conflict-20261001-093000-ABCDEFG.md",
+		"Scratch/old.sync-conflict-20261001-093000-ABCDEFG.md",
+	}
+	for _, f := range files {
+		p := filepath.Join(root, filepath.FromSlash(f))
+		os.MkdirAll(filepath.Dir(p), 0755)
+		os.WriteFile(p, []byte("# Note\n"), 0644)
+	}
+	os.WriteFile(filepath.Join(root, ".notaignore"), []byte("Scratch/\n"), 0644)
+
+	found, err := Find(root)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("expected 2 conflicts, got %+v", found)
+	}
+	if found[0].Path != filepath.Join("Inbox", "memo.sync-conflict-20261015-120000-HIJKLMN.md") ||
+		found[1].Original != filepath.Join("Projects", "garden.md") {
+		t.Errorf("unexpected conflicts: %+v", found)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	setup := func(t *testing.T) (string, Conflict) {
+		root := t.TempDir()
+		os.WriteFile(filepath.Join(root, "garden.md"), []byte("# Garden\n\n- tomatoes\n- basil\n"), 0600)
+		os.WriteFile(filepath.Join(root, "garden.sync-conflict-20261016-093000-ABCDEFG.md"), []byte("# Garden\n\n- tomatoes\n- chillies\n"), 0644)
+		c, _ := Parse("garden.sync-conflict-20261016-093000-ABCDEFG.md")
+		return root, c
+	}
+
+	tests := []struct {
+		choice   Choice
+		expected string
+	}{
+		{KeepOriginal, "# Garden\n\n- tomatoes\n- basil\n"},
+		{KeepConflict, "# Garden\n\n- tomatoes\n- chillies\n"},
+		{KeepBoth, "# Garden\n\n- tomatoes\n- basil\n- chillies\n"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.choice), func(t *testing.T) {
+			root, c := setup(t)
+			if err := Resolve(root, c, tt.choice); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			data, _ := os.ReadFile(filepath.Join(root, "garden.md"))
+			if string(data) != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, data)
+			}
+			if _, err := os.Stat(filepath.Join(root, c.Path)); !os.IsNotExist(err) {
+				t.Error("expected the conflict copy to be gone")
+			}
+		})
+	}
+
+	root, c := setup(t)
+	if err := Resolve(root, c, "newest"); !errors.Is(err, ErrInvalidChoice) {
+		t.Errorf("expected ErrInvalidChoice, got: %v", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\ntwo\nthree\nfour\nFIVE\nsix\nseven\neight\nnine\nten\neleven\n"
+	expected := `--- a.md
++++ b.md
+@@ -2,9 +2,10 @@
+ two
+ three
+ four
+-five
++FIVE
+ six
+ seven
+ eight
+ nine
+ ten
++eleven
+`
+	if got := Diff("a.md", "b.md", a, b); got != expected {
+		t.Errorf("unexpected diff:\n%s", got)
+	}
+
+	a = "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b = "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n"
+	expected = `--- a.md
++++ b.md
+@@ -1,3 +1,4 @@
++0
+ 1
+ 2
+ 3
+@@ -9,4 +10,3 @@
+ 9
+ 10
+ 11
+-12
+`
+	if got := Diff("a.md", "b.md", a, b); got != expected {
+		t.Errorf("unexpected diff:\n%s", got)
+	}
+
+	if got := Diff("a.md", "b.md", a, a); got != "" {
+		t.Errorf("expected no diff for equal texts, got:\n%s", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := "# Plan\n\n- call the bank\n- book flights\n\nNotes from Monday.\n"
+	b := "# Plan\n\n- call the bank\n- renew passport\n\nNotes from Monday.\nAnd Tuesday.\n"
+	expected := "# Plan\n\n- call the bank\n- book flights\n- renew passport\n\nNotes from Monday.\nAnd Tuesday.\n"
+	if got := Merge(a, b); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,199 @@
+package conflicts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is how many unchanged lines surround each hunk of a diff.
+const contextLines = 3
+
+// maxDiffCells bounds the table the line diff is computed with. Past it the
+// differing middle of the files is treated as replaced wholesale.
+const maxDiffCells = 4 << 20
+
+type op int
+
+const (
+	opEqual op = iota
+	opDelete
+	opInsert
+)
+
+// edit is one line of a diff.
+type edit struct {
+	op   op
+	line string
+}
+
+// splitLines splits text into lines, each without its newline.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines returns the edits turning a into b, by longest common
+// subsequence after trimming the common prefix and suffix.
+func diffLines(a, b []string) []edit {
+	var prefix, suffix []edit
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		prefix = append(prefix, edit{opEqual, a[0]})
+		a, b = a[1:], b[1:]
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		suffix = append([]edit{{opEqual, a[len(a)-1]}}, suffix...)
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+
+	edits := prefix
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, line := range a {
+			edits = append(edits, edit{opDelete, line})
+		}
+		for _, line := range b {
+			edits = append(edits, edit{opInsert, line})
+		}
+		return append(edits, suffix...)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{opEqual, a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{opDelete, a[i]})
+			i++
+		default:
+			edits = append(edits, edit{opInsert, b[j]})
+			j++
+		}
+	}
+	return append(edits, suffix...)
+}
+
+// Diff returns a unified diff from a, named aName, to b, named bName. It is
+// empty when the texts have the same lines.
+func Diff(aName, bName, a, b string) string {
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	var sb strings.Builder
+	// aLine and bLine are the 1-based line numbers at edits[k]
+	aLine, bLine := 1, 1
+	for k := 0; k < len(edits); {
+		if edits[k].op == opEqual {
+			aLine++
+			bLine++
+			k++
+			continue
+		}
+
+		// A hunk starts contextLines before the change and runs until
+		// more than twice contextLines unchanged lines follow a change
+		start := max(k-contextLines, 0)
+		end := k
+		for end < len(edits) {
+			if edits[end].op != opEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].op == opEqual {
+				run++
+			}
+			if run == len(edits) || run-end > 2*contextLines {
+				end = min(end+contextLines, len(edits))
+				break
+			}
+			end = run
+		}
+
+		aStart, bStart := aLine-(k-start), bLine-(k-start)
+		var aCount, bCount int
+		var body strings.Builder
+		for _, e := range edits[start:end] {
+			switch e.op {
+			case opEqual:
+				body.WriteString(" " + e.line + "\n")
+				aCount++
+				bCount++
+			case opDelete:
+				body.WriteString("-" + e.line + "\n")
+				aCount++
+			case opInsert:
+				body.WriteString("+" + e.line + "\n")
+				bCount++
+			}
+		}
+
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", aName, bName)
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		sb.WriteString(body.String())
+
+		aLine, bLine = aStart+aCount, bStart+bCount
+		k = end
+	}
+	return sb.String()
+}
+
+// hunkRange formats a hunk's line range as unified diffs do.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// An empty range names the line before it
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// Merge combines two versions of a note line by line: lines both share are
+// kept once, and where they differ the lines of a come first, then those of
+// b. Nothing either version added is lost.
+func Merge(a, b string) string {
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	var sb strings.Builder
+	var inserted []string
+	for _, e := range edits {
+		switch e.op {
+		case opEqual:
+			for _, line := range inserted {
+				sb.WriteString(line + "\n")
+			}
+			inserted = inserted[:0]
+			sb.WriteString(e.line + "\n")
+		case opDelete:
+			sb.WriteString(e.line + "\n")
+		case opInsert:
+			inserted = append(inserted, e.line)
+		}
+	}
+	for _, line := range inserted {
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,201 @@
+// Package crypt encrypts notes at rest with age, so notes in sensitive
+// areas of a vault only ever sync as ciphertext. An encrypted note keeps its
+// name with .age appended (garden.md becomes garden.md.age) and is
+// ASCII-armored, so it stays a text file.
+package crypt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// Ext is appended to the file name of encrypted notes.
+const Ext = ".age"
+
+// Errors returned by the package.
+var (
+	ErrNoRecipients = errors.New("no age recipients configured")
+	ErrNoIdentities = errors.New("no age identities found")
+	ErrExists       = errors.New("file already exists")
+	ErrEncrypted    = errors.New("note is already encrypted")
+	ErrNotEncrypted = errors.New("note is not encrypted")
+)
+
+// ParseRecipients parses age public keys (age1...).
+func ParseRecipients(keys []string) ([]age.Recipient, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoRecipients
+	}
+	recipients, err := age.ParseRecipients(strings.NewReader(strings.Join(keys, "\n")))
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient: %w", err)
+	}
+	return recipients, nil
+}
+
+// LoadIdentities reads the age identities (AGE-SECRET-KEY-1...) in the
+// file at path, one per line, as written by age-keygen.
+func LoadIdentities(path string) ([]age.Identity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s: %w", path, ErrNoIdentities)
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read identities from %s: %w", path, err)
+	}
+	return identities, nil
+}
+
+// IsEncrypted reports whether the file at path is an encrypted note, by its
+// name.
+func IsEncrypted(path string) bool {
+	return strings.HasSuffix(path, Ext)
+}
+
+// Encrypt returns data encrypted to recipients, ASCII-armored.
+func Encrypt(data []byte, recipients ...age.Recipient) ([]byte, error) {
+	var buf bytes.Buffer
+	aw := armor.NewWriter(&buf)
+	w, err := age.Encrypt(aw, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := aw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decrypt returns the plaintext of data, armored or binary age, using the
+// first identity that matches.
+func Decrypt(data []byte, identities ...age.Identity) ([]byte, error) {
+	if len(identities) == 0 {
+		return nil, ErrNoIdentities
+	}
+	var src io.Reader = bytes.NewReader(data)
+	if trimmed := bytes.TrimLeft(data, " \t\r\n"); bytes.HasPrefix(trimmed, []byte(armor.Header)) {
+		src = armor.NewReader(bytes.NewReader(trimmed))
+	}
+	r, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// ReadNote returns the content of the note at path, decrypting it when it
+// is encrypted, so tools can read encrypted and plain notes alike.
+func ReadNote(path string, identities ...age.Identity) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !IsEncrypted(path) {
+		return data, nil
+	}
+	plain, err := Decrypt(data, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", path, err)
+	}
+	return plain, nil
+}
+
+// EncryptFile encrypts the note at path to recipients, writing path plus
+// Ext and then removing the plaintext. It returns the encrypted note's
+// path.
+func EncryptFile(path string, recipients ...age.Recipient) (string, error) {
+	if IsEncrypted(path) {
+		return "", fmt.Errorf("%s: %w", path, ErrEncrypted)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := Encrypt(data, recipients...)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt %s: %w", path, err)
+	}
+	target := path + Ext
+	if err := replace(path, target, ciphertext); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// DecryptFile decrypts the encrypted note at path, writing it without Ext
+// and then removing the encrypted note. It returns the plain note's path.
+func DecryptFile(path string, identities ...age.Identity) (string, error) {
+	if !IsEncrypted(path) {
+		return "", fmt.Errorf("%s: %w", path, ErrNotEncrypted)
+	}
+	plain, err := ReadNote(path, identities...)
+	if err != nil {
+		return "", err
+	}
+	target := strings.TrimSuffix(path, Ext)
+	if err := replace(path, target, plain); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// replace writes data to target, which must not exist, keeping the mode of
+// the file at src, and then removes src.
+func replace(src, target string, data []byte) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(target); err == nil {
+		return fmt.Errorf("%s: %w", target, ErrExists)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(info.Mode().Perm())
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	// Linking fails rather than overwrite a target created meanwhile
+	if err := os.Link(tmp, target); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%s: %w", target, ErrExists)
+		}
+		return err
+	}
+	return os.Remove(src)
+}
@@ -0,0 +1,114 @@
+package crypt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func newIdentity(t *testing.T) *age.X25519Identity {
+	t.Helper()
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("failed to generate identity: %v", err)
+	}
+	return identity
+}
+
+func TestEncryptFile_RoundTrip(t *testing.T) {
+	identity := newIdentity(t)
+	path := filepath.Join(t.TempDir(), "results.md")
+	if err := os.WriteFile(path, []byte("# Blood test\n"), 0600); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+
+	encrypted, err := EncryptFile(path, identity.Recipient())
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if encrypted != path+Ext {
+		t.Errorf("expected %s, got %s", path+Ext, encrypted)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected the plaintext to be removed")
+	}
+	data, err := os.ReadFile(encrypted)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "-----BEGIN AGE ENCRYPTED FILE-----") || strings.Contains(string(data), "Blood") {
+		t.Errorf("expected armored ciphertext, got:\n%s", data)
+	}
+	if info, _ := os.Stat(encrypted); info.Mode().Perm() != 0600 {
+		t.Errorf("expected the note's mode to be kept, got %o", info.Mode().Perm())
+	}
+
+	plain, err := ReadNote(encrypted, identity)
+	if err != nil || string(plain) != "# Blood test\n" {
+		t.Errorf("expected to read the plaintext, got %q (%v)", plain, err)
+	}
+	if _, err := ReadNote(encrypted, newIdentity(t)); err == nil {
+		t.Error("expected another identity not to decrypt the note")
+	}
+
+	decrypted, err := DecryptFile(encrypted, identity)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if data, _ := os.ReadFile(decrypted); decrypted != path || string(data) != "# Blood test\n" {
+		t.Errorf("expected the note back at %s, got %s: %q", path, decrypted, data)
+	}
+}
+
+func TestEncryptFile_Errors(t *testing.T) {
+	identity := newIdentity(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.md")
+	os.WriteFile(path, []byte("# Results\n"), 0644)
+	os.WriteFile(path+Ext, []byte("old"), 0644)
+
+	if _, err := EncryptFile(path, identity.Recipient()); !errors.Is(err, ErrExists) {
+		t.Errorf("expected ErrExists, got: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Error("expected the plaintext to be kept when the encrypted note exists")
+	}
+	if _, err := EncryptFile(path+Ext, identity.Recipient()); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("expected ErrEncrypted, got: %v", err)
+	}
+	if _, err := DecryptFile(path, identity); !errors.Is(err, ErrNotEncrypted) {
+		t.Errorf("expected ErrNotEncrypted, got: %v", err)
+	}
+}
+
+func TestParseRecipients(t *testing.T) {
+	identity := newIdentity(t)
+	if _, err := ParseRecipients(nil); !errors.Is(err, ErrNoRecipients) {
+		t.Errorf("expected ErrNoRecipients, got: %v", err)
+	}
+	if _, err := ParseRecipients([]string{"not-a-key"}); err == nil {
+		t.Error("expected an invalid recipient error")
+	}
+	recipients, err := ParseRecipients([]string{identity.Recipient().String()})
+	if err != nil || len(recipients) != 1 {
+		t.Errorf("expected one recipient, got %d (%v)", len(recipients), err)
+	}
+}
+
+func TestLoadIdentities(t *testing.T) {
+	identity := newIdentity(t)
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	os.WriteFile(path, []byte("# created: 2026-01-01\n"+identity.String()+"\n"), 0600)
+
+	identities, err := LoadIdentities(path)
+	if err != nil || len(identities) != 1 {
+		t.Fatalf("expected one identity, got %d (%v)", len(identities), err)
+	}
+	if _, err := LoadIdentities(filepath.Join(t.TempDir(), "missing.txt")); !errors.Is(err, ErrNoIdentities) {
+		t.Errorf("expected ErrNoIdentities, got: %v", err)
+	}
+}
@@ -0,0 +1,477 @@
+// Package export renders notes to standalone HTML pages, or to PDF through
+// an external converter, for sharing outside the vault. Links between
+// exported notes point at each other's pages, and the attachments notes
+// embed or link to are copied alongside them.
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/frontmatter"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/ignore"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/index"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/links"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+)
+
+// Format is an export format.
+type Format string
+
+// Export formats.
+const (
+	FormatHTML Format = "html"
+	FormatPDF  Format = "pdf"
+)
+
+// Valid reports whether f is a supported format.
+func (f Format) Valid() bool {
+	return f == FormatHTML || f == FormatPDF
+}
+
+// DefaultPDFCommand converts an HTML page to PDF. {input} and {output} are
+// replaced with the page's and the PDF's paths.
+const DefaultPDFCommand = "wkhtmltopdf --enable-local-file-access {input} {output}"
+
+// Errors returned by Export.
+var (
+	ErrNoNotes       = errors.New("no notes selected")
+	ErrInvalidFormat = errors.New("invalid export format: must be html or pdf")
+)
+
+// Options configures an export.
+type Options struct {
+	// Paths selects the notes to export, relative to the vault root with
+	// forward slashes. A folder selects every note below it; "" or "."
+	// selects the whole vault.
+	Paths []string
+	// OutDir is the directory the export is written to. Notes keep their
+	// paths within the vault, e.g. Projects/garden.md becomes
+	// OutDir/Projects/garden.html.
+	OutDir string
+	// Format is the output format; defaults to FormatHTML.
+	Format Format
+	// PDFCommand converts each page to PDF when Format is FormatPDF;
+	// defaults to DefaultPDFCommand. It is split on spaces.
+	PDFCommand string
+}
+
+// Result lists what an export wrote, relative to the output directory.
+type Result struct {
+	Notes       []string `json:"notes"`
+	Attachments []string `json:"attachments"`
+}
+
+// Export renders the selected notes of the index to opts.OutDir.
+func Export(ctx context.Context, ix *index.Index, opts Options) (*Result, error) {
+	if opts.Format == "" {
+		opts.Format = FormatHTML
+	}
+	if !opts.Format.Valid() {
+		return nil, ErrInvalidFormat
+	}
+	if opts.PDFCommand == "" {
+		opts.PDFCommand = DefaultPDFCommand
+	}
+
+	selected := Select(ix, opts.Paths)
+	if len(selected) == 0 {
+		return nil, ErrNoNotes
+	}
+
+	// PDFs are converted from pages staged in a temporary directory, which
+	// also holds the attachments they show
+	pageDir := opts.OutDir
+	if opts.Format == FormatPDF {
+		staging, err := os.MkdirTemp("", "nota-export-*")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(staging)
+		pageDir = staging
+	}
+
+	e := &exporter{
+		ix:          ix,
+		resolver:    links.NewResolver(ix),
+		selected:    make(map[string]bool, len(selected)),
+		ext:         "." + string(opts.Format),
+		pageDir:     pageDir,
+		attachments: make(map[string]bool),
+	}
+	for _, p := range selected {
+		e.selected[p] = true
+	}
+
+	result := &Result{Notes: []string{}, Attachments: []string{}}
+	for _, p := range selected {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, err := e.render(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", p, err)
+		}
+		out := e.outputPath(p)
+		pagePath := filepath.Join(pageDir, filepath.FromSlash(strings.TrimSuffix(out, e.ext)+".html"))
+		if err := writeFile(pagePath, page); err != nil {
+			return nil, err
+		}
+		if opts.Format == FormatPDF {
+			pdfPath := filepath.Join(opts.OutDir, filepath.FromSlash(out))
+			if err := convertPDF(ctx, opts.PDFCommand, pagePath, pdfPath); err != nil {
+				return nil, fmt.Errorf("failed to convert %s to PDF: %w", p, err)
+			}
+		}
+		result.Notes = append(result.Notes, out)
+	}
+
+	// Attachments are only shipped alongside HTML pages; PDFs embed them
+	if opts.Format == FormatHTML {
+		for p := range e.attachments {
+			result.Attachments = append(result.Attachments, p)
+		}
+		sort.Strings(result.Attachments)
+	}
+	return result, nil
+}
+
+// Select returns the paths of the indexed notes that paths select, sorted.
+func Select(ix *index.Index, paths []string) []string {
+	var selected []string
+	for _, n := range ix.Notes() {
+		for _, p := range paths {
+			p = strings.Trim(path.Clean(filepath.ToSlash(p)), "/")
+			if p == "." || p == "" || n.Path == p || strings.HasPrefix(n.Path, p+"/") {
+				selected = append(selected, n.Path)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// exporter holds the state of one export.
+type exporter struct {
+	ix       *index.Index
+	resolver *links.Resolver
+	selected map[string]bool
+	// ext is the extension of the exported notes, e.g. ".html".
+	ext     string
+	pageDir string
+	// attachments records the attachments copied, relative to the vault
+	// root.
+	attachments map[string]bool
+	// files maps lowercased file names in the vault to their paths, for
+	// finding attachments linked by name. Built on first use.
+	files map[string][]string
+}
+
+// markdown converts notes, with GitHub-flavoured tables, task lists and
+// strikethrough, and heading ids that links to headings can point at.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+)
+
+// page is the layout of an exported note.
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { max-width: 46em; margin: 2em auto; padding: 0 1em; font-family: sans-serif; line-height: 1.5; }
+img { max-width: 100%; }
+pre { overflow-x: auto; background: #f6f8fa; padding: 0.75em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d0d7de; padding: 0.25em 0.75em; }
+</style>
+</head>
+<body>
+{{.Body}}
+</body>
+</html>
+`))
+
+// render renders the note at p as an HTML page.
+func (e *exporter) render(p string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(e.ix.Root(), filepath.FromSlash(p)))
+	if err != nil {
+		return nil, err
+	}
+	content := string(data)
+	if _, body, ok := frontmatter.Split(content); ok {
+		content = body
+	}
+	content, err = e.rewriteLinks(p, content)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	if err := markdown.Convert([]byte(content), &body); err != nil {
+		return nil, err
+	}
+	note, _ := e.ix.Lookup(p)
+	var out bytes.Buffer
+	err = page.Execute(&out, struct {
+		Title string
+		Body  template.HTML
+	}{note.Title, template.HTML(body.String())})
+	return out.Bytes(), err
+}
+
+// rewriteLinks rewrites the links in the note at from for the export:
+// links to exported notes point at their pages, attachments are copied
+// and linked, and links to notes left out become plain text.
+func (e *exporter) rewriteLinks(from, content string) (string, error) {
+	found := links.Parse(content)
+	var sb strings.Builder
+	last := 0
+	for _, link := range found {
+		replacement, err := e.rewrite(from, link)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(content[last:link.Start])
+		sb.WriteString(replacement)
+		last = link.End
+	}
+	sb.WriteString(content[last:])
+	return sb.String(), nil
+}
+
+// rewrite returns the markdown a link is replaced with.
+func (e *exporter) rewrite(from string, link links.Link) (string, error) {
+	text := linkText(link)
+
+	if target, ok := e.resolver.Resolve(from, link); ok {
+		if !e.selected[target] {
+			return escape(text), nil
+		}
+		dest := relative(e.outputPath(from), e.outputPath(target))
+		if target == from && link.Heading != "" {
+			dest = ""
+		}
+		if link.Heading != "" {
+			dest += "#" + anchor(link.Heading)
+		}
+		return "[" + escape(text) + "](<" + dest + ">)", nil
+	}
+
+	attachment, ok := e.findAttachment(from, link.Target)
+	if !ok {
+		return escape(text), nil
+	}
+	if err := e.copyAttachment(attachment); err != nil {
+		return "", err
+	}
+	dest := relative(e.outputPath(from), attachment)
+	if link.Embed && isImage(attachment) {
+		return "![" + escape(link.Alias) + "](<" + dest + ">)", nil
+	}
+	return "[" + escape(text) + "](<" + dest + ">)", nil
+}
+
+// linkText returns the text a link is shown as.
+func linkText(link links.Link) string {
+	switch {
+	case link.Alias != "":
+		return link.Alias
+	case link.Target == "":
+		return link.Heading
+	case link.Heading != "":
+		return link.Target + " > " + link.Heading
+	default:
+		return path.Base(strings.TrimSuffix(link.Target, index.NoteExt))
+	}
+}
+
+// outputPath returns the exported path of the note or attachment at p,
+// relative to the output directory.
+func (e *exporter) outputPath(p string) string {
+	if strings.EqualFold(path.Ext(p), index.NoteExt) {
+		return strings.TrimSuffix(p, path.Ext(p)) + e.ext
+	}
+	return p
+}
+
+// findAttachment finds the file a link to a non-note points at: relative
+// to the linking note, then to the vault root, then by file name anywhere
+// in the vault.
+func (e *exporter) findAttachment(from, target string) (string, bool) {
+	if target == "" || strings.EqualFold(path.Ext(target), index.NoteExt) {
+		return "", false
+	}
+	for _, p := range []string{path.Join(path.Dir(from), target), strings.TrimPrefix(path.Clean("/"+target), "/")} {
+		if strings.HasPrefix(p, "../") {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(e.ix.Root(), filepath.FromSlash(p))); err == nil && info.Mode().IsRegular() {
+			return p, true
+		}
+	}
+
+	if e.files == nil {
+		e.files = e.listFiles()
+	}
+	candidates := e.files[strings.ToLower(path.Base(target))]
+	if len(candidates) == 0 {
+		return "", false
+	}
+	return candidates[0], true
+}
+
+// listFiles maps the lowercased names of the files in the vault to their
+// paths, shortest first, skipping hidden and ignored paths.
+func (e *exporter) listFiles() map[string][]string {
+	files := make(map[string][]string)
+	ignored, _ := ignore.Load(e.ix.Root())
+	filepath.WalkDir(e.ix.Root(), func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == e.ix.Root() {
+			return nil
+		}
+		rel, _ := filepath.Rel(e.ix.Root(), p)
+		if strings.HasPrefix(d.Name(), ".") || ignored.Match(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			name := strings.ToLower(d.Name())
+			files[name] = append(files[name], filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	for _, paths := range files {
+		sort.Slice(paths, func(i, j int) bool {
+			if len(paths[i]) != len(paths[j]) {
+				return len(paths[i]) < len(paths[j])
+			}
+			return paths[i] < paths[j]
+		})
+	}
+	return files
+}
+
+// copyAttachment copies the attachment at p, relative to the vault root,
+// next to the exported pages, once.
+func (e *exporter) copyAttachment(p string) error {
+	if e.attachments[p] {
+		return nil
+	}
+	src, err := os.Open(filepath.Join(e.ix.Root(), filepath.FromSlash(p)))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst := filepath.Join(e.pageDir, filepath.FromSlash(p))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	e.attachments[p] = true
+	return nil
+}
+
+// convertPDF runs the PDF command on one page.
+func convertPDF(ctx context.Context, command, input, output string) error {
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return errors.New("empty PDF command")
+	}
+	for i, arg := range args {
+		arg = strings.ReplaceAll(arg, "{input}", input)
+		args[i] = strings.ReplaceAll(arg, "{output}", output)
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %w: %s", args[0], err, msg)
+		}
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	return nil
+}
+
+// writeFile writes data to p, creating its directory.
+func writeFile(p string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0644)
+}
+
+// relative returns the path of to relative to the directory of from, both
+// slash-separated and relative to the same root.
+func relative(from, to string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(from)), filepath.FromSlash(to))
+	if err != nil {
+		return to
+	}
+	return filepath.ToSlash(rel)
+}
+
+// anchor returns the id the HTML renderer gives a heading: lowercase ASCII
+// letters and digits, with spaces, hyphens and underscores as hyphens.
+func anchor(heading string) string {
+	var sb strings.Builder
+	for _, c := range strings.TrimSpace(heading) {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			sb.WriteRune(c + 'a' - 'A')
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			sb.WriteRune(c)
+		case c == ' ' || c == '\t' || c == '-' || c == '_':
+			sb.WriteByte('-')
+		}
+	}
+	if sb.Len() == 0 {
+		return "heading"
+	}
+	return sb.String()
+}
+
+// escape escapes the characters that would end a link's text early.
+func escape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(text)
+}
+
+// isImage reports whether the file at p is an image browsers show inline.
+func isImage(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp", ".bmp", ".avif":
+		return true
+	}
+	return false
+}
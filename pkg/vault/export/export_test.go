@@ -0,0 +1,152 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/index"
+)
+
+func writeVaultFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	p := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
+
+// setupVault creates a vault with a project folder linking to notes inside
+// and outside it and embedding an image.
+func setupVault(t *testing.T) *index.Index {
+	t.Helper()
+	root := t.TempDir()
+	writeVaultFile(t, root, "Projects/Garden/plan.md", "---\ntitle: Garden plan\n---\n"+
+		"# Plan\n\nSee [[beds#North side|the beds]], [[Compost]] and [soil](../../Areas/soil.md).\n\n"+
+		"![[sketch.png]]\n\n[Budget](budget.pdf) and `[[not a link]]`\n")
+	writeVaultFile(t, root, "Projects/Garden/beds.md", "# Beds\n\n## North side\n\nBack to [[plan]].\n")
+	writeVaultFile(t, root, "Areas/Compost.md", "# Compost\n")
+	writeVaultFile(t, root, "Areas/soil.md", "# Soil\n")
+	writeVaultFile(t, root, "Attachments/sketch.png", "png")
+	writeVaultFile(t, root, "Projects/Garden/budget.pdf", "pdf")
+
+	ix, err := index.Build(root)
+	if err != nil {
+		t.Fatalf("failed to build index: %v", err)
+	}
+	return ix
+}
+
+func TestExport_HTML(t *testing.T) {
+	ix := setupVault(t)
+	outDir := t.TempDir()
+
+	result, err := Export(context.Background(), ix, Options{Paths: []string{"Projects/Garden", "Areas/soil.md"}, OutDir: outDir})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	expected := &Result{
+		Notes:       []string{"Areas/soil.html", "Projects/Garden/beds.html", "Projects/Garden/plan.html"},
+		Attachments: []string{"Attachments/sketch.png", "Projects/Garden/budget.pdf"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+	for _, p := range append(expected.Notes, expected.Attachments...) {
+		if _, err := os.Stat(filepath.Join(outDir, p)); err != nil {
+			t.Errorf("expected %s to be written: %v", p, err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, "Projects/Garden/plan.html"))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	page := string(data)
+	for _, want := range []string{
+		"<title>Garden plan</title>",
+		`<h1 id="plan">Plan</h1>`,
+		`<a href="beds.html#north-side">the beds</a>`,
+		`<a href="../../Areas/soil.html">soil</a>`,
+		`<img src="../../Attachments/sketch.png" alt="">`,
+		`<a href="budget.pdf">Budget</a>`,
+		"<code>[[not a link]]</code>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected page to contain %q, got:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, "Compost.html") || !strings.Contains(page, "Compost") {
+		t.Errorf("expected the link to a note left out to become text, got:\n%s", page)
+	}
+	if strings.Contains(page, "title: Garden plan") {
+		t.Errorf("expected frontmatter to be left out, got:\n%s", page)
+	}
+}
+
+func TestExport_PDF(t *testing.T) {
+	ix := setupVault(t)
+	outDir := t.TempDir()
+
+	result, err := Export(context.Background(), ix, Options{
+		Paths:      []string{"Areas"},
+		OutDir:     outDir,
+		Format:     FormatPDF,
+		PDFCommand: "cp {input} {output}",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	expected := []string{"Areas/Compost.pdf", "Areas/soil.pdf"}
+	if !reflect.DeepEqual(result.Notes, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Notes)
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, "Areas/soil.pdf"))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !strings.Contains(string(data), "<h1 id=\"soil\">Soil</h1>") {
+		t.Errorf("expected the converter to be given the page, got:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "Areas/soil.html")); err == nil {
+		t.Error("expected no HTML pages left in the output")
+	}
+
+	_, err = Export(context.Background(), ix, Options{Paths: []string{"Areas"}, OutDir: outDir, Format: FormatPDF, PDFCommand: "false {input}"})
+	if err == nil {
+		t.Error("expected a failing converter to fail the export")
+	}
+}
+
+func TestExport_Errors(t *testing.T) {
+	ix := setupVault(t)
+
+	if _, err := Export(context.Background(), ix, Options{Paths: []string{"Missing"}, OutDir: t.TempDir()}); !errors.Is(err, ErrNoNotes) {
+		t.Errorf("expected ErrNoNotes, got: %v", err)
+	}
+	if _, err := Export(context.Background(), ix, Options{Paths: []string{"."}, OutDir: t.TempDir(), Format: "docx"}); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat, got: %v", err)
+	}
+}
+
+func TestAnchor(t *testing.T) {
+	tests := map[string]string{
+		"North side":       "north-side",
+		" Step 2: Plant! ": "step-2-plant",
+		"snake_case-name":  "snake-case-name",
+		"???":              "heading",
+	}
+	for heading, expected := range tests {
+		if got := anchor(heading); got != expected {
+			t.Errorf("anchor(%q): expected %q, got %q", heading, expected, got)
+		}
+	}
+}
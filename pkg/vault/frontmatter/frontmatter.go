@@ -0,0 +1,172 @@
+// Package frontmatter reads and edits the YAML frontmatter block at the top
+// of vault notes. Edits touch only the lines of the keys they change, so the
+// rest of the block keeps its order, comments and formatting.
+package frontmatter
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Split splits a note into its frontmatter lines, without the
+// delimiters, and the body after the closing delimiter.
+func Split(note string) (block, body string, ok bool) {
+	rest, found := strings.CutPrefix(note, "---\n")
+	if !found {
+		return "", "", false
+	}
+	if after, found := strings.CutPrefix(rest, "---\n"); found {
+		return "", after, true
+	}
+	end := strings.Index(rest, "\n---\n")
+	if end < 0 {
+		if !strings.HasSuffix(rest, "\n---") {
+			return "", "", false
+		}
+		return rest[:len(rest)-len("\n---")+1], "", true
+	}
+	return rest[:end+1], rest[end+len("\n---\n"):], true
+}
+
+// Parse returns the top-level key/value pairs of a note's frontmatter.
+// Double-quoted values are unquoted; other values are returned as written,
+// and keys holding nested values or lists map to "". ok is false if the note
+// does not start with frontmatter.
+func Parse(note string) (fields map[string]string, ok bool) {
+	block, _, ok := Split(note)
+	if !ok {
+		return nil, false
+	}
+
+	fields = make(map[string]string)
+	for _, line := range lines(block) {
+		key := keyOf(line)
+		if key == "" {
+			continue
+		}
+		_, value, _ := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+			value = unquoted
+		}
+		fields[key] = value
+	}
+	return fields, true
+}
+
+// Get returns the value of key in the note's frontmatter, as Parse reads it.
+func Get(note, key string) (string, bool) {
+	fields, _ := Parse(note)
+	value, ok := fields[key]
+	return value, ok
+}
+
+// Set sets key to value, a YAML value written as given (see Quote). An
+// existing entry, including any nested lines, is replaced where it stands;
+// otherwise the key is added to the end of the frontmatter, which is created
+// if the note has none.
+func Set(note, key, value string) string {
+	line := key + ": " + value
+	block, body, ok := Split(note)
+	if !ok {
+		return Append(note, line+"\n")
+	}
+
+	ls := lines(block)
+	start, end := entry(ls, key)
+	if start < 0 {
+		ls = append(ls, line)
+	} else {
+		ls = append(ls[:start], append([]string{line}, ls[end:]...)...)
+	}
+	return join(ls, body)
+}
+
+// Remove deletes keys, with any nested lines, from the note's frontmatter.
+// Keys the note does not have are ignored.
+func Remove(note string, keys ...string) string {
+	block, body, ok := Split(note)
+	if !ok {
+		return note
+	}
+
+	ls := lines(block)
+	for _, key := range keys {
+		if start, end := entry(ls, key); start >= 0 {
+			ls = append(ls[:start], ls[end:]...)
+		}
+	}
+	return join(ls, body)
+}
+
+// Quote returns s as a double-quoted YAML string, for Set.
+func Quote(s string) string {
+	return strconv.Quote(s)
+}
+
+// Append adds YAML lines to the end of content's frontmatter,
+// creating the block if content does not start with one.
+func Append(content, lines string) string {
+	if lines == "" {
+		return content
+	}
+
+	if rest, ok := strings.CutPrefix(content, "---\n"); ok {
+		if end := strings.Index(rest, "\n---"); end >= 0 {
+			return "---\n" + rest[:end+1] + lines + rest[end+1:]
+		}
+		if strings.HasPrefix(rest, "---") {
+			return "---\n" + lines + rest
+		}
+	}
+	return "---\n" + lines + "---\n\n" + content
+}
+
+// lines splits a frontmatter block into its lines.
+func lines(block string) []string {
+	if block == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(block, "\n"), "\n")
+}
+
+// join reassembles a note from its frontmatter lines and body.
+func join(lines []string, body string) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	for _, line := range lines {
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString("---\n")
+	sb.WriteString(body)
+	return sb.String()
+}
+
+// keyOf returns the top-level key a frontmatter line starts, or "" for
+// nested lines, list items, comments and blank lines.
+func keyOf(line string) string {
+	if line == "" || strings.ContainsAny(line[:1], " \t-#") {
+		return ""
+	}
+	key, _, found := strings.Cut(line, ":")
+	if !found {
+		return ""
+	}
+	return strings.TrimSpace(key)
+}
+
+// entry returns the range of lines [start, end) holding key and its nested
+// lines, or -1, -1 if the key is missing.
+func entry(lines []string, key string) (start, end int) {
+	for i, line := range lines {
+		if keyOf(line) != key {
+			continue
+		}
+		end = i + 1
+		for end < len(lines) && lines[end] != "" && strings.ContainsAny(lines[end][:1], " \t-") {
+			end++
+		}
+		return i, end
+	}
+	return -1, -1
+}
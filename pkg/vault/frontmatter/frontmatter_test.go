@@ -0,0 +1,101 @@
+package frontmatter
+
+import "testing"
+
+const note = `---
+title: "Groceries"
+# added by hand
+tags:
+  - shopping
+model: base
+---
+# Groceries
+`
+
+func TestParse(t *testing.T) {
+	fields, ok := Parse(note)
+	if !ok {
+		t.Fatal("expected frontmatter")
+	}
+	expected := map[string]string{"title": "Groceries", "tags": "", "model": "base"}
+	if len(fields) != len(expected) {
+		t.Errorf("expected %v, got %v", expected, fields)
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("expected %s %q, got %q", key, value, fields[key])
+		}
+	}
+
+	if _, ok := Parse("# No frontmatter\n"); ok {
+		t.Error("expected no frontmatter")
+	}
+}
+
+func TestGet(t *testing.T) {
+	if value, ok := Get(note, "title"); !ok || value != "Groceries" {
+		t.Errorf("expected title Groceries, got %q (found %v)", value, ok)
+	}
+	if _, ok := Get(note, "language"); ok {
+		t.Error("expected language to be missing")
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		note     string
+		key      string
+		value    string
+		expected string
+	}{
+		{
+			name:     "replace in place",
+			note:     note,
+			key:      "title",
+			value:    Quote("Milk and eggs"),
+			expected: "---\ntitle: \"Milk and eggs\"\n# added by hand\ntags:\n  - shopping\nmodel: base\n---\n# Groceries\n",
+		},
+		{
+			name:     "replace nested value",
+			note:     note,
+			key:      "tags",
+			value:    "[errands]",
+			expected: "---\ntitle: \"Groceries\"\n# added by hand\ntags: [errands]\nmodel: base\n---\n# Groceries\n",
+		},
+		{
+			name:     "add",
+			note:     note,
+			key:      "language",
+			value:    "en",
+			expected: "---\ntitle: \"Groceries\"\n# added by hand\ntags:\n  - shopping\nmodel: base\nlanguage: en\n---\n# Groceries\n",
+		},
+		{
+			name:     "no frontmatter",
+			note:     "# Groceries\n",
+			key:      "language",
+			value:    "en",
+			expected: "---\nlanguage: en\n---\n\n# Groceries\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Set(tt.note, tt.key, tt.value); got != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	got := Remove(note, "tags", "model", "missing")
+	expected := "---\ntitle: \"Groceries\"\n# added by hand\n---\n# Groceries\n"
+	if got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	if got := Remove("# Groceries\n", "title"); got != "# Groceries\n" {
+		t.Errorf("expected a note without frontmatter unchanged, got:\n%s", got)
+	}
+}
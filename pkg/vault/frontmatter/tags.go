@@ -0,0 +1,91 @@
+package frontmatter
+
+import "strings"
+
+// AddTags adds tags to the tags key of content's frontmatter, skipping
+// any already listed. A flow list ("tags: [a, b]") or single value is
+// rewritten as a flow list; a block list ("tags:" then "- a" lines) is
+// extended. Without a tags key one is added, creating the frontmatter block
+// if needed.
+func AddTags(content string, tags []string) string {
+	if len(tags) == 0 {
+		return content
+	}
+
+	block, body, ok := Split(content)
+	if !ok {
+		return Append(content, FormatTags(tags))
+	}
+	lines := strings.Split(strings.TrimSuffix(block, "\n"), "\n")
+
+	for i, line := range lines {
+		value, found := strings.CutPrefix(line, "tags:")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		if value == "" {
+			// Block list: collect the items that follow
+			end := i + 1
+			var existing []string
+			indent := "  "
+			for ; end < len(lines); end++ {
+				trimmed := strings.TrimLeft(lines[end], " ")
+				item, isItem := strings.CutPrefix(trimmed, "- ")
+				if !isItem {
+					break
+				}
+				indent = lines[end][:len(lines[end])-len(trimmed)]
+				existing = append(existing, unquoteTag(item))
+			}
+			var added []string
+			for _, tag := range mergeTags(existing, tags)[len(existing):] {
+				added = append(added, indent+"- "+tag)
+			}
+			lines = append(lines[:end], append(added, lines[end:]...)...)
+		} else {
+			var existing []string
+			if inner, isFlow := strings.CutPrefix(value, "["); isFlow {
+				for _, item := range strings.Split(strings.TrimSuffix(inner, "]"), ",") {
+					if item = unquoteTag(item); item != "" {
+						existing = append(existing, item)
+					}
+				}
+			} else {
+				existing = []string{unquoteTag(value)}
+			}
+			lines[i] = strings.TrimSuffix(FormatTags(mergeTags(existing, tags)), "\n")
+		}
+		return "---\n" + strings.Join(lines, "\n") + "\n---\n" + body
+	}
+	return Append(content, FormatTags(tags))
+}
+
+// FormatTags returns a frontmatter tags line with a flow list.
+func FormatTags(tags []string) string {
+	return "tags: [" + strings.Join(tags, ", ") + "]\n"
+}
+
+// mergeTags returns existing followed by the tags not already in it.
+func mergeTags(existing, tags []string) []string {
+	merged := append([]string(nil), existing...)
+	for _, tag := range tags {
+		found := false
+		for _, e := range merged {
+			if strings.EqualFold(strings.TrimPrefix(e, "#"), tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+// unquoteTag trims space and surrounding quotes from a YAML list item.
+func unquoteTag(item string) string {
+	return strings.Trim(strings.TrimSpace(item), `"'`)
+}
@@ -0,0 +1,46 @@
+package frontmatter
+
+import "testing"
+
+func TestAddTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "flow list",
+			content:  "---\ntitle: Voice Note\ntags: [voice-note, budget]\n---\n\n# Voice Note\n",
+			expected: "---\ntitle: Voice Note\ntags: [voice-note, budget, garden]\n---\n\n# Voice Note\n",
+		},
+		{
+			name:     "single value",
+			content:  "---\ntags: \"voice-note\"\n---\nbody\n",
+			expected: "---\ntags: [voice-note, budget, garden]\n---\nbody\n",
+		},
+		{
+			name:     "block list",
+			content:  "---\ntags:\n    - voice-note\ndate: today\n---\nbody\n",
+			expected: "---\ntags:\n    - voice-note\n    - budget\n    - garden\ndate: today\n---\nbody\n",
+		},
+		{
+			name:     "no tags key",
+			content:  "---\ndate: today\n---\nbody\n",
+			expected: "---\ndate: today\ntags: [budget, garden]\n---\nbody\n",
+		},
+		{
+			name:     "no frontmatter",
+			content:  "# Heading\n",
+			expected: "---\ntags: [budget, garden]\n---\n\n# Heading\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AddTags(tt.content, []string{"budget", "garden"})
+			if got != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
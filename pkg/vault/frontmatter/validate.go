@@ -0,0 +1,142 @@
+package frontmatter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalid is returned by Validate for frontmatter that YAML parsers,
+// such as Obsidian's, would reject.
+var ErrInvalid = errors.New("invalid frontmatter")
+
+// Validate checks the frontmatter a note starts with, if any, for the
+// mistakes that break it: a missing closing delimiter, lines that are not
+// "key: value" pairs, list items or nested lines, tab indentation,
+// repeated keys, and unclosed quotes and brackets. Flow lists and mappings
+// may continue onto the following lines. Errors give the line number within
+// the note and wrap ErrInvalid. It only covers the subset of YAML used in
+// note frontmatter, not the whole language.
+func Validate(note string) error {
+	note = strings.ReplaceAll(note, "\r\n", "\n")
+	if !strings.HasPrefix(note, "---\n") {
+		return nil
+	}
+	block, _, ok := Split(note)
+	if !ok {
+		return fmt.Errorf("%w: no closing --- line", ErrInvalid)
+	}
+
+	seen := make(map[string]bool)
+	nestable := false
+	blockLines := lines(block)
+	for i := 0; i < len(blockLines); i++ {
+		line := blockLines[i]
+		// The opening delimiter is line 1
+		n := i + 2
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if strings.Contains(indent, "\t") {
+			return fmt.Errorf("%w: line %d is indented with a tab", ErrInvalid, n)
+		}
+
+		if indent != "" || strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			if !nestable {
+				return fmt.Errorf("%w: line %d is nested under a key that already has a value", ErrInvalid, n)
+			}
+			value := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			if key, rest, found := strings.Cut(value, ": "); found && !strings.ContainsAny(key, `"'[{`) {
+				value = rest
+			}
+			value, i = continueFlow(blockLines, i, value)
+			if err := checkValue(value); err != nil {
+				return fmt.Errorf("%w: line %d: %v", ErrInvalid, n, err)
+			}
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		if !found || key == "" || (value != "" && !strings.HasPrefix(value, " ")) {
+			return fmt.Errorf("%w: line %d is not a \"key: value\" pair", ErrInvalid, n)
+		}
+		if seen[key] {
+			return fmt.Errorf("%w: line %d repeats the key %q", ErrInvalid, n, key)
+		}
+		seen[key] = true
+
+		value = strings.TrimSpace(value)
+		// Lists, mappings and block scalars continue on the following lines
+		nestable = value == "" || strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">")
+		value, i = continueFlow(blockLines, i, value)
+		if err := checkValue(value); err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalid, n, err)
+		}
+	}
+	return nil
+}
+
+// continueFlow joins a flow list or mapping that starts with value on line
+// i of lines onto the following lines until its brackets are closed, as in
+// "tags: [a," followed by "  b]". It returns the joined value and the index
+// of its last line. Other values, and flow collections that are never
+// closed, are returned as they are, for checkValue to report.
+func continueFlow(lines []string, i int, value string) (string, int) {
+	if !strings.HasPrefix(value, "[") && !strings.HasPrefix(value, "{") {
+		return value, i
+	}
+	joined := value
+	for j := i + 1; flowDepth(joined) > 0 && j < len(lines); j++ {
+		joined += " " + strings.TrimSpace(lines[j])
+		if flowDepth(joined) == 0 {
+			return joined, j
+		}
+	}
+	return value, i
+}
+
+// flowDepth returns how many brackets and braces of value are still open,
+// not counting those in quotes.
+func flowDepth(value string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// checkValue checks that a quoted value, flow list or flow mapping is
+// closed on its line.
+func checkValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	closing := map[byte]string{'"': `"`, '\'': "'", '[': "]", '{': "}"}[value[0]]
+	if closing == "" {
+		return nil
+	}
+	// Escaped backslashes can't escape the closing quote
+	unescaped := strings.ReplaceAll(value, `\\`, "")
+	if len(value) < 2 || !strings.HasSuffix(value, closing) || closing == `"` && strings.HasSuffix(unescaped, `\"`) {
+		return fmt.Errorf("unclosed %c", value[0])
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package frontmatter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		note     string
+		expected string
+	}{
+		{"no frontmatter", "# Meeting\n", ""},
+		{"valid", "---\ntype: meeting\n# a comment\ntags: [meeting, \"q1\"]\naliases:\n  - standup\n- sync\nsummary: |\n  Weekly.\nquoted: \"say \\\"hi\\\"\"\nsingle: 'it''s'\n---\n# Meeting\n", ""},
+		{"empty block", "---\n---\n# Meeting\n", ""},
+		{"not closed", "---\ntype: meeting\n# Meeting\n", "no closing --- line"},
+		{"missing colon", "---\ntype meeting\n---\n", "line 2 is not a \"key: value\" pair"},
+		{"no space after colon", "---\ntype:meeting\n---\n", "line 2 is not a \"key: value\" pair"},
+		{"tab", "---\ntags:\n\t- a\n---\n", "line 3 is indented with a tab"},
+		{"nested under a value", "---\ntype: meeting\n  - a\n---\n", "line 3 is nested under a key that already has a value"},
+		{"repeated key", "---\ntags: [a]\ntype: x\ntags: [b]\n---\n", "line 4 repeats the key \"tags\""},
+		{"unclosed quote", "---\ntitle: \"Budget\n---\n", "line 2: unclosed \""},
+		{"escaped closing quote", "---\ntitle: \"Budget\\\"\n---\n", "line 2: unclosed \""},
+		{"unclosed list", "---\ntags: [a, b\n---\n", "line 2: unclosed ["},
+		{"unclosed nested quote", "---\naliases:\n  - 'standup\n---\n", "line 3: unclosed '"},
+		{"multi-line flow list", "---\ntags: [a,\n  b]\ntype: meeting\n---\n", ""},
+		{"multi-line flow mapping", "---\nproject: {name: garden,\n  status: \"active\"\n}\n---\n", ""},
+		{"multi-line nested flow list", "---\nlinks:\n  - [a,\n    b]\n---\n", ""},
+		{"quoted bracket in flow list", "---\ntags: [\"]\",\n  b]\n---\n", ""},
+		{"unclosed multi-line list", "---\ntags: [a,\n  b\ntype: meeting\n---\n", "line 2: unclosed ["},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.note)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected an error containing %q, got: %v", tt.expected, err)
+			}
+		})
+	}
+}
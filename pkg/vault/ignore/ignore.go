@@ -0,0 +1,170 @@
+// Package ignore reads .notaignore files, which list the paths tools skip
+// when scanning a directory tree, in gitignore syntax.
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FileName is the ignore file's name, read from the root of the tree it
+// applies to.
+const FileName = ".notaignore"
+
+// Matcher reports whether paths are ignored. A nil Matcher ignores nothing.
+type Matcher struct {
+	rules []rule
+}
+
+// rule is one pattern line.
+type rule struct {
+	pattern *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// Load reads the ignore file in root. A missing file ignores nothing.
+func Load(root string) (*Matcher, error) {
+	data, err := os.ReadFile(filepath.Join(root, FileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Matcher{}, nil
+		}
+		return nil, err
+	}
+	return Parse(string(data)), nil
+}
+
+// Parse compiles the patterns in an ignore file's content. Lines follow
+// gitignore syntax: # starts a comment, ! re-includes what an earlier
+// pattern excluded, a trailing / matches only directories, a pattern with a
+// / elsewhere is relative to the root while one without matches at any
+// depth, and ** matches any number of directories.
+func Parse(content string) *Matcher {
+	m := &Matcher{}
+	for _, line := range strings.Split(content, "\n") {
+		if r, ok := parseRule(line); ok {
+			m.rules = append(m.rules, r)
+		}
+	}
+	return m
+}
+
+// parseRule compiles one line, reporting false for blank lines and comments.
+func parseRule(line string) (rule, bool) {
+	line = strings.TrimSuffix(line, "\r")
+	// Trailing spaces are dropped unless escaped
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false
+	}
+
+	var r rule
+	if rest, ok := strings.CutPrefix(line, "!"); ok {
+		r.negate = true
+		line = rest
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if rest, ok := strings.CutSuffix(line, "/"); ok {
+		r.dirOnly = true
+		line = rest
+	}
+	if line == "" {
+		return rule{}, false
+	}
+
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	expr := globToRegexp(line)
+	if anchored {
+		expr = "^" + expr + "$"
+	} else {
+		expr = "^(?:.*/)?" + expr + "$"
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return rule{}, false
+	}
+	r.pattern = pattern
+	return r, true
+}
+
+// globToRegexp translates a glob to a regular expression in which * and ?
+// stay within one path segment and ** spans segments.
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '\\' && i+1 < len(glob):
+			i++
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if rest, ok := strings.CutPrefix(class, "!"); ok {
+				class = "^" + rest
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
+
+// Match reports whether the path rel, relative to the root and separated
+// by the OS separator, is ignored. isDir tells whether it is a directory.
+// Everything inside an ignored directory is ignored too.
+func (m *Matcher) Match(rel string, isDir bool) bool {
+	if m == nil || len(m.rules) == 0 {
+		return false
+	}
+	rel = strings.Trim(filepath.ToSlash(filepath.Clean(rel)), "/")
+	if rel == "." || rel == "" {
+		return false
+	}
+
+	segments := strings.Split(rel, "/")
+	for i := 1; i < len(segments); i++ {
+		if m.matches(strings.Join(segments[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.matches(rel, isDir)
+}
+
+// matches applies the rules to one path, the last matching rule deciding.
+func (m *Matcher) matches(rel string, isDir bool) bool {
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if r.pattern.MatchString(rel) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,82 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	m := Parse(`# build output
+node_modules/
+*.mp4
+/Attachments/raw
+Projects/**/drafts
+docs/**
+!docs/keep.md
+\#literal.md
+trailing   
+`)
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"node_modules", true, true},
+		{"Projects/site/node_modules/lib/index.md", false, true},
+		{"node_modules", false, false},
+		{"talk.mp4", false, true},
+		{"Areas/video/talk.mp4", false, true},
+		{"Attachments/raw", true, true},
+		{"Attachments/raw/photo.png", false, true},
+		{"Inbox/Attachments/raw", true, false},
+		{"Projects/drafts", true, true},
+		{"Projects/garden/2024/drafts/plan.md", false, true},
+		{"docs/guide.md", false, true},
+		{"docs/keep.md", false, false},
+		{"#literal.md", false, true},
+		{"trailing", false, true},
+		{"Inbox/memo.md", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := m.Match(filepath.FromSlash(tt.path), tt.isDir); got != tt.ignored {
+			t.Errorf("Match(%q, %v): expected %v, got %v", tt.path, tt.isDir, tt.ignored, got)
+		}
+	}
+}
+
+func TestMatch_ExcludedParent(t *testing.T) {
+	// As in git, a file can't be re-included when its directory is excluded
+	m := Parse("build/\n!build/keep.md\n")
+	if !m.Match("build/keep.md", false) {
+		t.Error("expected build/keep.md to stay ignored")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	m, err := Load(root)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if m.Match("anything.md", false) {
+		t.Error("expected nothing ignored without an ignore file")
+	}
+
+	if err := os.WriteFile(filepath.Join(root, FileName), []byte("*.tmp\n"), 0644); err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+	if m, err = Load(root); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !m.Match("note.tmp", false) {
+		t.Error("expected note.tmp to be ignored")
+	}
+
+	var nilMatcher *Matcher
+	if nilMatcher.Match("note.tmp", false) {
+		t.Error("expected a nil matcher to ignore nothing")
+	}
+}
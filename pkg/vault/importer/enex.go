@@ -0,0 +1,399 @@
+package importer
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// enexTime is the layout of the times in .enex files.
+const enexTime = "20060102T150405Z"
+
+// enexNote is a <note> element of an .enex file.
+type enexNote struct {
+	Title     string         `xml:"title"`
+	Content   string         `xml:"content"`
+	Created   string         `xml:"created"`
+	Updated   string         `xml:"updated"`
+	Tags      []string       `xml:"tag"`
+	Resources []enexResource `xml:"resource"`
+}
+
+// enexResource is an attachment of an Evernote note.
+type enexResource struct {
+	Data     string `xml:"data"`
+	Mime     string `xml:"mime"`
+	FileName string `xml:"resource-attributes>file-name"`
+}
+
+// readEnex reads the notes in an Evernote .enex export. Each note's ENML
+// content is converted to markdown and its resources become attachments
+// in an Attachments folder.
+func readEnex(src string) ([]*item, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var items []*item
+	attachments := make(map[string]bool)
+	dec := xml.NewDecoder(f)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "note" {
+			continue
+		}
+
+		var en enexNote
+		if err := dec.DecodeElement(&en, &start); err != nil {
+			return nil, err
+		}
+
+		// Resources are referenced from the content by the MD5 of their data
+		media := make(map[string]*item)
+		for _, r := range en.Resources {
+			data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(r.Data), ""))
+			if err != nil {
+				return nil, fmt.Errorf("note %q: invalid resource: %w", en.Title, err)
+			}
+			sum := md5.Sum(data)
+			it := &item{src: attachmentPath(r, attachments), data: data}
+			it.name = path.Base(it.src)
+			media[hex.EncodeToString(sum[:])] = it
+			items = append(items, it)
+		}
+
+		title := strings.TrimSpace(en.Title)
+		name := cleanName(title, SourceEvernote) + ".md"
+		n := &note{
+			title:   title,
+			tags:    en.Tags,
+			content: enmlToMarkdown(en.Content, media),
+		}
+		n.created, _ = time.Parse(enexTime, en.Created)
+		n.updated, _ = time.Parse(enexTime, en.Updated)
+		items = append(items, &item{src: name, name: name, note: n})
+	}
+	return items, nil
+}
+
+// attachmentPath returns a path in the Attachments folder for a resource
+// not yet taken by another.
+func attachmentPath(r enexResource, taken map[string]bool) string {
+	name := r.FileName
+	if name == "" {
+		name = "attachment" + mimeExt(r.Mime)
+	}
+	name = cleanFileName(name, SourceEvernote)
+
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	p := "Attachments/" + name
+	for i := 2; taken[strings.ToLower(p)]; i++ {
+		p = fmt.Sprintf("Attachments/%s-%d%s", base, i, ext)
+	}
+	taken[strings.ToLower(p)] = true
+	return p
+}
+
+// mimeExt returns the file extension for common attachment types.
+func mimeExt(mime string) string {
+	switch mime {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "application/pdf":
+		return ".pdf"
+	case "audio/mpeg":
+		return ".mp3"
+	case "audio/mp4", "audio/x-m4a":
+		return ".m4a"
+	}
+	return ""
+}
+
+// enmlToMarkdown converts a note's ENML, Evernote's XHTML dialect, to
+// markdown. media maps resource hashes to the attachments <en-media>
+// elements refer to. Links to other Evernote notes become wikilinks to
+// their titles.
+func enmlToMarkdown(enml string, media map[string]*item) string {
+	c := &enmlConverter{media: media}
+	dec := xml.NewDecoder(strings.NewReader(enml))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			// Keep what was converted before any malformed markup
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			c.start(t)
+		case xml.EndElement:
+			c.end(t.Name.Local)
+		case xml.CharData:
+			c.text(string(t))
+		}
+	}
+	return c.String()
+}
+
+// enmlConverter holds the state of an ENML conversion.
+type enmlConverter struct {
+	media map[string]*item
+	sb    strings.Builder
+	// lists holds the open lists, innermost last: 0 for bulleted lists and
+	// the next number for numbered ones.
+	lists []int
+	pre   bool
+	// item is set right after a list item's marker, before its text.
+	item bool
+	// links holds the href and output offset of each open <a>.
+	links []enmlLink
+}
+
+type enmlLink struct {
+	href  string
+	start int
+}
+
+func (c *enmlConverter) start(t xml.StartElement) {
+	switch name := t.Name.Local; name {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		c.block(2)
+		c.sb.WriteString(strings.Repeat("#", int(name[1]-'0')) + " ")
+	case "p", "div":
+		c.paragraph()
+	case "table":
+		c.block(2)
+	case "tr":
+		c.block(1)
+	case "td", "th":
+		if !c.atLineStart() {
+			c.sb.WriteString(" | ")
+		}
+	case "br":
+		c.block(1)
+	case "hr":
+		c.block(2)
+		c.sb.WriteString("---")
+		c.block(2)
+	case "blockquote":
+		c.block(2)
+		c.sb.WriteString("> ")
+	case "pre":
+		c.block(2)
+		c.sb.WriteString("```\n")
+		c.pre = true
+	case "code":
+		if !c.pre {
+			c.sb.WriteString("`")
+		}
+	case "b", "strong":
+		c.sb.WriteString("**")
+	case "i", "em":
+		c.sb.WriteString("*")
+	case "s", "strike", "del":
+		c.sb.WriteString("~~")
+	case "ul":
+		c.lists = append(c.lists, 0)
+	case "ol":
+		c.lists = append(c.lists, 1)
+	case "li":
+		c.block(1)
+		depth := len(c.lists)
+		if depth == 0 {
+			depth = 1
+		}
+		c.sb.WriteString(strings.Repeat("  ", depth-1))
+		if len(c.lists) > 0 && c.lists[len(c.lists)-1] > 0 {
+			fmt.Fprintf(&c.sb, "%d. ", c.lists[len(c.lists)-1])
+			c.lists[len(c.lists)-1]++
+		} else {
+			c.sb.WriteString("- ")
+		}
+		c.item = true
+	case "en-todo":
+		if c.atLineStart() {
+			c.sb.WriteString("- ")
+		}
+		if attr(t, "checked") == "true" {
+			c.sb.WriteString("[x] ")
+		} else {
+			c.sb.WriteString("[ ] ")
+		}
+		c.item = true
+	case "en-media":
+		it := c.media[strings.ToLower(attr(t, "hash"))]
+		if it == nil {
+			return
+		}
+		if strings.HasPrefix(attr(t, "type"), "image/") {
+			c.sb.WriteString("![](<" + it.src + ">)")
+		} else {
+			c.sb.WriteString("[" + it.name + "](<" + it.src + ">)")
+		}
+	case "img":
+		if src := attr(t, "src"); src != "" {
+			c.sb.WriteString("![" + attr(t, "alt") + "](" + src + ")")
+		}
+	case "a":
+		c.links = append(c.links, enmlLink{href: attr(t, "href"), start: c.sb.Len()})
+	}
+}
+
+func (c *enmlConverter) end(name string) {
+	switch name {
+	case "h1", "h2", "h3", "h4", "h5", "h6", "blockquote", "table":
+		c.block(2)
+	case "p", "div":
+		c.paragraph()
+	case "pre":
+		c.pre = false
+		c.block(1)
+		c.sb.WriteString("```")
+		c.block(2)
+	case "code":
+		if !c.pre {
+			c.sb.WriteString("`")
+		}
+	case "b", "strong":
+		c.sb.WriteString("**")
+	case "i", "em":
+		c.sb.WriteString("*")
+	case "s", "strike", "del":
+		c.sb.WriteString("~~")
+	case "ul", "ol":
+		if len(c.lists) > 0 {
+			c.lists = c.lists[:len(c.lists)-1]
+		}
+		if len(c.lists) == 0 {
+			c.block(2)
+		}
+	case "a":
+		if len(c.links) == 0 {
+			return
+		}
+		link := c.links[len(c.links)-1]
+		c.links = c.links[:len(c.links)-1]
+		c.endLink(link)
+	}
+}
+
+// endLink turns the text written since the link opened into a link.
+func (c *enmlConverter) endLink(link enmlLink) {
+	out := c.sb.String()
+	text := strings.TrimSpace(out[link.start:])
+	c.sb.Reset()
+	c.sb.WriteString(out[:link.start])
+
+	switch {
+	case strings.HasPrefix(link.href, "evernote:"):
+		// Links between notes name the note by its title
+		c.sb.WriteString("[[" + text + "]]")
+	case link.href == "" || text == link.href:
+		c.sb.WriteString(text)
+	case text == "":
+		c.sb.WriteString("<" + link.href + ">")
+	default:
+		c.sb.WriteString("[" + text + "](" + link.href + ")")
+	}
+}
+
+func (c *enmlConverter) text(s string) {
+	if c.pre {
+		c.sb.WriteString(s)
+		return
+	}
+	// Evernote spaces words with &nbsp; freely
+	s = whitespace.ReplaceAllString(strings.ReplaceAll(s, "\u00a0", " "), " ")
+	if c.atLineStart() || c.item {
+		s = strings.TrimLeft(s, " ")
+	}
+	if s != "" {
+		c.item = false
+	}
+	c.sb.WriteString(s)
+}
+
+var (
+	whitespace = regexp.MustCompile(`\s+`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// paragraph separates paragraphs, which are single lines within lists.
+func (c *enmlConverter) paragraph() {
+	if c.item {
+		return
+	}
+	if len(c.lists) > 0 {
+		c.block(1)
+		return
+	}
+	c.block(2)
+}
+
+// block ends the current line and, for n of 2, leaves a blank line, unless
+// at the start of the note.
+func (c *enmlConverter) block(n int) {
+	if c.pre {
+		return
+	}
+	c.item = false
+	out := strings.TrimRight(c.sb.String(), " ")
+	if out == "" {
+		c.sb.Reset()
+		return
+	}
+	for i := len(out) - 1; i >= 0 && out[i] == '\n' && n > 0; i-- {
+		n--
+	}
+	c.sb.Reset()
+	c.sb.WriteString(out + strings.Repeat("\n", n))
+}
+
+// atLineStart reports whether output is at the start of a line.
+func (c *enmlConverter) atLineStart() bool {
+	out := c.sb.String()
+	return out == "" || strings.HasSuffix(out, "\n")
+}
+
+// String returns the converted markdown, ending in a newline.
+func (c *enmlConverter) String() string {
+	out := strings.TrimSpace(blankLines.ReplaceAllString(c.sb.String(), "\n\n"))
+	if out == "" {
+		return ""
+	}
+	return out + "\n"
+}
+
+// attr returns the value of the named attribute of an element.
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
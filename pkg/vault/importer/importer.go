@@ -0,0 +1,485 @@
+// Package importer brings notes exported from other tools into a vault:
+// folders of markdown files, Evernote .enex exports and Notion exports. File
+// names are cleaned up, frontmatter is filled in, and links between the
+// imported notes are rewritten to their new names.
+package importer
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/frontmatter"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/index"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/links"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/notes"
+)
+
+// Source is the kind of export being imported.
+type Source string
+
+// Supported sources.
+const (
+	// SourceMarkdown is a folder of markdown files, such as another vault.
+	SourceMarkdown Source = "markdown"
+	// SourceEvernote is an Evernote .enex export.
+	SourceEvernote Source = "evernote"
+	// SourceNotion is a Notion "Markdown & CSV" export, zipped or unzipped.
+	SourceNotion Source = "notion"
+)
+
+// Valid reports whether s is a supported source.
+func (s Source) Valid() bool {
+	return s == SourceMarkdown || s == SourceEvernote || s == SourceNotion
+}
+
+// Errors returned by Import.
+var (
+	ErrInvalidSource  = errors.New("invalid import source: must be markdown, evernote or notion")
+	ErrNotParaFolder  = errors.New("destination must be inside one of the vault's PARA folders (Inbox, Journal, Projects, Areas, Resources, Archive)")
+	ErrNothingToWrite = errors.New("no notes found to import")
+)
+
+// Options configures an import.
+type Options struct {
+	// Source is the kind of export; empty means detect it with Detect.
+	Source Source
+	// Folder is where the notes go, relative to the vault root, e.g.
+	// "Resources/Evernote". Its first element must be a PARA folder.
+	Folder string
+	// DryRun plans the import without writing anything.
+	DryRun bool
+	// Perms, when set, sets up the created directories and files.
+	Perms notes.Permissions
+}
+
+// Result lists what an import wrote, or would write on a dry run, relative
+// to the vault root.
+type Result struct {
+	Source      Source   `json:"source"`
+	Notes       []string `json:"notes"`
+	Attachments []string `json:"attachments"`
+}
+
+// item is a note or attachment read from an export.
+type item struct {
+	// src is the item's path in the export, slash-separated.
+	src string
+	// name is the item's file name after cleaning up, with extension.
+	name string
+	// dst is the item's path in the vault, slash-separated; set by plan.
+	dst string
+	// note is set for notes; attachments have data only.
+	note *note
+	data []byte
+}
+
+// note is the content of an imported note.
+type note struct {
+	title            string
+	created, updated time.Time
+	tags             []string
+	// content is the note's markdown, including any frontmatter.
+	content string
+}
+
+// Detect reports which kind of export is at p: a .enex file is Evernote, a
+// .zip file or a folder with Notion's page ids in its file names is Notion,
+// and any other folder is markdown.
+func Detect(p string) (Source, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".enex":
+			return SourceEvernote, nil
+		case ".zip":
+			return SourceNotion, nil
+		}
+		return "", fmt.Errorf("%s: %w", p, ErrInvalidSource)
+	}
+
+	source := SourceMarkdown
+	filepath.WalkDir(p, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && notionID.MatchString(strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))) {
+			source = SourceNotion
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return source, nil
+}
+
+// Import reads the export at src and writes its notes and attachments
+// under opts.Folder in the vault at vaultRoot. Notes keep the folder layout
+// of the export; names that clash with existing files get -2, -3 and so on.
+func Import(vaultRoot, src string, opts Options) (*Result, error) {
+	folder, err := destination(vaultRoot, opts.Folder)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Source == "" {
+		if opts.Source, err = Detect(src); err != nil {
+			return nil, err
+		}
+	}
+
+	var items []*item
+	switch opts.Source {
+	case SourceMarkdown, SourceNotion:
+		items, err = readTree(src, opts.Source)
+	case SourceEvernote:
+		items, err = readEnex(src)
+	default:
+		return nil, ErrInvalidSource
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", src, err)
+	}
+
+	result := &Result{Source: opts.Source, Notes: []string{}, Attachments: []string{}}
+	if err := plan(vaultRoot, folder, items, opts.Source); err != nil {
+		return nil, err
+	}
+	for _, it := range items {
+		if it.note != nil {
+			result.Notes = append(result.Notes, it.dst)
+		} else {
+			result.Attachments = append(result.Attachments, it.dst)
+		}
+	}
+	if len(result.Notes) == 0 {
+		return nil, ErrNothingToWrite
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for _, it := range items {
+		data := it.data
+		if it.note != nil {
+			data = []byte(normalize(rewriteLinks(it, items), it.note, opts.Source))
+		}
+		dir, name := path.Split(it.dst)
+		ext := path.Ext(name)
+		if _, err := notes.Create(filepath.Join(vaultRoot, filepath.FromSlash(dir)), strings.TrimSuffix(name, ext), ext, data, opts.Perms); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", it.dst, err)
+		}
+	}
+	return result, nil
+}
+
+// destination checks that folder is inside one of the vault's PARA folders
+// and returns it spelled as the vault spells that folder.
+func destination(vaultRoot, folder string) (string, error) {
+	folder = path.Clean(filepath.ToSlash(folder))
+	first, rest, _ := strings.Cut(strings.TrimPrefix(folder, "/"), "/")
+	para, ok := vault.ParaFolder(vaultRoot, first)
+	if !ok || strings.HasPrefix(rest, "..") {
+		return "", ErrNotParaFolder
+	}
+	return path.Join(para, rest), nil
+}
+
+// readTree reads a folder or zip file of markdown notes. Files other than
+// notes are imported as attachments; hidden files are skipped.
+func readTree(src string, source Source) ([]*item, error) {
+	var fsys fs.FS
+	if strings.EqualFold(filepath.Ext(src), ".zip") {
+		zr, err := zip.OpenReader(src)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		fsys = zr
+	} else {
+		fsys = os.DirFS(src)
+	}
+
+	var items []*item
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != "." && (strings.HasPrefix(d.Name(), ".") || d.Name() == "__MACOSX") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		it := &item{src: p, name: cleanFileName(d.Name(), source)}
+		if !strings.EqualFold(path.Ext(p), index.NoteExt) {
+			it.data = data
+			items = append(items, it)
+			return nil
+		}
+
+		content := strings.ReplaceAll(string(data), "\r\n", "\n")
+		n := &note{title: index.Title(content), content: content}
+		if n.title == "" {
+			n.title = strings.TrimSuffix(it.name, path.Ext(it.name))
+		}
+		if info, err := d.Info(); err == nil {
+			n.updated = info.ModTime()
+		}
+		it.note = n
+		items = append(items, it)
+		return nil
+	})
+	return items, err
+}
+
+// plan picks each item's path in the vault: under folder, in the cleaned up
+// folders of the export, with a free name.
+func plan(vaultRoot, folder string, items []*item, source Source) error {
+	taken := make(map[string]bool)
+	for _, it := range items {
+		var dirs []string
+		for _, dir := range strings.Split(path.Dir(it.src), "/") {
+			if dir != "." {
+				dirs = append(dirs, cleanName(dir, source))
+			}
+		}
+		dir := path.Join(append([]string{folder}, dirs...)...)
+
+		ext := path.Ext(it.name)
+		base := strings.TrimSuffix(it.name, ext)
+		for i := 1; ; i++ {
+			name := it.name
+			if i > 1 {
+				name = fmt.Sprintf("%s-%d%s", base, i, ext)
+			}
+			dst := path.Join(dir, name)
+			if taken[strings.ToLower(dst)] {
+				continue
+			}
+			if _, err := os.Lstat(filepath.Join(vaultRoot, filepath.FromSlash(dst))); err == nil {
+				continue
+			} else if !os.IsNotExist(err) {
+				return err
+			}
+			it.dst = dst
+			taken[strings.ToLower(dst)] = true
+			break
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].dst < items[j].dst })
+	return nil
+}
+
+// rewriteLinks points the links in a note at the other items' new paths.
+// Markdown links get the new relative path; wikilinks get the new
+// vault-relative path when the name they used changed.
+func rewriteLinks(it *item, items []*item) string {
+	bySrc := make(map[string]*item, len(items))
+	byName := make(map[string]*item, len(items))
+	for _, other := range items {
+		bySrc[strings.ToLower(other.src)] = other
+		name := path.Base(other.src)
+		if other.note != nil {
+			name = strings.TrimSuffix(name, path.Ext(name))
+			// Evernote links name notes by title
+			if key := strings.ToLower(other.note.title); byName[key] == nil {
+				byName[key] = other
+			}
+		}
+		byName[strings.ToLower(name)] = other
+	}
+
+	content := it.note.content
+	var sb strings.Builder
+	last := 0
+	for _, link := range links.Parse(content) {
+		replacement := ""
+		if link.Wiki {
+			replacement = rewriteWikilink(link, byName, bySrc)
+		} else {
+			replacement = rewriteMarkdownLink(it, link, bySrc)
+		}
+		if replacement == "" {
+			continue
+		}
+		sb.WriteString(content[last:link.Start])
+		sb.WriteString(replacement)
+		last = link.End
+	}
+	sb.WriteString(content[last:])
+	return sb.String()
+}
+
+// rewriteWikilink returns the wikilink pointing at the item the link names,
+// or "" if it needs no change.
+func rewriteWikilink(link links.Link, byName, bySrc map[string]*item) string {
+	if link.Target == "" {
+		return ""
+	}
+	key := strings.ToLower(strings.TrimSuffix(link.Target, index.NoteExt))
+	target := bySrc[key+index.NoteExt]
+	if target == nil {
+		target = bySrc[key]
+	}
+	if target == nil {
+		target = byName[path.Base(key)]
+	}
+	if target == nil {
+		return ""
+	}
+
+	name := path.Base(target.dst)
+	if target.note != nil {
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+	if name == link.Target {
+		return ""
+	}
+	dst := target.dst
+	if target.note != nil {
+		dst = strings.TrimSuffix(dst, path.Ext(dst))
+	}
+
+	var sb strings.Builder
+	if link.Embed {
+		sb.WriteString("!")
+	}
+	sb.WriteString("[[" + dst)
+	if link.Heading != "" {
+		sb.WriteString("#" + link.Heading)
+	}
+	if link.Alias != "" {
+		sb.WriteString("|" + link.Alias)
+	} else if target.note != nil && !link.Embed {
+		sb.WriteString("|" + link.Target)
+	}
+	sb.WriteString("]]")
+	return sb.String()
+}
+
+// rewriteMarkdownLink returns the markdown link pointing at the item the
+// link's path leads to, relative to the linking note, or "" if it leads
+// outside the import.
+func rewriteMarkdownLink(from *item, link links.Link, bySrc map[string]*item) string {
+	p := path.Join(path.Dir(from.src), link.Target)
+	if strings.HasPrefix(link.Target, "/") {
+		p = strings.TrimPrefix(path.Clean(link.Target), "/")
+	}
+	target := bySrc[strings.ToLower(p)]
+	if target == nil {
+		target = bySrc[strings.ToLower(p+index.NoteExt)]
+	}
+	if target == nil {
+		return ""
+	}
+
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(from.dst)), filepath.FromSlash(target.dst))
+	if err != nil {
+		return ""
+	}
+	dest := filepath.ToSlash(rel)
+	if link.Heading != "" {
+		dest += "#" + link.Heading
+	}
+	prefix := ""
+	if link.Embed {
+		prefix = "!"
+	}
+	return prefix + "[" + link.Alias + "](<" + dest + ">)"
+}
+
+// normalize fills in the frontmatter of an imported note: its title,
+// created and updated times, tags and source, keeping any values the note
+// already has.
+func normalize(content string, n *note, source Source) string {
+	fields, _ := frontmatter.Parse(content)
+	set := func(key, value string) {
+		if _, ok := fields[key]; !ok && value != "" {
+			content = frontmatter.Set(content, key, value)
+		}
+	}
+	set("title", frontmatter.Quote(n.title))
+	if !n.created.IsZero() {
+		set("created", n.created.Format(time.RFC3339))
+	}
+	if !n.updated.IsZero() {
+		set("updated", n.updated.Format(time.RFC3339))
+	}
+	set("source", string(source))
+	if len(n.tags) > 0 {
+		content = frontmatter.AddTags(content, n.tags)
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content
+}
+
+// notionID matches the page id Notion appends to exported file names.
+var notionID = regexp.MustCompile(`^(.*\S)\s+[0-9a-f]{32}$`)
+
+// unsafeChars are characters that break file names or links on some
+// systems.
+var unsafeChars = regexp.MustCompile(`[\\/:*?"<>|#^\[\]\x00-\x1f]+`)
+
+// cleanFileName cleans up a file name, keeping its extension.
+func cleanFileName(name string, source Source) string {
+	ext := path.Ext(name)
+	if len(ext) > 10 || strings.ContainsAny(ext, " ") {
+		ext = ""
+	}
+	return cleanName(strings.TrimSuffix(name, ext), source) + strings.ToLower(ext)
+}
+
+// cleanName cleans up a file or folder name without extension: Notion's
+// page ids are dropped, characters that break file names or links become
+// spaces, and runs of spaces collapse.
+func cleanName(name string, source Source) string {
+	if source == SourceNotion {
+		if m := notionID.FindStringSubmatch(name); m != nil {
+			name = m[1]
+		}
+	}
+	name = unsafeChars.ReplaceAllString(name, " ")
+	name = strings.Join(strings.Fields(name), " ")
+	name = strings.Trim(name, ". ")
+	// Keep names well under common file name limits
+	if len(name) > 100 {
+		name = strings.TrimSpace(truncate(name, 100))
+	}
+	if name == "" {
+		return "Untitled"
+	}
+	return name
+}
+
+// truncate shortens s to at most n bytes without splitting a character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := 0
+	for i := range s {
+		if i > n {
+			break
+		}
+		cut = i
+	}
+	return s[:cut]
+}
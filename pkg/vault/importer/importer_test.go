@@ -0,0 +1,234 @@
+package importer
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	p := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
+
+func readFile(t *testing.T, root, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, rel))
+	if err != nil {
+		t.Fatalf("expected %s to exist: %v", rel, err)
+	}
+	return string(data)
+}
+
+func TestImport_Markdown(t *testing.T) {
+	vaultRoot := t.TempDir()
+	src := t.TempDir()
+	writeFile(t, src, "Garden/plan: 2024?.md", "---\ntags: [garden]\n---\n# Plan\n\nSee [beds](beds.md#north), [[Compost]] and ![sketch](../img/sketch.png).\n")
+	writeFile(t, src, "Garden/beds.md", "Raised beds.\n")
+	writeFile(t, src, "Compost.md", "# Compost\n\nBack to [the plan](<Garden/plan: 2024?.md>).\n")
+	writeFile(t, src, "img/sketch.png", "png")
+	writeFile(t, src, ".obsidian/workspace.json", "{}")
+	// A note already in the vault keeps its name
+	writeFile(t, vaultRoot, "Resources/Old vault/Compost.md", "# Mine\n")
+
+	result, err := Import(vaultRoot, src, Options{Folder: "resources/Old vault"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	expected := &Result{
+		Source:      SourceMarkdown,
+		Notes:       []string{"Resources/Old vault/Compost-2.md", "Resources/Old vault/Garden/beds.md", "Resources/Old vault/Garden/plan 2024.md"},
+		Attachments: []string{"Resources/Old vault/img/sketch.png"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+
+	plan := readFile(t, vaultRoot, "Resources/Old vault/Garden/plan 2024.md")
+	for _, want := range []string{
+		"tags: [garden]\ntitle: \"Plan\"\nupdated: ",
+		"source: markdown\n---\n# Plan",
+		"[beds](<beds.md#north>)",
+		"[[Resources/Old vault/Compost-2|Compost]]",
+		"![sketch](<../img/sketch.png>)",
+	} {
+		if !strings.Contains(plan, want) {
+			t.Errorf("expected the plan to contain %q, got:\n%s", want, plan)
+		}
+	}
+	compost := readFile(t, vaultRoot, "Resources/Old vault/Compost-2.md")
+	if !strings.Contains(compost, "[the plan](<Garden/plan 2024.md>)") {
+		t.Errorf("expected the link to the renamed note to be rewritten, got:\n%s", compost)
+	}
+	if readFile(t, vaultRoot, "Resources/Old vault/Compost.md") != "# Mine\n" {
+		t.Error("expected the existing note to be left alone")
+	}
+	if _, err := os.Stat(filepath.Join(vaultRoot, "Resources/Old vault/.obsidian")); err == nil {
+		t.Error("expected hidden folders to be skipped")
+	}
+}
+
+func TestImport_Notion(t *testing.T) {
+	vaultRoot := t.TempDir()
+	src := filepath.Join(t.TempDir(), "Export.zip")
+	f, err := os.Create(src)
+	if err != nil {
+		t.Fatalf("failed to create zip: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range map[string]string{
+		"Garden 0123456789abcdef0123456789abcdef.md":                                       "# Garden\n\n[Beds](Garden%200123456789abcdef0123456789abcdef/Beds%20fedcba9876543210fedcba9876543210.md)\n",
+		"Garden 0123456789abcdef0123456789abcdef/Beds fedcba9876543210fedcba9876543210.md": "# Beds\n\nNorth side.\n",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to add %s: %v", name, err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+	f.Close()
+
+	if source, err := Detect(src); err != nil || source != SourceNotion {
+		t.Errorf("expected a notion export, got %q (%v)", source, err)
+	}
+	result, err := Import(vaultRoot, src, Options{Folder: "Projects"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	expected := []string{"Projects/Garden.md", "Projects/Garden/Beds.md"}
+	if !reflect.DeepEqual(result.Notes, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Notes)
+	}
+	garden := readFile(t, vaultRoot, "Projects/Garden.md")
+	if !strings.Contains(garden, "[Beds](<Garden/Beds.md>)") || !strings.Contains(garden, "source: notion") {
+		t.Errorf("expected the link to lose the page ids, got:\n%s", garden)
+	}
+}
+
+const enex = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE en-export SYSTEM "http://xml.evernote.com/pub/evernote-export3.dtd">
+<en-export export-date="20240105T120000Z" application="Evernote" version="10.0">
+<note>
+<title>Groceries</title>
+<created>20240101T093000Z</created>
+<updated>20240102T100000Z</updated>
+<tag>shopping</tag>
+<content><![CDATA[<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<!DOCTYPE en-note SYSTEM "http://xml.evernote.com/pub/enml2.dtd">
+<en-note><div>Buy <b>milk</b>&nbsp;and eggs.</div><div><en-todo checked="true"/>Bread</div>
+<en-media hash="e2fc714c4727ee9395f324cd2e7f331f" type="image/png"/>
+<div>See <a href="evernote:///view/1/s1/abc/abc/">Meal plan</a>.</div></en-note>]]></content>
+<resource>
+<data encoding="base64">YWJjZA==</data>
+<mime>image/png</mime>
+<resource-attributes><file-name>list.png</file-name></resource-attributes>
+</resource>
+</note>
+<note>
+<title>Meal plan</title>
+<created>20240103T093000Z</created>
+<content><![CDATA[<en-note><h1>Week 1</h1><ul><li><div>Soup</div></li><li>Pasta</li></ul></en-note>]]></content>
+</note>
+</en-export>
+`
+
+func TestImport_Evernote(t *testing.T) {
+	vaultRoot := t.TempDir()
+	src := filepath.Join(t.TempDir(), "Notebook.enex")
+	writeFile(t, filepath.Dir(src), "Notebook.enex", enex)
+
+	result, err := Import(vaultRoot, src, Options{Folder: "Resources/Evernote"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	expected := &Result{
+		Source:      SourceEvernote,
+		Notes:       []string{"Resources/Evernote/Groceries.md", "Resources/Evernote/Meal plan.md"},
+		Attachments: []string{"Resources/Evernote/Attachments/list.png"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+
+	groceries := readFile(t, vaultRoot, "Resources/Evernote/Groceries.md")
+	expectedNote := "---\ntitle: \"Groceries\"\ncreated: 2024-01-01T09:30:00Z\nupdated: 2024-01-02T10:00:00Z\nsource: evernote\ntags: [shopping]\n---\n\n" +
+		"Buy **milk** and eggs.\n\n- [x] Bread\n\n![](<Attachments/list.png>)\n\nSee [[Meal plan]].\n"
+	if groceries != expectedNote {
+		t.Errorf("expected:\n%q\ngot:\n%q", expectedNote, groceries)
+	}
+	if readFile(t, vaultRoot, "Resources/Evernote/Attachments/list.png") != "abcd" {
+		t.Error("expected the resource to be decoded")
+	}
+	meals := readFile(t, vaultRoot, "Resources/Evernote/Meal plan.md")
+	if !strings.HasSuffix(meals, "# Week 1\n\n- Soup\n- Pasta\n") {
+		t.Errorf("expected the list to be converted, got:\n%q", meals)
+	}
+}
+
+func TestImport_DryRun(t *testing.T) {
+	vaultRoot := t.TempDir()
+	src := t.TempDir()
+	writeFile(t, src, "memo.md", "# Memo\n")
+
+	result, err := Import(vaultRoot, src, Options{Folder: "Inbox", DryRun: true})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(result.Notes) != 1 || result.Notes[0] != "Inbox/memo.md" {
+		t.Errorf("expected the plan to list Inbox/memo.md, got %v", result.Notes)
+	}
+	if _, err := os.Stat(filepath.Join(vaultRoot, "Inbox")); err == nil {
+		t.Error("expected nothing to be written")
+	}
+}
+
+func TestImport_Errors(t *testing.T) {
+	vaultRoot := t.TempDir()
+	src := t.TempDir()
+	writeFile(t, src, "photo.png", "png")
+
+	for _, folder := range []string{"Recipes", "Projects/../..", ""} {
+		if _, err := Import(vaultRoot, src, Options{Folder: folder}); !errors.Is(err, ErrNotParaFolder) {
+			t.Errorf("folder %q: expected ErrNotParaFolder, got: %v", folder, err)
+		}
+	}
+	if _, err := Import(vaultRoot, src, Options{Folder: "Inbox"}); !errors.Is(err, ErrNothingToWrite) {
+		t.Errorf("expected ErrNothingToWrite, got: %v", err)
+	}
+	if _, err := Import(vaultRoot, src, Options{Folder: "Inbox", Source: "onenote"}); !errors.Is(err, ErrInvalidSource) {
+		t.Errorf("expected ErrInvalidSource, got: %v", err)
+	}
+}
+
+func TestCleanName(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   Source
+		expected string
+	}{
+		{"Garden plan", SourceMarkdown, "Garden plan"},
+		{"Q1: goals / review?", SourceMarkdown, "Q1 goals review"},
+		{"Garden 0123456789abcdef0123456789abcdef", SourceNotion, "Garden"},
+		{"Garden 0123456789abcdef0123456789abcdef", SourceMarkdown, "Garden 0123456789abcdef0123456789abcdef"},
+		{"...", SourceMarkdown, "Untitled"},
+		{strings.Repeat("é", 60), SourceMarkdown, strings.Repeat("é", 50)},
+	}
+	for _, tt := range tests {
+		if got := cleanName(tt.name, tt.source); got != tt.expected {
+			t.Errorf("cleanName(%q): expected %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+}
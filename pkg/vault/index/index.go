@@ -0,0 +1,393 @@
+// Package index lists the notes in a vault with the details tools look
+// notes up by, such as their titles. The index is saved in the vault's
+// .nota directory and brought up to date incrementally, re-reading only the
+// notes that changed.
+package index
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/frontmatter"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/ignore"
+)
+
+// NoteExt is the extension of the files indexed as notes.
+const NoteExt = ".md"
+
+// FileName is the saved index's file name in the vault's .nota directory.
+const FileName = "index.json"
+
+// fileVersion is the layout version of the saved index. A saved index with
+// another version is rebuilt.
+const fileVersion = 1
+
+// Note is an indexed note.
+type Note struct {
+	// Path is the note's path relative to the vault root, with forward
+	// slashes, e.g. "Projects/garden.md".
+	Path string `json:"path"`
+	// Title is the note's frontmatter title, else its first top-level
+	// heading, else its file name without extension.
+	Title   string    `json:"title"`
+	ModTime time.Time `json:"mod_time"`
+	Size    int64     `json:"size"`
+}
+
+// Name returns the note's file name without extension, the name wikilinks
+// refer to it by.
+func (n Note) Name() string {
+	return strings.TrimSuffix(path.Base(n.Path), NoteExt)
+}
+
+// Index is the set of notes in a vault. It is safe for concurrent use.
+type Index struct {
+	root string
+
+	mu      sync.RWMutex
+	notes   map[string]Note
+	ignored *ignore.Matcher
+}
+
+// New creates an index of the vault at root holding notes.
+func New(root string, notes ...Note) *Index {
+	ix := &Index{root: root, notes: make(map[string]Note, len(notes))}
+	for _, n := range notes {
+		ix.notes[n.Path] = n
+	}
+	return ix
+}
+
+// Build indexes every note in the vault at root. Hidden files and
+// directories, including .nota, are skipped, as are the paths the vault's
+// .notaignore lists.
+func Build(root string) (*Index, error) {
+	ix := New(root)
+	if _, err := ix.Refresh(); err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// Path returns the saved index's path in the vault at root.
+func Path(root string) string {
+	return filepath.Join(root, vault.VaultMarkerDir, FileName)
+}
+
+// savedIndex is the layout of the saved index.
+type savedIndex struct {
+	Version int    `json:"version"`
+	Notes   []Note `json:"notes"`
+}
+
+// Load reads the index saved in the vault at root, as it was when saved.
+// Returns an error wrapping fs.ErrNotExist if there is none.
+func Load(root string) (*Index, error) {
+	data, err := os.ReadFile(Path(root))
+	if err != nil {
+		return nil, err
+	}
+	var saved savedIndex
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", Path(root), err)
+	}
+	if saved.Version != fileVersion {
+		return nil, fmt.Errorf("%s has unsupported version %d", Path(root), saved.Version)
+	}
+	return New(root, saved.Notes...), nil
+}
+
+// Open returns the index of the vault at root: the saved index brought up
+// to date with Refresh, or one built from scratch when none is saved or it
+// can't be read. The result is not saved.
+func Open(root string) (*Index, error) {
+	ix, err := Load(root)
+	if err != nil {
+		return Build(root)
+	}
+	if _, err := ix.Refresh(); err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// Save writes the index to the vault's .nota directory, replacing the
+// saved index atomically.
+func (ix *Index) Save() error {
+	data, err := json.MarshalIndent(savedIndex{Version: fileVersion, Notes: ix.Notes()}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	p := Path(ix.root)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// Refresh brings the index up to date with the vault, re-reading only the
+// notes whose size or modification time changed, and returns the paths of
+// the notes added, changed or removed. .notaignore is re-read too.
+func (ix *Index) Refresh() ([]string, error) {
+	ignored, err := ignore.Load(ix.root)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var changed []string
+	err = filepath.WalkDir(ix.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == ix.root {
+			return nil
+		}
+		rel, _ := filepath.Rel(ix.root, p)
+		if skip(rel, d.IsDir(), ignored) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel = filepath.ToSlash(rel)
+		seen[rel] = true
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if n, ok := ix.Lookup(rel); ok && unchanged(n, info) {
+			return nil
+		}
+		note, err := ix.read(rel)
+		if err != nil {
+			return err
+		}
+		ix.mu.Lock()
+		ix.notes[rel] = note
+		ix.mu.Unlock()
+		changed = append(changed, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.ignored = ignored
+	for p := range ix.notes {
+		if !seen[p] {
+			delete(ix.notes, p)
+			changed = append(changed, p)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// Update re-reads the note at path, relative to the vault root, adding it
+// to the index, or dropping it when it no longer exists or is no longer
+// indexed. It reports whether the index changed.
+func (ix *Index) Update(path string) (bool, error) {
+	path = filepath.ToSlash(filepath.Clean(path))
+	ix.mu.RLock()
+	ignored := ix.ignored
+	old, had := ix.notes[path]
+	ix.mu.RUnlock()
+
+	info, err := os.Stat(filepath.Join(ix.root, filepath.FromSlash(path)))
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	if err != nil || info.IsDir() || hiddenParent(path) || skip(filepath.FromSlash(path), false, ignored) {
+		if !had {
+			return false, nil
+		}
+		ix.Remove(path)
+		return true, nil
+	}
+	if had && unchanged(old, info) {
+		return false, nil
+	}
+
+	note, err := ix.read(path)
+	if err != nil {
+		return false, err
+	}
+	ix.mu.Lock()
+	ix.notes[path] = note
+	ix.mu.Unlock()
+	return true, nil
+}
+
+// Remove drops the note at path, or every note below path when it is a
+// directory, and returns the paths dropped.
+func (ix *Index) Remove(path string) []string {
+	path = filepath.ToSlash(filepath.Clean(path))
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	var removed []string
+	for p := range ix.notes {
+		if p == path || strings.HasPrefix(p, path+"/") {
+			delete(ix.notes, p)
+			removed = append(removed, p)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// unchanged reports whether the file info matches the indexed note.
+func unchanged(n Note, info fs.FileInfo) bool {
+	return n.ModTime.Equal(info.ModTime()) && n.Size == info.Size()
+}
+
+// skip reports whether the path rel, relative to the vault root, is left
+// out of the index: hidden files and directories, the paths .notaignore
+// lists, and files that are not notes.
+func skip(rel string, isDir bool, ignored *ignore.Matcher) bool {
+	if strings.HasPrefix(filepath.Base(rel), ".") || ignored.Match(rel, isDir) {
+		return true
+	}
+	return !isDir && !strings.EqualFold(filepath.Ext(rel), NoteExt)
+}
+
+// hiddenParent reports whether any directory in the slash-separated path
+// is hidden.
+func hiddenParent(p string) bool {
+	dirs := strings.Split(path.Dir(p), "/")
+	for _, dir := range dirs {
+		if strings.HasPrefix(dir, ".") && dir != "." {
+			return true
+		}
+	}
+	return false
+}
+
+// read reads the note at rel, relative to the vault root.
+func (ix *Index) read(rel string) (Note, error) {
+	p := filepath.Join(ix.root, filepath.FromSlash(rel))
+	info, err := os.Stat(p)
+	if err != nil {
+		return Note{}, err
+	}
+	content, err := os.ReadFile(p)
+	if err != nil {
+		return Note{}, err
+	}
+
+	note := Note{Path: rel, ModTime: info.ModTime(), Size: info.Size()}
+	note.Title = Title(string(content))
+	if note.Title == "" {
+		note.Title = note.Name()
+	}
+	return note, nil
+}
+
+// Root returns the vault root the index was built for.
+func (ix *Index) Root() string {
+	return ix.root
+}
+
+// Len returns the number of indexed notes.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.notes)
+}
+
+// Notes returns the indexed notes, sorted by path.
+func (ix *Index) Notes() []Note {
+	ix.mu.RLock()
+	notes := make([]Note, 0, len(ix.notes))
+	for _, n := range ix.notes {
+		notes = append(notes, n)
+	}
+	ix.mu.RUnlock()
+	sort.Slice(notes, func(i, j int) bool { return notes[i].Path < notes[j].Path })
+	return notes
+}
+
+// Lookup returns the note at path, relative to the vault root.
+func (ix *Index) Lookup(path string) (Note, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	n, ok := ix.notes[path]
+	return n, ok
+}
+
+// Find returns the notes query refers to: the note at that path relative to
+// the vault root, with or without its extension, else the notes whose title
+// or name equals query ignoring case, else those whose title or name
+// contains it ignoring case. The result is sorted by path and empty when
+// nothing matches.
+func (ix *Index) Find(query string) []Note {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+	rel := strings.TrimPrefix(filepath.ToSlash(query), "./")
+	for _, p := range []string{rel, rel + NoteExt} {
+		if n, ok := ix.Lookup(p); ok {
+			return []Note{n}
+		}
+	}
+
+	lower := strings.ToLower(query)
+	var exact, partial []Note
+	for _, n := range ix.Notes() {
+		title, name := strings.ToLower(n.Title), strings.ToLower(n.Name())
+		switch {
+		case title == lower || name == lower:
+			exact = append(exact, n)
+		case strings.Contains(title, lower) || strings.Contains(name, lower):
+			partial = append(partial, n)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return partial
+}
+
+// Title returns a note's frontmatter title, else its first top-level
+// heading, or "" if it has neither.
+func Title(content string) string {
+	if title, ok := frontmatter.Get(content, "title"); ok && title != "" {
+		return title
+	}
+	_, body, ok := frontmatter.Split(content)
+	if !ok {
+		body = content
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if heading, found := strings.CutPrefix(line, "# "); found {
+			return strings.TrimSpace(heading)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,197 @@
+package index
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeNote(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	root := t.TempDir()
+	writeNote(t, root, "Inbox/memo.md", "---\ntitle: \"Call the dentist\"\n---\n# Voice Note\n")
+	writeNote(t, root, "Projects/garden.md", "Intro\n\n# Garden plan\n")
+	writeNote(t, root, "Areas/untitled.md", "Just text\n")
+	writeNote(t, root, "Areas/photo.png", "not a note")
+	writeNote(t, root, ".nota/templates/meeting.md", "# Meeting\n")
+	writeNote(t, root, ".trash/old.md", "# Old\n")
+	writeNote(t, root, "Projects/site/node_modules/pkg/README.md", "# Package\n")
+	writeNote(t, root, ".notaignore", "node_modules/\n")
+
+	ix, err := Build(root)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	expected := map[string]string{
+		"Areas/untitled.md":  "untitled",
+		"Inbox/memo.md":      "Call the dentist",
+		"Projects/garden.md": "Garden plan",
+	}
+	notes := ix.Notes()
+	if len(notes) != len(expected) {
+		t.Fatalf("expected %d notes, got %+v", len(expected), notes)
+	}
+	for _, n := range notes {
+		if n.Title != expected[n.Path] {
+			t.Errorf("%s: expected title %q, got %q", n.Path, expected[n.Path], n.Title)
+		}
+	}
+	if _, ok := ix.Lookup("Inbox/memo.md"); !ok {
+		t.Error("expected to look up Inbox/memo.md")
+	}
+}
+
+func TestOpen_RefreshesSavedIndex(t *testing.T) {
+	root := t.TempDir()
+	writeNote(t, root, "Inbox/memo.md", "# Memo\n")
+	writeNote(t, root, "Projects/garden.md", "# Garden\n")
+	writeNote(t, root, "Areas/old.md", "# Old\n")
+
+	ix, err := Build(root)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := ix.Save(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	writeNote(t, root, "Projects/garden.md", "# Garden plan\n")
+	writeNote(t, root, "Inbox/new.md", "# New\n")
+	if err := os.Remove(filepath.Join(root, "Areas/old.md")); err != nil {
+		t.Fatalf("failed to remove note: %v", err)
+	}
+
+	saved, err := Load(root)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if saved.Len() != 3 {
+		t.Errorf("expected the saved index to hold 3 notes, got %d", saved.Len())
+	}
+	changed, err := saved.Refresh()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	expected := []string{"Areas/old.md", "Inbox/new.md", "Projects/garden.md"}
+	if strings.Join(changed, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected changed %v, got %v", expected, changed)
+	}
+	if n, _ := saved.Lookup("Projects/garden.md"); n.Title != "Garden plan" {
+		t.Errorf("expected the changed title to be re-read, got %q", n.Title)
+	}
+
+	reopened, err := Open(root)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if reopened.Len() != 3 {
+		t.Errorf("expected 3 notes, got %+v", reopened.Notes())
+	}
+}
+
+func TestOpen_WithoutSavedIndex(t *testing.T) {
+	root := t.TempDir()
+	writeNote(t, root, "memo.md", "# Memo\n")
+
+	if _, err := Load(root); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected a not-exist error, got: %v", err)
+	}
+	ix, err := Open(root)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if ix.Len() != 1 {
+		t.Errorf("expected 1 note, got %+v", ix.Notes())
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	root := t.TempDir()
+	writeNote(t, root, ".notaignore", "Archive/\n")
+	ix, err := Build(root)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		setup    func()
+		path     string
+		expected bool
+		indexed  bool
+	}{
+		{"added", func() { writeNote(t, root, "Inbox/memo.md", "# Memo\n") }, "Inbox/memo.md", true, true},
+		{"unchanged", func() {}, "Inbox/memo.md", false, true},
+		{"ignored", func() { writeNote(t, root, "Archive/old.md", "# Old\n") }, "Archive/old.md", false, false},
+		{"hidden", func() { writeNote(t, root, ".trash/old.md", "# Old\n") }, ".trash/old.md", false, false},
+		{"not a note", func() { writeNote(t, root, "photo.png", "png") }, "photo.png", false, false},
+		{"removed", func() { os.Remove(filepath.Join(root, "Inbox/memo.md")) }, "Inbox/memo.md", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			changed, err := ix.Update(tt.path)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if changed != tt.expected {
+				t.Errorf("expected changed %v, got %v", tt.expected, changed)
+			}
+			if _, ok := ix.Lookup(tt.path); ok != tt.indexed {
+				t.Errorf("expected indexed %v, got %v", tt.indexed, ok)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	ix := New(t.TempDir(),
+		Note{Path: "Areas/Journal/2026-01-22.md", Title: "Thursday"},
+		Note{Path: "Inbox/memo.md", Title: "Call the dentist"},
+		Note{Path: "Projects/garden.md", Title: "Garden plan"},
+		Note{Path: "Projects/garden-shed.md", Title: "Garden shed"},
+	)
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"Inbox/memo.md", []string{"Inbox/memo.md"}},
+		{"Projects/garden", []string{"Projects/garden.md"}},
+		{"2026-01-22", []string{"Areas/Journal/2026-01-22.md"}},
+		{"call the DENTIST", []string{"Inbox/memo.md"}},
+		{"garden plan", []string{"Projects/garden.md"}},
+		{"garden", []string{"Projects/garden.md"}},
+		{"shed", []string{"Projects/garden-shed.md"}},
+		{"gard", []string{"Projects/garden-shed.md", "Projects/garden.md"}},
+		{"recipes", nil},
+		{" ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got []string
+			for _, n := range ix.Find(tt.query) {
+				got = append(got, n.Path)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
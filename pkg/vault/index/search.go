@@ -0,0 +1,146 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/crypt"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/ignore"
+)
+
+// ErrNotDecrypted is returned by Search, along with the matches, when some
+// encrypted notes could not be decrypted.
+var ErrNotDecrypted = errors.New("some encrypted notes could not be decrypted")
+
+// Match is a note containing a search query.
+type Match struct {
+	Note
+	// Line is the number of the first line containing the query, counting
+	// from 1, or 0 when only the title does. Text is that line.
+	Line int    `json:"line,omitempty"`
+	Text string `json:"text,omitempty"`
+	// Encrypted is set for encrypted notes, which were decrypted to search.
+	Encrypted bool `json:"encrypted,omitempty"`
+}
+
+// Search returns the indexed notes whose title or content contains query,
+// ignoring case, sorted by path.
+//
+// With identities, encrypted notes (garden.md.age) are decrypted with
+// crypt.ReadNote and searched too. They are only decrypted in memory and
+// never added to the index, whose saved copy syncs with the vault. Notes
+// that fail to decrypt, such as those encrypted to other recipients, are
+// left out and reported in an error wrapping ErrNotDecrypted, returned
+// alongside the matches.
+func (ix *Index) Search(query string, identities ...age.Identity) ([]Match, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil, nil
+	}
+
+	var matches []Match
+	for _, n := range ix.Notes() {
+		content, err := os.ReadFile(filepath.Join(ix.root, filepath.FromSlash(n.Path)))
+		if errors.Is(err, fs.ErrNotExist) {
+			// Removed since the index was refreshed
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if m, ok := match(n, string(content), query); ok {
+			matches = append(matches, m)
+		}
+	}
+	if len(identities) == 0 {
+		return matches, nil
+	}
+
+	encrypted, err := ix.encryptedNotes()
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, n := range encrypted {
+		plain, err := crypt.ReadNote(filepath.Join(ix.root, filepath.FromSlash(n.Path)), identities...)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		n.Title = Title(string(plain))
+		if n.Title == "" {
+			n.Title = strings.TrimSuffix(n.Name(), NoteExt+crypt.Ext)
+		}
+		if m, ok := match(n, string(plain), query); ok {
+			m.Encrypted = true
+			matches = append(matches, m)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Path < matches[j].Path })
+	if len(errs) > 0 {
+		return matches, fmt.Errorf("%w: %w", ErrNotDecrypted, errors.Join(errs...))
+	}
+	return matches, nil
+}
+
+// match reports whether the note n with content contains the lowercase
+// query in its title or a line.
+func match(n Note, content, query string) (Match, bool) {
+	m := Match{Note: n}
+	for i, line := range strings.Split(content, "\n") {
+		if strings.Contains(strings.ToLower(line), query) {
+			m.Line, m.Text = i+1, strings.TrimSpace(line)
+			return m, true
+		}
+	}
+	return m, strings.Contains(strings.ToLower(n.Title), query)
+}
+
+// encryptedNotes lists the vault's encrypted notes, skipping the same
+// hidden and ignored paths as the index.
+func (ix *Index) encryptedNotes() ([]Note, error) {
+	ignored, err := ignore.Load(ix.root)
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []Note
+	err = filepath.WalkDir(ix.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == ix.root {
+			return nil
+		}
+		rel, _ := filepath.Rel(ix.root, p)
+		if d.IsDir() {
+			if skip(rel, true, ignored) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !isEncryptedNote(rel) ||
+			strings.HasPrefix(filepath.Base(rel), ".") || ignored.Match(rel, false) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		notes = append(notes, Note{Path: filepath.ToSlash(rel), ModTime: info.ModTime(), Size: info.Size()})
+		return nil
+	})
+	return notes, err
+}
+
+// isEncryptedNote reports whether the file at rel is a note encrypted with
+// crypt, such as garden.md.age.
+func isEncryptedNote(rel string) bool {
+	return crypt.IsEncrypted(rel) && strings.EqualFold(filepath.Ext(strings.TrimSuffix(rel, crypt.Ext)), NoteExt)
+}
@@ -0,0 +1,114 @@
+package index
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/crypt"
+)
+
+func writeEncryptedNote(t *testing.T, root, rel, content string, recipient age.Recipient) {
+	t.Helper()
+	writeNote(t, root, rel, content)
+	if _, err := crypt.EncryptFile(filepath.Join(root, rel), recipient); err != nil {
+		t.Fatalf("failed to encrypt note: %v", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	root := t.TempDir()
+	writeNote(t, root, "Inbox/memo.md", "# Voice Note\n\nCall the Dentist on Monday.\n")
+	writeNote(t, root, "Projects/dentist.md", "# Dentist\n\nNothing here.\n")
+	writeNote(t, root, "Projects/garden.md", "# Garden plan\n")
+
+	ix, err := Build(root)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	matches, err := ix.Search("dentist")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected two matches, got %+v", matches)
+	}
+	if m := matches[0]; m.Path != "Inbox/memo.md" || m.Line != 3 || m.Text != "Call the Dentist on Monday." {
+		t.Errorf("unexpected content match %+v", m)
+	}
+	if m := matches[1]; m.Path != "Projects/dentist.md" || m.Line != 1 {
+		t.Errorf("unexpected heading match %+v", m)
+	}
+
+	if matches, _ := ix.Search("  "); matches != nil {
+		t.Errorf("expected no matches for an empty query, got %+v", matches)
+	}
+}
+
+func TestSearch_IncludeEncrypted(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("failed to generate identity: %v", err)
+	}
+	root := t.TempDir()
+	writeNote(t, root, "Inbox/memo.md", "# Memo\n\nAsk about the blood test.\n")
+	writeEncryptedNote(t, root, "Areas/Medical/results.md", "# Results\n\nBlood test: normal.\n", identity.Recipient())
+	writeEncryptedNote(t, root, ".trash/old.md", "# Old blood test\n", identity.Recipient())
+
+	ix, err := Build(root)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	matches, err := ix.Search("blood test")
+	if err != nil || len(matches) != 1 || matches[0].Path != "Inbox/memo.md" {
+		t.Errorf("expected encrypted notes left out without identities, got %+v, %v", matches, err)
+	}
+
+	matches, err = ix.Search("blood test", identity)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected the encrypted note found too, got %+v", matches)
+	}
+	m := matches[0]
+	if m.Path != "Areas/Medical/results.md.age" || !m.Encrypted || m.Title != "Results" || m.Text != "Blood test: normal." {
+		t.Errorf("unexpected encrypted match %+v", m)
+	}
+
+	// Nothing decrypted reaches the index or its saved copy
+	if _, ok := ix.Lookup(m.Path); ok {
+		t.Error("expected the encrypted note kept out of the index")
+	}
+	if err := ix.Save(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	saved, _ := os.ReadFile(Path(root))
+	if strings.Contains(string(saved), "Results") || strings.Contains(string(saved), "normal") {
+		t.Errorf("expected no plaintext in the saved index, got:\n%s", saved)
+	}
+}
+
+func TestSearch_ReportsUndecryptableNotes(t *testing.T) {
+	identity, _ := age.GenerateX25519Identity()
+	other, _ := age.GenerateX25519Identity()
+	root := t.TempDir()
+	writeNote(t, root, "memo.md", "# Memo\n\nBlood test booked.\n")
+	writeEncryptedNote(t, root, "results.md", "# Blood test\n", other.Recipient())
+
+	ix, err := Build(root)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	matches, err := ix.Search("blood", identity)
+	if !errors.Is(err, ErrNotDecrypted) {
+		t.Errorf("expected ErrNotDecrypted, got: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Path != "memo.md" {
+		t.Errorf("expected the other matches still returned, got %+v", matches)
+	}
+}
@@ -0,0 +1,251 @@
+package index
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/ignore"
+	"golang.org/x/sys/unix"
+)
+
+// watchMask is the set of inotify events that can change the index.
+const watchMask = unix.IN_CLOSE_WRITE | unix.IN_CREATE | unix.IN_DELETE |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_ONLYDIR
+
+// Watch keeps the index up to date with the vault until ctx is done,
+// watching every indexed directory and re-reading only the notes that
+// change. After each batch of changes onChange is called with the paths of
+// the notes added, changed or removed; it runs on the watching goroutine.
+// Editing .notaignore re-applies it to the whole vault.
+func (ix *Index) Watch(ctx context.Context, onChange func(paths []string)) error {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+
+	tw := &treeWatch{ix: ix, fd: fd, dirs: make(map[int]string), changed: make(map[string]bool)}
+	// Notes are re-read as their directories are watched, so changes made
+	// while the watches are being added are not missed
+	if err := tw.refresh(); err != nil {
+		return err
+	}
+	tw.flush(onChange)
+
+	buf := make([]byte, 64*1024)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		n, err := unix.Read(fd, buf)
+		if err != nil {
+			if err == unix.EAGAIN || err == unix.EWOULDBLOCK || err == unix.EINTR {
+				// No events available, sleep briefly and retry
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		for _, event := range parseEvents(buf[:n]) {
+			if err := tw.handle(event); err != nil {
+				return err
+			}
+		}
+		tw.flush(onChange)
+	}
+}
+
+// treeWatch is the state of a running Watch.
+type treeWatch struct {
+	ix *Index
+	fd int
+	// dirs maps watch descriptors to directories relative to the vault
+	// root, "" being the root itself.
+	dirs map[int]string
+	// changed collects the paths changed since the last flush.
+	changed map[string]bool
+}
+
+// handle applies one event to the index.
+func (tw *treeWatch) handle(event inotifyEvent) error {
+	if event.mask&unix.IN_Q_OVERFLOW != 0 {
+		// The kernel dropped events; catch up by rescanning the vault
+		return tw.refresh()
+	}
+	if event.mask&unix.IN_IGNORED != 0 {
+		delete(tw.dirs, event.wd)
+		return nil
+	}
+	dir, ok := tw.dirs[event.wd]
+	if !ok || event.name == "" {
+		return nil
+	}
+	rel := path.Join(dir, event.name)
+
+	switch {
+	case rel == ignore.FileName:
+		return tw.refresh()
+	case event.mask&unix.IN_ISDIR != 0:
+		if event.mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 {
+			return tw.add(rel)
+		}
+		tw.unwatch(rel)
+		tw.mark(tw.ix.Remove(rel)...)
+		return nil
+	default:
+		changed, err := tw.ix.Update(rel)
+		if err != nil {
+			// The note may have gone again before it could be read
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if changed {
+			tw.mark(rel)
+		}
+		return nil
+	}
+}
+
+// refresh rescans the whole vault, watching any directories not yet
+// watched.
+func (tw *treeWatch) refresh() error {
+	changed, err := tw.ix.Refresh()
+	if err != nil {
+		return err
+	}
+	tw.mark(changed...)
+	return tw.add("")
+}
+
+// add watches the directory rel and every indexed directory below it,
+// indexing the notes found in them.
+func (tw *treeWatch) add(rel string) error {
+	root := filepath.Join(tw.ix.root, filepath.FromSlash(rel))
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// A directory removed while being walked is reported by its
+			// own event
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		r, _ := filepath.Rel(tw.ix.root, p)
+		if p != tw.ix.root && tw.ix.skipped(r, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		r = filepath.ToSlash(r)
+		if !d.IsDir() {
+			changed, err := tw.ix.Update(r)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			if changed {
+				tw.mark(r)
+			}
+			return nil
+		}
+
+		wd, err := unix.InotifyAddWatch(tw.fd, p, watchMask)
+		if err != nil {
+			if err == unix.ENOENT || err == unix.ENOTDIR {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if r == "." {
+			r = ""
+		}
+		tw.dirs[wd] = r
+		return nil
+	})
+}
+
+// unwatch stops watching the directory rel and the directories below it.
+func (tw *treeWatch) unwatch(rel string) {
+	for wd, dir := range tw.dirs {
+		if dir == rel || strings.HasPrefix(dir, rel+"/") {
+			unix.InotifyRmWatch(tw.fd, uint32(wd))
+			delete(tw.dirs, wd)
+		}
+	}
+}
+
+// mark records changed paths for the next flush.
+func (tw *treeWatch) mark(paths ...string) {
+	for _, p := range paths {
+		tw.changed[p] = true
+	}
+}
+
+// flush passes the paths changed since the last flush to onChange.
+func (tw *treeWatch) flush(onChange func(paths []string)) {
+	if len(tw.changed) == 0 {
+		return
+	}
+	paths := make([]string, 0, len(tw.changed))
+	for p := range tw.changed {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	clear(tw.changed)
+	if onChange != nil {
+		onChange(paths)
+	}
+}
+
+// skipped reports whether the path rel, relative to the vault root, is
+// left out of the index under the .notaignore last read.
+func (ix *Index) skipped(rel string, isDir bool) bool {
+	ix.mu.RLock()
+	ignored := ix.ignored
+	ix.mu.RUnlock()
+	return skip(rel, isDir, ignored)
+}
+
+// inotifyEvent is one event read from the inotify descriptor.
+type inotifyEvent struct {
+	wd   int
+	mask uint32
+	// name is the file the event is about, or "" for events on the watched
+	// directory itself.
+	name string
+}
+
+// parseEvents decodes the events in buf, as filled by a read from an
+// inotify descriptor. A truncated trailing event is dropped.
+func parseEvents(buf []byte) []inotifyEvent {
+	var events []inotifyEvent
+	for offset := 0; offset+unix.SizeofInotifyEvent <= len(buf); {
+		raw := buf[offset:]
+		nameLen := int(binary.NativeEndian.Uint32(raw[12:16]))
+		end := unix.SizeofInotifyEvent + nameLen
+		if end > len(raw) {
+			break
+		}
+		events = append(events, inotifyEvent{
+			wd:   int(int32(binary.NativeEndian.Uint32(raw[0:4]))),
+			mask: binary.NativeEndian.Uint32(raw[4:8]),
+			// The name is padded with NULs to an alignment boundary
+			name: strings.TrimRight(string(raw[unix.SizeofInotifyEvent:end]), "\x00"),
+		})
+		offset += end
+	}
+	return events
+}
@@ -0,0 +1,95 @@
+package index
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// waitFor waits for onChange to report path.
+func waitFor(t *testing.T, changes <-chan []string, path string) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case paths := <-changes:
+			if slices.Contains(paths, path) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s to change", path)
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	root := t.TempDir()
+	writeNote(t, root, "Inbox/memo.md", "# Memo\n")
+	ix, err := Build(root)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan []string, 100)
+	done := make(chan error, 1)
+	go func() {
+		done <- ix.Watch(ctx, func(paths []string) { changes <- paths })
+	}()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+	}()
+
+	// Wait for the watches to be in place
+	writeNote(t, root, "ready.md", "# Ready\n")
+	waitFor(t, changes, "ready.md")
+
+	t.Run("edited", func(t *testing.T) {
+		writeNote(t, root, "Inbox/memo.md", "# Call the dentist\n")
+		waitFor(t, changes, "Inbox/memo.md")
+		if n, _ := ix.Lookup("Inbox/memo.md"); n.Title != "Call the dentist" {
+			t.Errorf("expected the title to be re-read, got %q", n.Title)
+		}
+	})
+
+	t.Run("new directory", func(t *testing.T) {
+		writeNote(t, root, "Projects/garden/plan.md", "# Plan\n")
+		waitFor(t, changes, "Projects/garden/plan.md")
+		writeNote(t, root, "Projects/garden/later.md", "# Later\n")
+		waitFor(t, changes, "Projects/garden/later.md")
+	})
+
+	t.Run("renamed", func(t *testing.T) {
+		if err := os.Rename(filepath.Join(root, "Inbox/memo.md"), filepath.Join(root, "Inbox/dentist.md")); err != nil {
+			t.Fatalf("failed to rename note: %v", err)
+		}
+		waitFor(t, changes, "Inbox/dentist.md")
+		if _, ok := ix.Lookup("Inbox/memo.md"); ok {
+			t.Error("expected the old path to be dropped")
+		}
+	})
+
+	t.Run("directory removed", func(t *testing.T) {
+		if err := os.RemoveAll(filepath.Join(root, "Projects")); err != nil {
+			t.Fatalf("failed to remove directory: %v", err)
+		}
+		waitFor(t, changes, "Projects/garden/plan.md")
+		if _, ok := ix.Lookup("Projects/garden/later.md"); ok {
+			t.Error("expected the notes below the directory to be dropped")
+		}
+	})
+
+	t.Run("notaignore", func(t *testing.T) {
+		writeNote(t, root, ".notaignore", "Inbox/\n")
+		waitFor(t, changes, "Inbox/dentist.md")
+		if _, ok := ix.Lookup("Inbox/dentist.md"); ok {
+			t.Error("expected the ignored note to be dropped")
+		}
+	})
+}
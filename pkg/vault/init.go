@@ -32,7 +32,8 @@ var (
 )
 
 // Init initializes a new vault at the given path with the specified name.
-// It creates the .nota directory, vault.json metadata file, and PARA+ folders.
+// It creates the .nota directory, vault.json metadata file, PARA+ folders and
+// starter templates in .nota/templates.
 // Existing folders with matching names (case-insensitive) are skipped.
 func Init(path, name string) error {
 	if name == "" {
@@ -84,6 +85,11 @@ func Init(path, name string) error {
 		}
 	}
 
+	// Create starter templates
+	if _, err := InitTemplates(path); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -116,3 +122,26 @@ func folderExistsCaseInsensitive(name string, existingFolders []string) bool {
 	}
 	return false
 }
+
+// ParaFolder returns the PARA+ folder matching name, ignoring case, spelled
+// as it is in the vault at root when the vault has it. ok is false if name
+// is not a PARA+ folder.
+func ParaFolder(root, name string) (folder string, ok bool) {
+	for _, f := range paraFolders {
+		if strings.EqualFold(f, name) {
+			folder, ok = f, true
+			break
+		}
+	}
+	if !ok {
+		return "", false
+	}
+
+	existing, _ := getExistingFolders(root)
+	for _, e := range existing {
+		if strings.EqualFold(e, folder) {
+			return e, true
+		}
+	}
+	return folder, true
+}
@@ -165,3 +165,26 @@ func TestInit_VaultJsonHasCorrectSchema(t *testing.T) {
 		t.Errorf("expected created_at to be set")
 	}
 }
+
+func TestParaFolder(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, "projects"), 0755); err != nil {
+		t.Fatalf("failed to create folder: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+		ok       bool
+	}{
+		{"Projects", "projects", true},
+		{"resources", "Resources", true},
+		{"Recipes", "", false},
+	}
+	for _, tt := range tests {
+		folder, ok := ParaFolder(tmpDir, tt.name)
+		if folder != tt.expected || ok != tt.ok {
+			t.Errorf("ParaFolder(%q): expected %q, %v, got %q, %v", tt.name, tt.expected, tt.ok, folder, ok)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+// Package links finds the links between notes, both [[wikilinks]] and
+// relative markdown links, and resolves them to the notes they point at.
+package links
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/index"
+)
+
+// Link is a link found in a note.
+type Link struct {
+	// Target is the linked note as written, without heading or alias: a
+	// name or path for wikilinks, a relative path for markdown links.
+	Target string
+	// Heading is the section linked to after #, if any.
+	Heading string
+	// Alias is the link's display text, if any.
+	Alias string
+	// Wiki reports whether the link is a [[wikilink]] rather than a
+	// markdown link.
+	Wiki bool
+	// Embed reports whether the link embeds its target (![[...]] or
+	// ![...](...)).
+	Embed bool
+	// Line is the 1-based line the link is on.
+	Line int
+	// Start and End are the byte offsets of the link in the content, so
+	// it can be rewritten in place.
+	Start, End int
+}
+
+var (
+	wikiPattern     = regexp.MustCompile(`(!?)\[\[([^\[\]|#\n]*)(?:#([^\[\]|\n]*))?(?:\|([^\[\]\n]*))?\]\]`)
+	markdownPattern = regexp.MustCompile(`(!?)\[([^\[\]\n]*)\]\((?:<([^>\n]+)>|([^()\s]+))(?:\s+"[^"\n]*")?\)`)
+	codeSpanPattern = regexp.MustCompile("`[^`\n]*`")
+)
+
+// Parse returns the links in a note's content in the order they appear.
+// Links in code blocks and code spans are ignored, as are markdown links to
+// URLs and to headings of the same note.
+func Parse(content string) []Link {
+	var links []Link
+	fence := ""
+	offset := 0
+	for i, line := range strings.Split(content, "\n") {
+		start := offset
+		offset += len(line) + 1
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		// Blank out code spans so offsets stay put while their contents
+		// no longer match
+		line = codeSpanPattern.ReplaceAllStringFunc(line, func(s string) string {
+			return strings.Repeat(" ", len(s))
+		})
+		links = append(links, parseLine(line, i+1, start)...)
+	}
+	return links
+}
+
+// parseLine returns the links on one line, in order. start is the line's
+// offset in the content.
+func parseLine(line string, lineNo, start int) []Link {
+	type found struct {
+		at   int
+		link Link
+	}
+	var all []found
+
+	for _, m := range wikiPattern.FindAllStringSubmatchIndex(line, -1) {
+		all = append(all, found{m[0], Link{
+			Target:  strings.TrimSpace(submatch(line, m, 2)),
+			Heading: strings.TrimSpace(submatch(line, m, 3)),
+			Alias:   strings.TrimSpace(submatch(line, m, 4)),
+			Wiki:    true,
+			Embed:   submatch(line, m, 1) == "!",
+			Line:    lineNo,
+			Start:   start + m[0],
+			End:     start + m[1],
+		}})
+	}
+	for _, m := range markdownPattern.FindAllStringSubmatchIndex(line, -1) {
+		dest := submatch(line, m, 3)
+		if dest == "" {
+			dest = submatch(line, m, 4)
+		}
+		if isExternal(dest) {
+			continue
+		}
+		target, heading, _ := strings.Cut(dest, "#")
+		if unescaped, err := url.PathUnescape(target); err == nil {
+			target = unescaped
+		}
+		all = append(all, found{m[0], Link{
+			Target:  target,
+			Heading: heading,
+			Alias:   submatch(line, m, 2),
+			Embed:   submatch(line, m, 1) == "!",
+			Line:    lineNo,
+			Start:   start + m[0],
+			End:     start + m[1],
+		}})
+	}
+
+	sort.SliceStable(all, func(i, j int) bool { return all[i].at < all[j].at })
+	links := make([]Link, len(all))
+	for i, f := range all {
+		links[i] = f.link
+	}
+	return links
+}
+
+// submatch returns the text of group g in the match m, or "" if the group
+// did not take part.
+func submatch(s string, m []int, g int) string {
+	if m[2*g] < 0 {
+		return ""
+	}
+	return s[m[2*g]:m[2*g+1]]
+}
+
+// isExternal reports whether a markdown link destination points outside
+// the vault or within the same note.
+func isExternal(dest string) bool {
+	if dest == "" || strings.HasPrefix(dest, "#") {
+		return true
+	}
+	u, err := url.Parse(dest)
+	return err == nil && u.Scheme != ""
+}
+
+// Resolver resolves links against the notes of a vault index.
+type Resolver struct {
+	ix *index.Index
+	// byPath maps lowercased note paths without extension to notes.
+	byPath map[string][]string
+	// byName maps lowercased note names to notes.
+	byName map[string][]string
+	// byTitle maps lowercased titles to notes.
+	byTitle map[string][]string
+}
+
+// NewResolver creates a resolver for the notes in ix.
+func NewResolver(ix *index.Index) *Resolver {
+	r := &Resolver{
+		ix:      ix,
+		byPath:  make(map[string][]string),
+		byName:  make(map[string][]string),
+		byTitle: make(map[string][]string),
+	}
+	for _, n := range ix.Notes() {
+		withoutExt := strings.TrimSuffix(n.Path, path.Ext(n.Path))
+		r.byPath[strings.ToLower(withoutExt)] = append(r.byPath[strings.ToLower(withoutExt)], n.Path)
+		r.byName[strings.ToLower(n.Name())] = append(r.byName[strings.ToLower(n.Name())], n.Path)
+		r.byTitle[strings.ToLower(n.Title)] = append(r.byTitle[strings.ToLower(n.Title)], n.Path)
+	}
+	return r
+}
+
+// Resolve returns the path, relative to the vault root, of the note a link
+// in the note at from points to. Wikilinks are matched against note paths,
+// then note names, then titles, ignoring case; when several notes match, one
+// whose name matches exactly wins, then one nearest to from. Markdown links
+// are relative to from's directory, or to the vault root when they start
+// with /. ok is false when no note matches.
+func (r *Resolver) Resolve(from string, link Link) (string, bool) {
+	if link.Target == "" {
+		_, ok := r.ix.Lookup(from)
+		return from, ok
+	}
+	if !link.Wiki {
+		return r.resolvePath(from, link.Target)
+	}
+
+	target := strings.TrimPrefix(strings.ReplaceAll(link.Target, "\\", "/"), "/")
+	target = strings.TrimSuffix(target, index.NoteExt)
+	key := strings.ToLower(target)
+
+	if strings.Contains(target, "/") {
+		if p, ok := r.pick(from, target, r.byPath[key]); ok {
+			return p, true
+		}
+		// A partial path matches the end of a note's path
+		var candidates []string
+		for withoutExt, paths := range r.byPath {
+			if strings.HasSuffix(withoutExt, "/"+key) {
+				candidates = append(candidates, paths...)
+			}
+		}
+		return r.pick(from, path.Base(target), candidates)
+	}
+	if p, ok := r.pick(from, target, r.byName[key]); ok {
+		return p, true
+	}
+	return r.pick(from, "", r.byTitle[key])
+}
+
+// resolvePath resolves a markdown link destination.
+func (r *Resolver) resolvePath(from, target string) (string, bool) {
+	p := path.Join(path.Dir(from), target)
+	if strings.HasPrefix(target, "/") {
+		p = strings.TrimPrefix(path.Clean(target), "/")
+	}
+	if strings.HasPrefix(p, "../") || p == ".." {
+		return "", false
+	}
+	if _, ok := r.ix.Lookup(p); ok {
+		return p, true
+	}
+
+	withoutExt := p
+	if strings.EqualFold(path.Ext(p), index.NoteExt) {
+		withoutExt = strings.TrimSuffix(p, path.Ext(p))
+	} else if path.Ext(p) != "" {
+		return "", false
+	}
+	return r.pick(from, path.Base(withoutExt), r.byPath[strings.ToLower(withoutExt)])
+}
+
+// pick chooses among the notes a link matches: a note whose name is
+// exactly name (when name is set), then the note nearest to from, then the
+// shortest path.
+func (r *Resolver) pick(from, name string, candidates []string) (string, bool) {
+	if len(candidates) == 0 {
+		return "", false
+	}
+	best := append([]string(nil), candidates...)
+	sort.SliceStable(best, func(i, j int) bool {
+		a, b := best[i], best[j]
+		if name != "" {
+			exactA := strings.TrimSuffix(path.Base(a), path.Ext(a)) == name
+			exactB := strings.TrimSuffix(path.Base(b), path.Ext(b)) == name
+			if exactA != exactB {
+				return exactA
+			}
+		}
+		if da, db := distance(from, a), distance(from, b); da != db {
+			return da < db
+		}
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+	return best[0], true
+}
+
+// distance counts the directory steps from the directory of from to the
+// directory of to.
+func distance(from, to string) int {
+	a := strings.Split(path.Dir(from), "/")
+	b := strings.Split(path.Dir(to), "/")
+	common := 0
+	for common < len(a) && common < len(b) && a[common] == b[common] {
+		common++
+	}
+	return len(a) - common + len(b) - common
+}
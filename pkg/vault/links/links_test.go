@@ -0,0 +1,93 @@
+package links
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/index"
+)
+
+func TestParse(t *testing.T) {
+	content := "---\ntitle: Garden\n---\n" +
+		"See [[Compost]] and [[Projects/Garden plan#Beds|the plan]].\n" +
+		"![[sketch.png]] and [notes](../Areas/soil%20tests.md#ph \"Soil\")\n" +
+		"[site](https://example.com), [top](#garden) and `[[not a link]]`\n" +
+		"```\n[[also not a link]]\n```\n" +
+		"[spaced](<Areas/my notes.md>)\n"
+
+	expected := []Link{
+		{Target: "Compost", Wiki: true, Line: 4},
+		{Target: "Projects/Garden plan", Heading: "Beds", Alias: "the plan", Wiki: true, Line: 4},
+		{Target: "sketch.png", Wiki: true, Embed: true, Line: 5},
+		{Target: "../Areas/soil tests.md", Heading: "ph", Alias: "notes", Line: 5},
+		{Target: "Areas/my notes.md", Alias: "spaced", Line: 10},
+	}
+	spans := []string{
+		"[[Compost]]",
+		"[[Projects/Garden plan#Beds|the plan]]",
+		"![[sketch.png]]",
+		`[notes](../Areas/soil%20tests.md#ph "Soil")`,
+		"[spaced](<Areas/my notes.md>)",
+	}
+
+	got := Parse(content)
+	for i, link := range got {
+		if i < len(spans) && content[link.Start:link.End] != spans[i] {
+			t.Errorf("expected link %d to span %q, got %q", i, spans[i], content[link.Start:link.End])
+		}
+		got[i].Start, got[i].End = 0, 0
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected:\n%+v\ngot:\n%+v", expected, got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	ix := index.New("/vault",
+		index.Note{Path: "Inbox/today.md", Title: "Today"},
+		index.Note{Path: "Areas/Compost.md", Title: "Compost"},
+		index.Note{Path: "Projects/Garden plan.md", Title: "Vegetable garden"},
+		index.Note{Path: "Projects/meeting.md", Title: "Kickoff"},
+		index.Note{Path: "Areas/meeting.md", Title: "Weekly"},
+		index.Note{Path: "Areas/soil tests.md", Title: "Soil tests"},
+	)
+	r := NewResolver(ix)
+
+	tests := []struct {
+		name     string
+		from     string
+		link     Link
+		expected string
+	}{
+		{"name", "Inbox/today.md", Link{Target: "Compost", Wiki: true}, "Areas/Compost.md"},
+		{"name ignoring case", "Inbox/today.md", Link{Target: "compost", Wiki: true}, "Areas/Compost.md"},
+		{"name with extension", "Inbox/today.md", Link{Target: "Compost.md", Wiki: true}, "Areas/Compost.md"},
+		{"path", "Inbox/today.md", Link{Target: "Projects/garden plan", Wiki: true}, "Projects/Garden plan.md"},
+		{"partial path", "Inbox/today.md", Link{Target: "areas/meeting", Wiki: true}, "Areas/meeting.md"},
+		{"nearest of same name", "Projects/Garden plan.md", Link{Target: "meeting", Wiki: true}, "Projects/meeting.md"},
+		{"title", "Inbox/today.md", Link{Target: "vegetable garden", Wiki: true}, "Projects/Garden plan.md"},
+		{"same note", "Inbox/today.md", Link{Heading: "Tasks", Wiki: true}, "Inbox/today.md"},
+		{"relative markdown", "Projects/meeting.md", Link{Target: "../Areas/soil tests.md"}, "Areas/soil tests.md"},
+		{"root markdown", "Projects/meeting.md", Link{Target: "/Areas/Compost.md"}, "Areas/Compost.md"},
+		{"markdown ignoring case and extension", "Areas/meeting.md", Link{Target: "compost"}, "Areas/Compost.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := r.Resolve(tt.from, tt.link)
+			if !ok || got != tt.expected {
+				t.Errorf("expected %s, got %q (ok %v)", tt.expected, got, ok)
+			}
+		})
+	}
+
+	for _, link := range []Link{
+		{Target: "Missing", Wiki: true},
+		{Target: "sketch.png", Wiki: true, Embed: true},
+		{Target: "../../outside.md"},
+	} {
+		if got, ok := r.Resolve("Inbox/today.md", link); ok {
+			t.Errorf("expected %+v not to resolve, got %s", link, got)
+		}
+	}
+}
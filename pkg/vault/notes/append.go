@@ -0,0 +1,163 @@
+package notes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// headingPattern matches an ATX heading line, capturing its markers and
+// text.
+var headingPattern = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)[ \t#]*$`)
+
+// listItemPattern matches a list item line.
+var listItemPattern = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s`)
+
+// Append adds text to the note at path, rewriting it atomically. Without a
+// heading the text goes at the end of the note; with one it goes at the end
+// of that heading's section, and the heading is added at the end of the
+// note when missing.
+//
+// heading is either a heading line such as "## Log", matched as written, or
+// just its text, matched at any level ignoring case and created as a level
+// two heading. The text is separated from what precedes it by a blank line,
+// unless both are list items, so entries appended to a list stay in it.
+func Append(path, text, heading string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	content := AppendText(string(data), text, heading)
+	return replaceFile(path, []byte(content), info.Mode().Perm())
+}
+
+// AppendText returns content with text appended as Append describes.
+func AppendText(content, text, heading string) string {
+	text = strings.Trim(text, "\n")
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+
+	end := len(lines)
+	if heading != "" {
+		start, level := findHeading(lines, heading)
+		if start < 0 {
+			if !strings.HasPrefix(heading, "#") {
+				heading = "## " + heading
+			}
+			lines = appendBlock(lines, heading)
+			start, end = len(lines)-1, len(lines)
+		} else {
+			end = sectionEnd(lines, start, level)
+		}
+		// Skip the blank lines before the next section
+		for end > start+1 && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+	}
+
+	block := strings.Split(text, "\n")
+	if end > 0 && strings.TrimSpace(lines[end-1]) != "" &&
+		!(listItemPattern.MatchString(lines[end-1]) && listItemPattern.MatchString(block[0])) {
+		block = append([]string{""}, block...)
+	}
+	if end < len(lines) && strings.TrimSpace(lines[end]) != "" {
+		block = append(block, "")
+	}
+
+	result := append(append(append([]string{}, lines[:end]...), block...), lines[end:]...)
+	return strings.Join(result, "\n") + "\n"
+}
+
+// appendBlock adds line to the end of lines after a blank line.
+func appendBlock(lines []string, line string) []string {
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	return append(lines, line)
+}
+
+// findHeading returns the index and level of the first heading matching
+// heading, outside code blocks, or -1.
+func findHeading(lines []string, heading string) (int, int) {
+	exact := strings.HasPrefix(heading, "#")
+	fenced := false
+	for i, line := range lines {
+		if isFence(line) {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		m := headingPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if exact && strings.TrimSpace(line) == strings.TrimSpace(heading) ||
+			!exact && strings.EqualFold(m[2], strings.TrimSpace(heading)) {
+			return i, len(m[1])
+		}
+	}
+	return -1, 0
+}
+
+// sectionEnd returns the index of the first line after the section whose
+// heading of the given level is at start: the next heading of that level
+// or higher outside code blocks, or the end of lines.
+func sectionEnd(lines []string, start, level int) int {
+	fenced := false
+	for i := start + 1; i < len(lines); i++ {
+		if isFence(lines[i]) {
+			fenced = !fenced
+			continue
+		}
+		if m := headingPattern.FindStringSubmatch(lines[i]); !fenced && m != nil && len(m[1]) <= level {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// isFence reports whether line opens or closes a fenced code block.
+func isFence(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// replaceFile atomically replaces the file at path with data, with mode
+// perm.
+func replaceFile(path string, data []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write note: %w", err)
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(perm)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write note: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAppendText(t *testing.T) {
+	daily := "---\ntitle: \"Today\"\n---\n# Today\n\n## Log\n\n- 09:00 standup\n\n## Tasks\n\n- [ ] groceries\n"
+
+	tests := []struct {
+		name     string
+		content  string
+		text     string
+		heading  string
+		expected string
+	}{
+		{
+			name:     "end of note",
+			content:  "# Ideas\n\nA garden shed.\n",
+			text:     "A pond.\n",
+			expected: "# Ideas\n\nA garden shed.\n\nA pond.\n",
+		},
+		{
+			name:     "empty note",
+			content:  "",
+			text:     "A pond.",
+			expected: "A pond.\n",
+		},
+		{
+			name:     "list item joins the list",
+			content:  daily,
+			text:     "- 10:30 called the plumber",
+			heading:  "Log",
+			expected: "---\ntitle: \"Today\"\n---\n# Today\n\n## Log\n\n- 09:00 standup\n- 10:30 called the plumber\n\n## Tasks\n\n- [ ] groceries\n",
+		},
+		{
+			name:     "exact heading",
+			content:  daily,
+			text:     "- [ ] plumber",
+			heading:  "## Tasks",
+			expected: daily + "- [ ] plumber\n",
+		},
+		{
+			name:     "missing heading is created",
+			content:  daily,
+			text:     "Slept well.",
+			heading:  "journal",
+			expected: daily + "\n## journal\n\nSlept well.\n",
+		},
+		{
+			name:     "heading in a code block is ignored",
+			content:  "```\n## Log\n```\n",
+			text:     "Entry.",
+			heading:  "## Log",
+			expected: "```\n## Log\n```\n\n## Log\n\nEntry.\n",
+		},
+		{
+			name:     "subsections stay in the section",
+			content:  "## Log\n\n### Morning\n\nRan.\n\n## Later\n",
+			text:     "Swam.",
+			heading:  "Log",
+			expected: "## Log\n\n### Morning\n\nRan.\n\nSwam.\n\n## Later\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AppendText(tt.content, tt.text, tt.heading); got != tt.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAppend_KeepsMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "today.md")
+	if err := os.WriteFile(path, []byte("# Today\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Append(path, "Slept well.", ""); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	content, _ := os.ReadFile(path)
+	if string(content) != "# Today\n\nSlept well.\n" {
+		t.Errorf("unexpected note %q", content)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+}
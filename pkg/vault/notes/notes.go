@@ -0,0 +1,230 @@
+// Package notes creates notes in a vault: it renders the note from a
+// template and frontmatter, picks a free file name and writes the file
+// atomically, so every tool that adds notes lays them out the same way.
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/frontmatter"
+)
+
+// DefaultExt is the extension of notes created without one.
+const DefaultExt = ".md"
+
+// maxSuffix is the highest collision suffix CreateNote tries.
+const maxSuffix = 1000
+
+// ErrNameTaken is returned by CreateNote when the note's name and every
+// numbered variant of it already exist.
+var ErrNameTaken = errors.New("too many notes with the same name")
+
+// Permissions sets up the directories and files CreateNote creates.
+// fileperm.Permissions implements it.
+type Permissions interface {
+	// MkdirAll creates dir and any missing parents.
+	MkdirAll(dir string) error
+	// ApplyFile applies the configured mode and group to a created file.
+	ApplyFile(path string) error
+}
+
+// Options describes a note to create.
+type Options struct {
+	// Dir is the directory the note is created in, created if missing.
+	Dir string
+	// Name is the file name without extension. When it is taken, -2, -3,
+	// etc. are appended.
+	Name string
+	// Ext is the file extension, with the dot; empty means DefaultExt.
+	Ext string
+	// TemplatePath, when set, is a template file the body is appended to.
+	TemplatePath string
+	// Frontmatter holds YAML lines, each ending in a newline, added to the
+	// end of the template's frontmatter or to a new block.
+	Frontmatter string
+	// Tags are merged into the frontmatter's tags, skipping any already
+	// listed.
+	Tags []string
+	// Body is the note's content, after a blank line when following a
+	// template or frontmatter.
+	Body string
+	// Perms, when set, sets up the created directories and note. Otherwise
+	// directories get mode 0755 and notes 0644.
+	Perms Permissions
+}
+
+// Render returns the content of the note described by opts. The note always
+// ends in a newline.
+func Render(opts Options) (string, error) {
+	var content string
+	if opts.TemplatePath != "" {
+		template, err := os.ReadFile(opts.TemplatePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read template: %w", err)
+		}
+		content = frontmatter.AddTags(string(template), opts.Tags)
+		content = frontmatter.Append(content, opts.Frontmatter)
+
+		// Ensure there's a blank line between the template and the body
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += "\n"
+	} else {
+		if opts.Frontmatter != "" {
+			content = "---\n" + opts.Frontmatter + "---\n\n"
+		}
+		content = frontmatter.AddTags(content, opts.Tags)
+	}
+
+	content += opts.Body
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content, nil
+}
+
+// CreateNote renders the note described by opts and writes it to a new file
+// in opts.Dir, returning its path. The note appears complete or not at all,
+// and an existing file is never overwritten: the name gets the first free
+// numbered suffix instead.
+func CreateNote(opts Options) (string, error) {
+	content, err := Render(opts)
+	if err != nil {
+		return "", err
+	}
+	return Create(opts.Dir, opts.Name, opts.Ext, []byte(content), opts.Perms)
+}
+
+// Create atomically writes data to a new file named name plus ext in dir,
+// adding -2, -3, etc. to the name while it is taken, and returns its path.
+// perms may be nil for the default modes.
+func Create(dir, name, ext string, data []byte, perms Permissions) (string, error) {
+	if ext == "" {
+		ext = DefaultExt
+	}
+	if perms == nil {
+		perms = defaultPermissions{}
+	}
+	if err := perms.MkdirAll(dir); err != nil {
+		return "", fmt.Errorf("failed to create note directory: %w", err)
+	}
+
+	tmp, err := writeTemp(dir, name+ext, data, perms)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp)
+
+	for i := 1; i <= maxSuffix; i++ {
+		path := filepath.Join(dir, name+ext)
+		if i > 1 {
+			path = filepath.Join(dir, fmt.Sprintf("%s-%d%s", name, i, ext))
+		}
+		claimed, err := claim(tmp, path)
+		if err != nil {
+			return "", fmt.Errorf("failed to write note: %w", err)
+		}
+		if claimed {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s%s: %w", name, ext, ErrNameTaken)
+}
+
+// writeTemp writes data to a hidden temporary file in dir, with the note's
+// final mode and group.
+func writeTemp(dir, name string, data []byte, perms Permissions) (string, error) {
+	f, err := os.CreateTemp(dir, "."+name+".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create note: %w", err)
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(0644)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = perms.ApplyFile(tmp)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to write note: %w", err)
+	}
+	return tmp, nil
+}
+
+// claim moves the written temporary file to path unless path exists,
+// reporting whether it did. Hard linking claims the name atomically; on
+// filesystems without hard links the name is checked, then renamed to.
+func claim(tmp, path string) (bool, error) {
+	err := os.Link(tmp, path)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, fs.ErrExist) {
+		return false, nil
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return false, nil
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// defaultPermissions creates directories with mode 0755 and leaves files as
+// written.
+type defaultPermissions struct{}
+
+func (defaultPermissions) MkdirAll(dir string) error { return os.MkdirAll(dir, 0755) }
+
+func (defaultPermissions) ApplyFile(string) error { return nil }
+
+// Slug turns a title into a lowercase, hyphen-separated file name part,
+// keeping letters and digits in any script and at most 60 bytes. It returns
+// "" when the title has no letters or digits.
+func Slug(title string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			hyphen = hyphen && sb.Len() > 0
+			size := utf8.RuneLen(r)
+			if hyphen {
+				size++
+			}
+			if sb.Len()+size > maxSlugLen {
+				return sb.String()
+			}
+			if hyphen {
+				sb.WriteByte('-')
+			}
+			hyphen = false
+			sb.WriteRune(r)
+		case r == '\'':
+			// Drop apostrophes so "don't" stays one word
+		default:
+			hyphen = true
+		}
+	}
+	return sb.String()
+}
+
+// maxSlugLen is the longest slug Slug returns, in bytes.
+const maxSlugLen = 60
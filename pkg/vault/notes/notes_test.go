@@ -0,0 +1,134 @@
+package notes
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "meeting.md")
+	if err := os.WriteFile(templatePath, []byte("---\ntags: [meeting]\n---\n# Meeting"), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{
+			name:     "body only",
+			opts:     Options{Body: "Buy milk."},
+			expected: "Buy milk.\n",
+		},
+		{
+			name:     "new frontmatter",
+			opts:     Options{Frontmatter: "type: journal\n", Tags: []string{"daily"}, Body: "# Today\n"},
+			expected: "---\ntype: journal\ntags: [daily]\n---\n\n# Today\n",
+		},
+		{
+			name:     "template",
+			opts:     Options{TemplatePath: templatePath, Frontmatter: "model: \"base\"\n", Tags: []string{"budget"}, Body: "Buy milk."},
+			expected: "---\ntags: [meeting, budget]\nmodel: \"base\"\n---\n# Meeting\n\nBuy milk.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.opts)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRender_MissingTemplate(t *testing.T) {
+	_, err := Render(Options{TemplatePath: filepath.Join(t.TempDir(), "missing.md")})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a not-exist error, got: %v", err)
+	}
+}
+
+func TestCreateNote_Collisions(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Inbox")
+	opts := Options{Dir: dir, Name: "2026-01-22-0930-voice-note", Body: "Buy milk."}
+
+	var paths []string
+	for range 3 {
+		path, err := CreateNote(opts)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		paths = append(paths, filepath.Base(path))
+	}
+
+	expected := []string{"2026-01-22-0930-voice-note.md", "2026-01-22-0930-voice-note-2.md", "2026-01-22-0930-voice-note-3.md"}
+	for i, name := range expected {
+		if paths[i] != name {
+			t.Errorf("expected note %d to be %s, got %s", i+1, name, paths[i])
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(entries) != len(expected) {
+		t.Errorf("expected only the notes to be left, got %d entries", len(entries))
+	}
+	info, err := os.Stat(filepath.Join(dir, expected[0]))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("expected mode 0644, got %o", info.Mode().Perm())
+	}
+}
+
+// recordingPerms records the files it is applied to.
+type recordingPerms struct {
+	dirs  []string
+	files []string
+}
+
+func (p *recordingPerms) MkdirAll(dir string) error {
+	p.dirs = append(p.dirs, dir)
+	return os.MkdirAll(dir, 0755)
+}
+
+func (p *recordingPerms) ApplyFile(path string) error {
+	p.files = append(p.files, path)
+	return os.Chmod(path, 0600)
+}
+
+func TestCreateNote_Permissions(t *testing.T) {
+	dir := t.TempDir()
+	perms := &recordingPerms{}
+
+	path, err := CreateNote(Options{Dir: dir, Name: "note", Ext: ".txt", Body: "Buy milk.", Perms: perms})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if filepath.Base(path) != "note.txt" {
+		t.Errorf("expected note.txt, got %s", filepath.Base(path))
+	}
+	if len(perms.dirs) != 1 || perms.dirs[0] != dir {
+		t.Errorf("expected the directory to be created through perms, got %v", perms.dirs)
+	}
+	if len(perms.files) != 1 {
+		t.Fatalf("expected perms applied once, got %v", perms.files)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected the note to keep the applied mode, got %o", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,91 @@
+package vault
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/TechnicallyShaun/nota-orbis/pkg/vault/frontmatter"
+)
+
+// TemplatesDir is the directory within the marker directory holding note
+// templates, which tools reference by name.
+const TemplatesDir = "templates"
+
+//go:embed templates/*.md
+var starterTemplates embed.FS
+
+// StarterTemplates returns the names of the templates InitTemplates creates.
+func StarterTemplates() []string {
+	entries, _ := fs.ReadDir(starterTemplates, TemplatesDir)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".md"))
+	}
+	return names
+}
+
+// TemplatePath returns the path of the named template in the vault at root.
+func TemplatePath(root, name string) string {
+	return filepath.Join(root, VaultMarkerDir, TemplatesDir, name+".md")
+}
+
+// InitTemplates creates .nota/templates in the vault at root with the starter
+// templates (daily note, meeting, voice note and project). Templates that
+// already exist are left untouched. It returns the names of the templates it
+// created.
+func InitTemplates(root string) ([]string, error) {
+	if err := os.MkdirAll(filepath.Join(root, VaultMarkerDir, TemplatesDir), 0755); err != nil {
+		return nil, err
+	}
+
+	var created []string
+	for _, name := range StarterTemplates() {
+		dest := TemplatePath(root, name)
+		if _, err := os.Stat(dest); err == nil {
+			continue
+		}
+		content, err := starterTemplates.ReadFile(path.Join(TemplatesDir, name+".md"))
+		if err != nil {
+			return created, err
+		}
+		if err := os.WriteFile(dest, content, 0644); err != nil {
+			return created, err
+		}
+		created = append(created, name)
+	}
+	return created, nil
+}
+
+// placeholderPattern matches template placeholders from other tools, such
+// as {{date}} or Templater's <% tp.date.now() %>.
+var placeholderPattern = regexp.MustCompile(`\{\{[^{}\n]*\}\}|<%[^%\n]*%>`)
+
+// LintTemplate checks the template at path for problems that would
+// otherwise only show in the notes written from it: frontmatter that
+// YAML parsers reject, and placeholders, which nota does not fill in since
+// notes are the template with their text appended. It returns a
+// description of each problem found.
+func LintTemplate(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	content := string(data)
+
+	var problems []string
+	if err := frontmatter.Validate(content); err != nil {
+		problems = append(problems, err.Error())
+	}
+	for i, line := range strings.Split(content, "\n") {
+		for _, placeholder := range placeholderPattern.FindAllString(line, -1) {
+			problems = append(problems, fmt.Sprintf("line %d: placeholder %s is copied into notes as written", i+1, placeholder))
+		}
+	}
+	return problems, nil
+}
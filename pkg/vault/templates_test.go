@@ -0,0 +1,89 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestInit_CreatesStarterTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if err := Init(tmpDir, "test-vault"); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	for _, name := range []string{"daily-note", "meeting", "voice-note", "project"} {
+		if _, err := os.Stat(TemplatePath(tmpDir, name)); err != nil {
+			t.Errorf("expected template %s to exist: %v", name, err)
+		}
+	}
+}
+
+func TestInitTemplates_KeepsExistingTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+	createVault(t, tmpDir)
+
+	os.MkdirAll(filepath.Join(tmpDir, VaultMarkerDir, TemplatesDir), 0755)
+	custom := []byte("# My meeting template\n")
+	if err := os.WriteFile(TemplatePath(tmpDir, "meeting"), custom, 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	created, err := InitTemplates(tmpDir)
+	if err != nil {
+		t.Fatalf("InitTemplates failed: %v", err)
+	}
+
+	expected := []string{"daily-note", "project", "voice-note"}
+	if !reflect.DeepEqual(created, expected) {
+		t.Errorf("expected created %v, got %v", expected, created)
+	}
+	data, _ := os.ReadFile(TemplatePath(tmpDir, "meeting"))
+	if string(data) != string(custom) {
+		t.Errorf("expected existing template to be kept, got:\n%s", data)
+	}
+}
+
+func TestLintTemplate(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{"valid", "---\ntype: meeting\ntags: [meeting]\n---\n# Meeting\n", nil},
+		{"invalid frontmatter", "---\ntags: [meeting\n---\n# Meeting\n", []string{"invalid frontmatter: line 2: unclosed ["}},
+		{"placeholders", "# {{title}}\n\nCreated <% tp.date.now() %>\n", []string{
+			"line 1: placeholder {{title}} is copied into notes as written",
+			"line 3: placeholder <% tp.date.now() %> is copied into notes as written",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".md")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			problems, err := LintTemplate(path)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if !reflect.DeepEqual(problems, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, problems)
+			}
+		})
+	}
+
+	// The starter templates are clean
+	for _, name := range StarterTemplates() {
+		content, _ := starterTemplates.ReadFile(TemplatesDir + "/" + name + ".md")
+		p := filepath.Join(dir, name+".md")
+		os.WriteFile(p, content, 0644)
+		if problems, _ := LintTemplate(p); len(problems) > 0 {
+			t.Errorf("%s: unexpected problems %q", name, problems)
+		}
+	}
+}